@@ -0,0 +1,99 @@
+package core
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	tmplfs "github.com/delaneyj/cbor/cborgen/templates"
+)
+
+// benchTemplate renders the --bench companion test file.
+var benchTemplate = template.Must(template.New("bench.gotmpl").Funcs(templateFuncs).ParseFS(tmplfs.FS, "bench.gotmpl"))
+
+// benchType is one generated type in the --bench file. Fixture is the
+// expression producing the value to benchmark.
+type benchType struct {
+	Name    string
+	Fixture string
+}
+
+// benchOutputPath derives "{output}_bench_test.go" from the generated
+// file's path, e.g. "types_cbor.go" -> "types_cbor_bench_test.go".
+func benchOutputPath(outputPath string) string {
+	return strings.TrimSuffix(outputPath, ".go") + "_bench_test.go"
+}
+
+// writeBenchFile emits encode and decode benchmarks for structs. Each
+// benchmark uses NewFixtureT() when the package declares one (a plain
+// function without parameters returning T or *T), and the zero T
+// otherwise. build is the input's //go:build line, repeated in the file.
+func writeBenchFile(srcDir, outputPath, pkg, build string, structs []structSpec) error {
+	fixtures, err := fixtureConstructors(srcDir)
+	if err != nil {
+		return err
+	}
+	data := struct {
+		Package string
+		Build   string
+		Types   []benchType
+	}{Package: pkg, Build: build}
+	for _, ss := range structs {
+		if ss.Instance != "" {
+			// The code of a generic instantiation has no fixture.
+			continue
+		}
+		bt := benchType{Name: ss.Name, Fixture: ss.Name + "{}"}
+		if fn := "NewFixture" + ss.Name; fixtures[fn] {
+			bt.Fixture = fn + "()"
+		}
+		data.Types = append(data.Types, bt)
+	}
+
+	var buf bytes.Buffer
+	if err := benchTemplate.ExecuteTemplate(&buf, "bench.gotmpl", data); err != nil {
+		return err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(benchOutputPath(outputPath), src, 0o644)
+}
+
+// fixtureConstructors returns the names of the NewFixture* functions
+// declared in the non-test Go files of dir that take no parameters and
+// return a single value.
+func fixtureConstructors(dir string) (map[string]bool, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	out := map[string]bool{}
+	fset := token.NewFileSet()
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") || !inBuild(path) {
+			continue
+		}
+		f, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		for _, decl := range f.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok || fd.Recv != nil || !strings.HasPrefix(fd.Name.Name, "NewFixture") {
+				continue
+			}
+			if fd.Type.Params.NumFields() == 0 && fd.Type.Results.NumFields() == 1 {
+				out[fd.Name.Name] = true
+			}
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,52 @@
+package core
+
+import (
+	"go/ast"
+	"go/build"
+	"path/filepath"
+	"strings"
+)
+
+// buildTags are the extra build tags of the current run, as given by
+// Options.Tags. Files whose build constraints exclude them are not part
+// of the package being generated for.
+var buildTags []string
+
+// ParseTags splits a -tags value the way go build does: a
+// comma-separated list, or the older space-separated form.
+func ParseTags(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' })
+}
+
+// MatchFile reports whether the Go file at path is part of the build for
+// the given tags, honoring //go:build lines and _GOOS/_GOARCH file name
+// suffixes like go build.
+func MatchFile(path string, tags []string) (bool, error) {
+	ctx := build.Default
+	ctx.BuildTags = tags
+	return ctx.MatchFile(filepath.Dir(path), filepath.Base(path))
+}
+
+// inBuild is MatchFile for the tags of the current run. Files that cannot
+// be read are treated as excluded; callers parse them again if needed.
+func inBuild(path string) bool {
+	ok, err := MatchFile(path, buildTags)
+	return err == nil && ok
+}
+
+// buildConstraint returns the //go:build line of file, or "". The
+// generated companion file repeats it so it compiles under exactly the
+// same tags as its input.
+func buildConstraint(file *ast.File) string {
+	for _, cg := range file.Comments {
+		if cg.Pos() > file.Package {
+			break
+		}
+		for _, c := range cg.List {
+			if strings.HasPrefix(c.Text, "//go:build ") {
+				return c.Text
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,162 @@
+package core
+
+import (
+	"fmt"
+	"go/ast"
+	"strings"
+)
+
+// cloneBody returns the statements of a generated Clone method for the
+// struct st: starting from a shallow copy y of *x, every field that may
+// share memory with x is replaced by a deep copy.
+func cloneBody(st *ast.StructType) string {
+	var sb strings.Builder
+	for _, field := range st.Fields.List {
+		for _, name := range fieldNames(field) {
+			sb.WriteString(cloneFix("y."+name, field.Type, 0, map[string]bool{}))
+		}
+	}
+	return sb.String()
+}
+
+// namedCloneBody is cloneBody for a named slice or map type, where the
+// shallow copy y is the value itself.
+func namedCloneBody(typ ast.Expr) string {
+	return cloneFix("y", typ, 0, map[string]bool{})
+}
+
+// fieldNames returns the names a struct field is selected by; an
+// embedded field is selected by its type name.
+func fieldNames(field *ast.Field) []string {
+	if len(field.Names) > 0 {
+		names := make([]string, 0, len(field.Names))
+		for _, n := range field.Names {
+			if n.Name != "_" {
+				names = append(names, n.Name)
+			}
+		}
+		return names
+	}
+	typ := field.Type
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	switch t := typ.(type) {
+	case *ast.Ident:
+		return []string{t.Name}
+	case *ast.SelectorExpr:
+		return []string{t.Sel.Name}
+	}
+	return nil
+}
+
+// cloneFix returns statements that replace the references held by ref,
+// currently a shallow copy of a value of type typ, with deep copies.
+// Interfaces, funcs and channels are left shared, as are types the
+// generator knows nothing about. seen guards against recursing through
+// file-local types that are not cloned by a generated method.
+func cloneFix(ref string, typ ast.Expr, depth int, seen map[string]bool) string {
+	if !needsClone(typ, map[string]bool{}) {
+		return ""
+	}
+	switch t := typ.(type) {
+	case *ast.Ident:
+		if _, ok := fileScalarTypes[t.Name]; ok {
+			return ""
+		}
+		if _, ok := generatedStructs[t.Name]; ok {
+			return ref + " = *" + ref + ".Clone()\n"
+		}
+		if seen[t.Name] {
+			return ""
+		}
+		seen[t.Name] = true
+		defer delete(seen, t.Name)
+		if st, ok := fileStructTypes[t.Name]; ok {
+			var sb strings.Builder
+			for _, field := range st.Fields.List {
+				for _, name := range fieldNames(field) {
+					sb.WriteString(cloneFix(ref+"."+name, field.Type, depth, seen))
+				}
+			}
+			return sb.String()
+		}
+		if under, ok := fileNamedTypes[t.Name]; ok {
+			return cloneFix(ref, under, depth, seen)
+		}
+	case *ast.SelectorExpr:
+		// Named byte slices such as json.RawMessage, cbor.RawMessage and
+		// net.IP.
+		return ref + " = slices.Clone(" + ref + ")\n"
+	case *ast.StarExpr:
+		if ident, ok := t.X.(*ast.Ident); ok {
+			if _, ok := generatedStructs[ident.Name]; ok {
+				return ref + " = " + ref + ".Clone()\n"
+			}
+		}
+		v := fmt.Sprintf("v%d", depth)
+		return "if " + ref + " != nil {\n" +
+			v + " := *" + ref + "\n" +
+			cloneFix(v, t.X, depth+1, seen) +
+			ref + " = &" + v + "\n}\n"
+	case *ast.ArrayType:
+		var sb strings.Builder
+		if t.Len == nil {
+			sb.WriteString(ref + " = slices.Clone(" + ref + ")\n")
+		}
+		i := fmt.Sprintf("i%d", depth)
+		if elem := cloneFix(ref+"["+i+"]", t.Elt, depth+1, seen); elem != "" {
+			sb.WriteString("for " + i + " := range " + ref + " {\n" + elem + "}\n")
+		}
+		return sb.String()
+	case *ast.MapType:
+		var sb strings.Builder
+		sb.WriteString(ref + " = maps.Clone(" + ref + ")\n")
+		k, v := fmt.Sprintf("k%d", depth), fmt.Sprintf("v%d", depth)
+		if val := cloneFix(v, t.Value, depth+1, seen); val != "" {
+			sb.WriteString("for " + k + ", " + v + " := range " + ref + " {\n" + val + ref + "[" + k + "] = " + v + "\n}\n")
+		}
+		return sb.String()
+	}
+	return ""
+}
+
+// needsClone reports whether a value of type typ can share memory with
+// a copy of itself: it is or contains a slice, map or pointer.
+func needsClone(typ ast.Expr, seen map[string]bool) bool {
+	switch t := typ.(type) {
+	case *ast.Ident:
+		if seen[t.Name] {
+			// A type reachable from itself holds a pointer, slice or
+			// map on the way.
+			return true
+		}
+		seen[t.Name] = true
+		defer delete(seen, t.Name)
+		if st, ok := fileStructTypes[t.Name]; ok {
+			for _, field := range st.Fields.List {
+				if needsClone(field.Type, seen) {
+					return true
+				}
+			}
+			return false
+		}
+		if under, ok := fileNamedTypes[t.Name]; ok {
+			return needsClone(under, seen)
+		}
+		return false
+	case *ast.SelectorExpr:
+		if pkg, ok := t.X.(*ast.Ident); ok {
+			switch pkg.Name + "." + t.Sel.Name {
+			case "json.RawMessage", "net.IP":
+				return true
+			}
+		}
+		return isRawType(t)
+	case *ast.StarExpr, *ast.MapType:
+		return true
+	case *ast.ArrayType:
+		return t.Len == nil || needsClone(t.Elt, seen)
+	}
+	return false
+}
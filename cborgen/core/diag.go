@@ -0,0 +1,151 @@
+package core
+
+import (
+	"fmt"
+	"go/ast"
+	"strconv"
+	"strings"
+)
+
+// diagKey returns a Go string literal holding the diagnostic notation of
+// the map key of fs followed by ": ", e.g. `"\"name\": "` or `"1: "`.
+func diagKey(fs fieldSpec) string {
+	if fs.KeyAsInt {
+		return strconv.Quote(strconv.FormatInt(fs.IntKey, 10) + ": ")
+	}
+	return strconv.Quote(strconv.Quote(fs.CBORName) + ": ")
+}
+
+// diagFieldStmt returns the statements of a generated appendDiag method
+// appending the value of field fs, of type typ, to b in diagnostic
+// notation, matching how MarshalCBOR encodes it.
+func diagFieldStmt(fs fieldSpec, typ ast.Expr) string {
+	if fs.NilCheck != "" {
+		return "if " + fs.NilCheck + " {\n" +
+			"b = append(b, \"null\"...)\n" +
+			"} else {\n" + diagFieldValue(fs, typ) + "}\n"
+	}
+	return diagFieldValue(fs, typ)
+}
+
+// diagFieldValue returns the statements of diagFieldStmt for a field
+// that is not null.
+func diagFieldValue(fs fieldSpec, typ ast.Expr) string {
+	ref := "x." + fs.GoName
+	switch {
+	case fs.Unit != "":
+		return "b = strconv.AppendInt(b, int64(" + ref + "/" + durationUnits[fs.Unit] + "), 10)\n"
+	case fs.TimeFloat:
+		return "b = append(b, \"1(\"...)\n" +
+			"b = " + runtimeName("AppendDiagFloat64") + "(b, " + runtimeName("EpochSeconds") + "(" + ref + "))\n" +
+			"b = append(b, ')')\n"
+	case fs.TagOpt != "" && ipCodecName(typ) != "":
+		// The tag follows the address family, so render the encoding.
+		return diagEncoded(runtimeName("Append"+ipCodecName(typ)+"Tagged") + "(nil, " + ref + ")")
+	case fs.TagOpt == "0" && isTimeType(typ):
+		return diagEncoded(runtimeName("AppendRFC3339Time") + "(nil, " + ref + ")")
+	case fs.TagOpt != "" && !isURLPtr(typ) && !isTimeType(typ):
+		return "b = append(b, " + strconv.Quote(fs.TagOpt+"(") + "...)\n" +
+			diagValue(ref, typ, 0) +
+			"b = append(b, ')')\n"
+	case fs.Union:
+		return diagFallback(ref)
+	case fs.AsString:
+		return "b = strconv.AppendQuote(b, " + diagStringText(ref, typ) + ")\n"
+	case fs.Set:
+		return diagEncoded(runtimeName("AppendStringSet") + "(nil, " + ref + ")")
+	case fs.AsMap:
+		// EncodeExpr is the AppendPairs call; render what it writes.
+		return "if enc, err := " + strings.Replace(fs.EncodeExpr, "(b, ", "(nil, ", 1) + "; err == nil {\n" +
+			diagEncoded("enc") + "}\n"
+	}
+	return diagValue(ref, typ, 0)
+}
+
+// diagValue returns statements appending the value ref, of type typ, to b
+// in diagnostic notation. Structs generated here and their pointers and
+// slices are rendered directly; anything else goes through
+// cbor.AppendDiag, which encodes the value with cbor.Marshal first.
+func diagValue(ref string, typ ast.Expr, depth int) string {
+	switch t := typ.(type) {
+	case *ast.Ident:
+		if under, ok := fileScalarTypes[t.Name]; ok {
+			return diagValue(under+"("+ref+")", &ast.Ident{Name: under}, depth)
+		}
+		if _, ok := generatedStructs[t.Name]; ok {
+			if _, ok := fileStructTypes[t.Name]; ok {
+				return "b = " + ref + ".appendDiag(b)\n"
+			}
+		}
+		switch t.Name {
+		case "string":
+			return "b = strconv.AppendQuote(b, " + ref + ")\n"
+		case "bool":
+			return "b = strconv.AppendBool(b, " + ref + ")\n"
+		case "int", "int8", "int16", "int32", "int64":
+			return "b = strconv.AppendInt(b, int64(" + ref + "), 10)\n"
+		case "uint", "uint8", "uint16", "uint32", "uint64", "byte", "uintptr":
+			return "b = strconv.AppendUint(b, uint64(" + ref + "), 10)\n"
+		case "float32":
+			return "b = " + runtimeName("AppendDiagFloat32") + "(b, " + ref + ")\n"
+		case "float64":
+			return "b = " + runtimeName("AppendDiagFloat64") + "(b, " + ref + ")\n"
+		}
+	case *ast.StarExpr:
+		if ident, ok := t.X.(*ast.Ident); ok {
+			if _, ok := fileStructTypes[ident.Name]; ok {
+				if _, ok := generatedStructs[ident.Name]; ok {
+					// appendDiag writes null for a nil receiver.
+					return "b = " + ref + ".appendDiag(b)\n"
+				}
+			}
+		}
+		elem := diagValue("*"+ref, t.X, depth)
+		if elem == diagFallback("*"+ref) {
+			// cbor.Marshal writes a nil pointer as null itself.
+			return diagFallback(ref)
+		}
+		return "if " + ref + " == nil {\nb = append(b, \"null\"...)\n} else {\n" + elem + "}\n"
+	case *ast.ArrayType:
+		if ident, ok := t.Elt.(*ast.Ident); ok && (ident.Name == "byte" || ident.Name == "uint8") {
+			if t.Len == nil {
+				return "b = " + runtimeName("AppendDiagBytes") + "(b, " + ref + ")\n"
+			}
+			return "b = " + runtimeName("AppendDiagBytes") + "(b, " + ref + "[:])\n"
+		}
+		i := fmt.Sprintf("i%d", depth)
+		return "b = append(b, '[')\n" +
+			"for " + i + " := range " + ref + " {\n" +
+			"if " + i + " > 0 {\nb = append(b, \", \"...)\n}\n" +
+			diagValue(ref+"["+i+"]", t.Elt, depth+1) +
+			"}\n" +
+			"b = append(b, ']')\n"
+	}
+	return diagFallback(ref)
+}
+
+// diagEncoded returns statements appending the diagnostic notation of
+// enc, an expression encoding a value into a new buffer.
+func diagEncoded(enc string) string {
+	return "if s, _, err := " + runtimeName("DiagBytes") + "(" + enc + "); err == nil {\nb = append(b, s...)\n}\n"
+}
+
+// diagFallback returns the statement appending ref via cbor.AppendDiag.
+func diagFallback(ref string) string {
+	return "b = " + runtimeName("AppendDiag") + "(b, " + ref + ")\n"
+}
+
+// diagStringText returns an expression formatting ref, of type typ, as
+// the text a "string" field writes.
+func diagStringText(ref string, typ ast.Expr) string {
+	ident := typ.(*ast.Ident)
+	switch codec := stringCodecs[ident.Name]; codec.kind {
+	case "Int":
+		return "strconv.FormatInt(int64(" + ref + "), 10)"
+	case "Uint":
+		return "strconv.FormatUint(uint64(" + ref + "), 10)"
+	case "Float":
+		return "strconv.FormatFloat(float64(" + ref + "), 'g', -1, " + codec.bits + ")"
+	}
+	return "strconv.FormatBool(" + ref + ")"
+}
@@ -0,0 +1,311 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// instantiateDirective declares an instantiation of a generic struct to
+// generate code for, e.g. "//cbor:instantiate Box[int]". Go has no
+// methods on a single instantiation, so the generic type's methods
+// dispatch on the type argument to the code for each one.
+const instantiateDirective = "//cbor:instantiate "
+
+// genericSpec is a generic struct with //cbor:instantiate directives.
+// Its methods, on *Name[Args], hand x to the generated code of the
+// instantiation matching its type arguments.
+type genericSpec struct {
+	Name string
+	// Params and Args are the type parameter list as declared, e.g.
+	// "[K comparable, V any]", and as used in a receiver, "[K, V]".
+	Params string
+	Args   string
+	// Instances are the instantiations in directive order.
+	Instances []genericInstance
+	// Recursive and Versioned select the methods to dispatch, which are
+	// those of every instance; see collectFileTypes.
+	Recursive bool
+	Versioned bool
+	// HasMsgsize reports whether any instance has a Msgsize method.
+	HasMsgsize bool
+}
+
+// genericInstance is one instantiation of a genericSpec.
+type genericInstance struct {
+	// Type is the instantiation, e.g. "Box[int]", and Impl the unexported
+	// type defined as it, whose methods are generated like those of any
+	// struct.
+	Type string
+	Impl string
+	// Generic is the name of the generic type.
+	Generic string
+	// HasMsgsize reports whether Impl has a Msgsize method.
+	HasMsgsize bool
+	// spec declares Impl; see instanceSpec.
+	spec *ast.TypeSpec
+}
+
+// fileInstances collects the //cbor:instantiate directives of file,
+// keyed by generic type name. A directive must name a generic struct
+// declared in file with a type argument per type parameter.
+//
+// Each instantiation, such as Box[int], is then referred to by name in
+// the file, so collectFileTypes registers it as a struct of the file,
+// "Box[int]", with the fields of Box for T int: fields of that type are
+// encoded and decoded like those of any struct generated alongside them.
+func fileInstances(fset *token.FileSet, file *ast.File) (map[string][]genericInstance, error) {
+	generics := map[string]*ast.TypeSpec{}
+	ast.Inspect(file, func(n ast.Node) bool {
+		if ts, ok := n.(*ast.TypeSpec); ok && ts.TypeParams != nil {
+			generics[ts.Name.Name] = ts
+		}
+		return true
+	})
+	instances := map[string][]genericInstance{}
+	known := map[string]bool{}
+	for _, cg := range file.Comments {
+		for _, c := range cg.List {
+			text, ok := strings.CutPrefix(c.Text, instantiateDirective)
+			if !ok {
+				continue
+			}
+			text = strings.TrimSpace(text)
+			name, args, err := parseInstance(text)
+			if err != nil {
+				return nil, fmt.Errorf("%s%s: %v", instantiateDirective, text, err)
+			}
+			ts := generics[name]
+			if ts == nil {
+				return nil, fmt.Errorf("%s%s: %s is not a generic type declared in this file", instantiateDirective, text, name)
+			}
+			if _, ok := ts.Type.(*ast.StructType); !ok {
+				return nil, fmt.Errorf("%s%s: %s is not a struct type", instantiateDirective, text, name)
+			}
+			if n := ts.TypeParams.NumFields(); n != len(args) {
+				return nil, fmt.Errorf("%s%s: %s has %d type parameters, not %d", instantiateDirective, text, name, n, len(args))
+			}
+			inst := genericInstance{Type: instanceString(name, args), Generic: name, Impl: instanceImplName(name, args)}
+			if known[inst.Type] {
+				return nil, fmt.Errorf("%s%s: duplicate instantiation", instantiateDirective, text)
+			}
+			known[inst.Type] = true
+			if inst.spec, err = instanceSpec(fset, ts, args, inst.Impl); err != nil {
+				return nil, fmt.Errorf("%s%s: %v", instantiateDirective, text, err)
+			}
+			instances[name] = append(instances[name], inst)
+		}
+	}
+
+	// Refer to the instantiations by name throughout the file.
+	for _, decl := range file.Decls {
+		if gd, ok := decl.(*ast.GenDecl); ok && gd.Tok == token.TYPE {
+			nameInstances(gd, known)
+		}
+	}
+	for _, insts := range instances {
+		for _, inst := range insts {
+			inst.spec.Type = nameInstances(inst.spec.Type, known).(ast.Expr)
+			genericOf[inst.Impl] = inst
+		}
+	}
+	return instances, nil
+}
+
+// nameInstances replaces every instantiation in known under node, such as
+// the type Box[int], by an identifier of the same name, so the generator
+// treats it like the name of any struct of the file. Go code generated
+// with the identifier spells the instantiation as it is.
+func nameInstances(node ast.Node, known map[string]bool) ast.Node {
+	return astutil.Apply(node, nil, func(c *astutil.Cursor) bool {
+		switch n := c.Node().(type) {
+		case *ast.IndexExpr, *ast.IndexListExpr:
+			if s := types.ExprString(n.(ast.Expr)); known[s] {
+				c.Replace(&ast.Ident{NamePos: n.Pos(), Name: s})
+			}
+		}
+		return true
+	})
+}
+
+// parseInstance splits an instantiation such as "Pair[string, int]" into
+// the generic type's name and its type arguments.
+func parseInstance(text string) (string, []ast.Expr, error) {
+	expr, err := parser.ParseExpr(text)
+	if err != nil {
+		return "", nil, err
+	}
+	var x ast.Expr
+	var args []ast.Expr
+	switch e := expr.(type) {
+	case *ast.IndexExpr:
+		x, args = e.X, []ast.Expr{e.Index}
+	case *ast.IndexListExpr:
+		x, args = e.X, e.Indices
+	default:
+		return "", nil, fmt.Errorf("want a generic type with type arguments, e.g. Box[int]")
+	}
+	ident, ok := x.(*ast.Ident)
+	if !ok {
+		return "", nil, fmt.Errorf("want a generic type of this package, not %s", types.ExprString(x))
+	}
+	return ident.Name, args, nil
+}
+
+// instanceString renders generic type name instantiated with args.
+func instanceString(name string, args []ast.Expr) string {
+	parts := make([]string, len(args))
+	for i, a := range args {
+		parts[i] = types.ExprString(a)
+	}
+	return name + "[" + strings.Join(parts, ", ") + "]"
+}
+
+// instanceImplName returns the name of the unexported type generated for
+// generic type name instantiated with args, e.g. "cborBoxInt" for
+// Box[int] and "cborPairStringSliceByte" for Pair[string, []byte].
+func instanceImplName(name string, args []ast.Expr) string {
+	var sb strings.Builder
+	sb.WriteString("cbor" + name)
+	for _, a := range args {
+		s := types.ExprString(a)
+		s = strings.NewReplacer("[]", " slice ", "*", " ptr ", "[", " ", "]", " ", ".", " ").Replace(s)
+		for _, w := range strings.FieldsFunc(s, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+			sb.WriteString(strings.ToUpper(w[:1]) + w[1:])
+		}
+	}
+	return sb.String()
+}
+
+// typeParams returns the type parameter list of ts as declared and as
+// type arguments, along with the parameter names.
+func typeParams(ts *ast.TypeSpec) (params, args string, names []string) {
+	var decl []string
+	for _, f := range ts.TypeParams.List {
+		var group []string
+		for _, n := range f.Names {
+			group = append(group, n.Name)
+			names = append(names, n.Name)
+		}
+		decl = append(decl, strings.Join(group, ", ")+" "+types.ExprString(f.Type))
+	}
+	return "[" + strings.Join(decl, ", ") + "]", "[" + strings.Join(names, ", ") + "]", names
+}
+
+// instanceSpec returns the declaration of impl: generic struct ts with
+// every type parameter replaced by its argument in args. It is generated
+// like any struct; its fields have the same names, tags and, for this
+// instantiation, types as those of ts.
+func instanceSpec(fset *token.FileSet, ts *ast.TypeSpec, args []ast.Expr, impl string) (*ast.TypeSpec, error) {
+	_, _, names := typeParams(ts)
+	subst := make(map[string]ast.Expr, len(names))
+	for i, n := range names {
+		subst[n] = args[i]
+	}
+	// Round-trip the struct through its source for a copy to rewrite.
+	var src bytes.Buffer
+	if err := format.Node(&src, fset, ts.Type); err != nil {
+		return nil, err
+	}
+	expr, err := parser.ParseExpr(src.String())
+	if err != nil {
+		return nil, err
+	}
+	st := astutil.Apply(expr, func(c *astutil.Cursor) bool {
+		if ident, ok := c.Node().(*ast.Ident); ok {
+			switch c.Parent().(type) {
+			case *ast.Field:
+				if c.Name() == "Names" {
+					return false
+				}
+			case *ast.SelectorExpr:
+				if c.Name() == "Sel" {
+					return false
+				}
+			}
+			if arg, ok := subst[ident.Name]; ok {
+				c.Replace(arg)
+			}
+		}
+		return true
+	}, nil).(*ast.StructType)
+	return &ast.TypeSpec{Name: ast.NewIdent(impl), Type: st}, nil
+}
+
+// genericOf maps the Impl of each instantiation generated in the current
+// run to the instantiation.
+var genericOf = map[string]genericInstance{}
+
+// baseTypeName returns the generic type name for the Impl of one of its
+// instantiations, and name itself otherwise.
+func baseTypeName(name string) string {
+	if inst, ok := genericOf[name]; ok {
+		return inst.Generic
+	}
+	return name
+}
+
+// expandInstances returns specs with each generic struct replaced by the
+// declarations of its instantiations in instances, and the genericSpec of
+// each such struct. Other generic types are left out: the generator has
+// no code for a type parameter on its own.
+func expandInstances(specs []ast.Spec, instances map[string][]genericInstance) ([]ast.Spec, []genericSpec) {
+	var out []ast.Spec
+	var gens []genericSpec
+	for _, spec := range specs {
+		ts, ok := spec.(*ast.TypeSpec)
+		if !ok || ts.TypeParams == nil {
+			out = append(out, spec)
+			continue
+		}
+		insts := instances[ts.Name.Name]
+		if len(insts) == 0 {
+			continue
+		}
+		g := genericSpec{Name: ts.Name.Name, Instances: insts}
+		g.Params, g.Args, _ = typeParams(ts)
+		_, g.Recursive = recursiveStructs[insts[0].Impl]
+		_, g.Versioned = versionedStructs[insts[0].Impl]
+		for _, inst := range insts {
+			out = append(out, inst.spec)
+		}
+		gens = append(gens, g)
+	}
+	return out, gens
+}
+
+// generatedGenerics returns gens with only the instances among structs,
+// the generated structs, and fills in what their code provides. A generic
+// struct with no instance left has no methods to dispatch to.
+func generatedGenerics(gens []genericSpec, structs []structSpec) []genericSpec {
+	byName := make(map[string]*structSpec, len(structs))
+	for i := range structs {
+		byName[structs[i].Name] = &structs[i]
+	}
+	var out []genericSpec
+	for _, g := range gens {
+		var insts []genericInstance
+		for _, inst := range g.Instances {
+			if ss, ok := byName[inst.Impl]; ok {
+				inst.HasMsgsize = ss.MsgSizeExpr != ""
+				g.HasMsgsize = g.HasMsgsize || inst.HasMsgsize
+				insts = append(insts, inst)
+			}
+		}
+		if len(insts) > 0 {
+			// The key tables are the same for every instantiation.
+			byName[insts[0].Impl].KeysName = g.Name
+			g.Instances = insts
+			out = append(out, g)
+		}
+	}
+	return out
+}
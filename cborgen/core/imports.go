@@ -0,0 +1,154 @@
+package core
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+// runtimePath is the import path of the runtime package generated code
+// calls.
+const runtimePath = "github.com/delaneyj/cbor/runtime"
+
+// importSpec is one import of the generated file; Name is empty for an
+// import under the package's own name.
+type importSpec struct {
+	Name string
+	Path string
+}
+
+// fileImports returns the name generated code calls the runtime by and
+// the imports of the generated file for file. The runtime keeps the name
+// file imports it by, and is otherwise "cbor", or "cborrt" when file
+// uses "cbor" for another package such as fxamacker/cbor. Every other
+// named or plain import of file is repeated with its name, so field types
+// from other packages resolve to the packages, and aliases, the source
+// uses; goimports then drops those the generated code does not need.
+// Blank and dot imports are left out.
+func fileImports(file *ast.File) (string, []importSpec) {
+	alias := ""
+	taken := map[string]bool{}
+	var specs []importSpec
+	for _, is := range file.Imports {
+		path, err := strconv.Unquote(is.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := ""
+		if is.Name != nil {
+			name = is.Name.Name
+		}
+		if name == "_" || name == "." {
+			continue
+		}
+		if path == runtimePath {
+			if alias == "" {
+				alias = name
+				if alias == "" {
+					alias = "cbor"
+				}
+			}
+			continue
+		}
+		if name != "" {
+			taken[name] = true
+		} else {
+			taken[assumedPackageName(path)] = true
+		}
+		specs = append(specs, importSpec{Name: name, Path: path})
+	}
+	if alias == "" {
+		alias = "cbor"
+		if taken[alias] {
+			alias = "cborrt"
+		}
+	}
+	return alias, append([]importSpec{{Name: alias, Path: runtimePath}}, specs...)
+}
+
+// assumedPackageName returns the name a package imported from path is
+// taken to have, as goimports assumes it: the last path element without
+// a major version suffix (".../cbor/v2", "gopkg.in/yaml.v3"), a "go-"
+// prefix or anything from a '.' or '-' on.
+func assumedPackageName(path string) string {
+	elems := strings.Split(path, "/")
+	name := elems[len(elems)-1]
+	if len(elems) > 1 && isMajorVersion(name) {
+		name = elems[len(elems)-2]
+	}
+	name = strings.TrimPrefix(name, "go-")
+	if i := strings.IndexAny(name, ".-"); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+// isMajorVersion reports whether elem is a major version suffix such as
+// "v2".
+func isMajorVersion(elem string) bool {
+	if len(elem) < 2 || elem[0] != 'v' {
+		return false
+	}
+	_, err := strconv.Atoi(elem[1:])
+	return err == nil
+}
+
+// stdPackages are the standard library packages whose types the
+// generator recognizes by package name, keyed by import path.
+var stdPackages = map[string]string{
+	"time":          "time",
+	"net":           "net",
+	"net/netip":     "netip",
+	"net/url":       "url",
+	"encoding/json": "json",
+	"math/big":      "big",
+}
+
+// unaliasStdImports rewrites the types declared in file to refer to the
+// packages of stdPackages by their own names where file imports them
+// under another, so that a field of type stdtime.Time from
+// `import stdtime "time"` is encoded as a time.Time. Imports whose name
+// file already uses for another package keep their alias.
+func unaliasStdImports(file *ast.File) {
+	used := map[string]bool{}
+	for _, is := range file.Imports {
+		path, _ := strconv.Unquote(is.Path.Value)
+		if is.Name != nil {
+			used[is.Name.Name] = true
+		} else {
+			used[assumedPackageName(path)] = true
+		}
+	}
+	renames := map[string]string{}
+	for _, is := range file.Imports {
+		path, _ := strconv.Unquote(is.Path.Value)
+		name, ok := stdPackages[path]
+		if !ok || is.Name == nil || is.Name.Name == name || is.Name.Name == "_" || is.Name.Name == "." || used[name] {
+			continue
+		}
+		used[name] = true
+		renames[is.Name.Name] = name
+		is.Name = nil
+	}
+	if len(renames) == 0 {
+		return
+	}
+	for _, decl := range file.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		ast.Inspect(gd, func(n ast.Node) bool {
+			if sel, ok := n.(*ast.SelectorExpr); ok {
+				if pkg, ok := sel.X.(*ast.Ident); ok {
+					if name, ok := renames[pkg.Name]; ok {
+						pkg.Name = name
+					}
+				}
+				return false
+			}
+			return true
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// fieldNameCase is the --namecase transform applied to Go field names
+// that have no explicit key in a cbor or json tag; empty keeps them as is.
+var fieldNameCase = ""
+
+// nameCases lists the supported --namecase values.
+var nameCases = map[string]struct{}{"snake": {}, "camel": {}, "kebab": {}, "lower": {}}
+
+// checkNameCase reports an error for an unknown --namecase value.
+func checkNameCase(c string) error {
+	if c == "" {
+		return nil
+	}
+	if _, ok := nameCases[c]; !ok {
+		return fmt.Errorf("unknown namecase %q (want snake, camel, kebab or lower)", c)
+	}
+	return nil
+}
+
+// applyNameCase derives a map key from the Go identifier name per c.
+//
+// The name is first split into words:
+//   - an upper-case letter after a lower-case letter or digit starts a
+//     word ("userName" -> user|Name, "Base64Data" -> Base64|Data);
+//   - in a run of upper-case letters, the last one starts a word when a
+//     lower-case letter follows, so acronyms stay whole
+//     ("JSONData" -> JSON|Data, "ConfigJSON" -> Config|JSON);
+//   - digits stay with the word before them ("RTT2" -> RTT2);
+//   - underscores separate words and are dropped.
+//
+// snake and kebab join the lower-cased words with "_" and "-", lower
+// joins them with nothing, and camel lower-cases the first word and
+// title-cases the rest ("ConfigJSON" -> "configJson", "ID" -> "id").
+func applyNameCase(name, c string) string {
+	if c == "" {
+		return name
+	}
+	words := splitWords(name)
+	for i, w := range words {
+		w = strings.ToLower(w)
+		if c == "camel" && i > 0 {
+			r, n := utf8.DecodeRuneInString(w)
+			w = string(unicode.ToUpper(r)) + w[n:]
+		}
+		words[i] = w
+	}
+	switch c {
+	case "snake":
+		return strings.Join(words, "_")
+	case "kebab":
+		return strings.Join(words, "-")
+	default:
+		return strings.Join(words, "")
+	}
+}
+
+// splitWords splits a Go identifier into words as described on
+// applyNameCase.
+func splitWords(name string) []string {
+	var words []string
+	rs := []rune(name)
+	start := 0
+	flush := func(end int) {
+		if end > start {
+			words = append(words, string(rs[start:end]))
+		}
+	}
+	for i, r := range rs {
+		if r == '_' {
+			flush(i)
+			start = i + 1
+			continue
+		}
+		if i == start || !unicode.IsUpper(r) {
+			continue
+		}
+		prev := rs[i-1]
+		nextLower := i+1 < len(rs) && unicode.IsLower(rs[i+1])
+		if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+			flush(i)
+			start = i
+		}
+	}
+	flush(len(rs))
+	return words
+}
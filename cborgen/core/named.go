@@ -0,0 +1,258 @@
+package core
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strings"
+)
+
+// fileNamedTypes holds the named slice and map types of the current
+// input file (e.g. "type Index map[string]uint64"), keyed by type name,
+// mapped to their underlying type.
+var fileNamedTypes = map[string]ast.Expr{}
+
+// fileScalarTypes maps the named scalar types of the current input file
+// (e.g. "type Letter rune") to their underlying basic type.
+var fileScalarTypes = map[string]string{}
+
+// userCodecTypes holds the types of the input file's package that declare
+// MarshalCBOR or UnmarshalCBOR themselves; see methodTypes.
+var userCodecTypes = map[string]bool{}
+
+// zeroerTypes holds the types of the input file's package that declare
+// IsZero() bool, which decides omitempty and omitzero for their fields.
+var zeroerTypes = map[string]bool{}
+
+// namedSpec describes the methods generated for a named slice, map or
+// scalar type. The snippets are the ones a struct field of the underlying
+// type would get, with the field reference x.Name rewritten to (*x).
+type namedSpec struct {
+	Name string
+	// Under is the basic underlying type of a named scalar type, or
+	// empty for a slice or map.
+	Under           string
+	EncodeBlock     string
+	EncodeExpr      string
+	UsesErr         bool
+	DecodeCaseSafe  string
+	DecodeCaseTrust string
+	ResetStmt       string
+	CloneBody       string
+}
+
+// isNamedContainer reports whether typ is a slice or map type, the
+// underlying types a named top-level type may have.
+func isNamedContainer(typ ast.Expr) bool {
+	switch t := typ.(type) {
+	case *ast.ArrayType:
+		return t.Len == nil
+	case *ast.MapType:
+		return true
+	}
+	return false
+}
+
+// namedTypeSpec builds the namedSpec for the named type name with
+// underlying slice or map type typ. The value is encoded as a bare array
+// or map, exactly like a struct field of type typ minus its key; a type
+// the field code paths cannot encode and decode is an error.
+func namedTypeSpec(name string, typ ast.Expr) (namedSpec, error) {
+	self := func(s string) string {
+		s = strings.ReplaceAll(s, "x."+name, "(*x)")
+		return strings.ReplaceAll(s, "((*x))", "(*x)")
+	}
+	ns := namedSpec{Name: name}
+
+	ns.EncodeBlock = self(encodeBlockForField(name, name, "", typ))
+	if ns.EncodeBlock == "" {
+		ns.EncodeExpr = self(encodeExprForField(name, name, typ))
+	}
+	safe, okSafe := decodeCaseExprSafe(name, name, typ)
+	trusted, okTrusted := decodeCaseExprTrusted(name, name, typ)
+	if (ns.EncodeBlock == "" && ns.EncodeExpr == "") || !okSafe || !okTrusted {
+		return ns, fmt.Errorf("%s: unsupported underlying type %s", name, types.ExprString(typ))
+	}
+	// Null decodes to nil or empty per cbor.NilContainers, like a field.
+	ns.EncodeBlock = self(nilEncodeBlock(name, "", ns.EncodeBlock, ns.EncodeExpr))
+	null := nilDecodeCase(name, typ)
+	safe = null + "\n" + strings.TrimLeft(safe, "\n")
+	trusted = null + "\n" + strings.TrimLeft(trusted, "\n")
+	if plainValueType(typ) {
+		untag := untagCase(typ)
+		safe = untag + "\n" + safe
+		trusted = untag + "\n" + trusted
+	}
+	ns.DecodeCaseSafe = self(wrapDecodeErrors(safe, ""))
+	ns.DecodeCaseTrust = self(trusted)
+	ns.UsesErr = ns.EncodeBlock == "" || strings.Contains(ns.EncodeBlock, "err")
+	stmt, _ := resetStmt(name, typ)
+	ns.ResetStmt = self(stmt)
+	return ns, nil
+}
+
+// namedScalar reports whether ts declares a named scalar type, one whose
+// underlying type is a basic type the generator encodes directly, and
+// returns that type. Aliases are not named types; resolveScalarAliases
+// replaces them with what they stand for.
+func namedScalar(ts *ast.TypeSpec) (string, bool) {
+	ident, ok := ts.Type.(*ast.Ident)
+	if !ok || ts.Assign.IsValid() || ts.TypeParams != nil || userCodecTypes[ts.Name.Name] {
+		return "", false
+	}
+	_, ok = scalarAppenders[ident.Name]
+	return ident.Name, ok
+}
+
+// isCodecMethod reports whether fd declares MarshalCBOR or UnmarshalCBOR.
+func isCodecMethod(fd *ast.FuncDecl) bool {
+	return fd.Name.Name == "MarshalCBOR" || fd.Name.Name == "UnmarshalCBOR"
+}
+
+// isZeroMethod reports whether fd declares IsZero() bool.
+func isZeroMethod(fd *ast.FuncDecl) bool {
+	ft := fd.Type
+	if fd.Name.Name != "IsZero" || ft.Params.NumFields() != 0 || ft.Results.NumFields() != 1 {
+		return false
+	}
+	ident, ok := ft.Results.List[0].Type.(*ast.Ident)
+	return ok && ident.Name == "bool"
+}
+
+// methodTypes returns the names of the types with a method matching
+// isMethod, on a value or pointer receiver, declared in file or in the
+// other source files of its package next to inputPath. Test files and
+// generated files, such as earlier cborgen output, are not consulted; a
+// sibling that fails to parse is skipped.
+func methodTypes(fset *token.FileSet, inputPath string, file *ast.File, isMethod func(*ast.FuncDecl) bool) map[string]bool {
+	names := map[string]bool{}
+	collect := func(f *ast.File) {
+		for _, decl := range f.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok || fd.Recv == nil || len(fd.Recv.List) != 1 || !isMethod(fd) {
+				continue
+			}
+			recv := fd.Recv.List[0].Type
+			if star, ok := recv.(*ast.StarExpr); ok {
+				recv = star.X
+			}
+			if ident, ok := recv.(*ast.Ident); ok {
+				names[ident.Name] = true
+			}
+		}
+	}
+	collect(file)
+	paths, _ := filepath.Glob(filepath.Join(filepath.Dir(inputPath), "*.go"))
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") || filepath.Clean(path) == filepath.Clean(inputPath) || !inBuild(path) {
+			continue
+		}
+		f, err := parser.ParseFile(fset, path, nil, parser.ParseComments|parser.SkipObjectResolution)
+		if err != nil || f.Name.Name != file.Name.Name || ast.IsGenerated(f) {
+			continue
+		}
+		collect(f)
+	}
+	return names
+}
+
+// scalarName returns the basic underlying type of the named scalar type
+// name, or name itself for any other type.
+func scalarName(name string) string {
+	if under, ok := fileScalarTypes[name]; ok {
+		return under
+	}
+	return name
+}
+
+// resolveScalarAliases replaces references to aliases of basic types,
+// such as "type Letter = rune", in the field types of file with the basic
+// type, which is the type they denote.
+func resolveScalarAliases(file *ast.File) {
+	aliases := map[string]string{}
+	for _, decl := range file.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts := spec.(*ast.TypeSpec)
+			if ident, ok := ts.Type.(*ast.Ident); ok && ts.Assign.IsValid() {
+				if _, ok := scalarAppenders[ident.Name]; ok {
+					aliases[ts.Name.Name] = ident.Name
+				}
+			}
+		}
+	}
+	if len(aliases) == 0 {
+		return
+	}
+	var visit func(n ast.Node) bool
+	visit = func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			// Package-qualified types are never file-local.
+			return false
+		case *ast.Field:
+			// Skip field names; only the type can refer to an alias.
+			ast.Inspect(n.Type, visit)
+			return false
+		case *ast.Ident:
+			if under, ok := aliases[n.Name]; ok {
+				n.Name = under
+			}
+		}
+		return true
+	}
+	for _, decl := range file.Decls {
+		if gd, ok := decl.(*ast.GenDecl); ok && gd.Tok == token.TYPE {
+			for _, spec := range gd.Specs {
+				if ts := spec.(*ast.TypeSpec); !ts.Assign.IsValid() {
+					ast.Inspect(ts.Type, visit)
+				}
+			}
+		}
+	}
+}
+
+// namedScalarSpec builds the namedSpec for the named type name with the
+// basic underlying type under: the value is converted to under and
+// encoded and decoded exactly like a field of that type.
+func namedScalarSpec(name, under string) namedSpec {
+	ns := namedSpec{Name: name, Under: under, UsesErr: true}
+	ns.EncodeExpr = runtimeName(scalarAppenders[under]) + "(b, " + under + "(*x)), nil"
+	sr, ok := scalarReaders[under]
+	if !ok {
+		// uint8 and byte are the only scalars without an entry.
+		sr.VarType, sr.ReadFunc = "uint8", "ReadUint8Bytes"
+	}
+	read := func(readFunc string) string {
+		return "var tmp " + sr.VarType + "\n" +
+			"tmp, v, err = " + runtimeName(readFunc) + "(v)\n" +
+			"if err != nil { return b, err }\n" +
+			"*x = " + name + "(tmp)"
+	}
+	safe, trusted := read(sr.ReadFunc), read(sr.ReadFunc)
+	if under == "string" {
+		trusted = read("ReadTrustedStringBytes")
+	}
+	if plainValueType(&ast.Ident{Name: under}) {
+		untag := untagCase(&ast.Ident{Name: under})
+		safe = untag + "\n" + safe
+		trusted = untag + "\n" + trusted
+	}
+	ns.DecodeCaseSafe = wrapDecodeErrors(safe, "")
+	ns.DecodeCaseTrust = trusted
+	switch under {
+	case "string":
+		ns.ResetStmt = `*x = ""`
+	case "bool":
+		ns.ResetStmt = "*x = false"
+	default:
+		ns.ResetStmt = "*x = 0"
+	}
+	return ns
+}
@@ -0,0 +1,277 @@
+package core
+
+import (
+	"go/ast"
+	"go/types"
+	"strconv"
+	"strings"
+)
+
+// nestedType reports whether typ is a container the shape templates do
+// not cover but whose elements are supported at every level: slices and
+// arrays of slices or arrays ([][]byte, [][]int, [2][]T, ...) and
+// string-keyed maps of such containers, byte strings or pointers
+// (map[string][][]byte, map[string][]byte, map[string][]*T). These are
+// encoded and decoded with the nested loops of appendNested and
+// readNested.
+func nestedType(typ ast.Expr) bool {
+	switch t := typ.(type) {
+	case *ast.ArrayType:
+		if _, ok := t.Elt.(*ast.ArrayType); ok {
+			return nestedElemOK(t.Elt)
+		}
+	case *ast.MapType:
+		key, ok := t.Key.(*ast.Ident)
+		if !ok || key.Name != "string" {
+			return false
+		}
+		arr, ok := t.Value.(*ast.ArrayType)
+		if !ok {
+			return false
+		}
+		if ident, ok := arr.Elt.(*ast.Ident); ok && arr.Len == nil && !isByteIdent(ident) {
+			// map[string][]T: encodeMapStrSlice and decodeCaseMapStrSlice.
+			return false
+		}
+		return nestedElemOK(arr)
+	}
+	return false
+}
+
+// nestedElemOK reports whether values of typ can be an element of a
+// nested container: scalars, byte strings, named types with CBOR
+// methods, pointers to them, and slices or arrays of any of these.
+func nestedElemOK(typ ast.Expr) bool {
+	switch t := typ.(type) {
+	case *ast.Ident:
+		if _, ok := interfaceVarType(t); ok {
+			return false
+		}
+		if _, ok := scalarAppenders[t.Name]; ok {
+			return !strings.HasPrefix(t.Name, "complex")
+		}
+		return ast.IsExported(t.Name) || isGenerated(t.Name)
+	case *ast.StarExpr:
+		ident, ok := t.X.(*ast.Ident)
+		if !ok {
+			return false
+		}
+		_, scalar := scalarAppenders[ident.Name]
+		return !scalar && nestedElemOK(ident)
+	case *ast.ArrayType:
+		return isByteString(t) || nestedElemOK(t.Elt)
+	}
+	return false
+}
+
+// isByteString reports whether t is written as a CBOR byte string:
+// []byte or [N]byte, however the byte is spelled (see isByteIdent).
+func isByteString(t *ast.ArrayType) bool {
+	ident, ok := t.Elt.(*ast.Ident)
+	return ok && isByteIdent(ident)
+}
+
+// isByteIdent reports whether ident is byte or uint8, the same type;
+// aliases of either are resolved to it (see resolveScalarAliases).
+func isByteIdent(ident *ast.Ident) bool {
+	return ident.Name == "byte" || ident.Name == "uint8"
+}
+
+func isGenerated(name string) bool {
+	_, ok := generatedStructs[name]
+	return ok
+}
+
+// appendNestedField returns the encode block of a nestedType field.
+func appendNestedField(structName, goName, appendKey string, typ ast.Expr) string {
+	var sb strings.Builder
+	if appendKey != "" {
+		sb.WriteString("b = " + appendKey + "\n")
+	}
+	ref := "x." + goName
+	m, ok := typ.(*ast.MapType)
+	if !ok {
+		sb.WriteString(appendNested(structName, ref, typ, 1))
+		return strings.TrimRight(sb.String(), "\n")
+	}
+	rt := runtimeName
+	elem := appendNested(structName, "v", m.Value, 1)
+	sb.WriteString("if " + rt("CanonicalMapEncode") + " {\n")
+	sb.WriteString("b, err = " + rt("AppendMapDeterministic") + "(b, " + ref + ", " + rt("EncKeyString") +
+		", func(b []byte, v " + types.ExprString(m.Value) + ") ([]byte, error) {\n")
+	sb.WriteString(elem)
+	sb.WriteString("return b, nil\n})\n")
+	sb.WriteString("if err != nil { return b, err }\n")
+	sb.WriteString("} else {\n")
+	sb.WriteString("b = " + rt("AppendMapHeader") + "(b, uint32(len(" + ref + ")))\n")
+	sb.WriteString("for k, v := range " + ref + " {\n")
+	sb.WriteString("b = " + rt("AppendString") + "(b, k)\n")
+	sb.WriteString(elem)
+	sb.WriteString("}\n}")
+	return sb.String()
+}
+
+// appendNested returns statements appending ref, a value of type typ, to
+// b. Each level of slice or array nesting gets its own loop variable,
+// i1, i2, ... by depth.
+func appendNested(structName, ref string, typ ast.Expr, depth int) string {
+	rt := runtimeName
+	switch t := typ.(type) {
+	case *ast.Ident:
+		if fn, ok := scalarAppenders[t.Name]; ok {
+			return "b = " + rt(fn) + "(b, " + ref + ")\n"
+		}
+		return "if b, err = " + ref + "." + marshalCall(structName, t.Name) + "; err != nil { return b, err }\n"
+	case *ast.StarExpr:
+		ident := t.X.(*ast.Ident)
+		return "if " + ref + " == nil {\n" +
+			"b = " + rt("AppendNil") + "(b)\n" +
+			"} else if b, err = " + ref + "." + marshalCall(structName, ident.Name) + "; err != nil { return b, err }\n"
+	case *ast.ArrayType:
+		if isByteString(t) {
+			if t.Len != nil {
+				return "b = " + rt("AppendBytes") + "(b, " + ref + "[:])\n"
+			}
+			return "b = " + rt("AppendBytes") + "(b, " + ref + ")\n"
+		}
+		i := "i" + strconv.Itoa(depth)
+		return "b = " + rt("AppendArrayHeader") + "(b, uint32(len(" + ref + ")))\n" +
+			"for " + i + " := range " + ref + " {\n" +
+			appendNested(structName, ref+"["+i+"]", t.Elt, depth+1) +
+			"}\n"
+	}
+	return ""
+}
+
+// readNestedField returns the decode case of a nestedType field. Safe
+// cases record the index and key of a failing element in the error path
+// and check repeated map keys against cbor.DuplicateMapKeys.
+func readNestedField(goName string, typ ast.Expr, safe bool) string {
+	rt := runtimeName
+	ref := "x." + goName
+	fail := func(e string) string { return "return b, " + e }
+	m, ok := typ.(*ast.MapType)
+	if !ok {
+		return readNested(ref, typ, 1, safe, fail)
+	}
+	i := "i" + fieldIdent(goName)
+	var sb strings.Builder
+	sb.WriteString("var sz uint32\n")
+	sb.WriteString("sz, v, err = " + rt("ReadMapHeaderBytes") + "(v)\n")
+	sb.WriteString("if err != nil { return b, err }\n")
+	sb.WriteString("if " + ref + " == nil && sz > 0 {\n" + ref + " = make(" + types.ExprString(m) + ", sz)\n")
+	sb.WriteString("} else if " + ref + " != nil {\nclear(" + ref + ")\n}\n")
+	sb.WriteString("for " + i + " := uint32(0); " + i + " < sz; " + i + "++ {\n")
+	sb.WriteString("var key string\n")
+	sb.WriteString("key, v, err = " + stringReader(safe) + "(v)\n")
+	sb.WriteString("if err != nil { return b, err }\n")
+	if safe {
+		sb.WriteString("if _, dup := " + ref + "[key]; dup {\n")
+		sb.WriteString("var skip bool\n")
+		sb.WriteString("if v, skip, err = " + rt("SkipDuplicate") + "(v); err != nil { return b, " + rt("WrapDecodeKey") + "(err, key) }\n")
+		sb.WriteString("if skip { continue }\n}\n")
+		fail = func(e string) string { return "return b, " + rt("WrapDecodeKey") + "(" + e + ", key)" }
+	}
+	sb.WriteString("var tmp " + types.ExprString(m.Value) + "\n")
+	sb.WriteString(readNested("tmp", m.Value, 1, safe, fail))
+	sb.WriteString(ref + "[key] = tmp\n}")
+	return sb.String()
+}
+
+// readNested returns statements decoding v into ref, an addressable
+// value of type typ. fail turns an error expression into the statement
+// returning it, so each enclosing level can add its index to the path.
+// Slices reuse the capacity of ref like the shape templates do.
+func readNested(ref string, typ ast.Expr, depth int, safe bool, fail func(string) string) string {
+	rt := runtimeName
+	d := strconv.Itoa(depth)
+	unmarshal := func(name string) string {
+		switch {
+		case !isGenerated(name):
+			return "UnmarshalCBOR(v)"
+		case safe:
+			return "DecodeInterned(v, in)"
+		}
+		return "DecodeTrusted(v)"
+	}
+	switch t := typ.(type) {
+	case *ast.Ident:
+		if t.Name == "string" {
+			return ref + ", v, err = " + stringReader(safe) + "(v)\n" +
+				"if err != nil { " + fail("err") + " }\n"
+		}
+		if r, ok := scalarReaders[t.Name]; ok {
+			return ref + ", v, err = " + rt(r.ReadFunc) + "(v)\n" +
+				"if err != nil { " + fail("err") + " }\n"
+		}
+		if isByteIdent(t) {
+			return ref + ", v, err = " + rt("ReadUint8Bytes") + "(v)\n" +
+				"if err != nil { " + fail("err") + " }\n"
+		}
+		if isGenerated(t.Name) {
+			return ref + ".resetCBOR()\n" +
+				"v, err = " + ref + "." + unmarshal(t.Name) + "\n" +
+				"if err != nil { " + fail("err") + " }\n"
+		}
+		return "var tmp" + d + " " + t.Name + "\n" +
+			"v, err = (&tmp" + d + ")." + unmarshal(t.Name) + "\n" +
+			"if err != nil { " + fail("err") + " }\n" +
+			ref + " = tmp" + d + "\n"
+	case *ast.StarExpr:
+		ident := t.X.(*ast.Ident)
+		reuse := ""
+		if isGenerated(ident.Name) {
+			reuse = " else {\n" + ref + ".resetCBOR()\n}"
+		}
+		return "if " + rt("IsNilOrUndefined") + "(v) {\n" +
+			"v = v[1:]\n" +
+			ref + " = nil\n" +
+			"} else {\n" +
+			"if " + ref + " == nil {\n" + ref + " = new(" + ident.Name + ")\n}" + reuse + "\n" +
+			"v, err = " + ref + "." + unmarshal(ident.Name) + "\n" +
+			"if err != nil { " + fail("err") + " }\n" +
+			"}\n"
+	case *ast.ArrayType:
+		if isByteString(t) {
+			if t.Len == nil {
+				return ref + ", v, err = " + rt("ReadBytesBytes") + "(v, nil)\n" +
+					"if err != nil { " + fail("err") + " }\n"
+			}
+			tmp := "tmp" + d
+			return "var " + tmp + " []byte\n" +
+				tmp + ", v, err = " + rt("ReadBytesBytes") + "(v, nil)\n" +
+				"if err != nil { " + fail("err") + " }\n" +
+				"if len(" + tmp + ") != len(" + ref + ") { " +
+				fail(rt("ArrayError")+"{Wanted: uint32(len("+ref+")), Got: uint32(len("+tmp+"))}") + " }\n" +
+				"copy(" + ref + "[:], " + tmp + ")\n"
+		}
+		i, n := "i"+d, "n"+d
+		inner := fail
+		if safe {
+			inner = func(e string) string { return fail(rt("WrapDecodeIndex") + "(" + e + ", " + i + ")") }
+		}
+		var sb strings.Builder
+		sb.WriteString("var " + n + " uint32\n")
+		if t.Len != nil {
+			sb.WriteString(n + ", v, err = " + rt("ReadArrayHeaderBytes") + "(v)\n")
+			sb.WriteString("if err != nil { " + fail("err") + " }\n")
+			sb.WriteString("if " + n + " != uint32(len(" + ref + ")) { " +
+				fail(rt("ArrayError")+"{Wanted: uint32(len("+ref+")), Got: "+n+"}") + " }\n")
+		} else {
+			indef := "indef" + d
+			sb.WriteString("var " + indef + " bool\n")
+			sb.WriteString(n + ", " + indef + ", v, err = " + rt("ReadArraySizeBytes") + "(v)\n")
+			sb.WriteString("if err != nil { " + fail("err") + " }\n")
+			sb.WriteString("if cap(" + ref + ") >= int(" + n + ") {\n" + ref + " = " + ref + "[:" + n + "]\n")
+			sb.WriteString("} else {\n" + ref + " = make(" + types.ExprString(t) + ", " + n + ")\n}\n")
+		}
+		sb.WriteString("for " + i + " := range " + ref + " {\n")
+		sb.WriteString(readNested(ref+"["+i+"]", t.Elt, depth+1, safe, inner))
+		sb.WriteString("}\n")
+		if t.Len == nil {
+			sb.WriteString("if indef" + d + " {\nv = v[1:] // break\n}\n")
+		}
+		return sb.String()
+	}
+	return ""
+}
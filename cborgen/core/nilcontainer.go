@@ -0,0 +1,49 @@
+package core
+
+import (
+	"go/ast"
+	"strings"
+)
+
+// nilCheck returns the condition under which the slice or map ref is
+// written as null: it is nil and cbor.NilContainers is cbor.NilAsNull.
+func nilCheck(ref string) string {
+	return ref + " == nil && " + runtimeName("NilContainers") + " == " + runtimeName("NilAsNull")
+}
+
+// nilEncodeBlock returns the encode block of slice or map field goName
+// with a nil value written as null per cbor.NilContainers; block and expr
+// are the field's EncodeBlock and EncodeExpr. A block writes the key
+// itself, so the null branch repeats it.
+func nilEncodeBlock(goName, appendKey, block, expr string) string {
+	var sb strings.Builder
+	key := ""
+	if appendKey != "" {
+		key = "b = " + appendKey + "\n"
+	}
+	if block == "" {
+		sb.WriteString(key)
+		key = ""
+	}
+	sb.WriteString("if " + nilCheck("x."+goName) + " {\n")
+	sb.WriteString(key + "b = " + runtimeName("AppendNil") + "(b)\n")
+	sb.WriteString("} else {\n")
+	if block != "" {
+		sb.WriteString(strings.Trim(block, "\n") + "\n")
+	} else {
+		sb.WriteString("b, err = " + expr + "\n")
+		sb.WriteString("if err != nil { return b, err }\n")
+	}
+	sb.WriteString("}")
+	return sb.String()
+}
+
+// nilDecodeCase renders decodeCaseNull for slice or map field goName.
+func nilDecodeCase(goName string, typ ast.Expr) string {
+	read := "NullSlice"
+	if _, ok := typ.(*ast.MapType); ok {
+		read = "NullMap"
+	}
+	data := decodeCaseTemplateData{Field: goName, ReadFunc: runtimeName(read)}
+	return strings.TrimLeft(renderDecodeCase("decodeCaseNull", data), "\n")
+}
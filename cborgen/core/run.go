@@ -2,14 +2,19 @@ package core
 
 import (
 	"bytes"
+	"cmp"
 	"fmt"
 	"go/ast"
 	"go/format"
 	"go/parser"
 	"go/token"
+	"go/types"
 	"os"
 	"path/filepath"
 	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"text/template"
 
@@ -24,16 +29,59 @@ import (
 // back to the generic UnmarshalCBOR path.
 var generatedStructs = map[string]struct{}{}
 
-const runtimeAlias = "cbor"
+// recursiveStructs tracks generated struct types that can reach
+// themselves through their fields (directly or via other structs in
+// the same file). Their encoders thread a depth counter so cyclic
+// values fail with cbor.ErrCycleDetected instead of recursing forever.
+var recursiveStructs = map[string]struct{}{}
+
+// versionedStructs tracks generated struct types with sinceversion
+// fields or fields leading to such structs. Their encoders take a
+// cbor.EncodeOptions and pass it on to the structs they contain.
+var versionedStructs = map[string]struct{}{}
+
+// interfaceTypes tracks interface types declared in the current input
+// file. Fields of these types are encoded via AppendInterface (which
+// applies RegisterType tags) and decoded via ReadInterfaceAsBytes.
+var interfaceTypes = map[string]struct{}{}
+
+// fileStructTypes holds the struct declarations of the current input
+// file, keyed by type name.
+var fileStructTypes = map[string]*ast.StructType{}
+
+// fileImportSpecs are the imports of the current output file.
+var fileImportSpecs []importSpec
+
+// runtimeAlias is the name generated code calls the runtime by in the
+// current output file (see fileImports).
+var runtimeAlias = "cbor"
 
 var templateFuncs = template.FuncMap{
-	"rt": runtimeName,
+	"rt":          runtimeName,
+	"ident":       fieldIdent,
+	"constructor": constructorName,
 }
 
 func runtimeName(name string) string {
 	return runtimeAlias + "." + name
 }
 
+// fieldIdent turns a field selector path such as "Opts.Timeout" into a
+// string usable inside a Go identifier.
+func fieldIdent(goName string) string {
+	return strings.ReplaceAll(goName, ".", "_")
+}
+
+// constructorName names the NewTFromCBOR function of type name. An
+// unexported type gets an unexported newTFromCBOR, as it could not be
+// used outside the package anyway.
+func constructorName(name string) string {
+	if !ast.IsExported(name) {
+		return "new" + strings.ToUpper(name[:1]) + name[1:] + "FromCBOR"
+	}
+	return "New" + name + "FromCBOR"
+}
+
 // Options configures how generation runs.
 // Additional switches can be added over time.
 type Options struct {
@@ -42,18 +90,80 @@ type Options struct {
 	// named struct types. Names must match Go type names
 	// exactly (no package qualification).
 	Structs []string
+	// Compat additionally emits, per struct T, a TCompat adapter type
+	// whose MarshalCBOR() ([]byte, error) and UnmarshalCBOR([]byte) error
+	// methods match the fxamacker/cbor Marshaler/Unmarshaler interfaces.
+	Compat bool
+	// Stream additionally emits, per struct, a MarshalCBORStream method
+	// that writes slice fields to a cbor.Encoder as indefinite-length
+	// arrays, one element at a time.
+	Stream bool
+	// NameCase derives the map keys of untagged fields from their Go
+	// names: "snake", "camel", "kebab" or "lower" (see applyNameCase).
+	// Empty keeps Go names unchanged.
+	NameCase string
+	// Clone additionally emits, per type T, a Clone() *T method returning
+	// a deep copy that shares no slices, maps or pointers with the
+	// original (see cloneBody).
+	Clone bool
+	// Bench additionally writes "{output}_bench_test.go" with encode and
+	// decode benchmarks for every generated type (see writeBenchFile).
+	Bench bool
+	// Diag additionally emits, per struct T, a DiagString() string method
+	// rendering x in CBOR diagnostic notation without encoding it first
+	// (see diagFieldStmt).
+	Diag bool
+	// Constructors additionally emits, per type T, a function
+	// NewTFromCBOR(b) (*T, []byte, error) decoding into a new T with the
+	// Safe path.
+	Constructors bool
+	// ExportKeys additionally emits, per map-encoded struct T, the tables
+	// TFieldKeys and TOmitEmptyKeys of its text keys, and TFieldIntKeys
+	// and TOmitEmptyIntKeys of its keyasint keys, for tooling that
+	// inspects the wire schema.
+	ExportKeys bool
+	// IOAdapters additionally emits, per type T, io.WriterTo and
+	// io.ReaderFrom methods writing and reading exactly one encoded T.
+	IOAdapters bool
+	// AllowJSONFallback encodes and decodes fields of types the generator
+	// knows no CBOR codec for (typically types from other packages) with
+	// cbor.AppendJSONFallback and cbor.ReadJSONFallback instead of
+	// cbor.AppendInterface and skipping them. Lossy and slow; a migration
+	// aid only.
+	AllowJSONFallback bool
+	// Tags are extra build tags, as for go build -tags. Other files of
+	// the package are only consulted when their build constraints match,
+	// and Run rejects an input file they exclude.
+	Tags []string
 }
 
 // Run generates CBOR code for a single Go source file.
 // It emits per-struct encode/decode implementations into outputPath.
 func Run(inputPath, outputPath string, opts Options) error {
 	fset := token.NewFileSet()
+	buildTags = opts.Tags
+	ok, err := MatchFile(inputPath, opts.Tags)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%s is excluded by build constraints (tags %q)", inputPath, strings.Join(opts.Tags, ","))
+	}
 	file, err := parser.ParseFile(fset, inputPath, nil, parser.ParseComments)
 	if err != nil {
 		return err
 	}
 
 	pkg := file.Name.Name
+	unaliasStdImports(file)
+	runtimeAlias, fileImportSpecs = fileImports(file)
+	userCodecTypes = methodTypes(fset, inputPath, file, isCodecMethod)
+	zeroerTypes = methodTypes(fset, inputPath, file, isZeroMethod)
+
+	if err := checkNameCase(opts.NameCase); err != nil {
+		return err
+	}
+	fieldNameCase = opts.NameCase
 
 	return generateStructCode(fset, file, outputPath, pkg, opts)
 }
@@ -61,7 +171,8 @@ func Run(inputPath, outputPath string, opts Options) error {
 type fieldSpec struct {
 	GoName          string
 	CBORName        string
-	OmitEmpty       bool
+	OmitEmpty       bool // omitted when ZeroCheck holds (omitempty and/or omitzero)
+	OmitZero        bool
 	ZeroCheck       string
 	DecodeCaseSafe  string
 	DecodeCaseTrust string
@@ -69,17 +180,126 @@ type fieldSpec struct {
 	EncodeExpr      string
 	EncodeBlock     string
 	Ignore          bool
+	// Union encodes an interface field as a {0: typeTag, 1: payload}
+	// discriminated union (tag option "union").
+	Union bool
+	// Inline splices the keys of a struct-typed field into the parent
+	// map (tag option "inline").
+	Inline bool
+	// KeyAsInt writes the field's key as the integer IntKey parsed from
+	// its name (tag option "keyasint") instead of a text string.
+	KeyAsInt bool
+	IntKey   int64
+	// AppendKey is the call appending the field's map key to b.
+	AppendKey string
+	// ResetStmt clears the field in resetCBOR.
+	ResetStmt string
+	// TagOpt is the N of a "tag=N" option, which wraps the field's value
+	// in CBOR tag N; see applyTagOption.
+	TagOpt string
+	// TimeFloat writes a tag=1 time.Time as float64 seconds even when it
+	// has no fractional second (tag option "float").
+	TimeFloat bool
+	// Unit is the u of a "unit=u" option, which encodes a time.Duration
+	// as an integer count of u; see applyUnitOption.
+	Unit string
+	// Aliases are further keys the decoders accept for the field (tag
+	// option "alias=name"); encoders always write CBORName.
+	Aliases []string
+	// AsString writes a number or bool as its text in a text string
+	// (tag option "string"); see applyStringOption.
+	AsString bool
+	// BoolAsInt writes a bool as the integer 0 or 1 (tag option
+	// "boolasint"); see applyBoolIntOption.
+	BoolAsInt bool
+	// Set encodes a map[string]struct{} as an array of its keys (tag
+	// option "set"); see applySetOption.
+	Set bool
+	// AsMap encodes a slice of two-field structs as a map from the first
+	// field to the second (tag option "asmap"); see applyAsMapOption.
+	AsMap bool
+	// Since is the schema version that added the field (tag option
+	// "sinceversion=N"); older versions are encoded without it.
+	Since int
+	// Flatten marks the map[string]any field whose entries share the
+	// struct's map (tag option "flatten"); see structSpec.Flatten.
+	Flatten bool
+	// NilCheck is the condition under which a slice or map field is
+	// written as null, per cbor.NilContainers; see nilCheck.
+	NilCheck string
+	// DiagKey and DiagStmt render the field's key and value in the
+	// appendDiag method generated with Options.Diag.
+	DiagKey  string
+	DiagStmt string
+	// StreamElem appends element x.GoName[i] to b in MarshalCBORStream;
+	// it is empty for fields that are not streamed element by element.
+	StreamElem string
+	// ViaNil is the condition under which a field inlined through an
+	// embedded pointer cannot be reached, such as "x.Base == nil"; the
+	// field is then left out. ViaAlloc allocates those pointers before
+	// the field is decoded.
+	ViaNil   string
+	ViaAlloc string
 }
 
 type structSpec struct {
 	Name        string
 	Fields      []fieldSpec
 	MsgSizeExpr string
+	// MsgSizeVia adds to Msgsize the sizes of fields inlined through
+	// embedded pointers, each guarded by the pointers being set.
+	MsgSizeVia string
 	HasOmit     bool
+	Recursive   bool
+	// Versioned structs have sinceversion fields, or reach such structs
+	// through their fields, and encode through AppendCBORWith.
+	Versioned bool
+	// UsesErr reports whether the MarshalCBOR body needs an err variable;
+	// it is false when every field is written by an error-free block.
+	UsesErr bool
+	// ResetUsesZero reports whether resetCBOR needs a zero value to copy
+	// fields from; it is false when every field is a slice or map.
+	ResetUsesZero bool
+	// HasIntKeys reports whether any field uses keyasint, in which case
+	// the decoders also dispatch on integer keys.
+	HasIntKeys bool
+	// SeenWords is the number of uint64 words in the set of keys a Safe
+	// decoder has read, one bit per field.
+	SeenWords int
+	// ToArray encodes the struct as an array of its field values in
+	// declaration order (key order for dense structs) instead of a map
+	// (see hasStructOption).
+	ToArray bool
+	// CloneBody holds the statements of the Clone method deep-copying
+	// the shallow copy y of x.
+	CloneBody string
+	// ArrayCount holds the statements computing count, the length of a
+	// toarray struct whose trailing fields may be dropped, and for
+	// Presence structs also present, the bitmap.
+	ArrayCount string
+	// Presence writes a toarray struct as a bitmap of its present fields
+	// followed by just those fields (see presenceFields).
+	Presence bool
+	// Flatten is the selector of the map[string]any field tagged
+	// "flatten": its entries are written after the struct's own keys,
+	// and text keys with no field decode into it.
+	Flatten string
+	// FieldKeys lists the quoted text keys and aliases of the fields,
+	// which win over entries of Flatten with the same key.
+	FieldKeys string
+	// Instance is set for the code of an instantiation of generic struct
+	// Generic, e.g. "Box[int]" of "Box": Name is then an unexported type
+	// defined as Instance, which the methods of Generic dispatch to.
+	Instance string
+	Generic  string
+	// KeysName prefixes the key tables generated with Options.ExportKeys:
+	// Name, or Generic on the first instantiation and "" on the others.
+	KeysName string
 }
 
 // generateStructCode finds struct types in the given file and generates
-// simple MarshalCBOR methods for each, honoring cbor/json tags.
+// simple MarshalCBOR methods for each, honoring cbor/json tags. Named
+// slice and map types get the same methods, encoding a bare array or map.
 //
 // cbor tag rules:
 //   - if cbor tag present: it wins
@@ -87,6 +307,7 @@ type structSpec struct {
 //   - if both absent, Go field name is used
 func generateStructCode(fset *token.FileSet, file *ast.File, outputPath, pkg string, opts Options) error {
 	var structs []structSpec
+	var named []namedSpec
 	useOmit := false
 
 	var allowed map[string]struct{}
@@ -101,43 +322,107 @@ func generateStructCode(fset *token.FileSet, file *ast.File, outputPath, pkg str
 		}
 	}
 
+	// Register every struct up front so fields may refer to types
+	// declared later in the file (or to their own type) and still
+	// pick the generated fast paths.
+	instances, err := fileInstances(fset, file)
+	if err != nil {
+		return err
+	}
+	collectFileTypes(file, allowed, instances)
+	var generics []genericSpec
+
 	for _, decl := range file.Decls {
 		gd, ok := decl.(*ast.GenDecl)
 		if !ok || gd.Tok != token.TYPE {
 			continue
 		}
-		for _, spec := range gd.Specs {
+		// Generic structs are generated once per instantiation.
+		specs, gens := expandInstances(gd.Specs, instances)
+		for _, spec := range specs {
 			ts, ok := spec.(*ast.TypeSpec)
 			if !ok {
 				continue
 			}
-			st, ok := ts.Type.(*ast.StructType)
-			if !ok {
-				continue
-			}
 			// If a struct allowlist is provided, skip
 			// types that are not explicitly listed.
 			if len(allowed) > 0 {
-				if _, ok := allowed[ts.Name.Name]; !ok {
+				if _, ok := allowed[baseTypeName(ts.Name.Name)]; !ok {
 					continue
 				}
 			}
-			ss := structSpec{Name: ts.Name.Name}
+			if isNamedContainer(ts.Type) {
+				ns, err := namedTypeSpec(ts.Name.Name, ts.Type)
+				if err != nil {
+					return err
+				}
+				if opts.Clone {
+					ns.CloneBody = namedCloneBody(ts.Type)
+				}
+				named = append(named, ns)
+				continue
+			}
+			if under, ok := namedScalar(ts); ok {
+				named = append(named, namedScalarSpec(ts.Name.Name, under))
+				continue
+			}
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+			sopts, err := structOptions(ts.Name.Name, st)
+			if err != nil {
+				return err
+			}
+			ss := structSpec{Name: ts.Name.Name, ToArray: sopts.ToArray}
+			_, ss.Recursive = recursiveStructs[ss.Name]
+			_, ss.Versioned = versionedStructs[ss.Name]
+			ss.KeysName = ss.Name
+			if inst, ok := genericOf[ss.Name]; ok {
+				ss.Instance, ss.Generic, ss.KeysName = inst.Type, inst.Generic, ""
+			}
 			var sizeExprParts []string
-			for _, field := range st.Fields.List {
-				// Skip anonymous fields for now.
-				if len(field.Names) == 0 {
-					continue
+			fields, err := flattenFields(ss.Name, st)
+			if err != nil {
+				return err
+			}
+			if sopts.Dense {
+				if err := orderDenseFields(ss.Name, fields); err != nil {
+					return err
 				}
-				name := field.Names[0].Name
-				// Only exported fields participate by default.
-				if !ast.IsExported(name) {
-					continue
+				ss.ToArray = true
+			}
+			if sopts.Presence {
+				if !ss.ToArray {
+					return fmt.Errorf("%s: presence requires toarray or dense", ss.Name)
 				}
-				fs := resolveFieldSpec(name, field.Tag)
-				if fs.Ignore {
+				ss.Presence = true
+				useOmit = true
+			}
+			var fieldKeys []string
+			for _, ff := range fields {
+				fs, field := ff.spec, ff.field
+				name := fs.GoName
+				if fs.Flatten {
+					if ss.ToArray {
+						return fmt.Errorf("%s.%s: flatten requires a struct encoded as a map", ss.Name, name)
+					}
+					// The entry count is only known at run time.
+					ss.Flatten, ss.HasOmit, ss.UsesErr = name, true, true
+					useOmit = true
 					continue
 				}
+				if !fs.KeyAsInt {
+					fieldKeys = append(fieldKeys, strconv.Quote(fs.CBORName))
+					for _, alias := range fs.Aliases {
+						fieldKeys = append(fieldKeys, strconv.Quote(alias))
+					}
+				}
+				if ss.ToArray {
+					// Fields are positional and carry no keys; see
+					// trimArrayFields for how omitempty applies.
+					fs.AppendKey = ""
+				}
 				if fs.OmitEmpty {
 					if z, ok := zeroCheckExpr(name, field.Type); ok {
 						fs.ZeroCheck = z
@@ -147,45 +432,200 @@ func generateStructCode(fset *token.FileSet, file *ast.File, outputPath, pkg str
 						fs.OmitEmpty = false
 					}
 				}
+				if fs.OmitZero {
+					// omitzero skips the field when it equals its zero
+					// value; combined with omitempty either check omits.
+					z := omitZeroCheckExpr(name, field.Type)
+					if fs.OmitEmpty && z != fs.ZeroCheck {
+						z = "(" + fs.ZeroCheck + ") || (" + z + ")"
+					}
+					fs.ZeroCheck = z
+					fs.OmitEmpty = true
+					useOmit = true
+					ss.HasOmit = true
+				}
+				if fs.Since > 0 {
+					// Encoders for an older schema leave the field out.
+					z := "opts.Omits(" + strconv.Itoa(fs.Since) + ")"
+					if fs.OmitEmpty {
+						z = "(" + fs.ZeroCheck + ") || " + z
+					}
+					fs.ZeroCheck = z
+					fs.OmitEmpty = true
+					useOmit = true
+					ss.HasOmit = true
+				}
+				if fs.ViaNil != "" {
+					// Fields behind a nil embedded pointer are left out.
+					if ss.ToArray {
+						return fmt.Errorf("%s.%s: a field inlined through a pointer requires a struct encoded as a map", ss.Name, name)
+					}
+					z := fs.ViaNil
+					if fs.OmitEmpty {
+						z += " || (" + fs.ZeroCheck + ")"
+					}
+					fs.ZeroCheck = z
+					fs.OmitEmpty = true
+					useOmit = true
+					ss.HasOmit = true
+				}
 				// Accumulate contribution to Msgsize expression where supported.
 				if szExpr, ok := fieldSizeExpr(fs.CBORName, fs.GoName, field.Type); ok {
-					sizeExprParts = append(sizeExprParts, szExpr)
+					if fs.TagOpt != "" {
+						szExpr += " + " + runtimeName("TagSize")
+					}
+					if fs.AsString {
+						szExpr += " + " + runtimeName("NumberStringSize")
+					}
+					if fs.ViaNil != "" {
+						ss.MsgSizeVia += "if !(" + fs.ViaNil + ") {\ns += " + szExpr + "\n}\n"
+					} else {
+						sizeExprParts = append(sizeExprParts, szExpr)
+					}
+				} else if fs.Set {
+					// Like []string, count a prefix per member.
+					sizeExprParts = append(sizeExprParts, fmt.Sprintf("%s + len(%q) + %s + len(x.%s)*%s",
+						runtimeName("StringPrefixSize"), fs.CBORName, runtimeName("ArrayHeaderSize"), fs.GoName, runtimeName("StringPrefixSize")))
 				}
 				if ec, ok := encodeCaseExpr(fs.GoName, field.Type); ok {
 					fs.EncodeCase = ec
 				}
-				fs.EncodeExpr = encodeExprForField(fs.GoName, field.Type)
-				fs.EncodeBlock = encodeBlockForField(ss.Name, fs.GoName, fs.CBORName, field.Type)
-				if dc, ok := decodeCaseExprSafe(ss.Name, fs.GoName, field.Type); ok {
+				fs.EncodeExpr = encodeExprForField(ss.Name, fs.GoName, field.Type)
+				fs.EncodeBlock = encodeBlockForField(ss.Name, fs.GoName, fs.AppendKey, field.Type)
+				if opts.Stream {
+					fs.StreamElem = streamElemExpr(ss.Name, fs.GoName, field.Type)
+				}
+				dc, decodeKnown := decodeCaseExprSafe(ss.Name, fs.GoName, field.Type)
+				// Fallback: a codec registered at run time (cbor.RegisterCodec)
+				// or the type's UnmarshalCBOR method, else skip the value.
+				fallback := renderDecodeCase("decodeCaseCodec", decodeCaseTemplateData{Field: fs.GoName})
+				fs.DecodeCaseSafe, fs.DecodeCaseTrust = fallback, fallback
+				if decodeKnown {
 					fs.DecodeCaseSafe = dc
-				} else {
-					// Fallback: skip the value for unsupported types using template.
-					var skipBuf bytes.Buffer
-					if err := decodeCaseTemplate.ExecuteTemplate(&skipBuf, "decodeCaseSkip", decodeCaseTemplateData{}); err == nil {
-						fs.DecodeCaseSafe = strings.TrimRight(skipBuf.String(), "\n")
-					}
 				}
-
 				if dc, ok := decodeCaseExprTrusted(ss.Name, fs.GoName, field.Type); ok {
 					fs.DecodeCaseTrust = dc
-				} else {
-					var skipBuf bytes.Buffer
-					if err := decodeCaseTemplate.ExecuteTemplate(&skipBuf, "decodeCaseSkip", decodeCaseTemplateData{}); err == nil {
-						fs.DecodeCaseTrust = strings.TrimRight(skipBuf.String(), "\n")
+				}
+				if _, isIface := interfaceVarType(field.Type); opts.AllowJSONFallback && !isIface &&
+					!decodeKnown && fs.EncodeExpr == "" && fs.EncodeBlock == "" {
+					// No known codec: round-trip through the type's JSON methods.
+					fs.EncodeExpr = runtimeName("AppendJSONFallback") + "(b, x." + fs.GoName + ")"
+					fs.DecodeCaseSafe = renderDecodeCase("decodeCaseJSONFallback", decodeCaseTemplateData{Field: fs.GoName})
+					fs.DecodeCaseTrust = fs.DecodeCaseSafe
+				}
+				if varType, ok := interfaceVarType(field.Type); ok && fs.Union {
+					fs.EncodeExpr = runtimeName("AppendUnion") + "(b, x." + fs.GoName + ")"
+					var buf bytes.Buffer
+					if err := decodeCaseTemplate.ExecuteTemplate(&buf, "decodeCaseUnion", decodeCaseTemplateData{Field: fs.GoName, VarType: varType}); err != nil {
+						return err
 					}
+					fs.DecodeCaseSafe = strings.TrimRight(buf.String(), "\n")
+					fs.DecodeCaseTrust = fs.DecodeCaseSafe
 				}
+				if fs.TagOpt != "" || fs.TimeFloat {
+					if err := applyTagOption(ss.Name, &fs, field.Type); err != nil {
+						return err
+					}
+				}
+				if fs.Unit != "" {
+					if err := applyUnitOption(ss.Name, &fs, field.Type); err != nil {
+						return err
+					}
+				}
+				if fs.AsString {
+					if err := applyStringOption(ss.Name, &fs, field.Type); err != nil {
+						return err
+					}
+				}
+				if fs.BoolAsInt {
+					if err := applyBoolIntOption(ss.Name, &fs, field.Type); err != nil {
+						return err
+					}
+				}
+				if fs.Set {
+					if err := applySetOption(ss.Name, &fs, field.Type); err != nil {
+						return err
+					}
+				}
+				if fs.AsMap {
+					if err := applyAsMapOption(ss.Name, &fs, field.Type); err != nil {
+						return err
+					}
+				}
+				if isNamedContainer(field.Type) && decodeKnown && fs.TagOpt == "" && (fs.EncodeBlock != "" || fs.EncodeExpr != "") {
+					// cbor.NilContainers decides between null and empty, both ways.
+					fs.NilCheck = nilCheck("x." + fs.GoName)
+					fs.EncodeBlock = nilEncodeBlock(fs.GoName, fs.AppendKey, fs.EncodeBlock, fs.EncodeExpr)
+					null := nilDecodeCase(fs.GoName, field.Type)
+					fs.DecodeCaseSafe = null + "\n" + strings.TrimLeft(fs.DecodeCaseSafe, "\n")
+					fs.DecodeCaseTrust = null + "\n" + strings.TrimLeft(fs.DecodeCaseTrust, "\n")
+				}
+				if fs.TagOpt == "" && !fs.Union && plainValueType(field.Type) {
+					untag := untagCase(field.Type)
+					fs.DecodeCaseSafe = untag + "\n" + fs.DecodeCaseSafe
+					fs.DecodeCaseTrust = untag + "\n" + fs.DecodeCaseTrust
+				}
+				if fs.ViaAlloc != "" {
+					fs.DecodeCaseSafe = fs.ViaAlloc + strings.TrimLeft(fs.DecodeCaseSafe, "\n")
+					fs.DecodeCaseTrust = fs.ViaAlloc + strings.TrimLeft(fs.DecodeCaseTrust, "\n")
+				}
+				if !ss.ToArray {
+					// Safe decoders apply cbor.DuplicateMapKeys to repeated keys.
+					dup := strings.TrimLeft(renderDecodeCase("decodeCaseDuplicate", decodeCaseTemplateData{Index: len(ss.Fields)}), "\n")
+					fs.DecodeCaseSafe = dup + "\n" + fs.DecodeCaseSafe
+				}
+				fs.DecodeCaseSafe = wrapDecodeErrors(fs.DecodeCaseSafe, fs.CBORName)
+				if opts.Diag {
+					fs.DiagKey = diagKey(fs)
+					fs.DiagStmt = strings.TrimRight(diagFieldStmt(fs, field.Type), "\n")
+				}
+				if fs.EncodeBlock == "" || strings.Contains(fs.EncodeBlock, "err") {
+					ss.UsesErr = true
+				}
+				ss.HasIntKeys = ss.HasIntKeys || fs.KeyAsInt
+				var usesZero bool
+				fs.ResetStmt, usesZero = resetStmt(fs.GoName, field.Type)
+				if fs.ViaNil != "" {
+					// zero has no pointee to copy the field from.
+					if usesZero {
+						fs.ResetStmt = "x." + fs.GoName + " = *new(" + types.ExprString(field.Type) + ")"
+						usesZero = false
+					}
+					fs.ResetStmt = "if !(" + fs.ViaNil + ") {\n" + fs.ResetStmt + "\n}"
+				}
+				ss.ResetUsesZero = ss.ResetUsesZero || usesZero
 				ss.Fields = append(ss.Fields, fs)
 			}
-			if len(ss.Fields) > 0 {
+			switch {
+			case ss.Presence:
+				presenceFields(&ss)
+				sizeExprParts = append(sizeExprParts, runtimeName("BytesPrefixSize")+" + "+strconv.Itoa(presenceLen(len(ss.Fields))))
+			case ss.ToArray:
+				trimArrayFields(&ss)
+				for _, fs := range ss.Fields {
+					if fs.Since > 0 && !fs.OmitEmpty {
+						return fmt.Errorf("%s.%s: sinceversion in a toarray struct requires every later field to be omitempty, omitzero or sinceversion", ss.Name, fs.GoName)
+					}
+				}
+			}
+			ss.FieldKeys = strings.Join(fieldKeys, ", ")
+			if opts.Clone {
+				ss.CloneBody = cloneBody(st)
+			}
+			if len(ss.Fields) > 0 || ss.Flatten != "" {
+				ss.SeenWords = (len(ss.Fields) + 63) / 64
 				generatedStructs[ss.Name] = struct{}{}
-				if len(sizeExprParts) > 0 {
+				ss.MsgSizeVia = strings.TrimSuffix(ss.MsgSizeVia, "\n")
+				if len(sizeExprParts) > 0 || ss.MsgSizeVia != "" {
 					// Map header plus per-field key/value contributions.
-					ss.MsgSizeExpr = runtimeName("MapHeaderSize") + " + " + strings.Join(sizeExprParts, " + ")
+					ss.MsgSizeExpr = strings.Join(append([]string{runtimeName("MapHeaderSize")}, sizeExprParts...), " + ")
 				}
 				structs = append(structs, ss)
 			}
 		}
+		generics = append(generics, gens...)
 	}
+	generics = generatedGenerics(generics, structs)
 
 	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
 		return err
@@ -198,13 +638,35 @@ func generateStructCode(fset *token.FileSet, file *ast.File, outputPath, pkg str
 	defer out.Close()
 
 	data := struct {
-		Package string
-		UseOmit bool
-		Structs []structSpec
+		Package      string
+		Build        string
+		Imports      []importSpec
+		UseOmit      bool
+		Compat       bool
+		Stream       bool
+		Clone        bool
+		Diag         bool
+		Constructors bool
+		ExportKeys   bool
+		IOAdapters   bool
+		Structs      []structSpec
+		Named        []namedSpec
+		Generics     []genericSpec
 	}{
-		Package: pkg,
-		UseOmit: useOmit,
-		Structs: structs,
+		Package:      pkg,
+		Build:        buildConstraint(file),
+		Imports:      fileImportSpecs,
+		UseOmit:      useOmit,
+		Compat:       opts.Compat,
+		Stream:       opts.Stream,
+		Clone:        opts.Clone,
+		Diag:         opts.Diag,
+		Constructors: opts.Constructors,
+		ExportKeys:   opts.ExportKeys,
+		IOAdapters:   opts.IOAdapters,
+		Structs:      structs,
+		Named:        named,
+		Generics:     generics,
 	}
 
 	var buf bytes.Buffer
@@ -222,71 +684,588 @@ func generateStructCode(fset *token.FileSet, file *ast.File, outputPath, pkg str
 		}
 	}
 
-	_, err = out.Write(src)
-	return err
+	if _, err := out.Write(src); err != nil {
+		return err
+	}
+	if opts.Bench {
+		srcDir := filepath.Dir(fset.File(file.Pos()).Name())
+		return writeBenchFile(srcDir, outputPath, pkg, buildConstraint(file), structs)
+	}
+	return nil
+}
+
+// flatField is a field participating in a struct's encoding. For fields
+// inlined from a nested struct, spec.GoName is the selector path from the
+// outer struct, e.g. "Opts.Timeout", and spec.ViaNil and spec.ViaAlloc
+// guard the embedded pointers on that path.
+type flatField struct {
+	spec  fieldSpec
+	field *ast.Field
+}
+
+// flattenFields returns the encoded fields of struct name in declaration
+// order, replacing each `cbor:",inline"` field, and each embedded struct
+// whose tag gives it no key, by the fields of its struct type. A field
+// reached through an embedded (or inline) pointer is left out while the
+// pointer is nil. It reports an error when an inline field is not a
+// struct declared in the same file, when two fields map to the same CBOR
+// key, or when there is more than one `cbor:",flatten"` field.
+func flattenFields(name string, st *ast.StructType) ([]flatField, error) {
+	var out []flatField
+	seen := map[string]string{}
+	flatten := ""
+	var walk func(st *ast.StructType, prefix, viaNil, viaAlloc string, inlining map[string]bool) error
+	walk = func(st *ast.StructType, prefix, viaNil, viaAlloc string, inlining map[string]bool) error {
+		for _, field := range st.Fields.List {
+			var goName string
+			if len(field.Names) > 0 {
+				goName = field.Names[0].Name
+			} else if goName = embeddedStructName(field); goName == "" {
+				// Embedded types other than structs of this file carry
+				// no keys the generator knows of.
+				continue
+			}
+			// Only exported fields participate by default.
+			if !ast.IsExported(goName) {
+				continue
+			}
+			fs, err := resolveFieldSpec(prefix+goName, field.Tag)
+			if err != nil {
+				return fmt.Errorf("%s.%s%s: %w", name, prefix, goName, err)
+			}
+			if fs.Ignore {
+				continue
+			}
+			if len(field.Names) == 0 && !hasTagName(field.Tag) {
+				// Like encoding/json, an embedded struct without a key
+				// of its own is inlined.
+				fs.Inline = true
+			}
+			if fs.Inline {
+				typ := field.Type
+				star, isPtr := typ.(*ast.StarExpr)
+				if isPtr {
+					typ = star.X
+				}
+				ident, _ := typ.(*ast.Ident)
+				var inner *ast.StructType
+				if ident != nil {
+					inner = fileStructTypes[ident.Name]
+				}
+				if inner == nil {
+					return fmt.Errorf("%s.%s: inline requires a struct type declared in the same file", name, fs.GoName)
+				}
+				if inlining[ident.Name] {
+					return fmt.Errorf("%s.%s: inline of %s is recursive", name, fs.GoName, ident.Name)
+				}
+				nilCond, alloc := viaNil, viaAlloc
+				if isPtr {
+					ref := "x." + fs.GoName
+					if nilCond != "" {
+						nilCond += " || "
+					}
+					nilCond += ref + " == nil"
+					alloc += "if " + ref + " == nil {\n" + ref + " = new(" + ident.Name + ")\n}\n"
+				}
+				inlining[ident.Name] = true
+				if err := walk(inner, fs.GoName+".", nilCond, alloc, inlining); err != nil {
+					return err
+				}
+				delete(inlining, ident.Name)
+				continue
+			}
+			fs.ViaNil, fs.ViaAlloc = viaNil, viaAlloc
+			if fs.Flatten {
+				if !isMapStrAny(field.Type) {
+					return fmt.Errorf("%s.%s: flatten requires a map[string]any field", name, fs.GoName)
+				}
+				if viaNil != "" {
+					return fmt.Errorf("%s.%s: flatten cannot be reached through an embedded pointer", name, fs.GoName)
+				}
+				if flatten != "" {
+					return fmt.Errorf("%s: fields %s and %s are both tagged flatten", name, flatten, fs.GoName)
+				}
+				flatten = fs.GoName
+				out = append(out, flatField{spec: fs, field: field})
+				continue
+			}
+			key := strconv.Quote(fs.CBORName)
+			fs.AppendKey = runtimeName("AppendString") + "(b, " + key + ")"
+			if fs.KeyAsInt {
+				n, err := strconv.ParseInt(fs.CBORName, 10, 64)
+				if err != nil {
+					return fmt.Errorf("%s.%s: keyasint name %q is not an integer", name, fs.GoName, fs.CBORName)
+				}
+				fs.IntKey = n
+				key = strconv.FormatInt(n, 10)
+				fs.AppendKey = runtimeName("AppendInt64") + "(b, " + key + ")"
+			}
+			if prev, ok := seen[key]; ok {
+				return fmt.Errorf("%s: fields %s and %s both use CBOR key %s", name, prev, fs.GoName, key)
+			}
+			seen[key] = fs.GoName
+			for _, alias := range fs.Aliases {
+				key := strconv.Quote(alias)
+				if prev, ok := seen[key]; ok {
+					return fmt.Errorf("%s: fields %s and %s both use CBOR key %s", name, prev, fs.GoName, key)
+				}
+				seen[key] = fs.GoName
+			}
+			out = append(out, flatField{spec: fs, field: field})
+		}
+		return nil
+	}
+	if err := walk(st, "", "", "", map[string]bool{name: true}); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// embeddedStructName returns the name of the struct type, declared in the
+// current file, that the embedded field holds or points to, or "" for
+// other embedded fields.
+func embeddedStructName(field *ast.Field) string {
+	typ := field.Type
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	ident, ok := typ.(*ast.Ident)
+	if !ok || fileStructTypes[ident.Name] == nil {
+		return ""
+	}
+	return ident.Name
+}
+
+// hasTagName reports whether tag names the field's key, in its cbor tag
+// or, without one, its json tag.
+func hasTagName(tag *ast.BasicLit) bool {
+	if tag == nil {
+		return false
+	}
+	raw, err := strconv.Unquote(tag.Value)
+	if err != nil {
+		return false
+	}
+	v, ok := reflect.StructTag(raw).Lookup("cbor")
+	if !ok {
+		v = reflect.StructTag(raw).Get("json")
+	}
+	name, _, _ := strings.Cut(v, ",")
+	return name != ""
+}
+
+// isMapStrAny reports whether typ is map[string]any, the type of a
+// flatten field.
+func isMapStrAny(typ ast.Expr) bool {
+	mt, ok := typ.(*ast.MapType)
+	if !ok {
+		return false
+	}
+	key, ok := mt.Key.(*ast.Ident)
+	if !ok || key.Name != "string" {
+		return false
+	}
+	vt, ok := interfaceVarType(mt.Value)
+	return ok && vt == "any"
+}
+
+// structOptions returns the options st carries on a blank field, as in
+//
+//	type Point struct {
+//		_ struct{} `cbor:",toarray"`
+//		X, Y int
+//	}
+func structOptions(name string, st *ast.StructType) (fieldTag, error) {
+	var opts fieldTag
+	for _, field := range st.Fields.List {
+		if len(field.Names) != 1 || field.Names[0].Name != "_" || field.Tag == nil {
+			continue
+		}
+		tag, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			continue
+		}
+		v := reflect.StructTag(tag).Get("cbor")
+		if v == "" {
+			continue
+		}
+		ft, err := parseCBORTag(v)
+		if err == nil {
+			err = checkFieldTag("_", ft)
+		}
+		if err != nil {
+			return opts, fmt.Errorf("%s._: %w", name, err)
+		}
+		opts.ToArray = opts.ToArray || ft.ToArray
+		opts.Dense = opts.Dense || ft.Dense
+		opts.Presence = opts.Presence || ft.Presence
+	}
+	return opts, nil
+}
+
+// orderDenseFields checks that the fields of a struct marked `cbor:",dense"`
+// all use keyasint with the keys 0..N-1, and sorts them by key so the
+// struct can be encoded as an array whose indexes are the keys.
+func orderDenseFields(name string, fields []flatField) error {
+	for _, ff := range fields {
+		if !ff.spec.KeyAsInt {
+			return fmt.Errorf("%s.%s: dense requires every field to use keyasint", name, ff.spec.GoName)
+		}
+		if ff.spec.IntKey < 0 || ff.spec.IntKey >= int64(len(fields)) {
+			return fmt.Errorf("%s.%s: dense keys must be 0..%d, got %d", name, ff.spec.GoName, len(fields)-1, ff.spec.IntKey)
+		}
+	}
+	// Keys are distinct (flattenFields rejects duplicates), so they are
+	// exactly 0..N-1.
+	slices.SortFunc(fields, func(a, b flatField) int { return cmp.Compare(a.spec.IntKey, b.spec.IntKey) })
+	return nil
+}
+
+// trimArrayFields applies omitempty (and omitzero) to a toarray struct.
+// Positions are fixed, so only the run of trailing omit fields may be
+// left out: empty ones at the end shorten the array, which positional
+// decoding reads back as untouched fields. Omit options on any field
+// before that run are dropped and the field is always written.
+func trimArrayFields(ss *structSpec) {
+	start := len(ss.Fields)
+	for start > 0 && ss.Fields[start-1].OmitEmpty {
+		start--
+	}
+	ss.HasOmit = start < len(ss.Fields)
+	for i := range ss.Fields[:start] {
+		ss.Fields[i].OmitEmpty, ss.Fields[i].ZeroCheck = false, ""
+	}
+	if !ss.HasOmit {
+		return
+	}
+	// count is one past the last non-empty field, and each trailing
+	// field is written when its position is below count.
+	var sb strings.Builder
+	sb.WriteString("var count uint32\n\tswitch {")
+	for i := len(ss.Fields) - 1; i >= start; i-- {
+		fs := &ss.Fields[i]
+		fmt.Fprintf(&sb, "\n\tcase !(%s):\n\t\tcount = %d", fs.ZeroCheck, i+1)
+		fs.ZeroCheck = fmt.Sprintf("count <= %d", i)
+	}
+	fmt.Fprintf(&sb, "\n\tdefault:\n\t\tcount = %d\n\t}", start)
+	ss.ArrayCount = sb.String()
+}
+
+// presenceFields applies omitempty (and omitzero) to a presence struct.
+// Any field may be left out: present, a bitmap with bit i (the 1<<(i%8)
+// bit of byte i/8) set for each field written, opens the array, and
+// count is its length. Fields without omit options are always present.
+func presenceFields(ss *structSpec) {
+	n := presenceLen(len(ss.Fields))
+	always := make([]string, n)
+	var count int
+	var sb strings.Builder
+	for i := range ss.Fields {
+		fs := &ss.Fields[i]
+		bit := fmt.Sprintf("0x%02x", 1<<(i%8))
+		if !fs.OmitEmpty {
+			if always[i/8] != "" {
+				always[i/8] += " | "
+			}
+			always[i/8] += bit
+			count++
+			continue
+		}
+		fmt.Fprintf(&sb, "\n\tif !(%s) {\n\t\tpresent[%d] |= %s\n\t\tcount++\n\t}", fs.ZeroCheck, i/8, bit)
+		fs.ZeroCheck = fmt.Sprintf("present[%d]&%s == 0", i/8, bit)
+	}
+	for i := range always {
+		if always[i] == "" {
+			always[i] = "0"
+		}
+	}
+	ss.HasOmit = true
+	ss.ArrayCount = fmt.Sprintf("present := [%d]byte{%s}\n\tcount := uint32(%d)", n, strings.Join(always, ", "), count+1) + sb.String()
+}
+
+// presenceLen returns the length of the presence bitmap of a struct with
+// n fields.
+func presenceLen(n int) int {
+	return (n + 7) / 8
+}
+
+// encodedFields returns the fields of st that participate in encoding,
+// using the same filtering rules as generateStructCode.
+func encodedFields(st *ast.StructType) []*ast.Field {
+	var out []*ast.Field
+	for _, field := range st.Fields.List {
+		if len(field.Names) == 0 || !ast.IsExported(field.Names[0].Name) {
+			continue
+		}
+		// Tag errors are reported by flattenFields.
+		if fs, _ := resolveFieldSpec(field.Names[0].Name, field.Tag); fs.Ignore {
+			continue
+		}
+		out = append(out, field)
+	}
+	return out
+}
+
+// collectFileTypes registers all struct types, instantiations of generic
+// structs and named slice, map and scalar types in file with
+// generatedStructs, records interface declarations in interfaceTypes,
+// records in recursiveStructs the structs whose field graph leads
+// back to themselves, and in versionedStructs those leading to a
+// sinceversion field.
+func collectFileTypes(file *ast.File, allowed map[string]struct{}, instances map[string][]genericInstance) {
+	resolveScalarAliases(file)
+	structTypes := map[string]*ast.StructType{}
+	addStruct := func(name, allowName string, st *ast.StructType) {
+		fileStructTypes[name] = st
+		if len(allowed) > 0 {
+			if _, ok := allowed[allowName]; !ok {
+				return
+			}
+		}
+		if len(encodedFields(st)) == 0 {
+			return
+		}
+		structTypes[name] = st
+		generatedStructs[name] = struct{}{}
+	}
+	for _, decl := range file.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+			if ts.TypeParams != nil {
+				// A generic struct is known by its instantiations alone.
+				for _, inst := range instances[ts.Name.Name] {
+					addStruct(inst.Type, ts.Name.Name, inst.spec.Type.(*ast.StructType))
+				}
+				continue
+			}
+			if _, ok := ts.Type.(*ast.InterfaceType); ok {
+				interfaceTypes[ts.Name.Name] = struct{}{}
+				continue
+			}
+			if isNamedContainer(ts.Type) {
+				fileNamedTypes[ts.Name.Name] = ts.Type
+				if _, ok := allowed[ts.Name.Name]; ok || len(allowed) == 0 {
+					generatedStructs[ts.Name.Name] = struct{}{}
+				}
+				continue
+			}
+			if under, ok := namedScalar(ts); ok {
+				fileScalarTypes[ts.Name.Name] = under
+				if _, ok := allowed[ts.Name.Name]; ok || len(allowed) == 0 {
+					generatedStructs[ts.Name.Name] = struct{}{}
+				}
+				continue
+			}
+			if st, ok := ts.Type.(*ast.StructType); ok {
+				addStruct(ts.Name.Name, ts.Name.Name, st)
+			}
+		}
+	}
+
+	// Edges from each struct to the file-local structs its fields mention.
+	edges := make(map[string][]string, len(structTypes))
+	for name, st := range structTypes {
+		for _, field := range encodedFields(st) {
+			ast.Inspect(field.Type, func(n ast.Node) bool {
+				switch n := n.(type) {
+				case *ast.SelectorExpr:
+					// Package-qualified types are never file-local.
+					return false
+				case *ast.Ident:
+					if _, ok := structTypes[n.Name]; ok {
+						edges[name] = append(edges[name], n.Name)
+					}
+				}
+				return true
+			})
+		}
+	}
+
+	for name := range structTypes {
+		seen := map[string]bool{}
+		stack := append([]string(nil), edges[name]...)
+		for len(stack) > 0 {
+			cur := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if cur == name {
+				recursiveStructs[name] = struct{}{}
+				break
+			}
+			if seen[cur] {
+				continue
+			}
+			seen[cur] = true
+			stack = append(stack, edges[cur]...)
+		}
+	}
+
+	// Structs with sinceversion fields are versioned, and so is every
+	// struct with an edge to a versioned one.
+	clear(versionedStructs)
+	for name, st := range structTypes {
+		for _, field := range encodedFields(st) {
+			if fs, _ := resolveFieldSpec(field.Names[0].Name, field.Tag); fs.Since > 0 {
+				versionedStructs[name] = struct{}{}
+			}
+		}
+	}
+	for changed := true; changed; {
+		changed = false
+		for name := range structTypes {
+			if _, ok := versionedStructs[name]; ok {
+				continue
+			}
+			for _, to := range edges[name] {
+				if _, ok := versionedStructs[to]; ok {
+					versionedStructs[name] = struct{}{}
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	// The methods of a generic struct dispatch to those of each
+	// instantiation, so all of them take the same parameters: if one
+	// instantiation is recursive or versioned, every one is.
+	for _, insts := range instances {
+		for _, set := range []map[string]struct{}{recursiveStructs, versionedStructs} {
+			for _, inst := range insts {
+				if _, ok := set[inst.Type]; ok {
+					for _, inst := range insts {
+						set[inst.Type] = struct{}{}
+						set[inst.Impl] = struct{}{}
+					}
+					break
+				}
+			}
+		}
+	}
+}
+
+// interfaceVarType reports whether typ is an interface type the
+// generator can decode polymorphically, returning the Go type name to
+// assert decoded values to ("any" for empty interfaces).
+func interfaceVarType(typ ast.Expr) (string, bool) {
+	switch t := typ.(type) {
+	case *ast.InterfaceType:
+		if t.Methods == nil || len(t.Methods.List) == 0 {
+			return "any", true
+		}
+	case *ast.Ident:
+		if t.Name == "any" {
+			return "any", true
+		}
+		if _, ok := interfaceTypes[t.Name]; ok {
+			return t.Name, true
+		}
+	}
+	return "", false
+}
+
+// marshalCall returns the method call used to encode a value of type
+// typeName from within structName's encoder. Within a recursive type
+// group the depth-tracking variant is used so cycles are detected.
+// Types generated from this file are appended to b with AppendCBOR, or
+// AppendCBORWith passing on opts when both are versioned; others are
+// only known to implement cbor.Marshaler.
+func marshalCall(structName, typeName string) string {
+	_, versioned := versionedStructs[structName]
+	if _, ok := versionedStructs[typeName]; !ok {
+		versioned = false
+	}
+	if _, ok := recursiveStructs[structName]; ok {
+		if _, ok := recursiveStructs[typeName]; ok {
+			if versioned {
+				return "marshalCBORDepth(b, depth+1, opts)"
+			}
+			return "marshalCBORDepth(b, depth+1)"
+		}
+	}
+	if _, ok := generatedStructs[typeName]; ok {
+		if versioned {
+			return "AppendCBORWith(b, opts)"
+		}
+		return "AppendCBOR(b)"
+	}
+	return "MarshalCBOR(b)"
 }
 
 // resolveFieldSpec applies tag resolution rules:
 // - cbor tag primary
 // - if no cbor tag, use json tag
 // - if both absent, use Go field name
-func resolveFieldSpec(goName string, tag *ast.BasicLit) fieldSpec {
-	fs := fieldSpec{GoName: goName, CBORName: goName}
+// - a tag of exactly "-" drops the field; "-," names it "-"
+// - an empty name (e.g. ",omitempty") keeps the Go field name
+//
+// goName may be a selector path for inlined fields; the default key is
+// derived from its last element, transformed per --namecase. Malformed
+// cbor tags are reported by parseCBORTag.
+func resolveFieldSpec(goName string, tag *ast.BasicLit) (fieldSpec, error) {
+	defName := applyNameCase(goName[strings.LastIndex(goName, ".")+1:], fieldNameCase)
+	fs := fieldSpec{GoName: goName, CBORName: defName}
 	if tag == nil {
-		return fs
+		return fs, nil
 	}
 	raw := tag.Value
 	if len(raw) >= 2 && (raw[0] == '`' && raw[len(raw)-1] == '`') {
 		raw = raw[1 : len(raw)-1]
 	}
 	st := reflect.StructTag(raw)
-	if v, ok := parseTag(st.Get("cbor")); ok {
+	var ft fieldTag
+	if v := st.Get("cbor"); v != "" {
 		if v == "-" {
 			fs.Ignore = true
-			return fs
+			return fs, nil
 		}
-		fs.CBORName, fs.OmitEmpty = splitNameOptions(v)
-		return fs
-	}
-	if v, ok := parseTag(st.Get("json")); ok {
+		var err error
+		if ft, err = parseCBORTag(v); err != nil {
+			return fs, err
+		}
+		if err := checkFieldTag(goName, ft); err != nil {
+			return fs, err
+		}
+	} else if v := st.Get("json"); v != "" {
 		if v == "-" {
 			fs.Ignore = true
-			return fs
-		}
-		fs.CBORName, fs.OmitEmpty = splitNameOptions(v)
-		return fs
-	}
-	return fs
-}
-
-// parseTag returns the raw tag string and whether it was present.
-func parseTag(v string) (string, bool) {
-	if v == "" {
-		return "", false
-	}
-	return v, true
-}
-
-// splitNameOptions splits a tag like "name,omitempty" into name and omitEmpty flag.
-func splitNameOptions(tag string) (string, bool) {
-	parts := strings.Split(tag, ",")
-	name := parts[0]
-	omit := false
-	for _, p := range parts[1:] {
-		if p == "omitempty" {
-			omit = true
+			return fs, nil
 		}
+		ft = parseJSONTag(v)
 	}
-	if name == "" {
-		name = "-"
+	if ft.Name != "" {
+		fs.CBORName = ft.Name
 	}
-	return name, omit
+	fs.OmitEmpty = ft.OmitEmpty
+	fs.OmitZero = ft.OmitZero
+	fs.Union = ft.Union
+	fs.Inline = ft.Inline
+	fs.KeyAsInt = ft.KeyAsInt
+	fs.TagOpt = ft.Tag
+	fs.TimeFloat = ft.Float
+	fs.Unit = ft.Unit
+	fs.Aliases = ft.Aliases
+	fs.AsString = ft.AsString
+	fs.BoolAsInt = ft.BoolAsInt
+	fs.Set = ft.Set
+	fs.AsMap = ft.AsMap
+	fs.Since = ft.Since
+	fs.Flatten = ft.Flatten
+	return fs, nil
 }
 
 type zeroCheckTemplateData struct {
 	Receiver string
 	Field    string
 	Kind     string
+	// Type is the field's type for Kind "comparable".
+	Type string
 }
 
 var zeroCheckTemplate = template.Must(template.New("zero_check").Funcs(templateFuncs).ParseFS(tmplfs.FS, "zero_check.gotmpl"))
@@ -295,6 +1274,51 @@ type decodeCaseTemplateData struct {
 	Field    string
 	VarType  string
 	ReadFunc string
+	// Safe marks a Safe decoder case, whose element errors record their
+	// index or key (see wrapDecodeErrors).
+	Safe bool
+	// StringRead reads the text strings of the case, map keys included
+	// (see stringReader).
+	StringRead string
+	// Unmarshal is the call decoding a nested value held in v:
+	// "UnmarshalCBOR(v)", or "DecodeInterned(v, in)" on the Safe path
+	// for structs generated here so an Interner reaches nested strings.
+	Unmarshal string
+	// InPlace decodes slice elements of a type generated here directly
+	// into the slice after resetCBOR, reusing their storage.
+	InPlace bool
+	// Unit is the time.Duration constant of a "unit=u" option.
+	Unit string
+	// Bits is the bit size passed to the reader of a "string" field and
+	// Conv the field type its result is converted to.
+	Bits string
+	Conv string
+	// Index is the position of the field among the struct's keys, its
+	// bit in the seen set of decodeCaseDuplicate.
+	Index int
+	// Bignum leaves tags 2 and 3 for the integer reader in
+	// decodeCaseUntag.
+	Bignum bool
+}
+
+// reader returns the runtime reader called name, or StringRead for
+// ReadStringBytes.
+func (d *decodeCaseTemplateData) reader(name string) string {
+	if name == "ReadStringBytes" {
+		return d.StringRead
+	}
+	return runtimeName(name)
+}
+
+// stringReader returns the reader of text strings for a decode case.
+// Safe cases go through the (possibly nil) Interner of DecodeInterned;
+// Trusted ones skip UTF-8 validation and may alias the input (see
+// cbor.ZeroCopyStrings).
+func stringReader(safe bool) string {
+	if safe {
+		return "in.ReadStringBytes"
+	}
+	return runtimeName("ReadTrustedStringBytes")
 }
 
 var decodeCaseTemplate = template.Must(template.New("decode_case").Funcs(templateFuncs).ParseFS(tmplfs.FS, "decode_case.gotmpl"))
@@ -302,15 +1326,32 @@ var decodeCaseTemplate = template.Must(template.New("decode_case").Funcs(templat
 type encodeBlockTemplateData struct {
 	StructName string
 	GoField    string
-	CBORName   string
 	FieldRef   string
-	KeyName    string
+	AppendKey  string
 	ElemVar    string
 	AppendFunc string
+	Marshal    string
+	ValType    string
 }
 
 var encodeBlockTemplate = template.Must(template.New("encode_block").Funcs(templateFuncs).ParseFS(tmplfs.FS, "encode_block.gotmpl"))
 
+// resetStmt returns the statement resetCBOR uses to clear field goName:
+// slices are truncated and maps cleared so their storage is reused, and
+// anything else is copied from a zero value of the struct (usesZero).
+func resetStmt(goName string, typ ast.Expr) (stmt string, usesZero bool) {
+	ref := "x." + goName
+	switch t := typ.(type) {
+	case *ast.ArrayType:
+		if t.Len == nil {
+			return ref + " = " + ref + "[:0]", false
+		}
+	case *ast.MapType:
+		return "clear(" + ref + ")", false
+	}
+	return ref + " = zero." + goName, true
+}
+
 // fieldSizeExpr builds a worst-case size expression for a single field
 // with the given CBOR name and Go field name. The returned expression
 // is written in terms of receiver 'x'. It returns ok=false if the type
@@ -323,7 +1364,7 @@ func fieldSizeExpr(cborName, goName string, typ ast.Expr) (expr string, ok bool)
 
 	switch t := typ.(type) {
 	case *ast.Ident:
-		switch t.Name {
+		switch scalarName(t.Name) {
 		case "string":
 			val = rt("StringPrefixSize") + " + len(" + fieldRef + ")"
 		case "bool":
@@ -352,31 +1393,44 @@ func fieldSizeExpr(cborName, goName string, typ ast.Expr) (expr string, ok bool)
 			val = rt("Float32Size")
 		case "float64":
 			val = rt("Float64Size")
+		case "complex64":
+			val = rt("Complex64Size")
+		case "complex128":
+			val = rt("Complex128Size")
 		default:
 			return "", false
 		}
 	case *ast.SelectorExpr:
-		// Support common time-based primitives.
+		// Support common time-based primitives and IP addresses.
 		switch t.Sel.Name {
 		case "Time":
 			val = rt("TimeSize")
 		case "Duration":
 			val = rt("DurationSize")
 		default:
-			return "", false
+			switch ipCodecName(t) {
+			case "IP", "Addr":
+				val = rt("IPSize")
+			case "AddrPort":
+				val = rt("AddrPortSize")
+			default:
+				return "", false
+			}
 		}
 	case *ast.ArrayType:
+		// Slices and fixed-size arrays size the same way; len() of an
+		// array field is a constant.
 		ident, ok := t.Elt.(*ast.Ident)
-		if !ok || t.Len != nil {
+		if !ok {
 			return "", false
 		}
 		// []byte: use bytes prefix + len(slice)
-		if ident.Name == "byte" {
+		if ident.Name == "byte" || ident.Name == "uint8" {
 			val = rt("BytesPrefixSize") + " + len(" + fieldRef + ")"
 		} else {
 			// Other supported scalar slices: approximate as header + len(slice)*elemSize.
 			var elem string
-			switch ident.Name {
+			switch scalarName(ident.Name) {
 			case "string":
 				// Prefix per element; data length is accounted for elsewhere at runtime.
 				elem = rt("StringPrefixSize")
@@ -471,13 +1525,16 @@ func fieldSizeExpr(cborName, goName string, typ ast.Expr) (expr string, ok bool)
 // handling is required. The block is written in terms of receiver 'x'
 // and appends to the buffer 'b', following the MarshalCBOR template
 // style.
-func encodeBlockForField(structName, goName, cborName string, typ ast.Expr) string {
+func encodeBlockForField(structName, goName, appendKey string, typ ast.Expr) string {
+	if nestedType(typ) {
+		return appendNestedField(structName, goName, appendKey, typ)
+	}
 	data := encodeBlockTemplateData{
 		StructName: structName,
 		GoField:    goName,
-		CBORName:   cborName,
 		FieldRef:   "x." + goName,
-		KeyName:    cborName,
+		AppendKey:  appendKey,
+		Marshal:    "MarshalCBOR(b)",
 	}
 
 	rt := runtimeName
@@ -489,11 +1546,13 @@ func encodeBlockForField(structName, goName, cborName string, typ ast.Expr) stri
 		if !okKey {
 			return ""
 		}
+		data.ValType = types.ExprString(t.Value)
 
 		// map[uint64]*T where *T has MarshalCBOR (assumed for exported T).
 		if keyIdent.Name == "uint64" {
 			if starVal, ok := t.Value.(*ast.StarExpr); ok {
 				if ident, ok := starVal.X.(*ast.Ident); ok && ast.IsExported(ident.Name) {
+					data.Marshal = marshalCall(structName, ident.Name)
 					tmplName = "encodeMapUint64PtrMarshaler"
 				}
 			} else if valIdent, ok := t.Value.(*ast.Ident); ok && valIdent.Name == "uint64" {
@@ -541,25 +1600,46 @@ func encodeBlockForField(structName, goName, cborName string, typ ast.Expr) stri
 					tmplName = "encodeMapStrScalar"
 				} else if tmplName == "" && ast.IsExported(valIdent.Name) {
 					// map[string]T where T has MarshalCBOR
+					data.Marshal = marshalCall(structName, valIdent.Name)
 					tmplName = "encodeMapStrValueMarshaler"
 				}
 			} else if starVal, ok := t.Value.(*ast.StarExpr); ok {
 				// map[string]*T where *T has MarshalCBOR
 				if ident, ok := starVal.X.(*ast.Ident); ok && ast.IsExported(ident.Name) {
+					data.Marshal = marshalCall(structName, ident.Name)
 					tmplName = "encodeMapStrPtrMarshaler"
 				}
+			} else if arr, ok := t.Value.(*ast.ArrayType); ok && arr.Len == nil {
+				// map[string][]S for scalar S or []T where T has MarshalCBOR.
+				if ident, ok := arr.Elt.(*ast.Ident); ok && ident.Name != "byte" && ident.Name != "uint8" {
+					if fn, ok := scalarAppenders[ident.Name]; ok {
+						data.AppendFunc = rt(fn)
+						tmplName = "encodeMapStrSlice"
+					} else if ast.IsExported(ident.Name) {
+						data.Marshal = marshalCall(structName, ident.Name)
+						tmplName = "encodeMapStrSlice"
+					}
+				}
 			}
 		}
 
-	case *ast.ArrayType:
-		if t.Len != nil {
-			return ""
+	case *ast.StarExpr:
+		// *S for scalar S: nil is written as null.
+		if ident, ok := t.X.(*ast.Ident); ok {
+			if fn, ok := scalarAppenders[ident.Name]; ok {
+				data.AppendFunc = rt(fn)
+				tmplName = "encodePtrScalar"
+			}
 		}
 
+	case *ast.ArrayType:
+		// Slices and fixed-size arrays [N]T share the same loops; an
+		// array is always written with exactly N elements.
+
 		// Scalar slices: []bool, []int*, []uint*, []float*, []string.
 		if ident, ok := t.Elt.(*ast.Ident); ok {
-			// []byte is encoded as a CBOR byte string, not an array.
-			if ident.Name == "byte" {
+			// []byte and [N]byte are encoded as a CBOR byte string, not an array.
+			if isByteIdent(ident) {
 				break
 			}
 			switch ident.Name {
@@ -598,16 +1678,40 @@ func encodeBlockForField(structName, goName, cborName string, typ ast.Expr) stri
 			}
 		}
 
+		// []I for an interface I: each element goes through
+		// AppendInterface so registered types carry their tag.
+		if _, ok := interfaceVarType(t.Elt); ok {
+			tmplName = "encodeSliceInterface"
+			break
+		}
+
 		// []*T where *T has MarshalCBOR (assumed for exported T).
 		if star, ok := t.Elt.(*ast.StarExpr); ok {
 			if ident, ok := star.X.(*ast.Ident); ok && ast.IsExported(ident.Name) {
 				data.ElemVar = strings.ToLower(string(ident.Name[0]))
+				data.Marshal = marshalCall(structName, ident.Name)
 				tmplName = "encodeSlicePtrMarshaler"
 			}
 		} else if ident, ok := t.Elt.(*ast.Ident); ok && ast.IsExported(ident.Name) {
 			// []T where T has MarshalCBOR.
+			data.Marshal = marshalCall(structName, ident.Name)
 			tmplName = "encodeSliceValueMarshaler"
 		}
+
+		// []cbor.T and []*cbor.T for the runtime codec types: the
+		// methods are called on each element in place, with no boxing.
+		if tmplName == "" {
+			if star, ok := t.Elt.(*ast.StarExpr); ok {
+				if name, ok := runtimeCodecType(star.X); ok {
+					data.ElemVar = strings.ToLower(name[:1])
+					data.Marshal = "MarshalCBOR(b)"
+					tmplName = "encodeSlicePtrMarshaler"
+				}
+			} else if _, ok := runtimeCodecType(t.Elt); ok {
+				data.Marshal = "MarshalCBOR(b)"
+				tmplName = "encodeSliceValueMarshaler"
+			}
+		}
 	}
 
 	if tmplName == "" {
@@ -651,7 +1755,7 @@ func encodeCaseExpr(goName string, typ ast.Expr) (string, bool) {
 		}
 	case *ast.ArrayType:
 		// []byte
-		if ident, ok := t.Elt.(*ast.Ident); ok && ident.Name == "byte" && t.Len == nil {
+		if ident, ok := t.Elt.(*ast.Ident); ok && isByteIdent(ident) && t.Len == nil {
 			return "if err := w.WriteBytes(" + field + "); err != nil { return err }", true
 		}
 	}
@@ -660,6 +1764,7 @@ func encodeCaseExpr(goName string, typ ast.Expr) (string, bool) {
 
 // zeroCheckExpr builds a zero-check expression for a field of the given
 // Go name and type. The expression is written in terms of receiver 'x'.
+// Types with an IsZero() bool method are empty when it returns true.
 // Returns ok=false if the type is not supported for omitempty.
 func zeroCheckExpr(goName string, typ ast.Expr) (expr string, ok bool) {
 	data := zeroCheckTemplateData{
@@ -667,9 +1772,25 @@ func zeroCheckExpr(goName string, typ ast.Expr) (expr string, ok bool) {
 		Field:    goName,
 	}
 
+	if _, ok := interfaceVarType(typ); ok {
+		typ = &ast.InterfaceType{}
+	}
+	if ident, ok := typ.(*ast.Ident); ok && zeroerTypes[ident.Name] {
+		// The type's own IsZero method takes precedence.
+		return "x." + goName + ".IsZero()", true
+	}
+
 	switch t := typ.(type) {
 	case *ast.Ident:
-		switch t.Name {
+		if under, ok := fileNamedTypes[t.Name]; ok {
+			if _, isMap := under.(*ast.MapType); isMap {
+				data.Kind = "map"
+			} else {
+				data.Kind = "slice"
+			}
+			break
+		}
+		switch scalarName(t.Name) {
 		case "string":
 			data.Kind = "string"
 		case "bool":
@@ -677,6 +1798,7 @@ func zeroCheckExpr(goName string, typ ast.Expr) (expr string, ok bool) {
 		case "int", "int8", "int16", "int32", "int64",
 			"uint", "uint8", "uint16", "uint32", "uint64",
 			"float32", "float64",
+			"complex64", "complex128",
 			"byte", "rune":
 			data.Kind = "numeric"
 		default:
@@ -690,47 +1812,159 @@ func zeroCheckExpr(goName string, typ ast.Expr) (expr string, ok bool) {
 		case "Duration":
 			data.Kind = "numeric"
 		default:
+			switch ipCodecName(t) {
+			case "IP":
+				data.Kind = "slice"
+			case "Addr", "AddrPort":
+				data.Kind = "comparable"
+				data.Type = types.ExprString(t)
+			default:
+				// A type from another package counts as empty only if
+				// it has an IsZero method saying so.
+				return runtimeName("IsZeroMethod") + "(&x." + goName + ")", true
+			}
+		}
+	case *ast.StarExpr, *ast.InterfaceType:
+		data.Kind = "ptrOrInterface"
+	case *ast.ArrayType:
+		// Only treat slices (Len == nil) as omitempty-capable.
+		if t.Len == nil {
+			data.Kind = "slice"
+		} else {
 			return "", false
 		}
-	case *ast.StarExpr, *ast.InterfaceType:
-		data.Kind = "ptrOrInterface"
-	case *ast.ArrayType:
-		// Only treat slices (Len == nil) as omitempty-capable.
-		if t.Len == nil {
-			data.Kind = "slice"
-		} else {
-			return "", false
+	case *ast.MapType:
+		data.Kind = "map"
+	default:
+		return "", false
+	}
+
+	var buf bytes.Buffer
+	if err := zeroCheckTemplate.ExecuteTemplate(&buf, "zeroCheck", data); err != nil {
+		return "", false
+	}
+	expr = strings.TrimSpace(buf.String())
+	if expr == "" {
+		return "", false
+	}
+	return expr, true
+}
+
+// omitZeroCheckExpr builds the omitzero condition for a field: true when
+// the field equals its type's zero value. A type of the package with an
+// IsZero method decides for itself. Comparable structs and arrays
+// declared in the input file are compared against a zero literal; other
+// composite types fall back to cbor.IsZeroValue, which uses reflection.
+func omitZeroCheckExpr(goName string, typ ast.Expr) string {
+	field := "x." + goName
+	if ident, ok := typ.(*ast.Ident); ok && zeroerTypes[ident.Name] {
+		return field + ".IsZero()"
+	}
+	switch t := typ.(type) {
+	case *ast.StarExpr, *ast.InterfaceType, *ast.MapType:
+		return field + " == nil"
+	case *ast.ArrayType:
+		if t.Len == nil {
+			return field + " == nil"
+		}
+		if isComparableType(t, map[string]bool{}) {
+			return field + " == (" + types.ExprString(t) + "{})"
+		}
+	case *ast.SelectorExpr:
+		if pkg, ok := t.X.(*ast.Ident); ok && pkg.Name == "time" && t.Sel.Name == "Time" {
+			return field + ".IsZero()"
+		}
+		if pkg, ok := t.X.(*ast.Ident); ok && pkg.Name == "time" && t.Sel.Name == "Duration" {
+			return field + " == 0"
+		}
+		switch ipCodecName(t) {
+		case "IP":
+			return field + " == nil"
+		case "Addr", "AddrPort":
+			// Comparable, so this is the omitempty check.
+			return field + " == (" + types.ExprString(t) + "{})"
+		}
+		// Another package's type may have an IsZero method.
+		return runtimeName("IsZeroAt") + "(&" + field + ")"
+	case *ast.Ident:
+		if _, ok := interfaceVarType(t); ok {
+			return field + " == nil"
+		}
+		if _, ok := fileStructTypes[t.Name]; !ok {
+			// Scalars share the omitempty check.
+			if z, ok := zeroCheckExpr(goName, typ); ok {
+				return z
+			}
+			break
+		}
+		if isComparableType(t, map[string]bool{}) {
+			return field + " == (" + t.Name + "{})"
 		}
-	case *ast.MapType:
-		data.Kind = "map"
-	default:
-		return "", false
 	}
+	return runtimeName("IsZeroValue") + "(" + field + ")"
+}
 
-	var buf bytes.Buffer
-	if err := zeroCheckTemplate.ExecuteTemplate(&buf, "zeroCheck", data); err != nil {
-		return "", false
-	}
-	expr = strings.TrimSpace(buf.String())
-	if expr == "" {
-		return "", false
+// isComparableType reports whether typ is known to support == based on
+// its declaration in the input file. Unknown named types are treated as
+// not comparable.
+func isComparableType(typ ast.Expr, seen map[string]bool) bool {
+	switch t := typ.(type) {
+	case *ast.StarExpr:
+		return true
+	case *ast.ArrayType:
+		return t.Len != nil && isComparableType(t.Elt, seen)
+	case *ast.SelectorExpr:
+		if n := ipCodecName(t); n == "Addr" || n == "AddrPort" {
+			return true
+		}
+		pkg, ok := t.X.(*ast.Ident)
+		return ok && pkg.Name == "time" && t.Sel.Name == "Duration"
+	case *ast.Ident:
+		if _, ok := scalarReaders[t.Name]; ok || t.Name == "byte" || t.Name == "uint8" {
+			return true
+		}
+		st, ok := fileStructTypes[t.Name]
+		if !ok {
+			return false
+		}
+		if seen[t.Name] {
+			return true
+		}
+		seen[t.Name] = true
+		for _, f := range st.Fields.List {
+			if !isComparableType(f.Type, seen) {
+				return false
+			}
+		}
+		return true
 	}
-	return expr, true
+	return false
 }
 
 // decodeCaseExprSafe builds the decode body for the Safe path.
 // It uses the validated, allocating helpers like ReadStringBytes.
 func decodeCaseExprSafe(structName, goName string, typ ast.Expr) (string, bool) {
-	data := decodeCaseTemplateData{Field: goName}
+	data := decodeCaseTemplateData{Field: goName, Safe: true, StringRead: stringReader(true)}
 	tmplName := ""
 	rt := runtimeName
 
+	if varType, ok := interfaceVarType(typ); ok {
+		data.VarType = varType
+		typ = nil
+		tmplName = "decodeCaseInterface"
+	}
+	if nestedType(typ) {
+		return readNestedField(goName, typ, true), true
+	}
+
 	switch t := typ.(type) {
+	case nil:
+		// Interface field, template already selected.
 	case *ast.Ident:
 		switch t.Name {
 		case "string":
 			data.VarType = "string"
-			data.ReadFunc = rt("ReadStringBytes")
+			data.ReadFunc = data.StringRead
 		case "bool":
 			data.VarType = "bool"
 			data.ReadFunc = rt("ReadBoolBytes")
@@ -770,6 +2004,12 @@ func decodeCaseExprSafe(structName, goName string, typ ast.Expr) (string, bool)
 		case "float64":
 			data.VarType = "float64"
 			data.ReadFunc = rt("ReadFloat64Bytes")
+		case "complex64":
+			data.VarType = "complex64"
+			data.ReadFunc = rt("ReadComplex64Bytes")
+		case "complex128":
+			data.VarType = "complex128"
+			data.ReadFunc = rt("ReadComplex128Bytes")
 		default:
 			// Fallback: assume user-defined type with UnmarshalCBOR.
 			data.VarType = t.Name
@@ -785,7 +2025,7 @@ func decodeCaseExprSafe(structName, goName string, typ ast.Expr) (string, bool)
 				switch t.Sel.Name {
 				case "Time":
 					data.VarType = "time.Time"
-					data.ReadFunc = rt("ReadTimeBytes")
+					data.ReadFunc = rt("ReadAnyTimeBytes")
 				case "Duration":
 					data.VarType = "time.Duration"
 					data.ReadFunc = rt("ReadDurationBytes")
@@ -800,8 +2040,16 @@ func decodeCaseExprSafe(structName, goName string, typ ast.Expr) (string, bool)
 				data.VarType = "json.Number"
 				data.ReadFunc = rt("ReadJSONNumberBytes")
 				tmplName = "decodeCaseBasic"
-			case "cbor":
-				if t.Sel.Name == "Raw" || t.Sel.Name == "Number" {
+			case "net", "netip":
+				name := ipCodecName(t)
+				if name == "" {
+					return "", false
+				}
+				data.VarType = types.ExprString(t)
+				data.ReadFunc = rt("Read" + name + "Bytes")
+				tmplName = "decodeCaseBasic"
+			case runtimeAlias:
+				if _, ok := runtimeCodecTypes[t.Sel.Name]; ok {
 					data.VarType = ""
 					tmplName = "decodeCaseUnmarshalField"
 					break
@@ -814,21 +2062,34 @@ func decodeCaseExprSafe(structName, goName string, typ ast.Expr) (string, bool)
 			return "", false
 		}
 	case *ast.ArrayType:
-		// []T containers
+		// [N]T fixed-size arrays
 		if t.Len != nil {
-			return "", false
+			tmplName = fixedArrayDecodeTemplate(&data, t, false)
+			if tmplName == "" {
+				return "", false
+			}
+			break
 		}
+		// []T containers
 		// []byte special case
-		if ident, ok := t.Elt.(*ast.Ident); ok && ident.Name == "byte" {
+		if ident, ok := t.Elt.(*ast.Ident); ok && isByteIdent(ident) {
 			tmplName = "decodeCaseBytes"
 			break
 		}
+		// []I for an interface I: elements must be registered types (or
+		// untagged values ReadInterfaceBytes understands).
+		if varType, ok := interfaceVarType(t.Elt); ok {
+			data.VarType = varType
+			data.ReadFunc = rt("ReadRegisteredAsBytes")
+			tmplName = "decodeCaseSliceInterface"
+			break
+		}
 		// Slice of scalar elements handled via template
 		if ident, ok := t.Elt.(*ast.Ident); ok {
 			switch ident.Name {
 			case "string":
 				data.VarType = "string"
-				data.ReadFunc = rt("ReadStringBytes")
+				data.ReadFunc = data.StringRead
 			case "bool":
 				data.VarType = "bool"
 				data.ReadFunc = rt("ReadBoolBytes")
@@ -884,6 +2145,16 @@ func decodeCaseExprSafe(structName, goName string, typ ast.Expr) (string, bool)
 				tmplName = "decodeCaseSlicePtrStruct"
 				break
 			}
+			if name, ok2 := runtimeCodecType(star.X); ok2 {
+				data.VarType = rt(name)
+				tmplName = "decodeCaseSlicePtrStruct"
+				break
+			}
+		}
+		if name, ok := runtimeCodecType(t.Elt); ok {
+			data.VarType = rt(name)
+			tmplName = "decodeCaseSliceStruct"
+			break
 		}
 		return "", false
 	case *ast.MapType:
@@ -916,7 +2187,7 @@ func decodeCaseExprSafe(structName, goName string, typ ast.Expr) (string, bool)
 			switch valIdent.Name {
 			case "string":
 				data.VarType = "string"
-				data.ReadFunc = rt("ReadStringBytes")
+				data.ReadFunc = data.StringRead
 			case "bool":
 				data.VarType = "bool"
 				data.ReadFunc = rt("ReadBoolBytes")
@@ -973,8 +2244,24 @@ func decodeCaseExprSafe(structName, goName string, typ ast.Expr) (string, bool)
 				break
 			}
 		}
+		// map[string][]T for scalar T or T with UnmarshalCBOR
+		if mapSliceElem(&data, t.Value) {
+			tmplName = "decodeCaseMapStrSlice"
+			break
+		}
 		return "", false
 	case *ast.StarExpr:
+		if isURLPtr(t) {
+			data.VarType = "*url.URL"
+			data.ReadFunc = rt("ReadURLBytes")
+			tmplName = "decodeCaseBasic"
+			break
+		}
+		if varType, readFunc, ok := ptrScalarReader(t); ok {
+			data.VarType, data.ReadFunc = varType, data.reader(readFunc)
+			tmplName = "decodeCasePtrScalar"
+			break
+		}
 		// Pointer to user-defined type with UnmarshalCBOR.
 		if ident, ok := t.X.(*ast.Ident); ok {
 			data.VarType = ident.Name
@@ -986,6 +2273,12 @@ func decodeCaseExprSafe(structName, goName string, typ ast.Expr) (string, bool)
 		return "", false
 	}
 
+	data.Unmarshal = "UnmarshalCBOR(v)"
+	if _, ok := generatedStructs[data.VarType]; ok {
+		data.Unmarshal = "DecodeInterned(v, in)"
+		data.InPlace = true
+	}
+
 	var buf bytes.Buffer
 	if err := decodeCaseTemplate.ExecuteTemplate(&buf, tmplName, data); err != nil {
 		return "", false
@@ -997,15 +2290,210 @@ func decodeCaseExprSafe(structName, goName string, typ ast.Expr) (string, bool)
 	return expr, true
 }
 
+// runtimeCodecTypes lists runtime package types that implement
+// MarshalCBOR/UnmarshalCBOR themselves; fields of these types call the
+// methods directly.
+var runtimeCodecTypes = map[string]struct{}{
+	"Raw":         {},
+	"RawMessage":  {},
+	"Number":      {},
+	"SimpleValue": {},
+	"Decimal":     {},
+}
+
+// runtimeCodecType reports whether expr names one of runtimeCodecTypes,
+// and returns its name.
+func runtimeCodecType(expr ast.Expr) (string, bool) {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return "", false
+	}
+	if pkg, ok := sel.X.(*ast.Ident); !ok || pkg.Name != runtimeAlias {
+		return "", false
+	}
+	_, ok = runtimeCodecTypes[sel.Sel.Name]
+	return sel.Sel.Name, ok
+}
+
+// scalarReaders maps scalar Go type names to the runtime reader used to
+// decode them.
+var scalarReaders = map[string]struct{ VarType, ReadFunc string }{
+	"string":     {"string", "ReadStringBytes"},
+	"bool":       {"bool", "ReadBoolBytes"},
+	"int":        {"int", "ReadIntBytes"},
+	"int64":      {"int64", "ReadInt64Bytes"},
+	"int32":      {"int32", "ReadInt32Bytes"},
+	"rune":       {"int32", "ReadInt32Bytes"},
+	"int16":      {"int16", "ReadInt16Bytes"},
+	"int8":       {"int8", "ReadInt8Bytes"},
+	"uint":       {"uint", "ReadUintBytes"},
+	"uint64":     {"uint64", "ReadUint64Bytes"},
+	"uint32":     {"uint32", "ReadUint32Bytes"},
+	"uint16":     {"uint16", "ReadUint16Bytes"},
+	"float32":    {"float32", "ReadFloat32Bytes"},
+	"float64":    {"float64", "ReadFloat64Bytes"},
+	"complex64":  {"complex64", "ReadComplex64Bytes"},
+	"complex128": {"complex128", "ReadComplex128Bytes"},
+}
+
+// plainValueType reports whether typ gives CBOR tags no meaning, so a
+// tag wrapping its value is handled by cbor.UntagBytes (see
+// SkipUnknownTags): scalars other than complex numbers, time.Duration,
+// slices, arrays and maps.
+func plainValueType(typ ast.Expr) bool {
+	switch t := typ.(type) {
+	case *ast.Ident:
+		switch t.Name {
+		case "complex64", "complex128":
+			return false
+		case "uint8", "byte":
+			return true
+		}
+		_, ok := scalarReaders[t.Name]
+		return ok
+	case *ast.ArrayType, *ast.MapType:
+		return true
+	case *ast.StarExpr:
+		_, _, ok := ptrScalarReader(t)
+		return ok && plainValueType(t.X)
+	case *ast.SelectorExpr:
+		pkg, ok := t.X.(*ast.Ident)
+		return ok && pkg.Name == "time" && t.Sel.Name == "Duration"
+	}
+	return false
+}
+
+// untagCase renders decodeCaseUntag for a field of a plain value type.
+// Integer fields keep bignums (tags 2 and 3), which their readers accept
+// when the value fits.
+func untagCase(typ ast.Expr) string {
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	data := decodeCaseTemplateData{}
+	if ident, ok := typ.(*ast.Ident); ok {
+		switch ident.Name {
+		case "int", "int8", "int16", "int32", "int64", "rune",
+			"uint", "uint8", "uint16", "uint32", "uint64", "byte":
+			data.Bignum = true
+		}
+	}
+	return strings.TrimLeft(renderDecodeCase("decodeCaseUntag", data), "\n")
+}
+
+// scalarAppenders maps scalar Go type names to the runtime Append*
+// helper used to encode them.
+var scalarAppenders = map[string]string{
+	"string":     "AppendString",
+	"bool":       "AppendBool",
+	"int":        "AppendInt",
+	"int64":      "AppendInt64",
+	"int32":      "AppendInt32",
+	"rune":       "AppendInt32",
+	"int16":      "AppendInt16",
+	"int8":       "AppendInt8",
+	"uint":       "AppendUint",
+	"uint64":     "AppendUint64",
+	"uint32":     "AppendUint32",
+	"uint16":     "AppendUint16",
+	"uint8":      "AppendUint8",
+	"byte":       "AppendUint8",
+	"float32":    "AppendFloat32",
+	"float64":    "AppendFloat64",
+	"complex64":  "AppendComplex64",
+	"complex128": "AppendComplex128",
+}
+
+// ptrScalarReader returns the decode template data for a *S field with
+// scalar S, readFunc naming the runtime reader, or ok=false for other
+// types.
+func ptrScalarReader(typ ast.Expr) (varType, readFunc string, ok bool) {
+	star, isStar := typ.(*ast.StarExpr)
+	if !isStar {
+		return "", "", false
+	}
+	ident, isIdent := star.X.(*ast.Ident)
+	if !isIdent {
+		return "", "", false
+	}
+	if ident.Name == "uint8" || ident.Name == "byte" {
+		return "uint8", "ReadUint8Bytes", true
+	}
+	sr, found := scalarReaders[ident.Name]
+	if !found {
+		return "", "", false
+	}
+	return sr.VarType, sr.ReadFunc, true
+}
+
+// fixedArrayDecodeTemplate fills data for a [N]T field and returns the
+// decode template to use, or "" when the element type is unsupported.
+// [N]byte is read from a byte string; other arrays must carry exactly
+// N elements.
+func fixedArrayDecodeTemplate(data *decodeCaseTemplateData, t *ast.ArrayType, trusted bool) string {
+	ident, ok := t.Elt.(*ast.Ident)
+	if !ok {
+		return ""
+	}
+	if ident.Name == "byte" || ident.Name == "uint8" {
+		return "decodeCaseFixedBytes"
+	}
+	if r, ok := scalarReaders[ident.Name]; ok {
+		data.VarType = r.VarType
+		data.ReadFunc = data.reader(r.ReadFunc)
+		return "decodeCaseFixedArrayBasic"
+	}
+	data.VarType = ident.Name
+	if _, ok := generatedStructs[ident.Name]; ok && trusted {
+		return "decodeCaseFixedArrayStructTrusted"
+	}
+	return "decodeCaseFixedArrayStruct"
+}
+
+// mapSliceElem fills data for the []T values of a map[string][]T field
+// and reports whether T is supported: scalars get a ReadFunc, and any
+// other named type is decoded through its Unmarshal call. []byte values
+// are byte strings, not arrays, and are left to other paths.
+func mapSliceElem(data *decodeCaseTemplateData, value ast.Expr) bool {
+	arr, ok := value.(*ast.ArrayType)
+	if !ok || arr.Len != nil {
+		return false
+	}
+	ident, ok := arr.Elt.(*ast.Ident)
+	if !ok || ident.Name == "byte" || ident.Name == "uint8" {
+		return false
+	}
+	if r, ok := scalarReaders[ident.Name]; ok {
+		data.VarType = r.VarType
+		data.ReadFunc = data.reader(r.ReadFunc)
+		return true
+	}
+	data.VarType = ident.Name
+	return true
+}
+
 // decodeCaseExprTrusted builds the decode body for the Trusted path.
-// For strings it uses zero-copy ReadStringZC + UnsafeString; other
-// scalar types share the same helpers as the Safe path.
+// Text strings, including slice elements and map keys and values, are
+// read with ReadTrustedStringBytes, which skips UTF-8 validation and
+// aliases the input while ZeroCopyStrings is set; other scalar types
+// share the same helpers as the Safe path.
 func decodeCaseExprTrusted(structName, goName string, typ ast.Expr) (string, bool) {
-	data := decodeCaseTemplateData{Field: goName}
+	data := decodeCaseTemplateData{Field: goName, StringRead: stringReader(false)}
 	tmplName := ""
 	rt := runtimeName
 
+	if varType, ok := interfaceVarType(typ); ok {
+		data.VarType = varType
+		typ = nil
+		tmplName = "decodeCaseInterface"
+	}
+	if nestedType(typ) {
+		return readNestedField(goName, typ, false), true
+	}
+
 	switch t := typ.(type) {
+	case nil:
+		// Interface field, template already selected.
 	case *ast.MapType:
 		keyIdent, okKey := t.Key.(*ast.Ident)
 		if !okKey {
@@ -1036,7 +2524,7 @@ func decodeCaseExprTrusted(structName, goName string, typ ast.Expr) (string, boo
 			switch valIdent.Name {
 			case "string":
 				data.VarType = "string"
-				data.ReadFunc = rt("ReadStringBytes")
+				data.ReadFunc = data.StringRead
 			case "bool":
 				data.VarType = "bool"
 				data.ReadFunc = rt("ReadBoolBytes")
@@ -1100,6 +2588,13 @@ func decodeCaseExprTrusted(structName, goName string, typ ast.Expr) (string, boo
 				break
 			}
 		}
+		if mapSliceElem(&data, t.Value) {
+			if _, ok := generatedStructs[data.VarType]; ok {
+				data.Unmarshal = "DecodeTrusted(v)"
+			}
+			tmplName = "decodeCaseMapStrSlice"
+			break
+		}
 		return "", false
 
 	case *ast.Ident:
@@ -1146,6 +2641,12 @@ func decodeCaseExprTrusted(structName, goName string, typ ast.Expr) (string, boo
 		case "float64":
 			data.VarType = "float64"
 			data.ReadFunc = rt("ReadFloat64Bytes")
+		case "complex64":
+			data.VarType = "complex64"
+			data.ReadFunc = rt("ReadComplex64Bytes")
+		case "complex128":
+			data.VarType = "complex128"
+			data.ReadFunc = rt("ReadComplex128Bytes")
 		default:
 			// Fallback: user-defined type. If it's a struct we
 			// generated code for, prefer DecodeTrusted. Otherwise,
@@ -1167,7 +2668,7 @@ func decodeCaseExprTrusted(structName, goName string, typ ast.Expr) (string, boo
 				switch t.Sel.Name {
 				case "Time":
 					data.VarType = "time.Time"
-					data.ReadFunc = rt("ReadTimeBytes")
+					data.ReadFunc = rt("ReadAnyTimeBytes")
 				case "Duration":
 					data.VarType = "time.Duration"
 					data.ReadFunc = rt("ReadDurationBytes")
@@ -1186,8 +2687,18 @@ func decodeCaseExprTrusted(structName, goName string, typ ast.Expr) (string, boo
 				if tmplName == "" {
 					tmplName = "decodeCaseBasic"
 				}
-			case "cbor":
-				if t.Sel.Name == "Raw" || t.Sel.Name == "Number" {
+			case "net", "netip":
+				name := ipCodecName(t)
+				if name == "" {
+					return "", false
+				}
+				data.VarType = types.ExprString(t)
+				data.ReadFunc = rt("Read" + name + "Bytes")
+				if tmplName == "" {
+					tmplName = "decodeCaseBasic"
+				}
+			case runtimeAlias:
+				if _, ok := runtimeCodecTypes[t.Sel.Name]; ok {
 					if tmplName == "" {
 						tmplName = "decodeCaseUnmarshalField"
 					}
@@ -1204,17 +2715,27 @@ func decodeCaseExprTrusted(structName, goName string, typ ast.Expr) (string, boo
 		// []T containers (Trusted path uses same scalar readers
 		// but prefers DecodeTrusted for generated struct types).
 		if t.Len != nil {
-			return "", false
+			tmplName = fixedArrayDecodeTemplate(&data, t, true)
+			if tmplName == "" {
+				return "", false
+			}
+			break
 		}
-		if ident, ok := t.Elt.(*ast.Ident); ok && ident.Name == "byte" {
+		if ident, ok := t.Elt.(*ast.Ident); ok && isByteIdent(ident) {
 			tmplName = "decodeCaseBytes"
 			break
 		}
+		if varType, ok := interfaceVarType(t.Elt); ok {
+			data.VarType = varType
+			data.ReadFunc = rt("ReadInterfaceAsBytes")
+			tmplName = "decodeCaseSliceInterface"
+			break
+		}
 		if ident, ok := t.Elt.(*ast.Ident); ok {
 			switch ident.Name {
 			case "string":
 				data.VarType = "string"
-				data.ReadFunc = rt("ReadStringBytes")
+				data.ReadFunc = data.StringRead
 			case "bool":
 				data.VarType = "bool"
 				data.ReadFunc = rt("ReadBoolBytes")
@@ -1277,9 +2798,30 @@ func decodeCaseExprTrusted(structName, goName string, typ ast.Expr) (string, boo
 				}
 				break
 			}
+			if name, ok2 := runtimeCodecType(star.X); ok2 {
+				data.VarType = rt(name)
+				tmplName = "decodeCaseSlicePtrStruct"
+				break
+			}
+		}
+		if name, ok := runtimeCodecType(t.Elt); ok {
+			data.VarType = rt(name)
+			tmplName = "decodeCaseSliceStruct"
+			break
 		}
 		return "", false
 	case *ast.StarExpr:
+		if isURLPtr(t) {
+			data.VarType = "*url.URL"
+			data.ReadFunc = rt("ReadURLBytes")
+			tmplName = "decodeCaseBasic"
+			break
+		}
+		if varType, readFunc, ok := ptrScalarReader(t); ok {
+			data.VarType, data.ReadFunc = varType, data.reader(readFunc)
+			tmplName = "decodeCasePtrScalar"
+			break
+		}
 		// Pointer to user-defined type. If the underlying type is a
 		// generated struct, prefer DecodeTrusted; otherwise fall back
 		// to the UnmarshalCBOR-based pointer path.
@@ -1297,6 +2839,10 @@ func decodeCaseExprTrusted(structName, goName string, typ ast.Expr) (string, boo
 		return "", false
 	}
 
+	if data.Unmarshal == "" {
+		data.Unmarshal = "UnmarshalCBOR(v)"
+	}
+
 	var buf bytes.Buffer
 	if err := decodeCaseTemplate.ExecuteTemplate(&buf, tmplName, data); err != nil {
 		return "", false
@@ -1318,10 +2864,473 @@ var marshalTemplate = template.Must(template.New("marshal.gotmpl").Funcs(templat
 // encodeExprForField returns a concrete encode expression for a field
 // where we want to avoid the generic AppendInterface path. It returns an
 // empty string when the generic path should be used.
-func encodeExprForField(goName string, typ ast.Expr) string {
+// isURLPtr reports whether typ is *url.URL.
+func isURLPtr(typ ast.Expr) bool {
+	star, ok := typ.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := star.X.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && pkg.Name == "url" && sel.Sel.Name == "URL"
+}
+
+// isTimeType reports whether typ is time.Time.
+func isTimeType(typ ast.Expr) bool {
+	sel, ok := typ.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && pkg.Name == "time" && sel.Sel.Name == "Time"
+}
+
+// ipCodecName returns "IP", "Addr" or "AddrPort" when typ is net.IP,
+// netip.Addr or netip.AddrPort, naming its runtime codec, and "" for any
+// other type.
+func ipCodecName(typ ast.Expr) string {
+	sel, ok := typ.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return ""
+	}
+	switch pkg.Name + "." + sel.Sel.Name {
+	case "net.IP":
+		return "IP"
+	case "netip.Addr":
+		return "Addr"
+	case "netip.AddrPort":
+		return "AddrPort"
+	}
+	return ""
+}
+
+// isRawType reports whether typ is cbor.Raw or its alias cbor.RawMessage.
+func isRawType(typ ast.Expr) bool {
+	sel, ok := typ.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && pkg.Name == runtimeAlias && (sel.Sel.Name == "Raw" || sel.Sel.Name == "RawMessage")
+}
+
+// applyTagOption handles the "tag=N" option by replacing the field's
+// encode and decode code with a codec that writes and checks tag N. Only
+// tag and type pairs with a runtime codec are supported:
+//
+//   - tag=0 (RFC 3339 text) on time.Time fields, keeping the UTC offset
+//   - tag=1 (epoch time) on time.Time fields, with "float" forcing the
+//     float64 form
+//   - tag=32 (URI) on string or *url.URL fields
+//   - tag=24 (encoded CBOR data item) on cbor.RawMessage or cbor.Raw fields
+//   - tag=52 or tag=54 (RFC 9164 IP address) on net.IP or netip.Addr
+//     fields, writing 52 for IPv4 and 54 for IPv6 addresses whichever
+//     is given
+//
+// Anything else is a generation error rather than a silently dropped tag.
+func applyTagOption(structName string, fs *fieldSpec, typ ast.Expr) error {
+	if fs.TimeFloat && (fs.TagOpt != "1" || !isTimeType(typ)) {
+		return fmt.Errorf("%s.%s: float requires tag=1 on a time.Time field", structName, fs.GoName)
+	}
+	tag, err := strconv.ParseUint(fs.TagOpt, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%s.%s: tag=%s is not a tag number", structName, fs.GoName, fs.TagOpt)
+	}
+	ident, _ := typ.(*ast.Ident)
+	switch {
+	case tag == 32 && ident != nil && ident.Name == "string":
+		fs.EncodeBlock = ""
+		fs.EncodeExpr = runtimeName("AppendURI") + "(b, x." + fs.GoName + "), nil"
+		data := decodeCaseTemplateData{Field: fs.GoName, VarType: "string", ReadFunc: runtimeName("ReadURIStringBytes")}
+		fs.DecodeCaseTrust = renderDecodeCase("decodeCaseBasic", data)
+		// The Safe path also rejects text that does not parse as a URI.
+		data.ReadFunc = runtimeName("ReadValidURIStringBytes")
+		fs.DecodeCaseSafe = renderDecodeCase("decodeCaseBasic", data)
+	case tag == 32 && isURLPtr(typ):
+		// *url.URL fields are always written as tag 32.
+	case tag == 0 && isTimeType(typ):
+		fs.EncodeBlock = ""
+		fs.EncodeExpr = runtimeName("AppendRFC3339Time") + "(b, x." + fs.GoName + "), nil"
+		// Either form decodes, so a field can move from tag 1 to tag 0
+		// without breaking stored data.
+		data := decodeCaseTemplateData{Field: fs.GoName, VarType: "time.Time", ReadFunc: runtimeName("ReadAnyTimeBytes")}
+		fs.DecodeCaseSafe = renderDecodeCase("decodeCaseBasic", data)
+		fs.DecodeCaseTrust = fs.DecodeCaseSafe
+	case tag == 1 && isTimeType(typ):
+		// tag=1 time.Time fields are written as tag 1 whatever
+		// cbor.DefaultTimeFormat is; float only changes whole seconds
+		// from an integer to a float.
+		fs.EncodeBlock = ""
+		fs.EncodeExpr = runtimeName("AppendEpochTime") + "(b, x." + fs.GoName + "), nil"
+		if fs.TimeFloat {
+			fs.EncodeExpr = runtimeName("AppendTimeFloat") + "(b, x." + fs.GoName + "), nil"
+		}
+	case (tag == 52 || tag == 54) && (ipCodecName(typ) == "IP" || ipCodecName(typ) == "Addr"):
+		// The decoders accept tagged and untagged addresses alike.
+		fs.EncodeBlock = ""
+		fs.EncodeExpr = runtimeName("Append"+ipCodecName(typ)+"Tagged") + "(b, x." + fs.GoName + "), nil"
+	case tag == 24 && isRawType(typ):
+		fs.EncodeBlock = ""
+		fs.EncodeExpr = runtimeName("AppendEmbeddedRaw") + "(b, x." + fs.GoName + "), nil"
+		// The Safe path also checks that the payload is one well-formed item.
+		data := decodeCaseTemplateData{Field: fs.GoName, ReadFunc: runtimeName("ReadEmbeddedRawBytes")}
+		fs.DecodeCaseSafe = renderDecodeCase("decodeCaseEmbedded", data)
+		data.ReadFunc = runtimeName("ReadEmbeddedRawTrustedBytes")
+		fs.DecodeCaseTrust = renderDecodeCase("decodeCaseEmbedded", data)
+	default:
+		return fmt.Errorf("%s.%s: tag=%d is not supported on %s", structName, fs.GoName, tag, types.ExprString(typ))
+	}
+	return nil
+}
+
+// durationUnits maps the values of the "unit=u" option to the
+// time.Duration constant counted in.
+var durationUnits = map[string]string{
+	"ns": "time.Nanosecond",
+	"us": "time.Microsecond",
+	"ms": "time.Millisecond",
+	"s":  "time.Second",
+	"m":  "time.Minute",
+	"h":  "time.Hour",
+}
+
+// applyUnitOption handles the "unit=u" option on a time.Duration field by
+// encoding it as an integer count of u (ns, us, ms, s, m or h) instead of
+// nanoseconds. Any other unit or field type is a generation error.
+func applyUnitOption(structName string, fs *fieldSpec, typ ast.Expr) error {
+	unit, ok := durationUnits[fs.Unit]
+	if !ok {
+		return fmt.Errorf("%s.%s: unit=%s is not one of ns, us, ms, s, m, h", structName, fs.GoName, fs.Unit)
+	}
+	if !isDurationType(typ) {
+		return fmt.Errorf("%s.%s: unit=%s requires a time.Duration field, not %s", structName, fs.GoName, fs.Unit, types.ExprString(typ))
+	}
+	fs.EncodeBlock = ""
+	fs.EncodeExpr = runtimeName("AppendDurationUnit") + "(b, x." + fs.GoName + ", " + unit + "), nil"
+	data := decodeCaseTemplateData{Field: fs.GoName, Unit: unit}
+	fs.DecodeCaseSafe = renderDecodeCase("decodeCaseDurationUnit", data)
+	fs.DecodeCaseTrust = fs.DecodeCaseSafe
+	return nil
+}
+
+// stringCodecs maps the field types the "string" option applies to onto
+// the kind of runtime codec writing them as text and the bit size that
+// codec checks.
+var stringCodecs = map[string]struct{ kind, bits string }{
+	"int": {"Int", "0"}, "int8": {"Int", "8"}, "int16": {"Int", "16"}, "int32": {"Int", "32"}, "int64": {"Int", "64"},
+	"uint": {"Uint", "0"}, "uint8": {"Uint", "8"}, "uint16": {"Uint", "16"}, "uint32": {"Uint", "32"}, "uint64": {"Uint", "64"},
+	"byte": {"Uint", "8"}, "float32": {"Float", "32"}, "float64": {"Float", "64"}, "bool": {"Bool", ""},
+}
+
+// applyStringOption makes field fs, tagged "string", encode its number
+// or bool as text in a text string and parse it back on decode.
+func applyStringOption(structName string, fs *fieldSpec, typ ast.Expr) error {
+	ident, ok := typ.(*ast.Ident)
+	var codec struct{ kind, bits string }
+	if ok {
+		codec, ok = stringCodecs[ident.Name]
+	}
+	if !ok {
+		return fmt.Errorf("%s.%s: string requires a number or bool field, not %s", structName, fs.GoName, types.ExprString(typ))
+	}
+	if fs.TagOpt != "" || fs.Unit != "" || fs.Union {
+		return fmt.Errorf("%s.%s: string cannot be combined with tag, unit or union", structName, fs.GoName)
+	}
+	ref := "x." + fs.GoName
+	data := decodeCaseTemplateData{Field: fs.GoName, ReadFunc: runtimeName("Read" + codec.kind + "StringBytes"), Bits: codec.bits, Conv: ident.Name}
+	switch codec.kind {
+	case "Int":
+		fs.EncodeExpr = runtimeName("AppendIntString") + "(b, int64(" + ref + ")), nil"
+		data.VarType = "int64"
+	case "Uint":
+		fs.EncodeExpr = runtimeName("AppendUintString") + "(b, uint64(" + ref + ")), nil"
+		data.VarType = "uint64"
+	case "Float":
+		fs.EncodeExpr = runtimeName("AppendFloatString") + "(b, float64(" + ref + "), " + codec.bits + "), nil"
+		data.VarType = "float64"
+	default:
+		fs.EncodeExpr = runtimeName("AppendBoolString") + "(b, " + ref + "), nil"
+		data.VarType = "bool"
+	}
+	fs.EncodeBlock = ""
+	fs.DecodeCaseSafe = renderDecodeCase("decodeCaseString", data)
+	fs.DecodeCaseTrust = fs.DecodeCaseSafe
+	return nil
+}
+
+// applyBoolIntOption makes bool field fs, tagged "boolasint", encode as
+// the integer 0 or 1 and decode from such an integer or from true or
+// false.
+func applyBoolIntOption(structName string, fs *fieldSpec, typ ast.Expr) error {
+	if ident, ok := typ.(*ast.Ident); !ok || ident.Name != "bool" {
+		return fmt.Errorf("%s.%s: boolasint requires a bool field, not %s", structName, fs.GoName, types.ExprString(typ))
+	}
+	if fs.AsString || fs.TagOpt != "" || fs.Union {
+		return fmt.Errorf("%s.%s: boolasint cannot be combined with string, tag or union", structName, fs.GoName)
+	}
+	ref := "x." + fs.GoName
+	fs.EncodeBlock = ""
+	fs.EncodeExpr = runtimeName("AppendBoolInt") + "(b, " + ref + "), nil"
+	fs.DecodeCaseSafe = ref + ", v, err = " + runtimeName("ReadBoolIntBytes") + "(v)\n" +
+		"if err != nil { return b, err }"
+	fs.DecodeCaseTrust = fs.DecodeCaseSafe
+	return nil
+}
+
+// applySetOption makes field fs, tagged "set", encode its
+// map[string]struct{} as an array of the keys and decode such an array
+// back into the set.
+func applySetOption(structName string, fs *fieldSpec, typ ast.Expr) error {
+	if !isStringSet(typ) {
+		return fmt.Errorf("%s.%s: set requires a map[string]struct{} field, not %s", structName, fs.GoName, types.ExprString(typ))
+	}
+	ref := "x." + fs.GoName
+	fs.EncodeBlock = ""
+	fs.EncodeExpr = runtimeName("AppendStringSet") + "(b, " + ref + "), nil"
+	read := ref + ", v, err = " + runtimeName("ReadStringSetBytes") + "(v, " + ref + ", %s)\n" +
+		"if err != nil { return b, err }"
+	fs.DecodeCaseSafe = fmt.Sprintf(read, "in")
+	fs.DecodeCaseTrust = fmt.Sprintf(read, "nil")
+	return nil
+}
+
+// pairField is the key or value field of the element of an "asmap"
+// slice.
+type pairField struct {
+	Name string
+	Type ast.Expr
+}
+
+// applyAsMapOption makes field fs, tagged "asmap", encode its slice of
+// two-field structs as a map from the first field of each element to the
+// second, through cbor.AppendPairs, and decode such a map back into the
+// slice through cbor.ReadPairsBytes. The key must be a scalar; the value
+// is encoded and decoded as a field of its type would be.
+func applyAsMapOption(structName string, fs *fieldSpec, typ ast.Expr) error {
+	if fs.TagOpt != "" || fs.Union || fs.AsString || fs.BoolAsInt || fs.Set || fs.Unit != "" {
+		return fmt.Errorf("%s.%s: asmap cannot be combined with tag, union, string, boolasint, set or unit", structName, fs.GoName)
+	}
+	elem, key, val, ok := pairFields(typ)
+	if !ok {
+		return fmt.Errorf("%s.%s: asmap requires a slice of a struct with two fields, not %s", structName, fs.GoName, types.ExprString(typ))
+	}
+	keyIdent, _ := key.Type.(*ast.Ident)
+	if keyIdent == nil || !isPairKeyType(keyIdent.Name) {
+		return fmt.Errorf("%s.%s: asmap key %s.%s must be a string, bool or number, not %s", structName, fs.GoName, elem, key.Name, types.ExprString(key.Type))
+	}
+	under := scalarName(keyIdent.Name)
+
+	// The key is written and read as its underlying scalar.
+	keyRef := "e." + key.Name
+	if under != keyIdent.Name {
+		keyRef = under + "(" + keyRef + ")"
+	}
+	keyEnc := "func(b []byte, e *" + elem + ") []byte {\nreturn " + runtimeName(scalarAppenders[under]) + "(b, " + keyRef + ")\n}"
+	keyVal := "k"
+	if under != keyIdent.Name {
+		keyVal = keyIdent.Name + "(k)"
+	}
+	keyType := under
+	if sr, ok := scalarReaders[under]; ok {
+		keyType = sr.VarType
+	}
+	keySet := "func(e *" + elem + ", k " + keyType + ") {\ne." + key.Name + " = " + keyVal + "\n}"
+
+	// The value reuses the field code paths, with x.Name standing for
+	// e.Name and, in decode cases, the returns of the closure's buf.
+	self := func(s string) string {
+		return strings.ReplaceAll(s, "x."+val.Name, "e."+val.Name)
+	}
+	valEnc := "func(b []byte, e *" + elem + ") ([]byte, error) {\n"
+	block := encodeBlockForField(structName, val.Name, "", val.Type)
+	expr := encodeExprForField(structName, val.Name, val.Type)
+	dcSafe, okSafe := decodeCaseExprSafe(structName, val.Name, val.Type)
+	dcTrust, okTrust := decodeCaseExprTrusted(structName, val.Name, val.Type)
+	fallback := renderDecodeCase("decodeCaseCodec", decodeCaseTemplateData{Field: val.Name})
+	if !okSafe {
+		dcSafe = fallback
+	}
+	if !okTrust {
+		dcTrust = fallback
+	}
+	if isNamedContainer(val.Type) && okSafe && (block != "" || expr != "") {
+		block = nilEncodeBlock(val.Name, "", block, expr)
+		null := nilDecodeCase(val.Name, val.Type)
+		dcSafe = null + "\n" + strings.TrimLeft(dcSafe, "\n")
+		dcTrust = null + "\n" + strings.TrimLeft(dcTrust, "\n")
+	}
+	if plainValueType(val.Type) {
+		untag := untagCase(val.Type)
+		dcSafe = untag + "\n" + dcSafe
+		dcTrust = untag + "\n" + dcTrust
+	}
+	switch {
+	case block != "":
+		valEnc = "func(b []byte, e *" + elem + ") (_ []byte, err error) {\n" + strings.Trim(block, "\n") + "\nreturn b, nil\n}"
+	case expr != "":
+		valEnc += "return " + expr + "\n}"
+	default:
+		valEnc += "return " + runtimeName("AppendInterface") + "(b, x." + val.Name + ")\n}"
+	}
+	valDec := func(dc string) string {
+		dc = strings.ReplaceAll(dc, "return b, ", "return buf, ")
+		return "func(buf []byte, e *" + elem + ") ([]byte, error) {\n" +
+			"v := buf\nvar err error\n" +
+			"switch {\ndefault:\n" + strings.Trim(dc, "\n") + "\n}\n" +
+			"return v, err\n}"
+	}
+
+	ref := "x." + fs.GoName
+	fs.EncodeBlock = ""
+	fs.EncodeExpr = self(runtimeName("AppendPairs") + "(b, " + ref + ", " + keyEnc + ", " + valEnc + ")")
+	fs.StreamElem = ""
+	read := func(dedupe, readKey, dc string) string {
+		return ref + ", v, err = " + runtimeName("ReadPairsBytes") + "(v, " + ref + ", " + dedupe + ", " + readKey + ", " + keySet + ", " + self(valDec(dc)) + ")\n" +
+			"if err != nil { return b, err }"
+	}
+	keyRead := runtimeName("ReadUint8Bytes")
+	if sr, ok := scalarReaders[under]; ok {
+		keyRead = runtimeName(sr.ReadFunc)
+	}
+	safeKeyRead, trustKeyRead := keyRead, keyRead
+	if under == "string" {
+		safeKeyRead, trustKeyRead = "in.ReadStringBytes", runtimeName("ReadTrustedStringBytes")
+	}
+	fs.DecodeCaseSafe = read("true", safeKeyRead, dcSafe)
+	fs.DecodeCaseTrust = read("false", trustKeyRead, dcTrust)
+	return nil
+}
+
+// pairFields reports whether typ is a slice of a struct declared in the
+// input file with exactly two encoded fields, and returns the struct's
+// name and the fields, key first.
+func pairFields(typ ast.Expr) (elem string, key, val pairField, ok bool) {
+	at, isSlice := typ.(*ast.ArrayType)
+	if !isSlice || at.Len != nil {
+		return "", key, val, false
+	}
+	ident, isIdent := at.Elt.(*ast.Ident)
+	if !isIdent || fileStructTypes[ident.Name] == nil {
+		return "", key, val, false
+	}
+	var fields []pairField
+	for _, f := range fileStructTypes[ident.Name].Fields.List {
+		if len(f.Names) == 0 {
+			return "", key, val, false
+		}
+		for _, n := range f.Names {
+			if !n.IsExported() {
+				continue
+			}
+			if spec, err := resolveFieldSpec(n.Name, f.Tag); err != nil || spec.Ignore {
+				continue
+			}
+			fields = append(fields, pairField{Name: n.Name, Type: f.Type})
+		}
+	}
+	if len(fields) != 2 {
+		return "", key, val, false
+	}
+	return ident.Name, fields[0], fields[1], true
+}
+
+// isPairKeyType reports whether a field of type name can be the key of
+// an "asmap" element: a string, bool or real number, or a named scalar
+// type of one.
+func isPairKeyType(name string) bool {
+	switch scalarName(name) {
+	case "complex64", "complex128":
+		return false
+	case "uint8", "byte":
+		return true
+	}
+	_, ok := scalarReaders[scalarName(name)]
+	return ok
+}
+
+// isStringSet reports whether typ is map[string]struct{}.
+func isStringSet(typ ast.Expr) bool {
+	m, ok := typ.(*ast.MapType)
+	if !ok {
+		return false
+	}
+	key, ok := m.Key.(*ast.Ident)
+	if !ok || key.Name != "string" {
+		return false
+	}
+	st, ok := m.Value.(*ast.StructType)
+	return ok && len(st.Fields.List) == 0
+}
+
+// isDurationType reports whether typ is time.Duration.
+func isDurationType(typ ast.Expr) bool {
+	sel, ok := typ.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && pkg.Name == "time" && sel.Sel.Name == "Duration"
+}
+
+// decodeReturnRe matches the error returns of a decode case, capturing
+// the returned error expression without the closing brace of a one-line
+// "if err != nil { return b, err }".
+var decodeReturnRe = regexp.MustCompile(`(?m)return b, (.+?)( \})?$`)
+
+// wrapDecodeErrors rewrites the error returns of the Safe decode case dc
+// to wrap the error in a cbor.DecodeError naming key and the offset of
+// the value being decoded, v, within the decoder's input b.
+func wrapDecodeErrors(dc, key string) string {
+	return decodeReturnRe.ReplaceAllStringFunc(dc, func(m string) string {
+		sub := decodeReturnRe.FindStringSubmatch(m)
+		if sub[1] == "nil" {
+			return m
+		}
+		return fmt.Sprintf("return b, %s(%s, %q, len(b)-len(v))%s", runtimeName("WrapDecodeError"), sub[1], key, sub[2])
+	})
+}
+
+// renderDecodeCase executes the named decode_case template.
+func renderDecodeCase(name string, data decodeCaseTemplateData) string {
+	var buf bytes.Buffer
+	if err := decodeCaseTemplate.ExecuteTemplate(&buf, name, data); err != nil {
+		panic(err)
+	}
+	return strings.TrimRight(buf.String(), "\n")
+}
+
+// streamElemExpr returns the expression appending element i of slice
+// field goName, for MarshalCBORStream. Byte slices, fixed arrays and
+// elements without a direct encode expression return "" and are written
+// whole.
+func streamElemExpr(structName, goName string, typ ast.Expr) string {
+	at, ok := typ.(*ast.ArrayType)
+	if !ok || at.Len != nil {
+		return ""
+	}
+	if ident, ok := at.Elt.(*ast.Ident); ok && (ident.Name == "byte" || ident.Name == "uint8") {
+		return ""
+	}
+	return encodeExprForField(structName, goName+"[i]", at.Elt)
+}
+
+func encodeExprForField(structName, goName string, typ ast.Expr) string {
 	field := "x." + goName
 	rt := runtimeName
 
+	if _, ok := interfaceVarType(typ); ok {
+		// Interface fields go through AppendInterface so registered
+		// concrete types are wrapped in their tag.
+		return ""
+	}
+
 	switch t := typ.(type) {
 	case *ast.Ident:
 		// Specialize primitive scalars to direct AppendX calls so we
@@ -1355,20 +3364,32 @@ func encodeExprForField(goName string, typ ast.Expr) string {
 			return rt("AppendFloat32") + "(b, " + field + "), nil"
 		case "float64":
 			return rt("AppendFloat64") + "(b, " + field + "), nil"
+		case "complex64":
+			return rt("AppendComplex64") + "(b, " + field + "), nil"
+		case "complex128":
+			return rt("AppendComplex128") + "(b, " + field + "), nil"
 		}
 		// For non-primitive identifiers, assume a struct type with
 		// a generated or user-defined MarshalCBOR method.
-		return field + ".MarshalCBOR(b)"
+		return field + "." + marshalCall(structName, t.Name)
 
 	case *ast.ArrayType:
 		// Slices: specialize []string; more complex shapes rely on
 		// EncodeBlock-generated loops when appropriate.
 		if t.Len != nil {
+			// [N]byte is written as a byte string; other arrays use
+			// EncodeBlock loops.
+			if ident, ok := t.Elt.(*ast.Ident); ok && (ident.Name == "byte" || ident.Name == "uint8") {
+				return rt("AppendBytes") + "(b, " + field + "[:]), nil"
+			}
 			return ""
 		}
 		if ident, ok := t.Elt.(*ast.Ident); ok && ident.Name == "string" {
 			return rt("AppendStringSlice") + "(b, " + field + "), nil"
 		}
+		if ident, ok := t.Elt.(*ast.Ident); ok && (ident.Name == "byte" || ident.Name == "uint8") {
+			return rt("AppendBytes") + "(b, " + field + "), nil"
+		}
 
 	case *ast.MapType:
 		// Map[string]string remains supported via a helper; other
@@ -1385,8 +3406,15 @@ func encodeExprForField(goName string, typ ast.Expr) string {
 		}
 
 	case *ast.StarExpr:
+		if isURLPtr(t) {
+			return rt("AppendURL") + "(b, " + field + "), nil"
+		}
 		// *T where T is exported; assume *T implements Marshaler.
 		if ident, ok := t.X.(*ast.Ident); ok && ast.IsExported(ident.Name) {
+			if call := marshalCall(structName, ident.Name); call != "MarshalCBOR(b)" {
+				// Generated encoders handle nil receivers themselves.
+				return field + "." + call
+			}
 			return rt("AppendPtrMarshaler") + "(b, " + field + ")"
 		}
 
@@ -1406,6 +3434,14 @@ func encodeExprForField(goName string, typ ast.Expr) string {
 				if t.Sel.Name == "RawMessage" {
 					return rt("AppendBytes") + "(b, []byte(" + field + ")), nil"
 				}
+			case "net", "netip":
+				if name := ipCodecName(t); name != "" {
+					return rt("Append"+name) + "(b, " + field + "), nil"
+				}
+			case runtimeAlias:
+				if _, ok := runtimeCodecTypes[t.Sel.Name]; ok {
+					return field + ".MarshalCBOR(b)"
+				}
 			}
 		}
 	}
@@ -0,0 +1,172 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// fieldTag is a parsed `cbor:"..."` struct tag.
+type fieldTag struct {
+	// Name is the key before the first comma; empty keeps the default.
+	Name      string
+	OmitEmpty bool
+	OmitZero  bool
+	KeyAsInt  bool
+	Inline    bool
+	Union     bool
+	ToArray   bool
+	Dense     bool
+	// Presence prefixes a toarray struct with a bitmap of the fields
+	// that follow ("presence").
+	Presence bool
+	// Tag is the N of "tag=N", validated by applyTagOption.
+	Tag string
+	// Float writes a tag=1 time.Time as float seconds ("float").
+	Float bool
+	// Unit is the u of "unit=u", validated by applyUnitOption.
+	Unit string
+	// AsString writes a number or bool as text ("string").
+	AsString bool
+	// BoolAsInt writes a bool as the integer 0 or 1 ("boolasint").
+	BoolAsInt bool
+	// Set writes a map[string]struct{} as an array of its keys ("set").
+	Set bool
+	// AsMap writes a slice of two-field structs as a map ("asmap").
+	AsMap bool
+	// Since is the N of "sinceversion=N": encoders given an older
+	// cbor.EncodeOptions.SchemaVersion leave the field out.
+	Since int
+	// Aliases are the names of "alias=name" options, further keys the
+	// field is decoded from; it is always encoded under Name.
+	Aliases []string
+	// Flatten merges the entries of a map[string]any field into the
+	// struct's own map ("flatten").
+	Flatten bool
+}
+
+// parseCBORTag parses the value of a cbor struct tag. Options may appear
+// in any order and empty options (",,") are ignored, but an option that
+// is unknown, repeated, missing its value or given an unexpected value is
+// an error so that typos fail generation instead of being dropped. Only
+// alias may be repeated, once per name.
+func parseCBORTag(tag string) (fieldTag, error) {
+	name, rest, _ := strings.Cut(tag, ",")
+	ft := fieldTag{Name: name}
+	seen := map[string]bool{}
+	for _, opt := range strings.Split(rest, ",") {
+		if opt == "" {
+			continue
+		}
+		key, val, hasVal := strings.Cut(opt, "=")
+		if key == "alias" {
+			if val == "" {
+				return ft, fmt.Errorf("tag option %q requires a value, as in alias=...", key)
+			}
+			if val == name || seen[opt] {
+				return ft, fmt.Errorf("duplicate alias %q", val)
+			}
+			seen[opt] = true
+			ft.Aliases = append(ft.Aliases, val)
+			continue
+		}
+		if seen[key] {
+			return ft, fmt.Errorf("duplicate tag option %q", key)
+		}
+		seen[key] = true
+		var flag *bool
+		switch key {
+		case "omitempty":
+			flag = &ft.OmitEmpty
+		case "omitzero":
+			flag = &ft.OmitZero
+		case "keyasint":
+			flag = &ft.KeyAsInt
+		case "inline":
+			flag = &ft.Inline
+		case "union":
+			flag = &ft.Union
+		case "toarray":
+			flag = &ft.ToArray
+		case "dense":
+			flag = &ft.Dense
+		case "presence":
+			flag = &ft.Presence
+		case "float":
+			flag = &ft.Float
+		case "string":
+			flag = &ft.AsString
+		case "boolasint":
+			flag = &ft.BoolAsInt
+		case "set":
+			flag = &ft.Set
+		case "asmap":
+			flag = &ft.AsMap
+		case "flatten":
+			flag = &ft.Flatten
+		case "sinceversion":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return ft, fmt.Errorf("tag option %q requires a positive version, as in sinceversion=2", key)
+			}
+			ft.Since = n
+			continue
+		case "tag", "unit":
+			if val == "" {
+				return ft, fmt.Errorf("tag option %q requires a value, as in %s=...", key, key)
+			}
+			if key == "tag" {
+				ft.Tag = val
+			} else {
+				ft.Unit = val
+			}
+			continue
+		default:
+			return ft, fmt.Errorf("unknown tag option %q", opt)
+		}
+		if hasVal {
+			return ft, fmt.Errorf("tag option %q takes no value", key)
+		}
+		*flag = true
+	}
+	return ft, nil
+}
+
+// parseJSONTag parses the value of a json struct tag, which names fields
+// that have no cbor tag. Only omitempty and omitzero carry over; other
+// options belong to encoding/json and are ignored.
+func parseJSONTag(tag string) fieldTag {
+	name, rest, _ := strings.Cut(tag, ",")
+	ft := fieldTag{Name: name}
+	for _, opt := range strings.Split(rest, ",") {
+		switch opt {
+		case "omitempty":
+			ft.OmitEmpty = true
+		case "omitzero":
+			ft.OmitZero = true
+		}
+	}
+	return ft
+}
+
+// checkFieldTag reports options that are valid on their own but not on
+// this field: struct options belong on a blank `_` field, which in turn
+// accepts nothing else.
+func checkFieldTag(goName string, ft fieldTag) error {
+	if goName == "_" {
+		if ft.Name != "" || ft.OmitEmpty || ft.OmitZero || ft.KeyAsInt || ft.Inline || ft.Union || ft.Tag != "" || ft.Float || ft.Unit != "" || len(ft.Aliases) > 0 || ft.AsString || ft.BoolAsInt || ft.Set || ft.AsMap || ft.Since > 0 || ft.Flatten {
+			return fmt.Errorf("only toarray, dense and presence are allowed on a _ field")
+		}
+		return nil
+	}
+	if ft.ToArray || ft.Dense || ft.Presence {
+		return fmt.Errorf("toarray, dense and presence belong on a _ field")
+	}
+	if len(ft.Aliases) > 0 && (ft.Inline || ft.KeyAsInt) {
+		return fmt.Errorf("alias applies to text keys and cannot be combined with inline or keyasint")
+	}
+	if ft.Flatten && (ft.Name != "" || ft.OmitEmpty || ft.OmitZero || ft.KeyAsInt || ft.Inline || ft.Union || ft.Tag != "" || ft.Float || ft.Unit != "" || len(ft.Aliases) > 0 || ft.AsString || ft.BoolAsInt || ft.Set || ft.AsMap || ft.Since > 0) {
+		return fmt.Errorf("flatten has no key of its own and takes no other options")
+	}
+	return nil
+}
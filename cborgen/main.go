@@ -17,14 +17,38 @@ import (
 //   - input: Go file or directory
 //   - output: override for the generated file (file mode only)
 //   - verbose: turn on diagnostic logging
+//   - compat: also emit fxamacker/cbor-compatible adapter types
+//   - stream: also emit MarshalCBORStream methods for an Encoder
+//   - namecase: derive untagged keys from Go names (snake, camel, ...)
+//   - clone: also emit deep-copy Clone methods
+//   - bench: also emit per-type encode/decode benchmarks
+//   - diag: also emit DiagString methods for logging
+//   - constructors: also emit NewTFromCBOR decode functions
+//   - export-keys: also emit tables of the map keys of each struct
+//   - io-adapters: also emit io.WriterTo and io.ReaderFrom methods
+//   - allow-json-fallback: encode fields of unknown types via their JSON methods
+//   - tags: build tags, as for go build, deciding which files are in the package
 //
 // In directory mode, each source file gets its own
 // "*_cbor.go" companion file and the --output flag is rejected.
 type CLI struct {
-	Input   string   `short:"i" help:"Input Go file or directory" default:"${env:GOFILE}"`
-	Output  string   `short:"o" help:"Output file (file input only; defaults to {input}_cbor.go)"`
-	Structs []string `short:"s" help:"Only generate for these struct types (may be repeated)"`
-	Verbose bool     `short:"v" help:"Enable verbose diagnostics"`
+	Input        string   `short:"i" help:"Input Go file or directory" default:"${env:GOFILE}"`
+	Output       string   `short:"o" help:"Output file (file input only; defaults to {input}_cbor.go)"`
+	Structs      []string `short:"s" help:"Only generate for these struct types (may be repeated)"`
+	Verbose      bool     `short:"v" help:"Enable verbose diagnostics"`
+	Compat       bool     `help:"Also emit fxamacker/cbor-compatible MarshalCBOR()/UnmarshalCBOR([]byte) error adapters"`
+	Stream       bool     `help:"Also emit MarshalCBORStream(*cbor.Encoder) methods that write slices as indefinite-length arrays"`
+	NameCase     string   `name:"namecase" help:"Derive keys of untagged fields from Go names: snake, camel, kebab or lower"`
+	Clone        bool     `help:"Also emit Clone() *T deep-copy methods"`
+	Bench        bool     `help:"Also emit {output}_bench_test.go with encode/decode benchmarks per type"`
+	Diag         bool     `help:"Also emit DiagString() string methods rendering values in CBOR diagnostic notation"`
+	Constructors bool     `help:"Also emit NewTFromCBOR(b) (*T, []byte, error) functions decoding into a new T with the Safe path"`
+	ExportKeys   bool     `name:"export-keys" help:"Also emit TFieldKeys/TOmitEmptyKeys (and TFieldIntKeys/TOmitEmptyIntKeys for keyasint) tables of each struct's map keys"`
+	IOAdapters   bool     `name:"io-adapters" help:"Also emit WriteTo(io.Writer) and ReadFrom(io.Reader) methods implementing io.WriterTo and io.ReaderFrom"`
+
+	AllowJSONFallback bool `name:"allow-json-fallback" help:"Encode fields of types with no known CBOR codec via their JSON methods (lossy and slow; migration aid)"`
+
+	Tags string `help:"Comma-separated build tags, as for go build -tags; files their build constraints exclude are skipped"`
 }
 
 func main() {
@@ -54,7 +78,7 @@ func run(cli *CLI) error {
 		if cli.Output != "" {
 			return errors.New("--output is not allowed when input is a directory")
 		}
-		return runForDir(input, cli.Verbose, cli.Structs)
+		return runForDir(input, cli.options())
 	}
 
 	// Single-file mode.
@@ -62,12 +86,17 @@ func run(cli *CLI) error {
 	if strings.TrimSpace(out) == "" {
 		out = defaultOutputPath(input)
 	}
-	return generateForFile(input, out, cli.Verbose, cli.Structs)
+	return generateForFile(input, out, cli.options())
+}
+
+// options converts the parsed flags into generator options.
+func (cli *CLI) options() core.Options {
+	return core.Options{Verbose: cli.Verbose, Structs: cli.Structs, Compat: cli.Compat, Stream: cli.Stream, NameCase: cli.NameCase, Clone: cli.Clone, Bench: cli.Bench, Diag: cli.Diag, Constructors: cli.Constructors, ExportKeys: cli.ExportKeys, IOAdapters: cli.IOAdapters, AllowJSONFallback: cli.AllowJSONFallback, Tags: core.ParseTags(cli.Tags)}
 }
 
 // runForDir walks a directory and generates a companion
 // "*_cbor.go" file for each eligible Go source file.
-func runForDir(dir string, verbose bool, structs []string) error {
+func runForDir(dir string, opts core.Options) error {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
 		return fmt.Errorf("read dir %q: %w", dir, err)
@@ -96,9 +125,14 @@ func runForDir(dir string, verbose bool, structs []string) error {
 		if !info.Mode().IsRegular() {
 			continue
 		}
+		if ok, err := core.MatchFile(inPath, opts.Tags); err != nil {
+			return fmt.Errorf("read build constraints of %q: %w", inPath, err)
+		} else if !ok {
+			continue
+		}
 
 		outPath := defaultOutputPath(inPath)
-		if err := generateForFile(inPath, outPath, verbose, structs); err != nil {
+		if err := generateForFile(inPath, outPath, opts); err != nil {
 			return err
 		}
 	}
@@ -119,6 +153,6 @@ func defaultOutputPath(inputPath string) string {
 	return filepath.Join(dir, name)
 }
 
-func generateForFile(inputPath, outputPath string, verbose bool, structs []string) error {
-	return core.Run(inputPath, outputPath, core.Options{Verbose: verbose, Structs: structs})
+func generateForFile(inputPath, outputPath string, opts core.Options) error {
+	return core.Run(inputPath, outputPath, opts)
 }
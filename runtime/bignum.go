@@ -0,0 +1,89 @@
+package cbor
+
+import bigmath "math/big"
+
+// BignumOverflow is returned when a bignum (tag 2 or 3) is read into an
+// int64 or uint64 that cannot hold its value.
+type BignumOverflow struct {
+	Value *bigmath.Int // the value of the bignum
+	Type  string       // the integer type it was read as, "int64" or "uint64"
+	ctx   string
+}
+
+// Error implements the error interface
+func (e BignumOverflow) Error() string {
+	str := "cbor: bignum " + e.Value.String() + " overflows " + e.Type
+	if e.ctx != "" {
+		str += " at " + e.ctx
+	}
+	return str
+}
+
+// Resumable is always 'true' for overflows
+func (e BignumOverflow) Resumable() bool { return true }
+
+func (e BignumOverflow) withContext(ctx string) error { e.ctx = addCtx(e.ctx, ctx); return e }
+
+// readBignumMagnitude reads a bignum and returns its magnitude when it
+// fits 64 bits. Leading zero bytes are allowed, as RFC 8949 permits them.
+func readBignumMagnitude(b []byte) (mag uint64, neg, fits bool, o []byte, err error) {
+	tag, o, err := ReadTagBytes(b)
+	if err != nil {
+		return 0, false, false, b, err
+	}
+	if tag != tagPosBignum && tag != tagNegBignum {
+		return 0, false, false, b, UnexpectedTagError{Tag: tag}
+	}
+	bs, o, err := ReadBytesBytes(o, nil)
+	if err != nil {
+		return 0, false, false, b, err
+	}
+	for len(bs) > 0 && bs[0] == 0 {
+		bs = bs[1:]
+	}
+	if len(bs) > 8 {
+		return 0, tag == tagNegBignum, false, o, nil
+	}
+	for _, c := range bs {
+		mag = mag<<8 | uint64(c)
+	}
+	return mag, tag == tagNegBignum, true, o, nil
+}
+
+// readBignumAsInt64 reads a bignum for ReadInt64Bytes.
+func readBignumAsInt64(b []byte) (int64, []byte, error) {
+	mag, neg, fits, o, err := readBignumMagnitude(b)
+	if err != nil {
+		return 0, b, err
+	}
+	if !fits || mag > 1<<63-1 {
+		return 0, b, bignumOverflow(b, "int64")
+	}
+	if neg {
+		return -1 - int64(mag), o, nil
+	}
+	return int64(mag), o, nil
+}
+
+// readBignumAsUint64 reads a bignum for ReadUint64Bytes. A negative
+// bignum never fits.
+func readBignumAsUint64(b []byte) (uint64, []byte, error) {
+	mag, neg, fits, o, err := readBignumMagnitude(b)
+	if err != nil {
+		return 0, b, err
+	}
+	if !fits || neg {
+		return 0, b, bignumOverflow(b, "uint64")
+	}
+	return mag, o, nil
+}
+
+// bignumOverflow builds the error for a bignum that does not fit typ.
+// The big.Int is only allocated on this path.
+func bignumOverflow(b []byte, typ string) error {
+	z, _, err := ReadBigIntBytes(b)
+	if err != nil {
+		return err
+	}
+	return BignumOverflow{Value: z, Type: typ}
+}
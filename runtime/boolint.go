@@ -0,0 +1,65 @@
+package cbor
+
+import "strconv"
+
+// Fields tagged ",boolasint" hold a bool as the integer 0 or 1, for peers
+// that cannot handle the CBOR simple values true and false. The reader
+// accepts those simple values too, so such a field also decodes data
+// written without the option.
+
+// BoolIntError is returned when a ",boolasint" field holds an integer
+// other than 0 or 1.
+type BoolIntError struct {
+	Value int64 // the integer that was read
+	ctx   string
+}
+
+// Error implements the error interface
+func (e BoolIntError) Error() string {
+	str := "cbor: integer " + strconv.FormatInt(e.Value, 10) + " is not a bool (0 or 1)"
+	if e.ctx != "" {
+		str += " at " + e.ctx
+	}
+	return str
+}
+
+// Resumable returns 'true' for BoolIntError
+func (e BoolIntError) Resumable() bool { return true }
+
+func (e BoolIntError) withContext(ctx string) error { e.ctx = addCtx(e.ctx, ctx); return e }
+
+// AppendBoolInt appends v as the integer 1 or 0.
+func AppendBoolInt(b []byte, v bool) []byte {
+	if v {
+		return append(b, 0x01)
+	}
+	return append(b, 0x00)
+}
+
+// ReadBoolIntBytes reads a bool written by AppendBoolInt, or as true or
+// false. Any other integer fails with BoolIntError.
+func ReadBoolIntBytes(b []byte) (v bool, o []byte, err error) {
+	if len(b) < 1 {
+		return false, b, ErrShortBytes
+	}
+	switch b[0] {
+	case 0x00, 0xf4:
+		return false, b[1:], nil
+	case 0x01, 0xf5:
+		return true, b[1:], nil
+	}
+	if m := getMajorType(b[0]); m != majorTypeUint && m != majorTypeNegInt {
+		return false, b, TypeError{Method: BoolType, Encoded: getType(b[0])}
+	}
+	i, o, err := ReadInt64Bytes(b)
+	if err != nil {
+		return false, b, err
+	}
+	switch i {
+	case 0:
+		return false, o, nil
+	case 1:
+		return true, o, nil
+	}
+	return false, b, BoolIntError{Value: i}
+}
@@ -0,0 +1,137 @@
+package cbor
+
+import (
+	"bytes"
+	"sort"
+)
+
+// CanonicalOrder selects how the keys of a deterministically encoded map
+// are ordered. Keys are always compared by their encoded CBOR bytes.
+type CanonicalOrder uint8
+
+const (
+	// RFC8949 sorts keys bytewise lexicographically (RFC 8949 §4.2.1,
+	// "core deterministic encoding").
+	RFC8949 CanonicalOrder = iota
+	// RFC7049 sorts shorter keys first and breaks ties bytewise
+	// (RFC 7049 §3.9, "canonical CBOR"), as some older peers expect.
+	RFC7049
+)
+
+// MapKeyOrder is the key order used by SortMapKeys, the Append*Deterministic
+// helpers, and generated code when CanonicalMapEncode is set.
+// The two orders only differ for maps that mix key types or lengths
+// across major types, e.g. {"a": .., 1000: ..}.
+var MapKeyOrder = RFC8949
+
+// CanonicalMapEncode makes generated MarshalCBOR methods emit map-typed
+// fields with their keys sorted per MapKeyOrder. When false (the default)
+// entries are written in Go map iteration order, which is faster but not
+// reproducible.
+var CanonicalMapEncode = false
+
+// String returns the RFC the order is defined by.
+func (o CanonicalOrder) String() string {
+	switch o {
+	case RFC8949:
+		return "RFC8949"
+	case RFC7049:
+		return "RFC7049"
+	}
+	return "CanonicalOrder(invalid)"
+}
+
+// Compare returns -1, 0 or +1 depending on whether the encoded key a
+// sorts before, equal to, or after the encoded key b under o.
+func (o CanonicalOrder) Compare(a, b []byte) int {
+	if o == RFC7049 && len(a) != len(b) {
+		if len(a) < len(b) {
+			return -1
+		}
+		return 1
+	}
+	return bytes.Compare(a, b)
+}
+
+// SortMapKeys sorts pairs in place by their encoded keys per MapKeyOrder.
+func SortMapKeys(pairs []RawPair) {
+	order := MapKeyOrder
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return order.Compare(pairs[i].Key, pairs[j].Key) < 0
+	})
+}
+
+// sortedKeyIndexes returns the indexes 0..n-1 ordered by the encoded key
+// returned by key(i), per MapKeyOrder.
+func sortedKeyIndexes(n int, key func(i int) []byte) []int {
+	if MapKeyOrder == RFC7049 {
+		return lengthFirstIndexes(n, key)
+	}
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool { return bytes.Compare(key(order[i]), key(order[j])) < 0 })
+	return order
+}
+
+// lengthFirstIndexes buckets keys by encoded length and sorts each bucket
+// bytewise, falling back to an LSD radix sort for large buckets of long keys.
+func lengthFirstIndexes(n int, key func(i int) []byte) []int {
+	byLen := make(map[int][]int)
+	for i := 0; i < n; i++ {
+		l := len(key(i))
+		byLen[l] = append(byLen[l], i)
+	}
+	lens := make([]int, 0, len(byLen))
+	for l := range byLen {
+		lens = append(lens, l)
+	}
+	sort.Ints(lens)
+	order := make([]int, 0, n)
+	counts := make([]int, 256)
+	var tmp []int
+	for _, l := range lens {
+		grp := byLen[l]
+		if len(grp) <= 1 {
+			order = append(order, grp...)
+			continue
+		}
+		// Adaptive: comparator is faster for smaller groups/short keys.
+		if l < 64 && len(grp) < 1024 {
+			sort.Slice(grp, func(i, j int) bool { return bytes.Compare(key(grp[i]), key(grp[j])) < 0 })
+			order = append(order, grp...)
+			continue
+		}
+		if cap(tmp) < len(grp) {
+			tmp = make([]int, len(grp))
+		} else {
+			tmp = tmp[:len(grp)]
+		}
+		cur := grp
+		aux := tmp
+		for pos := l - 1; pos >= 0; pos-- {
+			for i := range counts {
+				counts[i] = 0
+			}
+			for _, idx := range cur {
+				counts[int(key(idx)[pos])]++
+			}
+			sum := 0
+			for i := 0; i < 256; i++ {
+				c := counts[i]
+				counts[i] = sum
+				sum += c
+			}
+			for _, idx := range cur {
+				bv := key(idx)[pos]
+				p := counts[int(bv)]
+				aux[p] = idx
+				counts[int(bv)] = p + 1
+			}
+			cur, aux = aux, cur
+		}
+		order = append(order, cur...)
+	}
+	return order
+}
@@ -0,0 +1,28 @@
+// Package cbortest provides test helpers for code that produces CBOR.
+package cbortest
+
+import (
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+// AssertCanonical fails t unless buf holds exactly one CBOR item in
+// deterministic encoding, as checked by cbor.ValidateCanonical. The
+// failure names the first violation and its offset, followed by the item
+// in diagnostic notation when it is well-formed:
+//
+//	b, _ := msg.MarshalCBOR(nil)
+//	cbortest.AssertCanonical(t, b)
+func AssertCanonical(t testing.TB, buf []byte) {
+	t.Helper()
+	err := cbor.ValidateCanonical(buf)
+	if err == nil {
+		return
+	}
+	if d, _, derr := cbor.DiagBytes(buf); derr == nil {
+		t.Errorf("%v\n\titem: %s", err, d)
+		return
+	}
+	t.Errorf("%v", err)
+}
@@ -0,0 +1,149 @@
+package cbor
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrDuplicateCodec is returned by RegisterCodec when the type already has
+// a codec.
+var ErrDuplicateCodec = errors.New("cbor: codec already registered")
+
+// CodecEncodeFunc appends the encoding of v, a value of the type it was
+// registered for, to b.
+type CodecEncodeFunc func(b []byte, v any) ([]byte, error)
+
+// CodecDecodeFunc decodes the item at the start of b into v, a non-nil
+// pointer to the type it was registered for, and returns the bytes after
+// it.
+type CodecDecodeFunc func(b []byte, v any) ([]byte, error)
+
+type codec struct {
+	encode CodecEncodeFunc
+	decode CodecDecodeFunc
+}
+
+var codecRegistry struct {
+	mu     sync.RWMutex
+	byType map[reflect.Type]codec
+}
+
+// codecsInUse lets the encoders skip the codec lookup entirely until the
+// first RegisterCodec call.
+var codecsInUse atomic.Bool
+
+// RegisterCodec makes enc and dec the encoding of values of type t, for
+// types such as those of other packages that cannot have CBOR methods
+// generated. Marshal and Unmarshal use the codec wherever a t appears;
+// generated code uses it for fields whose type it has no encoding for,
+// through AppendInterface and ReadWithCodec. A field of type *t is
+// covered too, with nil written as null.
+//
+// RegisterCodec is safe for concurrent use. Each type takes one codec: a
+// second registration returns ErrDuplicateCodec and leaves the first in
+// place, so the codec in use never depends on registration order.
+func RegisterCodec(t reflect.Type, enc CodecEncodeFunc, dec CodecDecodeFunc) error {
+	if t == nil || t.Kind() == reflect.Interface {
+		return &ErrUnsupportedType{T: t}
+	}
+	if enc == nil || dec == nil {
+		return fmt.Errorf("cbor: RegisterCodec(%s) needs both an encode and a decode function", t)
+	}
+	codecRegistry.mu.Lock()
+	defer codecRegistry.mu.Unlock()
+	if _, ok := codecRegistry.byType[t]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicateCodec, t)
+	}
+	if codecRegistry.byType == nil {
+		codecRegistry.byType = make(map[reflect.Type]codec)
+	}
+	codecRegistry.byType[t] = codec{encode: enc, decode: dec}
+	codecsInUse.Store(true)
+	return nil
+}
+
+// lookupCodec returns the codec registered for t.
+func lookupCodec(t reflect.Type) (codec, bool) {
+	if !codecsInUse.Load() {
+		return codec{}, false
+	}
+	codecRegistry.mu.RLock()
+	c, ok := codecRegistry.byType[t]
+	codecRegistry.mu.RUnlock()
+	return c, ok
+}
+
+// appendCodec appends v with the codec of its type, or of the type it
+// points to, and reports whether there was one.
+func appendCodec(b []byte, v reflect.Value) ([]byte, bool, error) {
+	t := v.Type()
+	if c, ok := lookupCodec(t); ok {
+		b, err := c.encode(b, v.Interface())
+		return b, true, err
+	}
+	if t.Kind() != reflect.Pointer {
+		return b, false, nil
+	}
+	c, ok := lookupCodec(t.Elem())
+	if !ok {
+		return b, false, nil
+	}
+	if v.IsNil() {
+		return AppendNil(b), true, nil
+	}
+	b, err := c.encode(b, v.Elem().Interface())
+	return b, true, err
+}
+
+// decodeCodec decodes b into v, which must be settable, with the codec of
+// its type, or of the type it points to, and reports whether there was
+// one.
+func decodeCodec(b []byte, v reflect.Value) ([]byte, bool, error) {
+	t := v.Type()
+	if c, ok := lookupCodec(t); ok {
+		if !v.CanAddr() {
+			p := reflect.New(t)
+			o, err := c.decode(b, p.Interface())
+			v.Set(p.Elem())
+			return o, true, err
+		}
+		o, err := c.decode(b, v.Addr().Interface())
+		return o, true, err
+	}
+	if t.Kind() != reflect.Pointer {
+		return b, false, nil
+	}
+	c, ok := lookupCodec(t.Elem())
+	if !ok {
+		return b, false, nil
+	}
+	if IsNilOrUndefined(b) {
+		v.SetZero()
+		return b[1:], true, nil
+	}
+	if v.IsNil() {
+		v.Set(reflect.New(t.Elem()))
+	}
+	o, err := c.decode(b, v.Interface())
+	return o, true, err
+}
+
+// ReadWithCodec decodes the next item of b into the value v points to and
+// returns the remaining bytes. Generated decoders call it for fields whose
+// type they have no decoding for, such as types of other packages: a
+// codec registered with RegisterCodec decodes the item, then an
+// UnmarshalCBOR method; with neither, it is decoded as Unmarshal would.
+func ReadWithCodec(b []byte, v any) ([]byte, error) {
+	if codecsInUse.Load() {
+		if o, ok, err := decodeCodec(b, reflect.ValueOf(v).Elem()); ok {
+			return o, err
+		}
+	}
+	if u, ok := v.(Unmarshaler); ok {
+		return u.UnmarshalCBOR(b)
+	}
+	return decodeReflect(b, reflect.ValueOf(v).Elem(), 0)
+}
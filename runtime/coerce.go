@@ -0,0 +1,53 @@
+package cbor
+
+import "math"
+
+// readFloatAsInt64 reads a float for an integer reader under
+// CoerceNumbers.
+func readFloatAsInt64(b []byte) (int64, []byte, error) {
+	f, o, err := readPlainFloat64(b)
+	if err != nil {
+		return 0, b, err
+	}
+	// -2^63 is exact in float64; 2^63 is the first value past MaxInt64.
+	// NaN fails the Trunc comparison.
+	if f != math.Trunc(f) || f < -(1<<63) || f >= 1<<63 {
+		return 0, b, FloatConversionError{Value: f, Type: "int64"}
+	}
+	return int64(f), o, nil
+}
+
+// readFloatAsUint64 is readFloatAsInt64 for the unsigned readers.
+func readFloatAsUint64(b []byte) (uint64, []byte, error) {
+	f, o, err := readPlainFloat64(b)
+	if err != nil {
+		return 0, b, err
+	}
+	if f != math.Trunc(f) || f < 0 || f >= 1<<64 {
+		return 0, b, FloatConversionError{Value: f, Type: "uint64"}
+	}
+	return uint64(f), o, nil
+}
+
+// readPlainFloat64 reads a float without coercion, so a simple value
+// that is not a float keeps its usual type error.
+func readPlainFloat64(b []byte) (float64, []byte, error) {
+	switch b[0] {
+	case 0xf9, 0xfa, 0xfb:
+		return ReadFloat64Bytes(b)
+	}
+	return 0, b, badPrefix(majorTypeUint, majorTypeSimple)
+}
+
+// readIntAsFloat64 reads an integer of the given major type for a float
+// reader under CoerceNumbers.
+func readIntAsFloat64(b []byte, major uint8) (float64, []byte, error) {
+	u, o, err := readUintCore(b, major)
+	if err != nil {
+		return 0, b, err
+	}
+	if major == majorTypeNegInt {
+		return -1 - float64(u), o, nil
+	}
+	return float64(u), o, nil
+}
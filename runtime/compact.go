@@ -0,0 +1,143 @@
+package cbor
+
+import (
+	"bytes"
+	"math"
+)
+
+// Compact rewrites buf, which must hold exactly one well-formed item, into
+// the deterministic encoding ValidateCanonical accepts: integers, lengths,
+// tag numbers and simple values get their shortest head, indefinite-length
+// strings, arrays and maps become definite, map keys are sorted per
+// MapKeyOrder and floats take the shortest width that preserves their
+// value. Tags are kept and their content compacted. Two keys of one map
+// that compact to the same bytes yield ErrDuplicateMapKey.
+func Compact(buf []byte) ([]byte, error) {
+	rest, err := ValidateWellFormedBytes(buf)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, ErrTrailingBytes
+	}
+	out, _, err := compactItem(make([]byte, 0, len(buf)), buf)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// compactItem appends the compacted form of the well-formed item at the
+// start of b to dst and returns the bytes after the item.
+func compactItem(dst, b []byte) ([]byte, []byte, error) {
+	major, info, arg, rest, err := ReadHead(b)
+	if err != nil {
+		return dst, b, err
+	}
+	indef := info == addInfoIndefinite
+	switch major {
+	case majorTypeUint, majorTypeNegInt:
+		return appendUintCore(dst, major, arg), rest, nil
+	case majorTypeBytes, majorTypeText:
+		if !indef {
+			dst = appendUintCore(dst, major, arg)
+			return append(dst, rest[:arg]...), rest[arg:], nil
+		}
+		// Size the joined string first so its head can precede the chunks.
+		var n uint64
+		for p := rest; !isBreak(p); {
+			_, _, l, q, _ := ReadHead(p)
+			n += l
+			p = q[l:]
+		}
+		dst = appendUintCore(dst, major, n)
+		for !isBreak(rest) {
+			_, _, l, q, _ := ReadHead(rest)
+			dst = append(dst, q[:l]...)
+			rest = q[l:]
+		}
+		return dst, rest[1:], nil
+	case majorTypeArray:
+		if !indef {
+			dst = appendUintCore(dst, majorTypeArray, arg)
+			for i := uint64(0); i < arg; i++ {
+				if dst, rest, err = compactItem(dst, rest); err != nil {
+					return dst, rest, err
+				}
+			}
+			return dst, rest, nil
+		}
+		var items []byte
+		var n uint64
+		for ; !isBreak(rest); n++ {
+			if items, rest, err = compactItem(items, rest); err != nil {
+				return dst, rest, err
+			}
+		}
+		dst = appendUintCore(dst, majorTypeArray, n)
+		return append(dst, items...), rest[1:], nil
+	case majorTypeMap:
+		return compactMap(dst, rest, arg, indef)
+	case majorTypeTag:
+		return compactItem(appendUintCore(dst, majorTypeTag, arg), rest)
+	}
+	switch info {
+	case simpleFloat16, simpleFloat32, simpleFloat64:
+		var f float64
+		switch info {
+		case simpleFloat16:
+			f = float64(float16BitsToFloat32(uint16(arg)))
+		case simpleFloat32:
+			f = float64(math.Float32frombits(uint32(arg)))
+		default:
+			f = math.Float64frombits(arg)
+		}
+		if f == 0 && math.Signbit(f) {
+			// AppendFloatCanonical folds -0 into 0, which changes the value.
+			return append(dst, makeByte(majorTypeSimple, simpleFloat16), 0x80, 0x00), rest, nil
+		}
+		return AppendFloatCanonical(dst, f), rest, nil
+	}
+	return appendUintCore(dst, majorTypeSimple, arg), rest, nil
+}
+
+// compactMap compacts the pairs of a map whose head has been read into a
+// scratch buffer, then appends them to dst in key order.
+func compactMap(dst, b []byte, n uint64, indef bool) ([]byte, []byte, error) {
+	var pairs []byte
+	var ends [][2]int // end of each key and of its value within pairs
+	var err error
+	for i := uint64(0); (indef && !isBreak(b)) || (!indef && i < n); i++ {
+		if pairs, b, err = compactItem(pairs, b); err != nil {
+			return dst, b, err
+		}
+		k := len(pairs)
+		if pairs, b, err = compactItem(pairs, b); err != nil {
+			return dst, b, err
+		}
+		ends = append(ends, [2]int{k, len(pairs)})
+	}
+	if indef {
+		b = b[1:] // break
+	}
+	start := func(i int) int {
+		if i == 0 {
+			return 0
+		}
+		return ends[i-1][1]
+	}
+	key := func(i int) []byte { return pairs[start(i):ends[i][0]] }
+	dst = appendUintCore(dst, majorTypeMap, uint64(len(ends)))
+	order := sortedKeyIndexes(len(ends), key)
+	for j, i := range order {
+		if j > 0 && bytes.Equal(key(order[j-1]), key(i)) {
+			return dst, b, ErrDuplicateMapKey
+		}
+		dst = append(dst, pairs[start(i):ends[i][1]]...)
+	}
+	return dst, b, nil
+}
+
+func isBreak(b []byte) bool {
+	return len(b) > 0 && b[0] == makeByte(majorTypeSimple, simpleBreak)
+}
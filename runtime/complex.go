@@ -0,0 +1,81 @@
+package cbor
+
+// ComplexTag, when non-zero, makes AppendComplex64 and AppendComplex128
+// wrap the [real, imag] array in this CBOR tag. No tag number is
+// registered for complex numbers, so the default is an untagged array.
+// The readers accept the untagged form and, when ComplexTag is set,
+// the tagged form; any other tag is rejected.
+var ComplexTag uint64 = 0
+
+// AppendComplex64 appends c as a two-element array [real, imag] of
+// float32 values.
+func AppendComplex64(b []byte, c complex64) []byte {
+	if ComplexTag != 0 {
+		b = AppendTag(b, ComplexTag)
+	}
+	b = AppendArrayHeader(b, 2)
+	b = AppendFloat32(b, real(c))
+	return AppendFloat32(b, imag(c))
+}
+
+// AppendComplex128 appends c as a two-element array [real, imag] of
+// float64 values.
+func AppendComplex128(b []byte, c complex128) []byte {
+	if ComplexTag != 0 {
+		b = AppendTag(b, ComplexTag)
+	}
+	b = AppendArrayHeader(b, 2)
+	b = AppendFloat64(b, real(c))
+	return AppendFloat64(b, imag(c))
+}
+
+// ReadComplex64Bytes reads a complex number written by AppendComplex64
+// or AppendComplex128. Components may use any float width and are
+// converted to float32.
+func ReadComplex64Bytes(b []byte) (c complex64, o []byte, err error) {
+	re, im, o, err := readComplexBytes(b)
+	if err != nil {
+		return 0, b, err
+	}
+	return complex(float32(re), float32(im)), o, nil
+}
+
+// ReadComplex128Bytes reads a complex number written by AppendComplex64
+// or AppendComplex128. Components may use any float width.
+func ReadComplex128Bytes(b []byte) (c complex128, o []byte, err error) {
+	re, im, o, err := readComplexBytes(b)
+	if err != nil {
+		return 0, b, err
+	}
+	return complex(re, im), o, nil
+}
+
+func readComplexBytes(b []byte) (re, im float64, o []byte, err error) {
+	o = b
+	if len(o) > 0 && getMajorType(o[0]) == majorTypeTag {
+		var tag uint64
+		tag, o, err = ReadTagBytes(o)
+		if err != nil {
+			return 0, 0, b, err
+		}
+		if ComplexTag == 0 || tag != ComplexTag {
+			return 0, 0, b, TypeError{Method: ArrayType, Encoded: ExtensionType}
+		}
+	}
+	sz, o, err := ReadArrayHeaderBytes(o)
+	if err != nil {
+		return 0, 0, b, err
+	}
+	if sz != 2 {
+		return 0, 0, b, ArrayError{Wanted: 2, Got: sz}
+	}
+	re, o, err = ReadFloat64Bytes(o)
+	if err != nil {
+		return 0, 0, b, err
+	}
+	im, o, err = ReadFloat64Bytes(o)
+	if err != nil {
+		return 0, 0, b, err
+	}
+	return re, im, o, nil
+}
@@ -0,0 +1,108 @@
+package cbor
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Decimal is an exact decimal number, Value × 10^-Scale, for amounts
+// such as money that float rounding would spoil: Decimal{Scale: 2,
+// Value: 1999} is 19.99. A negative Scale stands for trailing zeros. It
+// is encoded as a tag 4 decimal fraction [exponent, mantissa] (RFC 8949
+// section 3.4.4) with exponent -Scale, so equal amounts at different
+// scales, 1.5 and 1.50, stay distinct on the wire. Use
+// AppendDecimalFraction and ReadDecimalFractionBytes for mantissas
+// beyond int64.
+type Decimal struct {
+	Scale int
+	Value int64
+}
+
+// AppendDecimal appends d as a tag 4 decimal fraction.
+func AppendDecimal(b []byte, d Decimal) []byte {
+	b = AppendTag(b, tagDecimalFrac)
+	b = AppendArrayHeader(b, 2)
+	b = AppendInt64(b, -int64(d.Scale))
+	return AppendInt64(b, d.Value)
+}
+
+// ReadDecimalBytes reads a tag 4 decimal fraction, whose array may be of
+// definite or indefinite length. Any other tag fails with
+// UnexpectedTagError, an array of other than two items with ArrayError,
+// a bignum mantissa beyond int64 with BignumOverflow, and any other
+// mantissa beyond int64, or an exponent whose negation does not fit an
+// int, with IntOverflow.
+func ReadDecimalBytes(b []byte) (d Decimal, o []byte, err error) {
+	tag, o, err := ReadTagBytes(b)
+	if err != nil {
+		return d, b, err
+	}
+	if tag != tagDecimalFrac {
+		return d, b, UnexpectedTagError{Tag: tag}
+	}
+	sz, indef, o, err := ReadArraySizeBytes(o)
+	if err != nil {
+		return d, b, err
+	}
+	if sz != 2 {
+		return d, b, ArrayError{Wanted: 2, Got: sz}
+	}
+	exp, o, err := ReadInt64Bytes(o)
+	if err != nil {
+		return d, b, err
+	}
+	if exp < -math.MaxInt || exp > math.MaxInt {
+		return d, b, IntOverflow{Value: exp, FailedBitsize: strconv.IntSize}
+	}
+	if d.Value, o, err = ReadInt64Bytes(o); err != nil {
+		return Decimal{}, b, err
+	}
+	d.Scale = -int(exp)
+	if indef {
+		o = o[1:] // break
+	}
+	return d, o, nil
+}
+
+// MarshalCBOR appends d as a tag 4 decimal fraction.
+func (d *Decimal) MarshalCBOR(b []byte) ([]byte, error) {
+	return AppendDecimal(b, *d), nil
+}
+
+// UnmarshalCBOR decodes a tag 4 decimal fraction into d.
+func (d *Decimal) UnmarshalCBOR(b []byte) ([]byte, error) {
+	v, o, err := ReadDecimalBytes(b)
+	if err != nil {
+		return b, err
+	}
+	*d = v
+	return o, nil
+}
+
+// Msgsize returns the worst-case encoded size.
+func (d *Decimal) Msgsize() int { return DecimalSize }
+
+// String returns d in plain decimal notation with Scale digits after the
+// point, such as "-0.05" for Decimal{Scale: 2, Value: -5}.
+func (d Decimal) String() string {
+	neg := d.Value < 0
+	mag := uint64(d.Value)
+	if neg {
+		mag = -mag
+	}
+	digits := strconv.FormatUint(mag, 10)
+	switch {
+	case d.Scale < 0 && mag != 0:
+		digits += strings.Repeat("0", -d.Scale)
+	case d.Scale > 0:
+		if len(digits) <= d.Scale {
+			digits = strings.Repeat("0", d.Scale-len(digits)+1) + digits
+		}
+		digits = digits[:len(digits)-d.Scale] + "." + digits[len(digits)-d.Scale:]
+	}
+	if neg {
+		return "-" + digits
+	}
+	return digits
+}
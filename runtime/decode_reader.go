@@ -0,0 +1,172 @@
+package cbor
+
+import (
+	"encoding/binary"
+	"io"
+	"slices"
+)
+
+// readChunk caps how much DecodeReader allocates ahead of the bytes it
+// has actually received, so a forged length cannot force a huge buffer.
+const readChunk = 64 << 10
+
+// DecodeReader reads exactly one CBOR item from r and decodes it into v
+// with its Safe path. Unlike a Decoder it never reads past the item: each
+// head is read first and only the bytes it announces follow, so r is left
+// at whatever comes after, such as the next item of a sequence. An empty
+// r yields io.EOF and one ending partway through the item
+// io.ErrUnexpectedEOF.
+//
+//	var req Request
+//	if err := cbor.DecodeReader(http.MaxBytesReader(w, r.Body, 1<<20), &req); err != nil {
+//		...
+//	}
+func DecodeReader(r io.Reader, v Unmarshaler) error {
+	var lead [1]byte
+	if _, err := io.ReadFull(r, lead[:]); err != nil {
+		return err
+	}
+	item, err := readItemBody(r, lead[:], 0)
+	if err != nil {
+		return err
+	}
+	_, err = v.UnmarshalCBOR(item)
+	return err
+}
+
+// ReadFrom reads exactly one CBOR item from r and decodes it into v with
+// its Safe path, returning the number of bytes the item took. Like
+// DecodeReader it never reads past the item, so successive calls walk a
+// CBOR sequence. As io.ReaderFrom reserves io.EOF for success, an empty r
+// yields io.ErrUnexpectedEOF. It backs the ReadFrom methods cborgen
+// generates with --io-adapters.
+func ReadFrom(r io.Reader, v Unmarshaler) (int64, error) {
+	item, err := readItem(r, nil, 0)
+	if err != nil {
+		return int64(len(item)), err
+	}
+	_, err = v.UnmarshalCBOR(item)
+	return int64(len(item)), err
+}
+
+// readItem appends the next complete item read from r to b.
+func readItem(r io.Reader, b []byte, depth int) ([]byte, error) {
+	b, err := readN(r, b, 1)
+	if err != nil {
+		return b, err
+	}
+	return readItemBody(r, b, depth)
+}
+
+// readItemBody reads the rest of the item whose initial byte is the last
+// byte of b, appending it to b.
+func readItemBody(r io.Reader, b []byte, depth int) ([]byte, error) {
+	if depth > recursionLimit {
+		return b, ErrMaxDepthExceeded
+	}
+	lead := b[len(b)-1]
+	major, add := getMajorType(lead), getAddInfo(lead)
+	var arg uint64
+	switch {
+	case add < addInfoUint8:
+		arg = uint64(add)
+	case add <= addInfoUint64:
+		n := 1 << (add - addInfoUint8)
+		var err error
+		if b, err = readN(r, b, uint64(n)); err != nil {
+			return b, err
+		}
+		var be [8]byte
+		copy(be[8-n:], b[len(b)-n:])
+		arg = binary.BigEndian.Uint64(be[:])
+	case add == addInfoIndefinite:
+		switch major {
+		case majorTypeBytes, majorTypeText:
+			return readIndefinite(r, b, depth, func(b []byte) ([]byte, error) {
+				// Chunks are definite-length strings of the same type.
+				lead := b[len(b)-1]
+				if getMajorType(lead) != major || getAddInfo(lead) == addInfoIndefinite {
+					return b, ErrMalformedHead
+				}
+				return readItemBody(r, b, depth+1)
+			})
+		case majorTypeArray:
+			return readIndefinite(r, b, depth, func(b []byte) ([]byte, error) {
+				return readItemBody(r, b, depth+1)
+			})
+		case majorTypeMap:
+			return readIndefinite(r, b, depth, func(b []byte) ([]byte, error) {
+				b, err := readItemBody(r, b, depth+1)
+				if err != nil {
+					return b, err
+				}
+				return readItem(r, b, depth+1)
+			})
+		}
+		return b, ErrMalformedHead
+	default:
+		return b, ErrMalformedHead
+	}
+
+	var err error
+	if major == majorTypeArray || major == majorTypeMap {
+		if err = checkElements(arg); err != nil {
+			return b, err
+		}
+	}
+	switch major {
+	case majorTypeBytes, majorTypeText:
+		b, err = readN(r, b, arg)
+	case majorTypeArray:
+		for i := uint64(0); i < arg && err == nil; i++ {
+			b, err = readItem(r, b, depth+1)
+		}
+	case majorTypeMap:
+		for i := uint64(0); i < arg && err == nil; i++ {
+			if b, err = readItem(r, b, depth+1); err == nil {
+				b, err = readItem(r, b, depth+1)
+			}
+		}
+	case majorTypeTag:
+		b, err = readItem(r, b, depth+1)
+	}
+	return b, err
+}
+
+// readIndefinite reads the elements of an indefinite-length item up to
+// its break, calling elem with each element's initial byte appended.
+func readIndefinite(r io.Reader, b []byte, depth int, elem func([]byte) ([]byte, error)) ([]byte, error) {
+	for n := uint64(1); ; n++ {
+		var err error
+		if b, err = readN(r, b, 1); err != nil {
+			return b, err
+		}
+		if b[len(b)-1] == makeByte(majorTypeSimple, simpleBreak) {
+			return b, nil
+		}
+		if err = checkElements(n); err != nil {
+			return b, err
+		}
+		if b, err = elem(b); err != nil {
+			return b, err
+		}
+	}
+}
+
+// readN appends n bytes read from r to b, growing b at most readChunk
+// bytes beyond what has been read so far.
+func readN(r io.Reader, b []byte, n uint64) ([]byte, error) {
+	for n > 0 {
+		k := int(min(n, readChunk))
+		b = slices.Grow(b, k)
+		if _, err := io.ReadFull(r, b[len(b):len(b)+k]); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return b, err
+		}
+		b = b[:len(b)+k]
+		n -= uint64(k)
+	}
+	return b, nil
+}
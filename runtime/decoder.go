@@ -0,0 +1,210 @@
+package cbor
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"time"
+)
+
+// decoderReadSize is the minimum number of bytes Decoder asks its reader for.
+const decoderReadSize = 4 << 10
+
+// internUnmarshaler is implemented by generated types, whose Safe decode
+// path can share repeated strings through an Interner.
+type internUnmarshaler interface {
+	DecodeInterned(b []byte, in *Interner) ([]byte, error)
+}
+
+// Decoder reads a stream of CBOR items (an RFC 8742 CBOR sequence) from
+// an io.Reader, decoding one complete item per Decode call. It reads
+// ahead in chunks, so bytes past the current item may be consumed from
+// the underlying reader.
+//
+// A Decoder is not safe for concurrent use.
+type Decoder struct {
+	r      io.Reader
+	buf    []byte // buf[off:] holds bytes read but not yet decoded
+	off    int
+	err    error // sticky read error
+	intern *Interner
+	pos    int64 // stream offset of buf[off]
+
+	onError   func(*ItemError)
+	resyncing bool // dropping bytes after malformed framing
+
+	onComplete func(ItemStats)
+
+	envelope    uint64 // tag every item must be wrapped in, if hasEnvelope
+	hasEnvelope bool
+}
+
+// ItemError reports an item of a CBOR sequence that a Decoder in
+// ContinueOnError mode skipped. Offset is the item's byte offset in the
+// stream.
+type ItemError struct {
+	Offset int64
+	Err    error
+}
+
+// Error implements the error interface
+func (e *ItemError) Error() string {
+	return "cbor: item at offset " + strconv.FormatInt(e.Offset, 10) + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *ItemError) Unwrap() error { return e.Err }
+
+// NewDecoder returns a Decoder reading from r.
+func NewDecoder(r io.Reader) *Decoder { return &Decoder{r: r} }
+
+// SetInternStrings controls whether text strings decoded through the
+// Safe path share storage when their bytes repeat, which saves an
+// allocation per repeated value (status codes, subjects, map keys).
+// The intern table belongs to this Decoder, so it is released with it;
+// turning the option off discards it.
+func (d *Decoder) SetInternStrings(on bool) {
+	switch {
+	case on && d.intern == nil:
+		d.intern = NewInterner()
+	case !on:
+		d.intern = nil
+	}
+}
+
+// ContinueOnError makes Decode skip items that fail instead of returning
+// their error, passing each failure to onError and moving on to the next
+// item, so one corrupt record does not abort a batch. An item that is
+// well-formed but does not decode into v is skipped whole. Malformed
+// framing leaves no item boundary to skip to, so bytes are dropped one at
+// a time until an item parses again; each such run is reported once, at
+// the offset where it started. Read errors, io.EOF and a stream ending
+// partway through an item are still returned. A nil onError turns the
+// mode off.
+func (d *Decoder) ContinueOnError(onError func(*ItemError)) {
+	d.onError = onError
+}
+
+// OnComplete makes Decode call fn after each item it decodes, or fails
+// to, with its size and the time spent decoding it, for metrics or
+// tracing. Items skipped under ContinueOnError are reported too; bytes
+// dropped to resynchronize after malformed framing are not. A nil fn
+// turns the hook off; unset, it costs Decode a nil check.
+func (d *Decoder) OnComplete(fn func(ItemStats)) {
+	d.onComplete = fn
+}
+
+// SetExpectTag makes Decode require every item to be wrapped in tag, as
+// protocols with a per-message envelope tag do, and strip it before
+// decoding the content into v. An item without the tag fails with
+// InvalidPrefixError and one with another tag with UnexpectedTagError;
+// either way it is consumed, so ContinueOnError can skip it.
+func (d *Decoder) SetExpectTag(tag uint64) {
+	d.envelope, d.hasEnvelope = tag, true
+}
+
+// ClearExpectTag undoes SetExpectTag, decoding items as they are.
+func (d *Decoder) ClearExpectTag() {
+	d.envelope, d.hasEnvelope = 0, false
+}
+
+// Buffered returns the bytes read from the underlying reader but not yet
+// decoded. It is valid until the next call to Decode.
+func (d *Decoder) Buffered() []byte { return d.buf[d.off:] }
+
+// Decode reads the next item from the stream and decodes it into v with
+// its Safe path. At the end of the stream it returns io.EOF; a stream
+// ending partway through an item yields io.ErrUnexpectedEOF. See
+// ContinueOnError for skipping items that fail.
+func (d *Decoder) Decode(v Unmarshaler) error {
+	for {
+		start := d.pos
+		n, malformed, err := d.next()
+		if err != nil {
+			if !malformed || d.onError == nil {
+				return err
+			}
+			if !d.resyncing {
+				d.onError(&ItemError{Offset: start, Err: err})
+				d.resyncing = true
+			}
+			d.advance(1)
+			continue
+		}
+		d.resyncing = false
+		item := d.buf[d.off : d.off+n]
+		// Consume the item even if decoding fails so the stream can continue.
+		d.advance(n)
+		var began time.Time
+		if d.onComplete != nil {
+			began = time.Now()
+		}
+		if d.hasEnvelope {
+			item, err = ReadExpectedTagBytes(item, d.envelope)
+		}
+		switch iu, ok := v.(internUnmarshaler); {
+		case err != nil:
+			// The item is not in the expected envelope.
+		case ok && d.intern != nil:
+			_, err = iu.DecodeInterned(item, d.intern)
+		default:
+			_, err = v.UnmarshalCBOR(item)
+		}
+		if d.onComplete != nil {
+			d.onComplete(ItemStats{Value: v, Size: n, Duration: time.Since(began), Err: err})
+		}
+		if err == nil || d.onError == nil {
+			return err
+		}
+		d.onError(&ItemError{Offset: start, Err: err})
+	}
+}
+
+// advance consumes n buffered bytes.
+func (d *Decoder) advance(n int) {
+	d.off += n
+	d.pos += int64(n)
+}
+
+// next makes sure a complete item is buffered and returns its length.
+// malformed reports that the buffered bytes do not start a well-formed
+// item, as opposed to a read error or the end of the stream.
+func (d *Decoder) next() (n int, malformed bool, err error) {
+	for {
+		pending := d.buf[d.off:]
+		if len(pending) > 0 {
+			rest, err := Skip(pending)
+			if err == nil {
+				return len(pending) - len(rest), false, nil
+			}
+			if !errors.Is(err, ErrShortBytes) {
+				return 0, true, err
+			}
+		}
+		if d.err != nil {
+			if d.err == io.EOF && len(pending) > 0 {
+				return 0, false, io.ErrUnexpectedEOF
+			}
+			return 0, false, d.err
+		}
+		d.fill()
+	}
+}
+
+// fill reads more bytes from the underlying reader. Decoded items may
+// alias the buffer (e.g. byte strings and Raw values), so bytes already
+// handed out are never overwritten: when there is no spare capacity the
+// pending bytes move to a new buffer instead of being shifted down.
+func (d *Decoder) fill() {
+	pending := d.buf[d.off:]
+	if cap(d.buf)-len(d.buf) < decoderReadSize/2 {
+		buf := make([]byte, len(pending), max(2*len(pending), decoderReadSize))
+		copy(buf, pending)
+		d.buf, d.off = buf, 0
+	}
+	n, err := d.r.Read(d.buf[len(d.buf):cap(d.buf)])
+	d.buf = d.buf[:len(d.buf)+n]
+	if err != nil {
+		d.err = err
+	}
+}
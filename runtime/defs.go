@@ -34,6 +34,11 @@ const (
     recursionLimit = 100000
 )
 
+// MaxEncodeDepth bounds how deeply generated encoders for recursive
+// (self-referential) types will descend before giving up with
+// ErrCycleDetected. Acyclic values nested deeper than this also fail.
+var MaxEncodeDepth = 10000
+
 // ErrNonCanonicalFloat is returned when a float is not encoded in the shortest form (strict mode).
 var ErrNonCanonicalFloat = errors.New("cbor: non-canonical float encoding")
 
@@ -92,6 +97,8 @@ const (
 	tagBase64String     = 34    // base64
 	tagRegexp           = 35    // Regular expression
 	tagMIME             = 36    // MIME message
+	tagIPv4             = 52    // IPv4 address (RFC 9164)
+	tagIPv6             = 54    // IPv6 address (RFC 9164)
 	tagSelfDescribeCBOR = 55799 // Self-describe CBOR (0xd9d9f7)
 )
 
@@ -173,6 +180,24 @@ type Marshaler interface {
 	MarshalCBOR([]byte) ([]byte, error)
 }
 
+// EncodeOptions are settings passed to a single encode, for generated
+// types with versioned fields: their AppendCBORWith method takes them and
+// hands them on to the generated structs inside. AppendCBOR and
+// MarshalCBOR encode with the zero EncodeOptions.
+type EncodeOptions struct {
+	// SchemaVersion is the version of the schema to write. Fields tagged
+	// "sinceversion=N" are left out when it is below N, so a peer still
+	// on an older schema receives only the fields it knows. Zero, the
+	// default, writes the latest schema: every field.
+	SchemaVersion int
+}
+
+// Omits reports whether a field added in schema version since is left
+// out under o.
+func (o EncodeOptions) Omits(since int) bool {
+	return o.SchemaVersion != 0 && o.SchemaVersion < since
+}
+
 // Unmarshaler is the interface fulfilled by objects that know how to unmarshal
 // themselves from CBOR. UnmarshalCBOR unmarshals the object from binary,
 // returning any leftover bytes and any errors encountered.
@@ -180,10 +205,137 @@ type Unmarshaler interface {
 	UnmarshalCBOR([]byte) ([]byte, error)
 }
 
+// Validator is implemented by types with invariants beyond what their
+// encoding can express, such as a non-empty name or a known enum value.
+type Validator interface {
+	Validate() error
+}
+
+// ValidateDecoded returns v.Validate() if v implements Validator and
+// SkipValidateHooks is not set, and nil otherwise. Generated DecodeSafe
+// methods call it on the value they have just filled in; DecodeTrusted
+// does not.
+func ValidateDecoded(v any) error {
+	if SkipValidateHooks {
+		return nil
+	}
+	if vv, ok := v.(Validator); ok {
+		return vv.Validate()
+	}
+	return nil
+}
+
+// AllowInvalidUTF8 turns off the UTF-8 check on text strings. CBOR text
+// must be valid UTF-8, so by default ReadStringBytes, and with it
+// generated DecodeSafe methods and Unmarshal, fail with ErrInvalidUTF8
+// rather than return a string holding invalid sequences. Set it to accept
+// such strings as they are, for instance from a peer known to send them.
+// DecodeTrusted never validates.
+var AllowInvalidUTF8 = false
+
 // ValidateUTF8OnDecode controls whether ReadStringBytes validates UTF-8.
-// Enabled by default for spec compliance; can be disabled in hot paths.
+//
+// Deprecated: Set AllowInvalidUTF8 instead. Setting either one turns the
+// check off.
 var ValidateUTF8OnDecode = true
 
+// MaxElements caps the number of elements in any single array, or
+// entries in any single map, that the decoders accept: definite lengths
+// are checked at the header and indefinite-length containers as they are
+// read, so a few bytes of input cannot claim, or add up to, an enormous
+// structure. Exceeding it is an ErrContainerTooLarge error. The default
+// of 1<<20 leaves plenty of room for real data; raise it for larger
+// containers.
+var MaxElements = 1 << 20
+
+// ResetBeforeDecode controls whether generated DecodeSafe/DecodeTrusted
+// clear the destination before decoding, so fields absent from the
+// payload do not keep values from an earlier decode. Slices are truncated
+// to zero length and maps cleared, keeping their storage for reuse.
+// Disabled by default: fields not present in the payload are left as is.
+var ResetBeforeDecode = false
+
+// IncludeHexContext makes generated DecodeSafe methods and Unmarshal fill
+// in the Snippet of the *DecodeError they return: the input bytes around
+// the failing offset, in hex. Disabled by default, since the snippet
+// copies payload bytes into error messages and from there into logs.
+var IncludeHexContext = false
+
+// SkipUnknownTags controls how generated decoders treat a tag wrapping
+// the value of a plain field: a string, number, bool, byte string, slice
+// or map, whose type gives tags no meaning. When false (the default) the
+// decode fails with UnexpectedTagError naming the tag; when true the tags
+// are skipped and their content is decoded into the field. Fields that
+// interpret tags themselves, such as time.Time, *url.URL, cbor.Number or
+// "tag=N" fields, still reject tags they do not expect.
+var SkipUnknownTags = false
+
+// TimeUTC normalizes times to UTC. AppendRFC3339Time writes tag 0 text
+// with a "Z" offset, and ReadRFC3339TimeBytes and ReadTimeBytes return
+// their time.Time in time.UTC. Disabled by default: tag 0 keeps the
+// offset of the time it was given and returns it in a zone with that
+// offset, and tag 1, which carries no offset, decodes in time.Local.
+var TimeUTC = false
+
+// LenientByteStrings makes the byte and text string readers accept either
+// major type: ReadBytesBytes returns the UTF-8 bytes of a text string and
+// ReadStringBytes/ReadStringZC return the contents of a byte string as
+// text, so generated []byte and string fields accept both. This also
+// applies to text map keys. UTF-8 validation still follows
+// AllowInvalidUTF8. Disabled by default: a mismatched major type is
+// an error.
+var LenientByteStrings = false
+
+// CoerceNumbers lets integer and float encodings stand in for each other
+// on decode. The integer readers accept a float holding a whole number in
+// range of the destination; a fractional, NaN, infinite or out-of-range
+// float fails with FloatConversionError, and narrower types still report
+// IntOverflow or UintOverflow. The float readers accept any integer,
+// rounded to the nearest representable value as a Go conversion would.
+// Disabled by default: a mismatched major type is an error.
+var CoerceNumbers = false
+
+// NarrowFloats lets a double precision float that float32 cannot hold
+// exactly decode into a float32, rounded to the nearest value (and to
+// ±Inf past its range). Disabled by default: such a float fails with
+// FloatConversionError, while one float32 holds exactly, and half and
+// single precision floats, decode either way.
+var NarrowFloats = false
+
+// TolerateExtraArrayElements controls how generated decoders of toarray
+// structs treat an array with more elements than the struct has fields.
+// When false (the default) the decode fails with ArrayError; when true
+// the extra trailing elements are skipped, so records written by a newer
+// schema that appended fields still decode.
+var TolerateExtraArrayElements = false
+
+// AllowTrailingBytes controls the one-shot decoders: Unmarshal and the
+// NewTFromCBOR functions and TCompat.UnmarshalCBOR methods cborgen
+// generates. When false (the default) bytes left over after the item fail
+// with ErrTrailingBytes, as they usually point to a framing bug; when true
+// they are ignored, or returned by NewTFromCBOR. Decode methods such as
+// DecodeSafe always return the rest and leave it to the caller.
+var AllowTrailingBytes = false
+
+// ZeroCopyStrings controls how generated DecodeTrusted methods build
+// text strings. When true every decoded string, including slice elements
+// and map keys and values, points into the input buffer instead of being
+// copied, which removes an allocation per string. Disabled by default:
+// DecodeTrusted then copies strings but still skips UTF-8 validation, and
+// DecodeSafe always copies.
+//
+// WARNING: such strings alias the buffer. The caller must neither modify
+// nor reuse the buffer (e.g. return it to a pool or read the next message
+// into it) while any decoded value is still in use; doing so silently
+// changes strings that Go treats as immutable, and may corrupt maps keyed
+// by them. Only set ZeroCopyStrings when the buffer outlives every value
+// decoded from it.
+var ZeroCopyStrings = false
+
+// SkipValidateHooks turns off the Validate calls made by generated
+// DecodeSafe methods (see ValidateDecoded). Disabled by default.
+var SkipValidateHooks = false
+
 // UnsafeStringDecode controls whether ReadStringBytes converts zero-copy using
 // UnsafeString (unsafe) instead of allocating a new string. Disabled by default.
 var UnsafeStringDecode = false
@@ -0,0 +1,38 @@
+package cbor
+
+import "encoding/hex"
+
+// AppendDiag appends v to b in diagnostic notation, rendering the item
+// Marshal encodes it as. It backs the DiagString methods generated with
+// -diag for field types they do not render directly. An encoding error is
+// written as a comment, /error: .../, so a log line is never lost.
+func AppendDiag(b []byte, v any) []byte {
+	enc, err := Marshal(v)
+	if err == nil {
+		var d string
+		if d, _, err = DiagBytes(enc); err == nil {
+			return append(b, d...)
+		}
+	}
+	return append(append(append(b, "/error: "...), err.Error()...), '/')
+}
+
+// AppendDiagBytes appends p to b as a byte string in diagnostic notation,
+// h'...'.
+func AppendDiagBytes(b, p []byte) []byte {
+	b = append(b, "h'"...)
+	b = hex.AppendEncode(b, p)
+	return append(b, '\'')
+}
+
+// AppendDiagFloat64 appends f to b in diagnostic notation, as DiagBytes
+// renders an encoded float.
+func AppendDiagFloat64(b []byte, f float64) []byte {
+	return append(b, formatFloat64Diag(f)...)
+}
+
+// AppendDiagFloat32 appends f to b in diagnostic notation, as DiagBytes
+// renders an encoded float.
+func AppendDiagFloat32(b []byte, f float32) []byte {
+	return append(b, formatFloat32Diag(f)...)
+}
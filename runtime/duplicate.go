@@ -0,0 +1,55 @@
+package cbor
+
+// DuplicateKeyPolicy selects what decoders do with a map that repeats a
+// key. RFC 8949 makes such maps invalid, but producers differ.
+type DuplicateKeyPolicy uint8
+
+const (
+	// DuplicateKeyError fails the decode with ErrDuplicateMapKey.
+	DuplicateKeyError DuplicateKeyPolicy = iota
+	// DuplicateKeyFirst keeps the first value and skips later ones.
+	DuplicateKeyFirst
+	// DuplicateKeyLast keeps the last value, each one overwriting the
+	// one before.
+	DuplicateKeyLast
+)
+
+// DuplicateMapKeys is the policy applied to repeated map keys by
+// generated Safe decoders, for both struct keys and map-typed fields, by
+// ReadInterfaceBytes and by Unmarshal. Generated Trusted decoders do not
+// look for duplicates and keep the last value.
+//
+// Detecting a duplicate means remembering every key already read: a bit
+// per field for structs, a lookup in the map being filled for maps.
+// DuplicateKeyLast skips that work.
+var DuplicateMapKeys = DuplicateKeyError
+
+// SkipDuplicate applies DuplicateMapKeys to a repeated map key whose
+// value starts at v. Under DuplicateKeyFirst it returns v past the value
+// with skip set; under DuplicateKeyLast it returns v unchanged, to be
+// decoded over the earlier value; otherwise it fails with
+// ErrDuplicateMapKey.
+func SkipDuplicate(v []byte) (o []byte, skip bool, err error) {
+	switch DuplicateMapKeys {
+	case DuplicateKeyLast:
+		return v, false, nil
+	case DuplicateKeyFirst:
+		if o, err = Skip(v); err != nil {
+			return v, false, err
+		}
+		return o, true, nil
+	}
+	return v, false, ErrDuplicateMapKey
+}
+
+// DuplicateField records in seen that a generated Safe decoder has read
+// the key of field i, whose value starts at v. If the key was read
+// before, it applies DuplicateMapKeys like SkipDuplicate.
+func DuplicateField(v []byte, seen []uint64, i int) (o []byte, skip bool, err error) {
+	bit := uint64(1) << (i % 64)
+	if seen[i/64]&bit == 0 {
+		seen[i/64] |= bit
+		return v, false, nil
+	}
+	return SkipDuplicate(v)
+}
@@ -0,0 +1,47 @@
+package cbor
+
+// AppendEmbeddedRaw appends raw as a tag(24) encoded CBOR data item: the
+// tag followed by a byte string holding raw. An empty raw is written as
+// null, matching Raw.MarshalCBOR.
+func AppendEmbeddedRaw(b []byte, raw RawMessage) []byte {
+	if len(raw) == 0 {
+		return AppendNil(b)
+	}
+	return AppendEmbeddedCBOR(b, raw)
+}
+
+// ReadEmbeddedRawBytes reads a tag(24) encoded CBOR data item and copies
+// its payload into raw, reusing raw's capacity. The payload must be
+// exactly one well-formed CBOR item; otherwise the validation error or
+// ErrTrailingBytes is returned. A null or undefined sets raw to nil.
+func ReadEmbeddedRawBytes(b []byte, raw *RawMessage) (o []byte, err error) {
+	return readEmbeddedRaw(b, raw, true)
+}
+
+// ReadEmbeddedRawTrustedBytes is ReadEmbeddedRawBytes without the
+// well-formedness check of the payload, for input from a trusted peer.
+func ReadEmbeddedRawTrustedBytes(b []byte, raw *RawMessage) (o []byte, err error) {
+	return readEmbeddedRaw(b, raw, false)
+}
+
+func readEmbeddedRaw(b []byte, raw *RawMessage, validate bool) ([]byte, error) {
+	if IsNilOrUndefined(b) {
+		*raw = nil
+		return b[1:], nil
+	}
+	payload, o, err := ReadEmbeddedCBORBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if validate {
+		rest, err := ValidateWellFormedBytes(payload)
+		if err != nil {
+			return b, err
+		}
+		if len(rest) != 0 {
+			return b, ErrTrailingBytes
+		}
+	}
+	*raw = append((*raw)[:0], payload...)
+	return o, nil
+}
@@ -0,0 +1,178 @@
+package cbor
+
+import (
+	"io"
+	"time"
+)
+
+const (
+	// encoderFlushSize is the buffered size at which Encode flushes on its own.
+	encoderFlushSize = 64 << 10
+	// encoderPooledSize is the initial pooled buffer size for a new Encoder.
+	encoderPooledSize = 4 << 10
+)
+
+// Encoder writes a stream of CBOR items (an RFC 8742 CBOR sequence) to an
+// io.Writer. Items are buffered and written on Flush, or automatically
+// once more than 64 KiB is pending; call Flush after the last item.
+//
+// An Encoder is not safe for concurrent use.
+type Encoder struct {
+	w      io.Writer
+	buf    []byte
+	pooled bool
+	hint   int
+
+	envelope    uint64 // tag Encode wraps every item in, if hasEnvelope
+	hasEnvelope bool
+
+	onComplete func(ItemStats)
+}
+
+// StreamMarshaler is implemented by types generated with cborgen --stream,
+// whose MarshalCBORStream writes the value to an Encoder incrementally.
+type StreamMarshaler interface {
+	MarshalCBORStream(enc *Encoder) error
+}
+
+// NewEncoder returns an Encoder writing to w.
+func NewEncoder(w io.Writer) *Encoder { return &Encoder{w: w, hint: encoderPooledSize} }
+
+// SetPooledBuffers controls whether the Encoder takes its scratch buffer
+// from the shared GetBuffer pool and hands it back on every Flush,
+// instead of keeping a private buffer for its lifetime. This suits
+// short-lived encoders created per request, where it avoids allocating
+// a fresh buffer each time.
+func (e *Encoder) SetPooledBuffers(on bool) {
+	if !on && e.pooled && e.buf != nil {
+		// Keep the current contents but stop returning it to the pool.
+		e.buf = append([]byte(nil), e.buf...)
+	}
+	e.pooled = on
+}
+
+// SetWrapTag makes Encode wrap every item in tag, the envelope a Decoder
+// with SetExpectTag(tag) strips. EncodeFunc, and so MarshalCBORStream,
+// writes its bytes unwrapped.
+func (e *Encoder) SetWrapTag(tag uint64) {
+	e.envelope, e.hasEnvelope = tag, true
+}
+
+// ClearWrapTag undoes SetWrapTag, encoding items as they are.
+func (e *Encoder) ClearWrapTag() {
+	e.envelope, e.hasEnvelope = 0, false
+}
+
+// OnComplete makes Encode call fn after each item with its size and the
+// time spent encoding it, for metrics or tracing. EncodeFunc, and so
+// MarshalCBORStream, is not reported. A nil fn turns the hook off; unset,
+// it costs Encode a nil check.
+func (e *Encoder) OnComplete(fn func(ItemStats)) {
+	e.onComplete = fn
+}
+
+// Buffered returns the number of encoded bytes not yet written.
+func (e *Encoder) Buffered() int { return len(e.buf) }
+
+// Encode appends the encoding of v to the stream. If v fails to encode,
+// nothing is written for it.
+func (e *Encoder) Encode(v Marshaler) error {
+	fn := v.MarshalCBOR
+	if e.hasEnvelope {
+		fn = func(b []byte) ([]byte, error) {
+			return v.MarshalCBOR(AppendTag(b, e.envelope))
+		}
+	}
+	if e.onComplete == nil {
+		return e.EncodeFunc(fn)
+	}
+	stats := ItemStats{Value: v}
+	err := e.EncodeFunc(func(b []byte) ([]byte, error) {
+		n, start := len(b), time.Now()
+		b, err := fn(b)
+		stats.Duration = time.Since(start)
+		if err == nil {
+			stats.Size = len(b) - n
+		}
+		stats.Err = err
+		return b, err
+	})
+	e.onComplete(stats)
+	return err
+}
+
+// EncodeFunc appends whatever fn appends to the buffer it is given, which
+// need not be a complete item: generated MarshalCBORStream methods use it
+// to write a map or array header, then each element, then a break, so the
+// pending output can be flushed between elements. If fn fails, nothing it
+// appended is written.
+func (e *Encoder) EncodeFunc(fn func(b []byte) ([]byte, error)) error {
+	if e.buf == nil && e.pooled {
+		e.buf = GetBuffer(e.hint)
+	}
+	n := len(e.buf)
+	b, err := fn(e.buf)
+	if err != nil {
+		// fn may return nil or a buffer of its own: keep the pending
+		// items where they were.
+		e.buf = e.buf[:n]
+		return err
+	}
+	if e.pooled && cap(b) != cap(e.buf) {
+		// The item outgrew the scratch buffer; recycle the old one.
+		PutBuffer(e.buf)
+	}
+	e.buf = b
+	if len(e.buf) > encoderFlushSize {
+		return e.Flush()
+	}
+	return nil
+}
+
+// Flush writes any buffered items to the underlying writer. With pooled
+// buffers enabled the scratch buffer is returned to the pool afterwards.
+func (e *Encoder) Flush() error {
+	if len(e.buf) > 0 {
+		n, err := e.w.Write(e.buf)
+		if err != nil {
+			// Keep the unwritten tail for a later retry.
+			e.buf = e.buf[:copy(e.buf, e.buf[n:])]
+			return err
+		}
+	}
+	if e.pooled {
+		if e.buf != nil {
+			e.hint = max(len(e.buf), encoderPooledSize)
+			PutBuffer(e.buf)
+			e.buf = nil
+		}
+		return nil
+	}
+	e.buf = e.buf[:0]
+	return nil
+}
+
+// MarshalTo encodes m into a pooled scratch buffer of at least sizeHint
+// bytes and writes the result to w in a single Write, returning the
+// number of bytes written. Nothing is written if m fails to encode. It
+// backs the MarshalCBORTo methods cborgen generates; to write a large
+// value in pieces as it is encoded, use an Encoder and MarshalCBORStream
+// instead.
+func MarshalTo(w io.Writer, m Marshaler, sizeHint int) (int, error) {
+	buf := GetBuffer(sizeHint)
+	b, err := m.MarshalCBOR(buf)
+	if cap(b) != cap(buf) {
+		// The encoding outgrew the scratch buffer; recycle both.
+		PutBuffer(buf)
+	}
+	if err != nil {
+		PutBuffer(b)
+		return 0, err
+	}
+	n, err := w.Write(b)
+	if err == nil && n < len(b) {
+		err = io.ErrShortWrite
+	}
+	PutBuffer(b)
+	return n, err
+}
@@ -0,0 +1,45 @@
+package cbor
+
+// ReadExpectedTagBytes strips the tag wrapping the item at the start of b,
+// which must be tag. It returns UnexpectedTagError if b starts with a
+// different tag and InvalidPrefixError if it does not start with a tag at
+// all.
+func ReadExpectedTagBytes(b []byte, tag uint64) (o []byte, err error) {
+	if len(b) < 1 {
+		return b, ErrShortBytes
+	}
+	if getMajorType(b[0]) != majorTypeTag {
+		return b, badPrefix(majorTypeTag, getMajorType(b[0]))
+	}
+	got, o, err := ReadTagBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if got != tag {
+		return b, UnexpectedTagError{Tag: got}
+	}
+	return o, nil
+}
+
+// UnmarshalTagged decodes the item at the start of b into v after
+// stripping its envelope tag, which must be tag, and returns the bytes
+// after the item. It is the counterpart of MarshalTagged for protocols
+// that wrap every message in one tag.
+func UnmarshalTagged(b []byte, tag uint64, v Unmarshaler) ([]byte, error) {
+	o, err := ReadExpectedTagBytes(b, tag)
+	if err != nil {
+		return b, err
+	}
+	return v.UnmarshalCBOR(o)
+}
+
+// MarshalTagged appends v to b wrapped in tag. If v fails to encode, b is
+// returned with nothing appended.
+func MarshalTagged(b []byte, tag uint64, v Marshaler) ([]byte, error) {
+	n := len(b)
+	o, err := v.MarshalCBOR(AppendTag(b, tag))
+	if err != nil {
+		return b[:n], err
+	}
+	return o, nil
+}
@@ -1,6 +1,7 @@
 package cbor
 
 import (
+	"encoding/hex"
 	"errors"
 	"reflect"
 	"strconv"
@@ -45,6 +46,25 @@ var (
 	// ErrNonCanonicalLength is returned when a length (array/map/str/bytes) is not encoded in the shortest form.
 	ErrNonCanonicalLength error = errors.New("cbor: non-canonical length encoding")
 
+	// ErrCycleDetected is returned by generated encoders for recursive types
+	// when the nesting depth exceeds MaxEncodeDepth, which in practice means
+	// the value graph contains a cycle.
+	ErrCycleDetected error = errors.New("cbor: cycle detected")
+
+	// ErrInvalidSimpleValue is returned when a simple value is expected but
+	// the item is a float, a break, or a reserved simple value (24..31).
+	ErrInvalidSimpleValue error = errors.New("cbor: invalid simple value")
+
+	// ErrMalformedHead is returned when an item's initial byte uses the
+	// reserved additional information values 28..30, or an indefinite
+	// length where a definite argument is required.
+	ErrMalformedHead error = errors.New("cbor: malformed item head")
+
+	// ErrTrailingBytes is returned when input that must hold exactly one
+	// item, such as the payload of a tag 24 embedded data item, has bytes
+	// left over after it.
+	ErrTrailingBytes error = errors.New("cbor: trailing bytes after item")
+
 )
 
 // Error is the interface satisfied
@@ -225,6 +245,30 @@ func (u UintOverflow) Resumable() bool { return true }
 
 func (u UintOverflow) withContext(ctx string) error { u.ctx = addCtx(u.ctx, ctx); return u }
 
+// FloatConversionError is returned when CoerceNumbers is set and
+// a float read as an integer is not a whole number that fits, and when
+// a double precision float read as a float32 would lose precision and
+// NarrowFloats is not set.
+type FloatConversionError struct {
+	Value float64 // the value of the float
+	Type  string  // the type it was read as, "int64", "uint64" or "float32"
+	ctx   string
+}
+
+// Error implements the error interface
+func (f FloatConversionError) Error() string {
+	str := "cbor: float " + strconv.FormatFloat(f.Value, 'g', -1, 64) + " does not convert to " + f.Type
+	if f.ctx != "" {
+		str += " at " + f.ctx
+	}
+	return str
+}
+
+// Resumable is always 'true' for FloatConversionError
+func (f FloatConversionError) Resumable() bool { return true }
+
+func (f FloatConversionError) withContext(ctx string) error { f.ctx = addCtx(f.ctx, ctx); return f }
+
 // InvalidTimestamp is returned when an invalid timestamp is encountered
 type InvalidTimestamp struct {
 	Nanos       int64 // value of the nano, if invalid
@@ -316,12 +360,156 @@ type InvalidPrefixError struct {
 
 // Error implements the error interface
 func (i InvalidPrefixError) Error() string {
-	return "cbor: expected major type " + strconv.Itoa(int(i.Want)) + " but got " + strconv.Itoa(int(i.Got))
+	return "cbor: expected major type " + majorTypeName(i.Want) + " but got " + majorTypeName(i.Got)
+}
+
+// majorTypeName returns a major type number followed by its name, e.g.
+// "3 (text string)".
+func majorTypeName(m uint8) string {
+	var name string
+	switch m {
+	case majorTypeUint:
+		name = "unsigned integer"
+	case majorTypeNegInt:
+		name = "negative integer"
+	case majorTypeBytes:
+		name = "byte string"
+	case majorTypeText:
+		name = "text string"
+	case majorTypeArray:
+		name = "array"
+	case majorTypeMap:
+		name = "map"
+	case majorTypeTag:
+		name = "tag"
+	case majorTypeSimple:
+		name = "float or simple value"
+	default:
+		return strconv.Itoa(int(m))
+	}
+	return strconv.Itoa(int(m)) + " (" + name + ")"
 }
 
 // Resumable returns 'false' for InvalidPrefixErrors
 func (i InvalidPrefixError) Resumable() bool { return false }
 
+// UnexpectedTagError is returned when an item carries a tag its
+// destination does not accept, such as tag 33 where a tag 32 URI is
+// expected or, unless SkipUnknownTags is set, any tag on a plain field.
+type UnexpectedTagError struct {
+	Tag uint64
+}
+
+// Error implements the error interface
+func (e UnexpectedTagError) Error() string {
+	return "cbor: unexpected tag " + strconv.FormatUint(e.Tag, 10)
+}
+
+// Resumable returns 'true' for UnexpectedTagError
+func (e UnexpectedTagError) Resumable() bool { return true }
+
+// DecodeError locates a failure reported by a generated Safe decoder.
+// Offset is the byte offset, within the buffer passed to DecodeSafe, of
+// the item that could not be decoded; Path names it from the outermost
+// value, e.g. "streams[3].group.peers[1]", and is empty for the outermost
+// value itself. Msg is the underlying error's message and Err the error.
+// Snippet, set only with IncludeHexContext, shows the input around Offset.
+type DecodeError struct {
+	Offset  int
+	Path    string
+	Msg     string
+	Err     error
+	Snippet string
+}
+
+// Error implements the error interface
+func (e *DecodeError) Error() string {
+	at := "offset " + strconv.Itoa(e.Offset)
+	if e.Path != "" {
+		at = e.Path + ", " + at
+	}
+	if e.Snippet != "" {
+		at += ", bytes " + e.Snippet
+	}
+	return e.Msg + " (at " + at + ")"
+}
+
+// Unwrap returns the underlying error.
+func (e *DecodeError) Unwrap() error { return e.Err }
+
+// Resumable reports whether the underlying error is resumable.
+func (e *DecodeError) Resumable() bool { return Resumable(e.Err) }
+
+// WrapDecodeError is used by generated Safe decoders as an error unwinds
+// out of a value: it returns err as a *DecodeError whose Path is prefixed
+// with the key field (unless empty) and whose Offset is advanced by off,
+// the offset of the value within the decoder's input.
+func WrapDecodeError(err error, field string, off int) error {
+	e := asDecodeError(err)
+	e.Offset += off
+	e.Path = joinDecodePath(field, e.Path)
+	return e
+}
+
+// WrapDecodeIndex prefixes the path of err with the element index i, as
+// generated Safe decoders do for errors inside slices and arrays.
+func WrapDecodeIndex(err error, i int) error {
+	e := asDecodeError(err)
+	e.Path = joinDecodePath("["+strconv.Itoa(i)+"]", e.Path)
+	return e
+}
+
+// WrapDecodeKey prefixes the path of err with the map key, as generated
+// Safe decoders do for errors inside string-keyed maps.
+func WrapDecodeKey(err error, key string) error {
+	e := asDecodeError(err)
+	e.Path = joinDecodePath("["+strconv.Quote(key)+"]", e.Path)
+	return e
+}
+
+// hexContextBytes is how many bytes of input a DecodeError snippet shows
+// on either side of the failing offset.
+const hexContextBytes = 8
+
+// WithHexContext sets the Snippet of err, a *DecodeError for the item
+// decoded from b, to the hex of up to 8 bytes either side of its offset
+// with the byte at the offset in brackets, e.g. "a163616765[63]6f6c64".
+// An offset at the end of b shows as "[end]". It returns err unchanged
+// unless IncludeHexContext is set. Generated DecodeSafe methods call it
+// on their errors; a nested DecodeSafe sets a snippet that the enclosing
+// one replaces, so the outermost buffer has the last word.
+func WithHexContext(err error, b []byte) error {
+	e, ok := err.(*DecodeError)
+	if !ok || !IncludeHexContext || e.Offset < 0 || e.Offset > len(b) {
+		return err
+	}
+	before := hex.EncodeToString(b[max(e.Offset-hexContextBytes, 0):e.Offset])
+	if e.Offset == len(b) {
+		e.Snippet = before + "[end]"
+		return e
+	}
+	after := b[e.Offset+1 : min(e.Offset+1+hexContextBytes, len(b))]
+	e.Snippet = before + "[" + hex.EncodeToString(b[e.Offset:e.Offset+1]) + "]" + hex.EncodeToString(after)
+	return e
+}
+
+func asDecodeError(err error) *DecodeError {
+	if e, ok := err.(*DecodeError); ok {
+		return e
+	}
+	return &DecodeError{Msg: err.Error(), Err: err}
+}
+
+func joinDecodePath(seg, path string) string {
+	switch {
+	case seg == "":
+		return path
+	case path == "" || path[0] == '[':
+		return seg + path
+	}
+	return seg + "." + path
+}
+
 // ErrUnsupportedType is returned when a bad argument is supplied to
 // a function that accepts arbitrary values.
 type ErrUnsupportedType struct {
@@ -332,7 +520,11 @@ type ErrUnsupportedType struct {
 
 // Error implements error
 func (e *ErrUnsupportedType) Error() string {
-	out := "cbor: type " + quoteStr(e.T.String()) + " not supported"
+	name := "<nil>"
+	if e.T != nil {
+		name = e.T.String()
+	}
+	out := "cbor: type " + quoteStr(name) + " not supported"
 	if e.ctx != "" {
 		out += " at " + e.ctx
 	}
@@ -347,3 +539,10 @@ func (e *ErrUnsupportedType) withContext(ctx string) error {
 	o.ctx = addCtx(o.ctx, ctx)
 	return &o
 }
+
+// NotInstantiated returns the error the generated methods of a generic
+// type return for v, an instantiation of it without code generated by a
+// //cbor:instantiate directive.
+func NotInstantiated(v any) error {
+	return &ErrUnsupportedType{T: reflect.TypeOf(v)}
+}
@@ -0,0 +1,83 @@
+package cbor
+
+import "sort"
+
+// FlattenedLen returns the number of entries of m, the map[string]any
+// field of a struct tagged "flatten", that AppendFlattened writes: those
+// whose key field does not report as taken by one of the struct's own
+// fields.
+func FlattenedLen(m map[string]any, field func(key string) bool) uint32 {
+	n := uint32(0)
+	for k := range m {
+		if !field(k) {
+			n++
+		}
+	}
+	return n
+}
+
+// AppendFlattened appends the entries of m as key/value pairs of the
+// enclosing struct's map, leaving out keys for which field reports true:
+// a struct field always wins over an entry of the same key, so a value
+// round-trips the same way whichever of the two was set. Entries are
+// written in map order, or sorted per MapKeyOrder when
+// CanonicalMapEncode is set.
+func AppendFlattened(b []byte, m map[string]any, field func(key string) bool) ([]byte, error) {
+	var err error
+	if CanonicalMapEncode {
+		for _, k := range FlattenedKeys(m, field) {
+			b = AppendString(b, k)
+			if b, err = AppendInterface(b, m[k]); err != nil {
+				return b, err
+			}
+		}
+		return b, nil
+	}
+	for k, v := range m {
+		if field(k) {
+			continue
+		}
+		b = AppendString(b, k)
+		if b, err = AppendInterface(b, v); err != nil {
+			return b, err
+		}
+	}
+	return b, nil
+}
+
+// FlattenedKeys returns the keys of m that AppendFlattened writes, sorted
+// by their encoding per MapKeyOrder.
+func FlattenedKeys(m map[string]any, field func(key string) bool) []string {
+	type kv struct {
+		key string
+		enc []byte
+	}
+	arr := make([]kv, 0, len(m))
+	for k := range m {
+		if !field(k) {
+			arr = append(arr, kv{key: k, enc: AppendString(nil, k)})
+		}
+	}
+	sort.Slice(arr, func(i, j int) bool { return MapKeyOrder.Compare(arr[i].enc, arr[j].enc) < 0 })
+	keys := make([]string, len(arr))
+	for i, it := range arr {
+		keys[i] = it.key
+	}
+	return keys
+}
+
+// ReadFlattenedBytes decodes the value of key, a text key the struct has
+// no field for, as ReadInterfaceBytes does and stores it in m, the
+// struct's "flatten" field, which is allocated when nil. It returns m and
+// the bytes after the value. key must not alias b.
+func ReadFlattenedBytes(b []byte, key string, m map[string]any) (map[string]any, []byte, error) {
+	v, o, err := ReadInterfaceBytes(b)
+	if err != nil {
+		return m, b, err
+	}
+	if m == nil {
+		m = make(map[string]any)
+	}
+	m[key] = v
+	return m, o, nil
+}
@@ -1,6 +1,10 @@
 package cbor
 
-import "unicode/utf8"
+import (
+	"reflect"
+	"slices"
+	"unicode/utf8"
+)
 
 // getType returns the CBOR type from a byte
 func getType(b byte) Type {
@@ -44,14 +48,14 @@ func NextType(b []byte) Type {
 
 // Require ensures that b has capacity for at least n additional bytes
 // without reallocation. It returns a slice that shares the original
-// contents and has sufficient capacity for appending n bytes.
+// contents and has sufficient capacity for appending n bytes. Growth is
+// amortized like append, so many small items appended to one buffer (as
+// an Encoder does) do not each copy it.
 func Require(b []byte, n int) []byte {
 	if cap(b)-len(b) >= n {
 		return b
 	}
-	nb := make([]byte, len(b), len(b)+n)
-	copy(nb, b)
-	return nb
+	return slices.Grow(b, n)
 }
 
 // IsLikelyJSON reports whether the given byte slice looks like JSON text
@@ -98,3 +102,45 @@ func IsLikelyJSON(b []byte) bool {
 	}
 	return false
 }
+
+// zeroer is implemented by types that define their own zero value, such
+// as time.Time.
+type zeroer interface {
+	IsZero() bool
+}
+
+// IsZeroValue reports whether v is nil or the zero value of its type. A
+// v with an IsZero() bool method decides for itself. Generated code uses
+// it for omitzero fields whose type cannot be compared against a zero
+// literal.
+func IsZeroValue(v any) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	if z, ok := v.(zeroer); ok && (rv.Kind() != reflect.Pointer || !rv.IsNil()) {
+		return z.IsZero()
+	}
+	return rv.IsZero()
+}
+
+// IsZeroAt reports whether the value p points to is zero: by its
+// IsZero() bool method, on a value or pointer receiver, if it has one,
+// and otherwise by comparison with the zero value of its type. Generated
+// code uses it for omitzero fields whose type is declared in another
+// package.
+func IsZeroAt(p any) bool {
+	if z, ok := p.(zeroer); ok {
+		return z.IsZero()
+	}
+	return reflect.ValueOf(p).Elem().IsZero()
+}
+
+// IsZeroMethod reports whether the value p points to has an IsZero() bool
+// method, on a value or pointer receiver, that returns true. Generated
+// code uses it for omitempty fields whose type is declared in another
+// package, which are never empty without such a method.
+func IsZeroMethod(p any) bool {
+	z, ok := p.(zeroer)
+	return ok && z.IsZero()
+}
@@ -0,0 +1,256 @@
+package cbor
+
+import "reflect"
+
+// ReadInterfaceBytes decodes the next CBOR item into a generic Go value.
+//
+// The mapping is:
+//   - unsigned integers -> uint64, negative integers -> int64
+//   - byte strings -> []byte (copied), text strings -> string
+//   - arrays -> []any, maps with only text keys -> map[string]any
+//   - other maps -> map[any]any, keyed by the decoded keys (integers,
+//     floats, bools, nil, ...); byte string, array and map keys, which
+//     cannot key a Go map, fail with ErrUnsupportedType
+//   - half/single floats -> float32, double floats -> float64
+//   - true/false -> bool, null/undefined -> nil
+//   - other simple values -> SimpleValue
+//   - tags registered with RegisterType -> the registered concrete type
+//   - tag 0 and tag 1 -> time.Time
+//   - any other tag -> Raw holding the complete tagged item
+//
+// When UseNumber is set, integers, floats and bignums (tags 2 and 3)
+// are returned as Number instead.
+func ReadInterfaceBytes(b []byte) (v any, o []byte, err error) {
+	return readInterface(b, 0)
+}
+
+// ReadInterfaceAsBytes decodes the next CBOR item with ReadInterfaceBytes
+// and asserts the result to T. It is intended for generated code decoding
+// interface-typed fields; a CBOR null yields the zero value of T.
+func ReadInterfaceAsBytes[T any](b []byte) (v T, o []byte, err error) {
+	iv, o, err := ReadInterfaceBytes(b)
+	if err != nil {
+		return v, b, err
+	}
+	if iv == nil {
+		return v, o, nil
+	}
+	v, ok := iv.(T)
+	if !ok {
+		return v, b, &ErrUnsupportedType{T: reflect.TypeOf(iv)}
+	}
+	return v, o, nil
+}
+
+// ReadRegisteredAsBytes is ReadInterfaceAsBytes for closed sets of types
+// registered with RegisterType: an item wrapped in a tag that has not been
+// registered fails with UnexpectedTagError instead of decoding as Raw.
+// Generated Safe decoders use it for elements of interface slices.
+func ReadRegisteredAsBytes[T any](b []byte) (v T, o []byte, err error) {
+	if len(b) > 0 && getMajorType(b[0]) == majorTypeTag {
+		tag, _, err := ReadTagBytes(b)
+		if err != nil {
+			return v, b, err
+		}
+		if tag != tagDateTimeString && tag != tagEpochDateTime && lookupRegisteredTag(tag) == nil {
+			return v, b, UnexpectedTagError{Tag: tag}
+		}
+	}
+	return ReadInterfaceAsBytes[T](b)
+}
+
+func readInterface(b []byte, depth int) (any, []byte, error) {
+	if depth > recursionLimit {
+		return nil, b, ErrMaxDepthExceeded
+	}
+	if len(b) < 1 {
+		return nil, b, ErrShortBytes
+	}
+
+	if UseNumber && isNumber(b) {
+		var n Number
+		o, err := n.UnmarshalCBOR(b)
+		if err != nil {
+			return nil, b, err
+		}
+		return n, o, nil
+	}
+
+	switch getMajorType(b[0]) {
+	case majorTypeUint:
+		return ReadUint64Bytes(b)
+	case majorTypeNegInt:
+		return ReadInt64Bytes(b)
+	case majorTypeBytes:
+		bs, o, err := ReadBytesBytes(b, nil)
+		if err != nil {
+			return nil, b, err
+		}
+		return append([]byte{}, bs...), o, nil
+	case majorTypeText:
+		return ReadStringBytes(b)
+	case majorTypeArray:
+		sz, indefinite, o, err := ReadArrayStartBytes(b)
+		if err != nil {
+			return nil, b, err
+		}
+		out := make([]any, 0, min(sz, 1024))
+		for i := uint32(0); indefinite || i < sz; i++ {
+			if indefinite {
+				var done bool
+				o, done, err = ReadBreakBytes(o)
+				if err != nil {
+					return nil, b, err
+				}
+				if done {
+					break
+				}
+				if err = checkElements(uint64(i) + 1); err != nil {
+					return nil, b, err
+				}
+			}
+			var elem any
+			elem, o, err = readInterface(o, depth+1)
+			if err != nil {
+				return nil, b, err
+			}
+			out = append(out, elem)
+		}
+		return out, o, nil
+	case majorTypeMap:
+		sz, indefinite, o, err := ReadMapStartBytes(b)
+		if err != nil {
+			return nil, b, err
+		}
+		out := make(map[string]any, min(sz, 1024))
+		// outAny replaces out from the first key that is not text on.
+		var outAny map[any]any
+		for i := uint32(0); indefinite || i < sz; i++ {
+			if indefinite {
+				var done bool
+				o, done, err = ReadBreakBytes(o)
+				if err != nil {
+					return nil, b, err
+				}
+				if done {
+					break
+				}
+				if err = checkElements(uint64(i) + 1); err != nil {
+					return nil, b, err
+				}
+			}
+			var key, val any
+			key, o, err = readInterface(o, depth+1)
+			if err != nil {
+				return nil, b, err
+			}
+			if DuplicateMapKeys != DuplicateKeyLast && hasInterfaceKey(out, outAny, key) {
+				var skip bool
+				if o, skip, err = SkipDuplicate(o); err != nil {
+					return nil, b, err
+				}
+				if skip {
+					continue
+				}
+			}
+			val, o, err = readInterface(o, depth+1)
+			if err != nil {
+				return nil, b, err
+			}
+			if s, ok := key.(string); ok && outAny == nil {
+				out[s] = val
+				continue
+			}
+			if key != nil && !reflect.TypeOf(key).Comparable() {
+				// Byte strings, arrays and maps cannot key a Go map.
+				return nil, b, &ErrUnsupportedType{T: reflect.TypeOf(key)}
+			}
+			if outAny == nil {
+				outAny = make(map[any]any, len(out)+1)
+				for k, v := range out {
+					outAny[k] = v
+				}
+			}
+			outAny[key] = val
+		}
+		if outAny != nil {
+			return outAny, o, nil
+		}
+		return out, o, nil
+	case majorTypeTag:
+		tag, inner, err := ReadTagBytes(b)
+		if err != nil {
+			return nil, b, err
+		}
+		if rt := lookupRegisteredTag(tag); rt != nil {
+			v, o, err := rt.decode(inner)
+			if err != nil {
+				return nil, b, err
+			}
+			return v, o, nil
+		}
+		switch tag {
+		case tagDateTimeString:
+			return ReadRFC3339TimeBytes(b)
+		case tagEpochDateTime:
+			return ReadTimeBytes(b)
+		}
+		o, err := skip(inner, depth+1)
+		if err != nil {
+			return nil, b, err
+		}
+		raw := make(Raw, len(b)-len(o))
+		copy(raw, b)
+		return raw, o, nil
+	default:
+		switch getAddInfo(b[0]) {
+		case simpleFalse, simpleTrue:
+			return ReadBoolBytes(b)
+		case simpleNull, simpleUndefined:
+			return nil, b[1:], nil
+		case simpleFloat16:
+			return ReadFloat16Bytes(b)
+		case simpleFloat32:
+			return ReadFloat32Bytes(b)
+		case simpleFloat64:
+			return ReadFloat64Bytes(b)
+		}
+		var sv SimpleValue
+		o, err := sv.UnmarshalCBOR(b)
+		if err != nil {
+			return nil, b, err
+		}
+		return sv, o, nil
+	}
+}
+
+// hasInterfaceKey reports whether the map being built by readInterface,
+// out or once a key that is not text has been read outAny, holds key.
+func hasInterfaceKey(out map[string]any, outAny map[any]any, key any) bool {
+	if outAny != nil {
+		if key != nil && !reflect.TypeOf(key).Comparable() {
+			return false
+		}
+		_, ok := outAny[key]
+		return ok
+	}
+	s, isText := key.(string)
+	_, ok := out[s]
+	return isText && ok
+}
+
+// isNumber reports whether b starts with an item Number can hold.
+func isNumber(b []byte) bool {
+	switch getMajorType(b[0]) {
+	case majorTypeUint, majorTypeNegInt:
+		return true
+	case majorTypeTag:
+		return IsBignum(b)
+	case majorTypeSimple:
+		switch getAddInfo(b[0]) {
+		case simpleFloat16, simpleFloat32, simpleFloat64:
+			return true
+		}
+	}
+	return false
+}
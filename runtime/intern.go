@@ -0,0 +1,55 @@
+package cbor
+
+const (
+	// internMaxLen is the longest text string an Interner will retain;
+	// longer strings are rarely repeated and are allocated as usual.
+	internMaxLen = 64
+	// internMaxEntries bounds the number of distinct strings retained.
+	internMaxEntries = 4096
+)
+
+// Interner deduplicates decoded text strings: repeated byte sequences
+// yield the same shared string instead of a fresh allocation each time.
+// Only short strings are retained, and the table stops growing once it
+// holds a few thousand entries. A nil *Interner is valid and interns
+// nothing.
+//
+// An Interner is not safe for concurrent use; Decoder keeps one per
+// instance when SetInternStrings is enabled.
+type Interner struct {
+	m map[string]string
+}
+
+// NewInterner returns an empty Interner.
+func NewInterner() *Interner { return &Interner{m: make(map[string]string)} }
+
+// Len returns the number of distinct strings retained.
+func (in *Interner) Len() int {
+	if in == nil {
+		return 0
+	}
+	return len(in.m)
+}
+
+// ReadStringBytes behaves like the package-level ReadStringBytes but
+// returns a previously seen string when the same bytes recur.
+func (in *Interner) ReadStringBytes(b []byte) (s string, o []byte, err error) {
+	if in == nil || UnsafeStringDecode || len(b) < 1 || b[0] == makeByte(majorTypeText, addInfoIndefinite) {
+		return ReadStringBytes(b)
+	}
+	v, o, err := ReadStringZC(b)
+	if err != nil {
+		return "", b, err
+	}
+	if s, ok := in.m[string(v)]; ok {
+		return s, o, nil
+	}
+	if !textUTF8OK(v) {
+		return "", b, ErrInvalidUTF8
+	}
+	s = string(v)
+	if len(v) <= internMaxLen && len(in.m) < internMaxEntries {
+		in.m[s] = s
+	}
+	return s, o, nil
+}
@@ -0,0 +1,46 @@
+package cbor
+
+import "encoding/json"
+
+// AppendJSONFallback appends v to b for fields generated with
+// -allow-json-fallback, whose types have no CBOR codec the generator
+// knows of. A v implementing Marshaler is encoded by it; anything else is
+// passed through json.Marshal, and so its MarshalJSON method if it has
+// one, and the JSON converted with FromJSONBytes.
+//
+// This is a migration aid, not a codec: it is slow, and lossy wherever
+// JSON is (byte strings become base64 text, integers beyond float64
+// precision may round, and the item is whatever the JSON happens to be).
+func AppendJSONFallback(b []byte, v any) ([]byte, error) {
+	if m, ok := v.(Marshaler); ok {
+		return m.MarshalCBOR(b)
+	}
+	js, err := json.Marshal(v)
+	if err != nil {
+		return b, err
+	}
+	enc, err := FromJSONBytes(js)
+	if err != nil {
+		return b, err
+	}
+	return append(b, enc...), nil
+}
+
+// ReadJSONFallback decodes the next item of b into the value v points to
+// and returns the remaining bytes; it is the decoding counterpart of
+// AppendJSONFallback. A v implementing Unmarshaler decodes the item
+// itself; otherwise the item is converted with ToJSONBytes and passed to
+// json.Unmarshal, and so to an UnmarshalJSON method.
+func ReadJSONFallback(b []byte, v any) ([]byte, error) {
+	if u, ok := v.(Unmarshaler); ok {
+		return u.UnmarshalCBOR(b)
+	}
+	js, rest, err := ToJSONBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if err := json.Unmarshal(js, v); err != nil {
+		return b, err
+	}
+	return rest, nil
+}
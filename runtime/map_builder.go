@@ -0,0 +1,117 @@
+package cbor
+
+import "slices"
+
+// MapBuilder assembles a CBOR map whose entries are computed one at a
+// time, keeping count so the caller does not have to write the header
+// first. Entries are buffered, which also lets AppendSorted write them in
+// canonical key order. Reset empties a builder but keeps its buffers, so
+// a builder reused across messages stops allocating once it has grown.
+//
+//	var mb cbor.MapBuilder
+//	for _, s := range stats {
+//		if s.N == 0 {
+//			continue
+//		}
+//		mb.AddString(s.Name, func(b []byte) ([]byte, error) {
+//			return cbor.AppendUint64(b, s.N), nil
+//		})
+//	}
+//	b, err := mb.AppendSorted(b)
+//
+// The zero MapBuilder is empty and ready to use. It is not safe for
+// concurrent use.
+type MapBuilder struct {
+	buf     []byte
+	entries []mapEntry
+	order   []int
+}
+
+// mapEntry locates one entry in MapBuilder.buf: the key is buf[key:val]
+// and the value buf[val:end].
+type mapEntry struct {
+	key, val, end int
+}
+
+// Add appends an entry whose key is appended by key and whose value is
+// appended by value. Each must append exactly one item. If value fails,
+// nothing is added and its error is returned.
+func (mb *MapBuilder) Add(key func(b []byte) []byte, value func(b []byte) ([]byte, error)) error {
+	e := mapEntry{key: len(mb.buf)}
+	mb.buf = key(mb.buf)
+	return mb.addValue(e, value)
+}
+
+// AddString is Add with the text string key k.
+func (mb *MapBuilder) AddString(k string, value func(b []byte) ([]byte, error)) error {
+	e := mapEntry{key: len(mb.buf)}
+	mb.buf = AppendString(mb.buf, k)
+	return mb.addValue(e, value)
+}
+
+// AddInt is Add with the integer key k.
+func (mb *MapBuilder) AddInt(k int64, value func(b []byte) ([]byte, error)) error {
+	e := mapEntry{key: len(mb.buf)}
+	mb.buf = AppendInt64(mb.buf, k)
+	return mb.addValue(e, value)
+}
+
+// addValue appends the value of e, whose key has just been appended.
+func (mb *MapBuilder) addValue(e mapEntry, value func(b []byte) ([]byte, error)) error {
+	e.val = len(mb.buf)
+	buf, err := value(mb.buf)
+	if err != nil {
+		mb.buf = mb.buf[:e.key]
+		return err
+	}
+	mb.buf = buf
+	e.end = len(buf)
+	mb.entries = append(mb.entries, e)
+	return nil
+}
+
+// Len returns the number of entries added since the last Reset.
+func (mb *MapBuilder) Len() int { return len(mb.entries) }
+
+// Reset removes all entries, keeping the builder's buffers for reuse.
+func (mb *MapBuilder) Reset() {
+	mb.buf = mb.buf[:0]
+	mb.entries = mb.entries[:0]
+}
+
+// Append appends the map to b, its entries in the order they were added.
+func (mb *MapBuilder) Append(b []byte) []byte {
+	b = AppendMapHeader(b, uint32(len(mb.entries)))
+	return append(b, mb.buf...)
+}
+
+// AppendSorted appends the map to b with its entries sorted by encoded
+// key per MapKeyOrder, as deterministic encoding requires. Two entries
+// with the same key fail with ErrDuplicateMapKey and leave b unchanged.
+func (mb *MapBuilder) AppendSorted(b []byte) ([]byte, error) {
+	mb.order = mb.order[:0]
+	for i := range mb.entries {
+		mb.order = append(mb.order, i)
+	}
+	order := MapKeyOrder
+	slices.SortFunc(mb.order, func(i, j int) int {
+		return order.Compare(mb.key(i), mb.key(j))
+	})
+	for n := 1; n < len(mb.order); n++ {
+		if order.Compare(mb.key(mb.order[n-1]), mb.key(mb.order[n])) == 0 {
+			return b, ErrDuplicateMapKey
+		}
+	}
+	b = AppendMapHeader(b, uint32(len(mb.entries)))
+	for _, i := range mb.order {
+		e := mb.entries[i]
+		b = append(b, mb.buf[e.key:e.end]...)
+	}
+	return b, nil
+}
+
+// key returns the encoded key of entry i.
+func (mb *MapBuilder) key(i int) []byte {
+	e := mb.entries[i]
+	return mb.buf[e.key:e.val]
+}
@@ -0,0 +1,181 @@
+package cbor
+
+import (
+	"net"
+	"net/netip"
+	"strconv"
+)
+
+// IP addresses are written as byte strings holding the address in
+// network order: 4 bytes for IPv4 and 16 for IPv6. The Tagged variants
+// wrap them in the RFC 9164 tag for the address family, 52 for IPv4 and
+// 54 for IPv6. Readers accept either form, and null or undefined as the
+// zero value.
+//
+// net.IP keeps IPv4 addresses in either a 4 or a 16-byte form, so an
+// IPv4 net.IP is always written as 4 bytes. netip.Addr tells the two
+// apart: an IPv4-mapped IPv6 address such as ::ffff:192.0.2.1 is written
+// as 16 bytes and reads back mapped, so every netip.Addr round-trips
+// exactly; call Unmap first to write it as IPv4. The zone of an IPv6
+// netip.Addr is not encoded.
+
+// InvalidIPError is returned when a byte string read as an IP address is
+// not 4 or 16 bytes long, or does not match the length its tag requires.
+type InvalidIPError struct {
+	Len int
+	Tag uint64
+}
+
+// Error implements error
+func (e InvalidIPError) Error() string {
+	msg := "cbor: invalid IP address length " + strconv.Itoa(e.Len)
+	if e.Tag != 0 {
+		msg += " in tag " + strconv.FormatUint(e.Tag, 10)
+	}
+	return msg
+}
+
+// Resumable returns 'true' for InvalidIPError
+func (e InvalidIPError) Resumable() bool { return true }
+
+// AppendIP appends ip as a byte string, or null when ip is nil.
+func AppendIP(b []byte, ip net.IP) []byte {
+	if ip == nil {
+		return AppendNil(b)
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	return AppendBytes(b, ip)
+}
+
+// AppendIPTagged appends ip like AppendIP under tag 52 or 54.
+func AppendIPTagged(b []byte, ip net.IP) []byte {
+	if ip == nil {
+		return AppendNil(b)
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return AppendBytes(AppendTag(b, tagIPv4), ip4)
+	}
+	return AppendBytes(AppendTag(b, tagIPv6), ip)
+}
+
+// ReadIPBytes reads an IP address written by AppendIP or AppendIPTagged
+// into a newly allocated net.IP of 4 or 16 bytes. A null or undefined
+// yields a nil net.IP.
+func ReadIPBytes(b []byte) (ip net.IP, o []byte, err error) {
+	if IsNilOrUndefined(b) {
+		return nil, b[1:], nil
+	}
+	var buf [16]byte
+	n, o, err := readIP(b, &buf)
+	if err != nil {
+		return nil, b, err
+	}
+	return append(net.IP(nil), buf[:n]...), o, nil
+}
+
+// AppendAddr appends a as a byte string, or null when a is the zero
+// Addr.
+func AppendAddr(b []byte, a netip.Addr) []byte {
+	switch {
+	case a.Is4():
+		ip := a.As4()
+		return AppendBytes(b, ip[:])
+	case a.Is6():
+		ip := a.As16()
+		return AppendBytes(b, ip[:])
+	}
+	return AppendNil(b)
+}
+
+// AppendAddrTagged appends a like AppendAddr under tag 52 or 54.
+func AppendAddrTagged(b []byte, a netip.Addr) []byte {
+	switch {
+	case a.Is4():
+		b = AppendTag(b, tagIPv4)
+	case a.Is6():
+		b = AppendTag(b, tagIPv6)
+	}
+	return AppendAddr(b, a)
+}
+
+// ReadAddrBytes reads an IP address written by AppendAddr or
+// AppendAddrTagged. A null or undefined yields the zero Addr.
+func ReadAddrBytes(b []byte) (a netip.Addr, o []byte, err error) {
+	if IsNilOrUndefined(b) {
+		return netip.Addr{}, b[1:], nil
+	}
+	var buf [16]byte
+	n, o, err := readIP(b, &buf)
+	if err != nil {
+		return netip.Addr{}, b, err
+	}
+	if n == 4 {
+		return netip.AddrFrom4([4]byte(buf[:4])), o, nil
+	}
+	return netip.AddrFrom16(buf), o, nil
+}
+
+// AppendAddrPort appends ap as a two-element array of its address, as
+// written by AppendAddr, and its port, or null when ap is the zero
+// AddrPort.
+func AppendAddrPort(b []byte, ap netip.AddrPort) []byte {
+	if ap == (netip.AddrPort{}) {
+		return AppendNil(b)
+	}
+	b = AppendArrayHeader(b, 2)
+	b = AppendAddr(b, ap.Addr())
+	return AppendUint16(b, ap.Port())
+}
+
+// ReadAddrPortBytes reads an address and port written by AppendAddrPort.
+// A null or undefined yields the zero AddrPort.
+func ReadAddrPortBytes(b []byte) (ap netip.AddrPort, o []byte, err error) {
+	if IsNilOrUndefined(b) {
+		return netip.AddrPort{}, b[1:], nil
+	}
+	sz, o, err := ReadArrayHeaderBytes(b)
+	if err != nil {
+		return netip.AddrPort{}, b, err
+	}
+	if sz != 2 {
+		return netip.AddrPort{}, b, ArrayError{Wanted: 2, Got: sz}
+	}
+	a, o, err := ReadAddrBytes(o)
+	if err != nil {
+		return netip.AddrPort{}, b, err
+	}
+	port, o, err := ReadUint16Bytes(o)
+	if err != nil {
+		return netip.AddrPort{}, b, err
+	}
+	return netip.AddrPortFrom(a, port), o, nil
+}
+
+// readIP copies the address bytes of an optionally tagged IP address
+// into buf and returns how many there are, 4 or 16.
+func readIP(b []byte, buf *[16]byte) (n int, o []byte, err error) {
+	var tag uint64
+	o = b
+	if len(o) > 0 && getMajorType(o[0]) == majorTypeTag {
+		if tag, o, err = ReadTagBytes(o); err != nil {
+			return 0, b, err
+		}
+		if tag != tagIPv4 && tag != tagIPv6 {
+			return 0, b, UnexpectedTagError{Tag: tag}
+		}
+	}
+	bs, o, err := ReadBytesBytes(o, buf[:0])
+	if err != nil {
+		return 0, b, err
+	}
+	n = len(bs)
+	switch {
+	case n == 4 && tag != tagIPv6, n == 16 && tag != tagIPv4:
+	default:
+		return 0, b, InvalidIPError{Len: n, Tag: tag}
+	}
+	copy(buf[:], bs)
+	return n, o, nil
+}
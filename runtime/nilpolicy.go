@@ -0,0 +1,57 @@
+package cbor
+
+// NilPolicy selects how nil slices and maps are encoded, and what CBOR
+// null decodes to in a slice or map, so that both directions agree.
+type NilPolicy uint8
+
+const (
+	// NilAsEmpty (the default) writes a nil slice or map as an empty
+	// array, byte string or map, and decodes null or undefined into an
+	// empty, non-nil one.
+	NilAsEmpty NilPolicy = iota
+	// NilAsNull writes a nil slice or map as null and decodes null or
+	// undefined into nil, so nil and empty survive a round trip apart.
+	NilAsNull
+)
+
+// NilContainers is the NilPolicy of generated code, Marshal and
+// Unmarshal for slice and map values, fixed-size arrays excepted.
+var NilContainers = NilAsEmpty
+
+// String returns the name of the policy.
+func (p NilPolicy) String() string {
+	switch p {
+	case NilAsEmpty:
+		return "NilAsEmpty"
+	case NilAsNull:
+		return "NilAsNull"
+	}
+	return "NilPolicy(invalid)"
+}
+
+// NullSlice returns the value a slice holding s takes when null or
+// undefined is decoded into it: nil under NilAsNull, and otherwise s
+// truncated to length zero, or a new empty slice when s is nil.
+func NullSlice[S ~[]E, E any](s S) S {
+	if NilContainers == NilAsNull {
+		return nil
+	}
+	if s == nil {
+		return S{}
+	}
+	return s[:0]
+}
+
+// NullMap returns the value a map holding m takes when null or undefined
+// is decoded into it: nil under NilAsNull, and otherwise m cleared, or a
+// new empty map when m is nil.
+func NullMap[M ~map[K]V, K comparable, V any](m M) M {
+	if NilContainers == NilAsNull {
+		return nil
+	}
+	if m == nil {
+		return M{}
+	}
+	clear(m)
+	return m
+}
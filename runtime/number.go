@@ -2,20 +2,31 @@ package cbor
 
 import (
 	"math"
+	bigmath "math/big"
 	"math/bits"
 	"strconv"
 )
 
+// UseNumber makes ReadInterfaceBytes, and therefore generated decoders
+// filling interface-typed fields, return every integer, float and bignum
+// as a Number instead of uint64, int64, float32, float64 or Raw, so
+// values such as large negative integers decode without loss.
+var UseNumber = false
+
 // Number represents a CBOR number that may be an int64, uint64,
-// float32, or float64 internally. The zero value is equivalent to
+// float32, or float64 internally. Integers outside that range (large
+// negative integers and bignums) are held as a big.Int, so any CBOR
+// integer or float round-trips exactly. The zero value is equivalent to
 // an int64 value of 0.
 type Number struct {
 	bits uint64
 	typ  Type
+	big  *bigmath.Int
 }
 
 // AsInt sets the number to an int64.
 func (n *Number) AsInt(i int64) {
+	n.big = nil
 	if i == 0 {
 		n.typ = InvalidType
 		n.bits = 0
@@ -28,26 +39,44 @@ func (n *Number) AsInt(i int64) {
 
 // AsUint sets the number to a uint64.
 func (n *Number) AsUint(u uint64) {
+	n.big = nil
 	n.typ = UintType
 	n.bits = u
 }
 
 // AsFloat32 sets the value of the number to a float32.
 func (n *Number) AsFloat32(f float32) {
+	n.big = nil
 	n.typ = Float32Type
 	n.bits = uint64(math.Float32bits(f))
 }
 
 // AsFloat64 sets the value of the number to a float64.
 func (n *Number) AsFloat64(f float64) {
+	n.big = nil
 	n.typ = Float64Type
 	n.bits = math.Float64bits(f)
 }
 
+// AsBigInt sets the number to the integer z. Values that fit in an
+// int64 or uint64 are stored as such; larger ones keep a copy of z.
+func (n *Number) AsBigInt(z *bigmath.Int) {
+	switch {
+	case z.IsInt64():
+		n.AsInt(z.Int64())
+	case z.IsUint64():
+		n.AsUint(z.Uint64())
+	default:
+		n.typ = IntType
+		n.bits = 0
+		n.big = new(bigmath.Int).Set(z)
+	}
+}
+
 // Int returns the value as an int64 and reports whether that was the
 // underlying type (or the zero value).
 func (n *Number) Int() (int64, bool) {
-	return int64(n.bits), n.typ == IntType || n.typ == InvalidType
+	return int64(n.bits), n.big == nil && (n.typ == IntType || n.typ == InvalidType)
 }
 
 // Uint returns the value as a uint64 and reports whether that was the
@@ -56,6 +85,21 @@ func (n *Number) Uint() (uint64, bool) {
 	return n.bits, n.typ == UintType
 }
 
+// BigInt returns the value as a big.Int and reports whether the number
+// is an integer of any size.
+func (n *Number) BigInt() (*bigmath.Int, bool) {
+	switch {
+	case n.big != nil:
+		return new(bigmath.Int).Set(n.big), true
+	case n.typ == UintType:
+		return new(bigmath.Int).SetUint64(n.bits), true
+	case n.typ == IntType || n.typ == InvalidType:
+		return bigmath.NewInt(int64(n.bits)), true
+	default:
+		return nil, false
+	}
+}
+
 // Float returns the value as a float64 and reports whether the
 // underlying type was float32 or float64.
 func (n *Number) Float() (float64, bool) {
@@ -77,45 +121,81 @@ func (n *Number) Type() Type {
 	return n.typ
 }
 
-// UnmarshalCBOR decodes a single CBOR number from b into n.
+// UnmarshalCBOR decodes a single CBOR number from b into n. It accepts
+// integers of either sign, bignums (tags 2 and 3) and half, single and
+// double precision floats, keeping the value exactly as encoded.
 func (n *Number) UnmarshalCBOR(b []byte) ([]byte, error) {
-	typ := NextType(b)
-	switch typ {
-	case IntType:
-		i, o, err := ReadInt64Bytes(b)
-		if err != nil {
-			return b, err
-		}
-		n.AsInt(i)
-		return o, nil
-	case UintType:
+	if len(b) < 1 {
+		return b, ErrShortBytes
+	}
+	switch getMajorType(b[0]) {
+	case majorTypeUint:
 		u, o, err := ReadUint64Bytes(b)
 		if err != nil {
 			return b, err
 		}
 		n.AsUint(u)
 		return o, nil
-	case Float64Type:
-		f, o, err := ReadFloat64Bytes(b)
+	case majorTypeNegInt:
+		// The argument u encodes -1-u, which overflows int64 for u > MaxInt64.
+		u, o, err := readUintCore(b, majorTypeNegInt)
 		if err != nil {
 			return b, err
 		}
-		n.AsFloat64(f)
+		if u <= math.MaxInt64 {
+			n.AsInt(-1 - int64(u))
+			return o, nil
+		}
+		z := new(bigmath.Int).SetUint64(u)
+		n.AsBigInt(z.Neg(z).Sub(z, bigmath.NewInt(1)))
 		return o, nil
-	case Float32Type:
-		f, o, err := ReadFloat32Bytes(b)
+	case majorTypeTag:
+		z, o, err := ReadBigIntBytes(b)
 		if err != nil {
 			return b, err
 		}
-		n.AsFloat32(f)
+		n.AsBigInt(z)
 		return o, nil
-	default:
-		return b, &ErrUnsupportedType{}
+	case majorTypeSimple:
+		switch getAddInfo(b[0]) {
+		case simpleFloat16:
+			f, o, err := ReadFloat16Bytes(b)
+			if err != nil {
+				return b, err
+			}
+			n.AsFloat32(f)
+			return o, nil
+		case simpleFloat32:
+			f, o, err := ReadFloat32Bytes(b)
+			if err != nil {
+				return b, err
+			}
+			n.AsFloat32(f)
+			return o, nil
+		case simpleFloat64:
+			f, o, err := ReadFloat64Bytes(b)
+			if err != nil {
+				return b, err
+			}
+			n.AsFloat64(f)
+			return o, nil
+		}
 	}
+	return b, TypeError{Method: IntType, Encoded: getType(b[0])}
 }
 
 // MarshalCBOR encodes the stored numeric value into b.
 func (n *Number) MarshalCBOR(b []byte) ([]byte, error) {
+	if n.big != nil {
+		// Negative values down to -2^64 still fit major type 1.
+		if n.big.Sign() < 0 {
+			u := new(bigmath.Int).Neg(n.big)
+			if u.Sub(u, bigmath.NewInt(1)).IsUint64() {
+				return appendUintCore(b, majorTypeNegInt, u.Uint64()), nil
+			}
+		}
+		return AppendBigInt(b, n.big), nil
+	}
 	switch n.typ {
 	case IntType:
 		return AppendInt64(b, int64(n.bits)), nil
@@ -133,6 +213,9 @@ func (n *Number) MarshalCBOR(b []byte) ([]byte, error) {
 // CoerceInt attempts to coerce the value into an int64 without loss of
 // precision and reports success.
 func (n *Number) CoerceInt() (int64, bool) {
+	if n.big != nil {
+		return 0, false
+	}
 	switch n.typ {
 	case InvalidType, IntType:
 		return int64(n.bits), true
@@ -159,6 +242,9 @@ func (n *Number) CoerceInt() (int64, bool) {
 // CoerceUInt attempts to coerce the value into a uint64 without loss of
 // precision and reports success.
 func (n *Number) CoerceUInt() (uint64, bool) {
+	if n.big != nil {
+		return 0, false
+	}
 	switch n.typ {
 	case InvalidType, IntType:
 		if int64(n.bits) >= 0 {
@@ -219,6 +305,10 @@ func (n *Number) isExactInt() bool {
 
 // CoerceFloat returns the value as a float64.
 func (n *Number) CoerceFloat() float64 {
+	if n.big != nil {
+		f, _ := new(bigmath.Float).SetInt(n.big).Float64()
+		return f
+	}
 	switch n.typ {
 	case IntType:
 		return float64(int64(n.bits))
@@ -235,6 +325,9 @@ func (n *Number) CoerceFloat() float64 {
 
 // Msgsize returns the worst-case encoded size.
 func (n *Number) Msgsize() int {
+	if n.big != nil {
+		return 1 + BytesPrefixSize + len(n.big.Bytes())
+	}
 	switch n.typ {
 	case Float32Type:
 		return Float32Size
@@ -251,10 +344,16 @@ func (n *Number) Msgsize() int {
 
 // String implements fmt.Stringer-style formatting.
 func (n *Number) String() string {
+	if n.big != nil {
+		return n.big.String()
+	}
 	switch n.typ {
 	case InvalidType:
 		return "0"
-	case Float32Type, Float64Type:
+	case Float32Type:
+		f, _ := n.Float()
+		return strconv.FormatFloat(f, 'f', -1, 32)
+	case Float64Type:
 		f, _ := n.Float()
 		return strconv.FormatFloat(f, 'f', -1, 64)
 	case IntType:
@@ -0,0 +1,117 @@
+package cbor
+
+// AppendPairs appends pairs, the elements of a slice field tagged
+// "asmap", as a map with one entry per element: key appends the key of an
+// element and value its value. Entries are written in slice order, or
+// sorted per MapKeyOrder when CanonicalMapEncode is set, in which case two
+// elements with the same encoded key fail with ErrDuplicateMapKey.
+// Otherwise keeping keys distinct is up to the caller. A nil slice is
+// written as an empty map.
+func AppendPairs[E any](b []byte, pairs []E, key func(b []byte, e *E) []byte, value func(b []byte, e *E) ([]byte, error)) ([]byte, error) {
+	var err error
+	if !CanonicalMapEncode || len(pairs) < 2 {
+		b = AppendMapHeader(b, uint32(len(pairs)))
+		for i := range pairs {
+			b = key(b, &pairs[i])
+			if b, err = value(b, &pairs[i]); err != nil {
+				return b, err
+			}
+		}
+		return b, nil
+	}
+	// Encode every key into one scratch buffer; ends[i] is where key i
+	// stops.
+	var scratch []byte
+	ends := make([]int, len(pairs))
+	for i := range pairs {
+		scratch = key(scratch, &pairs[i])
+		ends[i] = len(scratch)
+	}
+	keyOf := func(i int) []byte {
+		if i == 0 {
+			return scratch[:ends[0]]
+		}
+		return scratch[ends[i-1]:ends[i]]
+	}
+	order := sortedKeyIndexes(len(pairs), keyOf)
+	for n := 1; n < len(order); n++ {
+		if MapKeyOrder.Compare(keyOf(order[n-1]), keyOf(order[n])) == 0 {
+			return b, ErrDuplicateMapKey
+		}
+	}
+	b = AppendMapHeader(b, uint32(len(pairs)))
+	for _, i := range order {
+		b = append(b, keyOf(i)...)
+		if b, err = value(b, &pairs[i]); err != nil {
+			return b, err
+		}
+	}
+	return b, nil
+}
+
+// ReadPairsBytes reads a map written by AppendPairs into pairs, which is
+// truncated first, and returns the slice with one element per entry in
+// the order of the map. key reads the key of an entry, set stores it in a
+// new element, and value reads the value into the element.
+//
+// With dedupe set, as in generated Safe decoders, a key read before is
+// handled per DuplicateMapKeys: DuplicateKeyError fails with
+// ErrDuplicateMapKey, DuplicateKeyFirst skips the entry, and
+// DuplicateKeyLast decodes its value into the element of the earlier
+// one. Without it every entry becomes an element.
+func ReadPairsBytes[E any, K comparable](b []byte, pairs []E, dedupe bool, key func(b []byte) (K, []byte, error), set func(e *E, k K), value func(b []byte, e *E) ([]byte, error)) ([]E, []byte, error) {
+	sz, indefinite, o, err := ReadMapStartBytes(b)
+	if err != nil {
+		return pairs, b, err
+	}
+	if pairs == nil {
+		pairs = make([]E, 0, min(int(sz), 1024))
+	}
+	pairs = pairs[:0]
+	var seen map[K]int
+	for i := 0; indefinite || i < int(sz); i++ {
+		if indefinite {
+			var done bool
+			if o, done, err = ReadBreakBytes(o); err != nil {
+				return pairs, b, err
+			}
+			if done {
+				break
+			}
+			if err = checkElements(uint64(i) + 1); err != nil {
+				return pairs, b, err
+			}
+		}
+		var k K
+		if k, o, err = key(o); err != nil {
+			return pairs, b, err
+		}
+		at := len(pairs)
+		if dedupe {
+			if seen == nil {
+				seen = make(map[K]int)
+			}
+			if j, ok := seen[k]; ok {
+				var skip bool
+				if o, skip, err = SkipDuplicate(o); err != nil {
+					return pairs, b, WrapDecodeIndex(err, j)
+				}
+				if skip {
+					continue
+				}
+				at = j
+			} else {
+				seen[k] = at
+			}
+		}
+		if at == len(pairs) {
+			var e E
+			pairs = append(pairs, e)
+			set(&pairs[at], k)
+		}
+		if o, err = value(o, &pairs[at]); err != nil {
+			return pairs, b, WrapDecodeIndex(err, at)
+		}
+	}
+	return pairs, o, nil
+}
@@ -0,0 +1,45 @@
+package cbor
+
+// MajorType is the major type of a CBOR item: the top three bits of its
+// initial byte (RFC 8949 section 3.1).
+type MajorType uint8
+
+// CBOR major types, as reported by Peek.
+const (
+	MajorUint   MajorType = majorTypeUint   // unsigned integer
+	MajorNegInt MajorType = majorTypeNegInt // negative integer
+	MajorBytes  MajorType = majorTypeBytes  // byte string
+	MajorText   MajorType = majorTypeText   // text string
+	MajorArray  MajorType = majorTypeArray  // array
+	MajorMap    MajorType = majorTypeMap    // map
+	MajorTag    MajorType = majorTypeTag    // tagged item
+	MajorSimple MajorType = majorTypeSimple // float, simple value or break
+)
+
+// String returns the number of the major type followed by its name, e.g.
+// "3 (text string)".
+func (m MajorType) String() string { return majorTypeName(uint8(m)) }
+
+// Peek returns the major type of the item at the start of b without
+// consuming it, so a caller can pick the decoder for it. The head is
+// checked as ReadHead does: a truncated head is ErrShortBytes, and a
+// reserved or indefinite-length head on an integer or tag is
+// ErrMalformedHead. The rest of the item is not examined.
+func Peek(b []byte) (MajorType, error) {
+	major, info, _, _, err := ReadHead(b)
+	if err != nil {
+		return 0, err
+	}
+	if info == addInfoIndefinite && (major == majorTypeUint || major == majorTypeNegInt || major == majorTypeTag) {
+		return 0, ErrMalformedHead
+	}
+	return MajorType(major), nil
+}
+
+// PeekTag returns the number of the tag at the start of b without
+// consuming it, for routing tagged envelopes before decoding them. An
+// item other than a tag is an InvalidPrefixError.
+func PeekTag(b []byte) (uint64, error) {
+	num, _, err := ReadTagBytes(b)
+	return num, err
+}
@@ -0,0 +1,50 @@
+package cbor
+
+import (
+	"math/bits"
+	"sync"
+)
+
+// Scratch buffers are pooled by power-of-two capacity class, from
+// minPooledBuffer up to maxPooledBuffer. Smaller requests are rounded up;
+// larger buffers are allocated directly and never retained.
+const (
+	minPooledBufferShift = 9  // 512 B
+	maxPooledBufferShift = 20 // 1 MiB
+	minPooledBuffer      = 1 << minPooledBufferShift
+	maxPooledBuffer      = 1 << maxPooledBufferShift
+)
+
+var bufferPools [maxPooledBufferShift - minPooledBufferShift + 1]sync.Pool
+
+// GetBuffer returns an empty scratch slice with capacity for at least
+// size bytes, reusing a pooled slice of the matching size class when
+// one is available. Return it with PutBuffer once its contents are no
+// longer referenced.
+func GetBuffer(size int) []byte {
+	if size > maxPooledBuffer {
+		return make([]byte, 0, size)
+	}
+	class := 0
+	if size > minPooledBuffer {
+		class = bits.Len(uint(size-1)) - minPooledBufferShift
+	}
+	if p, ok := bufferPools[class].Get().(*[]byte); ok {
+		return (*p)[:0]
+	}
+	return make([]byte, 0, minPooledBuffer<<class)
+}
+
+// PutBuffer returns b to the pool for reuse by GetBuffer. The caller
+// must not use b afterwards. Slices outside the pooled size range are
+// dropped.
+func PutBuffer(b []byte) {
+	c := cap(b)
+	if c < minPooledBuffer || c > maxPooledBuffer {
+		return
+	}
+	// File under the largest class b can fully serve.
+	class := bits.Len(uint(c)) - 1 - minPooledBufferShift
+	b = b[:0]
+	bufferPools[class].Put(&b)
+}
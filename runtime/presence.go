@@ -0,0 +1,41 @@
+package cbor
+
+import "math/bits"
+
+// ReadPresenceBytes reads the bitmap that opens the array of a struct
+// generated with the presence option. sz is the length of the array, as
+// returned by ReadArraySizeBytes, and fields the number of fields of the
+// struct. Bit i of the bitmap, the 1<<(i%8) bit of byte i/8, is set when
+// field i follows; the fields that follow are the set bits in order, so
+// the array must hold exactly one element per set bit after the bitmap.
+//
+// Bits at or past fields stand for fields of a newer schema. They fail
+// with ArrayError unless TolerateExtraArrayElements is set, in which case
+// the caller skips their elements.
+func ReadPresenceBytes(b []byte, sz uint32, fields int) (present []byte, o []byte, err error) {
+	if sz == 0 {
+		return nil, b, ArrayError{Wanted: 1, Got: 0}
+	}
+	present, o, err = ReadBytesBytes(b, nil)
+	if err != nil {
+		return nil, b, err
+	}
+	var set, known uint32
+	for i, c := range present {
+		n := uint32(bits.OnesCount8(c))
+		set += n
+		switch k := fields - i*8; {
+		case k >= 8:
+			known += n
+		case k > 0:
+			known += uint32(bits.OnesCount8(c & (1<<k - 1)))
+		}
+	}
+	if set+1 != sz {
+		return nil, b, ArrayError{Wanted: set + 1, Got: sz}
+	}
+	if known != set && !TolerateExtraArrayElements {
+		return nil, b, ArrayError{Wanted: known + 1, Got: sz}
+	}
+	return present, o, nil
+}
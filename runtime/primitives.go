@@ -0,0 +1,124 @@
+package cbor
+
+// Low-level encoding primitives.
+//
+// The Append* functions are the building blocks generated code uses, and
+// they are a stable API for hand-written MarshalCBOR methods that need to
+// interoperate with generated types. Every one of them appends to b
+// (growing it as needed) and returns the extended slice, and every one
+// writes the preferred serialization of RFC 8949 §4.2.1:
+//
+//   - integers, lengths, and tag numbers use the shortest head that holds
+//     the value (0..23 in the initial byte, then 1, 2, 4, or 8 bytes);
+//   - negative integers use major type 1 rather than a tagged bignum;
+//   - strings, byte strings, arrays, and maps are definite-length (the
+//     *Indefinite variants exist for streaming, but are not deterministic);
+//   - AppendFloat64/AppendFloat32 write the width of their argument except
+//     for NaN and ±Inf, which use the canonical half-precision forms, and
+//     AppendFloatCanonical picks the shortest exact width.
+//
+// Map entries are written in the order appended; use SortMapKeys or the
+// Append*Deterministic helpers when key order must be deterministic.
+
+// AppendTextString appends s as a definite-length text string. It is the
+// same as AppendString.
+func AppendTextString(b []byte, s string) []byte { return AppendString(b, s) }
+
+// AppendByteString appends data as a definite-length byte string. It is
+// the same as AppendBytes.
+func AppendByteString(b []byte, data []byte) []byte { return AppendBytes(b, data) }
+
+// AppendNull appends the null simple value (0xf6). It is the same as
+// AppendNil.
+func AppendNull(b []byte) []byte { return AppendNil(b) }
+
+// Low-level decoding primitives.
+//
+// The Read* functions below mirror the Append* ones for hand-written
+// UnmarshalCBOR methods. Each reads one item (or head) from the start of
+// b, bounds-checks every length against len(b), and returns the bytes
+// following it. On error the returned rest is b itself, so the failing
+// offset within a larger buffer is len(buf)-len(rest). Errors are typed:
+// ErrShortBytes for truncated input, InvalidPrefixError or TypeError for
+// an unexpected major type, and ErrMalformedHead for reserved heads.
+
+// ReadHead decodes the head of the next item: its major type (0..7), the
+// additional information (0..31) from the initial byte, and the argument.
+// For additional information below 24 the argument is the value itself;
+// for 24..27 it is the following 1, 2, 4, or 8 bytes (for major type 7
+// these are the raw float bits); for 31 (indefinite length or break) it
+// is zero. The reserved values 28..30 yield ErrMalformedHead.
+func ReadHead(b []byte) (major, info byte, arg uint64, rest []byte, err error) {
+	if len(b) < 1 {
+		return 0, 0, 0, b, ErrShortBytes
+	}
+	major, info = getMajorType(b[0]), getAddInfo(b[0])
+	switch {
+	case info <= addInfoDirect:
+		return major, info, uint64(info), b[1:], nil
+	case info == addInfoUint8:
+		if len(b) < 2 {
+			return 0, 0, 0, b, ErrShortBytes
+		}
+		return major, info, uint64(b[1]), b[2:], nil
+	case info == addInfoUint16:
+		if len(b) < 3 {
+			return 0, 0, 0, b, ErrShortBytes
+		}
+		return major, info, uint64(be.Uint16(b[1:])), b[3:], nil
+	case info == addInfoUint32:
+		if len(b) < 5 {
+			return 0, 0, 0, b, ErrShortBytes
+		}
+		return major, info, uint64(be.Uint32(b[1:])), b[5:], nil
+	case info == addInfoUint64:
+		if len(b) < 9 {
+			return 0, 0, 0, b, ErrShortBytes
+		}
+		return major, info, be.Uint64(b[1:]), b[9:], nil
+	case info == addInfoIndefinite:
+		return major, info, 0, b[1:], nil
+	default:
+		return 0, 0, 0, b, ErrMalformedHead
+	}
+}
+
+// ReadUint reads an unsigned integer (major type 0).
+func ReadUint(b []byte) (v uint64, rest []byte, err error) {
+	major, info, v, rest, err := ReadHead(b)
+	if err != nil {
+		return 0, b, err
+	}
+	if major != majorTypeUint {
+		return 0, b, badPrefix(majorTypeUint, major)
+	}
+	if info == addInfoIndefinite {
+		return 0, b, ErrMalformedHead
+	}
+	return v, rest, nil
+}
+
+// ReadTextString reads a text string, definite or indefinite length,
+// validating UTF-8 as ReadStringBytes does. It is the same as
+// ReadStringBytes.
+func ReadTextString(b []byte) (s string, rest []byte, err error) { return ReadStringBytes(b) }
+
+// ReadByteString reads a byte string. A definite-length result aliases b;
+// an indefinite-length one is assembled into a new slice.
+func ReadByteString(b []byte) (v []byte, rest []byte, err error) { return ReadBytesBytes(b, nil) }
+
+// ReadArrayHeader reads a definite-length array header and returns the
+// number of elements. It is the same as ReadArrayHeaderBytes.
+func ReadArrayHeader(b []byte) (n uint32, rest []byte, err error) { return ReadArrayHeaderBytes(b) }
+
+// ReadMapHeader reads a definite-length map header and returns the
+// number of key/value pairs. It is the same as ReadMapHeaderBytes.
+func ReadMapHeader(b []byte) (n uint32, rest []byte, err error) { return ReadMapHeaderBytes(b) }
+
+// ReadTag reads a tag head and returns the tag number; rest starts at the
+// tagged item. It is the same as ReadTagBytes.
+func ReadTag(b []byte) (num uint64, rest []byte, err error) { return ReadTagBytes(b) }
+
+// ReadFloat64 reads a half, single, or double precision float, widening
+// it exactly. It is the same as ReadFloat64Bytes.
+func ReadFloat64(b []byte) (f float64, rest []byte, err error) { return ReadFloat64Bytes(b) }
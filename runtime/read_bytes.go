@@ -3,9 +3,11 @@ package cbor
 import (
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"math"
 	bigmath "math/big"
 	"regexp"
+	"strconv"
 	"time"
 )
 
@@ -19,7 +21,7 @@ func readUintCore(b []byte, expectedMajor uint8) (uint64, []byte, error) {
 
 	major := getMajorType(b[0])
 	if major != expectedMajor {
-		return 0, b, badPrefix(major, expectedMajor)
+		return 0, b, badPrefix(expectedMajor, major)
 	}
 
 	addInfo := getAddInfo(b[0])
@@ -52,8 +54,17 @@ func readUintCore(b []byte, expectedMajor uint8) (uint64, []byte, error) {
 	}
 }
 
-// ReadMapHeaderBytes reads a map header
+// ReadMapHeaderBytes reads a map header. A map of more than MaxElements
+// entries is an ErrContainerTooLarge error.
 func ReadMapHeaderBytes(b []byte) (sz uint32, o []byte, err error) {
+	sz, o, err = readMapHeaderBytes(b)
+	if err == nil && uint64(sz) > uint64(MaxElements) {
+		return 0, b, tooManyElements(uint64(sz))
+	}
+	return sz, o, err
+}
+
+func readMapHeaderBytes(b []byte) (sz uint32, o []byte, err error) {
 	if len(b) < 1 {
 		return 0, b, ErrShortBytes
 	}
@@ -94,11 +105,20 @@ func ReadMapHeaderBytes(b []byte) (sz uint32, o []byte, err error) {
 	}
 
 	major := getMajorType(lead)
-	return 0, b, badPrefix(major, majorTypeMap)
+	return 0, b, badPrefix(majorTypeMap, major)
 }
 
-// ReadArrayHeaderBytes reads an array header
+// ReadArrayHeaderBytes reads an array header. An array of more than
+// MaxElements elements is an ErrContainerTooLarge error.
 func ReadArrayHeaderBytes(b []byte) (sz uint32, o []byte, err error) {
+	sz, o, err = readArrayHeaderBytes(b)
+	if err == nil && uint64(sz) > uint64(MaxElements) {
+		return 0, b, tooManyElements(uint64(sz))
+	}
+	return sz, o, err
+}
+
+func readArrayHeaderBytes(b []byte) (sz uint32, o []byte, err error) {
 	if len(b) < 1 {
 		return 0, b, ErrShortBytes
 	}
@@ -139,7 +159,7 @@ func ReadArrayHeaderBytes(b []byte) (sz uint32, o []byte, err error) {
 	}
 
 	major := getMajorType(lead)
-	return 0, b, badPrefix(major, majorTypeArray)
+	return 0, b, badPrefix(majorTypeArray, major)
 }
 
 // ReadMapStartBytes reads a map start and indicates whether it is indefinite-length.
@@ -168,6 +188,49 @@ func ReadArrayStartBytes(b []byte) (sz uint32, indefinite bool, rest []byte, err
 	return s, false, o, e
 }
 
+// ReadArraySizeBytes reads a definite or indefinite-length array header and
+// returns the number of elements. For an indefinite-length array the
+// elements are counted by skipping ahead to the break without consuming
+// them: rest points at the first element, and after reading sz elements the
+// caller must consume the one-byte break.
+func ReadArraySizeBytes(b []byte) (sz uint32, indefinite bool, rest []byte, err error) {
+	sz, indefinite, rest, err = ReadArrayStartBytes(b)
+	if err != nil || !indefinite {
+		return sz, indefinite, rest, err
+	}
+	o := rest
+	for {
+		var done bool
+		o, done, err = ReadBreakBytes(o)
+		if err != nil {
+			return 0, false, b, err
+		}
+		if done {
+			return sz, true, rest, nil
+		}
+		if o, err = Skip(o); err != nil {
+			return 0, false, b, err
+		}
+		sz++
+		if err = checkElements(uint64(sz)); err != nil {
+			return 0, false, b, err
+		}
+	}
+}
+
+// checkElements returns an ErrContainerTooLarge error once n, the
+// elements of an array or map read so far, exceeds MaxElements.
+func checkElements(n uint64) error {
+	if n > uint64(MaxElements) {
+		return tooManyElements(n)
+	}
+	return nil
+}
+
+func tooManyElements(n uint64) error {
+	return fmt.Errorf("%w: %d elements, MaxElements is %d", ErrContainerTooLarge, n, MaxElements)
+}
+
 // ReadBreakBytes checks whether the next byte is a break (0xff) and consumes it if so.
 func ReadBreakBytes(b []byte) (rest []byte, ok bool, err error) {
 	if len(b) < 1 {
@@ -190,30 +253,67 @@ func ReadNilBytes(b []byte) ([]byte, error) {
 	return b[1:], nil
 }
 
-// ReadFloat64Bytes reads a float64
+// ReadFloat64Bytes reads a float64. Half and single precision encodings,
+// which AppendFloat64 emits for NaN and ±Inf, are widened exactly.
 func ReadFloat64Bytes(b []byte) (f float64, o []byte, err error) {
-	if len(b) < 9 {
+	// Ultra-fast path: direct byte comparison (0xfb = float64)
+	if len(b) >= 9 && b[0] == 0xfb {
+		return math.Float64frombits(be.Uint64(b[1:])), b[9:], nil
+	}
+	if len(b) < 1 {
 		return 0, b, ErrShortBytes
 	}
-	// Ultra-fast path: direct byte comparison (0xfb = float64)
-	if b[0] != 0xfb {
-		return 0, b, badPrefix(getMajorType(b[0]), majorTypeSimple)
+	switch b[0] {
+	case 0xf9, 0xfa:
+		f32, o, err := ReadFloat32Bytes(b)
+		return float64(f32), o, err
+	case 0xfb:
+		return 0, b, ErrShortBytes
+	}
+	if CoerceNumbers {
+		if major := getMajorType(b[0]); major == majorTypeUint || major == majorTypeNegInt {
+			return readIntAsFloat64(b, major)
+		}
 	}
-	f = math.Float64frombits(be.Uint64(b[1:]))
-	return f, b[9:], nil
+	return 0, b, badPrefix(majorTypeSimple, getMajorType(b[0]))
 }
 
-// ReadFloat32Bytes reads a float32
+// ReadFloat32Bytes reads a float32. Half precision encodings, which
+// AppendFloat32 emits for NaN and ±Inf, are widened exactly. A double
+// precision float is accepted when float32 holds its value exactly (or
+// it is NaN); any other fails with FloatConversionError unless
+// NarrowFloats is set, in which case it is rounded to the nearest float32.
 func ReadFloat32Bytes(b []byte) (f float32, o []byte, err error) {
-	if len(b) < 5 {
+	// Ultra-fast path: direct byte comparison (0xfa = float32)
+	if len(b) >= 5 && b[0] == 0xfa {
+		return math.Float32frombits(be.Uint32(b[1:])), b[5:], nil
+	}
+	if len(b) < 1 {
 		return 0, b, ErrShortBytes
 	}
-	// Ultra-fast path: direct byte comparison (0xfa = float32)
-	if b[0] != 0xfa {
-		return 0, b, badPrefix(getMajorType(b[0]), majorTypeSimple)
+	switch b[0] {
+	case 0xf9:
+		return ReadFloat16Bytes(b)
+	case 0xfa:
+		return 0, b, ErrShortBytes
+	case 0xfb:
+		if len(b) < 9 {
+			return 0, b, ErrShortBytes
+		}
+		f64 := math.Float64frombits(be.Uint64(b[1:]))
+		f = float32(f64)
+		if !NarrowFloats && float64(f) != f64 && !math.IsNaN(f64) {
+			return 0, b, FloatConversionError{Value: f64, Type: "float32"}
+		}
+		return f, b[9:], nil
 	}
-	f = math.Float32frombits(be.Uint32(b[1:]))
-	return f, b[5:], nil
+	if CoerceNumbers {
+		if major := getMajorType(b[0]); major == majorTypeUint || major == majorTypeNegInt {
+			f, o, err := readIntAsFloat64(b, major)
+			return float32(f), o, err
+		}
+	}
+	return 0, b, badPrefix(majorTypeSimple, getMajorType(b[0]))
 }
 
 // ReadFloat16Bytes reads a float16 (IEEE 754 binary16) and returns float32
@@ -222,7 +322,7 @@ func ReadFloat16Bytes(b []byte) (f float32, o []byte, err error) {
 		return 0, b, ErrShortBytes
 	}
 	if b[0] != 0xF9 {
-		return 0, b, badPrefix(getMajorType(b[0]), majorTypeSimple)
+		return 0, b, badPrefix(majorTypeSimple, getMajorType(b[0]))
 	}
 	h := binary.BigEndian.Uint16(b[1:])
 	f = float16BitsToFloat32(h)
@@ -244,7 +344,33 @@ func ReadBoolBytes(b []byte) (bool, []byte, error) {
 	return false, b, TypeError{Method: BoolType, Encoded: getType(b[0])}
 }
 
-// ReadInt64Bytes reads an int64
+// ReadIntKeyBytes reads an integer map key for a generated keyasint
+// decoder. Keys outside the int64 range (at least 2^63, or below -2^63)
+// cannot name a field, so they are consumed with fits false rather than
+// failing, letting the decoder skip them like any unknown key.
+func ReadIntKeyBytes(b []byte) (key int64, fits bool, o []byte, err error) {
+	if len(b) < 1 {
+		return 0, false, b, ErrShortBytes
+	}
+	major := getMajorType(b[0])
+	if major != majorTypeUint && major != majorTypeNegInt {
+		return 0, false, b, badPrefix(majorTypeUint, major)
+	}
+	u, o, err := readUintCore(b, major)
+	if err != nil {
+		return 0, false, b, err
+	}
+	if u > math.MaxInt64 {
+		return 0, false, o, nil
+	}
+	if major == majorTypeNegInt {
+		return -1 - int64(u), true, o, nil
+	}
+	return int64(u), true, o, nil
+}
+
+// ReadInt64Bytes reads an int64. A bignum (tag 2 or 3) is accepted when
+// its value fits, and returns a BignumOverflow otherwise.
 func ReadInt64Bytes(b []byte) (i int64, o []byte, err error) {
 	if len(b) < 1 {
 		return 0, b, ErrShortBytes
@@ -322,7 +448,13 @@ func ReadInt64Bytes(b []byte) (i int64, o []byte, err error) {
 
 	// Invalid major type for integer
 	major := (lead >> 5) & 0x07
-	return 0, b, badPrefix(major, majorTypeUint)
+	if major == majorTypeTag {
+		return readBignumAsInt64(b)
+	}
+	if CoerceNumbers && major == majorTypeSimple {
+		return readFloatAsInt64(b)
+	}
+	return 0, b, badPrefix(majorTypeUint, major)
 }
 
 // ReadInt32Bytes reads an int32
@@ -361,23 +493,38 @@ func ReadInt8Bytes(b []byte) (i int8, o []byte, err error) {
 	return int8(i64), o, nil
 }
 
-// ReadIntBytes reads an int
+// ReadIntBytes reads an int. An int is written as the 64-bit integer it
+// holds whatever the platform, so on one where int has 32 bits a value
+// outside its range fails with IntOverflow instead of being truncated.
 func ReadIntBytes(b []byte) (i int, o []byte, err error) {
 	i64, o, err := ReadInt64Bytes(b)
 	if err != nil {
 		return 0, b, err
 	}
+	if i64 > math.MaxInt || i64 < math.MinInt {
+		return 0, b, IntOverflow{Value: i64, FailedBitsize: strconv.IntSize}
+	}
 	return int(i64), o, nil
 }
 
-// ReadUint64Bytes reads a uint64
+// ReadUint64Bytes reads a uint64. A positive bignum (tag 2) is accepted
+// when its value fits, and returns a BignumOverflow otherwise.
 func ReadUint64Bytes(b []byte) (u uint64, o []byte, err error) {
-	return readUintCore(b, majorTypeUint)
+	u, o, err = readUintCore(b, majorTypeUint)
+	if err != nil && len(b) > 0 {
+		switch major := getMajorType(b[0]); {
+		case major == majorTypeTag:
+			return readBignumAsUint64(b)
+		case CoerceNumbers && major == majorTypeSimple:
+			return readFloatAsUint64(b)
+		}
+	}
+	return u, o, err
 }
 
 // ReadUint32Bytes reads a uint32
 func ReadUint32Bytes(b []byte) (u uint32, o []byte, err error) {
-	u64, o, err := readUintCore(b, majorTypeUint)
+	u64, o, err := ReadUint64Bytes(b)
 	if err != nil {
 		return 0, b, err
 	}
@@ -389,7 +536,7 @@ func ReadUint32Bytes(b []byte) (u uint32, o []byte, err error) {
 
 // ReadUint16Bytes reads a uint16
 func ReadUint16Bytes(b []byte) (u uint16, o []byte, err error) {
-	u64, o, err := readUintCore(b, majorTypeUint)
+	u64, o, err := ReadUint64Bytes(b)
 	if err != nil {
 		return 0, b, err
 	}
@@ -401,7 +548,7 @@ func ReadUint16Bytes(b []byte) (u uint16, o []byte, err error) {
 
 // ReadUint8Bytes reads a uint8
 func ReadUint8Bytes(b []byte) (u uint8, o []byte, err error) {
-	u64, o, err := readUintCore(b, majorTypeUint)
+	u64, o, err := ReadUint64Bytes(b)
 	if err != nil {
 		return 0, b, err
 	}
@@ -411,12 +558,17 @@ func ReadUint8Bytes(b []byte) (u uint8, o []byte, err error) {
 	return uint8(u64), o, nil
 }
 
-// ReadUintBytes reads a uint
+// ReadUintBytes reads a uint. As with ReadIntBytes, a value too large
+// for a 32-bit uint fails with UintOverflow on platforms where uint has
+// 32 bits.
 func ReadUintBytes(b []byte) (u uint, o []byte, err error) {
-	u64, o, err := readUintCore(b, majorTypeUint)
+	u64, o, err := ReadUint64Bytes(b)
 	if err != nil {
 		return 0, b, err
 	}
+	if u64 > math.MaxUint {
+		return 0, b, UintOverflow{Value: u64, FailedBitsize: strconv.IntSize}
+	}
 	return uint(u64), o, nil
 }
 
@@ -514,6 +666,9 @@ func ReadBytesBytes(b []byte, scratch []byte) (v []byte, o []byte, err error) {
 		}
 		return b[9 : 9+sz], b[9+sz:], nil
 	default:
+		if LenientByteStrings && getMajorType(lead) == majorTypeText {
+			return readTextAsBytes(b, scratch)
+		}
 		sz, o, err := readUintCore(b, majorTypeBytes)
 		if err != nil {
 			return nil, b, err
@@ -581,7 +736,10 @@ func ReadStringZC(b []byte) (v []byte, o []byte, err error) {
 	default:
 		// Invalid major type
 		major := getMajorType(lead)
-		return nil, b, badPrefix(major, majorTypeText)
+		if LenientByteStrings && major == majorTypeBytes {
+			return ReadBytesBytes(b, nil)
+		}
+		return nil, b, badPrefix(majorTypeText, major)
 	}
 
 	// Guard against integer overflow and out-of-bounds slicing.
@@ -596,6 +754,30 @@ func ReadStringZC(b []byte) (v []byte, o []byte, err error) {
 	return b[start:end], b[end:], nil
 }
 
+// readTextAsBytes reads a text string, definite or indefinite, as raw
+// bytes for ReadBytesBytes under LenientByteStrings.
+func readTextAsBytes(b []byte, scratch []byte) (v []byte, o []byte, err error) {
+	if b[0] != makeByte(majorTypeText, addInfoIndefinite) {
+		return ReadStringZC(b)
+	}
+	out := scratch[:0]
+	p := b[1:]
+	for {
+		if len(p) < 1 {
+			return nil, b, ErrShortBytes
+		}
+		if p[0] == makeByte(majorTypeSimple, simpleBreak) {
+			return out, p[1:], nil
+		}
+		chunk, q, e := ReadStringZC(p)
+		if e != nil {
+			return nil, b, e
+		}
+		out = append(out, chunk...)
+		p = q
+	}
+}
+
 // ReadStringBytes reads a text string
 func ReadStringBytes(b []byte) (s string, o []byte, err error) {
 	if len(b) < 1 {
@@ -610,7 +792,7 @@ func ReadStringBytes(b []byte) (s string, o []byte, err error) {
 				return "", b, ErrShortBytes
 			}
 			if p[0] == makeByte(majorTypeSimple, simpleBreak) {
-				if ValidateUTF8OnDecode && !isUTF8Valid(out) {
+				if !textUTF8OK(out) {
 					return "", b, ErrInvalidUTF8
 				}
 				return string(out), p[1:], nil
@@ -627,7 +809,7 @@ func ReadStringBytes(b []byte) (s string, o []byte, err error) {
 	if err != nil {
 		return "", b, err
 	}
-	if ValidateUTF8OnDecode && !isUTF8Valid(v) {
+	if !textUTF8OK(v) {
 		return "", b, ErrInvalidUTF8
 	}
 	if UnsafeStringDecode {
@@ -636,6 +818,29 @@ func ReadStringBytes(b []byte) (s string, o []byte, err error) {
 	return string(v), o, nil
 }
 
+// ReadTrustedStringBytes reads a text string for generated DecodeTrusted
+// methods. It never validates UTF-8, and while ZeroCopyStrings is set the
+// result aliases b; see ZeroCopyStrings for the hazards.
+func ReadTrustedStringBytes(b []byte) (s string, o []byte, err error) {
+	if len(b) > 0 && b[0] == makeByte(majorTypeText, addInfoIndefinite) {
+		// The chunks are concatenated into a new buffer; it is not
+		// shared with b, so it is always safe to alias.
+		v, o, err := readTextAsBytes(b, nil)
+		if err != nil {
+			return "", b, err
+		}
+		return UnsafeString(v), o, nil
+	}
+	v, o, err := ReadStringZC(b)
+	if err != nil {
+		return "", b, err
+	}
+	if ZeroCopyStrings {
+		return UnsafeString(v), o, nil
+	}
+	return string(v), o, nil
+}
+
 // ReadMapKeyZC reads a map key expecting a text string and returns its bytes zero-copy.
 // It is a thin wrapper around ReadStringZC for generated code compatibility.
 func ReadMapKeyZC(b []byte) (v []byte, o []byte, err error) {
@@ -652,46 +857,51 @@ func ReadMapKeyZC(b []byte) (v []byte, o []byte, err error) {
 
 // ReadSimpleValue reads a simple value and returns its numeric value.
 // Returns values 0..23 (including false/true/null/undefined) directly,
-// or 32..255 following a 0xf8 prefix. Float encodings are not handled here.
+// or 32..255 following a 0xf8 prefix. Floats, breaks and the reserved
+// values 24..31 fail with ErrInvalidSimpleValue.
 func ReadSimpleValue(b []byte) (val uint8, o []byte, err error) {
 	if len(b) < 1 {
 		return 0, b, ErrShortBytes
 	}
 	major := getMajorType(b[0])
 	if major != majorTypeSimple {
-		return 0, b, badPrefix(major, majorTypeSimple)
+		return 0, b, badPrefix(majorTypeSimple, major)
 	}
 	addInfo := getAddInfo(b[0])
 	switch addInfo {
-	case simpleFloat16, simpleFloat32, simpleFloat64:
-		return 0, b, &ErrUnsupportedType{}
 	case addInfoUint8: // 0xf8 XX
 		if len(b) < 2 {
 			return 0, b, ErrShortBytes
 		}
+		// RFC 8949 §3.3: values below 32 must use the one-byte form.
+		if b[1] < 32 {
+			return 0, b, ErrInvalidSimpleValue
+		}
 		return b[1], b[2:], nil
 	default:
 		if addInfo <= addInfoDirect {
 			return addInfo, b[1:], nil
 		}
-		return 0, b, &ErrUnsupportedType{}
+		return 0, b, ErrInvalidSimpleValue
 	}
 }
 
-// ReadTimeBytes reads a time.Time (CBOR tag 1 with Unix timestamp)
+// ReadTimeBytes reads a time.Time (CBOR tag 1 with Unix timestamp). The
+// tag carries no offset, so the result is in time.Local, or in time.UTC
+// with TimeUTC set.
 func ReadTimeBytes(b []byte) (t time.Time, o []byte, err error) {
 	if len(b) < 2 {
 		return time.Time{}, b, ErrShortBytes
 	}
 	if getMajorType(b[0]) != majorTypeTag {
-		return time.Time{}, b, badPrefix(getMajorType(b[0]), majorTypeTag)
+		return time.Time{}, b, badPrefix(majorTypeTag, getMajorType(b[0]))
 	}
 	tag, o, err := readUintCore(b, majorTypeTag)
 	if err != nil {
 		return time.Time{}, b, err
 	}
 	if tag != tagEpochDateTime {
-		return time.Time{}, b, errors.New("cbor: expected epoch datetime tag")
+		return time.Time{}, b, UnexpectedTagError{Tag: tag}
 	}
 	if len(o) < 1 {
 		return time.Time{}, b, ErrShortBytes
@@ -702,57 +912,84 @@ func ReadTimeBytes(b []byte) (t time.Time, o []byte, err error) {
 		if e != nil {
 			return time.Time{}, b, e
 		}
-		return time.Unix(sec, 0), o2, nil
+		return inTimeZone(time.Unix(sec, 0)), o2, nil
 	case majorTypeSimple:
-		add := getAddInfo(o[0])
-		switch add {
+		var f float64
+		var o2 []byte
+		var e error
+		switch getAddInfo(o[0]) {
 		case simpleFloat64:
-			f, o2, e := ReadFloat64Bytes(o)
-			if e != nil {
-				return time.Time{}, b, e
-			}
-			sec := math.Floor(f)
-			ns := int64(math.Round((f - sec) * 1e9))
-			secs := int64(sec)
-			if ns >= 1e9 {
-				secs++
-				ns -= 1e9
-			}
-			return time.Unix(secs, ns), o2, nil
+			f, o2, e = ReadFloat64Bytes(o)
 		case simpleFloat32:
-			f, o2, e := ReadFloat32Bytes(o)
-			if e != nil {
-				return time.Time{}, b, e
-			}
-			sec := math.Floor(float64(f))
-			ns := int64(math.Round((float64(f) - sec) * 1e9))
-			secs := int64(sec)
-			if ns >= 1e9 {
-				secs++
-				ns -= 1e9
-			}
-			return time.Unix(secs, ns), o2, nil
+			var f32 float32
+			f32, o2, e = ReadFloat32Bytes(o)
+			f = float64(f32)
 		case simpleFloat16:
-			f, o2, e := ReadFloat16Bytes(o)
-			if e != nil {
-				return time.Time{}, b, e
-			}
-			sec := math.Floor(float64(f))
-			ns := int64(math.Round((float64(f) - sec) * 1e9))
-			secs := int64(sec)
-			if ns >= 1e9 {
-				secs++
-				ns -= 1e9
-			}
-			return time.Unix(secs, ns), o2, nil
+			var f32 float32
+			f32, o2, e = ReadFloat16Bytes(o)
+			f = float64(f32)
 		default:
 			return time.Time{}, b, &ErrUnsupportedType{}
 		}
+		if e != nil {
+			return time.Time{}, b, e
+		}
+		return inTimeZone(epochFloatTime(f)), o2, nil
 	default:
 		return time.Time{}, b, &ErrUnsupportedType{}
 	}
 }
 
+// epochFloatTime converts f, seconds since the Unix epoch, to a time.Time.
+// The fraction is rounded to the nearest nanosecond, so a time written by
+// AppendTimeFloat comes back as the nearest time float64 can tell apart
+// from it and re-encodes to the same float.
+func epochFloatTime(f float64) time.Time {
+	sec := math.Floor(f)
+	ns := int64(math.Round((f - sec) * 1e9))
+	secs := int64(sec)
+	if ns >= 1e9 {
+		secs++
+		ns -= 1e9
+	}
+	return time.Unix(secs, ns)
+}
+
+// IsTagged reports whether b starts with a tag (major type 6).
+func IsTagged(b []byte) bool {
+	return len(b) > 0 && getMajorType(b[0]) == majorTypeTag
+}
+
+// IsBignum reports whether b starts with a bignum (tag 2 or 3), which
+// the integer readers accept in place of a plain integer.
+func IsBignum(b []byte) bool {
+	if !IsTagged(b) {
+		return false
+	}
+	tag, _, err := ReadTagBytes(b)
+	return err == nil && (tag == tagPosBignum || tag == tagNegBignum)
+}
+
+// UntagBytes prepares the value of a plain (tag-less) field for decoding.
+// If b does not start with a tag it is returned unchanged. Otherwise,
+// with SkipUnknownTags set, every tag wrapping the item is skipped and
+// the content returned; without it, UnexpectedTagError is returned.
+// Generated decoders call it only when IsTagged reports a tag.
+func UntagBytes(b []byte) ([]byte, error) {
+	o := b
+	for IsTagged(o) {
+		tag, rest, err := ReadTagBytes(o)
+		if err != nil {
+			return b, err
+		}
+		if !SkipUnknownTags {
+			return b, UnexpectedTagError{Tag: tag}
+		}
+		o = rest
+	}
+	return o, nil
+}
+
 // ReadTagBytes reads a semantic tag value (major type 6)
 func ReadTagBytes(b []byte) (tag uint64, o []byte, err error) {
 	tag, o, err = readUintCore(b, majorTypeTag)
@@ -762,14 +999,17 @@ func ReadTagBytes(b []byte) (tag uint64, o []byte, err error) {
 	return tag, o, nil
 }
 
-// ReadRFC3339TimeBytes reads a tag(0) RFC3339 time string into time.Time
+// ReadRFC3339TimeBytes reads a tag(0) RFC3339 time string into time.Time.
+// The result has the offset written in the text, in a zone with no name
+// (or time.Local when its offset matches, as time.Parse does), unless
+// TimeUTC is set.
 func ReadRFC3339TimeBytes(b []byte) (t time.Time, o []byte, err error) {
 	tag, o, err := ReadTagBytes(b)
 	if err != nil {
 		return time.Time{}, b, err
 	}
 	if tag != tagDateTimeString {
-		return time.Time{}, b, badPrefix(majorTypeTag, majorTypeTag)
+		return time.Time{}, b, UnexpectedTagError{Tag: tag}
 	}
 	s, o2, err := ReadStringBytes(o)
 	if err != nil {
@@ -779,9 +1019,35 @@ func ReadRFC3339TimeBytes(b []byte) (t time.Time, o []byte, err error) {
 	if perr != nil {
 		return time.Time{}, b, perr
 	}
+	if TimeUTC {
+		tt = tt.UTC()
+	}
 	return tt, o2, nil
 }
 
+// ReadAnyTimeBytes reads a time written as either tag 0 (RFC 3339 text)
+// or tag 1 (epoch seconds), the forms ReadRFC3339TimeBytes and
+// ReadTimeBytes read.
+func ReadAnyTimeBytes(b []byte) (t time.Time, o []byte, err error) {
+	tag, _, err := ReadTagBytes(b)
+	if err != nil {
+		return time.Time{}, b, err
+	}
+	if tag == tagDateTimeString {
+		return ReadRFC3339TimeBytes(b)
+	}
+	return ReadTimeBytes(b)
+}
+
+// inTimeZone returns t, a time decoded from tag 1, in time.Local or, with
+// TimeUTC set, in time.UTC.
+func inTimeZone(t time.Time) time.Time {
+	if TimeUTC {
+		return t.UTC()
+	}
+	return t
+}
+
 // ReadBase64URLStringBytes reads tag(33) base64url text string
 func ReadBase64URLStringBytes(b []byte) (s string, o []byte, err error) {
 	tag, o, err := ReadTagBytes(b)
@@ -789,7 +1055,7 @@ func ReadBase64URLStringBytes(b []byte) (s string, o []byte, err error) {
 		return "", b, err
 	}
 	if tag != tagBase64URLString {
-		return "", b, badPrefix(majorTypeTag, majorTypeTag)
+		return "", b, UnexpectedTagError{Tag: tag}
 	}
 	return ReadStringBytes(o)
 }
@@ -801,7 +1067,7 @@ func ReadBase64StringBytes(b []byte) (s string, o []byte, err error) {
 		return "", b, err
 	}
 	if tag != tagBase64String {
-		return "", b, badPrefix(majorTypeTag, majorTypeTag)
+		return "", b, UnexpectedTagError{Tag: tag}
 	}
 	return ReadStringBytes(o)
 }
@@ -813,7 +1079,7 @@ func ReadURIStringBytes(b []byte) (uri string, o []byte, err error) {
 		return "", b, err
 	}
 	if tag != tagURI {
-		return "", b, badPrefix(majorTypeTag, majorTypeTag)
+		return "", b, UnexpectedTagError{Tag: tag}
 	}
 	return ReadStringBytes(o)
 }
@@ -825,7 +1091,7 @@ func ReadEmbeddedCBORBytes(b []byte) (payload []byte, o []byte, err error) {
 		return nil, b, err
 	}
 	if tag != tagCBOR {
-		return nil, b, badPrefix(majorTypeTag, majorTypeTag)
+		return nil, b, UnexpectedTagError{Tag: tag}
 	}
 	return ReadBytesBytes(o, nil)
 }
@@ -837,7 +1103,7 @@ func ReadBase64URLBytes(b []byte) (bs []byte, o []byte, err error) {
 		return nil, b, err
 	}
 	if tag != tagBase64URL {
-		return nil, b, badPrefix(majorTypeTag, majorTypeTag)
+		return nil, b, UnexpectedTagError{Tag: tag}
 	}
 	return ReadBytesBytes(o, nil)
 }
@@ -849,7 +1115,7 @@ func ReadBase64Bytes(b []byte) (bs []byte, o []byte, err error) {
 		return nil, b, err
 	}
 	if tag != tagBase64 {
-		return nil, b, badPrefix(majorTypeTag, majorTypeTag)
+		return nil, b, UnexpectedTagError{Tag: tag}
 	}
 	return ReadBytesBytes(o, nil)
 }
@@ -861,7 +1127,7 @@ func ReadBase16Bytes(b []byte) (bs []byte, o []byte, err error) {
 		return nil, b, err
 	}
 	if tag != tagBase16 {
-		return nil, b, badPrefix(majorTypeTag, majorTypeTag)
+		return nil, b, UnexpectedTagError{Tag: tag}
 	}
 	return ReadBytesBytes(o, nil)
 }
@@ -873,7 +1139,7 @@ func ReadUUIDBytes(b []byte) (uuid [16]byte, o []byte, err error) {
 		return uuid, b, err
 	}
 	if tag != 37 {
-		return uuid, b, badPrefix(majorTypeTag, majorTypeTag)
+		return uuid, b, UnexpectedTagError{Tag: tag}
 	}
 	bs, o2, err := ReadBytesBytes(o, nil)
 	if err != nil {
@@ -893,7 +1159,7 @@ func ReadRegexpStringBytes(b []byte) (pattern string, o []byte, err error) {
 		return "", b, err
 	}
 	if tag != tagRegexp {
-		return "", b, badPrefix(majorTypeTag, majorTypeTag)
+		return "", b, UnexpectedTagError{Tag: tag}
 	}
 	return ReadStringBytes(o)
 }
@@ -905,7 +1171,7 @@ func ReadMIMEStringBytes(b []byte) (mime string, o []byte, err error) {
 		return "", b, err
 	}
 	if tag != tagMIME {
-		return "", b, badPrefix(majorTypeTag, majorTypeTag)
+		return "", b, UnexpectedTagError{Tag: tag}
 	}
 	return ReadStringBytes(o)
 }
@@ -960,7 +1226,7 @@ func ReadBigIntBytes(b []byte) (z *bigmath.Int, o []byte, err error) {
 		mag.Neg(mag)
 		return mag, o2, nil
 	default:
-		return nil, b, badPrefix(majorTypeTag, majorTypeTag)
+		return nil, b, UnexpectedTagError{Tag: tag}
 	}
 }
 
@@ -1333,6 +1599,20 @@ func ReadDurationBytes(b []byte) (d time.Duration, o []byte, err error) {
 	return time.Duration(i64), o, nil
 }
 
+// ReadDurationUnitBytes reads a time.Duration written as an integer count
+// of unit by AppendDurationUnit. Counts that do not fit a time.Duration
+// return IntOverflow.
+func ReadDurationUnitBytes(b []byte, unit time.Duration) (d time.Duration, o []byte, err error) {
+	i64, o, err := ReadInt64Bytes(b)
+	if err != nil {
+		return 0, b, err
+	}
+	if i64 > math.MaxInt64/int64(unit) || i64 < math.MinInt64/int64(unit) {
+		return 0, b, IntOverflow{Value: i64, FailedBitsize: 64}
+	}
+	return time.Duration(i64) * unit, o, nil
+}
+
 // ReadMapStrStrBytes reads a map[string]string
 func ReadMapStrStrBytes(b []byte, m map[string]string) (o []byte, err error) {
 	sz, o, err := ReadMapHeaderBytes(b)
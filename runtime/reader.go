@@ -8,7 +8,7 @@ import (
 
 // Reader provides a minimal slice-based CBOR reader. It is intended
 // for use by generated DecodeMsg implementations and operates on
-// an in-memory buffer.
+// an in-memory buffer. A Reader is not safe for concurrent use.
 type Reader struct {
 	buf           []byte
 	strict        bool
@@ -326,7 +326,7 @@ func isNonCanonicalLength(b []byte, expectedMajor uint8) (bool, error) {
 		return false, ErrShortBytes
 	}
 	if getMajorType(b[0]) != expectedMajor {
-		return false, badPrefix(getMajorType(b[0]), expectedMajor)
+		return false, badPrefix(expectedMajor, getMajorType(b[0]))
 	}
 	add := getAddInfo(b[0])
 	switch add {
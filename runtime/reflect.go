@@ -0,0 +1,1359 @@
+package cbor
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Marshal returns the CBOR encoding of v. Values implementing Marshaler,
+// which includes every type cborgen generated methods for, are encoded by
+// their MarshalCBOR method; anything else falls back to reflection.
+//
+// The reflection encoder follows the rules of generated code: struct
+// fields are named by their cbor tag, then their json tag, then their Go
+// name, and honor the omitempty, omitzero, keyasint, inline, toarray,
+// dense, presence, flatten, boolasint, asmap and tag=N options. Embedded structs
+// without a key in their tag are inlined, and while an embedded pointer is
+// nil its fields are left out. Unexported fields are skipped, and two
+// fields sharing a key, inlined fields and aliases included, are an error.
+// time.Time is written in the form DefaultTimeFormat selects, []byte and [N]byte as byte
+// strings, net.IP, netip.Addr and netip.AddrPort as AppendIP, AppendAddr
+// and AppendAddrPort write them, and values of registered types held in
+// interfaces in their CBOR tag. Map keys are sorted when CanonicalMapEncode is set.
+//
+// Reflection is much slower than generated code; it is meant for ad-hoc
+// types and prototyping.
+func Marshal(v any) ([]byte, error) {
+	if m, ok := v.(Marshaler); ok {
+		return m.MarshalCBOR(nil)
+	}
+	return appendReflect(nil, reflect.ValueOf(v), 0)
+}
+
+// Unmarshal decodes the single CBOR item in b into the value pointed to
+// by v. When v implements Unmarshaler, as pointers to generated types do,
+// its UnmarshalCBOR method decodes b directly: past the type assertion
+// this costs the same as calling the method yourself, and generated
+// types decode on their Safe path. Unmarshaler types met further down,
+// such as a generated struct in a field of a plain one, are decoded by
+// their method too; anything else is filled in by
+// reflection using the same field rules as Marshal, also accepting the
+// names of alias=name options as keys. Null sets the target to its zero
+// value, and tags on values other than time.Time and IP addresses are
+// ignored.
+//
+// Errors locating a failure are returned as *DecodeError. Bytes left over
+// after the item yield ErrTrailingBytes unless AllowTrailingBytes is set.
+func Unmarshal(b []byte, v any) error {
+	if u, ok := v.(Unmarshaler); ok {
+		o, err := u.UnmarshalCBOR(b)
+		if err != nil {
+			return WithHexContext(WrapDecodeError(err, "", 0), b)
+		}
+		return CheckTrailingBytes(b, o)
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return &ErrUnsupportedType{T: reflect.TypeOf(v)}
+	}
+	o, err := decodeReflect(b, rv.Elem(), 0)
+	if err != nil {
+		return WithHexContext(WrapDecodeError(err, "", 0), b)
+	}
+	return CheckTrailingBytes(b, o)
+}
+
+// DecodeOne decodes the CBOR item at the start of buf into the value
+// pointed to by v, as Unmarshal does, and returns the bytes after it. It
+// consumes exactly one item: the item's extent is found first and v is
+// decoded from those bytes alone, capped so not even an append reaches
+// past them. rest is never read or written and may hold anything, such as
+// the non-CBOR framing a message is embedded in. On error rest is buf.
+func DecodeOne(buf []byte, v any) (rest []byte, err error) {
+	o, err := Skip(buf)
+	if err != nil {
+		return buf, WrapDecodeError(err, "", 0)
+	}
+	n := len(buf) - len(o)
+	if err := Unmarshal(buf[:n:n], v); err != nil {
+		return buf, err
+	}
+	return o, nil
+}
+
+// DecodeValue decodes the CBOR item at the start of b into v, which must
+// be settable, such as an element of a slice or the Elem of a pointer,
+// and returns the bytes after it. It is the reflect.Value counterpart of
+// Unmarshal for code that only holds types at run time, and follows the
+// same rules: a value whose pointer implements Unmarshaler, as generated
+// types do, is decoded by its method, and anything else by reflection.
+// The package settings that govern Unmarshal, such as NilContainers and
+// registered codecs, apply here too. Errors are returned as *DecodeError.
+func DecodeValue(b []byte, v reflect.Value) ([]byte, error) {
+	if !v.CanSet() {
+		var t reflect.Type
+		if v.IsValid() {
+			t = v.Type()
+		}
+		return b, &ErrUnsupportedType{T: t}
+	}
+	o, err := decodeReflect(b, v, 0)
+	if err != nil {
+		return b, WithHexContext(WrapDecodeError(err, "", 0), b)
+	}
+	return o, nil
+}
+
+// CheckTrailingBytes returns the error a one-shot decoder reports when
+// decoding the item at the start of b left rest over: nil if rest is
+// empty or AllowTrailingBytes is set, else a *DecodeError wrapping
+// ErrTrailingBytes at the offset of rest.
+func CheckTrailingBytes(b, rest []byte) error {
+	if len(rest) == 0 || AllowTrailingBytes {
+		return nil
+	}
+	return WithHexContext(WrapDecodeError(ErrTrailingBytes, "", len(b)-len(rest)), b)
+}
+
+var (
+	marshalerType   = reflect.TypeFor[Marshaler]()
+	unmarshalerType = reflect.TypeFor[Unmarshaler]()
+	zeroerType      = reflect.TypeFor[zeroer]()
+	timeType        = reflect.TypeFor[time.Time]()
+	ipType          = reflect.TypeFor[net.IP]()
+	addrType        = reflect.TypeFor[netip.Addr]()
+	addrPortType    = reflect.TypeFor[netip.AddrPort]()
+	stringSetType   = reflect.TypeFor[map[string]struct{}]()
+	mapStrAnyType   = reflect.TypeFor[map[string]any]()
+)
+
+// reflectField is an encoded field of a struct, as resolved from its tag.
+type reflectField struct {
+	index     []int
+	goName    string // the Go field, "Inner.A" for one inlined from Inner
+	name      string
+	aliases   []string // further names decoded into the field
+	intKey    int64
+	keyAsInt  bool
+	omitEmpty bool
+	omitZero  bool
+	tag       uint64
+	hasTag    bool
+	asString  bool // a number or bool written as text (",string")
+	boolAsInt bool // a bool written as 0 or 1 (",boolasint")
+	asSet     bool // a map[string]struct{} written as an array of keys (",set")
+	asMap     bool // a slice of two-field structs written as a map (",asmap")
+	timeFloat bool // a tag=1 time.Time written as float seconds (",float")
+}
+
+// reflectStruct caches the encoded fields of a struct type.
+type reflectStruct struct {
+	fields  []reflectField
+	toArray bool
+	dense   bool
+	// presence prefixes toarray structs with a bitmap of the fields
+	// written, as ReadPresenceBytes reads.
+	presence bool
+	// flatten is the index of the map[string]any field tagged
+	// "flatten", whose entries share the struct's map; nil if none.
+	flatten []int
+	// viaPointer reports whether a field is reached through an embedded
+	// pointer, which may be nil.
+	viaPointer bool
+	byName     map[string]int
+	byInt      map[int64]int
+	err        error
+}
+
+var reflectStructs sync.Map // reflect.Type -> *reflectStruct
+
+func structFields(t reflect.Type) (*reflectStruct, error) {
+	if rs, ok := reflectStructs.Load(t); ok {
+		return rs.(*reflectStruct), rs.(*reflectStruct).err
+	}
+	rs := &reflectStruct{byName: map[string]int{}, byInt: map[int64]int{}}
+	rs.err = rs.collect(t, nil, "", false, map[reflect.Type]bool{t: true})
+	if rs.err == nil {
+		rs.err = rs.checkKeys(t)
+	}
+	if rs.err == nil && rs.dense {
+		rs.err = rs.orderDense(t)
+	}
+	if rs.err == nil && rs.presence && !rs.toArray {
+		rs.err = fmt.Errorf("cbor: %s: presence requires toarray or dense", t)
+	}
+	if rs.err == nil && rs.flatten != nil && rs.toArray {
+		rs.err = fmt.Errorf("cbor: %s: flatten requires a struct encoded as a map", t)
+	}
+	if rs.err == nil && rs.viaPointer && rs.toArray {
+		rs.err = fmt.Errorf("cbor: %s: a field inlined through a pointer requires a struct encoded as a map", t)
+	}
+	for i, f := range rs.fields {
+		if f.keyAsInt {
+			rs.byInt[f.intKey] = i
+		} else {
+			rs.byName[f.name] = i
+		}
+	}
+	for i, f := range rs.fields {
+		for _, a := range f.aliases {
+			if _, taken := rs.byName[a]; !taken {
+				rs.byName[a] = i
+			}
+		}
+	}
+	actual, _ := reflectStructs.LoadOrStore(t, rs)
+	return actual.(*reflectStruct), actual.(*reflectStruct).err
+}
+
+func (rs *reflectStruct) collect(t reflect.Type, index []int, prefix string, viaPointer bool, inlining map[reflect.Type]bool) error {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		// Like generated code, keyasint, inline, toarray and tag=N are
+		// only read from cbor tags.
+		tag, isCBOR := f.Tag.Lookup("cbor")
+		if !isCBOR {
+			tag = f.Tag.Get("json")
+		}
+		if f.Name == "_" && isCBOR && len(index) == 0 {
+			rs.toArray = rs.toArray || hasTagOption(tag, "toarray")
+			rs.dense = rs.dense || hasTagOption(tag, "dense")
+			rs.presence = rs.presence || hasTagOption(tag, "presence")
+			continue
+		}
+		if !f.IsExported() || tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		inner, isPtr := f.Type, false
+		if inner.Kind() == reflect.Pointer {
+			inner, isPtr = inner.Elem(), true
+		}
+		if f.Anonymous && inner.Kind() != reflect.Struct {
+			continue
+		}
+		idx := append(append([]int(nil), index...), i)
+		if f.Anonymous && name == "" || isCBOR && hasTagOption(tag, "inline") {
+			if inner.Kind() != reflect.Struct || inlining[inner] {
+				return fmt.Errorf("cbor: %s.%s: inline requires a non-recursive struct type", t, f.Name)
+			}
+			inlining[inner] = true
+			if err := rs.collect(inner, idx, prefix+f.Name+".", viaPointer || isPtr, inlining); err != nil {
+				return err
+			}
+			delete(inlining, inner)
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		rs.viaPointer = rs.viaPointer || viaPointer
+		if isCBOR && hasTagOption(tag, "flatten") {
+			if f.Type != mapStrAnyType || rs.flatten != nil {
+				return fmt.Errorf("cbor: %s.%s: flatten requires a single map[string]any field", t, f.Name)
+			}
+			if viaPointer {
+				return fmt.Errorf("cbor: %s.%s: flatten cannot be reached through an embedded pointer", t, f.Name)
+			}
+			rs.flatten = idx
+			continue
+		}
+		rf := reflectField{
+			index:     idx,
+			goName:    prefix + f.Name,
+			name:      name,
+			omitEmpty: hasTagOption(tag, "omitempty"),
+			omitZero:  hasTagOption(tag, "omitzero"),
+		}
+		if isCBOR && hasTagOption(tag, "keyasint") {
+			n, err := strconv.ParseInt(name, 10, 64)
+			if err != nil {
+				return fmt.Errorf("cbor: %s.%s: keyasint name %q is not an integer", t, f.Name, name)
+			}
+			rf.keyAsInt, rf.intKey = true, n
+		}
+		if isCBOR {
+			rf.aliases = tagOptionValues(tag, "alias")
+			rf.asString = hasTagOption(tag, "string") && stringKind(f.Type.Kind())
+			rf.boolAsInt = hasTagOption(tag, "boolasint") && f.Type.Kind() == reflect.Bool
+			rf.asSet = hasTagOption(tag, "set") && f.Type.ConvertibleTo(stringSetType)
+			rf.asMap = hasTagOption(tag, "asmap") && f.Type.Kind() == reflect.Slice && f.Type.Elem().Kind() == reflect.Struct
+		}
+		if v, ok := tagOptionValue(tag, "tag"); ok && isCBOR {
+			n, err := strconv.ParseUint(v, 10, 64)
+			if err != nil {
+				return fmt.Errorf("cbor: %s.%s: invalid tag number %q", t, f.Name, v)
+			}
+			rf.hasTag, rf.tag = true, n
+			rf.timeFloat = hasTagOption(tag, "float")
+		}
+		rs.fields = append(rs.fields, rf)
+	}
+	return nil
+}
+
+// checkKeys fails, as cborgen does, if two fields, inlined ones and
+// aliases included, share a key: the map written would repeat it.
+func (rs *reflectStruct) checkKeys(t reflect.Type) error {
+	seen := make(map[string]string, len(rs.fields))
+	use := func(key, goName string) error {
+		if prev, ok := seen[key]; ok {
+			return fmt.Errorf("cbor: %s: fields %s and %s both use CBOR key %s", t, prev, goName, key)
+		}
+		seen[key] = goName
+		return nil
+	}
+	for _, f := range rs.fields {
+		key := strconv.Quote(f.name)
+		if f.keyAsInt {
+			key = strconv.FormatInt(f.intKey, 10)
+		}
+		if err := use(key, f.goName); err != nil {
+			return err
+		}
+		for _, a := range f.aliases {
+			if err := use(strconv.Quote(a), f.goName); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// hasField reports whether key, a text key, names one of the struct's
+// fields or their aliases, which take precedence over flattened entries.
+func (rs *reflectStruct) hasField(key string) bool {
+	_, ok := rs.byName[key]
+	return ok
+}
+
+// orderDense sorts the fields of a struct marked `cbor:",dense"` by their
+// keyasint keys, which must be 0..N-1, and encodes it as an array.
+func (rs *reflectStruct) orderDense(t reflect.Type) error {
+	fields := make([]reflectField, len(rs.fields))
+	for _, f := range rs.fields {
+		if !f.keyAsInt || f.intKey < 0 || f.intKey >= int64(len(fields)) || fields[f.intKey].index != nil {
+			return fmt.Errorf("cbor: %s: dense requires keyasint keys 0..%d", t, len(fields)-1)
+		}
+		fields[f.intKey] = f
+	}
+	rs.fields, rs.toArray = fields, true
+	return nil
+}
+
+// hasTagOption reports whether the comma-separated options following
+// the name in tag include opt.
+func hasTagOption(tag, opt string) bool {
+	_, opts, _ := strings.Cut(tag, ",")
+	for opts != "" {
+		var o string
+		o, opts, _ = strings.Cut(opts, ",")
+		if o == opt {
+			return true
+		}
+	}
+	return false
+}
+
+// tagOptionValue returns the value of a "key=value" option following the
+// name in tag.
+func tagOptionValue(tag, key string) (string, bool) {
+	_, opts, _ := strings.Cut(tag, ",")
+	for opts != "" {
+		var o string
+		o, opts, _ = strings.Cut(opts, ",")
+		if v, ok := strings.CutPrefix(o, key+"="); ok {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// tagOptionValues returns the values of every "key=value" option
+// following the name in tag.
+func tagOptionValues(tag, key string) []string {
+	var vals []string
+	_, opts, _ := strings.Cut(tag, ",")
+	for opts != "" {
+		var o string
+		o, opts, _ = strings.Cut(opts, ",")
+		if v, ok := strings.CutPrefix(o, key+"="); ok && v != "" {
+			vals = append(vals, v)
+		}
+	}
+	return vals
+}
+
+// stringKind reports whether the ",string" option applies to kind k.
+func stringKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Bool, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return false
+}
+
+// appendReflectString appends the number or bool v as text, as generated
+// code writes ",string" fields.
+func appendReflectString(b []byte, v reflect.Value) []byte {
+	switch v.Kind() {
+	case reflect.Bool:
+		return AppendBoolString(b, v.Bool())
+	case reflect.Float32, reflect.Float64:
+		return AppendFloatString(b, v.Float(), v.Type().Bits())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return AppendIntString(b, v.Int())
+	}
+	return AppendUintString(b, v.Uint())
+}
+
+// pairFields returns the key and value fields of the elements of t, the
+// slice type of a field tagged "asmap": the two encoded fields of a
+// struct, the first a string, bool or number.
+func pairFields(t reflect.Type) (key, value reflectField, err error) {
+	rs, err := structFields(t.Elem())
+	if err != nil {
+		return key, value, err
+	}
+	if len(rs.fields) != 2 || rs.viaPointer {
+		return key, value, fmt.Errorf("cbor: asmap requires a slice of structs with two fields, not %s", t)
+	}
+	key, value = rs.fields[0], rs.fields[1]
+	if k := t.Elem().FieldByIndex(key.index).Type.Kind(); k != reflect.String && !stringKind(k) {
+		return key, value, fmt.Errorf("cbor: asmap requires a string, bool or number key in %s, not %s", t.Elem(), k)
+	}
+	return key, value, nil
+}
+
+// appendReflectPairs appends slice v, of a field tagged "asmap", as
+// AppendPairs does.
+func appendReflectPairs(b []byte, v reflect.Value, depth int) ([]byte, error) {
+	kf, vf, err := pairFields(v.Type())
+	if err != nil {
+		return b, err
+	}
+	idx := make([]int, v.Len())
+	for i := range idx {
+		idx[i] = i
+	}
+	return AppendPairs(b, idx, func(b []byte, i *int) []byte {
+		b, _ = appendReflect(b, v.Index(*i).FieldByIndex(kf.index), depth)
+		return b
+	}, func(b []byte, i *int) ([]byte, error) {
+		return appendReflect(b, v.Index(*i).FieldByIndex(vf.index), depth)
+	})
+}
+
+// decodeReflectPairs decodes a map into slice v, of a field tagged
+// "asmap", as ReadPairsBytes does with dedupe set.
+func decodeReflectPairs(b []byte, v reflect.Value, depth int) ([]byte, error) {
+	kf, vf, err := pairFields(v.Type())
+	if err != nil {
+		return b, err
+	}
+	sz, indefinite, o, err := ReadMapStartBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if v.IsNil() {
+		v.Set(reflect.MakeSlice(v.Type(), 0, min(int(sz), 1024)))
+	} else {
+		v.SetLen(0)
+	}
+	keyType := v.Type().Elem().FieldByIndex(kf.index).Type
+	var seen map[any]int
+	for i := 0; indefinite || i < int(sz); i++ {
+		if indefinite {
+			var done bool
+			if o, done, err = ReadBreakBytes(o); err != nil {
+				return b, err
+			}
+			if done {
+				break
+			}
+			if err = checkElements(uint64(i) + 1); err != nil {
+				return b, err
+			}
+		}
+		k := reflect.New(keyType).Elem()
+		if o, err = decodeReflect(o, k, depth+1); err != nil {
+			return b, err
+		}
+		at := v.Len()
+		if seen == nil {
+			seen = make(map[any]int)
+		}
+		if j, ok := seen[k.Interface()]; ok {
+			var skip bool
+			if o, skip, err = SkipDuplicate(o); err != nil {
+				return b, WrapDecodeIndex(err, j)
+			}
+			if skip {
+				continue
+			}
+			at = j
+		} else {
+			seen[k.Interface()] = at
+		}
+		if at == v.Len() {
+			v.Set(reflect.Append(v, reflect.Zero(v.Type().Elem())))
+			v.Index(at).FieldByIndex(kf.index).Set(k)
+		}
+		if o, err = vf.decode(o, v.Index(at).FieldByIndex(vf.index), depth+1); err != nil {
+			return b, WrapDecodeIndex(err, at)
+		}
+	}
+	return o, nil
+}
+
+// decode decodes the value of field f into v, parsing the text of a
+// ",string" field and the 0 or 1 of a ",boolasint" one, and the map of
+// an ",asmap" one.
+func (f *reflectField) decode(b []byte, v reflect.Value, depth int) ([]byte, error) {
+	if f.asMap && !IsNilOrUndefined(b) {
+		return decodeReflectPairs(b, v, depth)
+	}
+	if f.asSet && !IsNilOrUndefined(b) {
+		s, o, err := ReadStringSetBytes(b, v.Convert(stringSetType).Interface().(map[string]struct{}), nil)
+		if err != nil {
+			return b, err
+		}
+		v.Set(reflect.ValueOf(s).Convert(v.Type()))
+		return o, nil
+	}
+	if f.boolAsInt {
+		x, o, err := ReadBoolIntBytes(b)
+		if err != nil {
+			return b, err
+		}
+		v.SetBool(x)
+		return o, nil
+	}
+	if !f.asString {
+		return decodeReflect(b, v, depth)
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		x, o, err := ReadBoolStringBytes(b)
+		if err != nil {
+			return b, err
+		}
+		v.SetBool(x)
+		return o, nil
+	case reflect.Float32, reflect.Float64:
+		x, o, err := ReadFloatStringBytes(b, v.Type().Bits())
+		if err != nil {
+			return b, err
+		}
+		v.SetFloat(x)
+		return o, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		x, o, err := ReadIntStringBytes(b, v.Type().Bits())
+		if err != nil {
+			return b, err
+		}
+		v.SetInt(x)
+		return o, nil
+	}
+	x, o, err := ReadUintStringBytes(b, v.Type().Bits())
+	if err != nil {
+		return b, err
+	}
+	v.SetUint(x)
+	return o, nil
+}
+
+// omitted reports whether f of struct value sv is left out when encoding.
+func (f *reflectField) omitted(v reflect.Value) bool {
+	if !f.omitZero && !f.omitEmpty {
+		return false
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	}
+	// A type's own IsZero method takes precedence over both options.
+	if z, ok := v.Interface().(zeroer); ok {
+		return z.IsZero()
+	}
+	if reflect.PointerTo(v.Type()).Implements(zeroerType) {
+		if !v.CanAddr() {
+			p := reflect.New(v.Type())
+			p.Elem().Set(v)
+			v = p.Elem()
+		}
+		return v.Addr().Interface().(zeroer).IsZero()
+	}
+	if f.omitZero && v.IsZero() {
+		return true
+	}
+	if !f.omitEmpty {
+		return false
+	}
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Complex64, reflect.Complex128:
+		return v.Complex() == 0
+	}
+	switch v.Type() {
+	case addrType, addrPortType:
+		return v.IsZero()
+	}
+	return false
+}
+
+func appendReflect(b []byte, v reflect.Value, depth int) ([]byte, error) {
+	if depth > MaxEncodeDepth {
+		return b, ErrCycleDetected
+	}
+	if !v.IsValid() {
+		return AppendNil(b), nil
+	}
+	t := v.Type()
+	if o, ok, err := appendCodec(b, v); ok {
+		return o, err
+	}
+	switch {
+	case t.Implements(marshalerType):
+		if (t.Kind() == reflect.Pointer || t.Kind() == reflect.Interface) && v.IsNil() {
+			return AppendNil(b), nil
+		}
+		return v.Interface().(Marshaler).MarshalCBOR(b)
+	case t.Kind() != reflect.Pointer && reflect.PointerTo(t).Implements(marshalerType):
+		if !v.CanAddr() {
+			p := reflect.New(t)
+			p.Elem().Set(v)
+			v = p.Elem()
+		}
+		return v.Addr().Interface().(Marshaler).MarshalCBOR(b)
+	case t == timeType:
+		return AppendTime(b, v.Interface().(time.Time)), nil
+	case t == ipType:
+		return AppendIP(b, v.Interface().(net.IP)), nil
+	case t == addrType:
+		return AppendAddr(b, v.Interface().(netip.Addr)), nil
+	case t == addrPortType:
+		return AppendAddrPort(b, v.Interface().(netip.AddrPort)), nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return AppendBool(b, v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return AppendInt64(b, v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return AppendUint64(b, v.Uint()), nil
+	case reflect.Float32:
+		return AppendFloat32(b, float32(v.Float())), nil
+	case reflect.Float64:
+		return AppendFloat64(b, v.Float()), nil
+	case reflect.Complex64:
+		return AppendComplex64(b, complex64(v.Complex())), nil
+	case reflect.Complex128:
+		return AppendComplex128(b, v.Complex()), nil
+	case reflect.String:
+		return AppendString(b, v.String()), nil
+	case reflect.Pointer:
+		if v.IsNil() {
+			return AppendNil(b), nil
+		}
+		return appendReflect(b, v.Elem(), depth+1)
+	case reflect.Interface:
+		if v.IsNil() {
+			return AppendNil(b), nil
+		}
+		if rt := lookupRegisteredValue(v.Interface()); rt != nil {
+			return rt.encode(AppendTag(b, rt.tag), v.Interface())
+		}
+		return appendReflect(b, v.Elem(), depth+1)
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && v.IsNil() && NilContainers == NilAsNull {
+			return AppendNil(b), nil
+		}
+		if t.Elem().Kind() == reflect.Uint8 {
+			if t.Kind() == reflect.Slice {
+				return AppendBytes(b, v.Bytes()), nil
+			}
+			b = appendUintCore(b, majorTypeBytes, uint64(v.Len()))
+			for i := 0; i < v.Len(); i++ {
+				b = append(b, byte(v.Index(i).Uint()))
+			}
+			return b, nil
+		}
+		b = AppendArrayHeader(b, uint32(v.Len()))
+		var err error
+		for i := 0; i < v.Len(); i++ {
+			if b, err = appendReflect(b, v.Index(i), depth+1); err != nil {
+				return b, err
+			}
+		}
+		return b, nil
+	case reflect.Map:
+		if v.IsNil() && NilContainers == NilAsNull {
+			return AppendNil(b), nil
+		}
+		return appendReflectMap(b, v, depth)
+	case reflect.Struct:
+		return appendReflectStruct(b, v, depth)
+	}
+	return b, &ErrUnsupportedType{T: t}
+}
+
+func appendReflectMap(b []byte, v reflect.Value, depth int) ([]byte, error) {
+	var err error
+	if !CanonicalMapEncode {
+		b = AppendMapHeader(b, uint32(v.Len()))
+		iter := v.MapRange()
+		for iter.Next() {
+			if b, err = appendReflect(b, iter.Key(), depth+1); err != nil {
+				return b, err
+			}
+			if b, err = appendReflect(b, iter.Value(), depth+1); err != nil {
+				return b, err
+			}
+		}
+		return b, nil
+	}
+	// Encode every entry into one buffer, then sort the pairs by key.
+	var scratch []byte
+	ends := make([][2]int, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		if scratch, err = appendReflect(scratch, iter.Key(), depth+1); err != nil {
+			return b, err
+		}
+		mid := len(scratch)
+		if scratch, err = appendReflect(scratch, iter.Value(), depth+1); err != nil {
+			return b, err
+		}
+		ends = append(ends, [2]int{mid, len(scratch)})
+	}
+	pairs := make([]RawPair, len(ends))
+	start := 0
+	for i, e := range ends {
+		pairs[i] = RawPair{Key: scratch[start:e[0]], Value: scratch[e[0]:e[1]]}
+		start = e[1]
+	}
+	SortMapKeys(pairs)
+	b = AppendMapHeader(b, uint32(len(pairs)))
+	for _, p := range pairs {
+		b = append(b, p.Key...)
+		b = append(b, p.Value...)
+	}
+	return b, nil
+}
+
+// field returns field i of struct v, or false when an embedded pointer on
+// its path is nil.
+func (rs *reflectStruct) field(v reflect.Value, i int) (reflect.Value, bool) {
+	if !rs.viaPointer {
+		return v.FieldByIndex(rs.fields[i].index), true
+	}
+	fv, err := v.FieldByIndexErr(rs.fields[i].index)
+	return fv, err == nil
+}
+
+// fieldByIndexAlloc returns the field of struct v at index, allocating
+// the nil embedded pointers on its path.
+func fieldByIndexAlloc(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+func appendReflectStruct(b []byte, v reflect.Value, depth int) ([]byte, error) {
+	rs, err := structFields(v.Type())
+	if err != nil {
+		return b, err
+	}
+	// In toarray structs only the run of trailing empty omit fields is
+	// left out, shortening the array, as in generated code. Presence
+	// structs leave out every empty omit field and list the rest in a
+	// bitmap.
+	count := len(rs.fields)
+	var present []byte
+	switch {
+	case rs.presence:
+		present = make([]byte, (len(rs.fields)+7)/8)
+		for i := range rs.fields {
+			if rs.fields[i].omitted(v.FieldByIndex(rs.fields[i].index)) {
+				count--
+			} else {
+				present[i/8] |= 1 << (i % 8)
+			}
+		}
+		b = AppendArrayHeader(b, uint32(count)+1)
+		b = AppendBytes(b, present)
+	case rs.toArray:
+		for count > 0 && rs.fields[count-1].omitted(v.FieldByIndex(rs.fields[count-1].index)) {
+			count--
+		}
+		b = AppendArrayHeader(b, uint32(count))
+	default:
+		for i := range rs.fields {
+			if fv, ok := rs.field(v, i); !ok || rs.fields[i].omitted(fv) {
+				count--
+			}
+		}
+		var extra map[string]any
+		if rs.flatten != nil {
+			extra = v.FieldByIndex(rs.flatten).Interface().(map[string]any)
+		}
+		b = AppendMapHeader(b, uint32(count)+FlattenedLen(extra, rs.hasField))
+	}
+	for i := range rs.fields {
+		f := &rs.fields[i]
+		fv, ok := rs.field(v, i)
+		if !ok {
+			continue
+		}
+		switch {
+		case rs.presence:
+			if present[i/8]&(1<<(i%8)) == 0 {
+				continue
+			}
+		case rs.toArray:
+			if i >= count {
+				return b, nil
+			}
+		default:
+			if f.omitted(fv) {
+				continue
+			}
+			if f.keyAsInt {
+				b = AppendInt64(b, f.intKey)
+			} else {
+				b = AppendString(b, f.name)
+			}
+		}
+		switch {
+		case f.asString:
+			b = appendReflectString(b, fv)
+			continue
+		case f.boolAsInt:
+			b = AppendBoolInt(b, fv.Bool())
+			continue
+		case f.asSet && !(fv.IsNil() && NilContainers == NilAsNull):
+			b = AppendStringSet(b, fv.Convert(stringSetType).Interface().(map[string]struct{}))
+			continue
+		case f.asMap && !(fv.IsNil() && NilContainers == NilAsNull):
+			if b, err = appendReflectPairs(b, fv, depth+1); err != nil {
+				return b, err
+			}
+			continue
+		case f.hasTag && f.tag == tagDateTimeString && fv.Type() == timeType:
+			// Field tags win over DefaultTimeFormat.
+			b = AppendRFC3339Time(b, fv.Interface().(time.Time))
+			continue
+		case f.hasTag && f.tag == tagEpochDateTime && fv.Type() == timeType:
+			if f.timeFloat {
+				b = AppendTimeFloat(b, fv.Interface().(time.Time))
+			} else {
+				b = AppendEpochTime(b, fv.Interface().(time.Time))
+			}
+			continue
+		case f.hasTag && (f.tag == tagIPv4 || f.tag == tagIPv6) && fv.Type() == ipType:
+			// The family of the address picks the tag.
+			b = AppendIPTagged(b, fv.Interface().(net.IP))
+			continue
+		case f.hasTag && (f.tag == tagIPv4 || f.tag == tagIPv6) && fv.Type() == addrType:
+			b = AppendAddrTagged(b, fv.Interface().(netip.Addr))
+			continue
+		case f.hasTag:
+			b = AppendTag(b, f.tag)
+		}
+		if b, err = appendReflect(b, fv, depth+1); err != nil {
+			return b, err
+		}
+	}
+	if rs.flatten != nil {
+		return AppendFlattened(b, v.FieldByIndex(rs.flatten).Interface().(map[string]any), rs.hasField)
+	}
+	return b, nil
+}
+
+func decodeReflect(b []byte, v reflect.Value, depth int) ([]byte, error) {
+	if depth > recursionLimit {
+		return b, ErrMaxDepthExceeded
+	}
+	if len(b) < 1 {
+		return b, ErrShortBytes
+	}
+	t := v.Type()
+	if o, ok, err := decodeCodec(b, v); ok {
+		return o, err
+	}
+	if t.Kind() != reflect.Pointer && reflect.PointerTo(t).Implements(unmarshalerType) {
+		return v.Addr().Interface().(Unmarshaler).UnmarshalCBOR(b)
+	}
+	if IsNilOrUndefined(b) {
+		switch {
+		case NilContainers == NilAsNull:
+			v.SetZero()
+		case t.Kind() == reflect.Slice:
+			if v.IsNil() {
+				v.Set(reflect.MakeSlice(t, 0, 0))
+			} else {
+				v.SetLen(0)
+			}
+		case t.Kind() == reflect.Map:
+			if v.IsNil() {
+				v.Set(reflect.MakeMap(t))
+			} else {
+				v.Clear()
+			}
+		default:
+			v.SetZero()
+		}
+		return b[1:], nil
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			v.Set(reflect.New(t.Elem()))
+		}
+		return decodeReflect(b, v.Elem(), depth+1)
+	case reflect.Interface:
+		if e := v.Elem(); e.Kind() == reflect.Pointer && !e.IsNil() {
+			return decodeReflect(b, e.Elem(), depth+1)
+		}
+		iv, o, err := readInterface(b, depth+1)
+		if err != nil {
+			return b, err
+		}
+		if iv == nil {
+			v.SetZero()
+			return o, nil
+		}
+		rv := reflect.ValueOf(iv)
+		if !rv.Type().AssignableTo(t) {
+			return b, &ErrUnsupportedType{T: rv.Type()}
+		}
+		v.Set(rv)
+		return o, nil
+	}
+	if t == timeType {
+		tm, o, err := ReadAnyTimeBytes(b)
+		if err != nil {
+			return b, err
+		}
+		v.Set(reflect.ValueOf(tm))
+		return o, nil
+	}
+	switch t {
+	case ipType:
+		ip, o, err := ReadIPBytes(b)
+		if err != nil {
+			return b, err
+		}
+		v.Set(reflect.ValueOf(ip))
+		return o, nil
+	case addrType:
+		a, o, err := ReadAddrBytes(b)
+		if err != nil {
+			return b, err
+		}
+		v.Set(reflect.ValueOf(a))
+		return o, nil
+	case addrPortType:
+		ap, o, err := ReadAddrPortBytes(b)
+		if err != nil {
+			return b, err
+		}
+		v.Set(reflect.ValueOf(ap))
+		return o, nil
+	}
+	// Other destinations ignore tags, as generated decoders do. Integers
+	// keep bignums for ReadInt64Bytes and ReadUint64Bytes.
+	o := b
+	integer := reflect.Int <= t.Kind() && t.Kind() <= reflect.Uintptr
+	for len(o) > 0 && getMajorType(o[0]) == majorTypeTag && !(integer && IsBignum(o)) {
+		var err error
+		if _, o, err = ReadTagBytes(o); err != nil {
+			return b, err
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		x, o, err := ReadBoolBytes(o)
+		if err != nil {
+			return b, err
+		}
+		v.SetBool(x)
+		return o, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		x, o, err := ReadInt64Bytes(o)
+		if err != nil {
+			return b, err
+		}
+		if v.OverflowInt(x) {
+			return b, IntOverflow{Value: x, FailedBitsize: t.Bits()}
+		}
+		v.SetInt(x)
+		return o, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		x, o, err := ReadUint64Bytes(o)
+		if err != nil {
+			return b, err
+		}
+		if v.OverflowUint(x) {
+			return b, UintOverflow{Value: x, FailedBitsize: t.Bits()}
+		}
+		v.SetUint(x)
+		return o, nil
+	case reflect.Float32:
+		x, o, err := ReadFloat32Bytes(o)
+		if err != nil {
+			return b, err
+		}
+		v.SetFloat(float64(x))
+		return o, nil
+	case reflect.Float64:
+		x, o, err := ReadFloat64Bytes(o)
+		if err != nil {
+			return b, err
+		}
+		v.SetFloat(x)
+		return o, nil
+	case reflect.Complex64, reflect.Complex128:
+		x, o, err := ReadComplex128Bytes(o)
+		if err != nil {
+			return b, err
+		}
+		v.SetComplex(x)
+		return o, nil
+	case reflect.String:
+		x, o, err := ReadStringBytes(o)
+		if err != nil {
+			return b, err
+		}
+		v.SetString(x)
+		return o, nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return decodeReflectBytes(o, v)
+		}
+		return decodeReflectArray(o, v, depth)
+	case reflect.Map:
+		return decodeReflectMap(o, v, depth)
+	case reflect.Struct:
+		return decodeReflectStruct(o, v, depth)
+	}
+	return b, &ErrUnsupportedType{T: t}
+}
+
+func decodeReflectBytes(b []byte, v reflect.Value) ([]byte, error) {
+	x, o, err := ReadBytesBytes(b, nil)
+	if err != nil {
+		return b, err
+	}
+	if v.Kind() == reflect.Slice {
+		v.SetBytes(append(make([]byte, 0, len(x)), x...))
+		return o, nil
+	}
+	if len(x) != v.Len() {
+		return b, ArrayError{Wanted: uint32(v.Len()), Got: uint32(len(x))}
+	}
+	reflect.Copy(v, reflect.ValueOf(x))
+	return o, nil
+}
+
+func decodeReflectArray(b []byte, v reflect.Value, depth int) ([]byte, error) {
+	sz, indefinite, o, err := ReadArrayStartBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if v.Kind() == reflect.Array && !indefinite && int(sz) != v.Len() {
+		return b, ArrayError{Wanted: uint32(v.Len()), Got: sz}
+	}
+	if v.Kind() == reflect.Slice {
+		v.Set(reflect.MakeSlice(v.Type(), 0, min(int(sz), 1024)))
+	}
+	elem := v.Type().Elem()
+	for i := 0; indefinite || i < int(sz); i++ {
+		if indefinite {
+			var done bool
+			if o, done, err = ReadBreakBytes(o); err != nil {
+				return b, err
+			}
+			if done {
+				if v.Kind() == reflect.Array && i != v.Len() {
+					return b, ArrayError{Wanted: uint32(v.Len()), Got: uint32(i)}
+				}
+				break
+			}
+			if err = checkElements(uint64(i) + 1); err != nil {
+				return b, err
+			}
+		}
+		start := o
+		if v.Kind() == reflect.Slice {
+			e := reflect.New(elem).Elem()
+			o, err = decodeReflect(o, e, depth+1)
+			v.Set(reflect.Append(v, e))
+		} else if i < v.Len() {
+			o, err = decodeReflect(o, v.Index(i), depth+1)
+		} else {
+			return b, ArrayError{Wanted: uint32(v.Len()), Got: uint32(i + 1)}
+		}
+		if err != nil {
+			return b, WrapDecodeError(WrapDecodeIndex(err, i), "", len(b)-len(start))
+		}
+	}
+	return o, nil
+}
+
+func decodeReflectMap(b []byte, v reflect.Value, depth int) ([]byte, error) {
+	sz, indefinite, o, err := ReadMapStartBytes(b)
+	if err != nil {
+		return b, err
+	}
+	t := v.Type()
+	// A map that already holds entries records the keys read in seen to
+	// tell duplicates from earlier contents.
+	var seen reflect.Value
+	if v.IsNil() {
+		v.Set(reflect.MakeMapWithSize(t, min(int(sz), 1024)))
+	} else if v.Len() > 0 && DuplicateMapKeys != DuplicateKeyLast {
+		seen = reflect.MakeMap(reflect.MapOf(t.Key(), reflect.TypeFor[struct{}]()))
+	}
+	for i := 0; indefinite || i < int(sz); i++ {
+		if indefinite {
+			var done bool
+			if o, done, err = ReadBreakBytes(o); err != nil {
+				return b, err
+			}
+			if done {
+				break
+			}
+			if err = checkElements(uint64(i) + 1); err != nil {
+				return b, err
+			}
+		}
+		start := o
+		k := reflect.New(t.Key()).Elem()
+		if o, err = decodeReflect(o, k, depth+1); err != nil {
+			return b, WrapDecodeError(err, "", len(b)-len(start))
+		}
+		start = o
+		if DuplicateMapKeys != DuplicateKeyLast {
+			var dup bool
+			if seen.IsValid() {
+				dup = seen.MapIndex(k).IsValid()
+				seen.SetMapIndex(k, reflect.ValueOf(struct{}{}))
+			} else {
+				dup = v.MapIndex(k).IsValid()
+			}
+			if dup {
+				var skip bool
+				if o, skip, err = SkipDuplicate(o); err != nil {
+					if k.Kind() == reflect.String {
+						err = WrapDecodeKey(err, k.String())
+					}
+					return b, WrapDecodeError(err, "", len(b)-len(start))
+				}
+				if skip {
+					continue
+				}
+			}
+		}
+		e := reflect.New(t.Elem()).Elem()
+		if o, err = decodeReflect(o, e, depth+1); err != nil {
+			if k.Kind() == reflect.String {
+				err = WrapDecodeKey(err, k.String())
+			} else {
+				err = WrapDecodeError(err, fmt.Sprintf("[%v]", k.Interface()), 0)
+			}
+			return b, WrapDecodeError(err, "", len(b)-len(start))
+		}
+		v.SetMapIndex(k, e)
+	}
+	return o, nil
+}
+
+// decodeReflectPresence decodes a toarray struct with the presence
+// option: a bitmap followed by the fields whose bits are set.
+func decodeReflectPresence(b []byte, v reflect.Value, rs *reflectStruct, depth int) ([]byte, error) {
+	sz, indefinite, o, err := ReadArraySizeBytes(b)
+	if err != nil {
+		return b, err
+	}
+	present, o, err := ReadPresenceBytes(o, sz, len(rs.fields))
+	if err != nil {
+		return b, WrapDecodeError(err, "", len(b)-len(o))
+	}
+	for i := 0; i < len(present)*8; i++ {
+		if present[i/8]&(1<<(i%8)) == 0 {
+			continue
+		}
+		start := o
+		if i < len(rs.fields) {
+			o, err = rs.fields[i].decode(o, v.FieldByIndex(rs.fields[i].index), depth+1)
+			if err != nil {
+				return b, WrapDecodeError(err, rs.fields[i].name, len(b)-len(start))
+			}
+		} else if o, err = Skip(o); err != nil {
+			return b, WrapDecodeError(err, "", len(b)-len(start))
+		}
+	}
+	if indefinite {
+		o = o[1:] // break
+	}
+	return o, nil
+}
+
+func decodeReflectStruct(b []byte, v reflect.Value, depth int) ([]byte, error) {
+	rs, err := structFields(v.Type())
+	if err != nil {
+		return b, err
+	}
+	if rs.presence {
+		return decodeReflectPresence(b, v, rs, depth)
+	}
+	if rs.toArray {
+		sz, indefinite, o, err := ReadArrayStartBytes(b)
+		if err != nil {
+			return b, err
+		}
+		if !indefinite && int(sz) > len(rs.fields) && !TolerateExtraArrayElements {
+			return b, ArrayError{Wanted: uint32(len(rs.fields)), Got: sz}
+		}
+		for i := 0; indefinite || i < int(sz); i++ {
+			if indefinite {
+				var done bool
+				if o, done, err = ReadBreakBytes(o); err != nil {
+					return b, err
+				}
+				if done {
+					break
+				}
+				if err = checkElements(uint64(i) + 1); err != nil {
+					return b, err
+				}
+			}
+			start := o
+			if i < len(rs.fields) {
+				o, err = rs.fields[i].decode(o, v.FieldByIndex(rs.fields[i].index), depth+1)
+			} else if TolerateExtraArrayElements {
+				o, err = Skip(o)
+			} else {
+				err = ArrayError{Wanted: uint32(len(rs.fields)), Got: uint32(i + 1)}
+			}
+			if err != nil {
+				name := ""
+				if i < len(rs.fields) {
+					name = rs.fields[i].name
+				}
+				return b, WrapDecodeError(err, name, len(b)-len(start))
+			}
+		}
+		return o, nil
+	}
+
+	sz, indefinite, o, err := ReadMapStartBytes(b)
+	if err != nil {
+		return b, err
+	}
+	var seen []uint64
+	if DuplicateMapKeys != DuplicateKeyLast {
+		seen = make([]uint64, (len(rs.fields)+63)/64)
+	}
+	for i := 0; indefinite || i < int(sz); i++ {
+		if indefinite {
+			var done bool
+			if o, done, err = ReadBreakBytes(o); err != nil {
+				return b, err
+			}
+			if done {
+				break
+			}
+			if err = checkElements(uint64(i) + 1); err != nil {
+				return b, err
+			}
+		}
+		if len(o) < 1 {
+			return b, ErrShortBytes
+		}
+		idx, found := -1, false
+		start := o
+		var textKey string
+		isText := false
+		switch getMajorType(o[0]) {
+		case majorTypeText:
+			if textKey, o, err = ReadStringBytes(o); err == nil {
+				idx, found = rs.byName[textKey]
+				isText = true
+			}
+		case majorTypeUint, majorTypeNegInt:
+			var key int64
+			var fits bool
+			if key, fits, o, err = ReadIntKeyBytes(o); err == nil && fits {
+				idx, found = rs.byInt[key]
+			}
+		default:
+			o, err = Skip(o)
+		}
+		if err != nil {
+			return b, WrapDecodeError(err, "", len(b)-len(start))
+		}
+		start = o
+		skip := !found
+		if found && seen != nil {
+			if o, skip, err = DuplicateField(o, seen, idx); err != nil {
+				return b, WrapDecodeError(err, rs.fields[idx].name, len(b)-len(start))
+			}
+			if skip {
+				continue
+			}
+		}
+		if !found && isText && rs.flatten != nil {
+			// An unknown text key goes into the flatten field.
+			ev := v.FieldByIndex(rs.flatten)
+			var m map[string]any
+			m, o, err = ReadFlattenedBytes(o, textKey, ev.Interface().(map[string]any))
+			if err != nil {
+				return b, WrapDecodeError(err, textKey, len(b)-len(start))
+			}
+			ev.Set(reflect.ValueOf(m))
+			continue
+		}
+		if skip {
+			o, err = Skip(o)
+		} else {
+			o, err = rs.fields[idx].decode(o, fieldByIndexAlloc(v, rs.fields[idx].index), depth+1)
+		}
+		if err != nil {
+			name := ""
+			if found {
+				name = rs.fields[idx].name
+			}
+			return b, WrapDecodeError(err, name, len(b)-len(start))
+		}
+	}
+	return o, nil
+}
@@ -0,0 +1,128 @@
+package cbor
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrDuplicateTag is returned by RegisterType when the tag number is
+// already associated with another type.
+var ErrDuplicateTag = errors.New("cbor: tag already registered")
+
+// registeredType describes a concrete type registered for polymorphic
+// (interface-typed) encoding under a CBOR tag.
+type registeredType struct {
+	tag    uint64
+	typ    reflect.Type
+	encode func(b []byte, v any) ([]byte, error)
+	decode func(b []byte) (any, []byte, error)
+}
+
+var typeRegistry struct {
+	mu     sync.RWMutex
+	byTag  map[uint64]*registeredType
+	byType map[reflect.Type]*registeredType
+}
+
+// registryInUse lets AppendInterface skip the registry lookup entirely
+// until the first RegisterType call.
+var registryInUse atomic.Bool
+
+// RegisterType associates the concrete type T with a CBOR tag number so
+// values held in interface-typed fields can round-trip polymorphically.
+// The example value is only used to infer T.
+//
+// When a value of type T is encoded via AppendInterface (which generated
+// code uses for interface fields) it is wrapped in the tag; on decode,
+// ReadInterfaceBytes uses the tag to construct a T. T (or *T) must
+// implement Marshaler and *T (or T, when T is a pointer type) must
+// implement Unmarshaler.
+//
+// RegisterType is safe for concurrent use. Registering a tag twice, or
+// the same type under two tags, returns an error.
+func RegisterType[T any](tag uint64, example T) error {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	if typ.Kind() == reflect.Interface {
+		return &ErrUnsupportedType{T: typ}
+	}
+
+	var zero T
+	_, valMarshaler := any(zero).(Marshaler)
+	_, ptrMarshaler := any(&zero).(Marshaler)
+	if !valMarshaler && !ptrMarshaler {
+		return &ErrUnsupportedType{T: typ}
+	}
+	isPtr := typ.Kind() == reflect.Pointer
+	if isPtr {
+		if _, ok := reflect.New(typ.Elem()).Interface().(Unmarshaler); !ok {
+			return &ErrUnsupportedType{T: typ}
+		}
+	} else if _, ok := any(&zero).(Unmarshaler); !ok {
+		return &ErrUnsupportedType{T: typ}
+	}
+
+	rt := &registeredType{
+		tag: tag,
+		typ: typ,
+		encode: func(b []byte, v any) ([]byte, error) {
+			x := v.(T)
+			if m, ok := any(x).(Marshaler); ok {
+				return m.MarshalCBOR(b)
+			}
+			return any(&x).(Marshaler).MarshalCBOR(b)
+		},
+		decode: func(b []byte) (any, []byte, error) {
+			if isPtr {
+				p := reflect.New(typ.Elem()).Interface()
+				o, err := p.(Unmarshaler).UnmarshalCBOR(b)
+				return p, o, err
+			}
+			var x T
+			o, err := any(&x).(Unmarshaler).UnmarshalCBOR(b)
+			return x, o, err
+		},
+	}
+
+	typeRegistry.mu.Lock()
+	defer typeRegistry.mu.Unlock()
+	if prev, ok := typeRegistry.byTag[tag]; ok {
+		return fmt.Errorf("%w: tag %d is used by %s", ErrDuplicateTag, tag, prev.typ)
+	}
+	if prev, ok := typeRegistry.byType[typ]; ok {
+		return fmt.Errorf("cbor: type %s already registered with tag %d", typ, prev.tag)
+	}
+	if typeRegistry.byTag == nil {
+		typeRegistry.byTag = make(map[uint64]*registeredType)
+		typeRegistry.byType = make(map[reflect.Type]*registeredType)
+	}
+	typeRegistry.byTag[tag] = rt
+	typeRegistry.byType[typ] = rt
+	registryInUse.Store(true)
+	return nil
+}
+
+// lookupRegisteredTag returns the registration for tag, or nil.
+func lookupRegisteredTag(tag uint64) *registeredType {
+	if !registryInUse.Load() {
+		return nil
+	}
+	typeRegistry.mu.RLock()
+	rt := typeRegistry.byTag[tag]
+	typeRegistry.mu.RUnlock()
+	return rt
+}
+
+// lookupRegisteredValue returns the registration for the dynamic type
+// of v, or nil.
+func lookupRegisteredValue(v any) *registeredType {
+	if !registryInUse.Load() {
+		return nil
+	}
+	typeRegistry.mu.RLock()
+	rt := typeRegistry.byType[reflect.TypeOf(v)]
+	typeRegistry.mu.RUnlock()
+	return rt
+}
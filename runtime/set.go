@@ -0,0 +1,55 @@
+package cbor
+
+import (
+	"maps"
+	"slices"
+)
+
+// AppendStringSet appends the members of s as an array of text strings,
+// the encoding of a map[string]struct{} field tagged "set". Members are
+// written in map order, or sorted when CanonicalMapEncode is set so equal
+// sets encode to equal bytes. A nil set is written as an empty array.
+func AppendStringSet(b []byte, s map[string]struct{}) []byte {
+	b = AppendArrayHeader(b, uint32(len(s)))
+	if CanonicalMapEncode {
+		for _, k := range slices.Sorted(maps.Keys(s)) {
+			b = AppendString(b, k)
+		}
+		return b
+	}
+	for k := range s {
+		b = AppendString(b, k)
+	}
+	return b
+}
+
+// ReadStringSetBytes reads an array of text strings written by
+// AppendStringSet into s, which is cleared first, and returns the set. A
+// nil s is allocated when the array is not empty; null yields a nil set.
+// A member repeated in the array is kept once. Strings are read through
+// in, which may be nil.
+func ReadStringSetBytes(b []byte, s map[string]struct{}, in *Interner) (map[string]struct{}, []byte, error) {
+	if IsNil(b) {
+		return nil, b[1:], nil
+	}
+	sz, indef, o, err := ReadArraySizeBytes(b)
+	if err != nil {
+		return s, b, err
+	}
+	if s == nil && sz > 0 {
+		s = make(map[string]struct{}, sz)
+	} else {
+		clear(s)
+	}
+	for i := uint32(0); i < sz; i++ {
+		var k string
+		if k, o, err = in.ReadStringBytes(o); err != nil {
+			return s, b, err
+		}
+		s[k] = struct{}{}
+	}
+	if indef {
+		o = o[1:] // break
+	}
+	return s, o, nil
+}
@@ -0,0 +1,62 @@
+package cbor
+
+import "strconv"
+
+// SimpleValue is a CBOR simple value (major type 7, other than floats).
+// Fields of this type capture the numeric value as sent, including the
+// well-known false (20), true (21), null (22) and undefined (23).
+// Values 24..31 are reserved and cannot be encoded.
+type SimpleValue uint8
+
+// Well-known simple values.
+const (
+	SimpleFalse     SimpleValue = simpleFalse
+	SimpleTrue      SimpleValue = simpleTrue
+	SimpleNull      SimpleValue = simpleNull
+	SimpleUndefined SimpleValue = simpleUndefined
+)
+
+// MarshalCBOR implements Marshaler
+func (s SimpleValue) MarshalCBOR(b []byte) ([]byte, error) {
+	if s > addInfoDirect && s < 32 {
+		return b, ErrInvalidSimpleValue
+	}
+	return AppendSimpleValue(b, uint8(s)), nil
+}
+
+// UnmarshalCBOR implements Unmarshaler
+func (s *SimpleValue) UnmarshalCBOR(b []byte) ([]byte, error) {
+	v, o, err := ReadSimpleValue(b)
+	if err != nil {
+		return b, err
+	}
+	*s = SimpleValue(v)
+	return o, nil
+}
+
+// String returns the CBOR diagnostic notation for s.
+func (s SimpleValue) String() string {
+	switch s {
+	case SimpleFalse:
+		return "false"
+	case SimpleTrue:
+		return "true"
+	case SimpleNull:
+		return "null"
+	case SimpleUndefined:
+		return "undefined"
+	}
+	return "simple(" + strconv.Itoa(int(s)) + ")"
+}
+
+// IsUndefined reports whether the next item in b is undefined.
+func IsUndefined(b []byte) bool {
+	return len(b) > 0 && b[0] == makeByte(majorTypeSimple, simpleUndefined)
+}
+
+// IsNilOrUndefined reports whether the next item in b is null or
+// undefined. Generated decoders treat both as absent, leaving pointer
+// and interface fields nil.
+func IsNilOrUndefined(b []byte) bool {
+	return IsNil(b) || IsUndefined(b)
+}
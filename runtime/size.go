@@ -17,9 +17,22 @@ const (
 	Float64Size    = 9
 	Float32Size    = 5
 	DurationSize   = Int64Size
-	TimeSize       = 15
+	// Complex sizes allow for an optional ComplexTag.
+	Complex64Size  = 9 + 1 + 2*Float32Size
+	Complex128Size = 9 + 1 + 2*Float64Size
+	// A time is at most tag 0 and 35 characters of RFC 3339 text.
+	TimeSize       = 1 + 2 + 35
+	// IP sizes allow for an optional tag 52 or 54.
+	IPSize         = 2 + 1 + 16
+	AddrPortSize   = 1 + IPSize + Uint16Size
+	// A Decimal is tag 4 and an array of two integers.
+	DecimalSize = 1 + 1 + 2*Int64Size
+	// A ",string" number or bool is at most 24 characters of text, as
+	// in -2.2250738585072014e-308.
+	NumberStringSize = 2 + 24
 	BoolSize       = 1
 	NilSize        = 1
+	TagSize        = 9
 	MapHeaderSize   = 5
 	ArrayHeaderSize = 5
 	BytesPrefixSize     = 5
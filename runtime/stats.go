@@ -0,0 +1,69 @@
+package cbor
+
+import "time"
+
+// ItemStats describes one item handled by an Encoder or Decoder with an
+// OnComplete hook, for metrics and tracing.
+type ItemStats struct {
+	// Value is the Marshaler passed to Encode or the Unmarshaler passed
+	// to Decode, so a hook can break numbers down by type.
+	Value any
+	// Size is the length of the item's encoding in bytes, including any
+	// envelope tag. It is zero when Encode fails.
+	Size int
+	// Duration is the time spent encoding or decoding the item, leaving
+	// out reads from and writes to the stream.
+	Duration time.Duration
+	// Err is the error encoding or decoding the item failed with, if any.
+	Err error
+}
+
+// FieldSize is the size of one map entry, as reported by FieldSizes.
+type FieldSize struct {
+	// Key is a text key as it is, or the diagnostic notation of any other
+	// key, e.g. "7" for a keyasint field.
+	Key string
+	// Size is the length in bytes of the entry's key and value.
+	Size int
+}
+
+// FieldSizes returns the size of each entry of the map at the start of b
+// in encoded order, and the bytes after it. Run over a sample of encoded
+// values, it shows which fields dominate the payload without touching the
+// encoders themselves.
+func FieldSizes(b []byte) ([]FieldSize, []byte, error) {
+	sz, indefinite, o, err := ReadMapStartBytes(b)
+	if err != nil {
+		return nil, b, err
+	}
+	sizes := make([]FieldSize, 0, min(int(sz), 1024))
+	for i := 0; indefinite || i < int(sz); i++ {
+		if indefinite {
+			var done bool
+			if o, done, err = ReadBreakBytes(o); err != nil {
+				return sizes, b, err
+			}
+			if done {
+				break
+			}
+			if err = checkElements(uint64(i) + 1); err != nil {
+				return sizes, b, err
+			}
+		}
+		start := o
+		var key string
+		if m, _ := Peek(o); m == MajorText {
+			key, o, err = ReadStringBytes(o)
+		} else {
+			key, o, err = DiagBytes(o)
+		}
+		if err != nil {
+			return sizes, b, err
+		}
+		if o, err = Skip(o); err != nil {
+			return sizes, b, err
+		}
+		sizes = append(sizes, FieldSize{Key: key, Size: len(start) - len(o)})
+	}
+	return sizes, o, nil
+}
@@ -0,0 +1,138 @@
+package cbor
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Fields tagged ",string" hold a number or bool as its text form in a
+// text string, as encoding/json's ",string" option does: integers in
+// decimal, floats in the shortest form that reads back exactly (with
+// NaN, +Inf and -Inf spelled out), bools as true or false. The readers
+// parse with the strconv rules for the destination type, so a value out
+// of its range fails like any other text that does not parse.
+
+// StringNumberError is returned when the text of a ",string" field does
+// not parse as the field's type.
+type StringNumberError struct {
+	Value string // the text that was read
+	Type  string // the type it was parsed as, e.g. "int32" or "bool"
+	ctx   string
+}
+
+// Error implements the error interface
+func (e StringNumberError) Error() string {
+	str := "cbor: cannot parse " + strconv.Quote(e.Value) + " as " + e.Type
+	if e.ctx != "" {
+		str += " at " + e.ctx
+	}
+	return str
+}
+
+// Resumable returns 'true' for StringNumberError
+func (e StringNumberError) Resumable() bool { return true }
+
+func (e StringNumberError) withContext(ctx string) error { e.ctx = addCtx(e.ctx, ctx); return e }
+
+// AppendIntString appends i as a decimal text string.
+func AppendIntString(b []byte, i int64) []byte {
+	var buf [20]byte
+	return AppendStringFromBytes(b, strconv.AppendInt(buf[:0], i, 10))
+}
+
+// AppendUintString appends u as a decimal text string.
+func AppendUintString(b []byte, u uint64) []byte {
+	var buf [20]byte
+	return AppendStringFromBytes(b, strconv.AppendUint(buf[:0], u, 10))
+}
+
+// AppendFloatString appends f as a text string holding the shortest
+// decimal that reads back as the same float of bitSize bits.
+func AppendFloatString(b []byte, f float64, bitSize int) []byte {
+	var buf [32]byte
+	return AppendStringFromBytes(b, strconv.AppendFloat(buf[:0], f, 'g', -1, bitSize))
+}
+
+// AppendBoolString appends v as the text string "true" or "false".
+func AppendBoolString(b []byte, v bool) []byte {
+	if v {
+		return AppendString(b, "true")
+	}
+	return AppendString(b, "false")
+}
+
+// ReadIntStringBytes reads a text string written by AppendIntString and
+// parses it as a signed integer of bitSize bits (0 for int).
+func ReadIntStringBytes(b []byte, bitSize int) (i int64, o []byte, err error) {
+	s, o, err := readNumberText(b)
+	if err != nil {
+		return 0, b, err
+	}
+	if i, err = strconv.ParseInt(s, 10, bitSize); err != nil {
+		return 0, b, numberTextError(s, "int", bitSize)
+	}
+	return i, o, nil
+}
+
+// ReadUintStringBytes reads a text string written by AppendUintString
+// and parses it as an unsigned integer of bitSize bits (0 for uint).
+func ReadUintStringBytes(b []byte, bitSize int) (u uint64, o []byte, err error) {
+	s, o, err := readNumberText(b)
+	if err != nil {
+		return 0, b, err
+	}
+	if u, err = strconv.ParseUint(s, 10, bitSize); err != nil {
+		return 0, b, numberTextError(s, "uint", bitSize)
+	}
+	return u, o, nil
+}
+
+// ReadFloatStringBytes reads a text string written by AppendFloatString
+// and parses it as a float of bitSize bits.
+func ReadFloatStringBytes(b []byte, bitSize int) (f float64, o []byte, err error) {
+	s, o, err := readNumberText(b)
+	if err != nil {
+		return 0, b, err
+	}
+	if f, err = strconv.ParseFloat(s, bitSize); err != nil {
+		return 0, b, numberTextError(s, "float", bitSize)
+	}
+	return f, o, nil
+}
+
+// ReadBoolStringBytes reads a text string written by AppendBoolString.
+func ReadBoolStringBytes(b []byte) (v bool, o []byte, err error) {
+	s, o, err := readNumberText(b)
+	if err != nil {
+		return false, b, err
+	}
+	switch s {
+	case "true":
+		return true, o, nil
+	case "false":
+		return false, o, nil
+	}
+	return false, b, StringNumberError{Value: strings.Clone(s), Type: "bool"}
+}
+
+// readNumberText reads a text string without copying it where possible;
+// the result must not outlive b.
+func readNumberText(b []byte) (string, []byte, error) {
+	if len(b) > 0 && b[0] == makeByte(majorTypeText, addInfoIndefinite) {
+		return ReadStringBytes(b)
+	}
+	v, o, err := ReadStringZC(b)
+	if err != nil {
+		return "", b, err
+	}
+	return UnsafeString(v), o, nil
+}
+
+// numberTextError reports text s that did not parse as the given kind of
+// bitSize bits, copying s out of the input buffer.
+func numberTextError(s, kind string, bitSize int) error {
+	if bitSize != 0 {
+		kind += strconv.Itoa(bitSize)
+	}
+	return StringNumberError{Value: strings.Clone(s), Type: kind}
+}
@@ -0,0 +1,68 @@
+package cbor
+
+import "time"
+
+// TimeFormat selects how AppendTime, and so generated code, Marshal and
+// the Encoder, write a time.Time that has no tag option of its own.
+type TimeFormat uint8
+
+const (
+	// TimeEpoch (the default) writes tag 1 with integer seconds since
+	// the Unix epoch, or float seconds when the time has a fractional
+	// second.
+	TimeEpoch TimeFormat = iota
+	// TimeEpochInt writes tag 1 with integer seconds, dropping any
+	// fractional second.
+	TimeEpochInt
+	// TimeEpochFloat writes tag 1 with float seconds, as AppendTimeFloat
+	// does.
+	TimeEpochFloat
+	// TimeRFC3339 writes tag 0 with RFC 3339 text keeping the UTC offset,
+	// as AppendRFC3339Time does.
+	TimeRFC3339
+)
+
+// DefaultTimeFormat is the TimeFormat of time.Time values encoded without
+// a tag option: fields with "tag=0" or "tag=1" keep the form they name.
+// The decoders accept every form whatever it is set to, so a service can
+// change it without breaking data it has already stored.
+var DefaultTimeFormat = TimeEpoch
+
+// String returns the name of the format.
+func (f TimeFormat) String() string {
+	switch f {
+	case TimeEpoch:
+		return "TimeEpoch"
+	case TimeEpochInt:
+		return "TimeEpochInt"
+	case TimeEpochFloat:
+		return "TimeEpochFloat"
+	case TimeRFC3339:
+		return "TimeRFC3339"
+	}
+	return "TimeFormat(invalid)"
+}
+
+// AppendTime appends t in the form DefaultTimeFormat selects.
+func AppendTime(b []byte, t time.Time) []byte {
+	switch DefaultTimeFormat {
+	case TimeEpochInt:
+		return AppendInt64(AppendTag(b, tagEpochDateTime), t.Unix())
+	case TimeEpochFloat:
+		return AppendTimeFloat(b, t)
+	case TimeRFC3339:
+		return AppendRFC3339Time(b, t)
+	}
+	return AppendEpochTime(b, t)
+}
+
+// AppendEpochTime appends t as CBOR tag 1 with integer seconds since the
+// Unix epoch, or float seconds when t has a fractional second, whatever
+// DefaultTimeFormat is. It backs the tag=1 field option.
+func AppendEpochTime(b []byte, t time.Time) []byte {
+	b = AppendTag(b, tagEpochDateTime)
+	if t.Nanosecond() == 0 {
+		return AppendInt64(b, t.Unix())
+	}
+	return AppendFloat64(b, EpochSeconds(t))
+}
@@ -0,0 +1,166 @@
+package cbor
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+)
+
+// Keys of the discriminated-union map written by AppendUnion.
+const (
+	unionKeyTag     = 0
+	unionKeyPayload = 1
+)
+
+// ErrInvalidUnion is returned when a discriminated union is not a map
+// holding exactly the type tag (key 0) and payload (key 1).
+var ErrInvalidUnion = errors.New("cbor: malformed discriminated union")
+
+// LenientUnionDecode controls how ReadUnionBytes treats a union whose
+// type tag has not been registered. When false (the default) decoding
+// fails with UnknownUnionTagError; when true the complete union item is
+// returned as a RawMessage so it can be inspected or re-encoded verbatim.
+// A field of an interface type other than any cannot hold a RawMessage,
+// so ReadUnionAsBytes still fails for one, with an UnknownUnionTagError
+// carrying the item in Raw.
+var LenientUnionDecode = false
+
+// RawMessage holds a complete, already-encoded CBOR item.
+type RawMessage = Raw
+
+// UnknownUnionTagError is returned when a discriminated union names a
+// type tag that has not been registered with RegisterType. Raw is the
+// complete union item when LenientUnionDecode is set, and nil otherwise.
+type UnknownUnionTagError struct {
+	Tag uint64
+	Raw RawMessage
+}
+
+// Error implements error
+func (e UnknownUnionTagError) Error() string {
+	return "cbor: no type registered for union tag " + strconv.FormatUint(e.Tag, 10)
+}
+
+// Resumable returns 'true' for UnknownUnionTagError
+func (e UnknownUnionTagError) Resumable() bool { return true }
+
+// AppendUnion appends v as a discriminated union: a two-entry map
+// {0: typeTag, 1: payload} where typeTag is the tag v's concrete type
+// was registered under with RegisterType. A nil v is written as null and
+// a RawMessage is appended verbatim.
+func AppendUnion(b []byte, v any) ([]byte, error) {
+	if v == nil {
+		return AppendNil(b), nil
+	}
+	if raw, ok := v.(RawMessage); ok {
+		return raw.MarshalCBOR(b)
+	}
+	rt := lookupRegisteredValue(v)
+	if rt == nil {
+		return b, &ErrUnsupportedType{T: reflect.TypeOf(v)}
+	}
+	b = AppendMapHeader(b, 2)
+	b = AppendUint64(b, unionKeyTag)
+	b = AppendUint64(b, rt.tag)
+	b = AppendUint64(b, unionKeyPayload)
+	return rt.encode(b, v)
+}
+
+// ReadUnionBytes reads a discriminated union written by AppendUnion and
+// returns the decoded concrete value. A null or undefined yields a nil
+// value.
+func ReadUnionBytes(b []byte) (v any, o []byte, err error) {
+	v, _, o, err = readUnion(b)
+	return v, o, err
+}
+
+// readUnion is ReadUnionBytes, also returning the union's type tag.
+func readUnion(b []byte) (v any, tag uint64, o []byte, err error) {
+	if IsNilOrUndefined(b) {
+		return nil, 0, b[1:], nil
+	}
+	sz, o, err := ReadMapHeaderBytes(b)
+	if err != nil {
+		return nil, tag, b, err
+	}
+	if sz != 2 {
+		return nil, tag, b, ErrInvalidUnion
+	}
+
+	var (
+		payload    []byte
+		hasTag     bool
+		hasPayload bool
+	)
+	for i := 0; i < 2; i++ {
+		var key uint64
+		key, o, err = ReadUint64Bytes(o)
+		if err != nil {
+			return nil, tag, b, err
+		}
+		switch key {
+		case unionKeyTag:
+			if hasTag {
+				return nil, tag, b, ErrInvalidUnion
+			}
+			tag, o, err = ReadUint64Bytes(o)
+			if err != nil {
+				return nil, tag, b, err
+			}
+			hasTag = true
+		case unionKeyPayload:
+			if hasPayload {
+				return nil, tag, b, ErrInvalidUnion
+			}
+			payload = o
+			o, err = Skip(o)
+			if err != nil {
+				return nil, tag, b, err
+			}
+			payload = payload[:len(payload)-len(o)]
+			hasPayload = true
+		default:
+			return nil, tag, b, ErrInvalidUnion
+		}
+	}
+
+	rt := lookupRegisteredTag(tag)
+	if rt == nil {
+		if LenientUnionDecode {
+			raw := make(RawMessage, len(b)-len(o))
+			copy(raw, b)
+			return raw, tag, o, nil
+		}
+		return nil, tag, b, UnknownUnionTagError{Tag: tag}
+	}
+	v, _, err = rt.decode(payload)
+	if err != nil {
+		return nil, tag, b, err
+	}
+	return v, tag, o, nil
+}
+
+// ReadUnionAsBytes reads a discriminated union with ReadUnionBytes and
+// asserts the result to T. It is intended for generated code decoding
+// interface fields tagged with the `union` option. With
+// LenientUnionDecode set, an unregistered tag yields a RawMessage only
+// when T is any; otherwise it fails with an UnknownUnionTagError whose
+// Raw holds the item.
+func ReadUnionAsBytes[T any](b []byte) (v T, o []byte, err error) {
+	iv, tag, o, err := readUnion(b)
+	if err != nil {
+		return v, b, err
+	}
+	if iv == nil {
+		return v, o, nil
+	}
+	v, ok := iv.(T)
+	if !ok {
+		if raw, isRaw := iv.(RawMessage); isRaw && LenientUnionDecode {
+			// An unregistered tag, kept for a T that cannot hold it.
+			return v, b, UnknownUnionTagError{Tag: tag, Raw: raw}
+		}
+		return v, b, &ErrUnsupportedType{T: reflect.TypeOf(iv)}
+	}
+	return v, o, nil
+}
@@ -1,3 +1,5 @@
+//go:build !purego && !tinygo
+
 package cbor
 
 import "unsafe"
@@ -5,6 +7,9 @@ import "unsafe"
 // UnsafeString returns a string that shares the same underlying
 // memory as b. It must only be used in Trusted decode paths where
 // the backing buffer is immutable for the lifetime of the string.
+//
+// Building with the purego tag, or with TinyGo, replaces it with a
+// copying version so the package does not import unsafe.
 func UnsafeString(b []byte) string {
 	return *(*string)(unsafe.Pointer(&b))
 }
@@ -0,0 +1,14 @@
+//go:build purego || tinygo
+
+package cbor
+
+// UnsafeString returns b as a string. Without unsafe (the purego tag or
+// TinyGo) it copies b, so Trusted decoding allocates its strings like
+// the Safe path does.
+func UnsafeString(b []byte) string {
+	return string(b)
+}
+
+// UnsafeBytes returns the string as a byte slice. It is
+// equivalent to []byte(s) and retained for compatibility.
+func UnsafeBytes(s string) []byte { return []byte(s) }
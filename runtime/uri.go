@@ -0,0 +1,63 @@
+package cbor
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// InvalidURIError is returned when the text of a tag 32 (URI) item does
+// not parse as a URI reference.
+type InvalidURIError struct {
+	URI string
+	Err error
+}
+
+// Error implements error
+func (e InvalidURIError) Error() string {
+	return "cbor: invalid URI " + strconv.Quote(e.URI) + ": " + e.Err.Error()
+}
+
+// Unwrap returns the url.Parse error.
+func (e InvalidURIError) Unwrap() error { return e.Err }
+
+// Resumable returns 'true' for InvalidURIError
+func (e InvalidURIError) Resumable() bool { return true }
+
+// ReadValidURIStringBytes reads a tag(32) URI text string like
+// ReadURIStringBytes and also checks that it parses with url.Parse,
+// returning InvalidURIError otherwise.
+func ReadValidURIStringBytes(b []byte) (uri string, o []byte, err error) {
+	uri, o, err = ReadURIStringBytes(b)
+	if err != nil {
+		return "", b, err
+	}
+	if _, err := url.Parse(uri); err != nil {
+		return "", b, InvalidURIError{URI: uri, Err: err}
+	}
+	return uri, o, nil
+}
+
+// AppendURL appends u as a tag(32) URI text string, or null when u is nil.
+func AppendURL(b []byte, u *url.URL) []byte {
+	if u == nil {
+		return AppendNil(b)
+	}
+	return AppendURI(b, u.String())
+}
+
+// ReadURLBytes reads a tag(32) URI text string and parses it with
+// url.Parse. A null or undefined yields a nil URL.
+func ReadURLBytes(b []byte) (u *url.URL, o []byte, err error) {
+	if IsNilOrUndefined(b) {
+		return nil, b[1:], nil
+	}
+	uri, o, err := ReadURIStringBytes(b)
+	if err != nil {
+		return nil, b, err
+	}
+	u, err = url.Parse(uri)
+	if err != nil {
+		return nil, b, InvalidURIError{URI: uri, Err: err}
+	}
+	return u, o, nil
+}
@@ -6,3 +6,8 @@ import "unicode/utf8"
 // architecture-specific, SIMD-accelerated implementations via build tags.
 var isUTF8Valid = func(b []byte) bool { return utf8.Valid(b) }
 
+// textUTF8OK reports whether the bytes of a text string may be decoded:
+// they are valid UTF-8, or validation is off through AllowInvalidUTF8.
+func textUTF8OK(v []byte) bool {
+	return AllowInvalidUTF8 || !ValidateUTF8OnDecode || isUTF8Valid(v)
+}
@@ -31,20 +31,16 @@ func validateWellFormed(b []byte, depth int) ([]byte, error) {
 	if len(b) < 1 {
 		return b, ErrShortBytes
 	}
-	lead := b[0]
-	major := getMajorType(lead)
-	add := getAddInfo(lead)
-
-	// Reserved additional info values 28, 29, 30 are not well-formed
-	if add == 28 || add == 29 || add == 30 {
-		return b, InvalidPrefixError{Want: major, Got: major}
+	// ReadHead rejects the reserved additional info values 28, 29, 30.
+	major, add, _, o, err := ReadHead(b)
+	if err != nil {
+		return b, err
 	}
 
 	switch major {
 	case majorTypeUint, majorTypeNegInt, majorTypeTag:
-		_, o, err := readUintCore(b, major)
-		if err != nil {
-			return b, err
+		if add == addInfoIndefinite {
+			return b, ErrMalformedHead
 		}
 		if major == majorTypeTag {
 			return validateWellFormed(o, depth+1)
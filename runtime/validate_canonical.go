@@ -0,0 +1,144 @@
+package cbor
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// CanonicalError reports the first place an item departs from the
+// deterministic encoding ValidateCanonical checks for.
+type CanonicalError struct {
+	// Offset is the byte offset of the offending head within the input.
+	Offset int
+	Msg    string
+}
+
+func (e *CanonicalError) Error() string {
+	return "cbor: not canonical at offset " + strconv.Itoa(e.Offset) + ": " + e.Msg
+}
+
+// ValidateCanonical reports whether b holds exactly one well-formed item
+// in deterministic encoding (RFC 8949 §4.2.1): every integer, length and
+// tag number uses its shortest head, lengths are definite, map keys are
+// unique and sorted per MapKeyOrder, and floats use the shortest width
+// that preserves their value. A malformed item yields the error of
+// ValidateWellFormedBytes; any other violation a *CanonicalError locating
+// the first one.
+func ValidateCanonical(b []byte) error {
+	rest, err := ValidateWellFormedBytes(b)
+	if err != nil {
+		return err
+	}
+	if len(rest) > 0 {
+		return &CanonicalError{Offset: len(b) - len(rest), Msg: fmt.Sprintf("%d trailing bytes after the item", len(rest))}
+	}
+	_, err = canonicalItem(b, 0)
+	return err
+}
+
+// canonicalItem checks the well-formed item starting at b[off] and returns
+// the offset just past it.
+func canonicalItem(b []byte, off int) (int, error) {
+	major, info, arg, rest, _ := ReadHead(b[off:])
+	next := len(b) - len(rest)
+	fail := func(format string, args ...any) (int, error) {
+		return off, &CanonicalError{Offset: off, Msg: fmt.Sprintf(format, args...)}
+	}
+
+	if major == majorTypeSimple {
+		switch info {
+		case addInfoUint8:
+			if arg < 32 {
+				return fail("simple value %d must use the one-byte form", arg)
+			}
+		case simpleFloat16, simpleFloat32, simpleFloat64:
+			var f float64
+			switch info {
+			case simpleFloat16:
+				f = float64(float16BitsToFloat32(uint16(arg)))
+			case simpleFloat32:
+				f = float64(math.Float32frombits(uint32(arg)))
+			default:
+				f = math.Float64frombits(arg)
+			}
+			want := []byte{makeByte(majorTypeSimple, simpleFloat16), 0x80, 0x00}
+			if f != 0 || !math.Signbit(f) {
+				// AppendFloatCanonical folds -0 into 0, which changes the value.
+				want = AppendFloatCanonical(nil, f)
+			}
+			if string(b[off:next]) != string(want) {
+				return fail("float %v is %d bytes; preferred form is %x", f, next-off, want)
+			}
+		case addInfoIndefinite:
+			return fail("unexpected break")
+		}
+		return next, nil
+	}
+
+	if info == addInfoIndefinite {
+		return fail("indefinite length on major type %s", majorTypeName(major))
+	}
+	if info >= addInfoUint8 {
+		if short := headSize(arg); short < next-off {
+			return fail("head of major type %s with argument %d is %d bytes; shortest is %d",
+				majorTypeName(major), arg, next-off, short)
+		}
+	}
+
+	var err error
+	switch major {
+	case majorTypeBytes, majorTypeText:
+		next += int(arg)
+	case majorTypeArray:
+		for i := uint64(0); i < arg && err == nil; i++ {
+			next, err = canonicalItem(b, next)
+		}
+	case majorTypeMap:
+		var prev []byte
+		for i := uint64(0); i < arg && err == nil; i++ {
+			start := next
+			if next, err = canonicalItem(b, next); err != nil {
+				break
+			}
+			key := b[start:next]
+			if i > 0 {
+				if c := MapKeyOrder.Compare(prev, key); c >= 0 {
+					reason := "duplicate map key " + diagOrHex(key)
+					if c > 0 {
+						reason = fmt.Sprintf("map key %s sorts before %s under %v", diagOrHex(key), diagOrHex(prev), MapKeyOrder)
+					}
+					return start, &CanonicalError{Offset: start, Msg: reason}
+				}
+			}
+			prev = key
+			next, err = canonicalItem(b, next)
+		}
+	case majorTypeTag:
+		next, err = canonicalItem(b, next)
+	}
+	return next, err
+}
+
+// headSize returns the length of the shortest head carrying arg.
+func headSize(arg uint64) int {
+	switch {
+	case arg < addInfoUint8:
+		return 1
+	case arg <= math.MaxUint8:
+		return 2
+	case arg <= math.MaxUint16:
+		return 3
+	case arg <= math.MaxUint32:
+		return 5
+	}
+	return 9
+}
+
+// diagOrHex renders the encoded item b for an error message.
+func diagOrHex(b []byte) string {
+	if d, _, err := DiagBytes(b); err == nil {
+		return d
+	}
+	return string(AppendDiagBytes(nil, b))
+}
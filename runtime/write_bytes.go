@@ -1,11 +1,12 @@
 package cbor
 
 import (
-	"bytes"
 	"encoding/binary"
 	"encoding/json"
 	"math"
 	bigmath "math/big"
+	"net"
+	"net/netip"
 	"regexp"
 	"sort"
 	"time"
@@ -89,19 +90,56 @@ func AppendTextChunk(b []byte, s string) []byte { return AppendString(b, s) }
 // AppendBytesChunk appends a definite-length byte string chunk (use within indefinite bytes)
 func AppendBytesChunk(b []byte, bs []byte) []byte { return AppendBytes(b, bs) }
 
-// AppendFloat64 appends a float64
+// Half precision encodings of the non-finite values (RFC 8949 §4.2.2).
+const (
+	float16NaN    = 0x7e00
+	float16PosInf = 0x7c00
+	float16NegInf = 0xfc00
+
+	float64ExpMask = 0x7ff << 52
+	float32ExpMask = 0xff << 23
+)
+
+// appendNonFinite appends NaN or ±Inf in the canonical half precision
+// form: f97e00 for every NaN (payload and sign are dropped) and
+// f97c00/f9fc00 for ±Inf.
+func appendNonFinite(b []byte, f float64) []byte {
+	h := uint16(float16NaN)
+	if !math.IsNaN(f) {
+		h = float16PosInf
+		if f < 0 {
+			h = float16NegInf
+		}
+	}
+	o, n := ensure(b, 3)
+	o[n] = makeByte(majorTypeSimple, simpleFloat16)
+	binary.BigEndian.PutUint16(o[n+1:], h)
+	return o
+}
+
+// AppendFloat64 appends a float64. NaN and ±Inf are written in their
+// canonical half precision form.
 func AppendFloat64(b []byte, f float64) []byte {
+	u := math.Float64bits(f)
+	if u&float64ExpMask == float64ExpMask {
+		return appendNonFinite(b, f)
+	}
 	o, n := ensure(b, 9)
 	o[n] = makeByte(majorTypeSimple, simpleFloat64)
-	binary.BigEndian.PutUint64(o[n+1:], math.Float64bits(f))
+	binary.BigEndian.PutUint64(o[n+1:], u)
 	return o
 }
 
-// AppendFloat32 appends a float32
+// AppendFloat32 appends a float32. NaN and ±Inf are written in their
+// canonical half precision form.
 func AppendFloat32(b []byte, f float32) []byte {
+	u := math.Float32bits(f)
+	if u&float32ExpMask == float32ExpMask {
+		return appendNonFinite(b, float64(f))
+	}
 	o, n := ensure(b, 5)
 	o[n] = makeByte(majorTypeSimple, simpleFloat32)
-	binary.BigEndian.PutUint32(o[n+1:], math.Float32bits(f))
+	binary.BigEndian.PutUint32(o[n+1:], u)
 	return o
 }
 
@@ -109,9 +147,9 @@ func AppendFloat32(b []byte, f float32) []byte {
 func AppendFloatCanonical(b []byte, f float64) []byte {
     // Normalize -0 to +0 for canonical
     if f == 0 && math.Signbit(f) { f = 0 }
-    // NaN: canonicalize to float16 NaN
-    if math.IsNaN(f) {
-        return AppendFloat16(b, float32(f))
+    // NaN and ±Inf: canonical float16 forms
+    if math.IsNaN(f) || math.IsInf(f, 0) {
+        return appendNonFinite(b, f)
     }
     // Try f16
     f16 := float32ToFloat16Bits(float32(f))
@@ -148,6 +186,13 @@ func AppendDuration(b []byte, d time.Duration) []byte {
 	return AppendInt64(b, int64(d))
 }
 
+// AppendDurationUnit appends a time.Duration as an integer count of unit,
+// truncating any remainder (e.g. unit time.Millisecond writes 1500 for
+// 1.5s).
+func AppendDurationUnit(b []byte, d time.Duration, unit time.Duration) []byte {
+	return AppendInt64(b, int64(d/unit))
+}
+
 // AppendInt64 appends an int64 using canonical CBOR integer encoding.
 //
 // For small values in the common ranges we specialize the encoding
@@ -338,16 +383,22 @@ func AppendSimpleValue(b []byte, val uint8) []byte {
 	}
 }
 
-// AppendTime appends a time.Time as CBOR tag 1 (epoch timestamp)
-func AppendTime(b []byte, t time.Time) []byte {
+// AppendTimeFloat appends t as CBOR tag 1 with a float64 count of seconds
+// since the Unix epoch, even when t has no fractional second. It backs
+// the tag=1,float field option. float64 holds 53 bits, so ReadTimeBytes
+// gets back the exact nanosecond only within about 97 days of 1970; a
+// present-day time comes back within 120ns, and the error doubles each
+// time the distance from 1970 does. Re-encoding the decoded time writes
+// the same float.
+func AppendTimeFloat(b []byte, t time.Time) []byte {
 	b = AppendTag(b, tagEpochDateTime)
-	sec := t.Unix()
-	nsec := t.Nanosecond()
-	if nsec == 0 {
-		return AppendInt64(b, sec)
-	}
-	f := float64(sec) + float64(nsec)/1e9
-	return AppendFloat64(b, f)
+	return AppendFloat64(b, EpochSeconds(t))
+}
+
+// EpochSeconds returns t as seconds since the Unix epoch, the value
+// AppendTimeFloat writes.
+func EpochSeconds(t time.Time) float64 {
+	return float64(t.Unix()) + float64(t.Nanosecond())/1e9
 }
 
 // AppendTag appends a generic semantic tag
@@ -361,9 +412,16 @@ func AppendTagged(b []byte, tag uint64, value []byte) []byte {
 	return append(b, value...)
 }
 
-// AppendRFC3339Time appends a tag(0) RFC3339 datetime string
+// AppendRFC3339Time appends a tag(0) RFC3339 datetime string. The text
+// keeps t's UTC offset, so ReadRFC3339TimeBytes returns the same instant
+// at the same offset. RFC 3339 offsets are whole minutes: a time whose
+// offset has seconds, such as a historical local mean time, is written in
+// UTC so the instant survives. With TimeUTC set every time is.
 func AppendRFC3339Time(b []byte, t time.Time) []byte {
     b = AppendTag(b, tagDateTimeString)
+    if _, off := t.Zone(); TimeUTC || off%60 != 0 {
+        t = t.UTC()
+    }
     return AppendString(b, t.Format(time.RFC3339Nano))
 }
 
@@ -644,17 +702,16 @@ func AppendPtrMarshaler[T any](b []byte, v *T) ([]byte, error) {
 // generated code (cborgen) to avoid per-element AppendInterface overhead.
 //
 // T may be a type that itself implements Marshaler or whose pointer type
-// implements Marshaler (the common case for generated methods).
+// implements Marshaler (the common case for generated methods). Each
+// element is reached through its address, whose method set covers both,
+// so no element is copied into an interface and encoding does not
+// allocate.
 func AppendSliceMarshaler[T any](b []byte, v []T) ([]byte, error) {
 	b = AppendArrayHeader(b, uint32(len(v)))
 	var err error
 	for i := range v {
-		var m Marshaler
-		if mm, ok := any(v[i]).(Marshaler); ok {
-			m = mm
-		} else if mm, ok := any(&v[i]).(Marshaler); ok {
-			m = mm
-		} else {
+		m, ok := any(&v[i]).(Marshaler)
+		if !ok {
 			return b, &ErrUnsupportedType{}
 		}
 		b, err = m.MarshalCBOR(b)
@@ -665,11 +722,20 @@ func AppendSliceMarshaler[T any](b []byte, v []T) ([]byte, error) {
 	return b, nil
 }
 
-// AppendInterface appends an arbitrary value
+// AppendInterface appends an arbitrary value. Values whose type was
+// registered with RegisterType are wrapped in their CBOR tag.
 func AppendInterface(b []byte, i any) ([]byte, error) {
 	if i == nil {
 		return AppendNil(b), nil
 	}
+	if rt := lookupRegisteredValue(i); rt != nil {
+		return rt.encode(AppendTag(b, rt.tag), i)
+	}
+	if codecsInUse.Load() {
+		if o, ok, err := appendCodec(b, reflect.ValueOf(i)); ok {
+			return o, err
+		}
+	}
 
 	switch v := i.(type) {
 	case Marshaler:
@@ -702,12 +768,22 @@ func AppendInterface(b []byte, i any) ([]byte, error) {
 		return AppendFloat32(b, v), nil
 	case float64:
 		return AppendFloat64(b, v), nil
+	case complex64:
+		return AppendComplex64(b, v), nil
+	case complex128:
+		return AppendComplex128(b, v), nil
 	case []byte:
 		return AppendBytes(b, v), nil
 	case time.Time:
 		return AppendTime(b, v), nil
 	case time.Duration:
 		return AppendDuration(b, v), nil
+	case net.IP:
+		return AppendIP(b, v), nil
+	case netip.Addr:
+		return AppendAddr(b, v), nil
+	case netip.AddrPort:
+		return AppendAddrPort(b, v), nil
 	case []int:
 		b = AppendArrayHeader(b, uint32(len(v)))
 		for _, elem := range v { b = AppendInt(b, elem) }
@@ -801,6 +877,8 @@ func AppendInterface(b []byte, i any) ([]byte, error) {
 	case json.RawMessage:
 		// Treat RawMessage as an opaque CBOR byte string.
 		return AppendBytes(b, []byte(v)), nil
+	case Number:
+		return v.MarshalCBOR(b)
 	case json.Number:
 		if iv, err := v.Int64(); err == nil {
 			return AppendInt64(b, iv), nil
@@ -825,7 +903,7 @@ func AppendInterface(b []byte, i any) ([]byte, error) {
 		// Fallback: handle slices and maps of Marshaler types via reflection.
 		rv := reflect.ValueOf(i)
 		t := rv.Type()
-		if rv.Kind() == reflect.Slice {
+		if rv.Kind() == reflect.Slice && (t.Elem().Implements(marshalerType) || reflect.PointerTo(t.Elem()).Implements(marshalerType)) {
 			b = AppendArrayHeader(b, uint32(rv.Len()))
 			for idx := 0; idx < rv.Len(); idx++ {
 				val := rv.Index(idx)
@@ -835,7 +913,7 @@ func AppendInterface(b []byte, i any) ([]byte, error) {
 					m, ok = val.Addr().Interface().(Marshaler)
 				}
 				if !ok {
-					return b, &ErrUnsupportedType{}
+					return b, &ErrUnsupportedType{T: val.Type()}
 				}
 				var err error
 				b, err = m.MarshalCBOR(b)
@@ -845,7 +923,7 @@ func AppendInterface(b []byte, i any) ([]byte, error) {
 			}
 			return b, nil
 		}
-		if rv.Kind() == reflect.Map {
+		if rv.Kind() == reflect.Map && intOrStringKind(t.Key().Kind()) {
 			keyKind := t.Key().Kind()
 			keys := rv.MapKeys()
 			b = AppendMapHeader(b, uint32(len(keys)))
@@ -896,11 +974,24 @@ func AppendInterface(b []byte, i any) ([]byte, error) {
 			}
 			return b, nil
 		}
-		return b, &ErrUnsupportedType{}
+		// Anything else, such as a struct of another package or a slice
+		// of times, is encoded as Marshal would.
+		return appendReflect(b, rv, 0)
+	}
+}
+
+// intOrStringKind reports whether k is a string or integer kind, the map
+// key kinds AppendInterface writes without reflection.
+func intOrStringKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.String, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
 	}
+	return false
 }
 
-// AppendMapStrStrDeterministic appends a map[string]string with keys sorted by encoded key bytes.
+// AppendMapStrStrDeterministic appends a map[string]string with keys sorted by encoded key bytes per MapKeyOrder.
 func AppendMapStrStrDeterministic(b []byte, m map[string]string) []byte {
 	sz := uint32(len(m))
 	b = AppendMapHeader(b, sz)
@@ -912,7 +1003,7 @@ func AppendMapStrStrDeterministic(b []byte, m map[string]string) []byte {
 	for k := range m {
 		arr = append(arr, kv{key: k, enc: AppendString(nil, k)})
 	}
-	sort.Slice(arr, func(i, j int) bool { return bytes.Compare(arr[i].enc, arr[j].enc) < 0 })
+	sort.Slice(arr, func(i, j int) bool { return MapKeyOrder.Compare(arr[i].enc, arr[j].enc) < 0 })
 	for _, it := range arr {
 		b = AppendString(b, it.key)
 		b = AppendString(b, m[it.key])
@@ -920,7 +1011,7 @@ func AppendMapStrStrDeterministic(b []byte, m map[string]string) []byte {
 	return b
 }
 
-// AppendMapStrInterfaceDeterministic appends a map[string]any with keys sorted by encoded key bytes.
+// AppendMapStrInterfaceDeterministic appends a map[string]any with keys sorted by encoded key bytes per MapKeyOrder.
 func AppendMapStrInterfaceDeterministic(b []byte, m map[string]any) ([]byte, error) {
 	sz := uint32(len(m))
 	b = AppendMapHeader(b, sz)
@@ -932,7 +1023,7 @@ func AppendMapStrInterfaceDeterministic(b []byte, m map[string]any) ([]byte, err
 	for k := range m {
 		arr = append(arr, kv{key: k, enc: AppendString(nil, k)})
 	}
-	sort.Slice(arr, func(i, j int) bool { return bytes.Compare(arr[i].enc, arr[j].enc) < 0 })
+	sort.Slice(arr, func(i, j int) bool { return MapKeyOrder.Compare(arr[i].enc, arr[j].enc) < 0 })
 	for _, it := range arr {
 		b = AppendString(b, it.key)
 		var err error
@@ -955,56 +1046,10 @@ func AppendBreak(b []byte) []byte {
 }
 
 // AppendRawMapDeterministic appends a map with entries provided as raw CBOR key/value pairs.
-// Pairs are sorted by CBOR-encoded key bytes to ensure RFC 8949 deterministic order.
+// Pairs are sorted by CBOR-encoded key bytes per MapKeyOrder.
 func AppendRawMapDeterministic(b []byte, pairs []RawPair) []byte {
-    // Deterministic order: by encoded key length, then bytewise lexicographic.
-    n := len(pairs)
-    if n == 0 {
-        return AppendMapHeader(b, 0)
-    }
-    // Bucket indices by key length.
-    byLen := make(map[int][]int)
-    for i := 0; i < n; i++ {
-        l := len(pairs[i].Key)
-        byLen[l] = append(byLen[l], i)
-    }
-    lens := make([]int, 0, len(byLen))
-    for l := range byLen { lens = append(lens, l) }
-    sort.Ints(lens)
-    order := make([]int, 0, n)
-    counts := make([]int, 256)
-    var tmp []int
-    for _, l := range lens {
-        grp := byLen[l]
-        if len(grp) <= 1 {
-            order = append(order, grp...)
-            continue
-        }
-        // Adaptive: comparator is faster for smaller groups/short keys.
-        if l < 64 && len(grp) < 1024 {
-            sort.Slice(grp, func(i, j int) bool { return bytes.Compare(pairs[grp[i]].Key, pairs[grp[j]].Key) < 0 })
-            order = append(order, grp...)
-            continue
-        }
-        if cap(tmp) < len(grp) { tmp = make([]int, len(grp)) } else { tmp = tmp[:len(grp)] }
-        cur := grp
-        aux := tmp
-        for pos := l - 1; pos >= 0; pos-- {
-            for i := range counts { counts[i] = 0 }
-            for _, idx := range cur { counts[int(pairs[idx].Key[pos])]++ }
-            sum := 0
-            for i := 0; i < 256; i++ { c := counts[i]; counts[i] = sum; sum += c }
-            for _, idx := range cur {
-                bv := pairs[idx].Key[pos]
-                p := counts[int(bv)]
-                aux[p] = idx
-                counts[int(bv)] = p + 1
-            }
-            cur, aux = aux, cur
-        }
-        order = append(order, cur...)
-    }
-    b = AppendMapHeader(b, uint32(n))
+    order := sortedKeyIndexes(len(pairs), func(i int) []byte { return pairs[i].Key })
+    b = AppendMapHeader(b, uint32(len(pairs)))
     for _, i := range order {
         b = append(b, pairs[i].Key...)
         b = append(b, pairs[i].Value...)
@@ -1015,7 +1060,7 @@ func AppendRawMapDeterministic(b []byte, pairs []RawPair) []byte {
 // AppendMapDeterministic appends a map[K]V deterministically.
 // encKey appends the CBOR encoding of key k to dst and returns the extended dst.
 // encVal appends the CBOR encoding of value v to dst and returns the extended dst.
-// Keys are encoded once for sorting and then reused to avoid re-encoding.
+// Keys are encoded once for sorting (per MapKeyOrder) and then reused to avoid re-encoding.
 func AppendMapDeterministic[K comparable, V any](b []byte, m map[K]V,
     encKey func(dst []byte, k K) []byte,
     encVal func(dst []byte, v V) ([]byte, error),
@@ -1037,42 +1082,7 @@ func AppendMapDeterministic[K comparable, V any](b []byte, m map[K]V,
         ke := scratch[prev:]
         items = append(items, item{keyEnc: ke, key: k, val: v})
     }
-    // Bucket by encoded key length, then LSD radix within groups.
-    byLen := make(map[int][]int)
-    for i := range items { byLen[len(items[i].keyEnc)] = append(byLen[len(items[i].keyEnc)], i) }
-    lens := make([]int, 0, len(byLen))
-    for l := range byLen { lens = append(lens, l) }
-    sort.Ints(lens)
-    order := make([]int, 0, len(items))
-    counts := make([]int, 256)
-    var tmpIdx []int
-    for _, l := range lens {
-        grp := byLen[l]
-        if len(grp) <= 1 { order = append(order, grp...); continue }
-        // Adaptive: comparator wins for small groups/short keys
-        if l < 64 && len(grp) < 1024 {
-            sort.Slice(grp, func(i, j int) bool { return bytes.Compare(items[grp[i]].keyEnc, items[grp[j]].keyEnc) < 0 })
-            order = append(order, grp...)
-            continue
-        }
-        if cap(tmpIdx) < len(grp) { tmpIdx = make([]int, len(grp)) } else { tmpIdx = tmpIdx[:len(grp)] }
-        cur := grp
-        aux := tmpIdx
-        for pos := l - 1; pos >= 0; pos-- {
-            for i := range counts { counts[i] = 0 }
-            for _, idx := range cur { counts[int(items[idx].keyEnc[pos])]++ }
-            sum := 0
-            for i := 0; i < 256; i++ { c := counts[i]; counts[i] = sum; sum += c }
-            for _, idx := range cur {
-                bv := items[idx].keyEnc[pos]
-                p := counts[int(bv)]
-                aux[p] = idx
-                counts[int(bv)] = p + 1
-            }
-            cur, aux = aux, cur
-        }
-        order = append(order, cur...)
-    }
+    order := sortedKeyIndexes(len(items), func(i int) []byte { return items[i].keyEnc })
     b = AppendMapHeader(b, uint32(len(items)))
     var err error
     for _, oi := range order {
@@ -0,0 +1,50 @@
+package cborgen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNestedTypesCallAppendCBOR(t *testing.T) {
+	src := "type Inner struct{ N int }\n" +
+		"type Outer struct {\n" +
+		"\tA Inner\n" +
+		"\tB *Inner\n" +
+		"\tC []Inner\n" +
+		"\tD time.Duration\n" +
+		"}\n"
+	code, err := generate(t, src)
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	for _, want := range []string{
+		"func (x *Outer) AppendCBOR(b []byte) ([]byte, error) {",
+		"func (x *Outer) MarshalCBOR(b []byte) ([]byte, error) {\n\treturn x.AppendCBOR(b)\n}",
+		"x.A.AppendCBOR(b)",
+		"x.B.AppendCBOR(b)",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code missing %q", want)
+		}
+	}
+	if strings.Contains(code, "x.A.MarshalCBOR(") || strings.Contains(code, "AppendPtrMarshaler(b, x.B)") {
+		t.Errorf("nested generated type encoded through MarshalCBOR:\n%s", code)
+	}
+}
+
+func TestInterfaceAssertions(t *testing.T) {
+	code, err := generate(t, "type Tags []string\ntype Item struct{ T Tags }\n")
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	for _, want := range []string{
+		"_ cbor.Marshaler   = (*Item)(nil)",
+		"_ cbor.Unmarshaler = (*Item)(nil)",
+		"_ cbor.Marshaler   = (*Tags)(nil)",
+		"_ cbor.Unmarshaler = (*Tags)(nil)",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code missing %q", want)
+		}
+	}
+}
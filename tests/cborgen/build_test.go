@@ -0,0 +1,71 @@
+package cborgen
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/delaneyj/cbor/cborgen/core"
+)
+
+func TestBuildTags(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, src string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	in := write("types.go", "package types\n\ntype Code uint16\n\ntype T struct {\n\tC Code\n}\n")
+	// Code has its own codec only in "pro" builds.
+	write("code_pro.go", "//go:build pro\n\npackage types\n\n"+
+		"func (c Code) MarshalCBOR(b []byte) ([]byte, error) { return b, nil }\n")
+	pro := write("extra.go", "//go:build pro && !lite\n\npackage types\n\ntype Extra struct {\n\tN int\n}\n")
+
+	gen := func(in string, tags ...string) (string, error) {
+		out := strings.TrimSuffix(in, ".go") + "_cbor.go"
+		if err := core.Run(in, out, core.Options{Tags: tags}); err != nil {
+			return "", err
+		}
+		b, err := os.ReadFile(out)
+		return string(b), err
+	}
+
+	code, err := gen(in)
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	if !strings.Contains(code, "func (x *Code) AppendCBOR") {
+		t.Error("without tags, Code lacks generated methods")
+	}
+	code, err = gen(in, "pro")
+	if err != nil {
+		t.Fatalf("Run with pro error: %v", err)
+	}
+	if strings.Contains(code, "func (x *Code) AppendCBOR") {
+		t.Error("with pro, generated methods clash with code_pro.go")
+	}
+
+	// A constrained input keeps its constraint in the generated file.
+	code, err = gen(pro, "pro")
+	if err != nil {
+		t.Fatalf("Run on extra.go error: %v", err)
+	}
+	if !strings.Contains(code, "//go:build pro && !lite\n\npackage types") {
+		t.Errorf("generated code lacks the build constraint:\n%s", code[:min(len(code), 200)])
+	}
+	for _, tags := range [][]string{nil, {"pro", "lite"}} {
+		if _, err := gen(pro, tags...); err == nil || !strings.Contains(err.Error(), "excluded by build constraints") {
+			t.Errorf("tags %v: error = %v, want exclusion", tags, err)
+		}
+	}
+
+	if got := core.ParseTags("pro,lite"); len(got) != 2 || got[1] != "lite" {
+		t.Errorf("ParseTags(comma) = %q", got)
+	}
+	if got := core.ParseTags("pro lite"); len(got) != 2 || got[0] != "pro" {
+		t.Errorf("ParseTags(space) = %q", got)
+	}
+}
@@ -0,0 +1,52 @@
+package cborgen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInstantiateDirectiveErrors(t *testing.T) {
+	const decls = "type Box[T any] struct {\n\tV T\n}\n\n" +
+		"type List[T any] []T\n\n" +
+		"type Plain struct {\n\tV int\n}\n\n"
+	tests := []struct {
+		directive string
+		want      string
+	}{
+		{"Box", "want a generic type with type arguments, e.g. Box[int]"},
+		{"time.Box[int]", "want a generic type of this package, not time.Box"},
+		{"Plain[int]", "Plain is not a generic type declared in this file"},
+		{"List[int]", "List is not a struct type"},
+		{"Box[int, string]", "Box has 1 type parameters, not 2"},
+	}
+	for _, tc := range tests {
+		_, err := generate(t, "//cbor:instantiate "+tc.directive+"\n"+decls)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: error = %v, want %q", tc.directive, err, tc.want)
+		}
+	}
+
+	_, err := generate(t, "//cbor:instantiate Box[int]\n//cbor:instantiate Box[int]\n"+decls)
+	if want := "duplicate instantiation"; err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("error = %v, want %q", err, want)
+	}
+}
+
+func TestInstantiateDirective(t *testing.T) {
+	code, err := generate(t, "type Box[T any] struct {\n\tV T\n}\n\n"+
+		"type Other[T any] struct {\n\tV T\n}\n\n"+
+		"//cbor:instantiate Box[int]\n"+
+		"type Holder struct {\n\tB []Box[int]\n}\n")
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	for _, want := range []string{"type cborBoxInt Box[int]", "func (x *Box[T]) AppendCBOR(", "case *Box[int]:", "x.B[i].AppendCBOR(b)"} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code lacks %s", want)
+		}
+	}
+	// A generic struct without directives gets no code.
+	if strings.Contains(code, "Other[") {
+		t.Error("generated code for Other, which has no //cbor:instantiate directive")
+	}
+}
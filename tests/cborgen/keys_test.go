@@ -0,0 +1,36 @@
+package cborgen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestKeyAsIntDuplicates(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{"1", "01", "T: fields A and B both use CBOR key 1"},
+		{"-5", "-05", "T: fields A and B both use CBOR key -5"},
+		{"0", "-0", "T: fields A and B both use CBOR key 0"},
+	}
+	for _, tc := range tests {
+		src := "type T struct {\n" +
+			"\tA int `cbor:\"" + tc.a + ",keyasint\"`\n" +
+			"\tB int `cbor:\"" + tc.b + ",keyasint\"`\n" +
+			"}\n"
+		_, err := generate(t, src)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s/%s: error = %v, want %q", tc.a, tc.b, err, tc.want)
+		}
+	}
+
+	// A negative key and its absolute value are distinct keys.
+	code, err := generate(t, "type T struct {\n\tA int `cbor:\"5,keyasint\"`\n\tB int `cbor:\"-5,keyasint\"`\n}\n")
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	if !strings.Contains(code, "case -5:") || !strings.Contains(code, "cbor.AppendInt64(b, -5)") {
+		t.Fatalf("negative key not generated:\n%s", code)
+	}
+}
@@ -0,0 +1,101 @@
+package cborgen
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/delaneyj/cbor/cborgen/core"
+)
+
+func TestNamedScalarTypes(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "types.go")
+	out := filepath.Join(dir, "types_cbor.go")
+	src := "package types\n\n" +
+		"type Level int8\n\n" +
+		"type Code uint16\n\n" +
+		"type Grade = uint8\n\n" +
+		"type T struct {\n\tL Level\n\tC Code\n\tG Grade\n}\n"
+	// Code brings its own methods, in another file of the package.
+	codec := "package types\n\n" +
+		"func (c Code) MarshalCBOR(b []byte) ([]byte, error) { return b, nil }\n"
+	if err := os.WriteFile(in, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "code.go"), []byte(codec), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := core.Run(in, out, core.Options{}); err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	b, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := string(b)
+	for _, want := range []string{"func (x *Level) AppendCBOR", "cbor.AppendInt8(b, int8(*x))", "*x = Level(tmp)", "cbor.AppendUint8(b, x.G)"} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code lacks %s", want)
+		}
+	}
+	for _, unwanted := range []string{"func (x *Code)", "func (x *Grade)"} {
+		if strings.Contains(code, unwanted) {
+			t.Errorf("generated code has %s", unwanted)
+		}
+	}
+}
+
+func TestImportsOfOtherPackages(t *testing.T) {
+	cases := []struct {
+		name, src string
+		want      []string
+	}{
+		{
+			// Another package named cbor takes the name; the runtime
+			// moves aside.
+			"collision",
+			"import (\n\t\"time\"\n\n\t\"github.com/fxamacker/cbor/v2\"\n)\n\n" +
+				"type T struct {\n\tA string\n\tAt time.Time\n\tM cbor.RawMessage\n}\n",
+			[]string{`cborrt "github.com/delaneyj/cbor/runtime"`, "cborrt.AppendString(b, x.A)", "cborrt.AppendTime(b, x.At)"},
+		},
+		{
+			// The runtime keeps the alias the source gives it.
+			"runtime alias",
+			"import rt \"github.com/delaneyj/cbor/runtime\"\n\n" +
+				"type T struct {\n\tA string\n\tR rt.RawMessage\n}\n",
+			[]string{`rt "github.com/delaneyj/cbor/runtime"`, "rt.AppendString(b, x.A)", "x.R.MarshalCBOR(b)"},
+		},
+		{
+			// Aliased standard packages are still recognized.
+			"std alias",
+			"import (\n\tstdtime \"time\"\n\tip \"net/netip\"\n)\n\n" +
+				"type T struct {\n\tAt stdtime.Time\n\tAddr ip.Addr `cbor:\",omitempty\"`\n}\n",
+			[]string{"\"net/netip\"", "cbor.AppendTime(b, x.At)", "cbor.AppendAddr(b, x.Addr)", "x.Addr == (netip.Addr{})"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			in := filepath.Join(dir, "types.go")
+			out := filepath.Join(dir, "types_cbor.go")
+			if err := os.WriteFile(in, []byte("package types\n\n"+tc.src), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if err := core.Run(in, out, core.Options{}); err != nil {
+				t.Fatalf("Run error: %v", err)
+			}
+			b, err := os.ReadFile(out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			code := string(b)
+			for _, want := range tc.want {
+				if !strings.Contains(code, want) {
+					t.Errorf("generated code lacks %s", want)
+				}
+			}
+		})
+	}
+}
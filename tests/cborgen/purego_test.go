@@ -0,0 +1,52 @@
+package cborgen
+
+import (
+	"go/build"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestRuntimeAvoidsUnsafe(t *testing.T) {
+	// TinyGo sets the tinygo tag; purego asks for the same on gc.
+	for _, tag := range []string{"purego", "tinygo"} {
+		ctx := build.Default
+		ctx.BuildTags = []string{tag}
+		pkg, err := ctx.ImportDir(filepath.Join("..", "..", "runtime"), 0)
+		if err != nil {
+			t.Fatalf("%s: %v", tag, err)
+		}
+		if slices.Contains(pkg.Imports, "unsafe") {
+			t.Errorf("runtime imports unsafe with the %s tag", tag)
+		}
+	}
+}
+
+func TestGeneratedCodeAvoidsReflectAndUnsafe(t *testing.T) {
+	var files []string
+	for _, dir := range []string{"structs", "jetstreammeta"} {
+		m, err := filepath.Glob(filepath.Join("..", dir, "*_cbor.go"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, m...)
+	}
+	if len(files) == 0 {
+		t.Fatal("no generated files found")
+	}
+	fset := token.NewFileSet()
+	for _, path := range files {
+		f, err := parser.ParseFile(fset, path, nil, parser.ImportsOnly)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, imp := range f.Imports {
+			if p, _ := strconv.Unquote(imp.Path.Value); p == "reflect" || p == "unsafe" {
+				t.Errorf("%s imports %s", path, p)
+			}
+		}
+	}
+}
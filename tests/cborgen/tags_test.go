@@ -0,0 +1,175 @@
+package cborgen
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/delaneyj/cbor/cborgen/core"
+)
+
+// generate runs the generator over a file holding src and returns the
+// generated code.
+func generate(t *testing.T, src string) (string, error) {
+	t.Helper()
+	dir := t.TempDir()
+	in := filepath.Join(dir, "types.go")
+	out := filepath.Join(dir, "types_cbor.go")
+	if err := os.WriteFile(in, []byte("package types\n\nimport \"time\"\n\nvar _ time.Duration\n\n"+src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := core.Run(in, out, core.Options{}); err != nil {
+		return "", err
+	}
+	code, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(code), nil
+}
+
+func TestTagOptionsAnyOrder(t *testing.T) {
+	code, err := generate(t, "type T struct {\n"+
+		"\tA int `cbor:\"1,omitempty,keyasint\"`\n"+
+		"\tB int `cbor:\"2,keyasint,,omitempty\"`\n"+
+		"\tC string `cbor:\",omitempty,tag=32\"`\n"+
+		"}\n")
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	for _, want := range []string{"cbor.AppendInt64(b, 1)", "cbor.AppendInt64(b, 2)", `cbor.AppendString(b, "C")`, "cbor.AppendURI(b, x.C)", "x.A == 0"} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code lacks %s", want)
+		}
+	}
+}
+
+func TestTagOptionErrors(t *testing.T) {
+	tests := []struct {
+		field string
+		want  string
+	}{
+		{"A int `cbor:\"a,omitempty,omitempty\"`", `T.A: duplicate tag option "omitempty"`},
+		{"A int `cbor:\"a,omitempy\"`", `T.A: unknown tag option "omitempy"`},
+		{"A int `cbor:\"a,omitempty=true\"`", `T.A: tag option "omitempty" takes no value`},
+		{"A int `cbor:\"a,tag=\"`", `T.A: tag option "tag" requires a value`},
+		{"A int `cbor:\"a,tag=1,tag=2\"`", `T.A: duplicate tag option "tag"`},
+		{"A string `cbor:\",omitempty,tag=37\"`", "T.A: tag=37 is not supported on string"},
+		{"A time.Time `cbor:\"a,float\"`", "T.A: float requires tag=1 on a time.Time field"},
+		{"A float64 `cbor:\"a,tag=1,float\"`", "T.A: float requires tag=1 on a time.Time field"},
+		{"A netip.AddrPort `cbor:\"a,tag=54\"`", "T.A: tag=54 is not supported on netip.AddrPort"},
+		{"A int `cbor:\"a,toarray\"`", "T.A: toarray, dense and presence belong on a _ field"},
+		{"A int `cbor:\"a,unit=ms\"`", "T.A: unit=ms requires a time.Duration field, not int"},
+		{"A time.Duration `cbor:\"a,unit=days\"`", "T.A: unit=days is not one of"},
+		{"_ struct{} `cbor:\",toarray,omitempty\"`\n\tA int", "T._: only toarray, dense and presence are allowed on a _ field"},
+		{"_ struct{} `cbor:\",presence\"`\n\tA int", "T: presence requires toarray or dense"},
+		{"_ struct{} `cbor:\",toarry\"`\n\tA int", `T._: unknown tag option "toarry"`},
+		{"A int `cbor:\"a,alias=\"`", `T.A: tag option "alias" requires a value`},
+		{"A int `cbor:\"a,alias=b,alias=b\"`", `T.A: duplicate alias "b"`},
+		{"A int `cbor:\"a,alias=a\"`", `T.A: duplicate alias "a"`},
+		{"A int `cbor:\"1,keyasint,alias=2\"`", "T.A: alias applies to text keys"},
+		{"A int `cbor:\"a,alias=b\"`\n\tB int `cbor:\"b\"`", `T: fields A and B both use CBOR key "b"`},
+		{"A string `cbor:\"a,string\"`", "T.A: string requires a number or bool field, not string"},
+		{"A *int `cbor:\"a,string\"`", "T.A: string requires a number or bool field, not *int"},
+		{"A int `cbor:\"a,string=1\"`", `T.A: tag option "string" takes no value`},
+		{"A int `cbor:\"a,boolasint\"`", "T.A: boolasint requires a bool field, not int"},
+		{"A bool `cbor:\"a,string,boolasint\"`", "T.A: boolasint cannot be combined with string, tag or union"},
+		{"A map[string]bool `cbor:\"a,set\"`", "T.A: set requires a map[string]struct{} field, not map[string]bool"},
+		{"A map[string]struct{} `cbor:\"a,set,tag=258\"`", "T.A: tag=258 is not supported on map[string]struct{}"},
+		{"A int `cbor:\"a,sinceversion=0\"`", `T.A: tag option "sinceversion" requires a positive version`},
+		{"A int `cbor:\"a,sinceversion=x\"`", `T.A: tag option "sinceversion" requires a positive version`},
+		{"_ struct{} `cbor:\",toarray\"`\n\tA int `cbor:\"a,sinceversion=2\"`\n\tB int", "T.A: sinceversion in a toarray struct requires every later field"},
+		{"A map[string]string `cbor:\",flatten\"`", "T.A: flatten requires a map[string]any field"},
+		{"A map[string]any `cbor:\"a,flatten\"`", "T.A: flatten has no key of its own and takes no other options"},
+		{"A map[string]any `cbor:\",flatten,omitempty\"`", "T.A: flatten has no key of its own"},
+		{"A map[string]any `cbor:\",flatten\"`\n\tB map[string]any `cbor:\",flatten\"`", "T: fields A and B are both tagged flatten"},
+		{"_ struct{} `cbor:\",toarray\"`\n\tA map[string]any `cbor:\",flatten\"`", "T.A: flatten requires a struct encoded as a map"},
+	}
+	for _, tc := range tests {
+		_, err := generate(t, "type T struct {\n\t"+tc.field+"\n}\n")
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: error = %v, want %q", tc.field, err, tc.want)
+		}
+	}
+}
+
+func TestJSONTagOptionsIgnored(t *testing.T) {
+	// json tags only name fields lacking a cbor tag; their other options
+	// belong to encoding/json and are not errors.
+	code, err := generate(t, "type T struct {\n\tA int `json:\"a,string,omitempty\"`\n}\n")
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	if !strings.Contains(code, `cbor.AppendString(b, "a")`) || !strings.Contains(code, "x.A == 0") {
+		t.Fatalf("json tag not applied:\n%s", code)
+	}
+}
+
+func TestOmitEmptyUsesIsZero(t *testing.T) {
+	code, err := generate(t, "type Cents int64\n\n"+
+		"func (c Cents) IsZero() bool { return c < 1 }\n\n"+
+		"type T struct {\n"+
+		"\tA Cents `cbor:\"a,omitempty\"`\n"+
+		"\tB *Cents `cbor:\"b,omitempty\"`\n"+
+		"\tC time.Location `cbor:\"c,omitempty\"`\n"+
+		"\tD time.Location `cbor:\"d,omitzero\"`\n"+
+		"}\n")
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	for _, want := range []string{"!(x.A.IsZero())", "!(x.B == nil)", "!(cbor.IsZeroMethod(&x.C))", "cbor.IsZeroAt(&x.D)"} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code lacks %s", want)
+		}
+	}
+}
+
+func TestEmbeddedStructs(t *testing.T) {
+	code, err := generate(t, "type Base struct {\n\tID string `cbor:\"id\"`\n}\n\n"+
+		"type T struct {\n"+
+		"\t*Base\n"+
+		"\ttime.Time\n"+
+		"}\n")
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	// Base is inlined behind a nil check; time.Time, from another
+	// package, carries no keys the generator can see.
+	for _, want := range []string{"!(x.Base == nil)", "x.Base = new(Base)", `cbor.AppendString(b, "id")`} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code lacks %s", want)
+		}
+	}
+	if strings.Contains(code, "x.Time") {
+		t.Errorf("generated code encodes the embedded time.Time")
+	}
+
+	_, err = generate(t, "type Base struct {\n\tID string\n}\n\n"+
+		"type T struct {\n\t_ struct{} `cbor:\",toarray\"`\n\t*Base\n}\n")
+	if want := "T.Base.ID: a field inlined through a pointer requires a struct encoded as a map"; err == nil || !strings.Contains(err.Error(), want) {
+		t.Fatalf("error = %v, want %q", err, want)
+	}
+}
+
+func TestAsMapOptionErrors(t *testing.T) {
+	const pairs = "type KV struct {\n\tK string\n\tV int\n}\n\n" +
+		"type Triple struct {\n\tA, B, C string\n}\n\n" +
+		"type BytesKV struct {\n\tK []byte\n\tV int\n}\n\n"
+	tests := []struct {
+		field string
+		want  string
+	}{
+		{"A []string `cbor:\"a,asmap\"`", "T.A: asmap requires a slice of a struct with two fields, not []string"},
+		{"A []Triple `cbor:\"a,asmap\"`", "T.A: asmap requires a slice of a struct with two fields, not []Triple"},
+		{"A KV `cbor:\"a,asmap\"`", "T.A: asmap requires a slice of a struct with two fields, not KV"},
+		{"A []BytesKV `cbor:\"a,asmap\"`", "T.A: asmap key BytesKV.K must be a string, bool or number, not []byte"},
+		{"A []KV `cbor:\"a,asmap,union\"`", "T.A: asmap cannot be combined with tag, union, string, boolasint, set or unit"},
+	}
+	for _, tc := range tests {
+		_, err := generate(t, pairs+"type T struct {\n\t"+tc.field+"\n}\n")
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: error = %v, want %q", tc.field, err, tc.want)
+		}
+	}
+}
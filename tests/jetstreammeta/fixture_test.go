@@ -1,9 +1,14 @@
 package jetstreammeta
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"io"
 	"testing"
 	"time"
+
+	cbor "github.com/delaneyj/cbor/runtime"
 )
 
 func TestClientInfo_Encode(t *testing.T) {
@@ -104,3 +109,198 @@ func TestBuildMetaSnapshotFixture_TrustedDecode(t *testing.T) {
 }
 
 func testTime() time.Time { return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+func TestStreamAssignmentNilConsumers(t *testing.T) {
+	snap := BuildMetaSnapshotFixture(1, 2)
+	ws := &snap.Streams[0]
+	ws.Consumers = []*WriteableConsumerAssignment{nil, ws.Consumers[0], nil, ws.Consumers[1]}
+
+	b, err := snap.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR: %v", err)
+	}
+	decoders := map[string]func(*MetaSnapshot, []byte) ([]byte, error){
+		"DecodeSafe":    (*MetaSnapshot).DecodeSafe,
+		"DecodeTrusted": (*MetaSnapshot).DecodeTrusted,
+	}
+	for name, decode := range decoders {
+		var out MetaSnapshot
+		if _, err := decode(&out, b); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		got := out.Streams[0].Consumers
+		if len(got) != 4 || got[0] != nil || got[2] != nil || got[1] == nil || got[3] == nil {
+			t.Fatalf("%s: consumer alignment lost: %+v", name, got)
+		}
+		if got[1].Name != ws.Consumers[1].Name || got[3].Name != ws.Consumers[3].Name {
+			t.Fatalf("%s: consumer names %q/%q, want %q/%q", name, got[1].Name, got[3].Name, ws.Consumers[1].Name, ws.Consumers[3].Name)
+		}
+	}
+}
+
+// BenchmarkMetaSnapshotDecoderIntern decodes a stream of meta snapshots
+// through a Decoder with and without string interning; the fixture
+// repeats account, cluster, peer and stream names across records.
+func BenchmarkMetaSnapshotDecoderIntern(b *testing.B) {
+	snap := BuildMetaSnapshotFixture(20, 10)
+	var stream []byte
+	for i := 0; i < 16; i++ {
+		var err error
+		stream, err = snap.MarshalCBOR(stream)
+		if err != nil {
+			b.Fatalf("MarshalCBOR: %v", err)
+		}
+	}
+	for _, intern := range []bool{false, true} {
+		name := "plain"
+		if intern {
+			name = "intern"
+		}
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(stream)))
+			for i := 0; i < b.N; i++ {
+				dec := cbor.NewDecoder(bytes.NewReader(stream))
+				dec.SetInternStrings(intern)
+				for {
+					var out MetaSnapshot
+					if err := dec.Decode(&out); err == io.EOF {
+						break
+					} else if err != nil {
+						b.Fatalf("Decode: %v", err)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestNewWriteableStreamAssignmentFromCBOR(t *testing.T) {
+	in := &WriteableStreamAssignment{
+		Client:  &ClientInfo{Account: "G"},
+		Created: testTime(),
+		Group:   &RaftGroup{Name: "rg", Peers: []string{"n1"}},
+		Sync:    "_INBOX.sync",
+	}
+	b, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR: %v", err)
+	}
+
+	out, rest, err := NewWriteableStreamAssignmentFromCBOR(b)
+	if err != nil {
+		t.Fatalf("NewWriteableStreamAssignmentFromCBOR: %v", err)
+	}
+	if out.Sync != in.Sync || out.Group == nil || out.Group.Name != "rg" || !out.Created.Equal(in.Created) {
+		t.Fatalf("decoded %+v, want %+v", out, in)
+	}
+	if len(rest) != 0 {
+		t.Fatalf("rest = %x, want none", rest)
+	}
+
+	// Trailing bytes are an error unless the caller allows them.
+	b = cbor.AppendInt64(b, 7)
+	if out, _, err = NewWriteableStreamAssignmentFromCBOR(b); out != nil || !errors.Is(err, cbor.ErrTrailingBytes) {
+		t.Fatalf("trailing byte: got %v, %v; want nil, ErrTrailingBytes", out, err)
+	}
+	cbor.AllowTrailingBytes = true
+	defer func() { cbor.AllowTrailingBytes = false }()
+	if out, rest, err = NewWriteableStreamAssignmentFromCBOR(b); out == nil || err != nil || !bytes.Equal(rest, []byte{0x07}) {
+		t.Fatalf("with AllowTrailingBytes: got %v, %x, %v; want a value, 07, nil", out, rest, err)
+	}
+	b = b[:len(b)-1]
+
+	out, rest, err = NewWriteableStreamAssignmentFromCBOR(b[:len(b)-4])
+	if err == nil || out != nil || len(rest) != len(b)-4 {
+		t.Fatalf("truncated input: got %v, %d bytes, %v; want nil, all bytes, an error", out, len(rest), err)
+	}
+}
+
+// Unexported types get an unexported constructor.
+func TestNewUnexportedFromCBOR(t *testing.T) {
+	b := encodedConsumerAssignment(t)
+	out, rest, err := newConsumerAssignmentFromCBOR(b)
+	if err != nil || len(rest) != 0 {
+		t.Fatalf("newConsumerAssignmentFromCBOR: %v (%d bytes left)", err, len(rest))
+	}
+	if out.Name != "C" || out.Stream != "S" || out.Client == nil || out.Client.Name != "c1" {
+		t.Fatalf("decoded %+v", out)
+	}
+}
+
+func TestConsumerAssignmentDecodeReusesClient(t *testing.T) {
+	in := &WriteableConsumerAssignment{
+		Client:  &ClientInfo{Account: "G", Name: "c1", Tags: []string{"a", "b"}},
+		Created: testTime(),
+		Name:    "C",
+		Stream:  "S",
+	}
+	b, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR: %v", err)
+	}
+	decoders := map[string]func(*WriteableConsumerAssignment, []byte) ([]byte, error){
+		"DecodeSafe":    (*WriteableConsumerAssignment).DecodeSafe,
+		"DecodeTrusted": (*WriteableConsumerAssignment).DecodeTrusted,
+	}
+	for name, decode := range decoders {
+		// A pooled value: the pointee is decoded into, and fields the
+		// payload omits do not keep their old values.
+		client := &ClientInfo{Account: "old", Host: "stale", Tags: make([]string, 0, 4)}
+		out := WriteableConsumerAssignment{Client: client}
+		if _, err := decode(&out, b); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if out.Client != client {
+			t.Fatalf("%s: Client was reallocated", name)
+		}
+		if client.Account != "G" || client.Name != "c1" || client.Host != "" || len(client.Tags) != 2 {
+			t.Fatalf("%s: Client = %+v", name, client)
+		}
+	}
+
+	// Null still clears the pointer.
+	b = cbor.AppendMapHeader(nil, 1)
+	b = cbor.AppendString(b, "client")
+	b = cbor.AppendNil(b)
+	for name, decode := range decoders {
+		out := WriteableConsumerAssignment{Client: &ClientInfo{Account: "old"}}
+		if _, err := decode(&out, b); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if out.Client != nil {
+			t.Fatalf("%s: Client = %+v, want nil", name, out.Client)
+		}
+	}
+}
+
+// encodedConsumerAssignment returns a consumer assignment with a Client,
+// encoded.
+func encodedConsumerAssignment(t *testing.T) []byte {
+	t.Helper()
+	in := &WriteableConsumerAssignment{
+		Client: &ClientInfo{Account: "G", Name: "c1", Tags: []string{"a", "b"}},
+		Name:   "C",
+		Stream: "S",
+	}
+	b, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR: %v", err)
+	}
+	return b
+}
+
+func TestConsumerAssignmentDecodeTrustedReusesClient(t *testing.T) {
+	b := encodedConsumerAssignment(t)
+	var out WriteableConsumerAssignment
+	if _, err := out.DecodeTrusted(b); err != nil {
+		t.Fatalf("DecodeTrusted: %v", err)
+	}
+	client := out.Client
+	if _, err := out.DecodeTrusted(b); err != nil {
+		t.Fatalf("DecodeTrusted: %v", err)
+	}
+	if out.Client != client {
+		t.Fatalf("DecodeTrusted reallocated Client")
+	}
+}
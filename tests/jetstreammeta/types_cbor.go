@@ -3,17 +3,61 @@
 package jetstreammeta
 
 import (
+	"io"
 	"time"
 
 	cbor "github.com/delaneyj/cbor/runtime"
 )
 
+// Fail the build if a generated method drifts from the runtime interfaces.
+var (
+	_ cbor.Marshaler   = (*ClientInfo)(nil)
+	_ cbor.Unmarshaler = (*ClientInfo)(nil)
+	_ cbor.Marshaler   = (*RaftGroup)(nil)
+	_ cbor.Unmarshaler = (*RaftGroup)(nil)
+	_ cbor.Marshaler   = (*SequencePair)(nil)
+	_ cbor.Unmarshaler = (*SequencePair)(nil)
+	_ cbor.Marshaler   = (*Pending)(nil)
+	_ cbor.Unmarshaler = (*Pending)(nil)
+	_ cbor.Marshaler   = (*ConsumerState)(nil)
+	_ cbor.Unmarshaler = (*ConsumerState)(nil)
+	_ cbor.Marshaler   = (*consumerAssignment)(nil)
+	_ cbor.Unmarshaler = (*consumerAssignment)(nil)
+	_ cbor.Marshaler   = (*streamAssignment)(nil)
+	_ cbor.Unmarshaler = (*streamAssignment)(nil)
+	_ cbor.Marshaler   = (*WriteableConsumerAssignment)(nil)
+	_ cbor.Unmarshaler = (*WriteableConsumerAssignment)(nil)
+	_ cbor.Marshaler   = (*WriteableStreamAssignment)(nil)
+	_ cbor.Unmarshaler = (*WriteableStreamAssignment)(nil)
+	_ cbor.Marshaler   = (*MetaSnapshot)(nil)
+	_ cbor.Unmarshaler = (*MetaSnapshot)(nil)
+	_ cbor.Marshaler   = (*StreamConfigSnapshot)(nil)
+	_ cbor.Unmarshaler = (*StreamConfigSnapshot)(nil)
+	_ cbor.Marshaler   = (*ConsumerConfigSnapshot)(nil)
+	_ cbor.Unmarshaler = (*ConsumerConfigSnapshot)(nil)
+)
+
 func (x ClientInfo) Msgsize() (s int) {
 	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("host") + cbor.StringPrefixSize + len(x.Host) + cbor.StringPrefixSize + len("id") + cbor.Uint64Size + cbor.StringPrefixSize + len("acc") + cbor.StringPrefixSize + len(x.Account) + cbor.StringPrefixSize + len("svc") + cbor.StringPrefixSize + len(x.Service) + cbor.StringPrefixSize + len("user") + cbor.StringPrefixSize + len(x.User) + cbor.StringPrefixSize + len("name") + cbor.StringPrefixSize + len(x.Name) + cbor.StringPrefixSize + len("lang") + cbor.StringPrefixSize + len(x.Lang) + cbor.StringPrefixSize + len("ver") + cbor.StringPrefixSize + len(x.Version) + cbor.StringPrefixSize + len("rtt") + cbor.DurationSize + cbor.StringPrefixSize + len("server") + cbor.StringPrefixSize + len(x.Server) + cbor.StringPrefixSize + len("cluster") + cbor.StringPrefixSize + len(x.Cluster) + cbor.StringPrefixSize + len("alts") + cbor.ArrayHeaderSize + len(x.Alternates)*cbor.StringPrefixSize + cbor.StringPrefixSize + len("jwt") + cbor.StringPrefixSize + len(x.Jwt) + cbor.StringPrefixSize + len("issuer_key") + cbor.StringPrefixSize + len(x.IssuerKey) + cbor.StringPrefixSize + len("name_tag") + cbor.StringPrefixSize + len(x.NameTag) + cbor.StringPrefixSize + len("tags") + cbor.ArrayHeaderSize + len(x.Tags)*cbor.StringPrefixSize + cbor.StringPrefixSize + len("kind") + cbor.StringPrefixSize + len(x.Kind) + cbor.StringPrefixSize + len("client_type") + cbor.StringPrefixSize + len(x.ClientType) + cbor.StringPrefixSize + len("client_id") + cbor.StringPrefixSize + len(x.MQTTClient) + cbor.StringPrefixSize + len("nonce") + cbor.StringPrefixSize + len(x.Nonce)
 	return
 }
 
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
 func (x *ClientInfo) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *ClientInfo) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *ClientInfo) AppendCBOR(b []byte) ([]byte, error) {
 	if x == nil {
 		return cbor.AppendNil(b), nil
 	}
@@ -174,11 +218,15 @@ func (x *ClientInfo) MarshalCBOR(b []byte) ([]byte, error) {
 		}
 	}
 	if !(len(x.Alternates) == 0) {
-
-		b = cbor.AppendString(b, "alts")
-		b = cbor.AppendArrayHeader(b, uint32(len(x.Alternates)))
-		for _, v := range x.Alternates {
-			b = cbor.AppendString(b, v)
+		if x.Alternates == nil && cbor.NilContainers == cbor.NilAsNull {
+			b = cbor.AppendString(b, "alts")
+			b = cbor.AppendNil(b)
+		} else {
+			b = cbor.AppendString(b, "alts")
+			b = cbor.AppendArrayHeader(b, uint32(len(x.Alternates)))
+			for _, v := range x.Alternates {
+				b = cbor.AppendString(b, v)
+			}
 		}
 	}
 	if !(x.Stop == nil) {
@@ -210,11 +258,15 @@ func (x *ClientInfo) MarshalCBOR(b []byte) ([]byte, error) {
 		}
 	}
 	if !(len(x.Tags) == 0) {
-
-		b = cbor.AppendString(b, "tags")
-		b = cbor.AppendArrayHeader(b, uint32(len(x.Tags)))
-		for _, v := range x.Tags {
-			b = cbor.AppendString(b, v)
+		if x.Tags == nil && cbor.NilContainers == cbor.NilAsNull {
+			b = cbor.AppendString(b, "tags")
+			b = cbor.AppendNil(b)
+		} else {
+			b = cbor.AppendString(b, "tags")
+			b = cbor.AppendArrayHeader(b, uint32(len(x.Tags)))
+			for _, v := range x.Tags {
+				b = cbor.AppendString(b, v)
+			}
 		}
 	}
 	if !(x.Kind == "") {
@@ -249,121 +301,294 @@ func (x *ClientInfo) MarshalCBOR(b []byte) ([]byte, error) {
 	return b, nil
 }
 
-// DecodeSafe decodes using validated, allocating string handling.
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
 func (x *ClientInfo) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *ClientInfo) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
 	if x == nil {
 		return b, cbor.ErrNotNil
 	}
 	sz, rest, err := cbor.ReadMapHeaderBytes(b)
 	if err != nil {
-		return b, err
+		return b, cbor.WrapDecodeError(err, "", 0)
 	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
 	for i := uint32(0); i < sz; i++ {
-		key, v, err := cbor.ReadStringBytes(rest)
+		key, v, err := in.ReadStringBytes(rest)
 		if err != nil {
-			return b, err
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
 		}
 		switch key {
 		case "start":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "start", len(b)-len(v))
+				}
+				v = o
+				break
+			}
 
-			v, err = cbor.Skip(v)
+			v, err = cbor.ReadWithCodec(v, &x.Start)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "start", len(b)-len(v))
 			}
 		case "host":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "host", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "host", len(b)-len(v))
+				}
+			}
 
 			var tmp string
-			tmp, v, err = cbor.ReadStringBytes(v)
+			tmp, v, err = in.ReadStringBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "host", len(b)-len(v))
 			}
 			x.Host = tmp
 		case "id":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 2); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "id", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "id", len(b)-len(v))
+				}
+			}
 
 			var tmp uint64
 			tmp, v, err = cbor.ReadUint64Bytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "id", len(b)-len(v))
 			}
 			x.ID = tmp
 		case "acc":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 3); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "acc", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "acc", len(b)-len(v))
+				}
+			}
 
 			var tmp string
-			tmp, v, err = cbor.ReadStringBytes(v)
+			tmp, v, err = in.ReadStringBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "acc", len(b)-len(v))
 			}
 			x.Account = tmp
 		case "svc":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 4); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "svc", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "svc", len(b)-len(v))
+				}
+			}
 
 			var tmp string
-			tmp, v, err = cbor.ReadStringBytes(v)
+			tmp, v, err = in.ReadStringBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "svc", len(b)-len(v))
 			}
 			x.Service = tmp
 		case "user":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 5); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "user", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "user", len(b)-len(v))
+				}
+			}
 
 			var tmp string
-			tmp, v, err = cbor.ReadStringBytes(v)
+			tmp, v, err = in.ReadStringBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "user", len(b)-len(v))
 			}
 			x.User = tmp
 		case "name":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 6); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
+				}
+			}
 
 			var tmp string
-			tmp, v, err = cbor.ReadStringBytes(v)
+			tmp, v, err = in.ReadStringBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
 			}
 			x.Name = tmp
 		case "lang":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 7); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "lang", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "lang", len(b)-len(v))
+				}
+			}
 
 			var tmp string
-			tmp, v, err = cbor.ReadStringBytes(v)
+			tmp, v, err = in.ReadStringBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "lang", len(b)-len(v))
 			}
 			x.Lang = tmp
 		case "ver":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 8); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "ver", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "ver", len(b)-len(v))
+				}
+			}
 
 			var tmp string
-			tmp, v, err = cbor.ReadStringBytes(v)
+			tmp, v, err = in.ReadStringBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "ver", len(b)-len(v))
 			}
 			x.Version = tmp
 		case "rtt":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 9); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "rtt", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "rtt", len(b)-len(v))
+				}
+			}
 
 			var tmp time.Duration
 			tmp, v, err = cbor.ReadDurationBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "rtt", len(b)-len(v))
 			}
 			x.RTT = tmp
 		case "server":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 10); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "server", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "server", len(b)-len(v))
+				}
+			}
 
 			var tmp string
-			tmp, v, err = cbor.ReadStringBytes(v)
+			tmp, v, err = in.ReadStringBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "server", len(b)-len(v))
 			}
 			x.Server = tmp
 		case "cluster":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 11); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "cluster", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "cluster", len(b)-len(v))
+				}
+			}
 
 			var tmp string
-			tmp, v, err = cbor.ReadStringBytes(v)
+			tmp, v, err = in.ReadStringBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "cluster", len(b)-len(v))
 			}
 			x.Cluster = tmp
 		case "alts":
-
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 12); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "alts", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "alts", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Alternates = cbor.NullSlice(x.Alternates)
+				break
+			}
 			var sz uint32
-			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "alts", len(b)-len(v))
 			}
 			if cap(x.Alternates) >= int(sz) {
 				x.Alternates = x.Alternates[:sz]
@@ -375,48 +600,111 @@ func (x *ClientInfo) DecodeSafe(b []byte) ([]byte, error) {
 			}
 			for iAlternates := uint32(0); iAlternates < sz; iAlternates++ {
 				var tmp string
-				tmp, v, err = cbor.ReadStringBytes(v)
+				tmp, v, err = in.ReadStringBytes(v)
 				if err != nil {
-					return b, err
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, int(iAlternates)), "alts", len(b)-len(v))
 				}
 				x.Alternates[iAlternates] = tmp
 			}
+			if indef {
+				v = v[1:] // break
+			}
 		case "stop":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 13); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "stop", len(b)-len(v))
+				}
+				v = o
+				break
+			}
 
-			v, err = cbor.Skip(v)
+			v, err = cbor.ReadWithCodec(v, &x.Stop)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "stop", len(b)-len(v))
 			}
 		case "jwt":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 14); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "jwt", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "jwt", len(b)-len(v))
+				}
+			}
 
 			var tmp string
-			tmp, v, err = cbor.ReadStringBytes(v)
+			tmp, v, err = in.ReadStringBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "jwt", len(b)-len(v))
 			}
 			x.Jwt = tmp
 		case "issuer_key":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 15); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "issuer_key", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "issuer_key", len(b)-len(v))
+				}
+			}
 
 			var tmp string
-			tmp, v, err = cbor.ReadStringBytes(v)
+			tmp, v, err = in.ReadStringBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "issuer_key", len(b)-len(v))
 			}
 			x.IssuerKey = tmp
 		case "name_tag":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 16); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "name_tag", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "name_tag", len(b)-len(v))
+				}
+			}
 
 			var tmp string
-			tmp, v, err = cbor.ReadStringBytes(v)
+			tmp, v, err = in.ReadStringBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "name_tag", len(b)-len(v))
 			}
 			x.NameTag = tmp
 		case "tags":
-
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 17); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "tags", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "tags", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Tags = cbor.NullSlice(x.Tags)
+				break
+			}
 			var sz uint32
-			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "tags", len(b)-len(v))
 			}
 			if cap(x.Tags) >= int(sz) {
 				x.Tags = x.Tags[:sz]
@@ -428,56 +716,111 @@ func (x *ClientInfo) DecodeSafe(b []byte) ([]byte, error) {
 			}
 			for iTags := uint32(0); iTags < sz; iTags++ {
 				var tmp string
-				tmp, v, err = cbor.ReadStringBytes(v)
+				tmp, v, err = in.ReadStringBytes(v)
 				if err != nil {
-					return b, err
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, int(iTags)), "tags", len(b)-len(v))
 				}
 				x.Tags[iTags] = tmp
 			}
+			if indef {
+				v = v[1:] // break
+			}
 		case "kind":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 18); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "kind", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "kind", len(b)-len(v))
+				}
+			}
 
 			var tmp string
-			tmp, v, err = cbor.ReadStringBytes(v)
+			tmp, v, err = in.ReadStringBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "kind", len(b)-len(v))
 			}
 			x.Kind = tmp
 		case "client_type":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 19); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "client_type", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "client_type", len(b)-len(v))
+				}
+			}
 
 			var tmp string
-			tmp, v, err = cbor.ReadStringBytes(v)
+			tmp, v, err = in.ReadStringBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "client_type", len(b)-len(v))
 			}
 			x.ClientType = tmp
 		case "client_id":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 20); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "client_id", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "client_id", len(b)-len(v))
+				}
+			}
 
 			var tmp string
-			tmp, v, err = cbor.ReadStringBytes(v)
+			tmp, v, err = in.ReadStringBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "client_id", len(b)-len(v))
 			}
 			x.MQTTClient = tmp
 		case "nonce":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 21); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "nonce", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "nonce", len(b)-len(v))
+				}
+			}
 
 			var tmp string
-			tmp, v, err = cbor.ReadStringBytes(v)
+			tmp, v, err = in.ReadStringBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "nonce", len(b)-len(v))
 			}
 			x.Nonce = tmp
 		default:
 			v, err = cbor.Skip(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
 			}
 		}
 		rest = v
 	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
 	return rest, nil
 }
 
-// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
 func (x *ClientInfo) DecodeTrusted(b []byte) ([]byte, error) {
 	if x == nil {
 		return b, cbor.ErrNotNil
@@ -486,6 +829,9 @@ func (x *ClientInfo) DecodeTrusted(b []byte) ([]byte, error) {
 	if err != nil {
 		return b, err
 	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
 	for i := uint32(0); i < sz; i++ {
 		keyBytes, v, err := cbor.ReadStringZC(rest)
 		if err != nil {
@@ -495,19 +841,27 @@ func (x *ClientInfo) DecodeTrusted(b []byte) ([]byte, error) {
 		switch key {
 		case "start":
 
-			v, err = cbor.Skip(v)
+			v, err = cbor.ReadWithCodec(v, &x.Start)
 			if err != nil {
 				return b, err
 			}
 		case "host":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
-			var tmpBytes []byte
-			tmpBytes, v, err = cbor.ReadStringZC(v)
+			x.Host, v, err = cbor.ReadTrustedStringBytes(v)
 			if err != nil {
 				return b, err
 			}
-			x.Host = cbor.UnsafeString(tmpBytes)
 		case "id":
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
 			var tmp uint64
 			tmp, v, err = cbor.ReadUint64Bytes(v)
@@ -516,54 +870,77 @@ func (x *ClientInfo) DecodeTrusted(b []byte) ([]byte, error) {
 			}
 			x.ID = tmp
 		case "acc":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
-			var tmpBytes []byte
-			tmpBytes, v, err = cbor.ReadStringZC(v)
+			x.Account, v, err = cbor.ReadTrustedStringBytes(v)
 			if err != nil {
 				return b, err
 			}
-			x.Account = cbor.UnsafeString(tmpBytes)
 		case "svc":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
-			var tmpBytes []byte
-			tmpBytes, v, err = cbor.ReadStringZC(v)
+			x.Service, v, err = cbor.ReadTrustedStringBytes(v)
 			if err != nil {
 				return b, err
 			}
-			x.Service = cbor.UnsafeString(tmpBytes)
 		case "user":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
-			var tmpBytes []byte
-			tmpBytes, v, err = cbor.ReadStringZC(v)
+			x.User, v, err = cbor.ReadTrustedStringBytes(v)
 			if err != nil {
 				return b, err
 			}
-			x.User = cbor.UnsafeString(tmpBytes)
 		case "name":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
-			var tmpBytes []byte
-			tmpBytes, v, err = cbor.ReadStringZC(v)
+			x.Name, v, err = cbor.ReadTrustedStringBytes(v)
 			if err != nil {
 				return b, err
 			}
-			x.Name = cbor.UnsafeString(tmpBytes)
 		case "lang":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
-			var tmpBytes []byte
-			tmpBytes, v, err = cbor.ReadStringZC(v)
+			x.Lang, v, err = cbor.ReadTrustedStringBytes(v)
 			if err != nil {
 				return b, err
 			}
-			x.Lang = cbor.UnsafeString(tmpBytes)
 		case "ver":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
-			var tmpBytes []byte
-			tmpBytes, v, err = cbor.ReadStringZC(v)
+			x.Version, v, err = cbor.ReadTrustedStringBytes(v)
 			if err != nil {
 				return b, err
 			}
-			x.Version = cbor.UnsafeString(tmpBytes)
 		case "rtt":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
 			var tmp time.Duration
 			tmp, v, err = cbor.ReadDurationBytes(v)
@@ -572,25 +949,41 @@ func (x *ClientInfo) DecodeTrusted(b []byte) ([]byte, error) {
 			}
 			x.RTT = tmp
 		case "server":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
-			var tmpBytes []byte
-			tmpBytes, v, err = cbor.ReadStringZC(v)
+			x.Server, v, err = cbor.ReadTrustedStringBytes(v)
 			if err != nil {
 				return b, err
 			}
-			x.Server = cbor.UnsafeString(tmpBytes)
 		case "cluster":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
-			var tmpBytes []byte
-			tmpBytes, v, err = cbor.ReadStringZC(v)
+			x.Cluster, v, err = cbor.ReadTrustedStringBytes(v)
 			if err != nil {
 				return b, err
 			}
-			x.Cluster = cbor.UnsafeString(tmpBytes)
 		case "alts":
-
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Alternates = cbor.NullSlice(x.Alternates)
+				break
+			}
 			var sz uint32
-			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
 			if err != nil {
 				return b, err
 			}
@@ -604,46 +997,68 @@ func (x *ClientInfo) DecodeTrusted(b []byte) ([]byte, error) {
 			}
 			for iAlternates := uint32(0); iAlternates < sz; iAlternates++ {
 				var tmp string
-				tmp, v, err = cbor.ReadStringBytes(v)
+				tmp, v, err = cbor.ReadTrustedStringBytes(v)
 				if err != nil {
 					return b, err
 				}
 				x.Alternates[iAlternates] = tmp
 			}
+			if indef {
+				v = v[1:] // break
+			}
 		case "stop":
 
-			v, err = cbor.Skip(v)
+			v, err = cbor.ReadWithCodec(v, &x.Stop)
 			if err != nil {
 				return b, err
 			}
 		case "jwt":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
-			var tmpBytes []byte
-			tmpBytes, v, err = cbor.ReadStringZC(v)
+			x.Jwt, v, err = cbor.ReadTrustedStringBytes(v)
 			if err != nil {
 				return b, err
 			}
-			x.Jwt = cbor.UnsafeString(tmpBytes)
 		case "issuer_key":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
-			var tmpBytes []byte
-			tmpBytes, v, err = cbor.ReadStringZC(v)
+			x.IssuerKey, v, err = cbor.ReadTrustedStringBytes(v)
 			if err != nil {
 				return b, err
 			}
-			x.IssuerKey = cbor.UnsafeString(tmpBytes)
 		case "name_tag":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
-			var tmpBytes []byte
-			tmpBytes, v, err = cbor.ReadStringZC(v)
+			x.NameTag, v, err = cbor.ReadTrustedStringBytes(v)
 			if err != nil {
 				return b, err
 			}
-			x.NameTag = cbor.UnsafeString(tmpBytes)
 		case "tags":
-
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Tags = cbor.NullSlice(x.Tags)
+				break
+			}
 			var sz uint32
-			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
 			if err != nil {
 				return b, err
 			}
@@ -657,44 +1072,59 @@ func (x *ClientInfo) DecodeTrusted(b []byte) ([]byte, error) {
 			}
 			for iTags := uint32(0); iTags < sz; iTags++ {
 				var tmp string
-				tmp, v, err = cbor.ReadStringBytes(v)
+				tmp, v, err = cbor.ReadTrustedStringBytes(v)
 				if err != nil {
 					return b, err
 				}
 				x.Tags[iTags] = tmp
 			}
+			if indef {
+				v = v[1:] // break
+			}
 		case "kind":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
-			var tmpBytes []byte
-			tmpBytes, v, err = cbor.ReadStringZC(v)
+			x.Kind, v, err = cbor.ReadTrustedStringBytes(v)
 			if err != nil {
 				return b, err
 			}
-			x.Kind = cbor.UnsafeString(tmpBytes)
 		case "client_type":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
-			var tmpBytes []byte
-			tmpBytes, v, err = cbor.ReadStringZC(v)
+			x.ClientType, v, err = cbor.ReadTrustedStringBytes(v)
 			if err != nil {
 				return b, err
 			}
-			x.ClientType = cbor.UnsafeString(tmpBytes)
 		case "client_id":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
-			var tmpBytes []byte
-			tmpBytes, v, err = cbor.ReadStringZC(v)
+			x.MQTTClient, v, err = cbor.ReadTrustedStringBytes(v)
 			if err != nil {
 				return b, err
 			}
-			x.MQTTClient = cbor.UnsafeString(tmpBytes)
 		case "nonce":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
-			var tmpBytes []byte
-			tmpBytes, v, err = cbor.ReadStringZC(v)
+			x.Nonce, v, err = cbor.ReadTrustedStringBytes(v)
 			if err != nil {
 				return b, err
 			}
-			x.Nonce = cbor.UnsafeString(tmpBytes)
 		default:
 			v, err = cbor.Skip(v)
 			if err != nil {
@@ -706,17 +1136,75 @@ func (x *ClientInfo) DecodeTrusted(b []byte) ([]byte, error) {
 	return rest, nil
 }
 
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *ClientInfo) resetCBOR() {
+	var zero ClientInfo
+	x.Start = zero.Start
+	x.Host = zero.Host
+	x.ID = zero.ID
+	x.Account = zero.Account
+	x.Service = zero.Service
+	x.User = zero.User
+	x.Name = zero.Name
+	x.Lang = zero.Lang
+	x.Version = zero.Version
+	x.RTT = zero.RTT
+	x.Server = zero.Server
+	x.Cluster = zero.Cluster
+	x.Alternates = x.Alternates[:0]
+	x.Stop = zero.Stop
+	x.Jwt = zero.Jwt
+	x.IssuerKey = zero.IssuerKey
+	x.NameTag = zero.NameTag
+	x.Tags = x.Tags[:0]
+	x.Kind = zero.Kind
+	x.ClientType = zero.ClientType
+	x.MQTTClient = zero.MQTTClient
+	x.Nonce = zero.Nonce
+}
+
 // UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
 func (x *ClientInfo) UnmarshalCBOR(b []byte) ([]byte, error) {
 	return x.DecodeSafe(b)
 }
 
+// NewClientInfoFromCBOR decodes b into a newly allocated ClientInfo using the
+// Safe path. Bytes after the item fail with cbor.ErrTrailingBytes unless
+// cbor.AllowTrailingBytes is set, in which case they are returned.
+func NewClientInfoFromCBOR(b []byte) (*ClientInfo, []byte, error) {
+	x := new(ClientInfo)
+	o, err := x.DecodeSafe(b)
+	if err == nil {
+		err = cbor.CheckTrailingBytes(b, o)
+	}
+	if err != nil {
+		return nil, b, err
+	}
+	return x, o, nil
+}
+
 func (x RaftGroup) Msgsize() (s int) {
 	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("name") + cbor.StringPrefixSize + len(x.Name) + cbor.StringPrefixSize + len("peers") + cbor.ArrayHeaderSize + len(x.Peers)*cbor.StringPrefixSize + cbor.StringPrefixSize + len("cluster") + cbor.StringPrefixSize + len(x.Cluster) + cbor.StringPrefixSize + len("preferred") + cbor.StringPrefixSize + len(x.Preferred) + cbor.StringPrefixSize + len("scale_up") + cbor.BoolSize
 	return
 }
 
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
 func (x *RaftGroup) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *RaftGroup) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *RaftGroup) AppendCBOR(b []byte) ([]byte, error) {
 	if x == nil {
 		return cbor.AppendNil(b), nil
 	}
@@ -743,11 +1231,15 @@ func (x *RaftGroup) MarshalCBOR(b []byte) ([]byte, error) {
 	if err != nil {
 		return b, err
 	}
-
-	b = cbor.AppendString(b, "peers")
-	b = cbor.AppendArrayHeader(b, uint32(len(x.Peers)))
-	for _, v := range x.Peers {
-		b = cbor.AppendString(b, v)
+	if x.Peers == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = cbor.AppendString(b, "peers")
+		b = cbor.AppendNil(b)
+	} else {
+		b = cbor.AppendString(b, "peers")
+		b = cbor.AppendArrayHeader(b, uint32(len(x.Peers)))
+		for _, v := range x.Peers {
+			b = cbor.AppendString(b, v)
+		}
 	}
 	b = cbor.AppendString(b, "store")
 	b, err = x.Storage.MarshalCBOR(b)
@@ -779,35 +1271,81 @@ func (x *RaftGroup) MarshalCBOR(b []byte) ([]byte, error) {
 	return b, nil
 }
 
-// DecodeSafe decodes using validated, allocating string handling.
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
 func (x *RaftGroup) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *RaftGroup) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
 	if x == nil {
 		return b, cbor.ErrNotNil
 	}
 	sz, rest, err := cbor.ReadMapHeaderBytes(b)
 	if err != nil {
-		return b, err
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
 	}
+	var seen [1]uint64
 	for i := uint32(0); i < sz; i++ {
-		key, v, err := cbor.ReadStringBytes(rest)
+		key, v, err := in.ReadStringBytes(rest)
 		if err != nil {
-			return b, err
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
 		}
 		switch key {
 		case "name":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
+				}
+			}
 
 			var tmp string
-			tmp, v, err = cbor.ReadStringBytes(v)
+			tmp, v, err = in.ReadStringBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
 			}
 			x.Name = tmp
 		case "peers":
-
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "peers", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "peers", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Peers = cbor.NullSlice(x.Peers)
+				break
+			}
 			var sz uint32
-			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "peers", len(b)-len(v))
 			}
 			if cap(x.Peers) >= int(sz) {
 				x.Peers = x.Peers[:sz]
@@ -819,54 +1357,104 @@ func (x *RaftGroup) DecodeSafe(b []byte) ([]byte, error) {
 			}
 			for iPeers := uint32(0); iPeers < sz; iPeers++ {
 				var tmp string
-				tmp, v, err = cbor.ReadStringBytes(v)
+				tmp, v, err = in.ReadStringBytes(v)
 				if err != nil {
-					return b, err
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, int(iPeers)), "peers", len(b)-len(v))
 				}
 				x.Peers[iPeers] = tmp
 			}
+			if indef {
+				v = v[1:] // break
+			}
 		case "store":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 2); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "store", len(b)-len(v))
+				}
+				v = o
+				break
+			}
 
 			v, err = x.Storage.UnmarshalCBOR(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "store", len(b)-len(v))
 			}
 		case "cluster":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 3); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "cluster", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "cluster", len(b)-len(v))
+				}
+			}
 
 			var tmp string
-			tmp, v, err = cbor.ReadStringBytes(v)
+			tmp, v, err = in.ReadStringBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "cluster", len(b)-len(v))
 			}
 			x.Cluster = tmp
 		case "preferred":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 4); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "preferred", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "preferred", len(b)-len(v))
+				}
+			}
 
 			var tmp string
-			tmp, v, err = cbor.ReadStringBytes(v)
+			tmp, v, err = in.ReadStringBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "preferred", len(b)-len(v))
 			}
 			x.Preferred = tmp
 		case "scale_up":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 5); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "scale_up", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "scale_up", len(b)-len(v))
+				}
+			}
 
 			var tmp bool
 			tmp, v, err = cbor.ReadBoolBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "scale_up", len(b)-len(v))
 			}
 			x.ScaleUp = tmp
 		default:
 			v, err = cbor.Skip(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
 			}
 		}
 		rest = v
 	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
 	return rest, nil
 }
 
-// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
 func (x *RaftGroup) DecodeTrusted(b []byte) ([]byte, error) {
 	if x == nil {
 		return b, cbor.ErrNotNil
@@ -875,6 +1463,9 @@ func (x *RaftGroup) DecodeTrusted(b []byte) ([]byte, error) {
 	if err != nil {
 		return b, err
 	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
 	for i := uint32(0); i < sz; i++ {
 		keyBytes, v, err := cbor.ReadStringZC(rest)
 		if err != nil {
@@ -883,17 +1474,30 @@ func (x *RaftGroup) DecodeTrusted(b []byte) ([]byte, error) {
 		key := cbor.UnsafeString(keyBytes)
 		switch key {
 		case "name":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
-			var tmpBytes []byte
-			tmpBytes, v, err = cbor.ReadStringZC(v)
+			x.Name, v, err = cbor.ReadTrustedStringBytes(v)
 			if err != nil {
 				return b, err
 			}
-			x.Name = cbor.UnsafeString(tmpBytes)
 		case "peers":
-
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Peers = cbor.NullSlice(x.Peers)
+				break
+			}
 			var sz uint32
-			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
 			if err != nil {
 				return b, err
 			}
@@ -907,12 +1511,15 @@ func (x *RaftGroup) DecodeTrusted(b []byte) ([]byte, error) {
 			}
 			for iPeers := uint32(0); iPeers < sz; iPeers++ {
 				var tmp string
-				tmp, v, err = cbor.ReadStringBytes(v)
+				tmp, v, err = cbor.ReadTrustedStringBytes(v)
 				if err != nil {
 					return b, err
 				}
 				x.Peers[iPeers] = tmp
 			}
+			if indef {
+				v = v[1:] // break
+			}
 		case "store":
 
 			v, err = x.Storage.UnmarshalCBOR(v)
@@ -920,22 +1527,33 @@ func (x *RaftGroup) DecodeTrusted(b []byte) ([]byte, error) {
 				return b, err
 			}
 		case "cluster":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
-			var tmpBytes []byte
-			tmpBytes, v, err = cbor.ReadStringZC(v)
+			x.Cluster, v, err = cbor.ReadTrustedStringBytes(v)
 			if err != nil {
 				return b, err
 			}
-			x.Cluster = cbor.UnsafeString(tmpBytes)
 		case "preferred":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
-			var tmpBytes []byte
-			tmpBytes, v, err = cbor.ReadStringZC(v)
+			x.Preferred, v, err = cbor.ReadTrustedStringBytes(v)
 			if err != nil {
 				return b, err
 			}
-			x.Preferred = cbor.UnsafeString(tmpBytes)
 		case "scale_up":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
 			var tmp bool
 			tmp, v, err = cbor.ReadBoolBytes(v)
@@ -954,17 +1572,59 @@ func (x *RaftGroup) DecodeTrusted(b []byte) ([]byte, error) {
 	return rest, nil
 }
 
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *RaftGroup) resetCBOR() {
+	var zero RaftGroup
+	x.Name = zero.Name
+	x.Peers = x.Peers[:0]
+	x.Storage = zero.Storage
+	x.Cluster = zero.Cluster
+	x.Preferred = zero.Preferred
+	x.ScaleUp = zero.ScaleUp
+}
+
 // UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
 func (x *RaftGroup) UnmarshalCBOR(b []byte) ([]byte, error) {
 	return x.DecodeSafe(b)
 }
 
+// NewRaftGroupFromCBOR decodes b into a newly allocated RaftGroup using the
+// Safe path. Bytes after the item fail with cbor.ErrTrailingBytes unless
+// cbor.AllowTrailingBytes is set, in which case they are returned.
+func NewRaftGroupFromCBOR(b []byte) (*RaftGroup, []byte, error) {
+	x := new(RaftGroup)
+	o, err := x.DecodeSafe(b)
+	if err == nil {
+		err = cbor.CheckTrailingBytes(b, o)
+	}
+	if err != nil {
+		return nil, b, err
+	}
+	return x, o, nil
+}
+
 func (x SequencePair) Msgsize() (s int) {
 	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("consumer_seq") + cbor.Uint64Size + cbor.StringPrefixSize + len("stream_seq") + cbor.Uint64Size
 	return
 }
 
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
 func (x *SequencePair) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *SequencePair) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *SequencePair) AppendCBOR(b []byte) ([]byte, error) {
 	if x == nil {
 		return cbor.AppendNil(b), nil
 	}
@@ -987,49 +1647,94 @@ func (x *SequencePair) MarshalCBOR(b []byte) ([]byte, error) {
 	return b, nil
 }
 
-// DecodeSafe decodes using validated, allocating string handling.
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
 func (x *SequencePair) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *SequencePair) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
 	if x == nil {
 		return b, cbor.ErrNotNil
 	}
 	sz, rest, err := cbor.ReadMapHeaderBytes(b)
 	if err != nil {
-		return b, err
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
 	}
+	var seen [1]uint64
 	for i := uint32(0); i < sz; i++ {
-		key, v, err := cbor.ReadStringBytes(rest)
+		key, v, err := in.ReadStringBytes(rest)
 		if err != nil {
-			return b, err
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
 		}
 		switch key {
 		case "consumer_seq":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "consumer_seq", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "consumer_seq", len(b)-len(v))
+				}
+			}
 
 			var tmp uint64
 			tmp, v, err = cbor.ReadUint64Bytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "consumer_seq", len(b)-len(v))
 			}
 			x.Consumer = tmp
 		case "stream_seq":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "stream_seq", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "stream_seq", len(b)-len(v))
+				}
+			}
 
 			var tmp uint64
 			tmp, v, err = cbor.ReadUint64Bytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "stream_seq", len(b)-len(v))
 			}
 			x.Stream = tmp
 		default:
 			v, err = cbor.Skip(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
 			}
 		}
 		rest = v
 	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
 	return rest, nil
 }
 
-// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
 func (x *SequencePair) DecodeTrusted(b []byte) ([]byte, error) {
 	if x == nil {
 		return b, cbor.ErrNotNil
@@ -1038,6 +1743,9 @@ func (x *SequencePair) DecodeTrusted(b []byte) ([]byte, error) {
 	if err != nil {
 		return b, err
 	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
 	for i := uint32(0); i < sz; i++ {
 		keyBytes, v, err := cbor.ReadStringZC(rest)
 		if err != nil {
@@ -1046,6 +1754,11 @@ func (x *SequencePair) DecodeTrusted(b []byte) ([]byte, error) {
 		key := cbor.UnsafeString(keyBytes)
 		switch key {
 		case "consumer_seq":
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
 			var tmp uint64
 			tmp, v, err = cbor.ReadUint64Bytes(v)
@@ -1054,6 +1767,11 @@ func (x *SequencePair) DecodeTrusted(b []byte) ([]byte, error) {
 			}
 			x.Consumer = tmp
 		case "stream_seq":
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
 			var tmp uint64
 			tmp, v, err = cbor.ReadUint64Bytes(v)
@@ -1072,17 +1790,55 @@ func (x *SequencePair) DecodeTrusted(b []byte) ([]byte, error) {
 	return rest, nil
 }
 
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *SequencePair) resetCBOR() {
+	var zero SequencePair
+	x.Consumer = zero.Consumer
+	x.Stream = zero.Stream
+}
+
 // UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
 func (x *SequencePair) UnmarshalCBOR(b []byte) ([]byte, error) {
 	return x.DecodeSafe(b)
 }
 
+// NewSequencePairFromCBOR decodes b into a newly allocated SequencePair using the
+// Safe path. Bytes after the item fail with cbor.ErrTrailingBytes unless
+// cbor.AllowTrailingBytes is set, in which case they are returned.
+func NewSequencePairFromCBOR(b []byte) (*SequencePair, []byte, error) {
+	x := new(SequencePair)
+	o, err := x.DecodeSafe(b)
+	if err == nil {
+		err = cbor.CheckTrailingBytes(b, o)
+	}
+	if err != nil {
+		return nil, b, err
+	}
+	return x, o, nil
+}
+
 func (x Pending) Msgsize() (s int) {
 	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("sequence") + cbor.Uint64Size + cbor.StringPrefixSize + len("ts") + cbor.Int64Size
 	return
 }
 
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
 func (x *Pending) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *Pending) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *Pending) AppendCBOR(b []byte) ([]byte, error) {
 	if x == nil {
 		return cbor.AppendNil(b), nil
 	}
@@ -1105,49 +1861,94 @@ func (x *Pending) MarshalCBOR(b []byte) ([]byte, error) {
 	return b, nil
 }
 
-// DecodeSafe decodes using validated, allocating string handling.
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
 func (x *Pending) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *Pending) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
 	if x == nil {
 		return b, cbor.ErrNotNil
 	}
 	sz, rest, err := cbor.ReadMapHeaderBytes(b)
 	if err != nil {
-		return b, err
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
 	}
+	var seen [1]uint64
 	for i := uint32(0); i < sz; i++ {
-		key, v, err := cbor.ReadStringBytes(rest)
+		key, v, err := in.ReadStringBytes(rest)
 		if err != nil {
-			return b, err
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
 		}
 		switch key {
 		case "sequence":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "sequence", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "sequence", len(b)-len(v))
+				}
+			}
 
 			var tmp uint64
 			tmp, v, err = cbor.ReadUint64Bytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "sequence", len(b)-len(v))
 			}
 			x.Sequence = tmp
 		case "ts":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "ts", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "ts", len(b)-len(v))
+				}
+			}
 
 			var tmp int64
 			tmp, v, err = cbor.ReadInt64Bytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "ts", len(b)-len(v))
 			}
 			x.Timestamp = tmp
 		default:
 			v, err = cbor.Skip(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
 			}
 		}
 		rest = v
 	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
 	return rest, nil
 }
 
-// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
 func (x *Pending) DecodeTrusted(b []byte) ([]byte, error) {
 	if x == nil {
 		return b, cbor.ErrNotNil
@@ -1156,6 +1957,9 @@ func (x *Pending) DecodeTrusted(b []byte) ([]byte, error) {
 	if err != nil {
 		return b, err
 	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
 	for i := uint32(0); i < sz; i++ {
 		keyBytes, v, err := cbor.ReadStringZC(rest)
 		if err != nil {
@@ -1164,6 +1968,11 @@ func (x *Pending) DecodeTrusted(b []byte) ([]byte, error) {
 		key := cbor.UnsafeString(keyBytes)
 		switch key {
 		case "sequence":
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
 			var tmp uint64
 			tmp, v, err = cbor.ReadUint64Bytes(v)
@@ -1172,6 +1981,11 @@ func (x *Pending) DecodeTrusted(b []byte) ([]byte, error) {
 			}
 			x.Sequence = tmp
 		case "ts":
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
 			var tmp int64
 			tmp, v, err = cbor.ReadInt64Bytes(v)
@@ -1190,12 +2004,47 @@ func (x *Pending) DecodeTrusted(b []byte) ([]byte, error) {
 	return rest, nil
 }
 
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *Pending) resetCBOR() {
+	var zero Pending
+	x.Sequence = zero.Sequence
+	x.Timestamp = zero.Timestamp
+}
+
 // UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
 func (x *Pending) UnmarshalCBOR(b []byte) ([]byte, error) {
 	return x.DecodeSafe(b)
 }
 
+// NewPendingFromCBOR decodes b into a newly allocated Pending using the
+// Safe path. Bytes after the item fail with cbor.ErrTrailingBytes unless
+// cbor.AllowTrailingBytes is set, in which case they are returned.
+func NewPendingFromCBOR(b []byte) (*Pending, []byte, error) {
+	x := new(Pending)
+	o, err := x.DecodeSafe(b)
+	if err == nil {
+		err = cbor.CheckTrailingBytes(b, o)
+	}
+	if err != nil {
+		return nil, b, err
+	}
+	return x, o, nil
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
 func (x *ConsumerState) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *ConsumerState) MarshalCBORTo(w io.Writer) (int, error) {
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *ConsumerState) AppendCBOR(b []byte) ([]byte, error) {
 	if x == nil {
 		return cbor.AppendNil(b), nil
 	}
@@ -1212,77 +2061,151 @@ func (x *ConsumerState) MarshalCBOR(b []byte) ([]byte, error) {
 	b = cbor.AppendMapHeader(b, count)
 	var err error
 	b = cbor.AppendString(b, "delivered")
-	b, err = x.Delivered.MarshalCBOR(b)
+	b, err = x.Delivered.AppendCBOR(b)
 	if err != nil {
 		return b, err
 	}
 	b = cbor.AppendString(b, "ack_floor")
-	b, err = x.AckFloor.MarshalCBOR(b)
+	b, err = x.AckFloor.AppendCBOR(b)
 	if err != nil {
 		return b, err
 	}
 	if !(len(x.Pending) == 0) {
-
-		b = cbor.AppendString(b, "pending")
-		b = cbor.AppendMapHeader(b, uint32(len(x.Pending)))
-		for k, v := range x.Pending {
-			b = cbor.AppendUint64(b, k)
-			if v == nil {
-				b = cbor.AppendNil(b)
-			} else {
-				b, err = v.MarshalCBOR(b)
+		if x.Pending == nil && cbor.NilContainers == cbor.NilAsNull {
+			b = cbor.AppendString(b, "pending")
+			b = cbor.AppendNil(b)
+		} else {
+			b = cbor.AppendString(b, "pending")
+			if cbor.CanonicalMapEncode {
+				b, err = cbor.AppendMapDeterministic(b, x.Pending, cbor.EncKeyUint64, func(b []byte, v *Pending) ([]byte, error) {
+					if v == nil {
+						return cbor.AppendNil(b), nil
+					}
+					return v.AppendCBOR(b)
+				})
 				if err != nil {
 					return b, err
 				}
+			} else {
+				b = cbor.AppendMapHeader(b, uint32(len(x.Pending)))
+				for k, v := range x.Pending {
+					b = cbor.AppendUint64(b, k)
+					if v == nil {
+						b = cbor.AppendNil(b)
+					} else {
+						b, err = v.AppendCBOR(b)
+						if err != nil {
+							return b, err
+						}
+					}
+				}
 			}
 		}
 	}
 	if !(len(x.Redelivered) == 0) {
-
-		b = cbor.AppendString(b, "redelivered")
-		b = cbor.AppendMapHeader(b, uint32(len(x.Redelivered)))
-		for k, v := range x.Redelivered {
-			b = cbor.AppendUint64(b, k)
-			b = cbor.AppendUint64(b, v)
+		if x.Redelivered == nil && cbor.NilContainers == cbor.NilAsNull {
+			b = cbor.AppendString(b, "redelivered")
+			b = cbor.AppendNil(b)
+		} else {
+			b = cbor.AppendString(b, "redelivered")
+			if cbor.CanonicalMapEncode {
+				b, err = cbor.AppendMapDeterministic(b, x.Redelivered, cbor.EncKeyUint64, cbor.EncValUint64)
+				if err != nil {
+					return b, err
+				}
+			} else {
+				b = cbor.AppendMapHeader(b, uint32(len(x.Redelivered)))
+				for k, v := range x.Redelivered {
+					b = cbor.AppendUint64(b, k)
+					b = cbor.AppendUint64(b, v)
+				}
+			}
 		}
 	}
 
 	return b, nil
 }
 
-// DecodeSafe decodes using validated, allocating string handling.
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
 func (x *ConsumerState) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *ConsumerState) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
 	if x == nil {
 		return b, cbor.ErrNotNil
 	}
 	sz, rest, err := cbor.ReadMapHeaderBytes(b)
 	if err != nil {
-		return b, err
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
 	}
+	var seen [1]uint64
 	for i := uint32(0); i < sz; i++ {
-		key, v, err := cbor.ReadStringBytes(rest)
+		key, v, err := in.ReadStringBytes(rest)
 		if err != nil {
-			return b, err
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
 		}
 		switch key {
 		case "delivered":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "delivered", len(b)-len(v))
+				}
+				v = o
+				break
+			}
 
-			v, err = x.Delivered.UnmarshalCBOR(v)
+			v, err = x.Delivered.DecodeInterned(v, in)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "delivered", len(b)-len(v))
 			}
 		case "ack_floor":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "ack_floor", len(b)-len(v))
+				}
+				v = o
+				break
+			}
 
-			v, err = x.AckFloor.UnmarshalCBOR(v)
+			v, err = x.AckFloor.DecodeInterned(v, in)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "ack_floor", len(b)-len(v))
 			}
 		case "pending":
-
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 2); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "pending", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "pending", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Pending = cbor.NullMap(x.Pending)
+				break
+			}
 			var sz uint32
 			sz, v, err = cbor.ReadMapHeaderBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "pending", len(b)-len(v))
 			}
 			if x.Pending == nil && sz > 0 {
 				x.Pending = make(map[uint64]*Pending, sz)
@@ -1293,34 +2216,50 @@ func (x *ConsumerState) DecodeSafe(b []byte) ([]byte, error) {
 				var key uint64
 				key, v, err = cbor.ReadUint64Bytes(v)
 				if err != nil {
-					return b, err
+					return b, cbor.WrapDecodeError(err, "pending", len(b)-len(v))
 				}
 				if len(v) == 0 {
-					return b, cbor.ErrShortBytes
+					return b, cbor.WrapDecodeError(cbor.ErrShortBytes, "pending", len(b)-len(v))
 				}
 				if v[0] == 0xf6 { // null
 					var tmpBytes []byte
 					tmpBytes, err = cbor.ReadNilBytes(v)
 					if err != nil {
-						return b, err
+						return b, cbor.WrapDecodeError(err, "pending", len(b)-len(v))
 					}
 					v = tmpBytes
 					x.Pending[key] = nil
 					continue
 				}
 				tmp := new(Pending)
-				v, err = tmp.UnmarshalCBOR(v)
+				v, err = tmp.DecodeInterned(v, in)
 				if err != nil {
-					return b, err
+					return b, cbor.WrapDecodeError(err, "pending", len(b)-len(v))
 				}
 				x.Pending[key] = tmp
 			}
 		case "redelivered":
-
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 3); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "redelivered", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "redelivered", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Redelivered = cbor.NullMap(x.Redelivered)
+				break
+			}
 			var sz uint32
 			sz, v, err = cbor.ReadMapHeaderBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "redelivered", len(b)-len(v))
 			}
 			if x.Redelivered == nil && sz > 0 {
 				x.Redelivered = make(map[uint64]uint64, sz)
@@ -1331,27 +2270,31 @@ func (x *ConsumerState) DecodeSafe(b []byte) ([]byte, error) {
 				var key uint64
 				key, v, err = cbor.ReadUint64Bytes(v)
 				if err != nil {
-					return b, err
+					return b, cbor.WrapDecodeError(err, "redelivered", len(b)-len(v))
 				}
 				var val uint64
 				val, v, err = cbor.ReadUint64Bytes(v)
 				if err != nil {
-					return b, err
+					return b, cbor.WrapDecodeError(err, "redelivered", len(b)-len(v))
 				}
 				x.Redelivered[key] = val
 			}
 		default:
 			v, err = cbor.Skip(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
 			}
 		}
 		rest = v
 	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
 	return rest, nil
 }
 
-// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
 func (x *ConsumerState) DecodeTrusted(b []byte) ([]byte, error) {
 	if x == nil {
 		return b, cbor.ErrNotNil
@@ -1360,6 +2303,9 @@ func (x *ConsumerState) DecodeTrusted(b []byte) ([]byte, error) {
 	if err != nil {
 		return b, err
 	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
 	for i := uint32(0); i < sz; i++ {
 		keyBytes, v, err := cbor.ReadStringZC(rest)
 		if err != nil {
@@ -1380,7 +2326,16 @@ func (x *ConsumerState) DecodeTrusted(b []byte) ([]byte, error) {
 				return b, err
 			}
 		case "pending":
-
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Pending = cbor.NullMap(x.Pending)
+				break
+			}
 			var sz uint32
 			sz, v, err = cbor.ReadMapHeaderBytes(v)
 			if err != nil {
@@ -1416,7 +2371,16 @@ func (x *ConsumerState) DecodeTrusted(b []byte) ([]byte, error) {
 				x.Pending[key] = val
 			}
 		case "redelivered":
-
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Redelivered = cbor.NullMap(x.Redelivered)
+				break
+			}
 			var sz uint32
 			sz, v, err = cbor.ReadMapHeaderBytes(v)
 			if err != nil {
@@ -1449,17 +2413,57 @@ func (x *ConsumerState) DecodeTrusted(b []byte) ([]byte, error) {
 	return rest, nil
 }
 
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *ConsumerState) resetCBOR() {
+	var zero ConsumerState
+	x.Delivered = zero.Delivered
+	x.AckFloor = zero.AckFloor
+	clear(x.Pending)
+	clear(x.Redelivered)
+}
+
 // UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
 func (x *ConsumerState) UnmarshalCBOR(b []byte) ([]byte, error) {
 	return x.DecodeSafe(b)
 }
 
+// NewConsumerStateFromCBOR decodes b into a newly allocated ConsumerState using the
+// Safe path. Bytes after the item fail with cbor.ErrTrailingBytes unless
+// cbor.AllowTrailingBytes is set, in which case they are returned.
+func NewConsumerStateFromCBOR(b []byte) (*ConsumerState, []byte, error) {
+	x := new(ConsumerState)
+	o, err := x.DecodeSafe(b)
+	if err == nil {
+		err = cbor.CheckTrailingBytes(b, o)
+	}
+	if err != nil {
+		return nil, b, err
+	}
+	return x, o, nil
+}
+
 func (x consumerAssignment) Msgsize() (s int) {
 	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("created") + cbor.TimeSize + cbor.StringPrefixSize + len("name") + cbor.StringPrefixSize + len(x.Name) + cbor.StringPrefixSize + len("stream") + cbor.StringPrefixSize + len(x.Stream)
 	return
 }
 
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
 func (x *consumerAssignment) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *consumerAssignment) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *consumerAssignment) AppendCBOR(b []byte) ([]byte, error) {
 	if x == nil {
 		return cbor.AppendNil(b), nil
 	}
@@ -1482,7 +2486,7 @@ func (x *consumerAssignment) MarshalCBOR(b []byte) ([]byte, error) {
 	var err error
 	if !(x.Client == nil) {
 		b = cbor.AppendString(b, "client")
-		b, err = cbor.AppendPtrMarshaler(b, x.Client)
+		b, err = x.Client.AppendCBOR(b)
 		if err != nil {
 			return b, err
 		}
@@ -1508,13 +2512,13 @@ func (x *consumerAssignment) MarshalCBOR(b []byte) ([]byte, error) {
 		return b, err
 	}
 	b = cbor.AppendString(b, "group")
-	b, err = cbor.AppendPtrMarshaler(b, x.Group)
+	b, err = x.Group.AppendCBOR(b)
 	if err != nil {
 		return b, err
 	}
 	if !(x.State == nil) {
 		b = cbor.AppendString(b, "state")
-		b, err = cbor.AppendPtrMarshaler(b, x.State)
+		b, err = x.State.AppendCBOR(b)
 		if err != nil {
 			return b, err
 		}
@@ -1523,90 +2527,191 @@ func (x *consumerAssignment) MarshalCBOR(b []byte) ([]byte, error) {
 	return b, nil
 }
 
-// DecodeSafe decodes using validated, allocating string handling.
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
 func (x *consumerAssignment) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *consumerAssignment) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
 	if x == nil {
 		return b, cbor.ErrNotNil
 	}
 	sz, rest, err := cbor.ReadMapHeaderBytes(b)
 	if err != nil {
-		return b, err
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
 	}
+	var seen [1]uint64
 	for i := uint32(0); i < sz; i++ {
-		key, v, err := cbor.ReadStringBytes(rest)
+		key, v, err := in.ReadStringBytes(rest)
 		if err != nil {
-			return b, err
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
 		}
 		switch key {
 		case "client":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "client", len(b)-len(v))
+				}
+				v = o
+				break
+			}
 
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Client = nil
+				break
+			}
 			if x.Client == nil {
 				x.Client = new(ClientInfo)
+			} else {
+				x.Client.resetCBOR()
 			}
-			v, err = x.Client.UnmarshalCBOR(v)
+			v, err = x.Client.DecodeInterned(v, in)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "client", len(b)-len(v))
 			}
 		case "created":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "created", len(b)-len(v))
+				}
+				v = o
+				break
+			}
 
 			var tmp time.Time
-			tmp, v, err = cbor.ReadTimeBytes(v)
+			tmp, v, err = cbor.ReadAnyTimeBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "created", len(b)-len(v))
 			}
 			x.Created = tmp
 		case "name":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 2); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
+				}
+			}
 
 			var tmp string
-			tmp, v, err = cbor.ReadStringBytes(v)
+			tmp, v, err = in.ReadStringBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
 			}
 			x.Name = tmp
 		case "stream":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 3); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "stream", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "stream", len(b)-len(v))
+				}
+			}
 
 			var tmp string
-			tmp, v, err = cbor.ReadStringBytes(v)
+			tmp, v, err = in.ReadStringBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "stream", len(b)-len(v))
 			}
 			x.Stream = tmp
 		case "consumer":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 4); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "consumer", len(b)-len(v))
+				}
+				v = o
+				break
+			}
 
-			v, err = cbor.Skip(v)
+			v, err = cbor.ReadWithCodec(v, &x.ConfigJSON)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "consumer", len(b)-len(v))
 			}
 		case "group":
-
-			if x.Group == nil {
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 5); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "group", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Group = nil
+				break
+			}
+			if x.Group == nil {
 				x.Group = new(RaftGroup)
+			} else {
+				x.Group.resetCBOR()
 			}
-			v, err = x.Group.UnmarshalCBOR(v)
+			v, err = x.Group.DecodeInterned(v, in)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "group", len(b)-len(v))
 			}
 		case "state":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 6); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "state", len(b)-len(v))
+				}
+				v = o
+				break
+			}
 
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.State = nil
+				break
+			}
 			if x.State == nil {
 				x.State = new(ConsumerState)
+			} else {
+				x.State.resetCBOR()
 			}
-			v, err = x.State.UnmarshalCBOR(v)
+			v, err = x.State.DecodeInterned(v, in)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "state", len(b)-len(v))
 			}
 		default:
 			v, err = cbor.Skip(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
 			}
 		}
 		rest = v
 	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
 	return rest, nil
 }
 
-// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
 func (x *consumerAssignment) DecodeTrusted(b []byte) ([]byte, error) {
 	if x == nil {
 		return b, cbor.ErrNotNil
@@ -1615,6 +2720,9 @@ func (x *consumerAssignment) DecodeTrusted(b []byte) ([]byte, error) {
 	if err != nil {
 		return b, err
 	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
 	for i := uint32(0); i < sz; i++ {
 		keyBytes, v, err := cbor.ReadStringZC(rest)
 		if err != nil {
@@ -1624,8 +2732,15 @@ func (x *consumerAssignment) DecodeTrusted(b []byte) ([]byte, error) {
 		switch key {
 		case "client":
 
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Client = nil
+				break
+			}
 			if x.Client == nil {
 				x.Client = new(ClientInfo)
+			} else {
+				x.Client.resetCBOR()
 			}
 			v, err = x.Client.DecodeTrusted(v)
 			if err != nil {
@@ -1634,37 +2749,50 @@ func (x *consumerAssignment) DecodeTrusted(b []byte) ([]byte, error) {
 		case "created":
 
 			var tmp time.Time
-			tmp, v, err = cbor.ReadTimeBytes(v)
+			tmp, v, err = cbor.ReadAnyTimeBytes(v)
 			if err != nil {
 				return b, err
 			}
 			x.Created = tmp
 		case "name":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
-			var tmpBytes []byte
-			tmpBytes, v, err = cbor.ReadStringZC(v)
+			x.Name, v, err = cbor.ReadTrustedStringBytes(v)
 			if err != nil {
 				return b, err
 			}
-			x.Name = cbor.UnsafeString(tmpBytes)
 		case "stream":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
-			var tmpBytes []byte
-			tmpBytes, v, err = cbor.ReadStringZC(v)
+			x.Stream, v, err = cbor.ReadTrustedStringBytes(v)
 			if err != nil {
 				return b, err
 			}
-			x.Stream = cbor.UnsafeString(tmpBytes)
 		case "consumer":
 
-			v, err = cbor.Skip(v)
+			v, err = cbor.ReadWithCodec(v, &x.ConfigJSON)
 			if err != nil {
 				return b, err
 			}
 		case "group":
 
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Group = nil
+				break
+			}
 			if x.Group == nil {
 				x.Group = new(RaftGroup)
+			} else {
+				x.Group.resetCBOR()
 			}
 			v, err = x.Group.DecodeTrusted(v)
 			if err != nil {
@@ -1672,8 +2800,15 @@ func (x *consumerAssignment) DecodeTrusted(b []byte) ([]byte, error) {
 			}
 		case "state":
 
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.State = nil
+				break
+			}
 			if x.State == nil {
 				x.State = new(ConsumerState)
+			} else {
+				x.State.resetCBOR()
 			}
 			v, err = x.State.DecodeTrusted(v)
 			if err != nil {
@@ -1690,17 +2825,60 @@ func (x *consumerAssignment) DecodeTrusted(b []byte) ([]byte, error) {
 	return rest, nil
 }
 
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *consumerAssignment) resetCBOR() {
+	var zero consumerAssignment
+	x.Client = zero.Client
+	x.Created = zero.Created
+	x.Name = zero.Name
+	x.Stream = zero.Stream
+	x.ConfigJSON = zero.ConfigJSON
+	x.Group = zero.Group
+	x.State = zero.State
+}
+
 // UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
 func (x *consumerAssignment) UnmarshalCBOR(b []byte) ([]byte, error) {
 	return x.DecodeSafe(b)
 }
 
+// newConsumerAssignmentFromCBOR decodes b into a newly allocated consumerAssignment using the
+// Safe path. Bytes after the item fail with cbor.ErrTrailingBytes unless
+// cbor.AllowTrailingBytes is set, in which case they are returned.
+func newConsumerAssignmentFromCBOR(b []byte) (*consumerAssignment, []byte, error) {
+	x := new(consumerAssignment)
+	o, err := x.DecodeSafe(b)
+	if err == nil {
+		err = cbor.CheckTrailingBytes(b, o)
+	}
+	if err != nil {
+		return nil, b, err
+	}
+	return x, o, nil
+}
+
 func (x streamAssignment) Msgsize() (s int) {
 	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("created") + cbor.TimeSize + cbor.StringPrefixSize + len("sync") + cbor.StringPrefixSize + len(x.Sync)
 	return
 }
 
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
 func (x *streamAssignment) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *streamAssignment) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *streamAssignment) AppendCBOR(b []byte) ([]byte, error) {
 	if x == nil {
 		return cbor.AppendNil(b), nil
 	}
@@ -1719,7 +2897,7 @@ func (x *streamAssignment) MarshalCBOR(b []byte) ([]byte, error) {
 	var err error
 	if !(x.Client == nil) {
 		b = cbor.AppendString(b, "client")
-		b, err = cbor.AppendPtrMarshaler(b, x.Client)
+		b, err = x.Client.AppendCBOR(b)
 		if err != nil {
 			return b, err
 		}
@@ -1735,7 +2913,7 @@ func (x *streamAssignment) MarshalCBOR(b []byte) ([]byte, error) {
 		return b, err
 	}
 	b = cbor.AppendString(b, "group")
-	b, err = cbor.AppendPtrMarshaler(b, x.Group)
+	b, err = x.Group.AppendCBOR(b)
 	if err != nil {
 		return b, err
 	}
@@ -1748,73 +2926,148 @@ func (x *streamAssignment) MarshalCBOR(b []byte) ([]byte, error) {
 	return b, nil
 }
 
-// DecodeSafe decodes using validated, allocating string handling.
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
 func (x *streamAssignment) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *streamAssignment) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
 	if x == nil {
 		return b, cbor.ErrNotNil
 	}
 	sz, rest, err := cbor.ReadMapHeaderBytes(b)
 	if err != nil {
-		return b, err
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
 	}
+	var seen [1]uint64
 	for i := uint32(0); i < sz; i++ {
-		key, v, err := cbor.ReadStringBytes(rest)
+		key, v, err := in.ReadStringBytes(rest)
 		if err != nil {
-			return b, err
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
 		}
 		switch key {
 		case "client":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "client", len(b)-len(v))
+				}
+				v = o
+				break
+			}
 
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Client = nil
+				break
+			}
 			if x.Client == nil {
 				x.Client = new(ClientInfo)
+			} else {
+				x.Client.resetCBOR()
 			}
-			v, err = x.Client.UnmarshalCBOR(v)
+			v, err = x.Client.DecodeInterned(v, in)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "client", len(b)-len(v))
 			}
 		case "created":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "created", len(b)-len(v))
+				}
+				v = o
+				break
+			}
 
 			var tmp time.Time
-			tmp, v, err = cbor.ReadTimeBytes(v)
+			tmp, v, err = cbor.ReadAnyTimeBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "created", len(b)-len(v))
 			}
 			x.Created = tmp
 		case "stream":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 2); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "stream", len(b)-len(v))
+				}
+				v = o
+				break
+			}
 
-			v, err = cbor.Skip(v)
+			v, err = cbor.ReadWithCodec(v, &x.ConfigJSON)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "stream", len(b)-len(v))
 			}
 		case "group":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 3); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "group", len(b)-len(v))
+				}
+				v = o
+				break
+			}
 
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Group = nil
+				break
+			}
 			if x.Group == nil {
 				x.Group = new(RaftGroup)
+			} else {
+				x.Group.resetCBOR()
 			}
-			v, err = x.Group.UnmarshalCBOR(v)
+			v, err = x.Group.DecodeInterned(v, in)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "group", len(b)-len(v))
 			}
 		case "sync":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 4); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "sync", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "sync", len(b)-len(v))
+				}
+			}
 
 			var tmp string
-			tmp, v, err = cbor.ReadStringBytes(v)
+			tmp, v, err = in.ReadStringBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "sync", len(b)-len(v))
 			}
 			x.Sync = tmp
 		default:
 			v, err = cbor.Skip(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
 			}
 		}
 		rest = v
 	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
 	return rest, nil
 }
 
-// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
 func (x *streamAssignment) DecodeTrusted(b []byte) ([]byte, error) {
 	if x == nil {
 		return b, cbor.ErrNotNil
@@ -1823,6 +3076,9 @@ func (x *streamAssignment) DecodeTrusted(b []byte) ([]byte, error) {
 	if err != nil {
 		return b, err
 	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
 	for i := uint32(0); i < sz; i++ {
 		keyBytes, v, err := cbor.ReadStringZC(rest)
 		if err != nil {
@@ -1832,8 +3088,15 @@ func (x *streamAssignment) DecodeTrusted(b []byte) ([]byte, error) {
 		switch key {
 		case "client":
 
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Client = nil
+				break
+			}
 			if x.Client == nil {
 				x.Client = new(ClientInfo)
+			} else {
+				x.Client.resetCBOR()
 			}
 			v, err = x.Client.DecodeTrusted(v)
 			if err != nil {
@@ -1842,34 +3105,44 @@ func (x *streamAssignment) DecodeTrusted(b []byte) ([]byte, error) {
 		case "created":
 
 			var tmp time.Time
-			tmp, v, err = cbor.ReadTimeBytes(v)
+			tmp, v, err = cbor.ReadAnyTimeBytes(v)
 			if err != nil {
 				return b, err
 			}
 			x.Created = tmp
 		case "stream":
 
-			v, err = cbor.Skip(v)
+			v, err = cbor.ReadWithCodec(v, &x.ConfigJSON)
 			if err != nil {
 				return b, err
 			}
 		case "group":
 
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Group = nil
+				break
+			}
 			if x.Group == nil {
 				x.Group = new(RaftGroup)
+			} else {
+				x.Group.resetCBOR()
 			}
 			v, err = x.Group.DecodeTrusted(v)
 			if err != nil {
 				return b, err
 			}
 		case "sync":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
-			var tmpBytes []byte
-			tmpBytes, v, err = cbor.ReadStringZC(v)
+			x.Sync, v, err = cbor.ReadTrustedStringBytes(v)
 			if err != nil {
 				return b, err
 			}
-			x.Sync = cbor.UnsafeString(tmpBytes)
 		default:
 			v, err = cbor.Skip(v)
 			if err != nil {
@@ -1881,17 +3154,58 @@ func (x *streamAssignment) DecodeTrusted(b []byte) ([]byte, error) {
 	return rest, nil
 }
 
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *streamAssignment) resetCBOR() {
+	var zero streamAssignment
+	x.Client = zero.Client
+	x.Created = zero.Created
+	x.ConfigJSON = zero.ConfigJSON
+	x.Group = zero.Group
+	x.Sync = zero.Sync
+}
+
 // UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
 func (x *streamAssignment) UnmarshalCBOR(b []byte) ([]byte, error) {
 	return x.DecodeSafe(b)
 }
 
+// newStreamAssignmentFromCBOR decodes b into a newly allocated streamAssignment using the
+// Safe path. Bytes after the item fail with cbor.ErrTrailingBytes unless
+// cbor.AllowTrailingBytes is set, in which case they are returned.
+func newStreamAssignmentFromCBOR(b []byte) (*streamAssignment, []byte, error) {
+	x := new(streamAssignment)
+	o, err := x.DecodeSafe(b)
+	if err == nil {
+		err = cbor.CheckTrailingBytes(b, o)
+	}
+	if err != nil {
+		return nil, b, err
+	}
+	return x, o, nil
+}
+
 func (x WriteableConsumerAssignment) Msgsize() (s int) {
 	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("created") + cbor.TimeSize + cbor.StringPrefixSize + len("name") + cbor.StringPrefixSize + len(x.Name) + cbor.StringPrefixSize + len("stream") + cbor.StringPrefixSize + len(x.Stream)
 	return
 }
 
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
 func (x *WriteableConsumerAssignment) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *WriteableConsumerAssignment) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *WriteableConsumerAssignment) AppendCBOR(b []byte) ([]byte, error) {
 	if x == nil {
 		return cbor.AppendNil(b), nil
 	}
@@ -1914,7 +3228,7 @@ func (x *WriteableConsumerAssignment) MarshalCBOR(b []byte) ([]byte, error) {
 	var err error
 	if !(x.Client == nil) {
 		b = cbor.AppendString(b, "client")
-		b, err = cbor.AppendPtrMarshaler(b, x.Client)
+		b, err = x.Client.AppendCBOR(b)
 		if err != nil {
 			return b, err
 		}
@@ -1940,13 +3254,13 @@ func (x *WriteableConsumerAssignment) MarshalCBOR(b []byte) ([]byte, error) {
 		return b, err
 	}
 	b = cbor.AppendString(b, "group")
-	b, err = cbor.AppendPtrMarshaler(b, x.Group)
+	b, err = x.Group.AppendCBOR(b)
 	if err != nil {
 		return b, err
 	}
 	if !(x.State == nil) {
 		b = cbor.AppendString(b, "state")
-		b, err = cbor.AppendPtrMarshaler(b, x.State)
+		b, err = x.State.AppendCBOR(b)
 		if err != nil {
 			return b, err
 		}
@@ -1955,90 +3269,191 @@ func (x *WriteableConsumerAssignment) MarshalCBOR(b []byte) ([]byte, error) {
 	return b, nil
 }
 
-// DecodeSafe decodes using validated, allocating string handling.
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
 func (x *WriteableConsumerAssignment) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *WriteableConsumerAssignment) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
 	if x == nil {
 		return b, cbor.ErrNotNil
 	}
 	sz, rest, err := cbor.ReadMapHeaderBytes(b)
 	if err != nil {
-		return b, err
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
 	}
+	var seen [1]uint64
 	for i := uint32(0); i < sz; i++ {
-		key, v, err := cbor.ReadStringBytes(rest)
+		key, v, err := in.ReadStringBytes(rest)
 		if err != nil {
-			return b, err
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
 		}
 		switch key {
 		case "client":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "client", len(b)-len(v))
+				}
+				v = o
+				break
+			}
 
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Client = nil
+				break
+			}
 			if x.Client == nil {
 				x.Client = new(ClientInfo)
+			} else {
+				x.Client.resetCBOR()
 			}
-			v, err = x.Client.UnmarshalCBOR(v)
+			v, err = x.Client.DecodeInterned(v, in)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "client", len(b)-len(v))
 			}
 		case "created":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "created", len(b)-len(v))
+				}
+				v = o
+				break
+			}
 
 			var tmp time.Time
-			tmp, v, err = cbor.ReadTimeBytes(v)
+			tmp, v, err = cbor.ReadAnyTimeBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "created", len(b)-len(v))
 			}
 			x.Created = tmp
 		case "name":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 2); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
+				}
+			}
 
 			var tmp string
-			tmp, v, err = cbor.ReadStringBytes(v)
+			tmp, v, err = in.ReadStringBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
 			}
 			x.Name = tmp
 		case "stream":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 3); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "stream", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "stream", len(b)-len(v))
+				}
+			}
 
 			var tmp string
-			tmp, v, err = cbor.ReadStringBytes(v)
+			tmp, v, err = in.ReadStringBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "stream", len(b)-len(v))
 			}
 			x.Stream = tmp
 		case "consumer":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 4); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "consumer", len(b)-len(v))
+				}
+				v = o
+				break
+			}
 
-			v, err = cbor.Skip(v)
+			v, err = cbor.ReadWithCodec(v, &x.ConfigJSON)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "consumer", len(b)-len(v))
 			}
 		case "group":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 5); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "group", len(b)-len(v))
+				}
+				v = o
+				break
+			}
 
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Group = nil
+				break
+			}
 			if x.Group == nil {
 				x.Group = new(RaftGroup)
+			} else {
+				x.Group.resetCBOR()
 			}
-			v, err = x.Group.UnmarshalCBOR(v)
+			v, err = x.Group.DecodeInterned(v, in)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "group", len(b)-len(v))
 			}
 		case "state":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 6); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "state", len(b)-len(v))
+				}
+				v = o
+				break
+			}
 
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.State = nil
+				break
+			}
 			if x.State == nil {
 				x.State = new(ConsumerState)
+			} else {
+				x.State.resetCBOR()
 			}
-			v, err = x.State.UnmarshalCBOR(v)
+			v, err = x.State.DecodeInterned(v, in)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "state", len(b)-len(v))
 			}
 		default:
 			v, err = cbor.Skip(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
 			}
 		}
 		rest = v
 	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
 	return rest, nil
 }
 
-// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
 func (x *WriteableConsumerAssignment) DecodeTrusted(b []byte) ([]byte, error) {
 	if x == nil {
 		return b, cbor.ErrNotNil
@@ -2047,6 +3462,9 @@ func (x *WriteableConsumerAssignment) DecodeTrusted(b []byte) ([]byte, error) {
 	if err != nil {
 		return b, err
 	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
 	for i := uint32(0); i < sz; i++ {
 		keyBytes, v, err := cbor.ReadStringZC(rest)
 		if err != nil {
@@ -2056,8 +3474,15 @@ func (x *WriteableConsumerAssignment) DecodeTrusted(b []byte) ([]byte, error) {
 		switch key {
 		case "client":
 
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Client = nil
+				break
+			}
 			if x.Client == nil {
 				x.Client = new(ClientInfo)
+			} else {
+				x.Client.resetCBOR()
 			}
 			v, err = x.Client.DecodeTrusted(v)
 			if err != nil {
@@ -2066,37 +3491,50 @@ func (x *WriteableConsumerAssignment) DecodeTrusted(b []byte) ([]byte, error) {
 		case "created":
 
 			var tmp time.Time
-			tmp, v, err = cbor.ReadTimeBytes(v)
+			tmp, v, err = cbor.ReadAnyTimeBytes(v)
 			if err != nil {
 				return b, err
 			}
 			x.Created = tmp
 		case "name":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
-			var tmpBytes []byte
-			tmpBytes, v, err = cbor.ReadStringZC(v)
+			x.Name, v, err = cbor.ReadTrustedStringBytes(v)
 			if err != nil {
 				return b, err
 			}
-			x.Name = cbor.UnsafeString(tmpBytes)
 		case "stream":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
-			var tmpBytes []byte
-			tmpBytes, v, err = cbor.ReadStringZC(v)
+			x.Stream, v, err = cbor.ReadTrustedStringBytes(v)
 			if err != nil {
 				return b, err
 			}
-			x.Stream = cbor.UnsafeString(tmpBytes)
 		case "consumer":
 
-			v, err = cbor.Skip(v)
+			v, err = cbor.ReadWithCodec(v, &x.ConfigJSON)
 			if err != nil {
 				return b, err
 			}
 		case "group":
 
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Group = nil
+				break
+			}
 			if x.Group == nil {
 				x.Group = new(RaftGroup)
+			} else {
+				x.Group.resetCBOR()
 			}
 			v, err = x.Group.DecodeTrusted(v)
 			if err != nil {
@@ -2104,8 +3542,15 @@ func (x *WriteableConsumerAssignment) DecodeTrusted(b []byte) ([]byte, error) {
 			}
 		case "state":
 
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.State = nil
+				break
+			}
 			if x.State == nil {
 				x.State = new(ConsumerState)
+			} else {
+				x.State.resetCBOR()
 			}
 			v, err = x.State.DecodeTrusted(v)
 			if err != nil {
@@ -2122,17 +3567,60 @@ func (x *WriteableConsumerAssignment) DecodeTrusted(b []byte) ([]byte, error) {
 	return rest, nil
 }
 
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *WriteableConsumerAssignment) resetCBOR() {
+	var zero WriteableConsumerAssignment
+	x.Client = zero.Client
+	x.Created = zero.Created
+	x.Name = zero.Name
+	x.Stream = zero.Stream
+	x.ConfigJSON = zero.ConfigJSON
+	x.Group = zero.Group
+	x.State = zero.State
+}
+
 // UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
 func (x *WriteableConsumerAssignment) UnmarshalCBOR(b []byte) ([]byte, error) {
 	return x.DecodeSafe(b)
 }
 
+// NewWriteableConsumerAssignmentFromCBOR decodes b into a newly allocated WriteableConsumerAssignment using the
+// Safe path. Bytes after the item fail with cbor.ErrTrailingBytes unless
+// cbor.AllowTrailingBytes is set, in which case they are returned.
+func NewWriteableConsumerAssignmentFromCBOR(b []byte) (*WriteableConsumerAssignment, []byte, error) {
+	x := new(WriteableConsumerAssignment)
+	o, err := x.DecodeSafe(b)
+	if err == nil {
+		err = cbor.CheckTrailingBytes(b, o)
+	}
+	if err != nil {
+		return nil, b, err
+	}
+	return x, o, nil
+}
+
 func (x WriteableStreamAssignment) Msgsize() (s int) {
 	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("created") + cbor.TimeSize + cbor.StringPrefixSize + len("sync") + cbor.StringPrefixSize + len(x.Sync)
 	return
 }
 
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
 func (x *WriteableStreamAssignment) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *WriteableStreamAssignment) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *WriteableStreamAssignment) AppendCBOR(b []byte) ([]byte, error) {
 	if x == nil {
 		return cbor.AppendNil(b), nil
 	}
@@ -2154,7 +3642,7 @@ func (x *WriteableStreamAssignment) MarshalCBOR(b []byte) ([]byte, error) {
 	var err error
 	if !(x.Client == nil) {
 		b = cbor.AppendString(b, "client")
-		b, err = cbor.AppendPtrMarshaler(b, x.Client)
+		b, err = x.Client.AppendCBOR(b)
 		if err != nil {
 			return b, err
 		}
@@ -2170,7 +3658,7 @@ func (x *WriteableStreamAssignment) MarshalCBOR(b []byte) ([]byte, error) {
 		return b, err
 	}
 	b = cbor.AppendString(b, "group")
-	b, err = cbor.AppendPtrMarshaler(b, x.Group)
+	b, err = x.Group.AppendCBOR(b)
 	if err != nil {
 		return b, err
 	}
@@ -2180,16 +3668,20 @@ func (x *WriteableStreamAssignment) MarshalCBOR(b []byte) ([]byte, error) {
 		return b, err
 	}
 	if !(len(x.Consumers) == 0) {
-
-		b = cbor.AppendString(b, "consumers")
-		b = cbor.AppendArrayHeader(b, uint32(len(x.Consumers)))
-		for _, w := range x.Consumers {
-			if w == nil {
-				b = cbor.AppendNil(b)
-			} else {
-				b, err = w.MarshalCBOR(b)
-				if err != nil {
-					return b, err
+		if x.Consumers == nil && cbor.NilContainers == cbor.NilAsNull {
+			b = cbor.AppendString(b, "consumers")
+			b = cbor.AppendNil(b)
+		} else {
+			b = cbor.AppendString(b, "consumers")
+			b = cbor.AppendArrayHeader(b, uint32(len(x.Consumers)))
+			for _, w := range x.Consumers {
+				if w == nil {
+					b = cbor.AppendNil(b)
+				} else {
+					b, err = w.AppendCBOR(b)
+					if err != nil {
+						return b, err
+					}
 				}
 			}
 		}
@@ -2198,67 +3690,155 @@ func (x *WriteableStreamAssignment) MarshalCBOR(b []byte) ([]byte, error) {
 	return b, nil
 }
 
-// DecodeSafe decodes using validated, allocating string handling.
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
 func (x *WriteableStreamAssignment) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *WriteableStreamAssignment) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
 	if x == nil {
 		return b, cbor.ErrNotNil
 	}
 	sz, rest, err := cbor.ReadMapHeaderBytes(b)
 	if err != nil {
-		return b, err
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
 	}
+	var seen [1]uint64
 	for i := uint32(0); i < sz; i++ {
-		key, v, err := cbor.ReadStringBytes(rest)
+		key, v, err := in.ReadStringBytes(rest)
 		if err != nil {
-			return b, err
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
 		}
 		switch key {
 		case "client":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "client", len(b)-len(v))
+				}
+				v = o
+				break
+			}
 
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Client = nil
+				break
+			}
 			if x.Client == nil {
 				x.Client = new(ClientInfo)
+			} else {
+				x.Client.resetCBOR()
 			}
-			v, err = x.Client.UnmarshalCBOR(v)
+			v, err = x.Client.DecodeInterned(v, in)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "client", len(b)-len(v))
 			}
 		case "created":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "created", len(b)-len(v))
+				}
+				v = o
+				break
+			}
 
 			var tmp time.Time
-			tmp, v, err = cbor.ReadTimeBytes(v)
+			tmp, v, err = cbor.ReadAnyTimeBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "created", len(b)-len(v))
 			}
 			x.Created = tmp
 		case "stream":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 2); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "stream", len(b)-len(v))
+				}
+				v = o
+				break
+			}
 
-			v, err = cbor.Skip(v)
+			v, err = cbor.ReadWithCodec(v, &x.ConfigJSON)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "stream", len(b)-len(v))
 			}
 		case "group":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 3); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "group", len(b)-len(v))
+				}
+				v = o
+				break
+			}
 
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Group = nil
+				break
+			}
 			if x.Group == nil {
 				x.Group = new(RaftGroup)
+			} else {
+				x.Group.resetCBOR()
 			}
-			v, err = x.Group.UnmarshalCBOR(v)
+			v, err = x.Group.DecodeInterned(v, in)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "group", len(b)-len(v))
 			}
 		case "sync":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 4); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "sync", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "sync", len(b)-len(v))
+				}
+			}
 
 			var tmp string
-			tmp, v, err = cbor.ReadStringBytes(v)
+			tmp, v, err = in.ReadStringBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "sync", len(b)-len(v))
 			}
 			x.Sync = tmp
 		case "consumers":
-
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 5); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "consumers", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "consumers", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Consumers = cbor.NullSlice(x.Consumers)
+				break
+			}
 			var sz uint32
-			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "consumers", len(b)-len(v))
 			}
 			if cap(x.Consumers) >= int(sz) {
 				x.Consumers = x.Consumers[:sz]
@@ -2269,26 +3849,40 @@ func (x *WriteableStreamAssignment) DecodeSafe(b []byte) ([]byte, error) {
 				_ = x.Consumers[sz-1]
 			}
 			for iConsumers := uint32(0); iConsumers < sz; iConsumers++ {
+				if cbor.IsNilOrUndefined(v) {
+					v = v[1:]
+					x.Consumers[iConsumers] = nil
+					continue
+				}
 				if x.Consumers[iConsumers] == nil {
 					x.Consumers[iConsumers] = new(WriteableConsumerAssignment)
+				} else {
+					x.Consumers[iConsumers].resetCBOR()
 				}
-				v, err = x.Consumers[iConsumers].UnmarshalCBOR(v)
+				v, err = x.Consumers[iConsumers].DecodeInterned(v, in)
 				if err != nil {
-					return b, err
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, int(iConsumers)), "consumers", len(b)-len(v))
 				}
 			}
+			if indef {
+				v = v[1:] // break
+			}
 		default:
 			v, err = cbor.Skip(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
 			}
 		}
 		rest = v
 	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
 	return rest, nil
 }
 
-// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
 func (x *WriteableStreamAssignment) DecodeTrusted(b []byte) ([]byte, error) {
 	if x == nil {
 		return b, cbor.ErrNotNil
@@ -2297,6 +3891,9 @@ func (x *WriteableStreamAssignment) DecodeTrusted(b []byte) ([]byte, error) {
 	if err != nil {
 		return b, err
 	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
 	for i := uint32(0); i < sz; i++ {
 		keyBytes, v, err := cbor.ReadStringZC(rest)
 		if err != nil {
@@ -2306,8 +3903,15 @@ func (x *WriteableStreamAssignment) DecodeTrusted(b []byte) ([]byte, error) {
 		switch key {
 		case "client":
 
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Client = nil
+				break
+			}
 			if x.Client == nil {
 				x.Client = new(ClientInfo)
+			} else {
+				x.Client.resetCBOR()
 			}
 			v, err = x.Client.DecodeTrusted(v)
 			if err != nil {
@@ -2316,38 +3920,58 @@ func (x *WriteableStreamAssignment) DecodeTrusted(b []byte) ([]byte, error) {
 		case "created":
 
 			var tmp time.Time
-			tmp, v, err = cbor.ReadTimeBytes(v)
+			tmp, v, err = cbor.ReadAnyTimeBytes(v)
 			if err != nil {
 				return b, err
 			}
 			x.Created = tmp
 		case "stream":
 
-			v, err = cbor.Skip(v)
+			v, err = cbor.ReadWithCodec(v, &x.ConfigJSON)
 			if err != nil {
 				return b, err
 			}
 		case "group":
 
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Group = nil
+				break
+			}
 			if x.Group == nil {
 				x.Group = new(RaftGroup)
+			} else {
+				x.Group.resetCBOR()
 			}
 			v, err = x.Group.DecodeTrusted(v)
 			if err != nil {
 				return b, err
 			}
 		case "sync":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
-			var tmpBytes []byte
-			tmpBytes, v, err = cbor.ReadStringZC(v)
+			x.Sync, v, err = cbor.ReadTrustedStringBytes(v)
 			if err != nil {
 				return b, err
 			}
-			x.Sync = cbor.UnsafeString(tmpBytes)
 		case "consumers":
-
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Consumers = cbor.NullSlice(x.Consumers)
+				break
+			}
 			var sz uint32
-			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
 			if err != nil {
 				return b, err
 			}
@@ -2360,14 +3984,24 @@ func (x *WriteableStreamAssignment) DecodeTrusted(b []byte) ([]byte, error) {
 				_ = x.Consumers[sz-1]
 			}
 			for iConsumers := uint32(0); iConsumers < sz; iConsumers++ {
+				if cbor.IsNilOrUndefined(v) {
+					v = v[1:]
+					x.Consumers[iConsumers] = nil
+					continue
+				}
 				if x.Consumers[iConsumers] == nil {
 					x.Consumers[iConsumers] = new(WriteableConsumerAssignment)
+				} else {
+					x.Consumers[iConsumers].resetCBOR()
 				}
 				v, err = x.Consumers[iConsumers].DecodeTrusted(v)
 				if err != nil {
 					return b, err
 				}
 			}
+			if indef {
+				v = v[1:] // break
+			}
 		default:
 			v, err = cbor.Skip(v)
 			if err != nil {
@@ -2379,17 +4013,59 @@ func (x *WriteableStreamAssignment) DecodeTrusted(b []byte) ([]byte, error) {
 	return rest, nil
 }
 
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *WriteableStreamAssignment) resetCBOR() {
+	var zero WriteableStreamAssignment
+	x.Client = zero.Client
+	x.Created = zero.Created
+	x.ConfigJSON = zero.ConfigJSON
+	x.Group = zero.Group
+	x.Sync = zero.Sync
+	x.Consumers = x.Consumers[:0]
+}
+
 // UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
 func (x *WriteableStreamAssignment) UnmarshalCBOR(b []byte) ([]byte, error) {
 	return x.DecodeSafe(b)
 }
 
+// NewWriteableStreamAssignmentFromCBOR decodes b into a newly allocated WriteableStreamAssignment using the
+// Safe path. Bytes after the item fail with cbor.ErrTrailingBytes unless
+// cbor.AllowTrailingBytes is set, in which case they are returned.
+func NewWriteableStreamAssignmentFromCBOR(b []byte) (*WriteableStreamAssignment, []byte, error) {
+	x := new(WriteableStreamAssignment)
+	o, err := x.DecodeSafe(b)
+	if err == nil {
+		err = cbor.CheckTrailingBytes(b, o)
+	}
+	if err != nil {
+		return nil, b, err
+	}
+	return x, o, nil
+}
+
 func (x MetaSnapshot) Msgsize() (s int) {
 	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("streams") + cbor.ArrayHeaderSize + len(x.Streams)*0
 	return
 }
 
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
 func (x *MetaSnapshot) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *MetaSnapshot) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *MetaSnapshot) AppendCBOR(b []byte) ([]byte, error) {
 	if x == nil {
 		return cbor.AppendNil(b), nil
 	}
@@ -2398,40 +4074,78 @@ func (x *MetaSnapshot) MarshalCBOR(b []byte) ([]byte, error) {
 
 	b = cbor.AppendMapHeader(b, uint32(1))
 	var err error
-
-	b = cbor.AppendString(b, "streams")
-	b = cbor.AppendArrayHeader(b, uint32(len(x.Streams)))
-	for i := range x.Streams {
-		b, err = x.Streams[i].MarshalCBOR(b)
-		if err != nil {
-			return b, err
+	if x.Streams == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = cbor.AppendString(b, "streams")
+		b = cbor.AppendNil(b)
+	} else {
+		b = cbor.AppendString(b, "streams")
+		b = cbor.AppendArrayHeader(b, uint32(len(x.Streams)))
+		for i := range x.Streams {
+			b, err = x.Streams[i].AppendCBOR(b)
+			if err != nil {
+				return b, err
+			}
 		}
 	}
 
 	return b, nil
 }
 
-// DecodeSafe decodes using validated, allocating string handling.
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
 func (x *MetaSnapshot) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *MetaSnapshot) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
 	if x == nil {
 		return b, cbor.ErrNotNil
 	}
 	sz, rest, err := cbor.ReadMapHeaderBytes(b)
 	if err != nil {
-		return b, err
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
 	}
+	var seen [1]uint64
 	for i := uint32(0); i < sz; i++ {
-		key, v, err := cbor.ReadStringBytes(rest)
+		key, v, err := in.ReadStringBytes(rest)
 		if err != nil {
-			return b, err
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
 		}
 		switch key {
 		case "streams":
-
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "streams", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "streams", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Streams = cbor.NullSlice(x.Streams)
+				break
+			}
 			var sz uint32
-			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "streams", len(b)-len(v))
 			}
 			if cap(x.Streams) >= int(sz) {
 				x.Streams = x.Streams[:sz]
@@ -2442,25 +4156,31 @@ func (x *MetaSnapshot) DecodeSafe(b []byte) ([]byte, error) {
 				_ = x.Streams[sz-1]
 			}
 			for iStreams := uint32(0); iStreams < sz; iStreams++ {
-				var tmp WriteableStreamAssignment
-				v, err = (&tmp).UnmarshalCBOR(v)
+				x.Streams[iStreams].resetCBOR()
+				v, err = x.Streams[iStreams].DecodeInterned(v, in)
 				if err != nil {
-					return b, err
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, int(iStreams)), "streams", len(b)-len(v))
 				}
-				x.Streams[iStreams] = tmp
+			}
+			if indef {
+				v = v[1:] // break
 			}
 		default:
 			v, err = cbor.Skip(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
 			}
 		}
 		rest = v
 	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
 	return rest, nil
 }
 
-// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
 func (x *MetaSnapshot) DecodeTrusted(b []byte) ([]byte, error) {
 	if x == nil {
 		return b, cbor.ErrNotNil
@@ -2469,6 +4189,9 @@ func (x *MetaSnapshot) DecodeTrusted(b []byte) ([]byte, error) {
 	if err != nil {
 		return b, err
 	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
 	for i := uint32(0); i < sz; i++ {
 		keyBytes, v, err := cbor.ReadStringZC(rest)
 		if err != nil {
@@ -2477,9 +4200,19 @@ func (x *MetaSnapshot) DecodeTrusted(b []byte) ([]byte, error) {
 		key := cbor.UnsafeString(keyBytes)
 		switch key {
 		case "streams":
-
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Streams = cbor.NullSlice(x.Streams)
+				break
+			}
 			var sz uint32
-			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
 			if err != nil {
 				return b, err
 			}
@@ -2492,12 +4225,14 @@ func (x *MetaSnapshot) DecodeTrusted(b []byte) ([]byte, error) {
 				_ = x.Streams[sz-1]
 			}
 			for iStreams := uint32(0); iStreams < sz; iStreams++ {
-				var tmp WriteableStreamAssignment
-				v, err = (&tmp).DecodeTrusted(v)
+				x.Streams[iStreams].resetCBOR()
+				v, err = x.Streams[iStreams].DecodeTrusted(v)
 				if err != nil {
 					return b, err
 				}
-				x.Streams[iStreams] = tmp
+			}
+			if indef {
+				v = v[1:] // break
 			}
 		default:
 			v, err = cbor.Skip(v)
@@ -2510,17 +4245,53 @@ func (x *MetaSnapshot) DecodeTrusted(b []byte) ([]byte, error) {
 	return rest, nil
 }
 
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *MetaSnapshot) resetCBOR() {
+	x.Streams = x.Streams[:0]
+}
+
 // UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
 func (x *MetaSnapshot) UnmarshalCBOR(b []byte) ([]byte, error) {
 	return x.DecodeSafe(b)
 }
 
+// NewMetaSnapshotFromCBOR decodes b into a newly allocated MetaSnapshot using the
+// Safe path. Bytes after the item fail with cbor.ErrTrailingBytes unless
+// cbor.AllowTrailingBytes is set, in which case they are returned.
+func NewMetaSnapshotFromCBOR(b []byte) (*MetaSnapshot, []byte, error) {
+	x := new(MetaSnapshot)
+	o, err := x.DecodeSafe(b)
+	if err == nil {
+		err = cbor.CheckTrailingBytes(b, o)
+	}
+	if err != nil {
+		return nil, b, err
+	}
+	return x, o, nil
+}
+
 func (x StreamConfigSnapshot) Msgsize() (s int) {
 	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("name") + cbor.StringPrefixSize + len(x.Name) + cbor.StringPrefixSize + len("subjects") + cbor.ArrayHeaderSize + len(x.Subjects)*cbor.StringPrefixSize + cbor.StringPrefixSize + len("metadata") + cbor.MapHeaderSize + len(x.Metadata)*(cbor.StringPrefixSize+cbor.StringPrefixSize)
 	return
 }
 
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
 func (x *StreamConfigSnapshot) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *StreamConfigSnapshot) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *StreamConfigSnapshot) AppendCBOR(b []byte) ([]byte, error) {
 	if x == nil {
 		return cbor.AppendNil(b), nil
 	}
@@ -2541,11 +4312,15 @@ func (x *StreamConfigSnapshot) MarshalCBOR(b []byte) ([]byte, error) {
 	if err != nil {
 		return b, err
 	}
-
-	b = cbor.AppendString(b, "subjects")
-	b = cbor.AppendArrayHeader(b, uint32(len(x.Subjects)))
-	for _, v := range x.Subjects {
-		b = cbor.AppendString(b, v)
+	if x.Subjects == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = cbor.AppendString(b, "subjects")
+		b = cbor.AppendNil(b)
+	} else {
+		b = cbor.AppendString(b, "subjects")
+		b = cbor.AppendArrayHeader(b, uint32(len(x.Subjects)))
+		for _, v := range x.Subjects {
+			b = cbor.AppendString(b, v)
+		}
 	}
 	b = cbor.AppendString(b, "storage")
 	b, err = x.Storage.MarshalCBOR(b)
@@ -2553,47 +4328,104 @@ func (x *StreamConfigSnapshot) MarshalCBOR(b []byte) ([]byte, error) {
 		return b, err
 	}
 	if !(len(x.Metadata) == 0) {
-
-		b = cbor.AppendString(b, "metadata")
-		b = cbor.AppendMapHeader(b, uint32(len(x.Metadata)))
-		for k, v := range x.Metadata {
-			b = cbor.AppendString(b, k)
-			b = cbor.AppendString(b, v)
+		if x.Metadata == nil && cbor.NilContainers == cbor.NilAsNull {
+			b = cbor.AppendString(b, "metadata")
+			b = cbor.AppendNil(b)
+		} else {
+			b = cbor.AppendString(b, "metadata")
+			if cbor.CanonicalMapEncode {
+				b, err = cbor.AppendMapDeterministic(b, x.Metadata, cbor.EncKeyString, cbor.EncValString)
+				if err != nil {
+					return b, err
+				}
+			} else {
+				b = cbor.AppendMapHeader(b, uint32(len(x.Metadata)))
+				for k, v := range x.Metadata {
+					b = cbor.AppendString(b, k)
+					b = cbor.AppendString(b, v)
+				}
+			}
 		}
 	}
 
 	return b, nil
 }
 
-// DecodeSafe decodes using validated, allocating string handling.
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
 func (x *StreamConfigSnapshot) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *StreamConfigSnapshot) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
 	if x == nil {
 		return b, cbor.ErrNotNil
 	}
 	sz, rest, err := cbor.ReadMapHeaderBytes(b)
 	if err != nil {
-		return b, err
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
 	}
+	var seen [1]uint64
 	for i := uint32(0); i < sz; i++ {
-		key, v, err := cbor.ReadStringBytes(rest)
+		key, v, err := in.ReadStringBytes(rest)
 		if err != nil {
-			return b, err
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
 		}
 		switch key {
 		case "name":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
+				}
+			}
 
 			var tmp string
-			tmp, v, err = cbor.ReadStringBytes(v)
+			tmp, v, err = in.ReadStringBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
 			}
 			x.Name = tmp
 		case "subjects":
-
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "subjects", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "subjects", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Subjects = cbor.NullSlice(x.Subjects)
+				break
+			}
 			var sz uint32
-			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "subjects", len(b)-len(v))
 			}
 			if cap(x.Subjects) >= int(sz) {
 				x.Subjects = x.Subjects[:sz]
@@ -2605,24 +4437,50 @@ func (x *StreamConfigSnapshot) DecodeSafe(b []byte) ([]byte, error) {
 			}
 			for iSubjects := uint32(0); iSubjects < sz; iSubjects++ {
 				var tmp string
-				tmp, v, err = cbor.ReadStringBytes(v)
+				tmp, v, err = in.ReadStringBytes(v)
 				if err != nil {
-					return b, err
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, int(iSubjects)), "subjects", len(b)-len(v))
 				}
 				x.Subjects[iSubjects] = tmp
 			}
+			if indef {
+				v = v[1:] // break
+			}
 		case "storage":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 2); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "storage", len(b)-len(v))
+				}
+				v = o
+				break
+			}
 
 			v, err = x.Storage.UnmarshalCBOR(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "storage", len(b)-len(v))
 			}
 		case "metadata":
-
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 3); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "metadata", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "metadata", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Metadata = cbor.NullMap(x.Metadata)
+				break
+			}
 			var sz uint32
 			sz, v, err = cbor.ReadMapHeaderBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "metadata", len(b)-len(v))
 			}
 			if x.Metadata == nil && sz > 0 {
 				x.Metadata = make(map[string]string, sz)
@@ -2631,29 +4489,42 @@ func (x *StreamConfigSnapshot) DecodeSafe(b []byte) ([]byte, error) {
 			}
 			for iMetadata := uint32(0); iMetadata < sz; iMetadata++ {
 				var key string
-				key, v, err = cbor.ReadStringBytes(v)
+				key, v, err = in.ReadStringBytes(v)
 				if err != nil {
-					return b, err
+					return b, cbor.WrapDecodeError(err, "metadata", len(b)-len(v))
+				}
+				if _, dup := x.Metadata[key]; dup {
+					var skip bool
+					if v, skip, err = cbor.SkipDuplicate(v); err != nil {
+						return b, cbor.WrapDecodeError(cbor.WrapDecodeKey(err, key), "metadata", len(b)-len(v))
+					}
+					if skip {
+						continue
+					}
 				}
 				var tmp string
-				tmp, v, err = cbor.ReadStringBytes(v)
+				tmp, v, err = in.ReadStringBytes(v)
 				if err != nil {
-					return b, err
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeKey(err, key), "metadata", len(b)-len(v))
 				}
 				x.Metadata[key] = tmp
 			}
 		default:
 			v, err = cbor.Skip(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
 			}
 		}
 		rest = v
 	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
 	return rest, nil
 }
 
-// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
 func (x *StreamConfigSnapshot) DecodeTrusted(b []byte) ([]byte, error) {
 	if x == nil {
 		return b, cbor.ErrNotNil
@@ -2662,6 +4533,9 @@ func (x *StreamConfigSnapshot) DecodeTrusted(b []byte) ([]byte, error) {
 	if err != nil {
 		return b, err
 	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
 	for i := uint32(0); i < sz; i++ {
 		keyBytes, v, err := cbor.ReadStringZC(rest)
 		if err != nil {
@@ -2670,17 +4544,30 @@ func (x *StreamConfigSnapshot) DecodeTrusted(b []byte) ([]byte, error) {
 		key := cbor.UnsafeString(keyBytes)
 		switch key {
 		case "name":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
-			var tmpBytes []byte
-			tmpBytes, v, err = cbor.ReadStringZC(v)
+			x.Name, v, err = cbor.ReadTrustedStringBytes(v)
 			if err != nil {
 				return b, err
 			}
-			x.Name = cbor.UnsafeString(tmpBytes)
 		case "subjects":
-
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Subjects = cbor.NullSlice(x.Subjects)
+				break
+			}
 			var sz uint32
-			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
 			if err != nil {
 				return b, err
 			}
@@ -2694,12 +4581,15 @@ func (x *StreamConfigSnapshot) DecodeTrusted(b []byte) ([]byte, error) {
 			}
 			for iSubjects := uint32(0); iSubjects < sz; iSubjects++ {
 				var tmp string
-				tmp, v, err = cbor.ReadStringBytes(v)
+				tmp, v, err = cbor.ReadTrustedStringBytes(v)
 				if err != nil {
 					return b, err
 				}
 				x.Subjects[iSubjects] = tmp
 			}
+			if indef {
+				v = v[1:] // break
+			}
 		case "storage":
 
 			v, err = x.Storage.UnmarshalCBOR(v)
@@ -2707,7 +4597,16 @@ func (x *StreamConfigSnapshot) DecodeTrusted(b []byte) ([]byte, error) {
 				return b, err
 			}
 		case "metadata":
-
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Metadata = cbor.NullMap(x.Metadata)
+				break
+			}
 			var sz uint32
 			sz, v, err = cbor.ReadMapHeaderBytes(v)
 			if err != nil {
@@ -2720,12 +4619,12 @@ func (x *StreamConfigSnapshot) DecodeTrusted(b []byte) ([]byte, error) {
 			}
 			for iMetadata := uint32(0); iMetadata < sz; iMetadata++ {
 				var key string
-				key, v, err = cbor.ReadStringBytes(v)
+				key, v, err = cbor.ReadTrustedStringBytes(v)
 				if err != nil {
 					return b, err
 				}
 				var tmp string
-				tmp, v, err = cbor.ReadStringBytes(v)
+				tmp, v, err = cbor.ReadTrustedStringBytes(v)
 				if err != nil {
 					return b, err
 				}
@@ -2742,17 +4641,57 @@ func (x *StreamConfigSnapshot) DecodeTrusted(b []byte) ([]byte, error) {
 	return rest, nil
 }
 
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *StreamConfigSnapshot) resetCBOR() {
+	var zero StreamConfigSnapshot
+	x.Name = zero.Name
+	x.Subjects = x.Subjects[:0]
+	x.Storage = zero.Storage
+	clear(x.Metadata)
+}
+
 // UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
 func (x *StreamConfigSnapshot) UnmarshalCBOR(b []byte) ([]byte, error) {
 	return x.DecodeSafe(b)
 }
 
+// NewStreamConfigSnapshotFromCBOR decodes b into a newly allocated StreamConfigSnapshot using the
+// Safe path. Bytes after the item fail with cbor.ErrTrailingBytes unless
+// cbor.AllowTrailingBytes is set, in which case they are returned.
+func NewStreamConfigSnapshotFromCBOR(b []byte) (*StreamConfigSnapshot, []byte, error) {
+	x := new(StreamConfigSnapshot)
+	o, err := x.DecodeSafe(b)
+	if err == nil {
+		err = cbor.CheckTrailingBytes(b, o)
+	}
+	if err != nil {
+		return nil, b, err
+	}
+	return x, o, nil
+}
+
 func (x ConsumerConfigSnapshot) Msgsize() (s int) {
 	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("durable") + cbor.StringPrefixSize + len(x.Durable) + cbor.StringPrefixSize + len("mem_storage") + cbor.BoolSize + cbor.StringPrefixSize + len("metadata") + cbor.MapHeaderSize + len(x.Metadata)*(cbor.StringPrefixSize+cbor.StringPrefixSize)
 	return
 }
 
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
 func (x *ConsumerConfigSnapshot) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *ConsumerConfigSnapshot) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *ConsumerConfigSnapshot) AppendCBOR(b []byte) ([]byte, error) {
 	if x == nil {
 		return cbor.AppendNil(b), nil
 	}
@@ -2778,55 +4717,123 @@ func (x *ConsumerConfigSnapshot) MarshalCBOR(b []byte) ([]byte, error) {
 		return b, err
 	}
 	if !(len(x.Metadata) == 0) {
-
-		b = cbor.AppendString(b, "metadata")
-		b = cbor.AppendMapHeader(b, uint32(len(x.Metadata)))
-		for k, v := range x.Metadata {
-			b = cbor.AppendString(b, k)
-			b = cbor.AppendString(b, v)
+		if x.Metadata == nil && cbor.NilContainers == cbor.NilAsNull {
+			b = cbor.AppendString(b, "metadata")
+			b = cbor.AppendNil(b)
+		} else {
+			b = cbor.AppendString(b, "metadata")
+			if cbor.CanonicalMapEncode {
+				b, err = cbor.AppendMapDeterministic(b, x.Metadata, cbor.EncKeyString, cbor.EncValString)
+				if err != nil {
+					return b, err
+				}
+			} else {
+				b = cbor.AppendMapHeader(b, uint32(len(x.Metadata)))
+				for k, v := range x.Metadata {
+					b = cbor.AppendString(b, k)
+					b = cbor.AppendString(b, v)
+				}
+			}
 		}
 	}
 
 	return b, nil
 }
 
-// DecodeSafe decodes using validated, allocating string handling.
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
 func (x *ConsumerConfigSnapshot) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *ConsumerConfigSnapshot) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
 	if x == nil {
 		return b, cbor.ErrNotNil
 	}
 	sz, rest, err := cbor.ReadMapHeaderBytes(b)
 	if err != nil {
-		return b, err
+		return b, cbor.WrapDecodeError(err, "", 0)
 	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
 	for i := uint32(0); i < sz; i++ {
-		key, v, err := cbor.ReadStringBytes(rest)
+		key, v, err := in.ReadStringBytes(rest)
 		if err != nil {
-			return b, err
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
 		}
 		switch key {
 		case "durable":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "durable", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "durable", len(b)-len(v))
+				}
+			}
 
 			var tmp string
-			tmp, v, err = cbor.ReadStringBytes(v)
+			tmp, v, err = in.ReadStringBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "durable", len(b)-len(v))
 			}
 			x.Durable = tmp
 		case "mem_storage":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "mem_storage", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "mem_storage", len(b)-len(v))
+				}
+			}
 
 			var tmp bool
 			tmp, v, err = cbor.ReadBoolBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "mem_storage", len(b)-len(v))
 			}
 			x.MemoryStorage = tmp
 		case "metadata":
-
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 2); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "metadata", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "metadata", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Metadata = cbor.NullMap(x.Metadata)
+				break
+			}
 			var sz uint32
 			sz, v, err = cbor.ReadMapHeaderBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "metadata", len(b)-len(v))
 			}
 			if x.Metadata == nil && sz > 0 {
 				x.Metadata = make(map[string]string, sz)
@@ -2835,29 +4842,42 @@ func (x *ConsumerConfigSnapshot) DecodeSafe(b []byte) ([]byte, error) {
 			}
 			for iMetadata := uint32(0); iMetadata < sz; iMetadata++ {
 				var key string
-				key, v, err = cbor.ReadStringBytes(v)
+				key, v, err = in.ReadStringBytes(v)
 				if err != nil {
-					return b, err
+					return b, cbor.WrapDecodeError(err, "metadata", len(b)-len(v))
+				}
+				if _, dup := x.Metadata[key]; dup {
+					var skip bool
+					if v, skip, err = cbor.SkipDuplicate(v); err != nil {
+						return b, cbor.WrapDecodeError(cbor.WrapDecodeKey(err, key), "metadata", len(b)-len(v))
+					}
+					if skip {
+						continue
+					}
 				}
 				var tmp string
-				tmp, v, err = cbor.ReadStringBytes(v)
+				tmp, v, err = in.ReadStringBytes(v)
 				if err != nil {
-					return b, err
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeKey(err, key), "metadata", len(b)-len(v))
 				}
 				x.Metadata[key] = tmp
 			}
 		default:
 			v, err = cbor.Skip(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
 			}
 		}
 		rest = v
 	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
 	return rest, nil
 }
 
-// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
 func (x *ConsumerConfigSnapshot) DecodeTrusted(b []byte) ([]byte, error) {
 	if x == nil {
 		return b, cbor.ErrNotNil
@@ -2866,6 +4886,9 @@ func (x *ConsumerConfigSnapshot) DecodeTrusted(b []byte) ([]byte, error) {
 	if err != nil {
 		return b, err
 	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
 	for i := uint32(0); i < sz; i++ {
 		keyBytes, v, err := cbor.ReadStringZC(rest)
 		if err != nil {
@@ -2874,14 +4897,22 @@ func (x *ConsumerConfigSnapshot) DecodeTrusted(b []byte) ([]byte, error) {
 		key := cbor.UnsafeString(keyBytes)
 		switch key {
 		case "durable":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
-			var tmpBytes []byte
-			tmpBytes, v, err = cbor.ReadStringZC(v)
+			x.Durable, v, err = cbor.ReadTrustedStringBytes(v)
 			if err != nil {
 				return b, err
 			}
-			x.Durable = cbor.UnsafeString(tmpBytes)
 		case "mem_storage":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
 			var tmp bool
 			tmp, v, err = cbor.ReadBoolBytes(v)
@@ -2890,7 +4921,16 @@ func (x *ConsumerConfigSnapshot) DecodeTrusted(b []byte) ([]byte, error) {
 			}
 			x.MemoryStorage = tmp
 		case "metadata":
-
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Metadata = cbor.NullMap(x.Metadata)
+				break
+			}
 			var sz uint32
 			sz, v, err = cbor.ReadMapHeaderBytes(v)
 			if err != nil {
@@ -2903,12 +4943,12 @@ func (x *ConsumerConfigSnapshot) DecodeTrusted(b []byte) ([]byte, error) {
 			}
 			for iMetadata := uint32(0); iMetadata < sz; iMetadata++ {
 				var key string
-				key, v, err = cbor.ReadStringBytes(v)
+				key, v, err = cbor.ReadTrustedStringBytes(v)
 				if err != nil {
 					return b, err
 				}
 				var tmp string
-				tmp, v, err = cbor.ReadStringBytes(v)
+				tmp, v, err = cbor.ReadTrustedStringBytes(v)
 				if err != nil {
 					return b, err
 				}
@@ -2925,7 +4965,31 @@ func (x *ConsumerConfigSnapshot) DecodeTrusted(b []byte) ([]byte, error) {
 	return rest, nil
 }
 
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *ConsumerConfigSnapshot) resetCBOR() {
+	var zero ConsumerConfigSnapshot
+	x.Durable = zero.Durable
+	x.MemoryStorage = zero.MemoryStorage
+	clear(x.Metadata)
+}
+
 // UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
 func (x *ConsumerConfigSnapshot) UnmarshalCBOR(b []byte) ([]byte, error) {
 	return x.DecodeSafe(b)
 }
+
+// NewConsumerConfigSnapshotFromCBOR decodes b into a newly allocated ConsumerConfigSnapshot using the
+// Safe path. Bytes after the item fail with cbor.ErrTrailingBytes unless
+// cbor.AllowTrailingBytes is set, in which case they are returned.
+func NewConsumerConfigSnapshotFromCBOR(b []byte) (*ConsumerConfigSnapshot, []byte, error) {
+	x := new(ConsumerConfigSnapshot)
+	o, err := x.DecodeSafe(b)
+	if err == nil {
+		err = cbor.CheckTrailingBytes(b, o)
+	}
+	if err != nil {
+		return nil, b, err
+	}
+	return x, o, nil
+}
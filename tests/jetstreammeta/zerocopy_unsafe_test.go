@@ -0,0 +1,28 @@
+//go:build !purego && !tinygo
+
+package jetstreammeta
+
+import (
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+// Without unsafe (the purego tag or TinyGo) DecodeTrusted copies strings.
+func TestConsumerAssignmentDecodeTrustedClientNoAllocs(t *testing.T) {
+	cbor.ZeroCopyStrings = true
+	defer func() { cbor.ZeroCopyStrings = false }()
+	b := encodedConsumerAssignment(t)
+	var out WriteableConsumerAssignment
+	if _, err := out.DecodeTrusted(b); err != nil {
+		t.Fatalf("DecodeTrusted: %v", err)
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		if _, err := out.DecodeTrusted(b); err != nil {
+			t.Fatalf("DecodeTrusted: %v", err)
+		}
+	})
+	if allocs != 0 {
+		t.Fatalf("DecodeTrusted with a pre-allocated Client allocated %v times, want 0", allocs)
+	}
+}
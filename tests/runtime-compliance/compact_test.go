@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+func TestCompact(t *testing.T) {
+	cases := []struct{ in, want string }{
+		{"00", "00"},
+		{"1b0000000000000017", "17"},                             // 23 in eight bytes
+		{"3900ff", "38ff"},                                       // -256
+		{"5f42010243030405ff", "450102030405"},                   // indefinite byte string
+		{"7f61616162ff", "626162"},                               // indefinite text string
+		{"9f0102ff", "820102"},                                   // indefinite array
+		{"bf616201616102ff", "a2616102616201"},                   // indefinite, unsorted map
+		{"a2190002f401f5", "a201f502f4"},                         // {2: false, 1: true}
+		{"fb3ff8000000000000", "f93e00"},                         // 1.5
+		{"fb8000000000000000", "f98000"},                         // -0.0 keeps its sign
+		{"fb7ff8000000000000", "f97e00"},                         // NaN
+		{"fa47c35000", "fa47c35000"},                             // 100000.0 needs float32
+		{"d900011b000000000000000a", "c10a"},                     // 1(10) with long heads
+		{"f810", "f0"},                                           // simple(16)
+		{"9fbf616201616102ff5f4100ffff", "82a26161026162014100"}, // nesting
+	}
+	for _, tc := range cases {
+		in, _ := hex.DecodeString(tc.in)
+		out, err := cbor.Compact(in)
+		if err != nil {
+			t.Errorf("%s: %v", tc.in, err)
+			continue
+		}
+		if got := hex.EncodeToString(out); got != tc.want {
+			t.Errorf("%s: Compact = %s, want %s", tc.in, got, tc.want)
+		}
+		if err := cbor.ValidateCanonical(out); err != nil {
+			t.Errorf("%s: compacted item is not canonical: %v", tc.in, err)
+		}
+	}
+}
+
+func TestCompactErrors(t *testing.T) {
+	cases := []struct {
+		in   string
+		want error
+	}{
+		{"a2190001f40101", cbor.ErrDuplicateMapKey}, // keys 1 and 1 once shortened
+		{"0000", cbor.ErrTrailingBytes},
+		{"8201", cbor.ErrShortBytes},
+	}
+	for _, tc := range cases {
+		in, _ := hex.DecodeString(tc.in)
+		if _, err := cbor.Compact(in); !errors.Is(err, tc.want) {
+			t.Errorf("%s: error = %v, want %v", tc.in, err, tc.want)
+		}
+	}
+}
+
+func TestCompactRFC7049Order(t *testing.T) {
+	cbor.MapKeyOrder = cbor.RFC7049
+	defer func() { cbor.MapKeyOrder = cbor.RFC8949 }()
+
+	// {1000: 2, "a": 1}: length-first order puts "a" (0x6161) first.
+	in, _ := hex.DecodeString("a21903e802616101")
+	out, err := cbor.Compact(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := hex.EncodeToString(out); got != "a26161011903e802" {
+		t.Fatalf("Compact = %s, want a26161011903e802", got)
+	}
+}
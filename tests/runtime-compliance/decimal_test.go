@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"encoding/hex"
+	"errors"
+	"math"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+func TestDecimal(t *testing.T) {
+	cases := []struct {
+		hex  string
+		d    cbor.Decimal
+		want string
+	}{
+		{"c48221196ab3", cbor.Decimal{Scale: 2, Value: 27315}, "273.15"}, // RFC 8949 example
+		{"c482213907ce", cbor.Decimal{Scale: 2, Value: -1999}, "-19.99"},
+		{"c4822424", cbor.Decimal{Scale: 5, Value: -5}, "-0.00005"},
+		{"c482210f", cbor.Decimal{Scale: 2, Value: 15}, "0.15"},
+		{"c482020c", cbor.Decimal{Scale: -2, Value: 12}, "1200"},
+		{"c4820000", cbor.Decimal{}, "0"},
+		{"c482223b7fffffffffffffff", cbor.Decimal{Scale: 3, Value: math.MinInt64}, "-9223372036854775.808"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.want, func(t *testing.T) {
+			if got := hex.EncodeToString(cbor.AppendDecimal(nil, tc.d)); got != tc.hex {
+				t.Fatalf("AppendDecimal = %s, want %s", got, tc.hex)
+			}
+			in, _ := hex.DecodeString(tc.hex)
+			d, rest, err := cbor.ReadDecimalBytes(in)
+			if err != nil {
+				t.Fatalf("ReadDecimalBytes: %v", err)
+			}
+			if d != tc.d || len(rest) != 0 {
+				t.Fatalf("ReadDecimalBytes = %+v, %d bytes left; want %+v", d, len(rest), tc.d)
+			}
+			if got := d.String(); got != tc.want {
+				t.Fatalf("String = %q, want %q", got, tc.want)
+			}
+			if n := d.Msgsize(); n < len(in) {
+				t.Fatalf("Msgsize = %d, below the encoded %d bytes", n, len(in))
+			}
+		})
+	}
+}
+
+func TestDecimalIndefinite(t *testing.T) {
+	in, _ := hex.DecodeString("c49f213907ceff01")
+	d, rest, err := cbor.ReadDecimalBytes(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d != (cbor.Decimal{Scale: 2, Value: -1999}) || len(rest) != 1 {
+		t.Fatalf("got %+v with %d bytes left", d, len(rest))
+	}
+}
+
+func TestDecimalErrors(t *testing.T) {
+	var overflow cbor.IntOverflow
+	var tagErr cbor.UnexpectedTagError
+	var arrErr cbor.ArrayError
+	var bigErr cbor.BignumOverflow
+	cases := []struct {
+		name   string
+		hex    string
+		target any
+	}{
+		{"bigfloat", "c5822103", &tagErr},
+		{"three_items", "c483210301", &arrErr},
+		{"bignum_mantissa", "c48221c249010000000000000000", &bigErr},
+		{"mantissa_beyond_int64", "c482211b8000000000000000", &overflow},
+		{"exponent_min_int64", "c4823b7fffffffffffffff01", &overflow},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			in, _ := hex.DecodeString(tc.hex)
+			_, rest, err := cbor.ReadDecimalBytes(in)
+			if !errors.As(err, tc.target) {
+				t.Fatalf("error = %v (%T), want %T", err, err, tc.target)
+			}
+			if len(rest) != len(in) {
+				t.Fatalf("%d bytes consumed on error", len(in)-len(rest))
+			}
+		})
+	}
+}
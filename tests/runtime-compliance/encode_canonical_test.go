@@ -97,3 +97,91 @@ func TestCanonicalFloatEncoding(t *testing.T) {
 	}
 }
 
+
+// TestCanonicalMapKeyOrder checks both selectable key orders on a map
+// whose keys mix major types: {"a": 1, 1000: 2, -1: 3}.
+func TestCanonicalMapKeyOrder(t *testing.T) {
+	defer func(o cbor.CanonicalOrder) { cbor.MapKeyOrder = o }(cbor.MapKeyOrder)
+
+	pairs := []cbor.RawPair{
+		{Key: cbor.AppendString(nil, "a"), Value: cbor.AppendInt64(nil, 1)},
+		{Key: cbor.AppendInt64(nil, 1000), Value: cbor.AppendInt64(nil, 2)},
+		{Key: cbor.AppendInt64(nil, -1), Value: cbor.AppendInt64(nil, 3)},
+	}
+	cases := []struct {
+		order   cbor.CanonicalOrder
+		wantHex string
+	}{
+		// Bytewise: 19 03e8 < 20 < 61 61.
+		{cbor.RFC8949, "a31903e8022003616101"},
+		// Length first: 20 (1 byte), 61 61 (2 bytes), 19 03e8 (3 bytes).
+		{cbor.RFC7049, "a320036161011903e802"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.order.String(), func(t *testing.T) {
+			cbor.MapKeyOrder = tc.order
+			got := hex.EncodeToString(cbor.AppendRawMapDeterministic(nil, pairs))
+			if got != tc.wantHex {
+				t.Fatalf("AppendRawMapDeterministic: got %s want %s", got, tc.wantHex)
+			}
+
+			m := map[any]int64{"a": 1, int64(1000): 2, int64(-1): 3}
+			encKey := func(dst []byte, k any) []byte {
+				if s, ok := k.(string); ok {
+					return cbor.AppendString(dst, s)
+				}
+				return cbor.AppendInt64(dst, k.(int64))
+			}
+			b, err := cbor.AppendMapDeterministic(nil, m, encKey, cbor.EncValInt64)
+			if err != nil {
+				t.Fatalf("AppendMapDeterministic: %v", err)
+			}
+			if got := hex.EncodeToString(b); got != tc.wantHex {
+				t.Fatalf("AppendMapDeterministic: got %s want %s", got, tc.wantHex)
+			}
+
+			sorted := append([]cbor.RawPair(nil), pairs...)
+			cbor.SortMapKeys(sorted)
+			out := cbor.AppendMapHeader(nil, uint32(len(sorted)))
+			for _, p := range sorted {
+				out = append(out, p.Key...)
+				out = append(out, p.Value...)
+			}
+			if got := hex.EncodeToString(out); got != tc.wantHex {
+				t.Fatalf("SortMapKeys: got %s want %s", got, tc.wantHex)
+			}
+		})
+	}
+}
+
+// TestAppendPrimitivesShortestHead checks that the public Append*
+// primitives use the shortest head at every length boundary.
+func TestAppendPrimitivesShortestHead(t *testing.T) {
+	cases := []struct {
+		name    string
+		got     []byte
+		wantHex string
+	}{
+		{"uint_23", cbor.AppendUint(nil, 23), "17"},
+		{"uint_24", cbor.AppendUint(nil, 24), "1818"},
+		{"uint_65535", cbor.AppendUint(nil, 65535), "19ffff"},
+		{"uint_65536", cbor.AppendUint(nil, 65536), "1a00010000"},
+		{"uint64_2^32", cbor.AppendUint64(nil, 1<<32), "1b0000000100000000"},
+		{"int_-24", cbor.AppendInt(nil, -24), "37"},
+		{"int_-25", cbor.AppendInt(nil, -25), "3818"},
+		{"tag_1", cbor.AppendTag(nil, 1), "c1"},
+		{"tag_24", cbor.AppendTag(nil, 24), "d818"},
+		{"array_24", cbor.AppendArrayHeader(nil, 24), "9818"},
+		{"map_0", cbor.AppendMapHeader(nil, 0), "a0"},
+		{"text", cbor.AppendTextString(nil, "IETF"), "6449455446"},
+		{"bytes", cbor.AppendByteString(nil, []byte{1, 2, 3, 4}), "4401020304"},
+		{"null", cbor.AppendNull(nil), "f6"},
+		{"bool", cbor.AppendBool(nil, true), "f5"},
+		{"float64", cbor.AppendFloat64(nil, 1.1), "fb3ff199999999999a"},
+	}
+	for _, tc := range cases {
+		if got := hex.EncodeToString(tc.got); got != tc.wantHex {
+			t.Errorf("%s: got %s want %s", tc.name, got, tc.wantHex)
+		}
+	}
+}
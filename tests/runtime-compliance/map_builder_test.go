@@ -0,0 +1,81 @@
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+func TestMapBuilder(t *testing.T) {
+	var mb cbor.MapBuilder
+	add := func(k string, v int64) {
+		t.Helper()
+		if err := mb.AddString(k, func(b []byte) ([]byte, error) { return cbor.AppendInt64(b, v), nil }); err != nil {
+			t.Fatalf("AddString(%q) error: %v", k, err)
+		}
+	}
+	add("bb", 2)
+	add("a", 1)
+	if err := mb.AddInt(10, func(b []byte) ([]byte, error) { return cbor.AppendBool(b, true), nil }); err != nil {
+		t.Fatalf("AddInt error: %v", err)
+	}
+	boom := errors.New("boom")
+	if err := mb.AddString("skipped", func(b []byte) ([]byte, error) { return cbor.AppendString(b, "partial"), boom }); err != boom {
+		t.Fatalf("failing value: error = %v, want boom", err)
+	}
+	if mb.Len() != 3 {
+		t.Fatalf("Len = %d, want 3", mb.Len())
+	}
+
+	want := cbor.AppendMapHeader(nil, 3)
+	want = cbor.AppendInt64(cbor.AppendString(want, "bb"), 2)
+	want = cbor.AppendInt64(cbor.AppendString(want, "a"), 1)
+	want = cbor.AppendBool(cbor.AppendInt64(want, 10), true)
+	if got := mb.Append(nil); !bytes.Equal(got, want) {
+		t.Fatalf("Append = %x, want %x", got, want)
+	}
+
+	// Sorted bytewise: 0x0a, 0x6161, 0x626262.
+	want = cbor.AppendMapHeader(nil, 3)
+	want = cbor.AppendBool(cbor.AppendInt64(want, 10), true)
+	want = cbor.AppendInt64(cbor.AppendString(want, "a"), 1)
+	want = cbor.AppendInt64(cbor.AppendString(want, "bb"), 2)
+	got, err := mb.AppendSorted(nil)
+	if err != nil || !bytes.Equal(got, want) {
+		t.Fatalf("AppendSorted = %x, %v; want %x", got, err, want)
+	}
+	if err := cbor.ValidateCanonical(got); err != nil {
+		t.Fatalf("AppendSorted output not canonical: %v", err)
+	}
+
+	add("a", 3)
+	if _, err := mb.AppendSorted(nil); !errors.Is(err, cbor.ErrDuplicateMapKey) {
+		t.Fatalf("duplicate key: error = %v, want ErrDuplicateMapKey", err)
+	}
+
+	mb.Reset()
+	if got := mb.Append(nil); mb.Len() != 0 || !bytes.Equal(got, []byte{0xa0}) {
+		t.Fatalf("after Reset: Len = %d, Append = %x", mb.Len(), got)
+	}
+}
+
+func TestMapBuilderReuseAllocs(t *testing.T) {
+	var mb cbor.MapBuilder
+	out := make([]byte, 0, 256)
+	build := func() {
+		mb.Reset()
+		for i := int64(0); i < 8; i++ {
+			mb.AddInt(8-i, func(b []byte) ([]byte, error) { return cbor.AppendInt64(b, i), nil })
+		}
+		var err error
+		if out, err = mb.AppendSorted(out[:0]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	build()
+	if n := testing.AllocsPerRun(100, build); n != 0 {
+		t.Fatalf("reused MapBuilder allocated %v times per map, want 0", n)
+	}
+}
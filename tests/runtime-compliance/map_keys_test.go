@@ -0,0 +1,108 @@
+package tests
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+// boolFloatKeys encodes {true: "t", false: "f", 1.5: "half", 0.1: "tenth", null: "nil"}.
+func boolFloatKeys() []byte {
+	b := cbor.AppendMapHeader(nil, 5)
+	b = cbor.AppendBool(b, true)
+	b = cbor.AppendString(b, "t")
+	b = cbor.AppendBool(b, false)
+	b = cbor.AppendString(b, "f")
+	b = cbor.AppendFloat32(b, 1.5)
+	b = cbor.AppendString(b, "half")
+	b = cbor.AppendFloat64(b, 0.1)
+	b = cbor.AppendString(b, "tenth")
+	b = cbor.AppendNil(b)
+	return cbor.AppendString(b, "nil")
+}
+
+func TestReadInterfaceNonTextKeys(t *testing.T) {
+	want := map[any]any{true: "t", false: "f", float32(1.5): "half", 0.1: "tenth", nil: "nil"}
+
+	v, rest, err := cbor.ReadInterfaceBytes(boolFloatKeys())
+	if err != nil || len(rest) != 0 {
+		t.Fatalf("ReadInterfaceBytes error: %v (%d bytes left)", err, len(rest))
+	}
+	if !reflect.DeepEqual(v, want) {
+		t.Fatalf("decoded %#v, want %#v", v, want)
+	}
+
+	// Text keys decoded before the first other key are carried over.
+	b := cbor.AppendMapHeader(nil, 2)
+	b = cbor.AppendString(b, "a")
+	b = cbor.AppendInt(b, 1)
+	b = cbor.AppendBool(b, true)
+	b = cbor.AppendInt(b, 2)
+	v, _, err = cbor.ReadInterfaceBytes(b)
+	if err != nil || !reflect.DeepEqual(v, map[any]any{"a": uint64(1), true: uint64(2)}) {
+		t.Fatalf("mixed keys decoded %#v, %v", v, err)
+	}
+
+	// Maps with only text keys keep their map[string]any form.
+	b = cbor.AppendMapHeader(nil, 1)
+	b = cbor.AppendString(b, "a")
+	b = cbor.AppendInt(b, 1)
+	if v, _, err = cbor.ReadInterfaceBytes(b); err != nil {
+		t.Fatalf("ReadInterfaceBytes error: %v", err)
+	}
+	if _, ok := v.(map[string]any); !ok {
+		t.Fatalf("text-keyed map decoded as %T", v)
+	}
+}
+
+func TestUnmarshalNonTextKeys(t *testing.T) {
+	var generic any
+	if err := cbor.Unmarshal(boolFloatKeys(), &generic); err != nil {
+		t.Fatalf("Unmarshal into any error: %v", err)
+	}
+	if m, ok := generic.(map[any]any); !ok || m[true] != "t" || m[float32(1.5)] != "half" {
+		t.Fatalf("Unmarshal into any = %#v", generic)
+	}
+
+	var byBool map[bool]string
+	b := cbor.AppendMapHeader(nil, 2)
+	b = cbor.AppendBool(b, true)
+	b = cbor.AppendString(b, "yes")
+	b = cbor.AppendBool(b, false)
+	b = cbor.AppendString(b, "no")
+	if err := cbor.Unmarshal(b, &byBool); err != nil || byBool[true] != "yes" || byBool[false] != "no" {
+		t.Fatalf("Unmarshal into map[bool]string = %v, %v", byBool, err)
+	}
+
+	var byFloat map[float64]int
+	b = cbor.AppendMapHeader(nil, 2)
+	b = cbor.AppendFloat32(b, 2.5)
+	b = cbor.AppendInt(b, 1)
+	b = cbor.AppendFloat64(b, -0.25)
+	b = cbor.AppendInt(b, 2)
+	if err := cbor.Unmarshal(b, &byFloat); err != nil || byFloat[2.5] != 1 || byFloat[-0.25] != 2 {
+		t.Fatalf("Unmarshal into map[float64]int = %v, %v", byFloat, err)
+	}
+
+	// The keys round-trip through Marshal.
+	enc, err := cbor.Marshal(byFloat)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	var again map[float64]int
+	if err := cbor.Unmarshal(enc, &again); err != nil || !reflect.DeepEqual(again, byFloat) {
+		t.Fatalf("round trip = %v, %v", again, err)
+	}
+}
+
+func TestReadInterfaceUnhashableKey(t *testing.T) {
+	b := cbor.AppendMapHeader(nil, 1)
+	b = cbor.AppendArrayHeader(b, 0)
+	b = cbor.AppendInt(b, 1)
+	var unsupported *cbor.ErrUnsupportedType
+	if _, _, err := cbor.ReadInterfaceBytes(b); !errors.As(err, &unsupported) {
+		t.Fatalf("array key error = %v, want ErrUnsupportedType", err)
+	}
+}
@@ -0,0 +1,63 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+func TestMaxElements(t *testing.T) {
+	defer func(n int) { cbor.MaxElements = n }(cbor.MaxElements)
+	cbor.MaxElements = 3
+
+	mustHex := func(s string) []byte {
+		b, err := hex.DecodeString(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+	tooLarge := func(name string, err error) {
+		t.Helper()
+		if !errors.Is(err, cbor.ErrContainerTooLarge) {
+			t.Errorf("%s: error = %v, want ErrContainerTooLarge", name, err)
+		}
+	}
+
+	// Three elements are fine, four are not.
+	if _, _, err := cbor.ReadArrayHeaderBytes(mustHex("83010203")); err != nil {
+		t.Fatalf("3-element array: %v", err)
+	}
+	_, _, err := cbor.ReadArrayHeaderBytes(mustHex("8401020304"))
+	tooLarge("ReadArrayHeaderBytes", err)
+	_, _, err = cbor.ReadMapHeaderBytes(mustHex("a40101020203030404"))
+	tooLarge("ReadMapHeaderBytes", err)
+
+	// A definite header claiming 2^32-1 elements fails before any are read.
+	_, _, err = cbor.ReadArrayHeaderBytes(mustHex("9affffffff"))
+	tooLarge("huge header", err)
+
+	// Indefinite-length containers are counted as they are read.
+	indefArray := mustHex("9f01020304ff")
+	_, _, _, err = cbor.ReadArraySizeBytes(indefArray)
+	tooLarge("ReadArraySizeBytes", err)
+
+	var anyVal any
+	tooLarge("Unmarshal any array", cbor.Unmarshal(indefArray, &anyVal))
+	tooLarge("Unmarshal any map", cbor.Unmarshal(mustHex("bf0101020203030404ff"), &anyVal))
+
+	var ints []int
+	tooLarge("Unmarshal []int", cbor.Unmarshal(indefArray, &ints))
+	var m map[int]int
+	tooLarge("Unmarshal map[int]int", cbor.Unmarshal(mustHex("bf0101020203030404ff"), &m))
+
+	var raw cbor.Raw
+	tooLarge("DecodeReader", cbor.DecodeReader(bytes.NewReader(indefArray), &raw))
+
+	if err := cbor.Unmarshal(mustHex("9f010203ff"), &ints); err != nil || len(ints) != 3 {
+		t.Fatalf("3-element indefinite array: %v, %v", ints, err)
+	}
+}
@@ -0,0 +1,49 @@
+package tests
+
+import (
+	"encoding/hex"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+type nilFields struct {
+	List []int               `cbor:"l"`
+	Blob []byte              `cbor:"b"`
+	Map  map[string]int      `cbor:"m"`
+	Set  map[string]struct{} `cbor:"s,set"`
+}
+
+func TestReflectNilContainers(t *testing.T) {
+	defer func() { cbor.NilContainers = cbor.NilAsEmpty }()
+	tests := []struct {
+		policy cbor.NilPolicy
+		want   string
+	}{
+		{cbor.NilAsEmpty, "a4616c80616240616da0617380"},
+		{cbor.NilAsNull, "a4616cf66162f6616df66173f6"},
+	}
+	for _, tc := range tests {
+		cbor.NilContainers = tc.policy
+		b, err := cbor.Marshal(nilFields{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(b); got != tc.want {
+			t.Errorf("%v: Marshal = %s, want %s", tc.policy, got, tc.want)
+		}
+
+		null, _ := hex.DecodeString(tests[1].want)
+		out := nilFields{List: []int{1}, Blob: []byte{1}, Map: map[string]int{"a": 1}, Set: map[string]struct{}{"a": {}}}
+		if err := cbor.Unmarshal(null, &out); err != nil {
+			t.Fatal(err)
+		}
+		isNull := tc.policy == cbor.NilAsNull
+		if (out.List == nil) != isNull || (out.Blob == nil) != isNull || (out.Map == nil) != isNull || (out.Set == nil) != isNull {
+			t.Errorf("%v: Unmarshal(null) = %#v", tc.policy, out)
+		}
+		if len(out.List)+len(out.Blob)+len(out.Map)+len(out.Set) != 0 {
+			t.Errorf("%v: Unmarshal(null) kept elements: %#v", tc.policy, out)
+		}
+	}
+}
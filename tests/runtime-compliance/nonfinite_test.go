@@ -0,0 +1,107 @@
+package tests
+
+import (
+	"encoding/hex"
+	"errors"
+	"math"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+var (
+	quietNaN64     = math.Float64frombits(0x7ff8000000000000)
+	signalingNaN64 = math.Float64frombits(0x7ff0000000000001)
+	payloadNaN64   = math.Float64frombits(0xfff8dead0000beef)
+	quietNaN32     = math.Float32frombits(0x7fc00000)
+	signalingNaN32 = math.Float32frombits(0x7f800001)
+)
+
+func TestNonFiniteFloatEncoding(t *testing.T) {
+	cases := []struct {
+		name    string
+		build   func() []byte
+		wantHex string
+	}{
+		{name: "f64_+Inf", build: func() []byte { return cbor.AppendFloat64(nil, math.Inf(1)) }, wantHex: "f97c00"},
+		{name: "f64_-Inf", build: func() []byte { return cbor.AppendFloat64(nil, math.Inf(-1)) }, wantHex: "f9fc00"},
+		{name: "f64_quiet_NaN", build: func() []byte { return cbor.AppendFloat64(nil, quietNaN64) }, wantHex: "f97e00"},
+		{name: "f64_signaling_NaN", build: func() []byte { return cbor.AppendFloat64(nil, signalingNaN64) }, wantHex: "f97e00"},
+		{name: "f64_payload_NaN", build: func() []byte { return cbor.AppendFloat64(nil, payloadNaN64) }, wantHex: "f97e00"},
+		{name: "f32_+Inf", build: func() []byte { return cbor.AppendFloat32(nil, float32(math.Inf(1))) }, wantHex: "f97c00"},
+		{name: "f32_-Inf", build: func() []byte { return cbor.AppendFloat32(nil, float32(math.Inf(-1))) }, wantHex: "f9fc00"},
+		{name: "f32_quiet_NaN", build: func() []byte { return cbor.AppendFloat32(nil, quietNaN32) }, wantHex: "f97e00"},
+		{name: "f32_signaling_NaN", build: func() []byte { return cbor.AppendFloat32(nil, signalingNaN32) }, wantHex: "f97e00"},
+		{name: "canonical_-Inf", build: func() []byte { return cbor.AppendFloatCanonical(nil, math.Inf(-1)) }, wantHex: "f9fc00"},
+		{name: "canonical_NaN", build: func() []byte { return cbor.AppendFloatCanonical(nil, payloadNaN64) }, wantHex: "f97e00"},
+		{name: "float_NaN", build: func() []byte { return cbor.AppendFloat(nil, signalingNaN64) }, wantHex: "f97e00"},
+		// Finite values keep their fixed width.
+		{name: "f64_max", build: func() []byte { return cbor.AppendFloat64(nil, math.MaxFloat64) }, wantHex: "fb7fefffffffffffff"},
+		{name: "f32_max", build: func() []byte { return cbor.AppendFloat32(nil, math.MaxFloat32) }, wantHex: "fa7f7fffff"},
+	}
+	for _, tc := range cases {
+		if got := hex.EncodeToString(tc.build()); got != tc.wantHex {
+			t.Errorf("%s: got %s, want %s", tc.name, got, tc.wantHex)
+		}
+	}
+}
+
+func TestNonFiniteFloatDecoding(t *testing.T) {
+	cases := []struct {
+		hex   string
+		check func(float64) bool
+	}{
+		{hex: "f97c00", check: func(f float64) bool { return math.IsInf(f, 1) }},
+		{hex: "f9fc00", check: func(f float64) bool { return math.IsInf(f, -1) }},
+		{hex: "f97e00", check: math.IsNaN},
+		{hex: "fa7fc00000", check: math.IsNaN},
+		{hex: "fb7ff0000000000001", check: math.IsNaN},
+		{hex: "fbfff0000000000000", check: func(f float64) bool { return math.IsInf(f, -1) }},
+	}
+	for _, tc := range cases {
+		b, _ := hex.DecodeString(tc.hex)
+		f64, rest, err := cbor.ReadFloat64Bytes(b)
+		if err != nil || len(rest) != 0 || !tc.check(f64) {
+			t.Errorf("ReadFloat64Bytes(%s) = %v, %d, %v", tc.hex, f64, len(rest), err)
+		}
+		if b[0] == 0xfb {
+			continue
+		}
+		f32, rest, err := cbor.ReadFloat32Bytes(b)
+		if err != nil || len(rest) != 0 || !tc.check(float64(f32)) {
+			t.Errorf("ReadFloat32Bytes(%s) = %v, %d, %v", tc.hex, f32, len(rest), err)
+		}
+	}
+}
+
+func TestStrictReaderRejectsNonCanonicalNaN(t *testing.T) {
+	cases := []struct {
+		hex     string
+		wantErr bool
+	}{
+		{hex: "f97e00", wantErr: false},
+		{hex: "f97c00", wantErr: false},
+		{hex: "f97e01", wantErr: true},             // half NaN with payload
+		{hex: "fa7fc00000", wantErr: true},         // single NaN
+		{hex: "fb7ff8000000000000", wantErr: true}, // double quiet NaN
+		{hex: "fb7ff0000000000001", wantErr: true}, // double signaling NaN
+		{hex: "fb7ff0000000000000", wantErr: true}, // double +Inf
+	}
+	for _, tc := range cases {
+		b, _ := hex.DecodeString(tc.hex)
+		r := cbor.NewReaderBytes(b)
+		r.SetStrictDecode(true)
+		_, err := r.ReadFloat64()
+		if tc.wantErr != (err != nil) {
+			t.Errorf("strict ReadFloat64(%s): err = %v, wantErr %v", tc.hex, err, tc.wantErr)
+		}
+		if tc.wantErr && !errors.Is(err, cbor.ErrNonCanonicalFloat) {
+			t.Errorf("strict ReadFloat64(%s): expected ErrNonCanonicalFloat, got %v", tc.hex, err)
+		}
+
+		// Non-strict readers accept every form.
+		if _, err := cbor.NewReaderBytes(b).ReadFloat64(); err != nil {
+			t.Errorf("ReadFloat64(%s) error: %v", tc.hex, err)
+		}
+	}
+}
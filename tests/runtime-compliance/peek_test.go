@@ -0,0 +1,83 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+func TestPeek(t *testing.T) {
+	cases := []struct {
+		name string
+		in   []byte
+		want cbor.MajorType
+	}{
+		{"uint", []byte{0x18, 0x18}, cbor.MajorUint},
+		{"negint", []byte{0x20}, cbor.MajorNegInt},
+		{"bytes", []byte{0x42, 0x01, 0x02}, cbor.MajorBytes},
+		{"text", []byte{0x61, 'a'}, cbor.MajorText},
+		{"indefinite_array", []byte{0x9f, 0xff}, cbor.MajorArray},
+		{"map", []byte{0xa0}, cbor.MajorMap},
+		{"tag", []byte{0xc1, 0x00}, cbor.MajorTag},
+		{"float", []byte{0xf9, 0x3c, 0x00}, cbor.MajorSimple},
+		{"null", []byte{0xf6}, cbor.MajorSimple},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := cbor.Peek(tc.in)
+			if err != nil {
+				t.Fatalf("Peek: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("Peek = %v, want %v", got, tc.want)
+			}
+			// Peeking leaves the item to be read in full.
+			if _, err := cbor.Skip(tc.in); err != nil {
+				t.Fatalf("Skip after Peek: %v", err)
+			}
+		})
+	}
+}
+
+func TestPeekErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		in   []byte
+		want error
+	}{
+		{"empty", nil, cbor.ErrShortBytes},
+		{"short_head", []byte{0x19, 0x01}, cbor.ErrShortBytes},
+		{"reserved", []byte{0x1c}, cbor.ErrMalformedHead},
+		{"indefinite_uint", []byte{0x1f}, cbor.ErrMalformedHead},
+		{"indefinite_tag", []byte{0xdf}, cbor.ErrMalformedHead},
+	}
+	for _, tc := range cases {
+		if _, err := cbor.Peek(tc.in); !errors.Is(err, tc.want) {
+			t.Errorf("%s: error = %v, want %v", tc.name, err, tc.want)
+		}
+	}
+}
+
+func TestPeekTag(t *testing.T) {
+	b := cbor.AppendTag(nil, 1001)
+	b = cbor.AppendMapHeader(b, 0)
+	num, err := cbor.PeekTag(b)
+	if err != nil {
+		t.Fatalf("PeekTag: %v", err)
+	}
+	if num != 1001 {
+		t.Fatalf("PeekTag = %d, want 1001", num)
+	}
+	if major, _ := cbor.Peek(b); major != cbor.MajorTag {
+		t.Fatalf("Peek = %v, want %v", major, cbor.MajorTag)
+	}
+
+	var prefixErr cbor.InvalidPrefixError
+	if _, err := cbor.PeekTag([]byte{0xa0}); !errors.As(err, &prefixErr) {
+		t.Fatalf("PeekTag of a map: error = %v, want InvalidPrefixError", err)
+	}
+	if _, err := cbor.PeekTag([]byte{0xd9, 0x03}); !errors.Is(err, cbor.ErrShortBytes) {
+		t.Fatalf("PeekTag of a short head: error = %v, want ErrShortBytes", err)
+	}
+}
@@ -0,0 +1,141 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+func TestReadHead(t *testing.T) {
+	cases := []struct {
+		name  string
+		in    []byte
+		major byte
+		info  byte
+		arg   uint64
+		rest  int
+	}{
+		{"uint_direct", []byte{0x17, 0xff}, 0, 23, 23, 1},
+		{"uint8", []byte{0x18, 0x18}, 0, 24, 24, 0},
+		{"negint16", []byte{0x39, 0x01, 0x00}, 1, 25, 256, 0},
+		{"text32", []byte{0x7a, 0x00, 0x01, 0x00, 0x00}, 3, 26, 65536, 0},
+		{"tag64", []byte{0xdb, 0, 0, 0, 1, 0, 0, 0, 0, 0x00}, 6, 27, 1 << 32, 1},
+		{"float16_bits", []byte{0xf9, 0x3c, 0x00}, 7, 25, 0x3c00, 0},
+		{"indefinite_array", []byte{0x9f, 0xff}, 4, 31, 0, 1},
+		{"break", []byte{0xff}, 7, 31, 0, 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			major, info, arg, rest, err := cbor.ReadHead(tc.in)
+			if err != nil {
+				t.Fatalf("ReadHead: %v", err)
+			}
+			if major != tc.major || info != tc.info || arg != tc.arg || len(rest) != tc.rest {
+				t.Fatalf("got (%d, %d, %d, %d rest), want (%d, %d, %d, %d rest)",
+					major, info, arg, len(rest), tc.major, tc.info, tc.arg, tc.rest)
+			}
+		})
+	}
+
+	for _, in := range [][]byte{{0x1c}, {0x5d}, {0xfe}} {
+		if _, _, _, rest, err := cbor.ReadHead(in); !errors.Is(err, cbor.ErrMalformedHead) || len(rest) != len(in) {
+			t.Fatalf("ReadHead(%x) = %v, %d rest; want ErrMalformedHead, input unchanged", in, err, len(rest))
+		}
+	}
+}
+
+func TestReadPrimitivesShortBytes(t *testing.T) {
+	full := map[string][]byte{
+		"ReadHead":        cbor.AppendUint64(nil, 1<<40),
+		"ReadUint":        cbor.AppendUint64(nil, 1<<40),
+		"ReadTextString":  cbor.AppendTextString(nil, "hello"),
+		"ReadByteString":  cbor.AppendByteString(nil, []byte{1, 2, 3}),
+		"ReadArrayHeader": cbor.AppendArrayHeader(nil, 300),
+		"ReadMapHeader":   cbor.AppendMapHeader(nil, 300),
+		"ReadTag":         cbor.AppendTag(nil, 70000),
+		"ReadFloat64":     cbor.AppendFloat64(nil, 1.1),
+	}
+	read := map[string]func([]byte) ([]byte, error){
+		"ReadHead": func(b []byte) ([]byte, error) { _, _, _, o, err := cbor.ReadHead(b); return o, err },
+		"ReadUint": func(b []byte) ([]byte, error) { _, o, err := cbor.ReadUint(b); return o, err },
+		"ReadTextString": func(b []byte) ([]byte, error) {
+			_, o, err := cbor.ReadTextString(b)
+			return o, err
+		},
+		"ReadByteString": func(b []byte) ([]byte, error) {
+			_, o, err := cbor.ReadByteString(b)
+			return o, err
+		},
+		"ReadArrayHeader": func(b []byte) ([]byte, error) {
+			_, o, err := cbor.ReadArrayHeader(b)
+			return o, err
+		},
+		"ReadMapHeader": func(b []byte) ([]byte, error) {
+			_, o, err := cbor.ReadMapHeader(b)
+			return o, err
+		},
+		"ReadTag":     func(b []byte) ([]byte, error) { _, o, err := cbor.ReadTag(b); return o, err },
+		"ReadFloat64": func(b []byte) ([]byte, error) { _, o, err := cbor.ReadFloat64(b); return o, err },
+	}
+	for name, b := range full {
+		fn := read[name]
+		if _, err := fn(b); err != nil {
+			t.Fatalf("%s(%x): %v", name, b, err)
+		}
+		for n := 0; n < len(b); n++ {
+			rest, err := fn(b[:n])
+			if !errors.Is(err, cbor.ErrShortBytes) {
+				t.Fatalf("%s(%x) = %v, want ErrShortBytes", name, b[:n], err)
+			}
+			if len(rest) != n {
+				t.Fatalf("%s(%x) consumed input on error", name, b[:n])
+			}
+		}
+	}
+}
+
+func TestReadPrimitivesComposeWithAppend(t *testing.T) {
+	var b []byte
+	b = cbor.AppendTag(b, 1001)
+	b = cbor.AppendArrayHeader(b, 4)
+	b = cbor.AppendUint64(b, 500)
+	b = cbor.AppendTextString(b, "x")
+	b = cbor.AppendByteString(b, []byte{0xaa})
+	b = cbor.AppendFloat64(b, -2.5)
+
+	tag, o, err := cbor.ReadTag(b)
+	if err != nil || tag != 1001 {
+		t.Fatalf("ReadTag = %d, %v", tag, err)
+	}
+	n, o, err := cbor.ReadArrayHeader(o)
+	if err != nil || n != 4 {
+		t.Fatalf("ReadArrayHeader = %d, %v", n, err)
+	}
+	u, o, err := cbor.ReadUint(o)
+	if err != nil || u != 500 {
+		t.Fatalf("ReadUint = %d, %v", u, err)
+	}
+	s, o, err := cbor.ReadTextString(o)
+	if err != nil || s != "x" {
+		t.Fatalf("ReadTextString = %q, %v", s, err)
+	}
+	bs, o, err := cbor.ReadByteString(o)
+	if err != nil || len(bs) != 1 || bs[0] != 0xaa {
+		t.Fatalf("ReadByteString = %x, %v", bs, err)
+	}
+	f, o, err := cbor.ReadFloat64(o)
+	if err != nil || f != -2.5 || len(o) != 0 {
+		t.Fatalf("ReadFloat64 = %v, %v, %d rest", f, err, len(o))
+	}
+
+	// A wrong major type is a typed error and consumes nothing.
+	in := cbor.AppendTextString(nil, "x")
+	if _, rest, err := cbor.ReadUint(in); err == nil || len(rest) != len(in) {
+		t.Fatalf("ReadUint(text) = %v, %d rest", err, len(rest))
+	}
+	var pe cbor.InvalidPrefixError
+	if _, _, err := cbor.ReadUint(in); !errors.As(err, &pe) {
+		t.Fatalf("ReadUint(text) error %T, want InvalidPrefixError", err)
+	}
+}
@@ -0,0 +1,66 @@
+package tests
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+func TestDefaultTimeFormat(t *testing.T) {
+	defer func(f cbor.TimeFormat) { cbor.DefaultTimeFormat = f }(cbor.DefaultTimeFormat)
+	at := time.Unix(1700000000, 500e6).UTC()
+	cases := []struct {
+		f    cbor.TimeFormat
+		want string
+	}{
+		{cbor.TimeEpoch, "c1fb41d954fc40200000"},
+		{cbor.TimeEpochInt, "c11a6553f100"},
+		{cbor.TimeEpochFloat, "c1fb41d954fc40200000"},
+		{cbor.TimeRFC3339, "c07632303233" + hex.EncodeToString([]byte("-11-14T22:13:20.5Z"))},
+	}
+	for _, tc := range cases {
+		cbor.DefaultTimeFormat = tc.f
+		if got := hex.EncodeToString(cbor.AppendTime(nil, at)); got != tc.want {
+			t.Errorf("%v: AppendTime = %s, want %s", tc.f, got, tc.want)
+		}
+		// Marshal follows the default too.
+		b, err := cbor.Marshal(at)
+		if err != nil {
+			t.Fatalf("%v: Marshal error: %v", tc.f, err)
+		}
+		if got := hex.EncodeToString(b); got != tc.want {
+			t.Errorf("%v: Marshal = %s, want %s", tc.f, got, tc.want)
+		}
+		var out time.Time
+		if err := cbor.Unmarshal(b, &out); err != nil {
+			t.Fatalf("%v: Unmarshal error: %v", tc.f, err)
+		}
+		if want := at.Truncate(time.Second); tc.f == cbor.TimeEpochInt && !out.Equal(want) {
+			t.Errorf("%v: Unmarshal = %v, want %v", tc.f, out, want)
+		} else if tc.f != cbor.TimeEpochInt && !out.Equal(at) {
+			t.Errorf("%v: Unmarshal = %v, want %v", tc.f, out, at)
+		}
+	}
+}
+
+func TestTimeTagOptionsBeatDefaultTimeFormat(t *testing.T) {
+	defer func(f cbor.TimeFormat) { cbor.DefaultTimeFormat = f }(cbor.DefaultTimeFormat)
+	cbor.DefaultTimeFormat = cbor.TimeRFC3339
+	type event struct {
+		Epoch time.Time `cbor:"e,tag=1"`
+		Float time.Time `cbor:"f,tag=1,float"`
+		Text  time.Time `cbor:"t,tag=0"`
+	}
+	at := time.Unix(5, 0).UTC()
+	b, err := cbor.Marshal(event{Epoch: at, Float: at, Text: at})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Each field is tagged once, in the form its tag option names.
+	want := "a3" + "6165c105" + "6166c1fb4014000000000000" + "6174c074" + hex.EncodeToString([]byte("1970-01-01T00:00:05Z"))
+	if got := hex.EncodeToString(b); got != want {
+		t.Fatalf("Marshal = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,108 @@
+package tests
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+	"github.com/delaneyj/cbor/runtime/cbortest"
+)
+
+func TestValidateCanonical(t *testing.T) {
+	cases := []struct {
+		hex    string
+		offset int    // -1 when canonical
+		want   string // substring of the error message
+	}{
+		{"00", -1, ""},
+		{"1818", -1, ""},
+		{"a2616101616202", -1, ""}, // {"a": 1, "b": 2}
+		{"a201016161f4", -1, ""},   // {1: 1, "a": false}
+		{"f98000", -1, ""},         // -0.0
+		{"f97e00", -1, ""},         // NaN
+		{"fa47c35000", -1, ""},     // 100000.0
+		{"c11a514b67b0", -1, ""},   // 1(1363896240)
+		{"1817", 0, "argument 23 is 2 bytes; shortest is 1"},
+		{"8119000a", 1, "argument 10 is 3 bytes; shortest is 1"},
+		{"d80101", 0, "major type 6 (tag) with argument 1"},
+		{"9f01ff", 0, "indefinite length on major type 4 (array)"},
+		{"825f41004100ff00", 1, "indefinite length on major type 2 (byte string)"},
+		{"a2616201616102", 4, `map key "a" sorts before "b"`},
+		{"a2616101616102", 4, `duplicate map key "a"`},
+		{"fb3ff0000000000000", 0, "float 1 is 9 bytes; preferred form is f93c00"},
+		{"fa3fc00000", 0, "preferred form is f93e00"},
+		{"fb3ff199999999999a", -1, ""}, // 1.1 needs float64
+		{"f818", 0, "simple value 24 must use the one-byte form"},
+		{"0000", 1, "1 trailing bytes"},
+	}
+	for _, tc := range cases {
+		b, _ := hex.DecodeString(tc.hex)
+		err := cbor.ValidateCanonical(b)
+		if tc.offset < 0 {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tc.hex, err)
+			}
+			continue
+		}
+		var ce *cbor.CanonicalError
+		if !errors.As(err, &ce) {
+			t.Errorf("%s: error = %v, want *CanonicalError", tc.hex, err)
+			continue
+		}
+		if ce.Offset != tc.offset || !strings.Contains(ce.Error(), tc.want) {
+			t.Errorf("%s: error = %v, want offset %d and %q", tc.hex, err, tc.offset, tc.want)
+		}
+	}
+
+	// Malformed input reports the well-formedness error itself.
+	if err := cbor.ValidateCanonical([]byte{0x82, 0x01}); !errors.Is(err, cbor.ErrShortBytes) {
+		t.Fatalf("truncated array: error = %v, want ErrShortBytes", err)
+	}
+}
+
+func TestValidateCanonicalRFC7049Order(t *testing.T) {
+	cbor.MapKeyOrder = cbor.RFC7049
+	defer func() { cbor.MapKeyOrder = cbor.RFC8949 }()
+
+	// {"a": 1, 1000: 2}: "a" (0x6161) is shorter than 1000 (0x1903e8).
+	b, _ := hex.DecodeString("a26161011903e802")
+	if err := cbor.ValidateCanonical(b); err != nil {
+		t.Fatalf("RFC 7049 order rejected: %v", err)
+	}
+	cbor.MapKeyOrder = cbor.RFC8949
+	if err := cbor.ValidateCanonical(b); err == nil {
+		t.Fatalf("RFC 8949 order accepted a length-first map")
+	}
+}
+
+// recordingTB captures the failures reported through it.
+type recordingTB struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestAssertCanonical(t *testing.T) {
+	m := map[string]uint64{"b": 2, "a": 1, "c": 300}
+	b := cbor.AppendMapDeterministicStrUint64(nil, m)
+	cbortest.AssertCanonical(t, b)
+
+	rec := &recordingTB{TB: t}
+	bad, _ := hex.DecodeString("a2616201616102")
+	cbortest.AssertCanonical(rec, bad)
+	if len(rec.failures) != 1 {
+		t.Fatalf("failures = %q, want one", rec.failures)
+	}
+	got := rec.failures[0]
+	if !strings.Contains(got, "offset 4") || !strings.Contains(got, `{"b": 1, "a": 2}`) {
+		t.Fatalf("failure %q lacks the offset or diagnostic notation", got)
+	}
+}
@@ -0,0 +1,343 @@
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+	"unsafe"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+	"github.com/delaneyj/cbor/tests/structs"
+)
+
+func encodePeople(t *testing.T, people []structs.Person) []byte {
+	t.Helper()
+	var out []byte
+	for i := range people {
+		var err error
+		out, err = people[i].MarshalCBOR(out)
+		if err != nil {
+			t.Fatalf("MarshalCBOR error: %v", err)
+		}
+	}
+	return out
+}
+
+func TestDecoderReadsSequence(t *testing.T) {
+	people := []structs.Person{
+		{Name: "Ada", Age: 36, Data: []byte{1}},
+		{Name: "Grace", Data: bytes.Repeat([]byte{7}, 10000)},
+		{Name: "Ada", Age: 1},
+	}
+	stream := encodePeople(t, people)
+
+	readers := map[string]func() io.Reader{
+		"whole":   func() io.Reader { return bytes.NewReader(stream) },
+		"onebyte": func() io.Reader { return iotest.OneByteReader(bytes.NewReader(stream)) },
+		"dataerr": func() io.Reader { return iotest.DataErrReader(bytes.NewReader(stream)) },
+	}
+	for name, r := range readers {
+		for _, intern := range []bool{false, true} {
+			dec := cbor.NewDecoder(r())
+			dec.SetInternStrings(intern)
+			var got []structs.Person
+			for {
+				var p structs.Person
+				err := dec.Decode(&p)
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("%s intern=%v Decode error: %v", name, intern, err)
+				}
+				got = append(got, p)
+			}
+			if len(got) != len(people) {
+				t.Fatalf("%s intern=%v decoded %d items, want %d", name, intern, len(got), len(people))
+			}
+			for i := range people {
+				if got[i].Name != people[i].Name || got[i].Age != people[i].Age || !bytes.Equal(got[i].Data, people[i].Data) {
+					t.Fatalf("%s intern=%v item %d = %+v, want %+v", name, intern, i, got[i], people[i])
+				}
+			}
+			shared := unsafe.StringData(got[0].Name) == unsafe.StringData(got[2].Name)
+			if shared != intern {
+				t.Fatalf("%s intern=%v repeated name shared = %v", name, intern, shared)
+			}
+		}
+	}
+}
+
+func TestDecoderTruncatedItem(t *testing.T) {
+	stream := encodePeople(t, []structs.Person{{Name: "Ada"}, {Name: "Grace"}})
+	dec := cbor.NewDecoder(bytes.NewReader(stream[:len(stream)-2]))
+	var p structs.Person
+	if err := dec.Decode(&p); err != nil {
+		t.Fatalf("first Decode error: %v", err)
+	}
+	if err := dec.Decode(&p); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("second Decode error = %v, want io.ErrUnexpectedEOF", err)
+	}
+}
+
+func TestDecoderSkipsBadItem(t *testing.T) {
+	// A well-formed item of the wrong shape is consumed, so decoding can
+	// carry on with the next one.
+	stream := cbor.AppendString(nil, "not a person")
+	stream = append(stream, encodePeople(t, []structs.Person{{Name: "Ada"}})...)
+	dec := cbor.NewDecoder(bytes.NewReader(stream))
+	var p structs.Person
+	if err := dec.Decode(&p); err == nil {
+		t.Fatalf("expected an error decoding a string into Person")
+	}
+	if err := dec.Decode(&p); err != nil || p.Name != "Ada" {
+		t.Fatalf("Decode after failure = %+v, %v", p, err)
+	}
+}
+
+func TestDecoderContinueOnError(t *testing.T) {
+	var stream []byte
+	stream = append(stream, encodePeople(t, []structs.Person{{Name: "Ada"}})...)
+	badOff := len(stream)
+	stream = cbor.AppendString(stream, "not a person")
+	garbageOff := len(stream)
+	// Reserved additional info and stray breaks are not items at all.
+	stream = append(stream, 0x1c, 0xff, 0xff)
+	stream = append(stream, encodePeople(t, []structs.Person{{Name: "Grace"}, {Name: "Linus"}})...)
+
+	dec := cbor.NewDecoder(iotest.OneByteReader(bytes.NewReader(stream)))
+	var errs []*cbor.ItemError
+	dec.ContinueOnError(func(e *cbor.ItemError) { errs = append(errs, e) })
+	var names []string
+	for {
+		var p structs.Person
+		err := dec.Decode(&p)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Decode error: %v", err)
+		}
+		names = append(names, p.Name)
+	}
+	if len(names) != 3 || names[0] != "Ada" || names[1] != "Grace" || names[2] != "Linus" {
+		t.Fatalf("decoded %v, want [Ada Grace Linus]", names)
+	}
+	if len(errs) != 2 {
+		t.Fatalf("got %d item errors, want 2: %v", len(errs), errs)
+	}
+	if errs[0].Offset != int64(badOff) || errs[1].Offset != int64(garbageOff) {
+		t.Fatalf("item errors at %d and %d, want %d and %d", errs[0].Offset, errs[1].Offset, badOff, garbageOff)
+	}
+	var pe cbor.InvalidPrefixError
+	if !errors.As(errs[0], &pe) {
+		t.Fatalf("first item error = %v, want it to wrap InvalidPrefixError", errs[0])
+	}
+
+	// A stream cut short inside an item still fails.
+	dec = cbor.NewDecoder(bytes.NewReader(stream[:len(stream)-2]))
+	dec.ContinueOnError(func(*cbor.ItemError) {})
+	var err error
+	for err == nil {
+		var p structs.Person
+		err = dec.Decode(&p)
+	}
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("Decode error = %v, want io.ErrUnexpectedEOF", err)
+	}
+}
+
+func TestDecodeReaderReadsOneItem(t *testing.T) {
+	people := []structs.Person{
+		{Name: "Ada", Age: 36, Data: bytes.Repeat([]byte{7}, 100000)},
+		{Name: "Grace"},
+	}
+	stream := encodePeople(t, people)
+	r := bytes.NewReader(stream)
+	first := len(stream) - len(encodePeople(t, people[1:]))
+
+	var p structs.Person
+	if err := cbor.DecodeReader(iotest.OneByteReader(r), &p); err != nil {
+		t.Fatalf("DecodeReader error: %v", err)
+	}
+	if p.Name != "Ada" || len(p.Data) != 100000 {
+		t.Fatalf("decoded %q with %d data bytes", p.Name, len(p.Data))
+	}
+	if consumed := len(stream) - r.Len(); consumed != first {
+		t.Fatalf("DecodeReader consumed %d bytes, want exactly the %d of the first item", consumed, first)
+	}
+	if err := cbor.DecodeReader(r, &p); err != nil || p.Name != "Grace" {
+		t.Fatalf("second DecodeReader = %q, %v", p.Name, err)
+	}
+	if err := cbor.DecodeReader(r, &p); err != io.EOF {
+		t.Fatalf("DecodeReader at end = %v, want io.EOF", err)
+	}
+}
+
+func TestDecodeReaderIndefinite(t *testing.T) {
+	// {_ "name": (_ "A", "da"), "data": (_ h'01', h'02'), "x": [_ 1, [2]]}
+	var item []byte
+	item = append(item, 0xbf)
+	item = cbor.AppendString(item, "name")
+	item = append(item, 0x7f)
+	item = cbor.AppendString(item, "A")
+	item = cbor.AppendString(item, "da")
+	item = append(item, 0xff)
+	item = cbor.AppendString(item, "data")
+	item = append(item, 0x5f, 0x41, 0x01, 0x41, 0x02, 0xff)
+	item = cbor.AppendString(item, "x")
+	item = append(item, 0x9f, 0x01, 0x81, 0x02, 0xff)
+	item = append(item, 0xff)
+	next := cbor.AppendString(nil, "next")
+
+	r := bytes.NewReader(append(item, next...))
+	var raw cbor.Raw
+	if err := cbor.DecodeReader(r, &raw); err != nil {
+		t.Fatalf("DecodeReader error: %v", err)
+	}
+	if !bytes.Equal(raw, item) {
+		t.Fatalf("read %x, want %x", []byte(raw), item)
+	}
+	if r.Len() != len(next) {
+		t.Fatalf("%d bytes left, want %d", r.Len(), len(next))
+	}
+}
+
+func TestDecodeReaderErrors(t *testing.T) {
+	stream := encodePeople(t, []structs.Person{{Name: "Ada"}})
+	var p structs.Person
+	if err := cbor.DecodeReader(bytes.NewReader(stream[:len(stream)-1]), &p); err != io.ErrUnexpectedEOF {
+		t.Fatalf("truncated item error = %v, want io.ErrUnexpectedEOF", err)
+	}
+	// A huge announced length fails at the end of the input instead of
+	// allocating it up front.
+	huge := []byte{0x5b, 0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x00}
+	if err := cbor.DecodeReader(bytes.NewReader(huge), &p); err != io.ErrUnexpectedEOF {
+		t.Fatalf("huge length error = %v, want io.ErrUnexpectedEOF", err)
+	}
+	for _, bad := range [][]byte{{0x1c}, {0xff}, {0x9f, 0x01}, {0x7f, 0x41, 0x00, 0xff}} {
+		if err := cbor.DecodeReader(bytes.NewReader(bad), &p); err == nil {
+			t.Fatalf("DecodeReader(%x) succeeded", bad)
+		}
+	}
+	if err := cbor.DecodeReader(bytes.NewReader(cbor.AppendString(nil, "x")), &p); err == nil {
+		t.Fatalf("expected an error decoding a string into Person")
+	}
+}
+
+func TestInternerBounds(t *testing.T) {
+	in := cbor.NewInterner()
+	long := string(bytes.Repeat([]byte{'x'}, 100))
+	for _, s := range []string{"ok", "ok", long} {
+		if _, _, err := in.ReadStringBytes(cbor.AppendString(nil, s)); err != nil {
+			t.Fatalf("ReadStringBytes(%q) error: %v", s, err)
+		}
+	}
+	if in.Len() != 1 {
+		t.Fatalf("Interner retained %d strings, want 1 (long strings are not retained)", in.Len())
+	}
+
+	// A nil Interner falls back to plain decoding.
+	var none *cbor.Interner
+	s, rest, err := none.ReadStringBytes(cbor.AppendString(nil, "ok"))
+	if err != nil || s != "ok" || len(rest) != 0 {
+		t.Fatalf("nil Interner ReadStringBytes = %q, %d, %v", s, len(rest), err)
+	}
+
+	if _, _, err := in.ReadStringBytes([]byte{0x62, 0xff, 0xfe}); !errors.Is(err, cbor.ErrInvalidUTF8) {
+		t.Fatalf("invalid UTF-8 error = %v, want ErrInvalidUTF8", err)
+	}
+}
+
+func TestDecoderExpectTag(t *testing.T) {
+	const envelope = 18
+	var stream []byte
+	for _, p := range []structs.Person{{Name: "Ada"}, {Name: "Grace"}} {
+		var err error
+		if stream, err = cbor.MarshalTagged(stream, envelope, &p); err != nil {
+			t.Fatalf("MarshalTagged error: %v", err)
+		}
+	}
+	untaggedOff := len(stream)
+	stream = append(stream, encodePeople(t, []structs.Person{{Name: "Linus"}})...)
+	wrongOff := len(stream)
+	stream = cbor.AppendTag(stream, envelope+1)
+	stream = append(stream, encodePeople(t, []structs.Person{{Name: "Ken"}})...)
+
+	dec := cbor.NewDecoder(bytes.NewReader(stream))
+	dec.SetExpectTag(envelope)
+	var errs []*cbor.ItemError
+	dec.ContinueOnError(func(e *cbor.ItemError) { errs = append(errs, e) })
+	var names []string
+	for {
+		var p structs.Person
+		err := dec.Decode(&p)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Decode error: %v", err)
+		}
+		names = append(names, p.Name)
+	}
+	if len(names) != 2 || names[0] != "Ada" || names[1] != "Grace" {
+		t.Fatalf("decoded %v, want [Ada Grace]", names)
+	}
+	if len(errs) != 2 || errs[0].Offset != int64(untaggedOff) || errs[1].Offset != int64(wrongOff) {
+		t.Fatalf("item errors %v, want them at %d and %d", errs, untaggedOff, wrongOff)
+	}
+	var pe cbor.InvalidPrefixError
+	var te cbor.UnexpectedTagError
+	if !errors.As(errs[0], &pe) || !errors.As(errs[1], &te) || te.Tag != envelope+1 {
+		t.Fatalf("item errors = %v, %v; want InvalidPrefixError and UnexpectedTagError{%d}", errs[0], errs[1], envelope+1)
+	}
+
+	// Without the option the envelope is an unexpected tag on a struct.
+	dec = cbor.NewDecoder(bytes.NewReader(stream))
+	dec.SetExpectTag(envelope)
+	dec.ClearExpectTag()
+	var p structs.Person
+	if err := dec.Decode(&p); err == nil {
+		t.Fatalf("Decode of a tagged item without SetExpectTag succeeded")
+	}
+}
+
+func TestDecoderOnComplete(t *testing.T) {
+	ada := encodePeople(t, []structs.Person{{Name: "Ada", Age: 36}})
+	var stream []byte
+	stream = append(stream, ada...)
+	stream = cbor.AppendString(stream, "not a person")
+	// Malformed framing is dropped without a report.
+	stream = append(stream, 0xff)
+	grace := encodePeople(t, []structs.Person{{Name: "Grace"}})
+	stream = append(stream, grace...)
+
+	dec := cbor.NewDecoder(bytes.NewReader(stream))
+	dec.ContinueOnError(func(*cbor.ItemError) {})
+	var stats []cbor.ItemStats
+	dec.OnComplete(func(s cbor.ItemStats) { stats = append(stats, s) })
+	var p structs.Person
+	for {
+		if err := dec.Decode(&p); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("Decode error: %v", err)
+		}
+	}
+
+	want := []struct {
+		size int
+		fail bool
+	}{{len(ada), false}, {len(cbor.AppendString(nil, "not a person")), true}, {len(grace), false}}
+	if len(stats) != len(want) {
+		t.Fatalf("OnComplete called %d times, want %d", len(stats), len(want))
+	}
+	for i, w := range want {
+		s := stats[i]
+		if s.Value != &p || s.Size != w.size || (s.Err != nil) != w.fail || s.Duration < 0 {
+			t.Fatalf("stats[%d] = %+v, want Size %d, failed %v", i, s, w.size, w.fail)
+		}
+	}
+}
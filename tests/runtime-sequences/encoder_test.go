@@ -0,0 +1,235 @@
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+	"github.com/delaneyj/cbor/tests/structs"
+)
+
+func TestEncoderWritesSequence(t *testing.T) {
+	for _, pooled := range []bool{false, true} {
+		var out bytes.Buffer
+		enc := cbor.NewEncoder(&out)
+		enc.SetPooledBuffers(pooled)
+
+		people := []structs.Person{
+			{Name: "Ada", Age: 36},
+			{Name: "Grace", Data: []byte{1, 2, 3}},
+		}
+		var want []byte
+		for i := range people {
+			if err := enc.Encode(&people[i]); err != nil {
+				t.Fatalf("pooled=%v Encode error: %v", pooled, err)
+			}
+			want, _ = people[i].MarshalCBOR(want)
+		}
+		if out.Len() != 0 {
+			t.Fatalf("pooled=%v wrote %d bytes before Flush", pooled, out.Len())
+		}
+		if enc.Buffered() != len(want) {
+			t.Fatalf("pooled=%v Buffered = %d, want %d", pooled, enc.Buffered(), len(want))
+		}
+		if err := enc.Flush(); err != nil {
+			t.Fatalf("pooled=%v Flush error: %v", pooled, err)
+		}
+		if !bytes.Equal(out.Bytes(), want) {
+			t.Fatalf("pooled=%v output mismatch:\n got %x\nwant %x", pooled, out.Bytes(), want)
+		}
+
+		// The encoder stays usable after Flush.
+		if err := enc.Encode(&people[0]); err != nil {
+			t.Fatalf("pooled=%v Encode after Flush error: %v", pooled, err)
+		}
+		if err := enc.Flush(); err != nil {
+			t.Fatalf("pooled=%v second Flush error: %v", pooled, err)
+		}
+		items, err := cbor.SplitSequenceBytes(out.Bytes())
+		if err != nil || len(items) != 3 {
+			t.Fatalf("pooled=%v expected 3 items, got %d (%v)", pooled, len(items), err)
+		}
+	}
+}
+
+func TestEncoderDropsFailedItem(t *testing.T) {
+	var out bytes.Buffer
+	enc := cbor.NewEncoder(&out)
+	if err := enc.Encode(&structs.Person{Name: "ok"}); err != nil {
+		t.Fatalf("Encode error: %v", err)
+	}
+	before := enc.Buffered()
+
+	// A cyclic value fails part-way through encoding.
+	node := &structs.TreeNode{Value: "root"}
+	node.Next = node
+	if err := enc.Encode(node); !errors.Is(err, cbor.ErrCycleDetected) {
+		t.Fatalf("expected ErrCycleDetected, got %v", err)
+	}
+	if enc.Buffered() != before {
+		t.Fatalf("failed item left %d bytes buffered", enc.Buffered()-before)
+	}
+}
+
+// failingMarshaler fails to encode, returning its buffer as given or nil.
+type failingMarshaler struct{ returnNil bool }
+
+func (m failingMarshaler) MarshalCBOR(b []byte) ([]byte, error) {
+	if m.returnNil {
+		return nil, errors.New("boom")
+	}
+	return append(b, 0x01, 0x02), errors.New("boom")
+}
+
+func TestEncoderFailingMarshaler(t *testing.T) {
+	for _, pooled := range []bool{false, true} {
+		for _, m := range []failingMarshaler{{returnNil: true}, {}} {
+			var out bytes.Buffer
+			enc := cbor.NewEncoder(&out)
+			enc.SetPooledBuffers(pooled)
+			first := structs.Person{Name: "first"}
+			if err := enc.Encode(&first); err != nil {
+				t.Fatalf("pooled=%v Encode error: %v", pooled, err)
+			}
+			if err := enc.Encode(m); err == nil || err.Error() != "boom" {
+				t.Fatalf("pooled=%v %+v: Encode error = %v, want boom", pooled, m, err)
+			}
+			// Items from other encoders must not land in this one's buffer
+			// through the pool.
+			other := cbor.NewEncoder(io.Discard)
+			other.SetPooledBuffers(true)
+			if err := other.Encode(&structs.Person{Name: "other", Data: make([]byte, 64)}); err != nil {
+				t.Fatalf("Encode error: %v", err)
+			}
+			if err := enc.Flush(); err != nil {
+				t.Fatalf("pooled=%v Flush error: %v", pooled, err)
+			}
+			want, _ := first.MarshalCBOR(nil)
+			if !bytes.Equal(out.Bytes(), want) {
+				t.Fatalf("pooled=%v %+v: output %x, want %x", pooled, m, out.Bytes(), want)
+			}
+		}
+	}
+}
+
+func TestEncoderAutoFlush(t *testing.T) {
+	var out bytes.Buffer
+	enc := cbor.NewEncoder(&out)
+	enc.SetPooledBuffers(true)
+	big := structs.Person{Name: "big", Data: make([]byte, 70<<10)}
+	if err := enc.Encode(&big); err != nil {
+		t.Fatalf("Encode error: %v", err)
+	}
+	if enc.Buffered() != 0 || out.Len() == 0 {
+		t.Fatalf("expected automatic flush, buffered=%d written=%d", enc.Buffered(), out.Len())
+	}
+}
+
+func TestBufferPoolSizeClasses(t *testing.T) {
+	for _, size := range []int{0, 1, 512, 513, 4000, 1 << 20, 1<<20 + 1} {
+		b := cbor.GetBuffer(size)
+		if len(b) != 0 || cap(b) < size {
+			t.Fatalf("GetBuffer(%d): len=%d cap=%d", size, len(b), cap(b))
+		}
+		cbor.PutBuffer(append(b, 1))
+	}
+	// Buffers of odd capacity are filed under a class they can serve.
+	cbor.PutBuffer(make([]byte, 0, 3000))
+	if b := cbor.GetBuffer(2048); cap(b) < 2048 {
+		t.Fatalf("GetBuffer(2048) returned cap %d", cap(b))
+	}
+}
+
+func BenchmarkEncoderPooled(b *testing.B) {
+	p := structs.Person{Name: "Ada", Age: 36, Data: make([]byte, 256)}
+	for _, pooled := range []bool{false, true} {
+		name := "private"
+		if pooled {
+			name = "pooled"
+		}
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				// One short-lived encoder per "request".
+				enc := cbor.NewEncoder(io.Discard)
+				enc.SetPooledBuffers(pooled)
+				for j := 0; j < 8; j++ {
+					_ = enc.Encode(&p)
+				}
+				_ = enc.Flush()
+			}
+		})
+	}
+}
+
+func TestEncoderWrapTag(t *testing.T) {
+	var out bytes.Buffer
+	enc := cbor.NewEncoder(&out)
+	enc.SetWrapTag(18)
+	p := structs.Person{Name: "Ada"}
+	if err := enc.Encode(&p); err != nil {
+		t.Fatalf("Encode error: %v", err)
+	}
+	enc.ClearWrapTag()
+	if err := enc.Encode(&p); err != nil {
+		t.Fatalf("Encode error: %v", err)
+	}
+	if err := enc.Flush(); err != nil {
+		t.Fatalf("Flush error: %v", err)
+	}
+
+	item, _ := p.MarshalCBOR(nil)
+	want := append(cbor.AppendTag(nil, 18), item...)
+	want = append(want, item...)
+	if !bytes.Equal(out.Bytes(), want) {
+		t.Fatalf("output %x, want %x", out.Bytes(), want)
+	}
+
+	var got structs.Person
+	rest, err := cbor.UnmarshalTagged(out.Bytes(), 18, &got)
+	if err != nil || got.Name != "Ada" || !bytes.Equal(rest, item) {
+		t.Fatalf("UnmarshalTagged = %+v, %x, %v; want Ada, %x, nil", got, rest, err, item)
+	}
+	if _, err := cbor.UnmarshalTagged(rest, 18, &got); err == nil {
+		t.Fatalf("UnmarshalTagged of an untagged item succeeded")
+	}
+}
+
+func TestEncoderOnComplete(t *testing.T) {
+	var out bytes.Buffer
+	enc := cbor.NewEncoder(&out)
+	enc.SetWrapTag(18)
+	var stats []cbor.ItemStats
+	enc.OnComplete(func(s cbor.ItemStats) { stats = append(stats, s) })
+
+	p := structs.Person{Name: "Ada", Age: 36}
+	if err := enc.Encode(&p); err != nil {
+		t.Fatalf("Encode error: %v", err)
+	}
+	node := &structs.TreeNode{Value: "root"}
+	node.Next = node
+	if err := enc.Encode(node); !errors.Is(err, cbor.ErrCycleDetected) {
+		t.Fatalf("expected ErrCycleDetected, got %v", err)
+	}
+	// EncodeFunc writes pieces of items and is not reported.
+	if err := enc.EncodeFunc(func(b []byte) ([]byte, error) { return cbor.AppendNil(b), nil }); err != nil {
+		t.Fatalf("EncodeFunc error: %v", err)
+	}
+
+	if len(stats) != 2 {
+		t.Fatalf("OnComplete called %d times, want 2", len(stats))
+	}
+	if s := stats[0]; s.Value != &p || s.Size != enc.Buffered()-1 || s.Err != nil || s.Duration < 0 {
+		t.Fatalf("stats[0] = %+v, want Size %d and no error", s, enc.Buffered()-1)
+	}
+	if s := stats[1]; s.Value != node || s.Size != 0 || !errors.Is(s.Err, cbor.ErrCycleDetected) {
+		t.Fatalf("stats[1] = %+v, want Size 0 and ErrCycleDetected", s)
+	}
+
+	enc.OnComplete(nil)
+	if err := enc.Encode(&p); err != nil || len(stats) != 2 {
+		t.Fatalf("Encode after OnComplete(nil) = %v with %d reports, want nil and 2", err, len(stats))
+	}
+}
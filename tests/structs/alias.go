@@ -0,0 +1,10 @@
+package structs
+
+// Profile was renamed field by field across schema versions: decoders
+// still read the keys older writers used, encoders write the new ones.
+type Profile struct {
+	DisplayName string   `cbor:"display_name,alias=name,alias=nick"`
+	Email       string   `cbor:"email,omitempty,alias=mail"`
+	Roles       []string `cbor:"roles,alias=groups"`
+	Age         int      `cbor:"age"`
+}
@@ -0,0 +1,85 @@
+package structs
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+var profileDecoders = []struct {
+	name   string
+	decode func(dst *Profile, b []byte) ([]byte, error)
+}{
+	{"DecodeSafe", (*Profile).DecodeSafe},
+	{"DecodeTrusted", (*Profile).DecodeTrusted},
+}
+
+// legacyProfile encodes a Profile under the keys an older writer used.
+func legacyProfile(nameKey string) []byte {
+	var b []byte
+	b = cbor.AppendMapHeader(b, 4)
+	b = cbor.AppendString(b, nameKey)
+	b = cbor.AppendString(b, "Ada")
+	b = cbor.AppendString(b, "mail")
+	b = cbor.AppendString(b, "ada@example.com")
+	b = cbor.AppendString(b, "groups")
+	b = cbor.AppendArrayHeader(b, 1)
+	b = cbor.AppendString(b, "ops")
+	b = cbor.AppendString(b, "age")
+	return cbor.AppendInt(b, 36)
+}
+
+func TestProfileDecodesAliases(t *testing.T) {
+	want := Profile{DisplayName: "Ada", Email: "ada@example.com", Roles: []string{"ops"}, Age: 36}
+	for _, key := range []string{"display_name", "name", "nick"} {
+		for _, d := range profileDecoders {
+			var out Profile
+			rest, err := d.decode(&out, legacyProfile(key))
+			if err != nil || len(rest) != 0 {
+				t.Fatalf("%s %s: err=%v rest=%d", key, d.name, err, len(rest))
+			}
+			if !reflect.DeepEqual(out, want) {
+				t.Fatalf("%s %s: got %+v, want %+v", key, d.name, out, want)
+			}
+		}
+	}
+}
+
+func TestProfileEncodesPrimaryName(t *testing.T) {
+	in := Profile{DisplayName: "Ada", Email: "ada@example.com", Roles: []string{"ops"}, Age: 36}
+	b, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"display_name", "email", "roles"} {
+		if !bytes.Contains(b, cbor.AppendString(nil, key)) {
+			t.Fatalf("encoding lacks key %q: % x", key, b)
+		}
+	}
+	for _, key := range []string{"nick", "mail", "groups"} {
+		if bytes.Contains(b, cbor.AppendString(nil, key)) {
+			t.Fatalf("encoding uses alias %q: % x", key, b)
+		}
+	}
+}
+
+func TestProfileAliasReflect(t *testing.T) {
+	type plain Profile // no generated methods
+	var out plain
+	if err := cbor.Unmarshal(legacyProfile("nick"), &out); err != nil {
+		t.Fatal(err)
+	}
+	want := Profile{DisplayName: "Ada", Email: "ada@example.com", Roles: []string{"ops"}, Age: 36}
+	if !reflect.DeepEqual(Profile(out), want) {
+		t.Fatalf("Unmarshal = %+v, want %+v", out, want)
+	}
+	got, err := cbor.Marshal(plain(want))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gen, _ := want.MarshalCBOR(nil); !bytes.Equal(got, gen) {
+		t.Fatalf("Marshal = % x\nwant      % x", got, gen)
+	}
+}
@@ -0,0 +1,34 @@
+package structs
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAppendCBORNested(t *testing.T) {
+	bins := Bins{{Label: "a", Items: []string{"x", "y"}}, {Label: "b"}}
+	want, err := bins.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	prefix := []byte{0xAA, 0xBB}
+	got, err := bins.AppendCBOR(prefix)
+	if err != nil {
+		t.Fatalf("AppendCBOR error: %v", err)
+	}
+	if !bytes.Equal(got[:2], prefix) || !bytes.Equal(got[2:], want) {
+		t.Fatalf("AppendCBOR = % x, want % x after % x", got, want, prefix)
+	}
+
+	// Each Bin is appended straight into buf, so nothing is allocated
+	// once buf is large enough.
+	buf := make([]byte, 0, 2*len(want))
+	allocs := testing.AllocsPerRun(100, func() {
+		if _, err := bins.AppendCBOR(buf); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Fatalf("AppendCBOR allocated %v times", allocs)
+	}
+}
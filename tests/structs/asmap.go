@@ -0,0 +1,24 @@
+package structs
+
+// EnvVar is one variable of a process environment.
+type EnvVar struct {
+	Name  string `cbor:"name"`
+	Value string `cbor:"value"`
+}
+
+// Port is a named scalar key type.
+type Port uint16
+
+// Listener maps a port to the addresses listening on it.
+type Listener struct {
+	Port  Port
+	Hosts []string
+}
+
+// Deployment exercises the asmap option: slices of two-field structs
+// written as maps from the first field to the second, in slice order.
+type Deployment struct {
+	Env       []EnvVar   `cbor:"env,asmap"`
+	Listeners []Listener `cbor:"listeners,asmap,omitempty"`
+	Steps     []EnvVar   `cbor:"steps"`
+}
@@ -0,0 +1,271 @@
+package structs
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+func sampleDeployment() Deployment {
+	return Deployment{
+		Env: []EnvVar{{Name: "PATH", Value: "/bin"}, {Name: "HOME", Value: "/root"}},
+		Listeners: []Listener{
+			{Port: 443, Hosts: []string{"a.example", "b.example"}},
+			{Port: 80},
+		},
+		Steps: []EnvVar{{Name: "build", Value: "make"}},
+	}
+}
+
+// deploymentRepeatingEnv encodes a Deployment whose env map has key "A"
+// twice.
+func deploymentRepeatingEnv() []byte {
+	var b []byte
+	b = cbor.AppendMapHeader(b, 2)
+	b = cbor.AppendString(b, "env")
+	b = cbor.AppendMapHeader(b, 3)
+	b = cbor.AppendString(b, "A")
+	b = cbor.AppendString(b, "first")
+	b = cbor.AppendString(b, "B")
+	b = cbor.AppendString(b, "b")
+	b = cbor.AppendString(b, "A")
+	b = cbor.AppendString(b, "last")
+	b = cbor.AppendString(b, "steps")
+	return cbor.AppendArrayHeader(b, 0)
+}
+
+func TestDeploymentAsMapRoundTrip(t *testing.T) {
+	in := sampleDeployment()
+	want := `{"env": {"PATH": "/bin", "HOME": "/root"}, ` +
+		`"listeners": {443: ["a.example", "b.example"], 80: []}, ` +
+		`"steps": [{"name": "build", "value": "make"}]}`
+	if got := diagOf(t, &in); got != want {
+		t.Fatalf("encoding = %s\nwant %s", got, want)
+	}
+	if got := in.DiagString(); got != want {
+		t.Fatalf("DiagString() = %s\nwant %s", got, want)
+	}
+
+	b, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	decoders := map[string]func(*Deployment, []byte) ([]byte, error){
+		"DecodeSafe":    (*Deployment).DecodeSafe,
+		"DecodeTrusted": (*Deployment).DecodeTrusted,
+	}
+	for name, decode := range decoders {
+		var out Deployment
+		if _, err := decode(&out, b); err != nil {
+			t.Fatalf("%s error: %v", name, err)
+		}
+		if !reflect.DeepEqual(out, in) {
+			t.Fatalf("%s = %#v, want %#v", name, out, in)
+		}
+	}
+
+	// Marshal and Unmarshal write and read the same map.
+	rb, err := cbor.Marshal(in)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	if string(rb) != string(b) {
+		t.Fatalf("Marshal = %x, want %x", rb, b)
+	}
+	var out Deployment
+	if err := cbor.Unmarshal(b, &out); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if !reflect.DeepEqual(out, in) {
+		t.Fatalf("Unmarshal = %+v, want %+v", out, in)
+	}
+}
+
+func TestDeploymentAsMapReusesSlice(t *testing.T) {
+	in := Deployment{Env: []EnvVar{{Name: "A", Value: "1"}}}
+	b, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	out := Deployment{Env: make([]EnvVar, 3, 8)}
+	if _, err := out.DecodeSafe(b); err != nil {
+		t.Fatalf("DecodeSafe error: %v", err)
+	}
+	if len(out.Env) != 1 || cap(out.Env) != 8 || out.Env[0] != in.Env[0] {
+		t.Fatalf("Env = %+v (cap %d), want %+v in the old backing array", out.Env, cap(out.Env), in.Env)
+	}
+}
+
+func TestDeploymentAsMapDuplicateKeys(t *testing.T) {
+	b := deploymentRepeatingEnv()
+	tests := []struct {
+		policy cbor.DuplicateKeyPolicy
+		want   []EnvVar
+	}{
+		{cbor.DuplicateKeyFirst, []EnvVar{{"A", "first"}, {"B", "b"}}},
+		{cbor.DuplicateKeyLast, []EnvVar{{"A", "last"}, {"B", "b"}}},
+	}
+	for _, tc := range tests {
+		setOption(t, &cbor.DuplicateMapKeys, tc.policy)
+		var out Deployment
+		if _, err := out.DecodeSafe(b); err != nil {
+			t.Fatalf("%v: DecodeSafe error: %v", tc.policy, err)
+		}
+		if !reflect.DeepEqual(out.Env, tc.want) {
+			t.Fatalf("%v: DecodeSafe Env = %+v, want %+v", tc.policy, out.Env, tc.want)
+		}
+		out = Deployment{}
+		if err := cbor.Unmarshal(b, &out); err != nil {
+			t.Fatalf("%v: Unmarshal error: %v", tc.policy, err)
+		}
+		if !reflect.DeepEqual(out.Env, tc.want) {
+			t.Fatalf("%v: Unmarshal Env = %+v, want %+v", tc.policy, out.Env, tc.want)
+		}
+	}
+
+	setOption(t, &cbor.DuplicateMapKeys, cbor.DuplicateKeyError)
+	var out Deployment
+	var de *cbor.DecodeError
+	if _, err := out.DecodeSafe(b); !errors.Is(err, cbor.ErrDuplicateMapKey) || !errors.As(err, &de) || de.Path != "env[0]" {
+		t.Fatalf("DecodeSafe error = %v, want ErrDuplicateMapKey at env[0]", err)
+	}
+	if err := cbor.Unmarshal(b, &out); !errors.Is(err, cbor.ErrDuplicateMapKey) {
+		t.Fatalf("Unmarshal error = %v, want ErrDuplicateMapKey", err)
+	}
+
+	// DecodeTrusted keeps every entry.
+	out = Deployment{}
+	if _, err := out.DecodeTrusted(b); err != nil {
+		t.Fatalf("DecodeTrusted error: %v", err)
+	}
+	want := []EnvVar{{"A", "first"}, {"B", "b"}, {"A", "last"}}
+	if !reflect.DeepEqual(out.Env, want) {
+		t.Fatalf("DecodeTrusted Env = %+v, want %+v", out.Env, want)
+	}
+}
+
+func TestDeploymentAsMapCanonical(t *testing.T) {
+	defer func(v bool) { cbor.CanonicalMapEncode = v }(cbor.CanonicalMapEncode)
+	cbor.CanonicalMapEncode = true
+
+	in := sampleDeployment()
+	want := `{"env": {"HOME": "/root", "PATH": "/bin"}, ` +
+		`"listeners": {80: [], 443: ["a.example", "b.example"]}, ` +
+		`"steps": [{"name": "build", "value": "make"}]}`
+	if got := diagOf(t, &in); got != want {
+		t.Fatalf("encoding = %s\nwant %s", got, want)
+	}
+
+	in.Env = append(in.Env, EnvVar{Name: "PATH", Value: "/usr/bin"})
+	if _, err := in.MarshalCBOR(nil); !errors.Is(err, cbor.ErrDuplicateMapKey) {
+		t.Fatalf("MarshalCBOR error = %v, want ErrDuplicateMapKey", err)
+	}
+	if _, err := cbor.Marshal(in); !errors.Is(err, cbor.ErrDuplicateMapKey) {
+		t.Fatalf("Marshal error = %v, want ErrDuplicateMapKey", err)
+	}
+}
+
+func TestDeploymentAsMapIndefinite(t *testing.T) {
+	var b []byte
+	b = cbor.AppendMapHeader(b, 2)
+	b = cbor.AppendString(b, "env")
+	b = append(b, 0xbf)
+	b = cbor.AppendString(b, "A")
+	b = cbor.AppendString(b, "1")
+	b = cbor.AppendString(b, "B")
+	b = cbor.AppendString(b, "2")
+	b = append(b, 0xff)
+	b = cbor.AppendString(b, "steps")
+	b = cbor.AppendArrayHeader(b, 0)
+
+	want := []EnvVar{{"A", "1"}, {"B", "2"}}
+	var out Deployment
+	if _, err := out.DecodeSafe(b); err != nil {
+		t.Fatalf("DecodeSafe error: %v", err)
+	}
+	if !reflect.DeepEqual(out.Env, want) {
+		t.Fatalf("DecodeSafe Env = %+v, want %+v", out.Env, want)
+	}
+	out = Deployment{}
+	if err := cbor.Unmarshal(b, &out); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if !reflect.DeepEqual(out.Env, want) {
+		t.Fatalf("Unmarshal Env = %+v, want %+v", out.Env, want)
+	}
+}
+
+func TestDeploymentAsMapNil(t *testing.T) {
+	defer func() { cbor.NilContainers = cbor.NilAsEmpty }()
+	cbor.NilContainers = cbor.NilAsNull
+	in := Deployment{Steps: []EnvVar{}}
+	b, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	if got, want := diagOf(t, &in), `{"env": null, "steps": []}`; got != want {
+		t.Fatalf("encoding = %s, want %s", got, want)
+	}
+	out := Deployment{Env: []EnvVar{{"A", "1"}}}
+	if _, err := out.DecodeSafe(b); err != nil {
+		t.Fatalf("DecodeSafe error: %v", err)
+	}
+	if out.Env != nil {
+		t.Fatalf("Env = %+v, want nil", out.Env)
+	}
+}
+
+func TestDeploymentAsMapValueError(t *testing.T) {
+	var b []byte
+	b = cbor.AppendMapHeader(b, 1)
+	b = cbor.AppendString(b, "listeners")
+	b = cbor.AppendMapHeader(b, 2)
+	b = cbor.AppendUint16(b, 80)
+	b = cbor.AppendArrayHeader(b, 0)
+	b = cbor.AppendUint16(b, 443)
+	b = cbor.AppendInt(b, 1)
+
+	var out Deployment
+	var de *cbor.DecodeError
+	if _, err := out.DecodeSafe(b); !errors.As(err, &de) || de.Path != "listeners[1]" {
+		t.Fatalf("DecodeSafe error = %v, want a *DecodeError at listeners[1]", err)
+	}
+}
+
+func TestAsMapReflect(t *testing.T) {
+	type kv struct {
+		Key   int
+		Value bool
+	}
+	type doc struct {
+		Flags []kv `cbor:"flags,asmap"`
+	}
+	in := doc{Flags: []kv{{3, true}, {1, false}}}
+	b, err := cbor.Marshal(in)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	d, _, err := cbor.DiagBytes(b)
+	if err != nil {
+		t.Fatalf("DiagBytes error: %v", err)
+	}
+	if want := `{"flags": {3: true, 1: false}}`; d != want {
+		t.Fatalf("Marshal = %s, want %s", d, want)
+	}
+	var out doc
+	if err := cbor.Unmarshal(b, &out); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if !reflect.DeepEqual(out, in) {
+		t.Fatalf("Unmarshal = %+v, want %+v", out, in)
+	}
+
+	type bad struct {
+		Flags []struct{ A, B, C int } `cbor:"flags,asmap"`
+	}
+	if _, err := cbor.Marshal(bad{Flags: make([]struct{ A, B, C int }, 1)}); err == nil {
+		t.Fatal("Marshal of a three-field asmap element succeeded")
+	}
+}
@@ -0,0 +1,113 @@
+package structs
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+// appendBignum appends tag 2 or 3 around the given magnitude bytes, which
+// may carry leading zeros.
+func appendBignum(b []byte, neg bool, mag ...byte) []byte {
+	if neg {
+		b = cbor.AppendTag(b, 3)
+	} else {
+		b = cbor.AppendTag(b, 2)
+	}
+	return cbor.AppendBytes(b, mag)
+}
+
+func TestBignumIntoIntegerFields(t *testing.T) {
+	cases := []struct {
+		name string
+		in   []byte
+		want func(Scalars) bool
+	}{
+		{"i64 small", scalarsField("i64", func(b []byte) []byte { return appendBignum(b, false, 0x2a) }),
+			func(s Scalars) bool { return s.I64 == 42 }},
+		{"i64 leading zeros", scalarsField("i64", func(b []byte) []byte { return appendBignum(b, false, 0, 0, 0x01, 0x00) }),
+			func(s Scalars) bool { return s.I64 == 256 }},
+		{"i64 max", scalarsField("i64", func(b []byte) []byte {
+			return cbor.AppendBigInt(b, big.NewInt(math.MaxInt64))
+		}), func(s Scalars) bool { return s.I64 == math.MaxInt64 }},
+		{"i64 min", scalarsField("i64", func(b []byte) []byte {
+			return appendBignum(b, true, 0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff)
+		}), func(s Scalars) bool { return s.I64 == math.MinInt64 }},
+		{"i64 empty", scalarsField("i64", func(b []byte) []byte { return appendBignum(b, false) }),
+			func(s Scalars) bool { return s.I64 == 0 }},
+		{"i32 negative", scalarsField("i32", func(b []byte) []byte { return appendBignum(b, true, 0x09) }),
+			func(s Scalars) bool { return s.I32 == -10 }},
+		{"u64 max", scalarsField("u64", func(b []byte) []byte {
+			return appendBignum(b, false, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff)
+		}), func(s Scalars) bool { return s.U64 == math.MaxUint64 }},
+	}
+	for _, tc := range cases {
+		for _, dec := range scalarsDecoders {
+			var got Scalars
+			rest, err := dec.decode(&got, tc.in)
+			if err != nil || len(rest) != 0 || !tc.want(got) {
+				t.Fatalf("%s %s: got %+v, rest=%d, err=%v", tc.name, dec.name, got, len(rest), err)
+			}
+		}
+	}
+}
+
+func TestBignumOverflow(t *testing.T) {
+	nine := []byte{0x01, 0, 0, 0, 0, 0, 0, 0, 0} // 2^64
+	cases := []struct {
+		name, typ string
+		in        []byte
+	}{
+		{"i64 above max", "int64", scalarsField("i64", func(b []byte) []byte {
+			return appendBignum(b, false, 0x80, 0, 0, 0, 0, 0, 0, 0)
+		})},
+		{"i64 below min", "int64", scalarsField("i64", func(b []byte) []byte {
+			return appendBignum(b, true, 0x80, 0, 0, 0, 0, 0, 0, 0)
+		})},
+		{"i64 nine bytes", "int64", scalarsField("i64", func(b []byte) []byte { return appendBignum(b, false, nine...) })},
+		{"u64 nine bytes", "uint64", scalarsField("u64", func(b []byte) []byte { return appendBignum(b, false, nine...) })},
+		{"u64 negative", "uint64", scalarsField("u64", func(b []byte) []byte { return appendBignum(b, true, 0x00) })},
+	}
+	for _, tc := range cases {
+		for _, dec := range scalarsDecoders {
+			var got Scalars
+			_, err := dec.decode(&got, tc.in)
+			var ov cbor.BignumOverflow
+			if !errors.As(err, &ov) || ov.Type != tc.typ {
+				t.Fatalf("%s %s: error = %v, want BignumOverflow for %s", tc.name, dec.name, err, tc.typ)
+			}
+		}
+	}
+
+	// Smaller fields report the usual overflow once the bignum is read.
+	in := scalarsField("i8", func(b []byte) []byte { return appendBignum(b, false, 0x01, 0x00) })
+	var got Scalars
+	var ov cbor.IntOverflow
+	if _, err := got.DecodeSafe(in); !errors.As(err, &ov) || ov.FailedBitsize != 8 {
+		t.Fatalf("i8: error = %v, want IntOverflow", err)
+	}
+
+	// Other tags are still rejected.
+	in = scalarsField("i64", func(b []byte) []byte { return cbor.AppendInt(cbor.AppendTag(b, 1), 5) })
+	if _, err := got.DecodeSafe(in); err == nil {
+		t.Fatal("tag 1 decoded into an int64 field")
+	}
+}
+
+func TestBignumUnmarshalReflect(t *testing.T) {
+	var v struct {
+		N int64  `cbor:"n"`
+		U uint16 `cbor:"u"`
+	}
+	b := cbor.AppendMapHeader(nil, 2)
+	b = cbor.AppendString(b, "n")
+	b = appendBignum(b, true, 0x03, 0xe7)
+	b = cbor.AppendString(b, "u")
+	b = appendBignum(b, false, 0x10)
+	if err := cbor.Unmarshal(b, &v); err != nil || v.N != -1000 || v.U != 16 {
+		t.Fatalf("Unmarshal = %+v, %v", v, err)
+	}
+}
@@ -0,0 +1,22 @@
+package structs
+
+// Shelf and Bin exercise the deep copies of --clone.
+type Bin struct {
+	Label string   `cbor:"label"`
+	Items []string `cbor:"items"`
+}
+
+// Bins is a named slice; it gets a Clone method of its own.
+type Bins []Bin
+
+// Shelf holds every kind of reference Clone has to duplicate.
+type Shelf struct {
+	Name    string           `cbor:"name"`
+	Bins    Bins             `cbor:"bins"`
+	Spare   *Bin             `cbor:"spare"`
+	Counts  map[string][]int `cbor:"counts"`
+	Limit   *int             `cbor:"limit"`
+	Grid    [2][]byte        `cbor:"grid"`
+	ByLabel map[string]*Bin  `cbor:"by_label"`
+	note    []string
+}
@@ -0,0 +1,56 @@
+package structs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func newShelf() *Shelf {
+	limit := 10
+	spare := &Bin{Label: "spare", Items: []string{"fuse"}}
+	return &Shelf{
+		Name:    "A",
+		Bins:    Bins{{Label: "b1", Items: []string{"bolt", "nut"}}},
+		Spare:   spare,
+		Counts:  map[string][]int{"bolt": {1, 2}},
+		Limit:   &limit,
+		Grid:    [2][]byte{{1}, {2, 3}},
+		ByLabel: map[string]*Bin{"spare": spare},
+		note:    []string{"checked"},
+	}
+}
+
+func TestCloneIsDeep(t *testing.T) {
+	orig := newShelf()
+	cp := orig.Clone()
+	if !reflect.DeepEqual(cp, orig) {
+		t.Fatalf("clone differs: got %+v, want %+v", cp, orig)
+	}
+
+	// Mutate everything reachable from the clone; the original must not
+	// see any of it.
+	cp.Bins[0].Items[0] = "screw"
+	cp.Bins = append(cp.Bins, Bin{Label: "b2"})
+	cp.Spare.Items[0] = "wire"
+	cp.Counts["bolt"][0] = 99
+	cp.Counts["nut"] = nil
+	*cp.Limit = 20
+	cp.Grid[1][0] = 9
+	cp.ByLabel["spare"].Label = "changed"
+	cp.note[0] = "dirty"
+
+	if !reflect.DeepEqual(orig, newShelf()) {
+		t.Fatalf("mutating the clone changed the original: %+v", orig)
+	}
+}
+
+func TestCloneNil(t *testing.T) {
+	var s *Shelf
+	if s.Clone() != nil {
+		t.Fatalf("Clone of nil *Shelf is not nil")
+	}
+	empty := (&Shelf{}).Clone()
+	if empty.Bins != nil || empty.Counts != nil || empty.Spare != nil {
+		t.Fatalf("Clone of zero Shelf allocated references: %+v", empty)
+	}
+}
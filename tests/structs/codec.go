@@ -0,0 +1,11 @@
+package structs
+
+import "math/big"
+
+// Quote holds fields of a type from another package that has no CBOR
+// methods; tests register a codec for big.Rat with cbor.RegisterCodec.
+type Quote struct {
+	Symbol string   `cbor:"symbol"`
+	Price  big.Rat  `cbor:"price"`
+	Limit  *big.Rat `cbor:"limit,omitempty"`
+}
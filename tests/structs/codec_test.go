@@ -0,0 +1,109 @@
+package structs
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+// big.Rat is written as its text, e.g. "3/2".
+var ratCodecErr = cbor.RegisterCodec(reflect.TypeFor[big.Rat](),
+	func(b []byte, v any) ([]byte, error) {
+		r := v.(big.Rat)
+		return cbor.AppendString(b, r.RatString()), nil
+	},
+	func(b []byte, v any) ([]byte, error) {
+		s, o, err := cbor.ReadStringBytes(b)
+		if err != nil {
+			return b, err
+		}
+		if _, ok := v.(*big.Rat).SetString(s); !ok {
+			return b, errors.New("invalid rational " + s)
+		}
+		return o, nil
+	})
+
+func TestQuoteUsesRegisteredCodec(t *testing.T) {
+	if ratCodecErr != nil {
+		t.Fatal(ratCodecErr)
+	}
+	in := Quote{Symbol: "ACME", Limit: big.NewRat(7, 4)}
+	in.Price.SetFrac64(3, 2)
+	b, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	want := cbor.AppendMapHeader(nil, 3)
+	want = cbor.AppendString(cbor.AppendString(want, "symbol"), "ACME")
+	want = cbor.AppendString(cbor.AppendString(want, "price"), "3/2")
+	want = cbor.AppendString(cbor.AppendString(want, "limit"), "7/4")
+	if string(b) != string(want) {
+		t.Fatalf("MarshalCBOR = %x, want %x", b, want)
+	}
+
+	decoders := map[string]func(*Quote, []byte) ([]byte, error){
+		"DecodeSafe":    (*Quote).DecodeSafe,
+		"DecodeTrusted": (*Quote).DecodeTrusted,
+		"Unmarshal": func(q *Quote, b []byte) ([]byte, error) {
+			// Reflection, through a struct without generated methods.
+			var r struct {
+				Price big.Rat  `cbor:"price"`
+				Limit *big.Rat `cbor:"limit"`
+			}
+			err := cbor.Unmarshal(b, &r)
+			q.Price, q.Limit = r.Price, r.Limit
+			return nil, err
+		},
+	}
+	for name, decode := range decoders {
+		var out Quote
+		if _, err := decode(&out, b); err != nil {
+			t.Fatalf("%s error: %v", name, err)
+		}
+		if out.Price.Cmp(&in.Price) != 0 || out.Limit == nil || out.Limit.Cmp(in.Limit) != 0 {
+			t.Errorf("%s: price %v, limit %v", name, &out.Price, out.Limit)
+		}
+	}
+
+	rb, err := cbor.Marshal(map[string]*big.Rat{"limit": nil})
+	if err != nil || string(rb) != "\xa1\x65limit\xf6" {
+		t.Fatalf("Marshal of a nil *big.Rat = %x, %v; want null", rb, err)
+	}
+}
+
+var codecRuns atomic.Int32
+
+func TestRegisterCodecOnce(t *testing.T) {
+	// Distinct array types let each run register afresh.
+	typ := reflect.ArrayOf(int(codecRuns.Add(1)), reflect.TypeFor[big.Rat]())
+	enc := func(b []byte, v any) ([]byte, error) { return cbor.AppendNil(b), nil }
+	dec := func(b []byte, v any) ([]byte, error) { return cbor.Skip(b) }
+	var wins atomic.Int32
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Go(func() {
+			err := cbor.RegisterCodec(typ, enc, dec)
+			switch {
+			case err == nil:
+				wins.Add(1)
+			case !errors.Is(err, cbor.ErrDuplicateCodec):
+				t.Errorf("RegisterCodec error: %v", err)
+			}
+		})
+	}
+	wg.Wait()
+	if wins.Load() != 1 {
+		t.Fatalf("%d registrations succeeded, want 1", wins.Load())
+	}
+	if err := cbor.RegisterCodec(reflect.TypeFor[any](), enc, dec); err == nil {
+		t.Fatal("RegisterCodec accepted an interface type")
+	}
+	if err := cbor.RegisterCodec(reflect.TypeFor[Quote](), enc, nil); err == nil {
+		t.Fatal("RegisterCodec accepted a nil decode function")
+	}
+}
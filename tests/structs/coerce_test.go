@@ -0,0 +1,117 @@
+package structs
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+// scalarsField encodes a Scalars map holding only the given field.
+func scalarsField(key string, appendValue func([]byte) []byte) []byte {
+	b := cbor.AppendMapHeader(nil, 1)
+	b = cbor.AppendString(b, key)
+	return appendValue(b)
+}
+
+func TestCoerceNumbersStrict(t *testing.T) {
+	setOption(t, &cbor.CoerceNumbers, false)
+	cases := map[string][]byte{
+		"float into int": scalarsField("i", func(b []byte) []byte { return cbor.AppendFloat64(b, 3) }),
+		"float into u8":  scalarsField("u8", func(b []byte) []byte { return cbor.AppendFloat32(b, 3) }),
+		"int into f64":   scalarsField("f64", func(b []byte) []byte { return cbor.AppendInt(b, 3) }),
+	}
+	for name, b := range cases {
+		for _, tc := range scalarsDecoders {
+			var dst Scalars
+			if _, err := tc.decode(&dst, b); err == nil {
+				t.Fatalf("%s %s: decoded without error: %+v", name, tc.name, dst)
+			}
+		}
+	}
+}
+
+func TestCoerceNumbersLenient(t *testing.T) {
+	setOption(t, &cbor.CoerceNumbers, true)
+	var b []byte
+	b = cbor.AppendMapHeader(b, 6)
+	b = cbor.AppendString(b, "i")
+	b = cbor.AppendFloat64(b, -42)
+	b = cbor.AppendString(b, "i8")
+	b = cbor.AppendFloat16(b, 100)
+	b = cbor.AppendString(b, "u64")
+	b = cbor.AppendFloat64(b, 1<<63)
+	b = cbor.AppendString(b, "u16")
+	b = cbor.AppendFloat32(b, 65535)
+	b = cbor.AppendString(b, "f64")
+	b = cbor.AppendInt64(b, -1<<53)
+	b = cbor.AppendString(b, "f32")
+	b = cbor.AppendUint64(b, 7)
+	for _, tc := range scalarsDecoders {
+		var dst Scalars
+		rest, err := tc.decode(&dst, b)
+		if err != nil || len(rest) != 0 {
+			t.Fatalf("%s: err=%v rest=%d", tc.name, err, len(rest))
+		}
+		if dst.I != -42 || dst.I8 != 100 || dst.U64 != 1<<63 || dst.U16 != 65535 || dst.F64 != -1<<53 || dst.F32 != 7 {
+			t.Fatalf("%s: got %+v", tc.name, dst)
+		}
+	}
+}
+
+func TestCoerceNumbersRejectsInexact(t *testing.T) {
+	setOption(t, &cbor.CoerceNumbers, true)
+	float := func(f float64) func([]byte) []byte {
+		return func(b []byte) []byte { return cbor.AppendFloat64(b, f) }
+	}
+	cases := map[string][]byte{
+		"fraction":      scalarsField("i", float(2.5)),
+		"NaN":           scalarsField("i64", float(math.NaN())),
+		"Inf":           scalarsField("i64", float(math.Inf(1))),
+		"int64 range":   scalarsField("i64", float(1<<63)),
+		"negative uint": scalarsField("u", float(-1)),
+		"uint64 range":  scalarsField("u64", float(1<<64)),
+		"int8 overflow": scalarsField("i8", float(128)),
+		"uint8 range":   scalarsField("u8", float(256)),
+		"not a number":  scalarsField("i", func(b []byte) []byte { return cbor.AppendBool(b, true) }),
+		"neg into uint": scalarsField("u32", func(b []byte) []byte { return cbor.AppendInt(b, -1) }),
+	}
+	for name, b := range cases {
+		for _, tc := range scalarsDecoders {
+			var dst Scalars
+			if _, err := tc.decode(&dst, b); err == nil {
+				t.Fatalf("%s %s: decoded without error: %+v", name, tc.name, dst)
+			}
+		}
+	}
+
+	var fe cbor.FloatConversionError
+	_, _, err := cbor.ReadInt64Bytes(cbor.AppendFloat64(nil, 2.5))
+	if !errors.As(err, &fe) || fe.Value != 2.5 || fe.Type != "int64" {
+		t.Fatalf("ReadInt64Bytes(2.5) error = %v, want FloatConversionError", err)
+	}
+	var ov cbor.IntOverflow
+	_, _, err = cbor.ReadInt8Bytes(cbor.AppendFloat64(nil, 128))
+	if !errors.As(err, &ov) || ov.Value != 128 || ov.FailedBitsize != 8 {
+		t.Fatalf("ReadInt8Bytes(128.0) error = %v, want IntOverflow", err)
+	}
+}
+
+func TestCoerceNumbersReflect(t *testing.T) {
+	setOption(t, &cbor.CoerceNumbers, true)
+	type plain struct {
+		N int32   `cbor:"n"`
+		F float64 `cbor:"f"`
+	}
+	var b []byte
+	b = cbor.AppendMapHeader(b, 2)
+	b = cbor.AppendString(b, "n")
+	b = cbor.AppendFloat64(b, 12)
+	b = cbor.AppendString(b, "f")
+	b = cbor.AppendInt(b, -3)
+	var out plain
+	if err := cbor.Unmarshal(b, &out); err != nil || out.N != 12 || out.F != -3 {
+		t.Fatalf("Unmarshal = %+v, %v", out, err)
+	}
+}
@@ -0,0 +1,9 @@
+package structs
+
+// Contact is generated with --compat so ContactCompat satisfies the
+// fxamacker/cbor Marshaler and Unmarshaler interfaces.
+type Contact struct {
+	Name  string   `cbor:"name"`
+	Email string   `cbor:"email,omitempty"`
+	Tags  []string `cbor:"tags"`
+}
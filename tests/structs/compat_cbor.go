@@ -0,0 +1,328 @@
+// Code generated by cborgen DO NOT EDIT.
+
+package structs
+
+import (
+	"io"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+// Fail the build if a generated method drifts from the runtime interfaces.
+var (
+	_ cbor.Marshaler   = (*Contact)(nil)
+	_ cbor.Unmarshaler = (*Contact)(nil)
+)
+
+func (x Contact) Msgsize() (s int) {
+	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("name") + cbor.StringPrefixSize + len(x.Name) + cbor.StringPrefixSize + len("email") + cbor.StringPrefixSize + len(x.Email) + cbor.StringPrefixSize + len("tags") + cbor.ArrayHeaderSize + len(x.Tags)*cbor.StringPrefixSize
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *Contact) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *Contact) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *Contact) AppendCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	count := uint32(0)
+	count++
+	if !(x.Email == "") {
+		count++
+	}
+	count++
+	b = cbor.AppendMapHeader(b, count)
+	var err error
+	b = cbor.AppendString(b, "name")
+	b, err = cbor.AppendString(b, x.Name), nil
+	if err != nil {
+		return b, err
+	}
+	if !(x.Email == "") {
+		b = cbor.AppendString(b, "email")
+		b, err = cbor.AppendString(b, x.Email), nil
+		if err != nil {
+			return b, err
+		}
+	}
+	if x.Tags == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = cbor.AppendString(b, "tags")
+		b = cbor.AppendNil(b)
+	} else {
+		b = cbor.AppendString(b, "tags")
+		b = cbor.AppendArrayHeader(b, uint32(len(x.Tags)))
+		for _, v := range x.Tags {
+			b = cbor.AppendString(b, v)
+		}
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *Contact) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *Contact) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := in.ReadStringBytes(rest)
+		if err != nil {
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+		}
+		switch key {
+		case "name":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
+				}
+			}
+
+			var tmp string
+			tmp, v, err = in.ReadStringBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
+			}
+			x.Name = tmp
+		case "email":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "email", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "email", len(b)-len(v))
+				}
+			}
+
+			var tmp string
+			tmp, v, err = in.ReadStringBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "email", len(b)-len(v))
+			}
+			x.Email = tmp
+		case "tags":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 2); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "tags", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "tags", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Tags = cbor.NullSlice(x.Tags)
+				break
+			}
+			var sz uint32
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "tags", len(b)-len(v))
+			}
+			if cap(x.Tags) >= int(sz) {
+				x.Tags = x.Tags[:sz]
+			} else {
+				x.Tags = make([]string, sz)
+			}
+			if sz > 0 {
+				_ = x.Tags[sz-1]
+			}
+			for iTags := uint32(0); iTags < sz; iTags++ {
+				var tmp string
+				tmp, v, err = in.ReadStringBytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, int(iTags)), "tags", len(b)-len(v))
+				}
+				x.Tags[iTags] = tmp
+			}
+			if indef {
+				v = v[1:] // break
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *Contact) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "name":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			x.Name, v, err = cbor.ReadTrustedStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+		case "email":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			x.Email, v, err = cbor.ReadTrustedStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+		case "tags":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Tags = cbor.NullSlice(x.Tags)
+				break
+			}
+			var sz uint32
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap(x.Tags) >= int(sz) {
+				x.Tags = x.Tags[:sz]
+			} else {
+				x.Tags = make([]string, sz)
+			}
+			if sz > 0 {
+				_ = x.Tags[sz-1]
+			}
+			for iTags := uint32(0); iTags < sz; iTags++ {
+				var tmp string
+				tmp, v, err = cbor.ReadTrustedStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Tags[iTags] = tmp
+			}
+			if indef {
+				v = v[1:] // break
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *Contact) resetCBOR() {
+	var zero Contact
+	x.Name = zero.Name
+	x.Email = zero.Email
+	x.Tags = x.Tags[:0]
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Contact) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+// ContactCompat adapts Contact to the fxamacker/cbor Marshaler and
+// Unmarshaler interfaces; convert with (*ContactCompat)(&v).
+type ContactCompat Contact
+
+// MarshalCBOR encodes x into a new buffer.
+func (x *ContactCompat) MarshalCBOR() ([]byte, error) {
+	return (*Contact)(x).MarshalCBOR(nil)
+}
+
+// UnmarshalCBOR decodes b into x using the Safe path. Bytes after the
+// item fail with cbor.ErrTrailingBytes unless cbor.AllowTrailingBytes is
+// set.
+func (x *ContactCompat) UnmarshalCBOR(b []byte) error {
+	o, err := (*Contact)(x).DecodeSafe(b)
+	if err != nil {
+		return err
+	}
+	return cbor.CheckTrailingBytes(b, o)
+}
@@ -0,0 +1,88 @@
+package structs
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+	fxcbor "github.com/fxamacker/cbor/v2"
+)
+
+// Compile-time checks that the adapter satisfies fxamacker's interfaces.
+var (
+	_ fxcbor.Marshaler   = (*ContactCompat)(nil)
+	_ fxcbor.Unmarshaler = (*ContactCompat)(nil)
+)
+
+func TestContactCompatWithFxamacker(t *testing.T) {
+	src := Contact{Name: "Ada", Email: "ada@example.com", Tags: []string{"a", "b"}}
+
+	b, err := fxcbor.Marshal((*ContactCompat)(&src))
+	if err != nil {
+		t.Fatalf("fxcbor.Marshal error: %v", err)
+	}
+	fast, err := src.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	if string(b) != string(fast) {
+		t.Fatalf("adapter encoding differs:\n got %x\nwant %x", b, fast)
+	}
+
+	var dst Contact
+	if err := fxcbor.Unmarshal(b, (*ContactCompat)(&dst)); err != nil {
+		t.Fatalf("fxcbor.Unmarshal error: %v", err)
+	}
+	if !reflect.DeepEqual(src, dst) {
+		t.Fatalf("round trip mismatch:\n got %#v\nwant %#v", dst, src)
+	}
+
+	// Adapters also work as struct fields inside fxamacker-encoded values.
+	type wrapper struct {
+		C ContactCompat `cbor:"c"`
+	}
+	wb, err := fxcbor.Marshal(wrapper{C: ContactCompat(src)})
+	if err != nil {
+		t.Fatalf("fxcbor.Marshal wrapper error: %v", err)
+	}
+	var w wrapper
+	if err := fxcbor.Unmarshal(wb, &w); err != nil {
+		t.Fatalf("fxcbor.Unmarshal wrapper error: %v", err)
+	}
+	if !reflect.DeepEqual(src, Contact(w.C)) {
+		t.Fatalf("wrapper round trip mismatch: %#v", w.C)
+	}
+}
+
+func TestContactCompatUnmarshalError(t *testing.T) {
+	var dst ContactCompat
+	if err := dst.UnmarshalCBOR([]byte{0x01}); err == nil {
+		t.Fatalf("expected error decoding an integer into Contact")
+	}
+}
+
+func TestContactCompatTrailingBytes(t *testing.T) {
+	src := Contact{Name: "Ada"}
+	b, err := src.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	b = append(b, 0x00)
+
+	var dst ContactCompat
+	err = dst.UnmarshalCBOR(b)
+	if !errors.Is(err, cbor.ErrTrailingBytes) {
+		t.Fatalf("error = %v, want ErrTrailingBytes", err)
+	}
+	var de *cbor.DecodeError
+	if !errors.As(err, &de) || de.Offset != len(b)-1 {
+		t.Fatalf("error does not locate the trailing byte at %d: %v", len(b)-1, de)
+	}
+
+	cbor.AllowTrailingBytes = true
+	defer func() { cbor.AllowTrailingBytes = false }()
+	if err := dst.UnmarshalCBOR(b); err != nil || dst.Name != "Ada" {
+		t.Fatalf("with AllowTrailingBytes: %+v, %v", dst, err)
+	}
+}
@@ -0,0 +1,10 @@
+package structs
+
+// Phasor exercises complex64/complex128 fields, encoded as [real, imag]
+// float arrays.
+type Phasor struct {
+	Label string     `cbor:"label"`
+	Z     complex128 `cbor:"z"`
+	Z64   complex64  `cbor:"z64"`
+	Bias  complex128 `cbor:"bias,omitempty"`
+}
@@ -0,0 +1,269 @@
+// Code generated by cborgen DO NOT EDIT.
+
+package structs
+
+import (
+	"io"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+// Fail the build if a generated method drifts from the runtime interfaces.
+var (
+	_ cbor.Marshaler   = (*Phasor)(nil)
+	_ cbor.Unmarshaler = (*Phasor)(nil)
+)
+
+func (x Phasor) Msgsize() (s int) {
+	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("label") + cbor.StringPrefixSize + len(x.Label) + cbor.StringPrefixSize + len("z") + cbor.Complex128Size + cbor.StringPrefixSize + len("z64") + cbor.Complex64Size + cbor.StringPrefixSize + len("bias") + cbor.Complex128Size
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *Phasor) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *Phasor) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *Phasor) AppendCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	count := uint32(0)
+	count++
+	count++
+	count++
+	if !(x.Bias == 0) {
+		count++
+	}
+	b = cbor.AppendMapHeader(b, count)
+	var err error
+	b = cbor.AppendString(b, "label")
+	b, err = cbor.AppendString(b, x.Label), nil
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "z")
+	b, err = cbor.AppendComplex128(b, x.Z), nil
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "z64")
+	b, err = cbor.AppendComplex64(b, x.Z64), nil
+	if err != nil {
+		return b, err
+	}
+	if !(x.Bias == 0) {
+		b = cbor.AppendString(b, "bias")
+		b, err = cbor.AppendComplex128(b, x.Bias), nil
+		if err != nil {
+			return b, err
+		}
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *Phasor) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *Phasor) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := in.ReadStringBytes(rest)
+		if err != nil {
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+		}
+		switch key {
+		case "label":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "label", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "label", len(b)-len(v))
+				}
+			}
+
+			var tmp string
+			tmp, v, err = in.ReadStringBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "label", len(b)-len(v))
+			}
+			x.Label = tmp
+		case "z":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "z", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+
+			var tmp complex128
+			tmp, v, err = cbor.ReadComplex128Bytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "z", len(b)-len(v))
+			}
+			x.Z = tmp
+		case "z64":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 2); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "z64", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+
+			var tmp complex64
+			tmp, v, err = cbor.ReadComplex64Bytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "z64", len(b)-len(v))
+			}
+			x.Z64 = tmp
+		case "bias":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 3); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "bias", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+
+			var tmp complex128
+			tmp, v, err = cbor.ReadComplex128Bytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "bias", len(b)-len(v))
+			}
+			x.Bias = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *Phasor) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "label":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			x.Label, v, err = cbor.ReadTrustedStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+		case "z":
+
+			var tmp complex128
+			tmp, v, err = cbor.ReadComplex128Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Z = tmp
+		case "z64":
+
+			var tmp complex64
+			tmp, v, err = cbor.ReadComplex64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Z64 = tmp
+		case "bias":
+
+			var tmp complex128
+			tmp, v, err = cbor.ReadComplex128Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Bias = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *Phasor) resetCBOR() {
+	var zero Phasor
+	x.Label = zero.Label
+	x.Z = zero.Z
+	x.Z64 = zero.Z64
+	x.Bias = zero.Bias
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Phasor) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
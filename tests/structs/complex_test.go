@@ -0,0 +1,113 @@
+package structs
+
+import (
+	"bytes"
+	"math"
+	"math/cmplx"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+func TestPhasorRoundTrip(t *testing.T) {
+	cases := []Phasor{
+		{Label: "zero"},
+		{Label: "unit", Z: complex(1, -1), Z64: complex(0.5, 2), Bias: 3i},
+		{Label: "inf", Z: cmplx.Inf(), Z64: complex(float32(math.Inf(-1)), 0)},
+	}
+
+	decoders := []struct {
+		name string
+		fn   func(*Phasor, []byte) ([]byte, error)
+	}{
+		{name: "DecodeSafe", fn: (*Phasor).DecodeSafe},
+		{name: "DecodeTrusted", fn: (*Phasor).DecodeTrusted},
+	}
+
+	for _, src := range cases {
+		b, err := src.MarshalCBOR(nil)
+		if err != nil {
+			t.Fatalf("%s: MarshalCBOR error: %v", src.Label, err)
+		}
+		for _, dec := range decoders {
+			var dst Phasor
+			rest, err := dec.fn(&dst, b)
+			if err != nil {
+				t.Fatalf("%s: %s error: %v", src.Label, dec.name, err)
+			}
+			if len(rest) != 0 {
+				t.Fatalf("%s: %s left %d bytes", src.Label, dec.name, len(rest))
+			}
+			if dst != src {
+				t.Fatalf("%s: %s mismatch:\n got %#v\nwant %#v", src.Label, dec.name, dst, src)
+			}
+		}
+	}
+}
+
+func TestPhasorNaNComponents(t *testing.T) {
+	src := Phasor{Z: complex(math.NaN(), 1)}
+	b, err := src.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	var dst Phasor
+	if _, err := dst.DecodeSafe(b); err != nil {
+		t.Fatalf("DecodeSafe error: %v", err)
+	}
+	if !math.IsNaN(real(dst.Z)) || imag(dst.Z) != 1 {
+		t.Fatalf("expected (NaN+1i), got %v", dst.Z)
+	}
+}
+
+func TestComplexWireFormat(t *testing.T) {
+	want := cbor.AppendArrayHeader(nil, 2)
+	want = cbor.AppendFloat64(want, 1.5)
+	want = cbor.AppendFloat64(want, -2)
+	if got := cbor.AppendComplex128(nil, complex(1.5, -2)); !bytes.Equal(got, want) {
+		t.Fatalf("AppendComplex128 = %x, want %x", got, want)
+	}
+
+	// Components of any float width are accepted.
+	mixed := cbor.AppendArrayHeader(nil, 2)
+	mixed = append(mixed, 0xf9, 0x3e, 0x00) // 1.5 as float16
+	mixed = cbor.AppendFloat32(mixed, -2)
+	c, rest, err := cbor.ReadComplex128Bytes(mixed)
+	if err != nil || len(rest) != 0 || c != complex(1.5, -2) {
+		t.Fatalf("ReadComplex128Bytes = %v, %d, %v", c, len(rest), err)
+	}
+
+	// Wrong arity and unexpected tags are rejected.
+	three := cbor.AppendArrayHeader(nil, 3)
+	three = cbor.AppendFloat64(three, 1)
+	three = cbor.AppendFloat64(three, 2)
+	three = cbor.AppendFloat64(three, 3)
+	if _, _, err := cbor.ReadComplex128Bytes(three); err == nil {
+		t.Fatalf("expected error for 3-element array")
+	}
+	if _, _, err := cbor.ReadComplex128Bytes(cbor.AppendTag(nil, 43000)); err == nil {
+		t.Fatalf("expected error for unexpected tag")
+	}
+}
+
+func TestComplexTag(t *testing.T) {
+	cbor.ComplexTag = 43000
+	defer func() { cbor.ComplexTag = 0 }()
+
+	src := Phasor{Label: "tagged", Z: complex(1, 2), Z64: complex(3, 4)}
+	b, err := src.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	tagged := cbor.AppendTag(nil, 43000)
+	if !bytes.Contains(b, tagged) {
+		t.Fatalf("expected tag 43000 in %x", b)
+	}
+	var dst Phasor
+	if _, err := dst.DecodeSafe(b); err != nil {
+		t.Fatalf("DecodeSafe error: %v", err)
+	}
+	if dst != src {
+		t.Fatalf("mismatch:\n got %#v\nwant %#v", dst, src)
+	}
+}
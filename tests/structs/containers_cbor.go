@@ -2,14 +2,39 @@
 
 package structs
 
-import cbor "github.com/delaneyj/cbor/runtime"
+import (
+	"io"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+// Fail the build if a generated method drifts from the runtime interfaces.
+var (
+	_ cbor.Marshaler   = (*Containers)(nil)
+	_ cbor.Unmarshaler = (*Containers)(nil)
+)
 
 func (x Containers) Msgsize() (s int) {
 	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("items") + cbor.ArrayHeaderSize + len(x.Items)*0 + cbor.StringPrefixSize + len("map") + cbor.MapHeaderSize + len(x.Map)*(cbor.StringPrefixSize+0)
 	return
 }
 
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
 func (x *Containers) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *Containers) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *Containers) AppendCBOR(b []byte) ([]byte, error) {
 	if x == nil {
 		return cbor.AppendNil(b), nil
 	}
@@ -18,77 +43,146 @@ func (x *Containers) MarshalCBOR(b []byte) ([]byte, error) {
 
 	b = cbor.AppendMapHeader(b, 4)
 	var err error
-
-	b = cbor.AppendString(b, "items")
-	b = cbor.AppendArrayHeader(b, uint32(len(x.Items)))
-	for i := range x.Items {
-		b, err = x.Items[i].MarshalCBOR(b)
-		if err != nil {
-			return b, err
-		}
-	}
-
-	b = cbor.AppendString(b, "ptrs")
-	b = cbor.AppendArrayHeader(b, uint32(len(x.Ptrs)))
-	for _, s := range x.Ptrs {
-		if s == nil {
-			b = cbor.AppendNil(b)
-		} else {
-			b, err = s.MarshalCBOR(b)
+	if x.Items == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = cbor.AppendString(b, "items")
+		b = cbor.AppendNil(b)
+	} else {
+		b = cbor.AppendString(b, "items")
+		b = cbor.AppendArrayHeader(b, uint32(len(x.Items)))
+		for i := range x.Items {
+			b, err = x.Items[i].MarshalCBOR(b)
 			if err != nil {
 				return b, err
 			}
 		}
 	}
-
-	b = cbor.AppendString(b, "map")
-	b = cbor.AppendMapHeader(b, uint32(len(x.Map)))
-	for k, v := range x.Map {
-		b = cbor.AppendString(b, k)
-		b, err = v.MarshalCBOR(b)
-		if err != nil {
-			return b, err
+	if x.Ptrs == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = cbor.AppendString(b, "ptrs")
+		b = cbor.AppendNil(b)
+	} else {
+		b = cbor.AppendString(b, "ptrs")
+		b = cbor.AppendArrayHeader(b, uint32(len(x.Ptrs)))
+		for _, s := range x.Ptrs {
+			if s == nil {
+				b = cbor.AppendNil(b)
+			} else {
+				b, err = s.MarshalCBOR(b)
+				if err != nil {
+					return b, err
+				}
+			}
 		}
 	}
-
-	b = cbor.AppendString(b, "ptr_map")
-	b = cbor.AppendMapHeader(b, uint32(len(x.PtrMap)))
-	for k, v := range x.PtrMap {
-		b = cbor.AppendString(b, k)
-		if v == nil {
-			b = cbor.AppendNil(b)
+	if x.Map == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = cbor.AppendString(b, "map")
+		b = cbor.AppendNil(b)
+	} else {
+		b = cbor.AppendString(b, "map")
+		if cbor.CanonicalMapEncode {
+			b, err = cbor.AppendMapDeterministic(b, x.Map, cbor.EncKeyString, func(b []byte, v Scalars) ([]byte, error) { return v.MarshalCBOR(b) })
+			if err != nil {
+				return b, err
+			}
 		} else {
-			b, err = v.MarshalCBOR(b)
+			b = cbor.AppendMapHeader(b, uint32(len(x.Map)))
+			for k, v := range x.Map {
+				b = cbor.AppendString(b, k)
+				b, err = v.MarshalCBOR(b)
+				if err != nil {
+					return b, err
+				}
+			}
+		}
+	}
+	if x.PtrMap == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = cbor.AppendString(b, "ptr_map")
+		b = cbor.AppendNil(b)
+	} else {
+		b = cbor.AppendString(b, "ptr_map")
+		if cbor.CanonicalMapEncode {
+			b, err = cbor.AppendMapDeterministic(b, x.PtrMap, cbor.EncKeyString, func(b []byte, v *Scalars) ([]byte, error) {
+				if v == nil {
+					return cbor.AppendNil(b), nil
+				}
+				return v.MarshalCBOR(b)
+			})
 			if err != nil {
 				return b, err
 			}
+		} else {
+			b = cbor.AppendMapHeader(b, uint32(len(x.PtrMap)))
+			for k, v := range x.PtrMap {
+				b = cbor.AppendString(b, k)
+				if v == nil {
+					b = cbor.AppendNil(b)
+				} else {
+					b, err = v.MarshalCBOR(b)
+					if err != nil {
+						return b, err
+					}
+				}
+			}
 		}
 	}
 
 	return b, nil
 }
 
-// DecodeSafe decodes using validated, allocating string handling.
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
 func (x *Containers) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *Containers) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
 	if x == nil {
 		return b, cbor.ErrNotNil
 	}
 	sz, rest, err := cbor.ReadMapHeaderBytes(b)
 	if err != nil {
-		return b, err
+		return b, cbor.WrapDecodeError(err, "", 0)
 	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
 	for i := uint32(0); i < sz; i++ {
-		key, v, err := cbor.ReadStringBytes(rest)
+		key, v, err := in.ReadStringBytes(rest)
 		if err != nil {
-			return b, err
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
 		}
 		switch key {
 		case "items":
-
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "items", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "items", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Items = cbor.NullSlice(x.Items)
+				break
+			}
 			var sz uint32
-			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "items", len(b)-len(v))
 			}
 			if cap(x.Items) >= int(sz) {
 				x.Items = x.Items[:sz]
@@ -102,16 +196,36 @@ func (x *Containers) DecodeSafe(b []byte) ([]byte, error) {
 				var tmp Scalars
 				v, err = (&tmp).UnmarshalCBOR(v)
 				if err != nil {
-					return b, err
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, int(iItems)), "items", len(b)-len(v))
 				}
 				x.Items[iItems] = tmp
 			}
+			if indef {
+				v = v[1:] // break
+			}
 		case "ptrs":
-
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "ptrs", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "ptrs", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Ptrs = cbor.NullSlice(x.Ptrs)
+				break
+			}
 			var sz uint32
-			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "ptrs", len(b)-len(v))
 			}
 			if cap(x.Ptrs) >= int(sz) {
 				x.Ptrs = x.Ptrs[:sz]
@@ -122,20 +236,44 @@ func (x *Containers) DecodeSafe(b []byte) ([]byte, error) {
 				_ = x.Ptrs[sz-1]
 			}
 			for iPtrs := uint32(0); iPtrs < sz; iPtrs++ {
+				if cbor.IsNilOrUndefined(v) {
+					v = v[1:]
+					x.Ptrs[iPtrs] = nil
+					continue
+				}
 				if x.Ptrs[iPtrs] == nil {
 					x.Ptrs[iPtrs] = new(Scalars)
 				}
 				v, err = x.Ptrs[iPtrs].UnmarshalCBOR(v)
 				if err != nil {
-					return b, err
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, int(iPtrs)), "ptrs", len(b)-len(v))
 				}
 			}
+			if indef {
+				v = v[1:] // break
+			}
 		case "map":
-
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 2); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "map", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "map", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Map = cbor.NullMap(x.Map)
+				break
+			}
 			var sz uint32
 			sz, v, err = cbor.ReadMapHeaderBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "map", len(b)-len(v))
 			}
 			if x.Map == nil && sz > 0 {
 				x.Map = make(map[string]Scalars, sz)
@@ -144,23 +282,48 @@ func (x *Containers) DecodeSafe(b []byte) ([]byte, error) {
 			}
 			for iMap := uint32(0); iMap < sz; iMap++ {
 				var key string
-				key, v, err = cbor.ReadStringBytes(v)
+				key, v, err = in.ReadStringBytes(v)
 				if err != nil {
-					return b, err
+					return b, cbor.WrapDecodeError(err, "map", len(b)-len(v))
+				}
+				if _, dup := x.Map[key]; dup {
+					var skip bool
+					if v, skip, err = cbor.SkipDuplicate(v); err != nil {
+						return b, cbor.WrapDecodeError(cbor.WrapDecodeKey(err, key), "map", len(b)-len(v))
+					}
+					if skip {
+						continue
+					}
 				}
 				var tmp Scalars
 				v, err = (&tmp).UnmarshalCBOR(v)
 				if err != nil {
-					return b, err
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeKey(err, key), "map", len(b)-len(v))
 				}
 				x.Map[key] = tmp
 			}
 		case "ptr_map":
-
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 3); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "ptr_map", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "ptr_map", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.PtrMap = cbor.NullMap(x.PtrMap)
+				break
+			}
 			var sz uint32
 			sz, v, err = cbor.ReadMapHeaderBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "ptr_map", len(b)-len(v))
 			}
 			if x.PtrMap == nil && sz > 0 {
 				x.PtrMap = make(map[string]*Scalars, sz)
@@ -169,29 +332,47 @@ func (x *Containers) DecodeSafe(b []byte) ([]byte, error) {
 			}
 			for iPtrMap := uint32(0); iPtrMap < sz; iPtrMap++ {
 				var key string
-				key, v, err = cbor.ReadStringBytes(v)
+				key, v, err = in.ReadStringBytes(v)
 				if err != nil {
-					return b, err
+					return b, cbor.WrapDecodeError(err, "ptr_map", len(b)-len(v))
+				}
+				if _, dup := x.PtrMap[key]; dup {
+					var skip bool
+					if v, skip, err = cbor.SkipDuplicate(v); err != nil {
+						return b, cbor.WrapDecodeError(cbor.WrapDecodeKey(err, key), "ptr_map", len(b)-len(v))
+					}
+					if skip {
+						continue
+					}
+				}
+				if cbor.IsNilOrUndefined(v) {
+					v = v[1:]
+					x.PtrMap[key] = nil
+					continue
 				}
 				tmp := new(Scalars)
 				v, err = tmp.UnmarshalCBOR(v)
 				if err != nil {
-					return b, err
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeKey(err, key), "ptr_map", len(b)-len(v))
 				}
 				x.PtrMap[key] = tmp
 			}
 		default:
 			v, err = cbor.Skip(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
 			}
 		}
 		rest = v
 	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
 	return rest, nil
 }
 
-// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
 func (x *Containers) DecodeTrusted(b []byte) ([]byte, error) {
 	if x == nil {
 		return b, cbor.ErrNotNil
@@ -200,6 +381,9 @@ func (x *Containers) DecodeTrusted(b []byte) ([]byte, error) {
 	if err != nil {
 		return b, err
 	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
 	for i := uint32(0); i < sz; i++ {
 		keyBytes, v, err := cbor.ReadStringZC(rest)
 		if err != nil {
@@ -208,9 +392,19 @@ func (x *Containers) DecodeTrusted(b []byte) ([]byte, error) {
 		key := cbor.UnsafeString(keyBytes)
 		switch key {
 		case "items":
-
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Items = cbor.NullSlice(x.Items)
+				break
+			}
 			var sz uint32
-			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
 			if err != nil {
 				return b, err
 			}
@@ -230,10 +424,23 @@ func (x *Containers) DecodeTrusted(b []byte) ([]byte, error) {
 				}
 				x.Items[iItems] = tmp
 			}
+			if indef {
+				v = v[1:] // break
+			}
 		case "ptrs":
-
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Ptrs = cbor.NullSlice(x.Ptrs)
+				break
+			}
 			var sz uint32
-			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
 			if err != nil {
 				return b, err
 			}
@@ -246,6 +453,11 @@ func (x *Containers) DecodeTrusted(b []byte) ([]byte, error) {
 				_ = x.Ptrs[sz-1]
 			}
 			for iPtrs := uint32(0); iPtrs < sz; iPtrs++ {
+				if cbor.IsNilOrUndefined(v) {
+					v = v[1:]
+					x.Ptrs[iPtrs] = nil
+					continue
+				}
 				if x.Ptrs[iPtrs] == nil {
 					x.Ptrs[iPtrs] = new(Scalars)
 				}
@@ -254,8 +466,20 @@ func (x *Containers) DecodeTrusted(b []byte) ([]byte, error) {
 					return b, err
 				}
 			}
+			if indef {
+				v = v[1:] // break
+			}
 		case "map":
-
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Map = cbor.NullMap(x.Map)
+				break
+			}
 			var sz uint32
 			sz, v, err = cbor.ReadMapHeaderBytes(v)
 			if err != nil {
@@ -268,7 +492,7 @@ func (x *Containers) DecodeTrusted(b []byte) ([]byte, error) {
 			}
 			for iMap := uint32(0); iMap < sz; iMap++ {
 				var key string
-				key, v, err = cbor.ReadStringBytes(v)
+				key, v, err = cbor.ReadTrustedStringBytes(v)
 				if err != nil {
 					return b, err
 				}
@@ -280,7 +504,16 @@ func (x *Containers) DecodeTrusted(b []byte) ([]byte, error) {
 				x.Map[key] = tmp
 			}
 		case "ptr_map":
-
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.PtrMap = cbor.NullMap(x.PtrMap)
+				break
+			}
 			var sz uint32
 			sz, v, err = cbor.ReadMapHeaderBytes(v)
 			if err != nil {
@@ -293,10 +526,15 @@ func (x *Containers) DecodeTrusted(b []byte) ([]byte, error) {
 			}
 			for iPtrMap := uint32(0); iPtrMap < sz; iPtrMap++ {
 				var key string
-				key, v, err = cbor.ReadStringBytes(v)
+				key, v, err = cbor.ReadTrustedStringBytes(v)
 				if err != nil {
 					return b, err
 				}
+				if cbor.IsNilOrUndefined(v) {
+					v = v[1:]
+					x.PtrMap[key] = nil
+					continue
+				}
 				tmp := new(Scalars)
 				v, err = tmp.UnmarshalCBOR(v)
 				if err != nil {
@@ -315,6 +553,15 @@ func (x *Containers) DecodeTrusted(b []byte) ([]byte, error) {
 	return rest, nil
 }
 
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *Containers) resetCBOR() {
+	x.Items = x.Items[:0]
+	x.Ptrs = x.Ptrs[:0]
+	clear(x.Map)
+	clear(x.PtrMap)
+}
+
 // UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
 func (x *Containers) UnmarshalCBOR(b []byte) ([]byte, error) {
 	return x.DecodeSafe(b)
@@ -118,3 +118,180 @@ func TestContainersRoundTripSafeAndTrusted(t *testing.T) {
 		})
 	}
 }
+
+func TestContainersSlicePtrNilElements(t *testing.T) {
+	a := Scalars{S: "a", I: 1}
+	c := Scalars{S: "c", I: 3}
+	orig := &Containers{Ptrs: []*Scalars{nil, &a, nil, &c, nil}}
+
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+
+	for _, tc := range containersDecoders {
+		t.Run(tc.name, func(t *testing.T) {
+			// Reused destination: stale non-nil elements must be cleared
+			// where the payload has null.
+			dst := Containers{Ptrs: make([]*Scalars, 5, 8)}
+			for i := range dst.Ptrs {
+				dst.Ptrs[i] = &Scalars{S: "stale"}
+			}
+			rest, err := tc.decode(&dst, b)
+			if err != nil {
+				t.Fatalf("%s error: %v", tc.name, err)
+			}
+			if len(rest) != 0 {
+				t.Fatalf("%s leftover bytes: %d", tc.name, len(rest))
+			}
+			if len(dst.Ptrs) != len(orig.Ptrs) {
+				t.Fatalf("%s Ptrs length %d, want %d", tc.name, len(dst.Ptrs), len(orig.Ptrs))
+			}
+			for i, want := range orig.Ptrs {
+				got := dst.Ptrs[i]
+				if (got == nil) != (want == nil) {
+					t.Fatalf("%s Ptrs[%d] = %+v, want %+v", tc.name, i, got, want)
+				}
+				if want != nil && (got.S != want.S || got.I != want.I) {
+					t.Fatalf("%s Ptrs[%d] = %+v, want %+v", tc.name, i, got, want)
+				}
+			}
+		})
+	}
+
+	// undefined elements decode like null.
+	in := cbor.AppendMapHeader(nil, 1)
+	in = cbor.AppendString(in, "ptrs")
+	in = cbor.AppendArrayHeader(in, 2)
+	in = append(in, 0xf7)
+	in, _ = a.MarshalCBOR(in)
+	var dst Containers
+	if _, err := dst.DecodeSafe(in); err != nil {
+		t.Fatalf("DecodeSafe error: %v", err)
+	}
+	if len(dst.Ptrs) != 2 || dst.Ptrs[0] != nil || dst.Ptrs[1] == nil || dst.Ptrs[1].S != "a" {
+		t.Fatalf("unexpected Ptrs: %+v", dst.Ptrs)
+	}
+}
+
+func TestContainersCanonicalMapEncode(t *testing.T) {
+	defer func(v bool) { cbor.CanonicalMapEncode = v }(cbor.CanonicalMapEncode)
+	cbor.CanonicalMapEncode = true
+
+	orig := &Containers{
+		Map:    map[string]Scalars{},
+		PtrMap: map[string]*Scalars{},
+	}
+	for _, k := range []string{"zz", "a", "bbb", "c", "aa", "b"} {
+		orig.Map[k] = Scalars{S: k}
+		orig.PtrMap[k] = &Scalars{S: k}
+	}
+
+	first, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	for i := 0; i < 20; i++ {
+		b, err := orig.MarshalCBOR(nil)
+		if err != nil {
+			t.Fatalf("MarshalCBOR error: %v", err)
+		}
+		if string(b) != string(first) {
+			t.Fatalf("encoding %d differs from the first", i)
+		}
+	}
+
+	fields, _, err := cbor.ReadOrderedMapBytes(first)
+	if err != nil {
+		t.Fatalf("ReadOrderedMapBytes error: %v", err)
+	}
+	for _, f := range fields {
+		name, _, err := cbor.ReadStringBytes(f.Key)
+		if err != nil {
+			t.Fatalf("ReadStringBytes error: %v", err)
+		}
+		if name != "map" && name != "ptr_map" {
+			continue
+		}
+		entries, _, err := cbor.ReadOrderedMapBytes(f.Value)
+		if err != nil {
+			t.Fatalf("%s: ReadOrderedMapBytes error: %v", name, err)
+		}
+		for i := 1; i < len(entries); i++ {
+			if cbor.RFC8949.Compare(entries[i-1].Key, entries[i].Key) >= 0 {
+				t.Fatalf("%s: entry %d out of order", name, i)
+			}
+		}
+	}
+
+	var dst Containers
+	if _, err := dst.DecodeSafe(first); err != nil {
+		t.Fatalf("DecodeSafe error: %v", err)
+	}
+	if len(dst.Map) != len(orig.Map) || len(dst.PtrMap) != len(orig.PtrMap) || dst.Map["bbb"].S != "bbb" || dst.PtrMap["zz"].S != "zz" {
+		t.Fatalf("round trip mismatch: %+v", dst)
+	}
+}
+
+func TestContainersResetBeforeDecode(t *testing.T) {
+	payload, err := (&Containers{Items: []Scalars{{S: "new", I: 7}}}).MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	// Drop every field but "items" so the others are absent on the wire.
+	in := cbor.AppendMapHeader(nil, 1)
+	fields, _, err := cbor.ReadOrderedMapBytes(payload)
+	if err != nil {
+		t.Fatalf("ReadOrderedMapBytes error: %v", err)
+	}
+	for _, f := range fields {
+		if name, _, _ := cbor.ReadStringBytes(f.Key); name == "items" {
+			in = append(in, f.Key...)
+			in = append(in, f.Value...)
+		}
+	}
+
+	stale := func() *Containers {
+		return &Containers{
+			Items:  append(make([]Scalars, 0, 8), Scalars{S: "old"}, Scalars{S: "old"}),
+			Ptrs:   []*Scalars{{S: "old"}},
+			Map:    map[string]Scalars{"old": {S: "old"}},
+			PtrMap: map[string]*Scalars{"old": {S: "old"}},
+		}
+	}
+
+	for _, tc := range containersDecoders {
+		t.Run(tc.name, func(t *testing.T) {
+			// Default: absent fields keep their previous contents.
+			dst := stale()
+			if _, err := tc.decode(dst, in); err != nil {
+				t.Fatalf("%s error: %v", tc.name, err)
+			}
+			if len(dst.Ptrs) != 1 || len(dst.Map) != 1 || len(dst.PtrMap) != 1 {
+				t.Fatalf("%s without reset cleared absent fields: %+v", tc.name, dst)
+			}
+
+			cbor.ResetBeforeDecode = true
+			defer func() { cbor.ResetBeforeDecode = false }()
+
+			dst = stale()
+			items, m := &dst.Items[0], dst.Map
+			if _, err := tc.decode(dst, in); err != nil {
+				t.Fatalf("%s error: %v", tc.name, err)
+			}
+			if len(dst.Items) != 1 || dst.Items[0].S != "new" || dst.Items[0].I != 7 {
+				t.Fatalf("%s Items = %+v", tc.name, dst.Items)
+			}
+			if &dst.Items[0] != items {
+				t.Fatalf("%s Items was reallocated despite spare capacity", tc.name)
+			}
+			if len(dst.Ptrs) != 0 || len(dst.Map) != 0 || len(dst.PtrMap) != 0 {
+				t.Fatalf("%s absent fields not reset: %+v", tc.name, dst)
+			}
+			m["probe"] = Scalars{}
+			if len(dst.Map) != 1 {
+				t.Fatalf("%s Map was replaced instead of cleared", tc.name)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package structs
+
+import (
+	stdtime "time"
+
+	rt "github.com/delaneyj/cbor/runtime"
+	"github.com/delaneyj/cbor/tests/structs/ext/cbor"
+	g "github.com/delaneyj/cbor/tests/structs/geo"
+)
+
+// Vehicle has fields of types from other packages imported under an
+// alias, including the runtime and the standard time package, and from a
+// package named cbor like the runtime.
+type Vehicle struct {
+	ID    string        `cbor:"id"`
+	Pos   g.Point       `cbor:"pos"`
+	Route []g.Point     `cbor:"route"`
+	Ver   cbor.Version  `cbor:"ver"`
+	Prev  *cbor.Version `cbor:"prev,omitempty"`
+	Seen  stdtime.Time  `cbor:"seen"`
+	Extra rt.RawMessage `cbor:"extra,omitempty"`
+}
@@ -0,0 +1,354 @@
+// Code generated by cborgen DO NOT EDIT.
+
+package structs
+
+import (
+	"io"
+	"time"
+
+	rt "github.com/delaneyj/cbor/runtime"
+)
+
+// Fail the build if a generated method drifts from the runtime interfaces.
+var (
+	_ rt.Marshaler   = (*Vehicle)(nil)
+	_ rt.Unmarshaler = (*Vehicle)(nil)
+)
+
+func (x Vehicle) Msgsize() (s int) {
+	s = rt.MapHeaderSize + rt.StringPrefixSize + len("id") + rt.StringPrefixSize + len(x.ID) + rt.StringPrefixSize + len("seen") + rt.TimeSize
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *Vehicle) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *Vehicle) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return rt.MarshalTo(w, x, x.Msgsize())
+	}
+	return rt.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *Vehicle) AppendCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return rt.AppendNil(b), nil
+	}
+
+	b = rt.Require(b, x.Msgsize())
+
+	count := uint32(0)
+	count++
+	count++
+	count++
+	count++
+	if !(x.Prev == nil) {
+		count++
+	}
+	count++
+	if !(rt.IsZeroMethod(&x.Extra)) {
+		count++
+	}
+	b = rt.AppendMapHeader(b, count)
+	var err error
+	b = rt.AppendString(b, "id")
+	b, err = rt.AppendString(b, x.ID), nil
+	if err != nil {
+		return b, err
+	}
+	b = rt.AppendString(b, "pos")
+	b, err = rt.AppendInterface(b, x.Pos)
+	if err != nil {
+		return b, err
+	}
+	b = rt.AppendString(b, "route")
+	b, err = rt.AppendInterface(b, x.Route)
+	if err != nil {
+		return b, err
+	}
+	b = rt.AppendString(b, "ver")
+	b, err = rt.AppendInterface(b, x.Ver)
+	if err != nil {
+		return b, err
+	}
+	if !(x.Prev == nil) {
+		b = rt.AppendString(b, "prev")
+		b, err = rt.AppendInterface(b, x.Prev)
+		if err != nil {
+			return b, err
+		}
+	}
+	b = rt.AppendString(b, "seen")
+	b, err = rt.AppendTime(b, x.Seen), nil
+	if err != nil {
+		return b, err
+	}
+	if !(rt.IsZeroMethod(&x.Extra)) {
+		b = rt.AppendString(b, "extra")
+		b, err = x.Extra.MarshalCBOR(b)
+		if err != nil {
+			return b, err
+		}
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *Vehicle) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, rt.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *Vehicle) DecodeInterned(b []byte, in *rt.Interner) ([]byte, error) {
+	if x == nil {
+		return b, rt.ErrNotNil
+	}
+	sz, rest, err := rt.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, rt.WrapDecodeError(err, "", 0)
+	}
+	if rt.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := in.ReadStringBytes(rest)
+		if err != nil {
+			return b, rt.WrapDecodeError(err, "", len(b)-len(rest))
+		}
+		switch key {
+		case "id":
+			if o, skip, err := rt.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, rt.WrapDecodeError(err, "id", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if rt.IsTagged(v) {
+				if v, err = rt.UntagBytes(v); err != nil {
+					return b, rt.WrapDecodeError(err, "id", len(b)-len(v))
+				}
+			}
+
+			var tmp string
+			tmp, v, err = in.ReadStringBytes(v)
+			if err != nil {
+				return b, rt.WrapDecodeError(err, "id", len(b)-len(v))
+			}
+			x.ID = tmp
+		case "pos":
+			if o, skip, err := rt.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, rt.WrapDecodeError(err, "pos", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+
+			v, err = rt.ReadWithCodec(v, &x.Pos)
+			if err != nil {
+				return b, rt.WrapDecodeError(err, "pos", len(b)-len(v))
+			}
+		case "route":
+			if o, skip, err := rt.DuplicateField(v, seen[:], 2); skip || err != nil {
+				if err != nil {
+					return b, rt.WrapDecodeError(err, "route", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if rt.IsTagged(v) {
+				if v, err = rt.UntagBytes(v); err != nil {
+					return b, rt.WrapDecodeError(err, "route", len(b)-len(v))
+				}
+			}
+
+			v, err = rt.ReadWithCodec(v, &x.Route)
+			if err != nil {
+				return b, rt.WrapDecodeError(err, "route", len(b)-len(v))
+			}
+		case "ver":
+			if o, skip, err := rt.DuplicateField(v, seen[:], 3); skip || err != nil {
+				if err != nil {
+					return b, rt.WrapDecodeError(err, "ver", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+
+			v, err = rt.ReadWithCodec(v, &x.Ver)
+			if err != nil {
+				return b, rt.WrapDecodeError(err, "ver", len(b)-len(v))
+			}
+		case "prev":
+			if o, skip, err := rt.DuplicateField(v, seen[:], 4); skip || err != nil {
+				if err != nil {
+					return b, rt.WrapDecodeError(err, "prev", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+
+			v, err = rt.ReadWithCodec(v, &x.Prev)
+			if err != nil {
+				return b, rt.WrapDecodeError(err, "prev", len(b)-len(v))
+			}
+		case "seen":
+			if o, skip, err := rt.DuplicateField(v, seen[:], 5); skip || err != nil {
+				if err != nil {
+					return b, rt.WrapDecodeError(err, "seen", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+
+			var tmp time.Time
+			tmp, v, err = rt.ReadAnyTimeBytes(v)
+			if err != nil {
+				return b, rt.WrapDecodeError(err, "seen", len(b)-len(v))
+			}
+			x.Seen = tmp
+		case "extra":
+			if o, skip, err := rt.DuplicateField(v, seen[:], 6); skip || err != nil {
+				if err != nil {
+					return b, rt.WrapDecodeError(err, "extra", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+
+			v, err = x.Extra.UnmarshalCBOR(v)
+			if err != nil {
+				return b, rt.WrapDecodeError(err, "extra", len(b)-len(v))
+			}
+		default:
+			v, err = rt.Skip(v)
+			if err != nil {
+				return b, rt.WrapDecodeError(err, key, len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if err := rt.ValidateDecoded(x); err != nil {
+		return b, rt.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *Vehicle) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, rt.ErrNotNil
+	}
+	sz, rest, err := rt.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if rt.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := rt.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := rt.UnsafeString(keyBytes)
+		switch key {
+		case "id":
+			if rt.IsTagged(v) {
+				if v, err = rt.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			x.ID, v, err = rt.ReadTrustedStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+		case "pos":
+
+			v, err = rt.ReadWithCodec(v, &x.Pos)
+			if err != nil {
+				return b, err
+			}
+		case "route":
+			if rt.IsTagged(v) {
+				if v, err = rt.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			v, err = rt.ReadWithCodec(v, &x.Route)
+			if err != nil {
+				return b, err
+			}
+		case "ver":
+
+			v, err = rt.ReadWithCodec(v, &x.Ver)
+			if err != nil {
+				return b, err
+			}
+		case "prev":
+
+			v, err = rt.ReadWithCodec(v, &x.Prev)
+			if err != nil {
+				return b, err
+			}
+		case "seen":
+
+			var tmp time.Time
+			tmp, v, err = rt.ReadAnyTimeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Seen = tmp
+		case "extra":
+
+			v, err = x.Extra.UnmarshalCBOR(v)
+			if err != nil {
+				return b, err
+			}
+		default:
+			v, err = rt.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *Vehicle) resetCBOR() {
+	var zero Vehicle
+	x.ID = zero.ID
+	x.Pos = zero.Pos
+	x.Route = x.Route[:0]
+	x.Ver = zero.Ver
+	x.Prev = zero.Prev
+	x.Seen = zero.Seen
+	x.Extra = zero.Extra
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Vehicle) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
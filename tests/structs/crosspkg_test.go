@@ -0,0 +1,46 @@
+package structs
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+	ext "github.com/delaneyj/cbor/tests/structs/ext/cbor"
+	"github.com/delaneyj/cbor/tests/structs/geo"
+)
+
+func TestVehicleCrossPackageRoundTrip(t *testing.T) {
+	in := Vehicle{
+		ID:    "bus-7",
+		Pos:   geo.Point{Lat: 52.5, Lon: 13.4},
+		Route: []geo.Point{{Lat: 52.5, Lon: 13.4}, {Lat: 52.52, Lon: 13.41}},
+		Ver:   ext.Version{Major: 2, Minor: 1},
+		Prev:  &ext.Version{Major: 1},
+		Seen:  time.Unix(1700000000, 0),
+		Extra: cbor.RawMessage{0x83, 0x01, 0x02, 0x03},
+	}
+	b, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	for _, path := range []struct {
+		name   string
+		decode func(*Vehicle, []byte) ([]byte, error)
+	}{
+		{"DecodeSafe", (*Vehicle).DecodeSafe},
+		{"DecodeTrusted", (*Vehicle).DecodeTrusted},
+	} {
+		var out Vehicle
+		if _, err := path.decode(&out, b); err != nil {
+			t.Fatalf("%s error: %v", path.name, err)
+		}
+		if !out.Seen.Equal(in.Seen) {
+			t.Errorf("%s: Seen = %v, want %v", path.name, out.Seen, in.Seen)
+		}
+		out.Seen = in.Seen
+		if !reflect.DeepEqual(out, in) {
+			t.Errorf("%s = %+v, want %+v", path.name, out, in)
+		}
+	}
+}
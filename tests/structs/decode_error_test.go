@@ -0,0 +1,121 @@
+package structs
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+// badTeam encodes a Team whose second member has a text string age, and
+// returns it with the offset of that age value.
+func badTeam() ([]byte, int) {
+	var b []byte
+	b = cbor.AppendMapHeader(b, 2)
+	b = cbor.AppendString(b, "votes")
+	b = cbor.AppendMapHeader(b, 0)
+	b = cbor.AppendString(b, "members")
+	b = cbor.AppendArrayHeader(b, 2)
+	b, _ = (&Person{Name: "Ada"}).MarshalCBOR(b)
+	b = cbor.AppendMapHeader(b, 1)
+	b = cbor.AppendString(b, "age")
+	off := len(b)
+	b = cbor.AppendString(b, "old")
+	return b, off
+}
+
+func TestDecodeErrorLocatesNestedFailure(t *testing.T) {
+	b, off := badTeam()
+
+	var team Team
+	_, err := team.DecodeSafe(b)
+	var de *cbor.DecodeError
+	if !errors.As(err, &de) {
+		t.Fatalf("DecodeSafe error = %v (%T), want *cbor.DecodeError", err, err)
+	}
+	if de.Path != "members[1].age" {
+		t.Fatalf("Path = %q, want %q", de.Path, "members[1].age")
+	}
+	if de.Offset != off {
+		t.Fatalf("Offset = %d, want %d", de.Offset, off)
+	}
+	if !bytes.HasPrefix(b[de.Offset:], cbor.AppendString(nil, "old")) {
+		t.Fatalf("Offset %d does not point at the bad value", de.Offset)
+	}
+	if de.Msg != de.Err.Error() || de.Msg == "" {
+		t.Fatalf("Msg = %q, want the underlying error's message", de.Msg)
+	}
+	var pe cbor.InvalidPrefixError
+	if !errors.As(err, &pe) {
+		t.Fatalf("DecodeError does not unwrap to the InvalidPrefixError: %v", err)
+	}
+}
+
+func TestDecodeErrorMapKeyAndTopLevel(t *testing.T) {
+	var b []byte
+	b = cbor.AppendMapHeader(b, 1)
+	b = cbor.AppendString(b, "votes")
+	b = cbor.AppendMapHeader(b, 1)
+	b = cbor.AppendString(b, "yes")
+	off := len(b)
+	b = cbor.AppendInt(b, -1)
+
+	var team Team
+	_, err := team.DecodeSafe(b)
+	var de *cbor.DecodeError
+	if !errors.As(err, &de) {
+		t.Fatalf("DecodeSafe error = %v, want *cbor.DecodeError", err)
+	}
+	if de.Path != `votes["yes"]` || de.Offset != off {
+		t.Fatalf("got Path %q Offset %d, want %q at %d", de.Path, de.Offset, `votes["yes"]`, off)
+	}
+
+	// A failure in the outermost value has an empty path.
+	_, err = team.DecodeSafe(cbor.AppendArrayHeader(nil, 0))
+	if !errors.As(err, &de) || de.Path != "" || de.Offset != 0 {
+		t.Fatalf("DecodeSafe error = %v, want DecodeError at offset 0 with no path", err)
+	}
+}
+
+func TestDecodeErrorHexContext(t *testing.T) {
+	b, off := badTeam()
+
+	// Off by default, so payload bytes stay out of error messages.
+	var team Team
+	_, err := team.DecodeSafe(b)
+	var de *cbor.DecodeError
+	if !errors.As(err, &de) || de.Snippet != "" {
+		t.Fatalf("DecodeSafe error = %v, want no snippet", err)
+	}
+
+	cbor.IncludeHexContext = true
+	defer func() { cbor.IncludeHexContext = false }()
+	_, err = team.DecodeSafe(b)
+	if !errors.As(err, &de) {
+		t.Fatalf("DecodeSafe error = %v (%T), want *cbor.DecodeError", err, err)
+	}
+	want := hex.EncodeToString(b[off-8:off]) + "[63]6f6c64"
+	if de.Snippet != want {
+		t.Fatalf("Snippet = %q, want %q", de.Snippet, want)
+	}
+	if !strings.HasSuffix(err.Error(), "(at members[1].age, offset "+strconv.Itoa(off)+", bytes "+want+")") {
+		t.Fatalf("Error() = %q, want the snippet", err)
+	}
+
+	// Truncated input points past the end.
+	short := b[:off]
+	_, err = team.DecodeSafe(short)
+	if !errors.As(err, &de) || !strings.HasSuffix(de.Snippet, "[end]") {
+		t.Fatalf("truncated input: error = %v, want a snippet ending in [end]", err)
+	}
+
+	// Trailing bytes and reflection decoding get snippets too.
+	err = cbor.Unmarshal(append(cbor.AppendNil(nil), 0x01), new(any))
+	if !errors.As(err, &de) || de.Snippet != "f6[01]" {
+		t.Fatalf("Unmarshal error = %v, want snippet f6[01]", err)
+	}
+}
@@ -0,0 +1,43 @@
+package structs
+
+import (
+	"net"
+	"time"
+)
+
+// Incident is generated with --diag to exercise DiagString.
+type Incident struct {
+	ID       uint64              `cbor:"1,keyasint"`
+	Title    string              `cbor:"title"`
+	Severity float64             `cbor:"severity"`
+	Tags     []string            `cbor:"tags"`
+	Payload  []byte              `cbor:"payload,omitempty"`
+	Owner    *Person             `cbor:"owner"`
+	Steps    []Step              `cbor:"steps"`
+	Parent   *Incident           `cbor:"parent,omitempty"`
+	Labels   map[string]int      `cbor:"labels"`
+	At       time.Time           `cbor:"at"`
+	Note     *string             `cbor:"note"`
+	Link     string              `cbor:"link,omitempty,tag=32"`
+	Closed   time.Time           `cbor:"closed,tag=1,float"`
+	Opened   time.Time           `cbor:"opened,tag=0"`
+	Teams    map[string]struct{} `cbor:"teams,set"`
+	Source   net.IP              `cbor:"source,tag=54"`
+	Priority float32             `cbor:"priority,string"`
+}
+
+// Step is a toarray struct whose trailing omitempty field may be dropped.
+type Step struct {
+	_       struct{} `cbor:",toarray"`
+	Name    string
+	Took    time.Duration `cbor:",unit=ms"`
+	Retries int           `cbor:",omitempty"`
+}
+
+// Gauge is a presence struct, whose diagnostic form opens with the bitmap.
+type Gauge struct {
+	_     struct{} `cbor:",toarray,presence"`
+	Name  string   `cbor:",omitempty"`
+	Value float64  `cbor:",omitempty"`
+	Unit  string
+}
@@ -0,0 +1,1693 @@
+// Code generated by cborgen DO NOT EDIT.
+
+package structs
+
+import (
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+// Fail the build if a generated method drifts from the runtime interfaces.
+var (
+	_ cbor.Marshaler   = (*Incident)(nil)
+	_ cbor.Unmarshaler = (*Incident)(nil)
+	_ cbor.Marshaler   = (*Step)(nil)
+	_ cbor.Unmarshaler = (*Step)(nil)
+	_ cbor.Marshaler   = (*Gauge)(nil)
+	_ cbor.Unmarshaler = (*Gauge)(nil)
+)
+
+func (x Incident) Msgsize() (s int) {
+	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("1") + cbor.Uint64Size + cbor.StringPrefixSize + len("title") + cbor.StringPrefixSize + len(x.Title) + cbor.StringPrefixSize + len("severity") + cbor.Float64Size + cbor.StringPrefixSize + len("tags") + cbor.ArrayHeaderSize + len(x.Tags)*cbor.StringPrefixSize + cbor.StringPrefixSize + len("payload") + cbor.BytesPrefixSize + len(x.Payload) + cbor.StringPrefixSize + len("steps") + cbor.ArrayHeaderSize + len(x.Steps)*0 + cbor.StringPrefixSize + len("labels") + cbor.MapHeaderSize + len(x.Labels)*(cbor.StringPrefixSize+cbor.IntSize) + cbor.StringPrefixSize + len("at") + cbor.TimeSize + cbor.StringPrefixSize + len("link") + cbor.StringPrefixSize + len(x.Link) + cbor.TagSize + cbor.StringPrefixSize + len("closed") + cbor.TimeSize + cbor.TagSize + cbor.StringPrefixSize + len("opened") + cbor.TimeSize + cbor.TagSize + cbor.StringPrefixSize + len("teams") + cbor.ArrayHeaderSize + len(x.Teams)*cbor.StringPrefixSize + cbor.StringPrefixSize + len("source") + cbor.IPSize + cbor.TagSize + cbor.StringPrefixSize + len("priority") + cbor.Float32Size + cbor.NumberStringSize
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *Incident) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *Incident) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *Incident) AppendCBOR(b []byte) ([]byte, error) {
+	return x.marshalCBORDepth(b, 0)
+}
+
+// marshalCBORDepth encodes x at the given nesting depth, failing with
+// ErrCycleDetected once depth exceeds MaxEncodeDepth.
+func (x *Incident) marshalCBORDepth(b []byte, depth int) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+	if depth > cbor.MaxEncodeDepth {
+		return b, cbor.ErrCycleDetected
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	count := uint32(0)
+	count++
+	count++
+	count++
+	count++
+	if !(len(x.Payload) == 0) {
+		count++
+	}
+	count++
+	count++
+	if !(x.Parent == nil) {
+		count++
+	}
+	count++
+	count++
+	count++
+	if !(x.Link == "") {
+		count++
+	}
+	count++
+	count++
+	count++
+	count++
+	count++
+	b = cbor.AppendMapHeader(b, count)
+	var err error
+	b = cbor.AppendInt64(b, 1)
+	b, err = cbor.AppendUint64(b, x.ID), nil
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "title")
+	b, err = cbor.AppendString(b, x.Title), nil
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "severity")
+	b, err = cbor.AppendFloat64(b, x.Severity), nil
+	if err != nil {
+		return b, err
+	}
+	if x.Tags == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = cbor.AppendString(b, "tags")
+		b = cbor.AppendNil(b)
+	} else {
+		b = cbor.AppendString(b, "tags")
+		b = cbor.AppendArrayHeader(b, uint32(len(x.Tags)))
+		for _, v := range x.Tags {
+			b = cbor.AppendString(b, v)
+		}
+	}
+	if !(len(x.Payload) == 0) {
+		b = cbor.AppendString(b, "payload")
+		if x.Payload == nil && cbor.NilContainers == cbor.NilAsNull {
+			b = cbor.AppendNil(b)
+		} else {
+			b, err = cbor.AppendBytes(b, x.Payload), nil
+			if err != nil {
+				return b, err
+			}
+		}
+	}
+	b = cbor.AppendString(b, "owner")
+	b, err = cbor.AppendPtrMarshaler(b, x.Owner)
+	if err != nil {
+		return b, err
+	}
+	if x.Steps == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = cbor.AppendString(b, "steps")
+		b = cbor.AppendNil(b)
+	} else {
+		b = cbor.AppendString(b, "steps")
+		b = cbor.AppendArrayHeader(b, uint32(len(x.Steps)))
+		for i := range x.Steps {
+			b, err = x.Steps[i].AppendCBOR(b)
+			if err != nil {
+				return b, err
+			}
+		}
+	}
+	if !(x.Parent == nil) {
+		b = cbor.AppendString(b, "parent")
+		b, err = x.Parent.marshalCBORDepth(b, depth+1)
+		if err != nil {
+			return b, err
+		}
+	}
+	if x.Labels == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = cbor.AppendString(b, "labels")
+		b = cbor.AppendNil(b)
+	} else {
+		b = cbor.AppendString(b, "labels")
+		if cbor.CanonicalMapEncode {
+			b, err = cbor.AppendMapDeterministic(b, x.Labels, cbor.EncKeyString, func(b []byte, v int) ([]byte, error) { return cbor.AppendInt(b, v), nil })
+			if err != nil {
+				return b, err
+			}
+		} else {
+			b = cbor.AppendMapHeader(b, uint32(len(x.Labels)))
+			for k, v := range x.Labels {
+				b = cbor.AppendString(b, k)
+				b = cbor.AppendInt(b, v)
+			}
+		}
+	}
+	b = cbor.AppendString(b, "at")
+	b, err = cbor.AppendTime(b, x.At), nil
+	if err != nil {
+		return b, err
+	}
+
+	b = cbor.AppendString(b, "note")
+	if x.Note == nil {
+		b = cbor.AppendNil(b)
+	} else {
+		b = cbor.AppendString(b, *x.Note)
+	}
+	if !(x.Link == "") {
+		b = cbor.AppendString(b, "link")
+		b, err = cbor.AppendURI(b, x.Link), nil
+		if err != nil {
+			return b, err
+		}
+	}
+	b = cbor.AppendString(b, "closed")
+	b, err = cbor.AppendTimeFloat(b, x.Closed), nil
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "opened")
+	b, err = cbor.AppendRFC3339Time(b, x.Opened), nil
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "teams")
+	b, err = cbor.AppendStringSet(b, x.Teams), nil
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "source")
+	b, err = cbor.AppendIPTagged(b, x.Source), nil
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "priority")
+	b, err = cbor.AppendFloatString(b, float64(x.Priority), 32), nil
+	if err != nil {
+		return b, err
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *Incident) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *Incident) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
+	for i := uint32(0); i < sz; i++ {
+		if t := cbor.NextType(rest); t == cbor.UintType || t == cbor.IntType {
+			ikey, fits, v, err := cbor.ReadIntKeyBytes(rest)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+			}
+			if !fits {
+				// No field has a key outside the int64 range.
+				if rest, err = cbor.Skip(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "", len(b)-len(v))
+				}
+				continue
+			}
+			switch ikey {
+			case 1:
+				if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+					if err != nil {
+						return b, cbor.WrapDecodeError(err, "1", len(b)-len(v))
+					}
+					v = o
+					break
+				}
+				if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+					if v, err = cbor.UntagBytes(v); err != nil {
+						return b, cbor.WrapDecodeError(err, "1", len(b)-len(v))
+					}
+				}
+
+				var tmp uint64
+				tmp, v, err = cbor.ReadUint64Bytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "1", len(b)-len(v))
+				}
+				x.ID = tmp
+			default:
+				v, err = cbor.Skip(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "", len(b)-len(v))
+				}
+			}
+			rest = v
+			continue
+		}
+		key, v, err := in.ReadStringBytes(rest)
+		if err != nil {
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+		}
+		switch key {
+		case "title":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "title", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "title", len(b)-len(v))
+				}
+			}
+
+			var tmp string
+			tmp, v, err = in.ReadStringBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "title", len(b)-len(v))
+			}
+			x.Title = tmp
+		case "severity":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 2); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "severity", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "severity", len(b)-len(v))
+				}
+			}
+
+			var tmp float64
+			tmp, v, err = cbor.ReadFloat64Bytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "severity", len(b)-len(v))
+			}
+			x.Severity = tmp
+		case "tags":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 3); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "tags", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "tags", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Tags = cbor.NullSlice(x.Tags)
+				break
+			}
+			var sz uint32
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "tags", len(b)-len(v))
+			}
+			if cap(x.Tags) >= int(sz) {
+				x.Tags = x.Tags[:sz]
+			} else {
+				x.Tags = make([]string, sz)
+			}
+			if sz > 0 {
+				_ = x.Tags[sz-1]
+			}
+			for iTags := uint32(0); iTags < sz; iTags++ {
+				var tmp string
+				tmp, v, err = in.ReadStringBytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, int(iTags)), "tags", len(b)-len(v))
+				}
+				x.Tags[iTags] = tmp
+			}
+			if indef {
+				v = v[1:] // break
+			}
+		case "payload":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 4); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "payload", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "payload", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Payload = cbor.NullSlice(x.Payload)
+				break
+			}
+			var tmp []byte
+			tmp, v, err = cbor.ReadBytesBytes(v, nil)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "payload", len(b)-len(v))
+			}
+			x.Payload = tmp
+		case "owner":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 5); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "owner", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Owner = nil
+				break
+			}
+			if x.Owner == nil {
+				x.Owner = new(Person)
+			}
+			v, err = x.Owner.UnmarshalCBOR(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "owner", len(b)-len(v))
+			}
+		case "steps":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 6); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "steps", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "steps", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Steps = cbor.NullSlice(x.Steps)
+				break
+			}
+			var sz uint32
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "steps", len(b)-len(v))
+			}
+			if cap(x.Steps) >= int(sz) {
+				x.Steps = x.Steps[:sz]
+			} else {
+				x.Steps = make([]Step, sz)
+			}
+			if sz > 0 {
+				_ = x.Steps[sz-1]
+			}
+			for iSteps := uint32(0); iSteps < sz; iSteps++ {
+				x.Steps[iSteps].resetCBOR()
+				v, err = x.Steps[iSteps].DecodeInterned(v, in)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, int(iSteps)), "steps", len(b)-len(v))
+				}
+			}
+			if indef {
+				v = v[1:] // break
+			}
+		case "parent":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 7); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "parent", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Parent = nil
+				break
+			}
+			if x.Parent == nil {
+				x.Parent = new(Incident)
+			} else {
+				x.Parent.resetCBOR()
+			}
+			v, err = x.Parent.DecodeInterned(v, in)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "parent", len(b)-len(v))
+			}
+		case "labels":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 8); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "labels", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "labels", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Labels = cbor.NullMap(x.Labels)
+				break
+			}
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "labels", len(b)-len(v))
+			}
+			if x.Labels == nil && sz > 0 {
+				x.Labels = make(map[string]int, sz)
+			} else if x.Labels != nil {
+				clear(x.Labels)
+			}
+			for iLabels := uint32(0); iLabels < sz; iLabels++ {
+				var key string
+				key, v, err = in.ReadStringBytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "labels", len(b)-len(v))
+				}
+				if _, dup := x.Labels[key]; dup {
+					var skip bool
+					if v, skip, err = cbor.SkipDuplicate(v); err != nil {
+						return b, cbor.WrapDecodeError(cbor.WrapDecodeKey(err, key), "labels", len(b)-len(v))
+					}
+					if skip {
+						continue
+					}
+				}
+				var tmp int
+				tmp, v, err = cbor.ReadIntBytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeKey(err, key), "labels", len(b)-len(v))
+				}
+				x.Labels[key] = tmp
+			}
+		case "at":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 9); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "at", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+
+			var tmp time.Time
+			tmp, v, err = cbor.ReadAnyTimeBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "at", len(b)-len(v))
+			}
+			x.At = tmp
+		case "note":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 10); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "note", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "note", len(b)-len(v))
+				}
+			}
+
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Note = nil
+				break
+			}
+			var tmp string
+			tmp, v, err = in.ReadStringBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "note", len(b)-len(v))
+			}
+			if x.Note == nil {
+				x.Note = new(string)
+			}
+			*x.Note = tmp
+		case "link":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 11); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "link", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+
+			var tmp string
+			tmp, v, err = cbor.ReadValidURIStringBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "link", len(b)-len(v))
+			}
+			x.Link = tmp
+		case "closed":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 12); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "closed", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+
+			var tmp time.Time
+			tmp, v, err = cbor.ReadAnyTimeBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "closed", len(b)-len(v))
+			}
+			x.Closed = tmp
+		case "opened":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 13); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "opened", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+
+			var tmp time.Time
+			tmp, v, err = cbor.ReadAnyTimeBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "opened", len(b)-len(v))
+			}
+			x.Opened = tmp
+		case "teams":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 14); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "teams", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "teams", len(b)-len(v))
+				}
+			}
+			x.Teams, v, err = cbor.ReadStringSetBytes(v, x.Teams, in)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "teams", len(b)-len(v))
+			}
+		case "source":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 15); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "source", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+
+			var tmp net.IP
+			tmp, v, err = cbor.ReadIPBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "source", len(b)-len(v))
+			}
+			x.Source = tmp
+		case "priority":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 16); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "priority", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "priority", len(b)-len(v))
+				}
+			}
+
+			var tmp float64
+			tmp, v, err = cbor.ReadFloatStringBytes(v, 32)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "priority", len(b)-len(v))
+			}
+			x.Priority = float32(tmp)
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *Incident) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		if t := cbor.NextType(rest); t == cbor.UintType || t == cbor.IntType {
+			ikey, fits, v, err := cbor.ReadIntKeyBytes(rest)
+			if err != nil {
+				return b, err
+			}
+			if !fits {
+				if rest, err = cbor.Skip(v); err != nil {
+					return b, err
+				}
+				continue
+			}
+			switch ikey {
+			case 1:
+				if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+					if v, err = cbor.UntagBytes(v); err != nil {
+						return b, err
+					}
+				}
+
+				var tmp uint64
+				tmp, v, err = cbor.ReadUint64Bytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.ID = tmp
+			default:
+				v, err = cbor.Skip(v)
+				if err != nil {
+					return b, err
+				}
+			}
+			rest = v
+			continue
+		}
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "title":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			x.Title, v, err = cbor.ReadTrustedStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+		case "severity":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp float64
+			tmp, v, err = cbor.ReadFloat64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Severity = tmp
+		case "tags":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Tags = cbor.NullSlice(x.Tags)
+				break
+			}
+			var sz uint32
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap(x.Tags) >= int(sz) {
+				x.Tags = x.Tags[:sz]
+			} else {
+				x.Tags = make([]string, sz)
+			}
+			if sz > 0 {
+				_ = x.Tags[sz-1]
+			}
+			for iTags := uint32(0); iTags < sz; iTags++ {
+				var tmp string
+				tmp, v, err = cbor.ReadTrustedStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Tags[iTags] = tmp
+			}
+			if indef {
+				v = v[1:] // break
+			}
+		case "payload":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Payload = cbor.NullSlice(x.Payload)
+				break
+			}
+			var tmp []byte
+			tmp, v, err = cbor.ReadBytesBytes(v, nil)
+			if err != nil {
+				return b, err
+			}
+			x.Payload = tmp
+		case "owner":
+
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Owner = nil
+				break
+			}
+			if x.Owner == nil {
+				x.Owner = new(Person)
+			}
+			v, err = x.Owner.UnmarshalCBOR(v)
+			if err != nil {
+				return b, err
+			}
+		case "steps":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Steps = cbor.NullSlice(x.Steps)
+				break
+			}
+			var sz uint32
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap(x.Steps) >= int(sz) {
+				x.Steps = x.Steps[:sz]
+			} else {
+				x.Steps = make([]Step, sz)
+			}
+			if sz > 0 {
+				_ = x.Steps[sz-1]
+			}
+			for iSteps := uint32(0); iSteps < sz; iSteps++ {
+				x.Steps[iSteps].resetCBOR()
+				v, err = x.Steps[iSteps].DecodeTrusted(v)
+				if err != nil {
+					return b, err
+				}
+			}
+			if indef {
+				v = v[1:] // break
+			}
+		case "parent":
+
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Parent = nil
+				break
+			}
+			if x.Parent == nil {
+				x.Parent = new(Incident)
+			} else {
+				x.Parent.resetCBOR()
+			}
+			v, err = x.Parent.DecodeTrusted(v)
+			if err != nil {
+				return b, err
+			}
+		case "labels":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Labels = cbor.NullMap(x.Labels)
+				break
+			}
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if x.Labels == nil && sz > 0 {
+				x.Labels = make(map[string]int, sz)
+			} else if x.Labels != nil {
+				clear(x.Labels)
+			}
+			for iLabels := uint32(0); iLabels < sz; iLabels++ {
+				var key string
+				key, v, err = cbor.ReadTrustedStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				var tmp int
+				tmp, v, err = cbor.ReadIntBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Labels[key] = tmp
+			}
+		case "at":
+
+			var tmp time.Time
+			tmp, v, err = cbor.ReadAnyTimeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.At = tmp
+		case "note":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Note = nil
+				break
+			}
+			var tmp string
+			tmp, v, err = cbor.ReadTrustedStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if x.Note == nil {
+				x.Note = new(string)
+			}
+			*x.Note = tmp
+		case "link":
+
+			var tmp string
+			tmp, v, err = cbor.ReadURIStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Link = tmp
+		case "closed":
+
+			var tmp time.Time
+			tmp, v, err = cbor.ReadAnyTimeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Closed = tmp
+		case "opened":
+
+			var tmp time.Time
+			tmp, v, err = cbor.ReadAnyTimeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Opened = tmp
+		case "teams":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			x.Teams, v, err = cbor.ReadStringSetBytes(v, x.Teams, nil)
+			if err != nil {
+				return b, err
+			}
+		case "source":
+
+			var tmp net.IP
+			tmp, v, err = cbor.ReadIPBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Source = tmp
+		case "priority":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp float64
+			tmp, v, err = cbor.ReadFloatStringBytes(v, 32)
+			if err != nil {
+				return b, err
+			}
+			x.Priority = float32(tmp)
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *Incident) resetCBOR() {
+	var zero Incident
+	x.ID = zero.ID
+	x.Title = zero.Title
+	x.Severity = zero.Severity
+	x.Tags = x.Tags[:0]
+	x.Payload = x.Payload[:0]
+	x.Owner = zero.Owner
+	x.Steps = x.Steps[:0]
+	x.Parent = zero.Parent
+	clear(x.Labels)
+	x.At = zero.At
+	x.Note = zero.Note
+	x.Link = zero.Link
+	x.Closed = zero.Closed
+	x.Opened = zero.Opened
+	clear(x.Teams)
+	x.Source = zero.Source
+	x.Priority = zero.Priority
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Incident) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+// DiagString returns x in CBOR diagnostic notation (RFC 8949 §8), as
+// cbor.DiagBytes renders its encoding, for logs and test failures.
+func (x *Incident) DiagString() string {
+	return string(x.appendDiag(nil))
+}
+
+// appendDiag appends x to b in diagnostic notation.
+func (x *Incident) appendDiag(b []byte) []byte {
+	if x == nil {
+		return append(b, "null"...)
+	}
+	b = append(b, '{')
+	n := len(b)
+	b = append(b, "1: "...)
+	b = strconv.AppendUint(b, uint64(x.ID), 10)
+	b = append(b, ", "...)
+	b = append(b, "\"title\": "...)
+	b = strconv.AppendQuote(b, x.Title)
+	b = append(b, ", "...)
+	b = append(b, "\"severity\": "...)
+	b = cbor.AppendDiagFloat64(b, x.Severity)
+	b = append(b, ", "...)
+	b = append(b, "\"tags\": "...)
+	if x.Tags == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = append(b, "null"...)
+	} else {
+		b = append(b, '[')
+		for i0 := range x.Tags {
+			if i0 > 0 {
+				b = append(b, ", "...)
+			}
+			b = strconv.AppendQuote(b, x.Tags[i0])
+		}
+		b = append(b, ']')
+	}
+	b = append(b, ", "...)
+	if !(len(x.Payload) == 0) {
+		b = append(b, "\"payload\": "...)
+		if x.Payload == nil && cbor.NilContainers == cbor.NilAsNull {
+			b = append(b, "null"...)
+		} else {
+			b = cbor.AppendDiagBytes(b, x.Payload)
+		}
+		b = append(b, ", "...)
+	}
+	b = append(b, "\"owner\": "...)
+	b = cbor.AppendDiag(b, x.Owner)
+	b = append(b, ", "...)
+	b = append(b, "\"steps\": "...)
+	if x.Steps == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = append(b, "null"...)
+	} else {
+		b = append(b, '[')
+		for i0 := range x.Steps {
+			if i0 > 0 {
+				b = append(b, ", "...)
+			}
+			b = x.Steps[i0].appendDiag(b)
+		}
+		b = append(b, ']')
+	}
+	b = append(b, ", "...)
+	if !(x.Parent == nil) {
+		b = append(b, "\"parent\": "...)
+		b = x.Parent.appendDiag(b)
+		b = append(b, ", "...)
+	}
+	b = append(b, "\"labels\": "...)
+	if x.Labels == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = append(b, "null"...)
+	} else {
+		b = cbor.AppendDiag(b, x.Labels)
+	}
+	b = append(b, ", "...)
+	b = append(b, "\"at\": "...)
+	b = cbor.AppendDiag(b, x.At)
+	b = append(b, ", "...)
+	b = append(b, "\"note\": "...)
+	if x.Note == nil {
+		b = append(b, "null"...)
+	} else {
+		b = strconv.AppendQuote(b, *x.Note)
+	}
+	b = append(b, ", "...)
+	if !(x.Link == "") {
+		b = append(b, "\"link\": "...)
+		b = append(b, "32("...)
+		b = strconv.AppendQuote(b, x.Link)
+		b = append(b, ')')
+		b = append(b, ", "...)
+	}
+	b = append(b, "\"closed\": "...)
+	b = append(b, "1("...)
+	b = cbor.AppendDiagFloat64(b, cbor.EpochSeconds(x.Closed))
+	b = append(b, ')')
+	b = append(b, ", "...)
+	b = append(b, "\"opened\": "...)
+	if s, _, err := cbor.DiagBytes(cbor.AppendRFC3339Time(nil, x.Opened)); err == nil {
+		b = append(b, s...)
+	}
+	b = append(b, ", "...)
+	b = append(b, "\"teams\": "...)
+	if s, _, err := cbor.DiagBytes(cbor.AppendStringSet(nil, x.Teams)); err == nil {
+		b = append(b, s...)
+	}
+	b = append(b, ", "...)
+	b = append(b, "\"source\": "...)
+	if s, _, err := cbor.DiagBytes(cbor.AppendIPTagged(nil, x.Source)); err == nil {
+		b = append(b, s...)
+	}
+	b = append(b, ", "...)
+	b = append(b, "\"priority\": "...)
+	b = strconv.AppendQuote(b, strconv.FormatFloat(float64(x.Priority), 'g', -1, 32))
+	b = append(b, ", "...)
+	if len(b) > n {
+		b = b[:len(b)-2]
+	}
+	return append(b, '}')
+}
+
+func (x Step) Msgsize() (s int) {
+	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("Name") + cbor.StringPrefixSize + len(x.Name) + cbor.StringPrefixSize + len("Took") + cbor.DurationSize + cbor.StringPrefixSize + len("Retries") + cbor.IntSize
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *Step) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *Step) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *Step) AppendCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	var count uint32
+	switch {
+	case !(x.Retries == 0):
+		count = 3
+	default:
+		count = 2
+	}
+	b = cbor.AppendArrayHeader(b, count)
+	var err error
+	b, err = cbor.AppendString(b, x.Name), nil
+	if err != nil {
+		return b, err
+	}
+	b, err = cbor.AppendDurationUnit(b, x.Took, time.Millisecond), nil
+	if err != nil {
+		return b, err
+	}
+	if !(count <= 2) {
+		b, err = cbor.AppendInt(b, x.Retries), nil
+		if err != nil {
+			return b, err
+		}
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *Step) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *Step) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, indef, rest, err := cbor.ReadArraySizeBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if sz > 3 && !cbor.TolerateExtraArrayElements {
+		return b, cbor.WrapDecodeError(cbor.ArrayError{Wanted: 3, Got: sz}, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		v := rest
+		switch i {
+		case 0:
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "Name", len(b)-len(v))
+				}
+			}
+
+			var tmp string
+			tmp, v, err = in.ReadStringBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "Name", len(b)-len(v))
+			}
+			x.Name = tmp
+		case 1:
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "Took", len(b)-len(v))
+				}
+			}
+
+			var tmp time.Duration
+			tmp, v, err = cbor.ReadDurationUnitBytes(v, time.Millisecond)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "Took", len(b)-len(v))
+			}
+			x.Took = tmp
+		case 2:
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "Retries", len(b)-len(v))
+				}
+			}
+
+			var tmp int
+			tmp, v, err = cbor.ReadIntBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "Retries", len(b)-len(v))
+			}
+			x.Retries = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "", len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if indef {
+		rest = rest[1:] // break
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *Step) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, indef, rest, err := cbor.ReadArraySizeBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if sz > 3 && !cbor.TolerateExtraArrayElements {
+		return b, cbor.ArrayError{Wanted: 3, Got: sz}
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		v := rest
+		switch i {
+		case 0:
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			x.Name, v, err = cbor.ReadTrustedStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+		case 1:
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp time.Duration
+			tmp, v, err = cbor.ReadDurationUnitBytes(v, time.Millisecond)
+			if err != nil {
+				return b, err
+			}
+			x.Took = tmp
+		case 2:
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp int
+			tmp, v, err = cbor.ReadIntBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Retries = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	if indef {
+		rest = rest[1:] // break
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *Step) resetCBOR() {
+	var zero Step
+	x.Name = zero.Name
+	x.Took = zero.Took
+	x.Retries = zero.Retries
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Step) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+// DiagString returns x in CBOR diagnostic notation (RFC 8949 §8), as
+// cbor.DiagBytes renders its encoding, for logs and test failures.
+func (x *Step) DiagString() string {
+	return string(x.appendDiag(nil))
+}
+
+// appendDiag appends x to b in diagnostic notation.
+func (x *Step) appendDiag(b []byte) []byte {
+	if x == nil {
+		return append(b, "null"...)
+	}
+	var count uint32
+	switch {
+	case !(x.Retries == 0):
+		count = 3
+	default:
+		count = 2
+	}
+	b = append(b, '[')
+	n := len(b)
+	b = strconv.AppendQuote(b, x.Name)
+	b = append(b, ", "...)
+	b = strconv.AppendInt(b, int64(x.Took/time.Millisecond), 10)
+	b = append(b, ", "...)
+	if !(count <= 2) {
+		b = strconv.AppendInt(b, int64(x.Retries), 10)
+		b = append(b, ", "...)
+	}
+	if len(b) > n {
+		b = b[:len(b)-2]
+	}
+	return append(b, ']')
+}
+
+func (x Gauge) Msgsize() (s int) {
+	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("Name") + cbor.StringPrefixSize + len(x.Name) + cbor.StringPrefixSize + len("Value") + cbor.Float64Size + cbor.StringPrefixSize + len("Unit") + cbor.StringPrefixSize + len(x.Unit) + cbor.BytesPrefixSize + 1
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *Gauge) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *Gauge) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *Gauge) AppendCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	present := [1]byte{0x04}
+	count := uint32(2)
+	if !(x.Name == "") {
+		present[0] |= 0x01
+		count++
+	}
+	if !(x.Value == 0) {
+		present[0] |= 0x02
+		count++
+	}
+	b = cbor.AppendArrayHeader(b, count)
+	b = cbor.AppendBytes(b, present[:])
+	var err error
+	if !(present[0]&0x01 == 0) {
+		b, err = cbor.AppendString(b, x.Name), nil
+		if err != nil {
+			return b, err
+		}
+	}
+	if !(present[0]&0x02 == 0) {
+		b, err = cbor.AppendFloat64(b, x.Value), nil
+		if err != nil {
+			return b, err
+		}
+	}
+	b, err = cbor.AppendString(b, x.Unit), nil
+	if err != nil {
+		return b, err
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *Gauge) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *Gauge) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, indef, rest, err := cbor.ReadArraySizeBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	present, rest, err := cbor.ReadPresenceBytes(rest, sz, 3)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := 0; i < len(present)*8; i++ {
+		if present[i/8]&(1<<(i%8)) == 0 {
+			continue
+		}
+		v := rest
+		switch i {
+		case 0:
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "Name", len(b)-len(v))
+				}
+			}
+
+			var tmp string
+			tmp, v, err = in.ReadStringBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "Name", len(b)-len(v))
+			}
+			x.Name = tmp
+		case 1:
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "Value", len(b)-len(v))
+				}
+			}
+
+			var tmp float64
+			tmp, v, err = cbor.ReadFloat64Bytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "Value", len(b)-len(v))
+			}
+			x.Value = tmp
+		case 2:
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "Unit", len(b)-len(v))
+				}
+			}
+
+			var tmp string
+			tmp, v, err = in.ReadStringBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "Unit", len(b)-len(v))
+			}
+			x.Unit = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "", len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if indef {
+		rest = rest[1:] // break
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *Gauge) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, indef, rest, err := cbor.ReadArraySizeBytes(b)
+	if err != nil {
+		return b, err
+	}
+	present, rest, err := cbor.ReadPresenceBytes(rest, sz, 3)
+	if err != nil {
+		return b, err
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := 0; i < len(present)*8; i++ {
+		if present[i/8]&(1<<(i%8)) == 0 {
+			continue
+		}
+		v := rest
+		switch i {
+		case 0:
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			x.Name, v, err = cbor.ReadTrustedStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+		case 1:
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp float64
+			tmp, v, err = cbor.ReadFloat64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Value = tmp
+		case 2:
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			x.Unit, v, err = cbor.ReadTrustedStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	if indef {
+		rest = rest[1:] // break
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *Gauge) resetCBOR() {
+	var zero Gauge
+	x.Name = zero.Name
+	x.Value = zero.Value
+	x.Unit = zero.Unit
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Gauge) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+// DiagString returns x in CBOR diagnostic notation (RFC 8949 §8), as
+// cbor.DiagBytes renders its encoding, for logs and test failures.
+func (x *Gauge) DiagString() string {
+	return string(x.appendDiag(nil))
+}
+
+// appendDiag appends x to b in diagnostic notation.
+func (x *Gauge) appendDiag(b []byte) []byte {
+	if x == nil {
+		return append(b, "null"...)
+	}
+	present := [1]byte{0x04}
+	count := uint32(2)
+	if !(x.Name == "") {
+		present[0] |= 0x01
+		count++
+	}
+	if !(x.Value == 0) {
+		present[0] |= 0x02
+		count++
+	}
+	b = append(b, '[')
+	_ = count
+	b = cbor.AppendDiagBytes(b, present[:])
+	b = append(b, ", "...)
+	n := len(b)
+	if !(present[0]&0x01 == 0) {
+		b = strconv.AppendQuote(b, x.Name)
+		b = append(b, ", "...)
+	}
+	if !(present[0]&0x02 == 0) {
+		b = cbor.AppendDiagFloat64(b, x.Value)
+		b = append(b, ", "...)
+	}
+	b = strconv.AppendQuote(b, x.Unit)
+	b = append(b, ", "...)
+	if len(b) > n {
+		b = b[:len(b)-2]
+	}
+	return append(b, ']')
+}
@@ -0,0 +1,77 @@
+package structs
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+// diagOf renders the encoding of m with the generic diagnostic walker.
+func diagOf(t *testing.T, m cbor.Marshaler) string {
+	t.Helper()
+	b, err := m.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	d, rest, err := cbor.DiagBytes(b)
+	if err != nil || len(rest) != 0 {
+		t.Fatalf("DiagBytes error: %v (%d bytes left)", err, len(rest))
+	}
+	return d
+}
+
+func TestDiagStringMatchesEncoding(t *testing.T) {
+	note := "paged"
+	cases := []*Incident{
+		{},
+		{
+			ID:       42,
+			Title:    "disk \"full\"",
+			Severity: 2.5,
+			Tags:     []string{"db", "prod"},
+			Payload:  []byte{0xde, 0xad},
+			Owner:    &Person{Name: "Ada", Age: 36},
+			Steps: []Step{
+				{Name: "page", Took: 1500 * time.Millisecond},
+				{Name: "fix", Took: time.Minute, Retries: 2},
+			},
+			Parent:   &Incident{ID: 7, Title: "root"},
+			Labels:   map[string]int{"p": 1},
+			At:       time.Unix(1700000000, 0).UTC(),
+			Note:     &note,
+			Link:     "https://example.com/i/42",
+			Closed:   time.Unix(1700000600, 250e6),
+			Opened:   time.Date(2023, 11, 14, 14, 13, 20, 0, time.FixedZone("", -8*3600)),
+			Teams:    map[string]struct{}{"sre": {}},
+			Source:   net.ParseIP("192.0.2.1"),
+			Priority: 0.1,
+		},
+	}
+	for _, in := range cases {
+		if got, want := in.DiagString(), diagOf(t, in); got != want {
+			t.Fatalf("DiagString() = %s\nwant %s", got, want)
+		}
+	}
+
+	var nilIncident *Incident
+	if got := nilIncident.DiagString(); got != "null" {
+		t.Fatalf("nil DiagString() = %s, want null", got)
+	}
+}
+
+func TestDiagStringReadable(t *testing.T) {
+	s := Step{Name: "page", Took: 1500 * time.Millisecond}
+	if got, want := s.DiagString(), `["page", 1500]`; got != want {
+		t.Fatalf("DiagString() = %s, want %s", got, want)
+	}
+}
+
+func TestDiagStringPresence(t *testing.T) {
+	for _, in := range []*Gauge{{}, {Name: "g", Unit: "C"}, {Value: 0.5}} {
+		if got, want := in.DiagString(), diagOf(t, in); got != want {
+			t.Fatalf("DiagString() = %s, want %s", got, want)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package structs
+
+import (
+	"errors"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+// personRepeatingName encodes a Person map whose "name" key appears twice.
+func personRepeatingName() []byte {
+	var b []byte
+	b = cbor.AppendMapHeader(b, 3)
+	b = cbor.AppendString(b, "name")
+	b = cbor.AppendString(b, "first")
+	b = cbor.AppendString(b, "age")
+	b = cbor.AppendInt(b, 36)
+	b = cbor.AppendString(b, "name")
+	return cbor.AppendString(b, "last")
+}
+
+func TestDuplicateStructKeyPolicy(t *testing.T) {
+	b := personRepeatingName()
+
+	setOption(t, &cbor.DuplicateMapKeys, cbor.DuplicateKeyError)
+	var p Person
+	_, err := p.DecodeSafe(b)
+	if !errors.Is(err, cbor.ErrDuplicateMapKey) || !containsPath(err, "name") {
+		t.Fatalf("Error policy: error = %v, want ErrDuplicateMapKey at name", err)
+	}
+
+	for policy, want := range map[cbor.DuplicateKeyPolicy]string{cbor.DuplicateKeyFirst: "first", cbor.DuplicateKeyLast: "last"} {
+		setOption(t, &cbor.DuplicateMapKeys, policy)
+		var p Person
+		rest, err := p.DecodeSafe(b)
+		if err != nil || len(rest) != 0 || p.Name != want || p.Age != 36 {
+			t.Fatalf("policy %d: got %+v, rest=%d, err=%v; want name %q", policy, p, len(rest), err, want)
+		}
+	}
+
+	// Trusted decoders do not track keys and keep the last value.
+	setOption(t, &cbor.DuplicateMapKeys, cbor.DuplicateKeyError)
+	p = Person{}
+	if _, err := p.DecodeTrusted(b); err != nil || p.Name != "last" {
+		t.Fatalf("DecodeTrusted: got %+v, err=%v", p, err)
+	}
+}
+
+func TestDuplicateIntAndAliasKeys(t *testing.T) {
+	setOption(t, &cbor.DuplicateMapKeys, cbor.DuplicateKeyError)
+	var b []byte
+	b = cbor.AppendMapHeader(b, 3)
+	b = cbor.AppendInt64(b, 1)
+	b = cbor.AppendInt64(b, 2)
+	b = cbor.AppendInt64(b, -1)
+	b = cbor.AppendInt64(b, 1)
+	b = cbor.AppendInt64(b, 1)
+	b = cbor.AppendInt64(b, 4)
+	var k CoseKey
+	if _, err := k.DecodeSafe(b); !errors.Is(err, cbor.ErrDuplicateMapKey) {
+		t.Fatalf("keyasint: error = %v, want ErrDuplicateMapKey", err)
+	}
+
+	// An alias names the same field as its primary key.
+	b = cbor.AppendMapHeader(nil, 2)
+	b = cbor.AppendString(b, "display_name")
+	b = cbor.AppendString(b, "Ada")
+	b = cbor.AppendString(b, "nick")
+	b = cbor.AppendString(b, "ada")
+	var pr Profile
+	if _, err := pr.DecodeSafe(b); !errors.Is(err, cbor.ErrDuplicateMapKey) {
+		t.Fatalf("alias: error = %v, want ErrDuplicateMapKey", err)
+	}
+	setOption(t, &cbor.DuplicateMapKeys, cbor.DuplicateKeyFirst)
+	pr = Profile{}
+	if _, err := pr.DecodeSafe(b); err != nil || pr.DisplayName != "Ada" {
+		t.Fatalf("alias, First policy: got %+v, err=%v", pr, err)
+	}
+}
+
+func TestDuplicateMapFieldPolicy(t *testing.T) {
+	var b []byte
+	b = cbor.AppendMapHeader(b, 1)
+	b = cbor.AppendString(b, "seqs")
+	b = cbor.AppendMapHeader(b, 2)
+	b = cbor.AppendString(b, "a")
+	b = append(b, 0x81, 0x01) // [1]
+	b = cbor.AppendString(b, "a")
+	b = append(b, 0x82, 0x02, 0x03) // [2, 3]
+
+	for policy, want := range map[cbor.DuplicateKeyPolicy]int{cbor.DuplicateKeyFirst: 1, cbor.DuplicateKeyLast: 2} {
+		setOption(t, &cbor.DuplicateMapKeys, policy)
+		var s Stream
+		rest, err := s.DecodeSafe(b)
+		if err != nil || len(rest) != 0 || len(s.Seqs["a"]) != want {
+			t.Fatalf("policy %d: got %v, rest=%d, err=%v", policy, s.Seqs, len(rest), err)
+		}
+	}
+}
+
+func TestDuplicateKeyInterfaceAndReflect(t *testing.T) {
+	// {"k": 1, 2: true, "k": 3, 2: false}
+	var b []byte
+	b = cbor.AppendMapHeader(b, 4)
+	b = cbor.AppendString(b, "k")
+	b = cbor.AppendInt(b, 1)
+	b = cbor.AppendInt(b, 2)
+	b = cbor.AppendBool(b, true)
+	b = cbor.AppendString(b, "k")
+	b = cbor.AppendInt(b, 3)
+	b = cbor.AppendInt(b, 2)
+	b = cbor.AppendBool(b, false)
+
+	setOption(t, &cbor.DuplicateMapKeys, cbor.DuplicateKeyError)
+	if _, _, err := cbor.ReadInterfaceBytes(b); !errors.Is(err, cbor.ErrDuplicateMapKey) {
+		t.Fatalf("ReadInterfaceBytes error = %v, want ErrDuplicateMapKey", err)
+	}
+	var m map[any]any
+	if err := cbor.Unmarshal(b, &m); !errors.Is(err, cbor.ErrDuplicateMapKey) {
+		t.Fatalf("Unmarshal map error = %v, want ErrDuplicateMapKey", err)
+	}
+
+	for policy, want := range map[cbor.DuplicateKeyPolicy][2]any{
+		cbor.DuplicateKeyFirst: {uint64(1), true},
+		cbor.DuplicateKeyLast:  {uint64(3), false},
+	} {
+		setOption(t, &cbor.DuplicateMapKeys, policy)
+		v, rest, err := cbor.ReadInterfaceBytes(b)
+		got, _ := v.(map[any]any)
+		if err != nil || len(rest) != 0 || len(got) != 2 || got["k"] != want[0] || got[uint64(2)] != want[1] {
+			t.Fatalf("policy %d: ReadInterfaceBytes = %v, %v", policy, v, err)
+		}
+		// Entries already in the map are not duplicates.
+		m := map[any]any{"k": "old"}
+		if err := cbor.Unmarshal(b, &m); err != nil || m["k"] != want[0] {
+			t.Fatalf("policy %d: Unmarshal = %v, %v", policy, m, err)
+		}
+	}
+
+	type plain Person // no generated methods
+	setOption(t, &cbor.DuplicateMapKeys, cbor.DuplicateKeyError)
+	var p plain
+	if err := cbor.Unmarshal(personRepeatingName(), &p); !errors.Is(err, cbor.ErrDuplicateMapKey) {
+		t.Fatalf("Unmarshal struct error = %v, want ErrDuplicateMapKey", err)
+	}
+	setOption(t, &cbor.DuplicateMapKeys, cbor.DuplicateKeyFirst)
+	if err := cbor.Unmarshal(personRepeatingName(), &p); err != nil || p.Name != "first" {
+		t.Fatalf("Unmarshal struct, First policy: got %+v, err=%v", p, err)
+	}
+}
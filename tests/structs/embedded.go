@@ -0,0 +1,11 @@
+package structs
+
+import cbor "github.com/delaneyj/cbor/runtime"
+
+// Document exercises tag 24 (encoded CBOR data item) on raw fields.
+type Document struct {
+	Kind  string          `cbor:"kind"`
+	Body  cbor.RawMessage `cbor:"body,tag=24"`
+	Sig   cbor.Raw        `cbor:"sig,tag=24"`
+	Extra cbor.RawMessage `cbor:"extra"`
+}
@@ -0,0 +1,101 @@
+package structs
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+type documentDecoder struct {
+	name   string
+	decode func(dst *Document, b []byte) ([]byte, error)
+}
+
+var documentDecoders = []documentDecoder{
+	{
+		name:   "DecodeSafe",
+		decode: (*Document).DecodeSafe,
+	},
+	{
+		name:   "DecodeTrusted",
+		decode: (*Document).DecodeTrusted,
+	},
+}
+
+func TestDocumentEmbeddedCBORRoundTrip(t *testing.T) {
+	inner := &Person{Name: "Ada", Age: 36}
+	body, err := inner.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	orig := &Document{Kind: "person", Body: body, Extra: cbor.RawMessage{0x01}}
+
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	// tag 24 (0xd8 0x18) + byte string header + the encoded person.
+	want := cbor.AppendEmbeddedCBOR(nil, body)
+	if want[0] != 0xd8 || want[1] != 24 || !bytes.Contains(b, want) {
+		t.Fatalf("missing tag 24 item % x in % x", want, b)
+	}
+
+	for _, tc := range documentDecoders {
+		t.Run(tc.name, func(t *testing.T) {
+			dst := Document{Sig: cbor.Raw{0xff}}
+			rest, err := tc.decode(&dst, b)
+			if err != nil {
+				t.Fatalf("%s error: %v", tc.name, err)
+			}
+			if len(rest) != 0 {
+				t.Fatalf("%s leftover bytes: %d", tc.name, len(rest))
+			}
+			if dst.Kind != "person" || !bytes.Equal(dst.Body, body) || dst.Sig != nil || !bytes.Equal(dst.Extra, orig.Extra) {
+				t.Fatalf("%s mismatch: got %+v want %+v", tc.name, dst, *orig)
+			}
+			// The captured payload is a copy, not a view of b.
+			if len(dst.Body) > 0 && &dst.Body[0] == &b[bytes.Index(b, body)] {
+				t.Fatalf("%s: Body aliases the input buffer", tc.name)
+			}
+			var p Person
+			if _, err := p.DecodeSafe(dst.Body); err != nil || p.Name != "Ada" || p.Age != 36 {
+				t.Fatalf("%s: inner decode = %+v, %v", tc.name, p, err)
+			}
+		})
+	}
+}
+
+func TestDocumentEmbeddedCBORValidation(t *testing.T) {
+	build := func(payload []byte) []byte {
+		var b []byte
+		b = cbor.AppendMapHeader(b, 1)
+		b = cbor.AppendString(b, "body")
+		return cbor.AppendEmbeddedCBOR(b, payload)
+	}
+	cases := []struct {
+		name    string
+		payload []byte
+		want    error
+	}{
+		{"truncated", []byte{0x82, 0x01}, cbor.ErrShortBytes},
+		{"trailing", []byte{0x01, 0x02}, cbor.ErrTrailingBytes},
+		{"reserved_head", []byte{0x1c}, cbor.ErrMalformedHead},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var dst Document
+			if _, err := dst.DecodeSafe(build(tc.payload)); !errors.Is(err, tc.want) {
+				t.Fatalf("DecodeSafe error = %v, want %v", err, tc.want)
+			}
+			// The Trusted path captures the payload unchecked.
+			if _, err := dst.DecodeTrusted(build(tc.payload)); err != nil {
+				t.Fatalf("DecodeTrusted error: %v", err)
+			}
+			if !bytes.Equal(dst.Body, tc.payload) {
+				t.Fatalf("DecodeTrusted Body = % x, want % x", dst.Body, tc.payload)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package structs
+
+import "time"
+
+// Audit is embedded by pointer in Issue: its keys sit beside Issue's
+// own while it is set and are left out while it is nil.
+type Audit struct {
+	CreatedBy string    `cbor:"created_by"`
+	CreatedAt time.Time `cbor:"created_at,omitempty"`
+	Tags      []string  `cbor:"tags,omitempty"`
+	*Origin
+}
+
+// Origin is embedded by pointer one level deeper, through Audit.
+type Origin struct {
+	Host string `cbor:"host"`
+	Port int    `cbor:"port,omitempty"`
+}
+
+// Labels is embedded by value, which always contributes its keys.
+type Labels struct {
+	Team string `cbor:"team,omitempty"`
+}
+
+// Issue exercises embedded structs: Audit and Origin through pointers
+// and Labels by value. Owner embeds Origin under a key of its own.
+type Issue struct {
+	ID string `cbor:"id"`
+	*Audit
+	Labels
+	Owner *Origin `cbor:"owner,omitempty"`
+}
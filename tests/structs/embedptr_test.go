@@ -0,0 +1,133 @@
+package structs
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+var issueDecoders = map[string]func(*Issue, []byte) ([]byte, error){
+	"DecodeSafe":    (*Issue).DecodeSafe,
+	"DecodeTrusted": (*Issue).DecodeTrusted,
+}
+
+func TestIssueEmbeddedPointers(t *testing.T) {
+	cases := []struct {
+		name string
+		in   *Issue
+		want string
+	}{
+		{"nil", &Issue{ID: "i1"}, `{"id": "i1"}`},
+		{"nil_inner", &Issue{ID: "i2", Audit: &Audit{CreatedBy: "ann"}, Labels: Labels{Team: "core"}},
+			`{"id": "i2", "created_by": "ann", "team": "core"}`},
+		{"set", &Issue{ID: "i3", Audit: &Audit{CreatedBy: "bo", Tags: []string{"p1"}, Origin: &Origin{Host: "h", Port: 80}}},
+			`{"id": "i3", "created_by": "bo", "tags": ["p1"], "host": "h", "port": 80}`},
+		{"named", &Issue{ID: "i4", Owner: &Origin{Host: "o"}}, `{"id": "i4", "owner": {"host": "o"}}`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := tc.in.MarshalCBOR(nil)
+			if err != nil {
+				t.Fatalf("MarshalCBOR: %v", err)
+			}
+			if len(b) > tc.in.Msgsize() {
+				t.Fatalf("encoded %d bytes, Msgsize %d", len(b), tc.in.Msgsize())
+			}
+			if got := diagOf(t, tc.in); got != tc.want {
+				t.Fatalf("encoding = %s, want %s", got, tc.want)
+			}
+			if got := tc.in.DiagString(); got != tc.want {
+				t.Fatalf("DiagString() = %s, want %s", got, tc.want)
+			}
+
+			var w bytes.Buffer
+			enc := cbor.NewEncoder(&w)
+			if err := tc.in.MarshalCBORStream(enc); err != nil {
+				t.Fatalf("MarshalCBORStream: %v", err)
+			}
+			if err := enc.Flush(); err != nil {
+				t.Fatalf("Flush: %v", err)
+			}
+			// Slices are streamed as indefinite-length arrays.
+			var streamed Issue
+			if _, err := streamed.DecodeSafe(w.Bytes()); err != nil || !reflect.DeepEqual(&streamed, tc.in) {
+				t.Fatalf("MarshalCBORStream wrote % x, decoded as %+v, %v", w.Bytes(), streamed, err)
+			}
+
+			// A nil pointer stays nil: no key of it was decoded.
+			for name, decode := range issueDecoders {
+				var out Issue
+				if _, err := decode(&out, b); err != nil {
+					t.Fatalf("%s: %v", name, err)
+				}
+				if !reflect.DeepEqual(&out, tc.in) {
+					t.Fatalf("%s: got %+v, want %+v", name, out, *tc.in)
+				}
+			}
+
+			type plain Issue // no generated methods
+			got, err := cbor.Marshal((*plain)(tc.in))
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			if !bytes.Equal(got, b) {
+				t.Fatalf("Marshal = % x, want % x", got, b)
+			}
+			var out plain
+			if err := cbor.Unmarshal(b, &out); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if !reflect.DeepEqual((*Issue)(&out), tc.in) {
+				t.Fatalf("Unmarshal = %+v, want %+v", out, *tc.in)
+			}
+		})
+	}
+}
+
+func TestIssueDecodeAllocatesEmbedded(t *testing.T) {
+	// {"id": "i", "port": 8080}: only a key of Origin, two pointers deep.
+	b := cbor.AppendMapHeader(nil, 2)
+	b = cbor.AppendString(b, "id")
+	b = cbor.AppendString(b, "i")
+	b = cbor.AppendString(b, "port")
+	b = cbor.AppendInt(b, 8080)
+	want := &Issue{ID: "i", Audit: &Audit{Origin: &Origin{Port: 8080}}}
+	for name, decode := range issueDecoders {
+		var out Issue
+		if _, err := decode(&out, b); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !reflect.DeepEqual(&out, want) {
+			t.Fatalf("%s: got %+v, want %+v", name, out, *want)
+		}
+	}
+}
+
+func TestIssueResetKeepsEmbedded(t *testing.T) {
+	cbor.ResetBeforeDecode = true
+	defer func() { cbor.ResetBeforeDecode = false }()
+
+	audit := &Audit{CreatedBy: "old", Tags: []string{"stale"}}
+	out := Issue{ID: "old", Audit: audit}
+	b, err := (&Issue{ID: "new"}).MarshalCBOR(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := out.DecodeSafe(b); err != nil {
+		t.Fatal(err)
+	}
+	// The pointee is reused and cleared, as for other pointer fields.
+	if out.Audit != audit || out.CreatedBy != "" || len(out.Tags) != 0 || out.ID != "new" {
+		t.Fatalf("got %+v, audit %+v", out, *out.Audit)
+	}
+}
+
+func TestIssueCloneCopiesEmbedded(t *testing.T) {
+	in := &Issue{ID: "c", Audit: &Audit{CreatedBy: "x", Origin: &Origin{Host: "h"}}}
+	c := in.Clone()
+	if c.Audit == in.Audit || c.Origin == in.Origin || !reflect.DeepEqual(c, in) {
+		t.Fatalf("Clone = %+v shares embedded pointers with %+v", c, in)
+	}
+}
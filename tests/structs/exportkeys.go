@@ -0,0 +1,26 @@
+package structs
+
+// Meter is generated with --export-keys to exercise the key tables.
+type Meter struct {
+	ID     uint64  `cbor:"1,keyasint"`
+	Scale  int     `cbor:"2,keyasint,omitempty"`
+	Name   string  `cbor:"name"`
+	Unit   string  `cbor:"unit,omitempty"`
+	Value  float64 `json:"value"`
+	Labels []string
+	Window MeterWindow `cbor:",inline"`
+	secret string
+	Skip   string `cbor:"-"`
+}
+
+// MeterWindow is inlined into Meter.
+type MeterWindow struct {
+	From int64 `cbor:"from"`
+	To   int64 `cbor:"to,omitzero"`
+}
+
+// MeterRow is a toarray struct, which has no keys to export.
+type MeterRow struct {
+	_    struct{} `cbor:",toarray"`
+	Name string
+}
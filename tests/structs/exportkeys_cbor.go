@@ -0,0 +1,945 @@
+// Code generated by cborgen DO NOT EDIT.
+
+package structs
+
+import (
+	"io"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+// Fail the build if a generated method drifts from the runtime interfaces.
+var (
+	_ cbor.Marshaler   = (*Meter)(nil)
+	_ cbor.Unmarshaler = (*Meter)(nil)
+	_ cbor.Marshaler   = (*MeterWindow)(nil)
+	_ cbor.Unmarshaler = (*MeterWindow)(nil)
+	_ cbor.Marshaler   = (*MeterRow)(nil)
+	_ cbor.Unmarshaler = (*MeterRow)(nil)
+)
+
+func (x Meter) Msgsize() (s int) {
+	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("1") + cbor.Uint64Size + cbor.StringPrefixSize + len("2") + cbor.IntSize + cbor.StringPrefixSize + len("name") + cbor.StringPrefixSize + len(x.Name) + cbor.StringPrefixSize + len("unit") + cbor.StringPrefixSize + len(x.Unit) + cbor.StringPrefixSize + len("value") + cbor.Float64Size + cbor.StringPrefixSize + len("Labels") + cbor.ArrayHeaderSize + len(x.Labels)*cbor.StringPrefixSize + cbor.StringPrefixSize + len("from") + cbor.Int64Size + cbor.StringPrefixSize + len("to") + cbor.Int64Size
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *Meter) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *Meter) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *Meter) AppendCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	count := uint32(0)
+	count++
+	if !(x.Scale == 0) {
+		count++
+	}
+	count++
+	if !(x.Unit == "") {
+		count++
+	}
+	count++
+	count++
+	count++
+	if !(x.Window.To == 0) {
+		count++
+	}
+	b = cbor.AppendMapHeader(b, count)
+	var err error
+	b = cbor.AppendInt64(b, 1)
+	b, err = cbor.AppendUint64(b, x.ID), nil
+	if err != nil {
+		return b, err
+	}
+	if !(x.Scale == 0) {
+		b = cbor.AppendInt64(b, 2)
+		b, err = cbor.AppendInt(b, x.Scale), nil
+		if err != nil {
+			return b, err
+		}
+	}
+	b = cbor.AppendString(b, "name")
+	b, err = cbor.AppendString(b, x.Name), nil
+	if err != nil {
+		return b, err
+	}
+	if !(x.Unit == "") {
+		b = cbor.AppendString(b, "unit")
+		b, err = cbor.AppendString(b, x.Unit), nil
+		if err != nil {
+			return b, err
+		}
+	}
+	b = cbor.AppendString(b, "value")
+	b, err = cbor.AppendFloat64(b, x.Value), nil
+	if err != nil {
+		return b, err
+	}
+	if x.Labels == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = cbor.AppendString(b, "Labels")
+		b = cbor.AppendNil(b)
+	} else {
+		b = cbor.AppendString(b, "Labels")
+		b = cbor.AppendArrayHeader(b, uint32(len(x.Labels)))
+		for _, v := range x.Labels {
+			b = cbor.AppendString(b, v)
+		}
+	}
+	b = cbor.AppendString(b, "from")
+	b, err = cbor.AppendInt64(b, x.Window.From), nil
+	if err != nil {
+		return b, err
+	}
+	if !(x.Window.To == 0) {
+		b = cbor.AppendString(b, "to")
+		b, err = cbor.AppendInt64(b, x.Window.To), nil
+		if err != nil {
+			return b, err
+		}
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *Meter) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *Meter) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
+	for i := uint32(0); i < sz; i++ {
+		if t := cbor.NextType(rest); t == cbor.UintType || t == cbor.IntType {
+			ikey, fits, v, err := cbor.ReadIntKeyBytes(rest)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+			}
+			if !fits {
+				// No field has a key outside the int64 range.
+				if rest, err = cbor.Skip(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "", len(b)-len(v))
+				}
+				continue
+			}
+			switch ikey {
+			case 1:
+				if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+					if err != nil {
+						return b, cbor.WrapDecodeError(err, "1", len(b)-len(v))
+					}
+					v = o
+					break
+				}
+				if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+					if v, err = cbor.UntagBytes(v); err != nil {
+						return b, cbor.WrapDecodeError(err, "1", len(b)-len(v))
+					}
+				}
+
+				var tmp uint64
+				tmp, v, err = cbor.ReadUint64Bytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "1", len(b)-len(v))
+				}
+				x.ID = tmp
+			case 2:
+				if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+					if err != nil {
+						return b, cbor.WrapDecodeError(err, "2", len(b)-len(v))
+					}
+					v = o
+					break
+				}
+				if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+					if v, err = cbor.UntagBytes(v); err != nil {
+						return b, cbor.WrapDecodeError(err, "2", len(b)-len(v))
+					}
+				}
+
+				var tmp int
+				tmp, v, err = cbor.ReadIntBytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "2", len(b)-len(v))
+				}
+				x.Scale = tmp
+			default:
+				v, err = cbor.Skip(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "", len(b)-len(v))
+				}
+			}
+			rest = v
+			continue
+		}
+		key, v, err := in.ReadStringBytes(rest)
+		if err != nil {
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+		}
+		switch key {
+		case "name":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 2); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
+				}
+			}
+
+			var tmp string
+			tmp, v, err = in.ReadStringBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
+			}
+			x.Name = tmp
+		case "unit":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 3); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "unit", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "unit", len(b)-len(v))
+				}
+			}
+
+			var tmp string
+			tmp, v, err = in.ReadStringBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "unit", len(b)-len(v))
+			}
+			x.Unit = tmp
+		case "value":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 4); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "value", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "value", len(b)-len(v))
+				}
+			}
+
+			var tmp float64
+			tmp, v, err = cbor.ReadFloat64Bytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "value", len(b)-len(v))
+			}
+			x.Value = tmp
+		case "Labels":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 5); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "Labels", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "Labels", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Labels = cbor.NullSlice(x.Labels)
+				break
+			}
+			var sz uint32
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "Labels", len(b)-len(v))
+			}
+			if cap(x.Labels) >= int(sz) {
+				x.Labels = x.Labels[:sz]
+			} else {
+				x.Labels = make([]string, sz)
+			}
+			if sz > 0 {
+				_ = x.Labels[sz-1]
+			}
+			for iLabels := uint32(0); iLabels < sz; iLabels++ {
+				var tmp string
+				tmp, v, err = in.ReadStringBytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, int(iLabels)), "Labels", len(b)-len(v))
+				}
+				x.Labels[iLabels] = tmp
+			}
+			if indef {
+				v = v[1:] // break
+			}
+		case "from":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 6); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "from", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "from", len(b)-len(v))
+				}
+			}
+
+			var tmp int64
+			tmp, v, err = cbor.ReadInt64Bytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "from", len(b)-len(v))
+			}
+			x.Window.From = tmp
+		case "to":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 7); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "to", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "to", len(b)-len(v))
+				}
+			}
+
+			var tmp int64
+			tmp, v, err = cbor.ReadInt64Bytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "to", len(b)-len(v))
+			}
+			x.Window.To = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *Meter) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		if t := cbor.NextType(rest); t == cbor.UintType || t == cbor.IntType {
+			ikey, fits, v, err := cbor.ReadIntKeyBytes(rest)
+			if err != nil {
+				return b, err
+			}
+			if !fits {
+				if rest, err = cbor.Skip(v); err != nil {
+					return b, err
+				}
+				continue
+			}
+			switch ikey {
+			case 1:
+				if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+					if v, err = cbor.UntagBytes(v); err != nil {
+						return b, err
+					}
+				}
+
+				var tmp uint64
+				tmp, v, err = cbor.ReadUint64Bytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.ID = tmp
+			case 2:
+				if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+					if v, err = cbor.UntagBytes(v); err != nil {
+						return b, err
+					}
+				}
+
+				var tmp int
+				tmp, v, err = cbor.ReadIntBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Scale = tmp
+			default:
+				v, err = cbor.Skip(v)
+				if err != nil {
+					return b, err
+				}
+			}
+			rest = v
+			continue
+		}
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "name":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			x.Name, v, err = cbor.ReadTrustedStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+		case "unit":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			x.Unit, v, err = cbor.ReadTrustedStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+		case "value":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp float64
+			tmp, v, err = cbor.ReadFloat64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Value = tmp
+		case "Labels":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Labels = cbor.NullSlice(x.Labels)
+				break
+			}
+			var sz uint32
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap(x.Labels) >= int(sz) {
+				x.Labels = x.Labels[:sz]
+			} else {
+				x.Labels = make([]string, sz)
+			}
+			if sz > 0 {
+				_ = x.Labels[sz-1]
+			}
+			for iLabels := uint32(0); iLabels < sz; iLabels++ {
+				var tmp string
+				tmp, v, err = cbor.ReadTrustedStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Labels[iLabels] = tmp
+			}
+			if indef {
+				v = v[1:] // break
+			}
+		case "from":
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp int64
+			tmp, v, err = cbor.ReadInt64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Window.From = tmp
+		case "to":
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp int64
+			tmp, v, err = cbor.ReadInt64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Window.To = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *Meter) resetCBOR() {
+	var zero Meter
+	x.ID = zero.ID
+	x.Scale = zero.Scale
+	x.Name = zero.Name
+	x.Unit = zero.Unit
+	x.Value = zero.Value
+	x.Labels = x.Labels[:0]
+	x.Window.From = zero.Window.From
+	x.Window.To = zero.Window.To
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Meter) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+// MeterFieldKeys lists the text keys of Meter in encoding order;
+// MeterOmitEmptyKeys lists those left out when their field is empty.
+var (
+	MeterFieldKeys     = []string{"name", "unit", "value", "Labels", "from", "to"}
+	MeterOmitEmptyKeys = []string{"unit", "to"}
+)
+
+// MeterFieldIntKeys lists the keyasint keys of Meter in encoding
+// order; MeterOmitEmptyIntKeys lists those left out when their field
+// is empty.
+var (
+	MeterFieldIntKeys     = []int64{1, 2}
+	MeterOmitEmptyIntKeys = []int64{2}
+)
+
+func (x MeterWindow) Msgsize() (s int) {
+	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("from") + cbor.Int64Size + cbor.StringPrefixSize + len("to") + cbor.Int64Size
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *MeterWindow) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *MeterWindow) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *MeterWindow) AppendCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	count := uint32(0)
+	count++
+	if !(x.To == 0) {
+		count++
+	}
+	b = cbor.AppendMapHeader(b, count)
+	var err error
+	b = cbor.AppendString(b, "from")
+	b, err = cbor.AppendInt64(b, x.From), nil
+	if err != nil {
+		return b, err
+	}
+	if !(x.To == 0) {
+		b = cbor.AppendString(b, "to")
+		b, err = cbor.AppendInt64(b, x.To), nil
+		if err != nil {
+			return b, err
+		}
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *MeterWindow) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *MeterWindow) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := in.ReadStringBytes(rest)
+		if err != nil {
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+		}
+		switch key {
+		case "from":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "from", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "from", len(b)-len(v))
+				}
+			}
+
+			var tmp int64
+			tmp, v, err = cbor.ReadInt64Bytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "from", len(b)-len(v))
+			}
+			x.From = tmp
+		case "to":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "to", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "to", len(b)-len(v))
+				}
+			}
+
+			var tmp int64
+			tmp, v, err = cbor.ReadInt64Bytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "to", len(b)-len(v))
+			}
+			x.To = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *MeterWindow) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "from":
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp int64
+			tmp, v, err = cbor.ReadInt64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.From = tmp
+		case "to":
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp int64
+			tmp, v, err = cbor.ReadInt64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.To = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *MeterWindow) resetCBOR() {
+	var zero MeterWindow
+	x.From = zero.From
+	x.To = zero.To
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *MeterWindow) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+// MeterWindowFieldKeys lists the text keys of MeterWindow in encoding order;
+// MeterWindowOmitEmptyKeys lists those left out when their field is empty.
+var (
+	MeterWindowFieldKeys     = []string{"from", "to"}
+	MeterWindowOmitEmptyKeys = []string{"to"}
+)
+
+func (x MeterRow) Msgsize() (s int) {
+	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("Name") + cbor.StringPrefixSize + len(x.Name)
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *MeterRow) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *MeterRow) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *MeterRow) AppendCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	b = cbor.AppendArrayHeader(b, uint32(1))
+	var err error
+	b, err = cbor.AppendString(b, x.Name), nil
+	if err != nil {
+		return b, err
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *MeterRow) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *MeterRow) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, indef, rest, err := cbor.ReadArraySizeBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if sz > 1 && !cbor.TolerateExtraArrayElements {
+		return b, cbor.WrapDecodeError(cbor.ArrayError{Wanted: 1, Got: sz}, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		v := rest
+		switch i {
+		case 0:
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "Name", len(b)-len(v))
+				}
+			}
+
+			var tmp string
+			tmp, v, err = in.ReadStringBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "Name", len(b)-len(v))
+			}
+			x.Name = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "", len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if indef {
+		rest = rest[1:] // break
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *MeterRow) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, indef, rest, err := cbor.ReadArraySizeBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if sz > 1 && !cbor.TolerateExtraArrayElements {
+		return b, cbor.ArrayError{Wanted: 1, Got: sz}
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		v := rest
+		switch i {
+		case 0:
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			x.Name, v, err = cbor.ReadTrustedStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	if indef {
+		rest = rest[1:] // break
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *MeterRow) resetCBOR() {
+	var zero MeterRow
+	x.Name = zero.Name
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *MeterRow) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
@@ -0,0 +1,72 @@
+package structs
+
+import (
+	"slices"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+func TestExportedKeyTables(t *testing.T) {
+	if want := []string{"name", "unit", "value", "Labels", "from", "to"}; !slices.Equal(MeterFieldKeys, want) {
+		t.Errorf("MeterFieldKeys = %q, want %q", MeterFieldKeys, want)
+	}
+	if want := []string{"unit", "to"}; !slices.Equal(MeterOmitEmptyKeys, want) {
+		t.Errorf("MeterOmitEmptyKeys = %q, want %q", MeterOmitEmptyKeys, want)
+	}
+	if want := []int64{1, 2}; !slices.Equal(MeterFieldIntKeys, want) {
+		t.Errorf("MeterFieldIntKeys = %v, want %v", MeterFieldIntKeys, want)
+	}
+	if want := []int64{2}; !slices.Equal(MeterOmitEmptyIntKeys, want) {
+		t.Errorf("MeterOmitEmptyIntKeys = %v, want %v", MeterOmitEmptyIntKeys, want)
+	}
+}
+
+// keysOf returns the text and integer keys of the encoding of g.
+func keysOf(t *testing.T, g *Meter) (text []string, ints []int64) {
+	t.Helper()
+	b, err := g.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	var m map[any]any
+	if err := cbor.Unmarshal(b, &m); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	for k := range m {
+		switch k := k.(type) {
+		case string:
+			text = append(text, k)
+		case int64:
+			ints = append(ints, k)
+		case uint64:
+			ints = append(ints, int64(k))
+		default:
+			t.Fatalf("unexpected key %#v", k)
+		}
+	}
+	slices.Sort(text)
+	slices.Sort(ints)
+	return text, ints
+}
+
+func TestExportedKeysMatchEncoding(t *testing.T) {
+	full := &Meter{ID: 1, Scale: 3, Name: "cpu", Unit: "%", Value: 0.5, Labels: []string{"host"}, Window: MeterWindow{From: 10, To: 20}}
+	text, ints := keysOf(t, full)
+	if want := slices.Sorted(slices.Values(MeterFieldKeys)); !slices.Equal(text, want) {
+		t.Errorf("full Meter text keys = %q, want %q", text, want)
+	}
+	if want := slices.Sorted(slices.Values(MeterFieldIntKeys)); !slices.Equal(ints, want) {
+		t.Errorf("full Meter int keys = %v, want %v", ints, want)
+	}
+
+	text, ints = keysOf(t, &Meter{})
+	wantText := slices.DeleteFunc(slices.Clone(MeterFieldKeys), func(k string) bool { return slices.Contains(MeterOmitEmptyKeys, k) })
+	if slices.Sort(wantText); !slices.Equal(text, wantText) {
+		t.Errorf("zero Meter text keys = %q, want %q", text, wantText)
+	}
+	wantInts := slices.DeleteFunc(slices.Clone(MeterFieldIntKeys), func(k int64) bool { return slices.Contains(MeterOmitEmptyIntKeys, k) })
+	if slices.Sort(wantInts); !slices.Equal(ints, wantInts) {
+		t.Errorf("zero Meter int keys = %v, want %v", ints, wantInts)
+	}
+}
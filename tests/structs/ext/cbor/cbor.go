@@ -0,0 +1,10 @@
+// Package cbor stands in for another package named cbor, such as
+// fxamacker/cbor, in the crosspkg fixture: generated code importing it
+// must call the runtime by another name.
+package cbor
+
+// Version is a schema version.
+type Version struct {
+	Major int `cbor:"major"`
+	Minor int `cbor:"minor"`
+}
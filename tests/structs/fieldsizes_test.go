@@ -0,0 +1,47 @@
+package structs
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+func TestFieldSizes(t *testing.T) {
+	r := Reading{Sensor: "t1", Value: 21.5, Tags: []string{"lab"}, Note: "ok"}
+	b, err := r.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	b = cbor.AppendNil(b)
+	sizes, rest, err := cbor.FieldSizes(b)
+	if err != nil {
+		t.Fatalf("FieldSizes error: %v", err)
+	}
+	// Each size is the key's head plus the value: 1+3 for "t1", 1+9 for
+	// the float64, 1+5 for ["lab"] and 5+3 for "note": "ok".
+	want := []cbor.FieldSize{
+		{Key: "1", Size: 4},
+		{Key: "2", Size: 10},
+		{Key: "4", Size: 6},
+		{Key: "note", Size: 8},
+	}
+	if !reflect.DeepEqual(sizes, want) {
+		t.Fatalf("FieldSizes = %+v, want %+v", sizes, want)
+	}
+	total := 1
+	for _, s := range sizes {
+		total += s.Size
+	}
+	if total != len(b)-1 || len(rest) != 1 {
+		t.Fatalf("sizes add up to %d with %d bytes left, want %d and 1", total, len(rest), len(b)-1)
+	}
+
+	if _, _, err := cbor.FieldSizes(cbor.AppendArrayHeader(nil, 0)); err == nil {
+		t.Fatal("FieldSizes of an array succeeded")
+	}
+	if _, _, err := cbor.FieldSizes(b[:len(b)-3]); !errors.Is(err, cbor.ErrShortBytes) {
+		t.Fatalf("FieldSizes of a truncated map error = %v, want ErrShortBytes", err)
+	}
+}
@@ -0,0 +1,31 @@
+package structs
+
+// Point is a small struct used as a fixed-size array element.
+type Point struct {
+	X int32 `cbor:"x"`
+	Y int32 `cbor:"y"`
+}
+
+// Fixed exercises fixed-size array fields: [N]byte is encoded as a byte
+// string and other [N]T as an array of exactly N elements.
+type Fixed struct {
+	Hash    [32]byte   `cbor:"hash"`
+	Quad    [4]uint32  `cbor:"quad"`
+	Labels  [2]string  `cbor:"labels"`
+	Corners [2]Point   `cbor:"corners"`
+	Weights [3]float64 `cbor:"weights"`
+}
+
+// Bits8 is an alias of uint8, which is byte.
+type Bits8 = uint8
+
+// ByteSpellings spells its byte slices and arrays as uint8 and through
+// an alias: they are byte strings all the same, like []byte and [N]byte.
+// A defined type such as Octet is not byte, so []Octet is an array of
+// integers (see Glyphs).
+type ByteSpellings struct {
+	Slice      []uint8  `cbor:"slice"`
+	AliasSlice []Bits8  `cbor:"alias_slice"`
+	Array      [2]uint8 `cbor:"array"`
+	AliasArray [2]Bits8 `cbor:"alias_array"`
+}
@@ -0,0 +1,924 @@
+// Code generated by cborgen DO NOT EDIT.
+
+package structs
+
+import (
+	"io"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+// Fail the build if a generated method drifts from the runtime interfaces.
+var (
+	_ cbor.Marshaler   = (*Point)(nil)
+	_ cbor.Unmarshaler = (*Point)(nil)
+	_ cbor.Marshaler   = (*Fixed)(nil)
+	_ cbor.Unmarshaler = (*Fixed)(nil)
+	_ cbor.Marshaler   = (*ByteSpellings)(nil)
+	_ cbor.Unmarshaler = (*ByteSpellings)(nil)
+)
+
+func (x Point) Msgsize() (s int) {
+	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("x") + cbor.Int32Size + cbor.StringPrefixSize + len("y") + cbor.Int32Size
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *Point) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *Point) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *Point) AppendCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	b = cbor.AppendMapHeader(b, 2)
+	var err error
+	b = cbor.AppendString(b, "x")
+	b, err = cbor.AppendInt32(b, x.X), nil
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "y")
+	b, err = cbor.AppendInt32(b, x.Y), nil
+	if err != nil {
+		return b, err
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *Point) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *Point) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := in.ReadStringBytes(rest)
+		if err != nil {
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+		}
+		switch key {
+		case "x":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "x", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "x", len(b)-len(v))
+				}
+			}
+
+			var tmp int32
+			tmp, v, err = cbor.ReadInt32Bytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "x", len(b)-len(v))
+			}
+			x.X = tmp
+		case "y":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "y", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "y", len(b)-len(v))
+				}
+			}
+
+			var tmp int32
+			tmp, v, err = cbor.ReadInt32Bytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "y", len(b)-len(v))
+			}
+			x.Y = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *Point) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "x":
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp int32
+			tmp, v, err = cbor.ReadInt32Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.X = tmp
+		case "y":
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp int32
+			tmp, v, err = cbor.ReadInt32Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Y = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *Point) resetCBOR() {
+	var zero Point
+	x.X = zero.X
+	x.Y = zero.Y
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Point) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x Fixed) Msgsize() (s int) {
+	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("hash") + cbor.BytesPrefixSize + len(x.Hash) + cbor.StringPrefixSize + len("quad") + cbor.ArrayHeaderSize + len(x.Quad)*cbor.Uint32Size + cbor.StringPrefixSize + len("labels") + cbor.ArrayHeaderSize + len(x.Labels)*cbor.StringPrefixSize + cbor.StringPrefixSize + len("corners") + cbor.ArrayHeaderSize + len(x.Corners)*0 + cbor.StringPrefixSize + len("weights") + cbor.ArrayHeaderSize + len(x.Weights)*cbor.Float64Size
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *Fixed) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *Fixed) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *Fixed) AppendCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	b = cbor.AppendMapHeader(b, 5)
+	var err error
+	b = cbor.AppendString(b, "hash")
+	b, err = cbor.AppendBytes(b, x.Hash[:]), nil
+	if err != nil {
+		return b, err
+	}
+
+	b = cbor.AppendString(b, "quad")
+	b = cbor.AppendArrayHeader(b, uint32(len(x.Quad)))
+	for _, v := range x.Quad {
+		b = cbor.AppendUint32(b, v)
+	}
+
+	b = cbor.AppendString(b, "labels")
+	b = cbor.AppendArrayHeader(b, uint32(len(x.Labels)))
+	for _, v := range x.Labels {
+		b = cbor.AppendString(b, v)
+	}
+
+	b = cbor.AppendString(b, "corners")
+	b = cbor.AppendArrayHeader(b, uint32(len(x.Corners)))
+	for i := range x.Corners {
+		b, err = x.Corners[i].AppendCBOR(b)
+		if err != nil {
+			return b, err
+		}
+	}
+
+	b = cbor.AppendString(b, "weights")
+	b = cbor.AppendArrayHeader(b, uint32(len(x.Weights)))
+	for _, v := range x.Weights {
+		b = cbor.AppendFloat64(b, v)
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *Fixed) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *Fixed) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := in.ReadStringBytes(rest)
+		if err != nil {
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+		}
+		switch key {
+		case "hash":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "hash", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "hash", len(b)-len(v))
+				}
+			}
+
+			var tmp []byte
+			tmp, v, err = cbor.ReadBytesBytes(v, nil)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "hash", len(b)-len(v))
+			}
+			if len(tmp) != len(x.Hash) {
+				return b, cbor.WrapDecodeError(cbor.ArrayError{Wanted: uint32(len(x.Hash)), Got: uint32(len(tmp))}, "hash", len(b)-len(v))
+			}
+			copy(x.Hash[:], tmp)
+		case "quad":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "quad", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "quad", len(b)-len(v))
+				}
+			}
+
+			var sz uint32
+			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "quad", len(b)-len(v))
+			}
+			if sz != uint32(len(x.Quad)) {
+				return b, cbor.WrapDecodeError(cbor.ArrayError{Wanted: uint32(len(x.Quad)), Got: sz}, "quad", len(b)-len(v))
+			}
+			for iQuad := range x.Quad {
+				x.Quad[iQuad], v, err = cbor.ReadUint32Bytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, int(iQuad)), "quad", len(b)-len(v))
+				}
+			}
+		case "labels":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 2); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "labels", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "labels", len(b)-len(v))
+				}
+			}
+
+			var sz uint32
+			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "labels", len(b)-len(v))
+			}
+			if sz != uint32(len(x.Labels)) {
+				return b, cbor.WrapDecodeError(cbor.ArrayError{Wanted: uint32(len(x.Labels)), Got: sz}, "labels", len(b)-len(v))
+			}
+			for iLabels := range x.Labels {
+				x.Labels[iLabels], v, err = in.ReadStringBytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, int(iLabels)), "labels", len(b)-len(v))
+				}
+			}
+		case "corners":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 3); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "corners", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "corners", len(b)-len(v))
+				}
+			}
+
+			var sz uint32
+			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "corners", len(b)-len(v))
+			}
+			if sz != uint32(len(x.Corners)) {
+				return b, cbor.WrapDecodeError(cbor.ArrayError{Wanted: uint32(len(x.Corners)), Got: sz}, "corners", len(b)-len(v))
+			}
+			for iCorners := range x.Corners {
+				v, err = x.Corners[iCorners].DecodeInterned(v, in)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, int(iCorners)), "corners", len(b)-len(v))
+				}
+			}
+		case "weights":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 4); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "weights", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "weights", len(b)-len(v))
+				}
+			}
+
+			var sz uint32
+			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "weights", len(b)-len(v))
+			}
+			if sz != uint32(len(x.Weights)) {
+				return b, cbor.WrapDecodeError(cbor.ArrayError{Wanted: uint32(len(x.Weights)), Got: sz}, "weights", len(b)-len(v))
+			}
+			for iWeights := range x.Weights {
+				x.Weights[iWeights], v, err = cbor.ReadFloat64Bytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, int(iWeights)), "weights", len(b)-len(v))
+				}
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *Fixed) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "hash":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp []byte
+			tmp, v, err = cbor.ReadBytesBytes(v, nil)
+			if err != nil {
+				return b, err
+			}
+			if len(tmp) != len(x.Hash) {
+				return b, cbor.ArrayError{Wanted: uint32(len(x.Hash)), Got: uint32(len(tmp))}
+			}
+			copy(x.Hash[:], tmp)
+		case "quad":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var sz uint32
+			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if sz != uint32(len(x.Quad)) {
+				return b, cbor.ArrayError{Wanted: uint32(len(x.Quad)), Got: sz}
+			}
+			for iQuad := range x.Quad {
+				x.Quad[iQuad], v, err = cbor.ReadUint32Bytes(v)
+				if err != nil {
+					return b, err
+				}
+			}
+		case "labels":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var sz uint32
+			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if sz != uint32(len(x.Labels)) {
+				return b, cbor.ArrayError{Wanted: uint32(len(x.Labels)), Got: sz}
+			}
+			for iLabels := range x.Labels {
+				x.Labels[iLabels], v, err = cbor.ReadTrustedStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+			}
+		case "corners":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var sz uint32
+			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if sz != uint32(len(x.Corners)) {
+				return b, cbor.ArrayError{Wanted: uint32(len(x.Corners)), Got: sz}
+			}
+			for iCorners := range x.Corners {
+				v, err = x.Corners[iCorners].DecodeTrusted(v)
+				if err != nil {
+					return b, err
+				}
+			}
+		case "weights":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var sz uint32
+			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if sz != uint32(len(x.Weights)) {
+				return b, cbor.ArrayError{Wanted: uint32(len(x.Weights)), Got: sz}
+			}
+			for iWeights := range x.Weights {
+				x.Weights[iWeights], v, err = cbor.ReadFloat64Bytes(v)
+				if err != nil {
+					return b, err
+				}
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *Fixed) resetCBOR() {
+	var zero Fixed
+	x.Hash = zero.Hash
+	x.Quad = zero.Quad
+	x.Labels = zero.Labels
+	x.Corners = zero.Corners
+	x.Weights = zero.Weights
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Fixed) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x ByteSpellings) Msgsize() (s int) {
+	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("slice") + cbor.BytesPrefixSize + len(x.Slice) + cbor.StringPrefixSize + len("alias_slice") + cbor.BytesPrefixSize + len(x.AliasSlice) + cbor.StringPrefixSize + len("array") + cbor.BytesPrefixSize + len(x.Array) + cbor.StringPrefixSize + len("alias_array") + cbor.BytesPrefixSize + len(x.AliasArray)
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *ByteSpellings) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *ByteSpellings) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *ByteSpellings) AppendCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	b = cbor.AppendMapHeader(b, 4)
+	var err error
+	b = cbor.AppendString(b, "slice")
+	if x.Slice == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = cbor.AppendNil(b)
+	} else {
+		b, err = cbor.AppendBytes(b, x.Slice), nil
+		if err != nil {
+			return b, err
+		}
+	}
+	b = cbor.AppendString(b, "alias_slice")
+	if x.AliasSlice == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = cbor.AppendNil(b)
+	} else {
+		b, err = cbor.AppendBytes(b, x.AliasSlice), nil
+		if err != nil {
+			return b, err
+		}
+	}
+	b = cbor.AppendString(b, "array")
+	b, err = cbor.AppendBytes(b, x.Array[:]), nil
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "alias_array")
+	b, err = cbor.AppendBytes(b, x.AliasArray[:]), nil
+	if err != nil {
+		return b, err
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *ByteSpellings) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *ByteSpellings) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := in.ReadStringBytes(rest)
+		if err != nil {
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+		}
+		switch key {
+		case "slice":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "slice", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "slice", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Slice = cbor.NullSlice(x.Slice)
+				break
+			}
+			var tmp []byte
+			tmp, v, err = cbor.ReadBytesBytes(v, nil)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "slice", len(b)-len(v))
+			}
+			x.Slice = tmp
+		case "alias_slice":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "alias_slice", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "alias_slice", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.AliasSlice = cbor.NullSlice(x.AliasSlice)
+				break
+			}
+			var tmp []byte
+			tmp, v, err = cbor.ReadBytesBytes(v, nil)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "alias_slice", len(b)-len(v))
+			}
+			x.AliasSlice = tmp
+		case "array":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 2); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "array", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "array", len(b)-len(v))
+				}
+			}
+
+			var tmp []byte
+			tmp, v, err = cbor.ReadBytesBytes(v, nil)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "array", len(b)-len(v))
+			}
+			if len(tmp) != len(x.Array) {
+				return b, cbor.WrapDecodeError(cbor.ArrayError{Wanted: uint32(len(x.Array)), Got: uint32(len(tmp))}, "array", len(b)-len(v))
+			}
+			copy(x.Array[:], tmp)
+		case "alias_array":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 3); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "alias_array", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "alias_array", len(b)-len(v))
+				}
+			}
+
+			var tmp []byte
+			tmp, v, err = cbor.ReadBytesBytes(v, nil)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "alias_array", len(b)-len(v))
+			}
+			if len(tmp) != len(x.AliasArray) {
+				return b, cbor.WrapDecodeError(cbor.ArrayError{Wanted: uint32(len(x.AliasArray)), Got: uint32(len(tmp))}, "alias_array", len(b)-len(v))
+			}
+			copy(x.AliasArray[:], tmp)
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *ByteSpellings) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "slice":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Slice = cbor.NullSlice(x.Slice)
+				break
+			}
+			var tmp []byte
+			tmp, v, err = cbor.ReadBytesBytes(v, nil)
+			if err != nil {
+				return b, err
+			}
+			x.Slice = tmp
+		case "alias_slice":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.AliasSlice = cbor.NullSlice(x.AliasSlice)
+				break
+			}
+			var tmp []byte
+			tmp, v, err = cbor.ReadBytesBytes(v, nil)
+			if err != nil {
+				return b, err
+			}
+			x.AliasSlice = tmp
+		case "array":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp []byte
+			tmp, v, err = cbor.ReadBytesBytes(v, nil)
+			if err != nil {
+				return b, err
+			}
+			if len(tmp) != len(x.Array) {
+				return b, cbor.ArrayError{Wanted: uint32(len(x.Array)), Got: uint32(len(tmp))}
+			}
+			copy(x.Array[:], tmp)
+		case "alias_array":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp []byte
+			tmp, v, err = cbor.ReadBytesBytes(v, nil)
+			if err != nil {
+				return b, err
+			}
+			if len(tmp) != len(x.AliasArray) {
+				return b, cbor.ArrayError{Wanted: uint32(len(x.AliasArray)), Got: uint32(len(tmp))}
+			}
+			copy(x.AliasArray[:], tmp)
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *ByteSpellings) resetCBOR() {
+	var zero ByteSpellings
+	x.Slice = x.Slice[:0]
+	x.AliasSlice = x.AliasSlice[:0]
+	x.Array = zero.Array
+	x.AliasArray = zero.AliasArray
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *ByteSpellings) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
@@ -0,0 +1,183 @@
+package structs
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+type fixedDecoder struct {
+	name   string
+	decode func(dst *Fixed, b []byte) ([]byte, error)
+}
+
+var fixedDecoders = []fixedDecoder{
+	{
+		name:   "DecodeSafe",
+		decode: (*Fixed).DecodeSafe,
+	},
+	{
+		name:   "DecodeTrusted",
+		decode: (*Fixed).DecodeTrusted,
+	},
+}
+
+func sampleFixed() *Fixed {
+	f := &Fixed{
+		Quad:    [4]uint32{1, 2, 3, 4},
+		Labels:  [2]string{"left", "right"},
+		Corners: [2]Point{{X: -1, Y: 2}, {X: 30, Y: -40}},
+		Weights: [3]float64{0.5, 1.5, -2},
+	}
+	for i := range f.Hash {
+		f.Hash[i] = byte(i)
+	}
+	return f
+}
+
+func TestFixedArraysRoundTrip(t *testing.T) {
+	orig := sampleFixed()
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+
+	for _, tc := range fixedDecoders {
+		t.Run(tc.name, func(t *testing.T) {
+			var dst Fixed
+			rest, err := tc.decode(&dst, b)
+			if err != nil {
+				t.Fatalf("%s error: %v", tc.name, err)
+			}
+			if len(rest) != 0 {
+				t.Fatalf("%s leftover bytes: %d", tc.name, len(rest))
+			}
+			if dst != *orig {
+				t.Fatalf("%s mismatch: got %+v, want %+v", tc.name, dst, *orig)
+			}
+		})
+	}
+}
+
+func TestFixedByteArrayIsByteString(t *testing.T) {
+	orig := sampleFixed()
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	_, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		t.Fatalf("ReadMapHeaderBytes error: %v", err)
+	}
+	key, rest, err := cbor.ReadStringBytes(rest)
+	if err != nil || key != "hash" {
+		t.Fatalf("expected first key hash, got %q err=%v", key, err)
+	}
+	if cbor.NextType(rest) != cbor.BinType {
+		t.Fatalf("expected [32]byte as byte string, got %v", cbor.NextType(rest))
+	}
+}
+
+func TestFixedArraysLengthMismatch(t *testing.T) {
+	cases := []struct {
+		name  string
+		key   string
+		value []byte
+	}{
+		{name: "short-bytes", key: "hash", value: cbor.AppendBytes(nil, make([]byte, 31))},
+		{name: "long-bytes", key: "hash", value: cbor.AppendBytes(nil, make([]byte, 33))},
+		{name: "short-scalars", key: "quad", value: func() []byte {
+			b := cbor.AppendArrayHeader(nil, 3)
+			for i := 0; i < 3; i++ {
+				b = cbor.AppendUint32(b, uint32(i))
+			}
+			return b
+		}()},
+		{name: "long-scalars", key: "labels", value: func() []byte {
+			b := cbor.AppendArrayHeader(nil, 3)
+			for i := 0; i < 3; i++ {
+				b = cbor.AppendString(b, "x")
+			}
+			return b
+		}()},
+		{name: "short-structs", key: "corners", value: func() []byte {
+			p := Point{X: 1, Y: 1}
+			b := cbor.AppendArrayHeader(nil, 1)
+			b, _ = p.MarshalCBOR(b)
+			return b
+		}()},
+	}
+
+	for _, tc := range cases {
+		b := cbor.AppendMapHeader(nil, 1)
+		b = cbor.AppendString(b, tc.key)
+		b = append(b, tc.value...)
+		for _, d := range fixedDecoders {
+			t.Run(tc.name+"/"+d.name, func(t *testing.T) {
+				var dst Fixed
+				_, err := d.decode(&dst, b)
+				var arrErr cbor.ArrayError
+				if !errors.As(err, &arrErr) {
+					t.Fatalf("expected ArrayError, got %v", err)
+				}
+			})
+		}
+	}
+}
+
+func TestUint8SlicesAndArraysAreByteStrings(t *testing.T) {
+	in := ByteSpellings{
+		Slice:      []uint8{1, 2},
+		AliasSlice: []Bits8{3},
+		Array:      [2]uint8{4, 5},
+		AliasArray: [2]Bits8{6, 7},
+	}
+	want := cbor.AppendMapHeader(nil, 4)
+	want = cbor.AppendString(want, "slice")
+	want = cbor.AppendBytes(want, []byte{1, 2})
+	want = cbor.AppendString(want, "alias_slice")
+	want = cbor.AppendBytes(want, []byte{3})
+	want = cbor.AppendString(want, "array")
+	want = cbor.AppendBytes(want, []byte{4, 5})
+	want = cbor.AppendString(want, "alias_array")
+	want = cbor.AppendBytes(want, []byte{6, 7})
+
+	b, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	if !bytes.Equal(b, want) {
+		t.Fatalf("MarshalCBOR = %x, want %x", b, want)
+	}
+	decoders := map[string]func(*ByteSpellings, []byte) ([]byte, error){
+		"DecodeSafe":    (*ByteSpellings).DecodeSafe,
+		"DecodeTrusted": (*ByteSpellings).DecodeTrusted,
+	}
+	for name, decode := range decoders {
+		var out ByteSpellings
+		if _, err := decode(&out, b); err != nil {
+			t.Fatalf("%s error: %v", name, err)
+		}
+		if !reflect.DeepEqual(out, in) {
+			t.Fatalf("%s = %+v, want %+v", name, out, in)
+		}
+	}
+
+	// Reflection agrees: []uint8 and [N]uint8 are []byte and [N]byte.
+	type plain struct {
+		Slice      []uint8  `cbor:"slice"`
+		AliasSlice []Bits8  `cbor:"alias_slice"`
+		Array      [2]uint8 `cbor:"array"`
+		AliasArray [2]Bits8 `cbor:"alias_array"`
+	}
+	rb, err := cbor.Marshal(plain(in))
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	if !bytes.Equal(rb, want) {
+		t.Fatalf("Marshal = %x, want %x", rb, want)
+	}
+}
@@ -0,0 +1,18 @@
+package structs
+
+// Event carries a fixed set of fields plus a `cbor:",flatten"` catch-all:
+// the entries of Extra share the event's map, and keys the event has no
+// field for decode into Extra. It is generated with --diag and --stream
+// to cover those methods too.
+type Event struct {
+	ID    string         `cbor:"id"`
+	Kind  string         `cbor:"kind,alias=type"`
+	Seq   int64          `cbor:"1,keyasint,omitempty"`
+	Note  string         `cbor:"note,omitempty"`
+	Extra map[string]any `cbor:",flatten"`
+}
+
+// Bag has nothing but a catch-all, so every entry lands in Rest.
+type Bag struct {
+	Rest map[string]any `cbor:",flatten"`
+}
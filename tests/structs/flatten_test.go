@@ -0,0 +1,184 @@
+package structs
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+var eventDecoders = map[string]func(*Event, []byte) ([]byte, error){
+	"DecodeSafe":    (*Event).DecodeSafe,
+	"DecodeTrusted": (*Event).DecodeTrusted,
+}
+
+func TestEventFlattenRoundTrip(t *testing.T) {
+	in := &Event{
+		ID:    "e1",
+		Kind:  "click",
+		Seq:   7,
+		Extra: map[string]any{"x": uint64(10), "label": "ok", "tags": []any{"a"}},
+	}
+	b, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR: %v", err)
+	}
+	// The entries of Extra sit in the event's own map.
+	if n, _, err := cbor.ReadMapHeaderBytes(b); err != nil || n != 6 {
+		t.Fatalf("map header = %d, %v; want 6 entries", n, err)
+	}
+	for name, decode := range eventDecoders {
+		var out Event
+		if _, err := decode(&out, b); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !reflect.DeepEqual(&out, in) {
+			t.Fatalf("%s: got %+v, want %+v", name, out, *in)
+		}
+	}
+}
+
+func TestEventFlattenFieldsWin(t *testing.T) {
+	// "id" is a field key and "type" an alias of Kind: the fields win,
+	// whichever map order the entries come in.
+	in := &Event{ID: "e1", Kind: "k", Extra: map[string]any{"id": "shadow", "type": "shadow", "z": true}}
+	for range 10 {
+		b, err := in.MarshalCBOR(nil)
+		if err != nil {
+			t.Fatalf("MarshalCBOR: %v", err)
+		}
+		for name, decode := range eventDecoders {
+			var out Event
+			if _, err := decode(&out, b); err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			want := Event{ID: "e1", Kind: "k", Extra: map[string]any{"z": true}}
+			if !reflect.DeepEqual(out, want) {
+				t.Fatalf("%s: got %+v, want %+v", name, out, want)
+			}
+		}
+	}
+}
+
+func TestEventFlattenDecodesUnknownKeys(t *testing.T) {
+	b := cbor.AppendMapHeader(nil, 4)
+	b = cbor.AppendString(b, "type") // an alias, not an unknown key
+	b = cbor.AppendString(b, "k")
+	b = cbor.AppendString(b, "extra")
+	b = cbor.AppendInt64(b, -2)
+	b = cbor.AppendInt64(b, 99) // integer keys cannot go into Extra
+	b = cbor.AppendString(b, "skipped")
+	b = cbor.AppendString(b, "")
+	b = cbor.AppendNil(b)
+	for name, decode := range eventDecoders {
+		out := Event{Extra: map[string]any{"kept": 1}}
+		if _, err := decode(&out, b); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		want := Event{Kind: "k", Extra: map[string]any{"kept": 1, "extra": int64(-2), "": nil}}
+		if !reflect.DeepEqual(out, want) {
+			t.Fatalf("%s: got %+v, want %+v", name, out, want)
+		}
+	}
+
+	cbor.ResetBeforeDecode = true
+	defer func() { cbor.ResetBeforeDecode = false }()
+	out := Event{Extra: map[string]any{"kept": 1}}
+	if _, err := out.DecodeSafe(b); err != nil {
+		t.Fatalf("DecodeSafe: %v", err)
+	}
+	if _, ok := out.Extra["kept"]; ok || len(out.Extra) != 2 {
+		t.Fatalf("ResetBeforeDecode left Extra = %v", out.Extra)
+	}
+}
+
+func TestEventFlattenCanonical(t *testing.T) {
+	cbor.CanonicalMapEncode = true
+	defer func() { cbor.CanonicalMapEncode = false }()
+
+	in := &Event{ID: "e1", Kind: "k", Extra: map[string]any{"bb": 2, "a": 1, "c": 3, "kind": 0}}
+	want := `{"id": "e1", "kind": "k", "a": 1, "c": 3, "bb": 2}`
+	b, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR: %v", err)
+	}
+	if got := diagOf(t, in); got != want {
+		t.Fatalf("encoding = %s, want %s", got, want)
+	}
+	if got := in.DiagString(); got != want {
+		t.Fatalf("DiagString() = %s, want %s", got, want)
+	}
+
+	var w bytes.Buffer
+	enc := cbor.NewEncoder(&w)
+	if err := in.MarshalCBORStream(enc); err != nil {
+		t.Fatalf("MarshalCBORStream: %v", err)
+	}
+	if err := enc.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if !bytes.Equal(w.Bytes(), b) {
+		t.Fatalf("MarshalCBORStream wrote % x, want % x", w.Bytes(), b)
+	}
+}
+
+func TestBagFlattenOnly(t *testing.T) {
+	in := &Bag{Rest: map[string]any{"a": "x", "b": false}}
+	b, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR: %v", err)
+	}
+	var out Bag
+	if _, err := out.DecodeTrusted(b); err != nil {
+		t.Fatalf("DecodeTrusted: %v", err)
+	}
+	if !reflect.DeepEqual(&out, in) {
+		t.Fatalf("got %+v, want %+v", out, *in)
+	}
+
+	b, err = (&Bag{}).MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR: %v", err)
+	}
+	if !bytes.Equal(b, []byte{0xa0}) {
+		t.Fatalf("empty Bag = % x, want a0", b)
+	}
+}
+
+// reflectEvent has Event's tags but no generated methods, so Marshal and
+// Unmarshal handle it by reflection.
+type reflectEvent struct {
+	ID    string         `cbor:"id"`
+	Kind  string         `cbor:"kind,alias=type"`
+	Seq   int64          `cbor:"1,keyasint,omitempty"`
+	Note  string         `cbor:"note,omitempty"`
+	Extra map[string]any `cbor:",flatten"`
+}
+
+func TestReflectFlattenMatchesGenerated(t *testing.T) {
+	cbor.CanonicalMapEncode = true
+	defer func() { cbor.CanonicalMapEncode = false }()
+
+	extra := map[string]any{"x": uint64(10), "id": "shadow", "label": "ok"}
+	gen, err := (&Event{ID: "e1", Kind: "k", Seq: 3, Extra: extra}).MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR: %v", err)
+	}
+	refl, err := cbor.Marshal(&reflectEvent{ID: "e1", Kind: "k", Seq: 3, Extra: extra})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if !bytes.Equal(refl, gen) {
+		t.Fatalf("Marshal = % x, want % x", refl, gen)
+	}
+
+	var out reflectEvent
+	if err := cbor.Unmarshal(gen, &out); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	want := reflectEvent{ID: "e1", Kind: "k", Seq: 3, Extra: map[string]any{"x": uint64(10), "label": "ok"}}
+	if !reflect.DeepEqual(out, want) {
+		t.Fatalf("Unmarshal = %+v, want %+v", out, want)
+	}
+}
@@ -0,0 +1,159 @@
+package structs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	fxcbor "github.com/fxamacker/cbor/v2"
+)
+
+// Plain copies of the fixture types: the same fields and tags without the
+// generated methods, so fxamacker/cbor encodes them by reflection.
+type (
+	plainPerson      Person
+	plainContact     Contact
+	plainReading     Reading
+	plainCoseKey     CoseKey
+	plainMeasurement Measurement
+)
+
+// diffCodec is the generated API the differential test drives.
+type diffCodec interface {
+	MarshalCBOR(b []byte) ([]byte, error)
+	DecodeSafe(b []byte) ([]byte, error)
+}
+
+var (
+	// fxEnc writes nil slices and maps as empty containers, as generated
+	// encoders do.
+	fxEnc = mustEncMode(fxcbor.EncOptions{NilContainers: fxcbor.NilContainerAsEmpty})
+	// fxCanon re-encodes decoded values deterministically so two
+	// encodings of the same data compare equal byte for byte.
+	fxCanon = mustEncMode(fxcbor.CoreDetEncOptions())
+)
+
+func mustEncMode(opts fxcbor.EncOptions) fxcbor.EncMode {
+	em, err := opts.EncMode()
+	if err != nil {
+		panic(err)
+	}
+	return em
+}
+
+// canonical decodes b generically and re-encodes it with fxCanon, erasing
+// differences that carry no meaning: map order, integer and float widths.
+func canonical(t *testing.T, what string, b []byte) []byte {
+	t.Helper()
+	var v any
+	if err := fxcbor.Unmarshal(b, &v); err != nil {
+		t.Fatalf("%s: fxamacker cannot decode % x: %v", what, b, err)
+	}
+	c, err := fxCanon.Marshal(v)
+	if err != nil {
+		t.Fatalf("%s: canonical re-encode: %v", what, err)
+	}
+	return c
+}
+
+// checkDifferential encodes v with both libraries and requires the two
+// encodings to mean the same thing, then decodes each with the other
+// library and requires the value to re-encode to the same data. plain is
+// v viewed as its plain type; fresh returns a new zero value as both.
+func checkDifferential(t *testing.T, name string, v diffCodec, plain any, fresh func() (diffCodec, any)) {
+	t.Helper()
+	ours, err := v.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("%s: MarshalCBOR: %v", name, err)
+	}
+	theirs, err := fxEnc.Marshal(plain)
+	if err != nil {
+		t.Fatalf("%s: fxamacker Marshal: %v", name, err)
+	}
+	want := canonical(t, name+" generated", ours)
+	if got := canonical(t, name+" fxamacker", theirs); !bytes.Equal(got, want) {
+		t.Fatalf("%s: encodings differ\ngenerated % x\nfxamacker % x", name, ours, theirs)
+	}
+
+	// Generated encoding -> fxamacker decode -> generated encode.
+	o, p := fresh()
+	if err := fxcbor.Unmarshal(ours, p); err != nil {
+		t.Fatalf("%s: fxamacker Unmarshal of % x: %v", name, ours, err)
+	}
+	again, err := o.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("%s: MarshalCBOR after fxamacker decode: %v", name, err)
+	}
+	if got := canonical(t, name+" via fxamacker", again); !bytes.Equal(got, want) {
+		t.Fatalf("%s: fxamacker decode changed the value\n got % x\nwant % x", name, again, ours)
+	}
+
+	// fxamacker encoding -> generated decode -> fxamacker encode.
+	o, p = fresh()
+	rest, err := o.DecodeSafe(theirs)
+	if err != nil || len(rest) != 0 {
+		t.Fatalf("%s: DecodeSafe of % x: err=%v rest=%d", name, theirs, err, len(rest))
+	}
+	again, err = fxEnc.Marshal(p)
+	if err != nil {
+		t.Fatalf("%s: fxamacker Marshal after DecodeSafe: %v", name, err)
+	}
+	if got := canonical(t, name+" via DecodeSafe", again); !bytes.Equal(got, want) {
+		t.Fatalf("%s: DecodeSafe changed the value\n got % x\nwant % x", name, again, theirs)
+	}
+}
+
+// FuzzDifferentialFxamacker builds fixture values from the fuzzer's
+// inputs and checks that generated code and fxamacker/cbor agree on
+// their encoding and decode each other's output to the same data.
+func FuzzDifferentialFxamacker(f *testing.F) {
+	f.Add("Ada", "ada@example.com,ops", int64(36), int64(-7), 2.5, []byte{1, 2, 3})
+	f.Add("", "", int64(0), int64(0), 0.0, []byte(nil))
+	f.Add("temp", "°C", int64(1)<<40, int64(-1)<<63, -1e300, []byte("x"))
+	f.Add("n", "a,,b", int64(23), int64(24), 65504.0, make([]byte, 24))
+
+	f.Fuzz(func(t *testing.T, s1, s2 string, n1, n2 int64, fl float64, data []byte) {
+		// Both libraries reject invalid UTF-8 text on decode.
+		if !utf8.ValidString(s1) || !utf8.ValidString(s2) {
+			return
+		}
+		var tags []string
+		if s2 != "" {
+			tags = strings.Split(s2, ",")
+		}
+
+		person := Person{Name: s1, Age: int(n1), Data: data}
+		checkDifferential(t, "Person", &person, (*plainPerson)(&person), func() (diffCodec, any) {
+			var x Person
+			return &x, (*plainPerson)(&x)
+		})
+
+		contact := Contact{Name: s1, Email: s2, Tags: tags}
+		checkDifferential(t, "Contact", &contact, (*plainContact)(&contact), func() (diffCodec, any) {
+			var x Contact
+			return &x, (*plainContact)(&x)
+		})
+
+		reading := Reading{Sensor: s1, Value: fl, Unit: s2, Tags: tags, Note: s2}
+		if s1 != "" {
+			reading.Meta = map[string]string{s1: s2}
+		}
+		checkDifferential(t, "Reading", &reading, (*plainReading)(&reading), func() (diffCodec, any) {
+			var x Reading
+			return &x, (*plainReading)(&x)
+		})
+
+		key := CoseKey{Kty: n1, Kid: data, Alg: n2, Crv: n2, X: data}
+		checkDifferential(t, "CoseKey", &key, (*plainCoseKey)(&key), func() (diffCodec, any) {
+			var x CoseKey
+			return &x, (*plainCoseKey)(&x)
+		})
+
+		m := Measurement{Sensor: s1, Value: fl, Tags: tags}
+		checkDifferential(t, "Measurement", &m, (*plainMeasurement)(&m), func() (diffCodec, any) {
+			var x Measurement
+			return &x, (*plainMeasurement)(&x)
+		})
+	})
+}
@@ -0,0 +1,49 @@
+package structs
+
+import "errors"
+
+// Box holds a value of any type along with a list of more of them.
+//
+//cbor:instantiate Box[int]
+//cbor:instantiate Box[string]
+//cbor:instantiate Box[Point]
+type Box[T any] struct {
+	Value T      `cbor:"value"`
+	Items []T    `cbor:"items,omitempty"`
+	Note  string `cbor:"note,omitempty"`
+}
+
+// Pair is a generic struct with two type parameters.
+//
+//cbor:instantiate Pair[string, []byte]
+type Pair[K comparable, V any] struct {
+	Key    K       `cbor:"k"`
+	Val    V       `cbor:"v"`
+	Counts map[K]V `cbor:"counts,omitempty"`
+}
+
+// Validate rejects a pair without a key.
+func (p *Pair[K, V]) Validate() error {
+	var zero K
+	if p.Key == zero {
+		return errors.New("pair has no key")
+	}
+	return nil
+}
+
+// Chain is a recursive generic struct: every instantiation tracks its
+// nesting depth.
+//
+//cbor:instantiate Chain[int]
+type Chain[T any] struct {
+	Head T         `cbor:"head"`
+	Next *Chain[T] `cbor:"next,omitempty"`
+}
+
+// Crate holds instantiations of generic structs in its fields.
+type Crate struct {
+	Ints   Box[int]     `cbor:"ints"`
+	Names  *Box[string] `cbor:"names,omitempty"`
+	Points []Box[Point] `cbor:"points,omitempty"`
+	Chain  Chain[int]   `cbor:"chain"`
+}
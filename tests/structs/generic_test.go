@@ -0,0 +1,157 @@
+package structs
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+func sampleCrate() Crate {
+	return Crate{
+		Ints:   Box[int]{Value: 7, Items: []int{1, 2, 3}},
+		Names:  &Box[string]{Value: "a", Items: []string{"b"}, Note: "names"},
+		Points: []Box[Point]{{Value: Point{X: 1, Y: 2}}, {Value: Point{X: -3}, Items: []Point{{Y: 4}}}},
+		Chain:  Chain[int]{Head: 1, Next: &Chain[int]{Head: 2}},
+	}
+}
+
+func TestCrateGenericRoundTrip(t *testing.T) {
+	in := sampleCrate()
+	want := `{"ints": {"value": 7, "items": [1, 2, 3]}, ` +
+		`"names": {"value": "a", "items": ["b"], "note": "names"}, ` +
+		`"points": [{"value": {"x": 1, "y": 2}}, {"value": {"x": -3, "y": 0}, "items": [{"x": 0, "y": 4}]}], ` +
+		`"chain": {"head": 1, "next": {"head": 2}}}`
+	if got := diagOf(t, &in); got != want {
+		t.Fatalf("encoding = %s\nwant %s", got, want)
+	}
+	if got := in.DiagString(); got != want {
+		t.Fatalf("DiagString() = %s\nwant %s", got, want)
+	}
+
+	b, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	decoders := map[string]func(*Crate, []byte) ([]byte, error){
+		"DecodeSafe":    (*Crate).DecodeSafe,
+		"DecodeTrusted": (*Crate).DecodeTrusted,
+	}
+	for name, decode := range decoders {
+		var out Crate
+		if _, err := decode(&out, b); err != nil {
+			t.Fatalf("%s error: %v", name, err)
+		}
+		if !reflect.DeepEqual(out, in) {
+			t.Fatalf("%s = %+v, want %+v", name, out, in)
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := cbor.NewEncoder(&buf)
+	if err := enc.Encode(&in); err != nil {
+		t.Fatalf("Encode error: %v", err)
+	}
+	if err := enc.Flush(); err != nil {
+		t.Fatalf("Flush error: %v", err)
+	}
+	var out Crate
+	if err := cbor.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("Unmarshal of streamed encoding error: %v", err)
+	}
+	if !reflect.DeepEqual(out, in) {
+		t.Fatalf("Unmarshal of streamed encoding = %+v, want %+v", out, in)
+	}
+
+	c := in.Clone()
+	c.Ints.Items[0] = 100
+	c.Names.Items[0] = "z"
+	c.Chain.Next.Head = 100
+	if in.Ints.Items[0] != 1 || in.Names.Items[0] != "b" || in.Chain.Next.Head != 2 {
+		t.Fatalf("Clone shares storage with the original: %+v", in)
+	}
+}
+
+func TestGenericInstances(t *testing.T) {
+	pair := &Pair[string, []byte]{Key: "k", Val: []byte{1, 2}, Counts: map[string][]byte{"c": {3}}}
+	if got, want := diagOf(t, pair), `{"k": "k", "v": h'0102', "counts": {"c": h'03'}}`; got != want {
+		t.Fatalf("encoding = %s, want %s", got, want)
+	}
+	b, err := cbor.Marshal(pair)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	out, rest, err := NewPairFromCBOR[string, []byte](b)
+	if err != nil || len(rest) != 0 {
+		t.Fatalf("NewPairFromCBOR error: %v (%d bytes left)", err, len(rest))
+	}
+	if !reflect.DeepEqual(out, pair) {
+		t.Fatalf("NewPairFromCBOR = %+v, want %+v", out, pair)
+	}
+
+	// The generic type's Validate method runs after decoding.
+	var empty Pair[string, []byte]
+	b, err = empty.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	if _, err := empty.DecodeSafe(b); err == nil {
+		t.Fatal("DecodeSafe of a pair without a key succeeded")
+	}
+
+	box := Box[int]{Value: 1}
+	var compat BoxCompat[int]
+	cb, err := (*BoxCompat[int])(&box).MarshalCBOR()
+	if err != nil {
+		t.Fatalf("Compat MarshalCBOR error: %v", err)
+	}
+	if err := compat.UnmarshalCBOR(cb); err != nil {
+		t.Fatalf("Compat UnmarshalCBOR error: %v", err)
+	}
+	if !reflect.DeepEqual(Box[int](compat), box) {
+		t.Fatalf("Compat UnmarshalCBOR = %+v, want %+v", compat, box)
+	}
+}
+
+func TestGenericNotInstantiated(t *testing.T) {
+	in := Box[float64]{Value: 1.5}
+	var ut *cbor.ErrUnsupportedType
+	if _, err := in.MarshalCBOR(nil); !errors.As(err, &ut) || ut.T != reflect.TypeOf(&in) {
+		t.Fatalf("MarshalCBOR error = %v, want ErrUnsupportedType for *Box[float64]", err)
+	}
+	b := cbor.AppendMapHeader(nil, 0)
+	var out Box[float64]
+	if _, err := out.DecodeSafe(b); !errors.As(err, &ut) {
+		t.Fatalf("DecodeSafe error = %v, want ErrUnsupportedType", err)
+	}
+	if _, err := out.DecodeTrusted(b); !errors.As(err, &ut) {
+		t.Fatalf("DecodeTrusted error = %v, want ErrUnsupportedType", err)
+	}
+}
+
+func TestChainGenericCycle(t *testing.T) {
+	c := &Chain[int]{Head: 1}
+	c.Next = c
+	if _, err := c.MarshalCBOR(nil); !errors.Is(err, cbor.ErrCycleDetected) {
+		t.Fatalf("MarshalCBOR error = %v, want ErrCycleDetected", err)
+	}
+}
+
+func TestGenericDecodeAllocs(t *testing.T) {
+	in := Box[int]{Value: 7, Items: []int{1, 2, 3}}
+	b, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	out := Box[int]{Items: make([]int, 0, 8)}
+	allocs := testing.AllocsPerRun(100, func() {
+		if _, err := out.DecodeTrusted(b); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Fatalf("DecodeTrusted allocated %v times per run, want 0", allocs)
+	}
+}
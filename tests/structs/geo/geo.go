@@ -0,0 +1,9 @@
+// Package geo holds a generated type that the crosspkg fixture imports
+// under an alias.
+package geo
+
+// Point is a position in degrees.
+type Point struct {
+	Lat float64 `cbor:"lat"`
+	Lon float64 `cbor:"lon"`
+}
@@ -0,0 +1,25 @@
+package structs
+
+import "time"
+
+// RetryPolicy is an option group inlined into Request; its keys sit
+// beside Request's own keys in the encoded map.
+type RetryPolicy struct {
+	Attempts int           `cbor:"attempts"`
+	Backoff  time.Duration `cbor:"backoff,omitempty"`
+	Codes    []int         `cbor:"codes,omitempty"`
+}
+
+// Limits is a second option group, itself inlining RetryPolicy one level
+// deeper to exercise nested inlining.
+type Limits struct {
+	MaxBytes int64       `cbor:"max_bytes"`
+	Retry    RetryPolicy `cbor:",inline"`
+}
+
+// Request exercises `cbor:",inline"` on named struct fields.
+type Request struct {
+	URL     string            `cbor:"url"`
+	Limits  Limits            `cbor:",inline"`
+	Headers map[string]string `cbor:"headers,omitempty"`
+}
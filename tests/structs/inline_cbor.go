@@ -0,0 +1,1149 @@
+// Code generated by cborgen DO NOT EDIT.
+
+package structs
+
+import (
+	"io"
+	"time"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+// Fail the build if a generated method drifts from the runtime interfaces.
+var (
+	_ cbor.Marshaler   = (*RetryPolicy)(nil)
+	_ cbor.Unmarshaler = (*RetryPolicy)(nil)
+	_ cbor.Marshaler   = (*Limits)(nil)
+	_ cbor.Unmarshaler = (*Limits)(nil)
+	_ cbor.Marshaler   = (*Request)(nil)
+	_ cbor.Unmarshaler = (*Request)(nil)
+)
+
+func (x RetryPolicy) Msgsize() (s int) {
+	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("attempts") + cbor.IntSize + cbor.StringPrefixSize + len("backoff") + cbor.DurationSize + cbor.StringPrefixSize + len("codes") + cbor.ArrayHeaderSize + len(x.Codes)*cbor.IntSize
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *RetryPolicy) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *RetryPolicy) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *RetryPolicy) AppendCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	count := uint32(0)
+	count++
+	if !(x.Backoff == 0) {
+		count++
+	}
+	if !(len(x.Codes) == 0) {
+		count++
+	}
+	b = cbor.AppendMapHeader(b, count)
+	var err error
+	b = cbor.AppendString(b, "attempts")
+	b, err = cbor.AppendInt(b, x.Attempts), nil
+	if err != nil {
+		return b, err
+	}
+	if !(x.Backoff == 0) {
+		b = cbor.AppendString(b, "backoff")
+		b, err = cbor.AppendDuration(b, x.Backoff), nil
+		if err != nil {
+			return b, err
+		}
+	}
+	if !(len(x.Codes) == 0) {
+		if x.Codes == nil && cbor.NilContainers == cbor.NilAsNull {
+			b = cbor.AppendString(b, "codes")
+			b = cbor.AppendNil(b)
+		} else {
+			b = cbor.AppendString(b, "codes")
+			b = cbor.AppendArrayHeader(b, uint32(len(x.Codes)))
+			for _, v := range x.Codes {
+				b = cbor.AppendInt(b, v)
+			}
+		}
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *RetryPolicy) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *RetryPolicy) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := in.ReadStringBytes(rest)
+		if err != nil {
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+		}
+		switch key {
+		case "attempts":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "attempts", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "attempts", len(b)-len(v))
+				}
+			}
+
+			var tmp int
+			tmp, v, err = cbor.ReadIntBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "attempts", len(b)-len(v))
+			}
+			x.Attempts = tmp
+		case "backoff":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "backoff", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "backoff", len(b)-len(v))
+				}
+			}
+
+			var tmp time.Duration
+			tmp, v, err = cbor.ReadDurationBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "backoff", len(b)-len(v))
+			}
+			x.Backoff = tmp
+		case "codes":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 2); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "codes", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "codes", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Codes = cbor.NullSlice(x.Codes)
+				break
+			}
+			var sz uint32
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "codes", len(b)-len(v))
+			}
+			if cap(x.Codes) >= int(sz) {
+				x.Codes = x.Codes[:sz]
+			} else {
+				x.Codes = make([]int, sz)
+			}
+			if sz > 0 {
+				_ = x.Codes[sz-1]
+			}
+			for iCodes := uint32(0); iCodes < sz; iCodes++ {
+				var tmp int
+				tmp, v, err = cbor.ReadIntBytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, int(iCodes)), "codes", len(b)-len(v))
+				}
+				x.Codes[iCodes] = tmp
+			}
+			if indef {
+				v = v[1:] // break
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *RetryPolicy) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "attempts":
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp int
+			tmp, v, err = cbor.ReadIntBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Attempts = tmp
+		case "backoff":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp time.Duration
+			tmp, v, err = cbor.ReadDurationBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Backoff = tmp
+		case "codes":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Codes = cbor.NullSlice(x.Codes)
+				break
+			}
+			var sz uint32
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap(x.Codes) >= int(sz) {
+				x.Codes = x.Codes[:sz]
+			} else {
+				x.Codes = make([]int, sz)
+			}
+			if sz > 0 {
+				_ = x.Codes[sz-1]
+			}
+			for iCodes := uint32(0); iCodes < sz; iCodes++ {
+				var tmp int
+				tmp, v, err = cbor.ReadIntBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Codes[iCodes] = tmp
+			}
+			if indef {
+				v = v[1:] // break
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *RetryPolicy) resetCBOR() {
+	var zero RetryPolicy
+	x.Attempts = zero.Attempts
+	x.Backoff = zero.Backoff
+	x.Codes = x.Codes[:0]
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *RetryPolicy) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x Limits) Msgsize() (s int) {
+	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("max_bytes") + cbor.Int64Size + cbor.StringPrefixSize + len("attempts") + cbor.IntSize + cbor.StringPrefixSize + len("backoff") + cbor.DurationSize + cbor.StringPrefixSize + len("codes") + cbor.ArrayHeaderSize + len(x.Retry.Codes)*cbor.IntSize
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *Limits) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *Limits) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *Limits) AppendCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	count := uint32(0)
+	count++
+	count++
+	if !(x.Retry.Backoff == 0) {
+		count++
+	}
+	if !(len(x.Retry.Codes) == 0) {
+		count++
+	}
+	b = cbor.AppendMapHeader(b, count)
+	var err error
+	b = cbor.AppendString(b, "max_bytes")
+	b, err = cbor.AppendInt64(b, x.MaxBytes), nil
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "attempts")
+	b, err = cbor.AppendInt(b, x.Retry.Attempts), nil
+	if err != nil {
+		return b, err
+	}
+	if !(x.Retry.Backoff == 0) {
+		b = cbor.AppendString(b, "backoff")
+		b, err = cbor.AppendDuration(b, x.Retry.Backoff), nil
+		if err != nil {
+			return b, err
+		}
+	}
+	if !(len(x.Retry.Codes) == 0) {
+		if x.Retry.Codes == nil && cbor.NilContainers == cbor.NilAsNull {
+			b = cbor.AppendString(b, "codes")
+			b = cbor.AppendNil(b)
+		} else {
+			b = cbor.AppendString(b, "codes")
+			b = cbor.AppendArrayHeader(b, uint32(len(x.Retry.Codes)))
+			for _, v := range x.Retry.Codes {
+				b = cbor.AppendInt(b, v)
+			}
+		}
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *Limits) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *Limits) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := in.ReadStringBytes(rest)
+		if err != nil {
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+		}
+		switch key {
+		case "max_bytes":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "max_bytes", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "max_bytes", len(b)-len(v))
+				}
+			}
+
+			var tmp int64
+			tmp, v, err = cbor.ReadInt64Bytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "max_bytes", len(b)-len(v))
+			}
+			x.MaxBytes = tmp
+		case "attempts":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "attempts", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "attempts", len(b)-len(v))
+				}
+			}
+
+			var tmp int
+			tmp, v, err = cbor.ReadIntBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "attempts", len(b)-len(v))
+			}
+			x.Retry.Attempts = tmp
+		case "backoff":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 2); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "backoff", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "backoff", len(b)-len(v))
+				}
+			}
+
+			var tmp time.Duration
+			tmp, v, err = cbor.ReadDurationBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "backoff", len(b)-len(v))
+			}
+			x.Retry.Backoff = tmp
+		case "codes":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 3); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "codes", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "codes", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Retry.Codes = cbor.NullSlice(x.Retry.Codes)
+				break
+			}
+			var sz uint32
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "codes", len(b)-len(v))
+			}
+			if cap(x.Retry.Codes) >= int(sz) {
+				x.Retry.Codes = x.Retry.Codes[:sz]
+			} else {
+				x.Retry.Codes = make([]int, sz)
+			}
+			if sz > 0 {
+				_ = x.Retry.Codes[sz-1]
+			}
+			for iRetry_Codes := uint32(0); iRetry_Codes < sz; iRetry_Codes++ {
+				var tmp int
+				tmp, v, err = cbor.ReadIntBytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, int(iRetry_Codes)), "codes", len(b)-len(v))
+				}
+				x.Retry.Codes[iRetry_Codes] = tmp
+			}
+			if indef {
+				v = v[1:] // break
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *Limits) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "max_bytes":
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp int64
+			tmp, v, err = cbor.ReadInt64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.MaxBytes = tmp
+		case "attempts":
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp int
+			tmp, v, err = cbor.ReadIntBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Retry.Attempts = tmp
+		case "backoff":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp time.Duration
+			tmp, v, err = cbor.ReadDurationBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Retry.Backoff = tmp
+		case "codes":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Retry.Codes = cbor.NullSlice(x.Retry.Codes)
+				break
+			}
+			var sz uint32
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap(x.Retry.Codes) >= int(sz) {
+				x.Retry.Codes = x.Retry.Codes[:sz]
+			} else {
+				x.Retry.Codes = make([]int, sz)
+			}
+			if sz > 0 {
+				_ = x.Retry.Codes[sz-1]
+			}
+			for iRetry_Codes := uint32(0); iRetry_Codes < sz; iRetry_Codes++ {
+				var tmp int
+				tmp, v, err = cbor.ReadIntBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Retry.Codes[iRetry_Codes] = tmp
+			}
+			if indef {
+				v = v[1:] // break
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *Limits) resetCBOR() {
+	var zero Limits
+	x.MaxBytes = zero.MaxBytes
+	x.Retry.Attempts = zero.Retry.Attempts
+	x.Retry.Backoff = zero.Retry.Backoff
+	x.Retry.Codes = x.Retry.Codes[:0]
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Limits) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x Request) Msgsize() (s int) {
+	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("url") + cbor.StringPrefixSize + len(x.URL) + cbor.StringPrefixSize + len("max_bytes") + cbor.Int64Size + cbor.StringPrefixSize + len("attempts") + cbor.IntSize + cbor.StringPrefixSize + len("backoff") + cbor.DurationSize + cbor.StringPrefixSize + len("codes") + cbor.ArrayHeaderSize + len(x.Limits.Retry.Codes)*cbor.IntSize + cbor.StringPrefixSize + len("headers") + cbor.MapHeaderSize + len(x.Headers)*(cbor.StringPrefixSize+cbor.StringPrefixSize)
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *Request) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *Request) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *Request) AppendCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	count := uint32(0)
+	count++
+	count++
+	count++
+	if !(x.Limits.Retry.Backoff == 0) {
+		count++
+	}
+	if !(len(x.Limits.Retry.Codes) == 0) {
+		count++
+	}
+	if !(len(x.Headers) == 0) {
+		count++
+	}
+	b = cbor.AppendMapHeader(b, count)
+	var err error
+	b = cbor.AppendString(b, "url")
+	b, err = cbor.AppendString(b, x.URL), nil
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "max_bytes")
+	b, err = cbor.AppendInt64(b, x.Limits.MaxBytes), nil
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "attempts")
+	b, err = cbor.AppendInt(b, x.Limits.Retry.Attempts), nil
+	if err != nil {
+		return b, err
+	}
+	if !(x.Limits.Retry.Backoff == 0) {
+		b = cbor.AppendString(b, "backoff")
+		b, err = cbor.AppendDuration(b, x.Limits.Retry.Backoff), nil
+		if err != nil {
+			return b, err
+		}
+	}
+	if !(len(x.Limits.Retry.Codes) == 0) {
+		if x.Limits.Retry.Codes == nil && cbor.NilContainers == cbor.NilAsNull {
+			b = cbor.AppendString(b, "codes")
+			b = cbor.AppendNil(b)
+		} else {
+			b = cbor.AppendString(b, "codes")
+			b = cbor.AppendArrayHeader(b, uint32(len(x.Limits.Retry.Codes)))
+			for _, v := range x.Limits.Retry.Codes {
+				b = cbor.AppendInt(b, v)
+			}
+		}
+	}
+	if !(len(x.Headers) == 0) {
+		if x.Headers == nil && cbor.NilContainers == cbor.NilAsNull {
+			b = cbor.AppendString(b, "headers")
+			b = cbor.AppendNil(b)
+		} else {
+			b = cbor.AppendString(b, "headers")
+			if cbor.CanonicalMapEncode {
+				b, err = cbor.AppendMapDeterministic(b, x.Headers, cbor.EncKeyString, cbor.EncValString)
+				if err != nil {
+					return b, err
+				}
+			} else {
+				b = cbor.AppendMapHeader(b, uint32(len(x.Headers)))
+				for k, v := range x.Headers {
+					b = cbor.AppendString(b, k)
+					b = cbor.AppendString(b, v)
+				}
+			}
+		}
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *Request) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *Request) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := in.ReadStringBytes(rest)
+		if err != nil {
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+		}
+		switch key {
+		case "url":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "url", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "url", len(b)-len(v))
+				}
+			}
+
+			var tmp string
+			tmp, v, err = in.ReadStringBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "url", len(b)-len(v))
+			}
+			x.URL = tmp
+		case "max_bytes":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "max_bytes", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "max_bytes", len(b)-len(v))
+				}
+			}
+
+			var tmp int64
+			tmp, v, err = cbor.ReadInt64Bytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "max_bytes", len(b)-len(v))
+			}
+			x.Limits.MaxBytes = tmp
+		case "attempts":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 2); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "attempts", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "attempts", len(b)-len(v))
+				}
+			}
+
+			var tmp int
+			tmp, v, err = cbor.ReadIntBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "attempts", len(b)-len(v))
+			}
+			x.Limits.Retry.Attempts = tmp
+		case "backoff":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 3); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "backoff", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "backoff", len(b)-len(v))
+				}
+			}
+
+			var tmp time.Duration
+			tmp, v, err = cbor.ReadDurationBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "backoff", len(b)-len(v))
+			}
+			x.Limits.Retry.Backoff = tmp
+		case "codes":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 4); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "codes", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "codes", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Limits.Retry.Codes = cbor.NullSlice(x.Limits.Retry.Codes)
+				break
+			}
+			var sz uint32
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "codes", len(b)-len(v))
+			}
+			if cap(x.Limits.Retry.Codes) >= int(sz) {
+				x.Limits.Retry.Codes = x.Limits.Retry.Codes[:sz]
+			} else {
+				x.Limits.Retry.Codes = make([]int, sz)
+			}
+			if sz > 0 {
+				_ = x.Limits.Retry.Codes[sz-1]
+			}
+			for iLimits_Retry_Codes := uint32(0); iLimits_Retry_Codes < sz; iLimits_Retry_Codes++ {
+				var tmp int
+				tmp, v, err = cbor.ReadIntBytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, int(iLimits_Retry_Codes)), "codes", len(b)-len(v))
+				}
+				x.Limits.Retry.Codes[iLimits_Retry_Codes] = tmp
+			}
+			if indef {
+				v = v[1:] // break
+			}
+		case "headers":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 5); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "headers", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "headers", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Headers = cbor.NullMap(x.Headers)
+				break
+			}
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "headers", len(b)-len(v))
+			}
+			if x.Headers == nil && sz > 0 {
+				x.Headers = make(map[string]string, sz)
+			} else if x.Headers != nil {
+				clear(x.Headers)
+			}
+			for iHeaders := uint32(0); iHeaders < sz; iHeaders++ {
+				var key string
+				key, v, err = in.ReadStringBytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "headers", len(b)-len(v))
+				}
+				if _, dup := x.Headers[key]; dup {
+					var skip bool
+					if v, skip, err = cbor.SkipDuplicate(v); err != nil {
+						return b, cbor.WrapDecodeError(cbor.WrapDecodeKey(err, key), "headers", len(b)-len(v))
+					}
+					if skip {
+						continue
+					}
+				}
+				var tmp string
+				tmp, v, err = in.ReadStringBytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeKey(err, key), "headers", len(b)-len(v))
+				}
+				x.Headers[key] = tmp
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *Request) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "url":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			x.URL, v, err = cbor.ReadTrustedStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+		case "max_bytes":
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp int64
+			tmp, v, err = cbor.ReadInt64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Limits.MaxBytes = tmp
+		case "attempts":
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp int
+			tmp, v, err = cbor.ReadIntBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Limits.Retry.Attempts = tmp
+		case "backoff":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp time.Duration
+			tmp, v, err = cbor.ReadDurationBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Limits.Retry.Backoff = tmp
+		case "codes":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Limits.Retry.Codes = cbor.NullSlice(x.Limits.Retry.Codes)
+				break
+			}
+			var sz uint32
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap(x.Limits.Retry.Codes) >= int(sz) {
+				x.Limits.Retry.Codes = x.Limits.Retry.Codes[:sz]
+			} else {
+				x.Limits.Retry.Codes = make([]int, sz)
+			}
+			if sz > 0 {
+				_ = x.Limits.Retry.Codes[sz-1]
+			}
+			for iLimits_Retry_Codes := uint32(0); iLimits_Retry_Codes < sz; iLimits_Retry_Codes++ {
+				var tmp int
+				tmp, v, err = cbor.ReadIntBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Limits.Retry.Codes[iLimits_Retry_Codes] = tmp
+			}
+			if indef {
+				v = v[1:] // break
+			}
+		case "headers":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Headers = cbor.NullMap(x.Headers)
+				break
+			}
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if x.Headers == nil && sz > 0 {
+				x.Headers = make(map[string]string, sz)
+			} else if x.Headers != nil {
+				clear(x.Headers)
+			}
+			for iHeaders := uint32(0); iHeaders < sz; iHeaders++ {
+				var key string
+				key, v, err = cbor.ReadTrustedStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				var tmp string
+				tmp, v, err = cbor.ReadTrustedStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Headers[key] = tmp
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *Request) resetCBOR() {
+	var zero Request
+	x.URL = zero.URL
+	x.Limits.MaxBytes = zero.Limits.MaxBytes
+	x.Limits.Retry.Attempts = zero.Limits.Retry.Attempts
+	x.Limits.Retry.Backoff = zero.Limits.Retry.Backoff
+	x.Limits.Retry.Codes = x.Limits.Retry.Codes[:0]
+	clear(x.Headers)
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Request) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
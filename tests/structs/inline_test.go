@@ -0,0 +1,92 @@
+package structs
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+type requestDecoder struct {
+	name   string
+	decode func(dst *Request, b []byte) ([]byte, error)
+}
+
+var requestDecoders = []requestDecoder{
+	{
+		name:   "DecodeSafe",
+		decode: (*Request).DecodeSafe,
+	},
+	{
+		name:   "DecodeTrusted",
+		decode: (*Request).DecodeTrusted,
+	},
+}
+
+func TestRequestInlineRoundTrip(t *testing.T) {
+	orig := &Request{
+		URL: "https://example.com",
+		Limits: Limits{
+			MaxBytes: 1 << 20,
+			Retry:    RetryPolicy{Attempts: 3, Backoff: 250 * time.Millisecond, Codes: []int{502, 503}},
+		},
+		Headers: map[string]string{"accept": "application/cbor"},
+	}
+
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+
+	// The inlined groups' keys appear directly in the outer map.
+	iv, _, err := cbor.ReadInterfaceBytes(b)
+	if err != nil {
+		t.Fatalf("ReadInterfaceBytes error: %v", err)
+	}
+	m, ok := iv.(map[string]any)
+	if !ok {
+		t.Fatalf("decoded %T, want map[string]any", iv)
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	if got, want := strings.Join(keys, ","), "attempts,backoff,codes,headers,max_bytes,url"; got != want {
+		t.Fatalf("keys = %s, want %s", got, want)
+	}
+
+	for _, tc := range requestDecoders {
+		t.Run(tc.name, func(t *testing.T) {
+			var dst Request
+			rest, err := tc.decode(&dst, b)
+			if err != nil {
+				t.Fatalf("%s error: %v", tc.name, err)
+			}
+			if len(rest) != 0 {
+				t.Fatalf("%s leftover bytes: %d", tc.name, len(rest))
+			}
+			if dst.URL != orig.URL || dst.Limits.MaxBytes != orig.Limits.MaxBytes ||
+				dst.Limits.Retry.Attempts != 3 || dst.Limits.Retry.Backoff != orig.Limits.Retry.Backoff ||
+				!equalInts(dst.Limits.Retry.Codes, orig.Limits.Retry.Codes) || dst.Headers["accept"] != "application/cbor" {
+				t.Fatalf("%s mismatch: got %+v want %+v", tc.name, dst, *orig)
+			}
+		})
+	}
+
+	// omitempty inside an inlined group still drops the key and shrinks
+	// the outer map header.
+	b, err = (&Request{URL: "u"}).MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	sz, _, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		t.Fatalf("ReadMapHeaderBytes error: %v", err)
+	}
+	if sz != 3 {
+		t.Fatalf("map header = %d entries, want 3 (url, max_bytes, attempts)", sz)
+	}
+}
@@ -0,0 +1,94 @@
+package structs
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+// Run these under GOARCH=386 too (task test-386): int and uint are
+// written at 64 bits everywhere, and only decoding depends on their width.
+
+func TestPlatformIntWidthEncoding(t *testing.T) {
+	in := Scalars{I: math.MaxInt, U: math.MaxUint}
+	b, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	var wide struct {
+		I int64  `cbor:"i"`
+		U uint64 `cbor:"u"`
+	}
+	if err := cbor.Unmarshal(b, &wide); err != nil {
+		t.Fatalf("Unmarshal into 64-bit fields error: %v", err)
+	}
+	if wide.I != math.MaxInt || wide.U != math.MaxUint {
+		t.Fatalf("64-bit fields = %d, %d, want %d, %d", wide.I, wide.U, int64(math.MaxInt), uint64(math.MaxUint))
+	}
+	if got, want := cbor.AppendInt(nil, -1), cbor.AppendInt64(nil, -1); string(got) != string(want) {
+		t.Fatalf("AppendInt(-1) = %x, want %x", got, want)
+	}
+}
+
+func TestPlatformIntWidthDecoding(t *testing.T) {
+	// Every value fits a 64-bit int or uint; beyond 32 bits none fits
+	// a 32-bit one.
+	fits := strconv.IntSize == 64
+	ints := []int64{math.MaxInt32, math.MinInt32, math.MaxInt32 + 1, math.MinInt32 - 1, math.MaxInt64, math.MinInt64}
+	for _, v := range ints {
+		ok := fits || (v >= math.MinInt32 && v <= math.MaxInt32)
+		b := cbor.AppendMapHeader(nil, 1)
+		b = cbor.AppendString(b, "i")
+		b = cbor.AppendInt64(b, v)
+		for name, decode := range map[string]func(*Scalars, []byte) ([]byte, error){
+			"DecodeSafe":    (*Scalars).DecodeSafe,
+			"DecodeTrusted": (*Scalars).DecodeTrusted,
+		} {
+			var out Scalars
+			_, err := decode(&out, b)
+			var overflow cbor.IntOverflow
+			switch {
+			case ok && (err != nil || int64(out.I) != v):
+				t.Errorf("%s int %d = %d, %v", name, v, out.I, err)
+			case !ok && (!errors.As(err, &overflow) || overflow.FailedBitsize != 32 || overflow.Value != v):
+				t.Errorf("%s int %d error = %v, want IntOverflow of 32 bits", name, v, err)
+			}
+		}
+		var out struct {
+			I int `cbor:"i"`
+		}
+		if err := cbor.Unmarshal(b, &out); (err == nil) != ok {
+			t.Errorf("Unmarshal int %d error = %v", v, err)
+		}
+	}
+
+	uints := []uint64{math.MaxUint32, math.MaxUint32 + 1, math.MaxUint64}
+	for _, v := range uints {
+		ok := fits || v <= math.MaxUint32
+		b := cbor.AppendMapHeader(nil, 1)
+		b = cbor.AppendString(b, "u")
+		b = cbor.AppendUint64(b, v)
+		var out Scalars
+		_, err := out.DecodeSafe(b)
+		var overflow cbor.UintOverflow
+		switch {
+		case ok && (err != nil || uint64(out.U) != v):
+			t.Errorf("DecodeSafe uint %d = %d, %v", v, out.U, err)
+		case !ok && (!errors.As(err, &overflow) || overflow.FailedBitsize != 32):
+			t.Errorf("DecodeSafe uint %d error = %v, want UintOverflow of 32 bits", v, err)
+		}
+	}
+
+	// A []int and a map[string]int decode their elements the same way.
+	b := cbor.AppendMapHeader(nil, 1)
+	b = cbor.AppendString(b, "ints")
+	b = cbor.AppendArrayHeader(b, 1)
+	b = cbor.AppendInt64(b, math.MaxInt32+1)
+	var out Scalars
+	if _, err := out.DecodeSafe(b); (err == nil) != fits {
+		t.Errorf("DecodeSafe []int error = %v", err)
+	}
+}
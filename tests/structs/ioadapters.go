@@ -0,0 +1,11 @@
+package structs
+
+// Packet is generated with --io-adapters to exercise WriteTo and
+// ReadFrom.
+type Packet struct {
+	Seq     uint32 `cbor:"seq"`
+	Payload []byte `cbor:"payload"`
+}
+
+// PacketBatch is a named slice generated with --io-adapters.
+type PacketBatch []Packet
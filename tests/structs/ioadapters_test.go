@@ -0,0 +1,51 @@
+package structs
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestPacketIOAdapters(t *testing.T) {
+	in := &Packet{Seq: 7, Payload: []byte("ping")}
+	want, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	var wt io.WriterTo = in
+	n, err := wt.WriteTo(&buf)
+	if err != nil || n != int64(len(want)) || !bytes.Equal(buf.Bytes(), want) {
+		t.Fatalf("WriteTo = %d, %v, %x; want %d, %x", n, err, buf.Bytes(), len(want), want)
+	}
+
+	// ReadFrom takes exactly one item, leaving the next in r.
+	batch := PacketBatch{{Seq: 1}, {Seq: 2, Payload: []byte{0xff}}}
+	if _, err := batch.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo error: %v", err)
+	}
+	r := bytes.NewReader(append(buf.Bytes(), "tail"...))
+	var out Packet
+	if n, err := out.ReadFrom(r); err != nil || n != int64(len(want)) || !reflect.DeepEqual(&out, in) {
+		t.Fatalf("ReadFrom = %d, %v, %+v; want %d, %+v", n, err, out, len(want), in)
+	}
+	var gotBatch PacketBatch
+	var rf io.ReaderFrom = &gotBatch
+	if _, err := rf.ReadFrom(r); err != nil || !reflect.DeepEqual(gotBatch, batch) {
+		t.Fatalf("ReadFrom into PacketBatch = %v, %+v; want %+v", err, gotBatch, batch)
+	}
+	if rest, _ := io.ReadAll(r); string(rest) != "tail" {
+		t.Fatalf("ReadFrom left %q, want %q", rest, "tail")
+	}
+
+	// io.ReaderFrom reserves io.EOF for success.
+	if _, err := out.ReadFrom(bytes.NewReader(nil)); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("ReadFrom on empty reader error = %v, want io.ErrUnexpectedEOF", err)
+	}
+	if _, err := out.ReadFrom(bytes.NewReader(want[:len(want)-1])); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("ReadFrom on short item error = %v, want io.ErrUnexpectedEOF", err)
+	}
+}
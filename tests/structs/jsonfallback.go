@@ -0,0 +1,11 @@
+package structs
+
+import "github.com/delaneyj/cbor/tests/structs/legacy"
+
+// Invoice has fields of a type that only implements json.Marshaler; it is
+// generated with --allow-json-fallback.
+type Invoice struct {
+	ID       string        `cbor:"id"`
+	Total    legacy.Money  `cbor:"total"`
+	Discount *legacy.Money `cbor:"discount"`
+}
@@ -0,0 +1,52 @@
+package structs
+
+import (
+	"reflect"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+	"github.com/delaneyj/cbor/tests/structs/legacy"
+)
+
+func TestInvoiceJSONFallback(t *testing.T) {
+	tests := []Invoice{
+		{ID: "a", Total: legacy.Money{Cents: 1234, Currency: "USD"}},
+		{ID: "b", Total: legacy.Money{Cents: 5, Currency: "EUR"}, Discount: &legacy.Money{Cents: 100, Currency: "EUR"}},
+	}
+	for _, orig := range tests {
+		b, err := orig.MarshalCBOR(nil)
+		if err != nil {
+			t.Fatalf("MarshalCBOR error: %v", err)
+		}
+		d, _, err := cbor.DiagBytes(b)
+		if err != nil {
+			t.Fatalf("DiagBytes error: %v", err)
+		}
+		want := `{"id": "a", "total": "12.34 USD", "discount": null}`
+		if orig.ID == "b" {
+			want = `{"id": "b", "total": "0.05 EUR", "discount": "1.00 EUR"}`
+		}
+		if d != want {
+			t.Fatalf("encoded %s, want %s", d, want)
+		}
+
+		for _, decode := range []func(*Invoice, []byte) ([]byte, error){(*Invoice).DecodeSafe, (*Invoice).DecodeTrusted} {
+			var got Invoice
+			if _, err := decode(&got, b); err != nil {
+				t.Fatalf("decode error: %v", err)
+			}
+			if !reflect.DeepEqual(got, orig) {
+				t.Fatalf("round trip = %+v, want %+v", got, orig)
+			}
+		}
+	}
+
+	// A value the JSON method rejects fails the decode.
+	b := cbor.AppendMapHeader(nil, 1)
+	b = cbor.AppendString(b, "total")
+	b = cbor.AppendString(b, "twelve")
+	var got Invoice
+	if _, err := got.DecodeSafe(b); err == nil {
+		t.Fatalf("decoding a malformed Money succeeded")
+	}
+}
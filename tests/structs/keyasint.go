@@ -0,0 +1,34 @@
+package structs
+
+// Reading is a compact record keyed by integers (`keyasint`), mixing
+// dense small keys, a sparse large key, and a regular text key.
+type Reading struct {
+	Sensor string            `cbor:"1,keyasint"`
+	Value  float64           `cbor:"2,keyasint"`
+	Unit   string            `cbor:"3,keyasint,omitempty"`
+	Tags   []string          `cbor:"4,keyasint,omitempty"`
+	Meta   map[string]string `cbor:"1000,keyasint,omitempty"`
+	Note   string            `cbor:"note,omitempty"`
+}
+
+// DenseReading uses the keys 0..N-1 and the dense option, so it is
+// encoded as the array [sensor, value, unit] indexed by key rather than
+// as a map.
+type DenseReading struct {
+	_      struct{} `cbor:",dense"`
+	Value  float64  `cbor:"1,keyasint"`
+	Sensor string   `cbor:"0,keyasint"`
+	Unit   string   `cbor:"2,keyasint,omitempty"`
+}
+
+// CoseKey follows the COSE_Key layout (RFC 9052 §7), where common
+// parameters use positive labels and key-type parameters negative ones.
+type CoseKey struct {
+	Kty int64  `cbor:"1,keyasint"`
+	Kid []byte `cbor:"2,keyasint,omitempty"`
+	Alg int64  `cbor:"3,keyasint,omitempty"`
+	Crv int64  `cbor:"-1,keyasint"`
+	X   []byte `cbor:"-2,keyasint"`
+	Y   []byte `cbor:"-3,keyasint,omitempty"`
+	D   []byte `cbor:"-4,keyasint,omitempty"`
+}
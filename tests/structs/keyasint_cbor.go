@@ -0,0 +1,1281 @@
+// Code generated by cborgen DO NOT EDIT.
+
+package structs
+
+import (
+	"io"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+// Fail the build if a generated method drifts from the runtime interfaces.
+var (
+	_ cbor.Marshaler   = (*Reading)(nil)
+	_ cbor.Unmarshaler = (*Reading)(nil)
+	_ cbor.Marshaler   = (*DenseReading)(nil)
+	_ cbor.Unmarshaler = (*DenseReading)(nil)
+	_ cbor.Marshaler   = (*CoseKey)(nil)
+	_ cbor.Unmarshaler = (*CoseKey)(nil)
+)
+
+func (x Reading) Msgsize() (s int) {
+	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("1") + cbor.StringPrefixSize + len(x.Sensor) + cbor.StringPrefixSize + len("2") + cbor.Float64Size + cbor.StringPrefixSize + len("3") + cbor.StringPrefixSize + len(x.Unit) + cbor.StringPrefixSize + len("4") + cbor.ArrayHeaderSize + len(x.Tags)*cbor.StringPrefixSize + cbor.StringPrefixSize + len("1000") + cbor.MapHeaderSize + len(x.Meta)*(cbor.StringPrefixSize+cbor.StringPrefixSize) + cbor.StringPrefixSize + len("note") + cbor.StringPrefixSize + len(x.Note)
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *Reading) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *Reading) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *Reading) AppendCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	count := uint32(0)
+	count++
+	count++
+	if !(x.Unit == "") {
+		count++
+	}
+	if !(len(x.Tags) == 0) {
+		count++
+	}
+	if !(len(x.Meta) == 0) {
+		count++
+	}
+	if !(x.Note == "") {
+		count++
+	}
+	b = cbor.AppendMapHeader(b, count)
+	var err error
+	b = cbor.AppendInt64(b, 1)
+	b, err = cbor.AppendString(b, x.Sensor), nil
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendInt64(b, 2)
+	b, err = cbor.AppendFloat64(b, x.Value), nil
+	if err != nil {
+		return b, err
+	}
+	if !(x.Unit == "") {
+		b = cbor.AppendInt64(b, 3)
+		b, err = cbor.AppendString(b, x.Unit), nil
+		if err != nil {
+			return b, err
+		}
+	}
+	if !(len(x.Tags) == 0) {
+		if x.Tags == nil && cbor.NilContainers == cbor.NilAsNull {
+			b = cbor.AppendInt64(b, 4)
+			b = cbor.AppendNil(b)
+		} else {
+			b = cbor.AppendInt64(b, 4)
+			b = cbor.AppendArrayHeader(b, uint32(len(x.Tags)))
+			for _, v := range x.Tags {
+				b = cbor.AppendString(b, v)
+			}
+		}
+	}
+	if !(len(x.Meta) == 0) {
+		if x.Meta == nil && cbor.NilContainers == cbor.NilAsNull {
+			b = cbor.AppendInt64(b, 1000)
+			b = cbor.AppendNil(b)
+		} else {
+			b = cbor.AppendInt64(b, 1000)
+			if cbor.CanonicalMapEncode {
+				b, err = cbor.AppendMapDeterministic(b, x.Meta, cbor.EncKeyString, cbor.EncValString)
+				if err != nil {
+					return b, err
+				}
+			} else {
+				b = cbor.AppendMapHeader(b, uint32(len(x.Meta)))
+				for k, v := range x.Meta {
+					b = cbor.AppendString(b, k)
+					b = cbor.AppendString(b, v)
+				}
+			}
+		}
+	}
+	if !(x.Note == "") {
+		b = cbor.AppendString(b, "note")
+		b, err = cbor.AppendString(b, x.Note), nil
+		if err != nil {
+			return b, err
+		}
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *Reading) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *Reading) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
+	for i := uint32(0); i < sz; i++ {
+		if t := cbor.NextType(rest); t == cbor.UintType || t == cbor.IntType {
+			ikey, fits, v, err := cbor.ReadIntKeyBytes(rest)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+			}
+			if !fits {
+				// No field has a key outside the int64 range.
+				if rest, err = cbor.Skip(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "", len(b)-len(v))
+				}
+				continue
+			}
+			switch ikey {
+			case 1:
+				if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+					if err != nil {
+						return b, cbor.WrapDecodeError(err, "1", len(b)-len(v))
+					}
+					v = o
+					break
+				}
+				if cbor.IsTagged(v) {
+					if v, err = cbor.UntagBytes(v); err != nil {
+						return b, cbor.WrapDecodeError(err, "1", len(b)-len(v))
+					}
+				}
+
+				var tmp string
+				tmp, v, err = in.ReadStringBytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "1", len(b)-len(v))
+				}
+				x.Sensor = tmp
+			case 2:
+				if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+					if err != nil {
+						return b, cbor.WrapDecodeError(err, "2", len(b)-len(v))
+					}
+					v = o
+					break
+				}
+				if cbor.IsTagged(v) {
+					if v, err = cbor.UntagBytes(v); err != nil {
+						return b, cbor.WrapDecodeError(err, "2", len(b)-len(v))
+					}
+				}
+
+				var tmp float64
+				tmp, v, err = cbor.ReadFloat64Bytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "2", len(b)-len(v))
+				}
+				x.Value = tmp
+			case 3:
+				if o, skip, err := cbor.DuplicateField(v, seen[:], 2); skip || err != nil {
+					if err != nil {
+						return b, cbor.WrapDecodeError(err, "3", len(b)-len(v))
+					}
+					v = o
+					break
+				}
+				if cbor.IsTagged(v) {
+					if v, err = cbor.UntagBytes(v); err != nil {
+						return b, cbor.WrapDecodeError(err, "3", len(b)-len(v))
+					}
+				}
+
+				var tmp string
+				tmp, v, err = in.ReadStringBytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "3", len(b)-len(v))
+				}
+				x.Unit = tmp
+			case 4:
+				if o, skip, err := cbor.DuplicateField(v, seen[:], 3); skip || err != nil {
+					if err != nil {
+						return b, cbor.WrapDecodeError(err, "4", len(b)-len(v))
+					}
+					v = o
+					break
+				}
+				if cbor.IsTagged(v) {
+					if v, err = cbor.UntagBytes(v); err != nil {
+						return b, cbor.WrapDecodeError(err, "4", len(b)-len(v))
+					}
+				}
+				if cbor.IsNilOrUndefined(v) {
+					v = v[1:]
+					x.Tags = cbor.NullSlice(x.Tags)
+					break
+				}
+				var sz uint32
+				var indef bool
+				sz, indef, v, err = cbor.ReadArraySizeBytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "4", len(b)-len(v))
+				}
+				if cap(x.Tags) >= int(sz) {
+					x.Tags = x.Tags[:sz]
+				} else {
+					x.Tags = make([]string, sz)
+				}
+				if sz > 0 {
+					_ = x.Tags[sz-1]
+				}
+				for iTags := uint32(0); iTags < sz; iTags++ {
+					var tmp string
+					tmp, v, err = in.ReadStringBytes(v)
+					if err != nil {
+						return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, int(iTags)), "4", len(b)-len(v))
+					}
+					x.Tags[iTags] = tmp
+				}
+				if indef {
+					v = v[1:] // break
+				}
+			case 1000:
+				if o, skip, err := cbor.DuplicateField(v, seen[:], 4); skip || err != nil {
+					if err != nil {
+						return b, cbor.WrapDecodeError(err, "1000", len(b)-len(v))
+					}
+					v = o
+					break
+				}
+				if cbor.IsTagged(v) {
+					if v, err = cbor.UntagBytes(v); err != nil {
+						return b, cbor.WrapDecodeError(err, "1000", len(b)-len(v))
+					}
+				}
+				if cbor.IsNilOrUndefined(v) {
+					v = v[1:]
+					x.Meta = cbor.NullMap(x.Meta)
+					break
+				}
+				var sz uint32
+				sz, v, err = cbor.ReadMapHeaderBytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "1000", len(b)-len(v))
+				}
+				if x.Meta == nil && sz > 0 {
+					x.Meta = make(map[string]string, sz)
+				} else if x.Meta != nil {
+					clear(x.Meta)
+				}
+				for iMeta := uint32(0); iMeta < sz; iMeta++ {
+					var key string
+					key, v, err = in.ReadStringBytes(v)
+					if err != nil {
+						return b, cbor.WrapDecodeError(err, "1000", len(b)-len(v))
+					}
+					if _, dup := x.Meta[key]; dup {
+						var skip bool
+						if v, skip, err = cbor.SkipDuplicate(v); err != nil {
+							return b, cbor.WrapDecodeError(cbor.WrapDecodeKey(err, key), "1000", len(b)-len(v))
+						}
+						if skip {
+							continue
+						}
+					}
+					var tmp string
+					tmp, v, err = in.ReadStringBytes(v)
+					if err != nil {
+						return b, cbor.WrapDecodeError(cbor.WrapDecodeKey(err, key), "1000", len(b)-len(v))
+					}
+					x.Meta[key] = tmp
+				}
+			default:
+				v, err = cbor.Skip(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "", len(b)-len(v))
+				}
+			}
+			rest = v
+			continue
+		}
+		key, v, err := in.ReadStringBytes(rest)
+		if err != nil {
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+		}
+		switch key {
+		case "note":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 5); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "note", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "note", len(b)-len(v))
+				}
+			}
+
+			var tmp string
+			tmp, v, err = in.ReadStringBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "note", len(b)-len(v))
+			}
+			x.Note = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *Reading) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		if t := cbor.NextType(rest); t == cbor.UintType || t == cbor.IntType {
+			ikey, fits, v, err := cbor.ReadIntKeyBytes(rest)
+			if err != nil {
+				return b, err
+			}
+			if !fits {
+				if rest, err = cbor.Skip(v); err != nil {
+					return b, err
+				}
+				continue
+			}
+			switch ikey {
+			case 1:
+				if cbor.IsTagged(v) {
+					if v, err = cbor.UntagBytes(v); err != nil {
+						return b, err
+					}
+				}
+
+				x.Sensor, v, err = cbor.ReadTrustedStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+			case 2:
+				if cbor.IsTagged(v) {
+					if v, err = cbor.UntagBytes(v); err != nil {
+						return b, err
+					}
+				}
+
+				var tmp float64
+				tmp, v, err = cbor.ReadFloat64Bytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Value = tmp
+			case 3:
+				if cbor.IsTagged(v) {
+					if v, err = cbor.UntagBytes(v); err != nil {
+						return b, err
+					}
+				}
+
+				x.Unit, v, err = cbor.ReadTrustedStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+			case 4:
+				if cbor.IsTagged(v) {
+					if v, err = cbor.UntagBytes(v); err != nil {
+						return b, err
+					}
+				}
+				if cbor.IsNilOrUndefined(v) {
+					v = v[1:]
+					x.Tags = cbor.NullSlice(x.Tags)
+					break
+				}
+				var sz uint32
+				var indef bool
+				sz, indef, v, err = cbor.ReadArraySizeBytes(v)
+				if err != nil {
+					return b, err
+				}
+				if cap(x.Tags) >= int(sz) {
+					x.Tags = x.Tags[:sz]
+				} else {
+					x.Tags = make([]string, sz)
+				}
+				if sz > 0 {
+					_ = x.Tags[sz-1]
+				}
+				for iTags := uint32(0); iTags < sz; iTags++ {
+					var tmp string
+					tmp, v, err = cbor.ReadTrustedStringBytes(v)
+					if err != nil {
+						return b, err
+					}
+					x.Tags[iTags] = tmp
+				}
+				if indef {
+					v = v[1:] // break
+				}
+			case 1000:
+				if cbor.IsTagged(v) {
+					if v, err = cbor.UntagBytes(v); err != nil {
+						return b, err
+					}
+				}
+				if cbor.IsNilOrUndefined(v) {
+					v = v[1:]
+					x.Meta = cbor.NullMap(x.Meta)
+					break
+				}
+				var sz uint32
+				sz, v, err = cbor.ReadMapHeaderBytes(v)
+				if err != nil {
+					return b, err
+				}
+				if x.Meta == nil && sz > 0 {
+					x.Meta = make(map[string]string, sz)
+				} else if x.Meta != nil {
+					clear(x.Meta)
+				}
+				for iMeta := uint32(0); iMeta < sz; iMeta++ {
+					var key string
+					key, v, err = cbor.ReadTrustedStringBytes(v)
+					if err != nil {
+						return b, err
+					}
+					var tmp string
+					tmp, v, err = cbor.ReadTrustedStringBytes(v)
+					if err != nil {
+						return b, err
+					}
+					x.Meta[key] = tmp
+				}
+			default:
+				v, err = cbor.Skip(v)
+				if err != nil {
+					return b, err
+				}
+			}
+			rest = v
+			continue
+		}
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "note":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			x.Note, v, err = cbor.ReadTrustedStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *Reading) resetCBOR() {
+	var zero Reading
+	x.Sensor = zero.Sensor
+	x.Value = zero.Value
+	x.Unit = zero.Unit
+	x.Tags = x.Tags[:0]
+	clear(x.Meta)
+	x.Note = zero.Note
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Reading) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x DenseReading) Msgsize() (s int) {
+	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("0") + cbor.StringPrefixSize + len(x.Sensor) + cbor.StringPrefixSize + len("1") + cbor.Float64Size + cbor.StringPrefixSize + len("2") + cbor.StringPrefixSize + len(x.Unit)
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *DenseReading) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *DenseReading) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *DenseReading) AppendCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	var count uint32
+	switch {
+	case !(x.Unit == ""):
+		count = 3
+	default:
+		count = 2
+	}
+	b = cbor.AppendArrayHeader(b, count)
+	var err error
+	b, err = cbor.AppendString(b, x.Sensor), nil
+	if err != nil {
+		return b, err
+	}
+	b, err = cbor.AppendFloat64(b, x.Value), nil
+	if err != nil {
+		return b, err
+	}
+	if !(count <= 2) {
+		b, err = cbor.AppendString(b, x.Unit), nil
+		if err != nil {
+			return b, err
+		}
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *DenseReading) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *DenseReading) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, indef, rest, err := cbor.ReadArraySizeBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if sz > 3 && !cbor.TolerateExtraArrayElements {
+		return b, cbor.WrapDecodeError(cbor.ArrayError{Wanted: 3, Got: sz}, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		v := rest
+		switch i {
+		case 0:
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "0", len(b)-len(v))
+				}
+			}
+
+			var tmp string
+			tmp, v, err = in.ReadStringBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "0", len(b)-len(v))
+			}
+			x.Sensor = tmp
+		case 1:
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "1", len(b)-len(v))
+				}
+			}
+
+			var tmp float64
+			tmp, v, err = cbor.ReadFloat64Bytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "1", len(b)-len(v))
+			}
+			x.Value = tmp
+		case 2:
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "2", len(b)-len(v))
+				}
+			}
+
+			var tmp string
+			tmp, v, err = in.ReadStringBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "2", len(b)-len(v))
+			}
+			x.Unit = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "", len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if indef {
+		rest = rest[1:] // break
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *DenseReading) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, indef, rest, err := cbor.ReadArraySizeBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if sz > 3 && !cbor.TolerateExtraArrayElements {
+		return b, cbor.ArrayError{Wanted: 3, Got: sz}
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		v := rest
+		switch i {
+		case 0:
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			x.Sensor, v, err = cbor.ReadTrustedStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+		case 1:
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp float64
+			tmp, v, err = cbor.ReadFloat64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Value = tmp
+		case 2:
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			x.Unit, v, err = cbor.ReadTrustedStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	if indef {
+		rest = rest[1:] // break
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *DenseReading) resetCBOR() {
+	var zero DenseReading
+	x.Sensor = zero.Sensor
+	x.Value = zero.Value
+	x.Unit = zero.Unit
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *DenseReading) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x CoseKey) Msgsize() (s int) {
+	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("1") + cbor.Int64Size + cbor.StringPrefixSize + len("2") + cbor.BytesPrefixSize + len(x.Kid) + cbor.StringPrefixSize + len("3") + cbor.Int64Size + cbor.StringPrefixSize + len("-1") + cbor.Int64Size + cbor.StringPrefixSize + len("-2") + cbor.BytesPrefixSize + len(x.X) + cbor.StringPrefixSize + len("-3") + cbor.BytesPrefixSize + len(x.Y) + cbor.StringPrefixSize + len("-4") + cbor.BytesPrefixSize + len(x.D)
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *CoseKey) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *CoseKey) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *CoseKey) AppendCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	count := uint32(0)
+	count++
+	if !(len(x.Kid) == 0) {
+		count++
+	}
+	if !(x.Alg == 0) {
+		count++
+	}
+	count++
+	count++
+	if !(len(x.Y) == 0) {
+		count++
+	}
+	if !(len(x.D) == 0) {
+		count++
+	}
+	b = cbor.AppendMapHeader(b, count)
+	var err error
+	b = cbor.AppendInt64(b, 1)
+	b, err = cbor.AppendInt64(b, x.Kty), nil
+	if err != nil {
+		return b, err
+	}
+	if !(len(x.Kid) == 0) {
+		b = cbor.AppendInt64(b, 2)
+		if x.Kid == nil && cbor.NilContainers == cbor.NilAsNull {
+			b = cbor.AppendNil(b)
+		} else {
+			b, err = cbor.AppendBytes(b, x.Kid), nil
+			if err != nil {
+				return b, err
+			}
+		}
+	}
+	if !(x.Alg == 0) {
+		b = cbor.AppendInt64(b, 3)
+		b, err = cbor.AppendInt64(b, x.Alg), nil
+		if err != nil {
+			return b, err
+		}
+	}
+	b = cbor.AppendInt64(b, -1)
+	b, err = cbor.AppendInt64(b, x.Crv), nil
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendInt64(b, -2)
+	if x.X == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = cbor.AppendNil(b)
+	} else {
+		b, err = cbor.AppendBytes(b, x.X), nil
+		if err != nil {
+			return b, err
+		}
+	}
+	if !(len(x.Y) == 0) {
+		b = cbor.AppendInt64(b, -3)
+		if x.Y == nil && cbor.NilContainers == cbor.NilAsNull {
+			b = cbor.AppendNil(b)
+		} else {
+			b, err = cbor.AppendBytes(b, x.Y), nil
+			if err != nil {
+				return b, err
+			}
+		}
+	}
+	if !(len(x.D) == 0) {
+		b = cbor.AppendInt64(b, -4)
+		if x.D == nil && cbor.NilContainers == cbor.NilAsNull {
+			b = cbor.AppendNil(b)
+		} else {
+			b, err = cbor.AppendBytes(b, x.D), nil
+			if err != nil {
+				return b, err
+			}
+		}
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *CoseKey) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *CoseKey) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
+	for i := uint32(0); i < sz; i++ {
+		if t := cbor.NextType(rest); t == cbor.UintType || t == cbor.IntType {
+			ikey, fits, v, err := cbor.ReadIntKeyBytes(rest)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+			}
+			if !fits {
+				// No field has a key outside the int64 range.
+				if rest, err = cbor.Skip(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "", len(b)-len(v))
+				}
+				continue
+			}
+			switch ikey {
+			case 1:
+				if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+					if err != nil {
+						return b, cbor.WrapDecodeError(err, "1", len(b)-len(v))
+					}
+					v = o
+					break
+				}
+				if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+					if v, err = cbor.UntagBytes(v); err != nil {
+						return b, cbor.WrapDecodeError(err, "1", len(b)-len(v))
+					}
+				}
+
+				var tmp int64
+				tmp, v, err = cbor.ReadInt64Bytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "1", len(b)-len(v))
+				}
+				x.Kty = tmp
+			case 2:
+				if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+					if err != nil {
+						return b, cbor.WrapDecodeError(err, "2", len(b)-len(v))
+					}
+					v = o
+					break
+				}
+				if cbor.IsTagged(v) {
+					if v, err = cbor.UntagBytes(v); err != nil {
+						return b, cbor.WrapDecodeError(err, "2", len(b)-len(v))
+					}
+				}
+				if cbor.IsNilOrUndefined(v) {
+					v = v[1:]
+					x.Kid = cbor.NullSlice(x.Kid)
+					break
+				}
+				var tmp []byte
+				tmp, v, err = cbor.ReadBytesBytes(v, nil)
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "2", len(b)-len(v))
+				}
+				x.Kid = tmp
+			case 3:
+				if o, skip, err := cbor.DuplicateField(v, seen[:], 2); skip || err != nil {
+					if err != nil {
+						return b, cbor.WrapDecodeError(err, "3", len(b)-len(v))
+					}
+					v = o
+					break
+				}
+				if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+					if v, err = cbor.UntagBytes(v); err != nil {
+						return b, cbor.WrapDecodeError(err, "3", len(b)-len(v))
+					}
+				}
+
+				var tmp int64
+				tmp, v, err = cbor.ReadInt64Bytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "3", len(b)-len(v))
+				}
+				x.Alg = tmp
+			case -1:
+				if o, skip, err := cbor.DuplicateField(v, seen[:], 3); skip || err != nil {
+					if err != nil {
+						return b, cbor.WrapDecodeError(err, "-1", len(b)-len(v))
+					}
+					v = o
+					break
+				}
+				if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+					if v, err = cbor.UntagBytes(v); err != nil {
+						return b, cbor.WrapDecodeError(err, "-1", len(b)-len(v))
+					}
+				}
+
+				var tmp int64
+				tmp, v, err = cbor.ReadInt64Bytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "-1", len(b)-len(v))
+				}
+				x.Crv = tmp
+			case -2:
+				if o, skip, err := cbor.DuplicateField(v, seen[:], 4); skip || err != nil {
+					if err != nil {
+						return b, cbor.WrapDecodeError(err, "-2", len(b)-len(v))
+					}
+					v = o
+					break
+				}
+				if cbor.IsTagged(v) {
+					if v, err = cbor.UntagBytes(v); err != nil {
+						return b, cbor.WrapDecodeError(err, "-2", len(b)-len(v))
+					}
+				}
+				if cbor.IsNilOrUndefined(v) {
+					v = v[1:]
+					x.X = cbor.NullSlice(x.X)
+					break
+				}
+				var tmp []byte
+				tmp, v, err = cbor.ReadBytesBytes(v, nil)
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "-2", len(b)-len(v))
+				}
+				x.X = tmp
+			case -3:
+				if o, skip, err := cbor.DuplicateField(v, seen[:], 5); skip || err != nil {
+					if err != nil {
+						return b, cbor.WrapDecodeError(err, "-3", len(b)-len(v))
+					}
+					v = o
+					break
+				}
+				if cbor.IsTagged(v) {
+					if v, err = cbor.UntagBytes(v); err != nil {
+						return b, cbor.WrapDecodeError(err, "-3", len(b)-len(v))
+					}
+				}
+				if cbor.IsNilOrUndefined(v) {
+					v = v[1:]
+					x.Y = cbor.NullSlice(x.Y)
+					break
+				}
+				var tmp []byte
+				tmp, v, err = cbor.ReadBytesBytes(v, nil)
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "-3", len(b)-len(v))
+				}
+				x.Y = tmp
+			case -4:
+				if o, skip, err := cbor.DuplicateField(v, seen[:], 6); skip || err != nil {
+					if err != nil {
+						return b, cbor.WrapDecodeError(err, "-4", len(b)-len(v))
+					}
+					v = o
+					break
+				}
+				if cbor.IsTagged(v) {
+					if v, err = cbor.UntagBytes(v); err != nil {
+						return b, cbor.WrapDecodeError(err, "-4", len(b)-len(v))
+					}
+				}
+				if cbor.IsNilOrUndefined(v) {
+					v = v[1:]
+					x.D = cbor.NullSlice(x.D)
+					break
+				}
+				var tmp []byte
+				tmp, v, err = cbor.ReadBytesBytes(v, nil)
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "-4", len(b)-len(v))
+				}
+				x.D = tmp
+			default:
+				v, err = cbor.Skip(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "", len(b)-len(v))
+				}
+			}
+			rest = v
+			continue
+		}
+		key, v, err := in.ReadStringBytes(rest)
+		if err != nil {
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+		}
+		switch key {
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *CoseKey) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		if t := cbor.NextType(rest); t == cbor.UintType || t == cbor.IntType {
+			ikey, fits, v, err := cbor.ReadIntKeyBytes(rest)
+			if err != nil {
+				return b, err
+			}
+			if !fits {
+				if rest, err = cbor.Skip(v); err != nil {
+					return b, err
+				}
+				continue
+			}
+			switch ikey {
+			case 1:
+				if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+					if v, err = cbor.UntagBytes(v); err != nil {
+						return b, err
+					}
+				}
+
+				var tmp int64
+				tmp, v, err = cbor.ReadInt64Bytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Kty = tmp
+			case 2:
+				if cbor.IsTagged(v) {
+					if v, err = cbor.UntagBytes(v); err != nil {
+						return b, err
+					}
+				}
+				if cbor.IsNilOrUndefined(v) {
+					v = v[1:]
+					x.Kid = cbor.NullSlice(x.Kid)
+					break
+				}
+				var tmp []byte
+				tmp, v, err = cbor.ReadBytesBytes(v, nil)
+				if err != nil {
+					return b, err
+				}
+				x.Kid = tmp
+			case 3:
+				if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+					if v, err = cbor.UntagBytes(v); err != nil {
+						return b, err
+					}
+				}
+
+				var tmp int64
+				tmp, v, err = cbor.ReadInt64Bytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Alg = tmp
+			case -1:
+				if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+					if v, err = cbor.UntagBytes(v); err != nil {
+						return b, err
+					}
+				}
+
+				var tmp int64
+				tmp, v, err = cbor.ReadInt64Bytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Crv = tmp
+			case -2:
+				if cbor.IsTagged(v) {
+					if v, err = cbor.UntagBytes(v); err != nil {
+						return b, err
+					}
+				}
+				if cbor.IsNilOrUndefined(v) {
+					v = v[1:]
+					x.X = cbor.NullSlice(x.X)
+					break
+				}
+				var tmp []byte
+				tmp, v, err = cbor.ReadBytesBytes(v, nil)
+				if err != nil {
+					return b, err
+				}
+				x.X = tmp
+			case -3:
+				if cbor.IsTagged(v) {
+					if v, err = cbor.UntagBytes(v); err != nil {
+						return b, err
+					}
+				}
+				if cbor.IsNilOrUndefined(v) {
+					v = v[1:]
+					x.Y = cbor.NullSlice(x.Y)
+					break
+				}
+				var tmp []byte
+				tmp, v, err = cbor.ReadBytesBytes(v, nil)
+				if err != nil {
+					return b, err
+				}
+				x.Y = tmp
+			case -4:
+				if cbor.IsTagged(v) {
+					if v, err = cbor.UntagBytes(v); err != nil {
+						return b, err
+					}
+				}
+				if cbor.IsNilOrUndefined(v) {
+					v = v[1:]
+					x.D = cbor.NullSlice(x.D)
+					break
+				}
+				var tmp []byte
+				tmp, v, err = cbor.ReadBytesBytes(v, nil)
+				if err != nil {
+					return b, err
+				}
+				x.D = tmp
+			default:
+				v, err = cbor.Skip(v)
+				if err != nil {
+					return b, err
+				}
+			}
+			rest = v
+			continue
+		}
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *CoseKey) resetCBOR() {
+	var zero CoseKey
+	x.Kty = zero.Kty
+	x.Kid = x.Kid[:0]
+	x.Alg = zero.Alg
+	x.Crv = zero.Crv
+	x.X = x.X[:0]
+	x.Y = x.Y[:0]
+	x.D = x.D[:0]
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *CoseKey) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
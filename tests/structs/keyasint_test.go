@@ -0,0 +1,254 @@
+package structs
+
+import (
+	"encoding/hex"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+type readingDecoder struct {
+	name   string
+	decode func(dst *Reading, b []byte) ([]byte, error)
+}
+
+var readingDecoders = []readingDecoder{
+	{
+		name:   "DecodeSafe",
+		decode: (*Reading).DecodeSafe,
+	},
+	{
+		name:   "DecodeTrusted",
+		decode: (*Reading).DecodeTrusted,
+	},
+}
+
+func TestReadingKeyAsIntWire(t *testing.T) {
+	b, err := (&Reading{Sensor: "t", Value: 1.5}).MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	// {1: "t", 2: 1.5}
+	if got, want := hex.EncodeToString(b), "a2016174"+"02fb3ff8000000000000"; got != want {
+		t.Fatalf("encoding = %s, want %s", got, want)
+	}
+}
+
+func TestReadingKeyAsIntRoundTrip(t *testing.T) {
+	orig := &Reading{
+		Sensor: "temp-1",
+		Value:  21.5,
+		Unit:   "C",
+		Tags:   []string{"indoor"},
+		Meta:   map[string]string{"room": "lab"},
+		Note:   "calibrated",
+	}
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	for _, tc := range readingDecoders {
+		t.Run(tc.name, func(t *testing.T) {
+			var dst Reading
+			rest, err := tc.decode(&dst, b)
+			if err != nil {
+				t.Fatalf("%s error: %v", tc.name, err)
+			}
+			if len(rest) != 0 {
+				t.Fatalf("%s leftover bytes: %d", tc.name, len(rest))
+			}
+			if dst.Sensor != orig.Sensor || dst.Value != orig.Value || dst.Unit != orig.Unit ||
+				!equalStrings(dst.Tags, orig.Tags) || dst.Meta["room"] != "lab" || dst.Note != orig.Note {
+				t.Fatalf("%s mismatch: got %+v want %+v", tc.name, dst, *orig)
+			}
+		})
+	}
+}
+
+func TestReadingKeyAsIntSkipsUnknownKeys(t *testing.T) {
+	// {7: [1, 2], -3: "x", 1: "s", "other": true, 2: 0.5}
+	in := cbor.AppendMapHeader(nil, 5)
+	in = cbor.AppendInt64(in, 7)
+	in = cbor.AppendArrayHeader(in, 2)
+	in = cbor.AppendInt64(in, 1)
+	in = cbor.AppendInt64(in, 2)
+	in = cbor.AppendInt64(in, -3)
+	in = cbor.AppendString(in, "x")
+	in = cbor.AppendInt64(in, 1)
+	in = cbor.AppendString(in, "s")
+	in = cbor.AppendString(in, "other")
+	in = cbor.AppendBool(in, true)
+	in = cbor.AppendInt64(in, 2)
+	in = cbor.AppendFloat64(in, 0.5)
+
+	for _, tc := range readingDecoders {
+		t.Run(tc.name, func(t *testing.T) {
+			var dst Reading
+			rest, err := tc.decode(&dst, in)
+			if err != nil {
+				t.Fatalf("%s error: %v", tc.name, err)
+			}
+			if len(rest) != 0 || dst.Sensor != "s" || dst.Value != 0.5 {
+				t.Fatalf("%s = %+v (rest %d)", tc.name, dst, len(rest))
+			}
+		})
+	}
+}
+
+// readingFieldDecoders backs decodeReadingMapDispatch, which looks
+// integer keys up in a map instead of the generated switch.
+var readingFieldDecoders = map[int64]func(x *Reading, v []byte) ([]byte, error){
+	1: func(x *Reading, v []byte) (o []byte, err error) { x.Sensor, o, err = cbor.ReadStringBytes(v); return },
+	2: func(x *Reading, v []byte) (o []byte, err error) { x.Value, o, err = cbor.ReadFloat64Bytes(v); return },
+	3: func(x *Reading, v []byte) (o []byte, err error) { x.Unit, o, err = cbor.ReadStringBytes(v); return },
+}
+
+func decodeReadingMapDispatch(x *Reading, b []byte) ([]byte, error) {
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadInt64Bytes(rest)
+		if err != nil {
+			return b, err
+		}
+		if dec, ok := readingFieldDecoders[key]; ok {
+			v, err = dec(x, v)
+		} else {
+			v, err = cbor.Skip(v)
+		}
+		if err != nil {
+			return b, err
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+func BenchmarkReadingKeyDispatch(b *testing.B) {
+	in, err := (&Reading{Sensor: "temp-1", Value: 21.5, Unit: "C"}).MarshalCBOR(nil)
+	if err != nil {
+		b.Fatalf("MarshalCBOR error: %v", err)
+	}
+	b.Run("switch", func(b *testing.B) {
+		b.ReportAllocs()
+		var dst Reading
+		for i := 0; i < b.N; i++ {
+			if _, err := dst.DecodeSafe(in); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("map", func(b *testing.B) {
+		b.ReportAllocs()
+		var dst Reading
+		for i := 0; i < b.N; i++ {
+			if _, err := decodeReadingMapDispatch(&dst, in); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestDenseReadingWire(t *testing.T) {
+	b, err := (&DenseReading{Sensor: "t", Value: 1.5}).MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	// ["t", 1.5]: keys are array indexes and the empty trailing unit is
+	// dropped.
+	if got, want := hex.EncodeToString(b), "826174"+"fb3ff8000000000000"; got != want {
+		t.Fatalf("encoding = %s, want %s", got, want)
+	}
+
+	// The reflection fallback packs the same fields identically.
+	adhoc := struct {
+		_      struct{} `cbor:",dense"`
+		Unit   string   `cbor:"2,keyasint,omitempty"`
+		Sensor string   `cbor:"0,keyasint"`
+		Value  float64  `cbor:"1,keyasint"`
+	}{Sensor: "t", Value: 1.5}
+	rb, err := cbor.Marshal(adhoc)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	if got := hex.EncodeToString(rb); got != hex.EncodeToString(b) {
+		t.Fatalf("reflection encoding = %s, want %x", got, b)
+	}
+}
+
+func TestDenseReadingRoundTrip(t *testing.T) {
+	orig := &DenseReading{Sensor: "temp-1", Value: 21.5, Unit: "C"}
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	for _, decode := range []func(*DenseReading, []byte) ([]byte, error){
+		(*DenseReading).DecodeSafe,
+		(*DenseReading).DecodeTrusted,
+	} {
+		var got DenseReading
+		rest, err := decode(&got, b)
+		if err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+		if len(rest) != 0 || got != *orig {
+			t.Fatalf("decoded %+v with %d bytes left, want %+v", got, len(rest), *orig)
+		}
+	}
+}
+
+func TestCoseKeyNegativeKeysWire(t *testing.T) {
+	// RFC 9052 C.7.1: an EC2 public key {1: 2, -1: 1, -2: h'65ed', -3: h'1e52'},
+	// shortened coordinates.
+	k := CoseKey{Kty: 2, Crv: 1, X: []byte{0x65, 0xed}, Y: []byte{0x1e, 0x52}}
+	b, err := k.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	if got, want := hex.EncodeToString(b), "a4010220012142"+"65ed"+"2242"+"1e52"; got != want {
+		t.Fatalf("encoded %s, want %s", got, want)
+	}
+
+	for _, decode := range []func(*CoseKey, []byte) ([]byte, error){(*CoseKey).DecodeSafe, (*CoseKey).DecodeTrusted} {
+		var got CoseKey
+		if _, err := decode(&got, b); err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+		if got.Kty != 2 || got.Crv != 1 || hex.EncodeToString(got.X) != "65ed" || hex.EncodeToString(got.Y) != "1e52" {
+			t.Fatalf("decoded %+v", got)
+		}
+	}
+}
+
+func TestCoseKeySkipsOutOfRangeKeys(t *testing.T) {
+	// Keys beyond the int64 range name no field and are skipped like
+	// other unknown keys.
+	b := cbor.AppendMapHeader(nil, 4)
+	b = append(b, 0x1b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff) // 2^64-1
+	b = cbor.AppendString(b, "ignored")
+	b = append(b, 0x3b, 0x80, 0, 0, 0, 0, 0, 0, 0) // -2^63-1
+	b = cbor.AppendInt64(b, 7)
+	b = cbor.AppendInt64(b, -1)
+	b = cbor.AppendInt64(b, 4)
+	b = cbor.AppendInt64(b, 1)
+	b = cbor.AppendInt64(b, 2)
+
+	for _, decode := range []func(*CoseKey, []byte) ([]byte, error){(*CoseKey).DecodeSafe, (*CoseKey).DecodeTrusted} {
+		var got CoseKey
+		if _, err := decode(&got, b); err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+		if got.Crv != 4 || got.Kty != 2 {
+			t.Fatalf("decoded %+v", got)
+		}
+	}
+
+	var generic struct {
+		Crv int64 `cbor:"-1,keyasint"`
+	}
+	if err := cbor.Unmarshal(b, &generic); err != nil || generic.Crv != 4 {
+		t.Fatalf("Unmarshal = %+v, %v", generic, err)
+	}
+}
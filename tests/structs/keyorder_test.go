@@ -0,0 +1,114 @@
+package structs
+
+import (
+	"math/rand/v2"
+	"reflect"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+// decodable is a generated type decoded by both paths.
+type decodable interface {
+	cbor.Marshaler
+	DecodeSafe([]byte) ([]byte, error)
+	DecodeTrusted([]byte) ([]byte, error)
+}
+
+// mapEntries splits the encoded map b into its key/value pairs.
+func mapEntries(t *testing.T, b []byte) [][]byte {
+	t.Helper()
+	n, rest, err := cbor.ReadMapHeader(b)
+	if err != nil {
+		t.Fatalf("ReadMapHeader error: %v", err)
+	}
+	entries := make([][]byte, n)
+	for i := range entries {
+		o, err := cbor.Skip(rest)
+		if err == nil {
+			o, err = cbor.Skip(o)
+		}
+		if err != nil {
+			t.Fatalf("entry %d: %v", i, err)
+		}
+		entries[i] = rest[:len(rest)-len(o)]
+		rest = o
+	}
+	return entries
+}
+
+// unknownEntries returns entries under keys no fixture declares, with
+// nested values the decoders must skip whole. With intKeys it adds
+// integer keys too, for types that use keyasint.
+func unknownEntries(intKeys bool) [][]byte {
+	e := cbor.AppendString(nil, "zz-unknown")
+	e = cbor.AppendArrayHeader(e, 2)
+	e = cbor.AppendString(e, "x")
+	e = cbor.AppendMapHeader(e, 1)
+	e = cbor.AppendInt64(e, 1)
+	e = cbor.AppendBytes(e, []byte{1, 2})
+	out := [][]byte{e, cbor.AppendNil(cbor.AppendString(nil, "other"))}
+	if intKeys {
+		out = append(out, cbor.AppendFloat64(cbor.AppendInt64(nil, 999), 2.5))
+		out = append(out, cbor.AppendString(cbor.AppendInt64(nil, -77), "neg"))
+	}
+	return out
+}
+
+// checkAnyKeyOrder re-encodes v with its map entries shuffled, with and
+// without unknown entries interspersed, and checks that both decode paths
+// restore v.
+func checkAnyKeyOrder(t *testing.T, v decodable, newT func() decodable, unknown [][]byte) {
+	t.Helper()
+	b, err := v.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	entries := mapEntries(t, b)
+	rng := rand.New(rand.NewPCG(1, 2))
+	for round := range 50 {
+		shuffled := append([][]byte(nil), entries...)
+		if round%2 == 1 {
+			shuffled = append(shuffled, unknown...)
+		}
+		rng.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+		in := cbor.AppendMapHeader(nil, uint32(len(shuffled)))
+		for _, e := range shuffled {
+			in = append(in, e...)
+		}
+
+		for _, path := range []struct {
+			name   string
+			decode func(decodable, []byte) ([]byte, error)
+		}{
+			{"DecodeSafe", decodable.DecodeSafe},
+			{"DecodeTrusted", decodable.DecodeTrusted},
+		} {
+			got := newT()
+			rest, err := path.decode(got, in)
+			if err != nil {
+				t.Fatalf("round %d: %s error: %v", round, path.name, err)
+			}
+			if len(rest) != 0 {
+				t.Fatalf("round %d: %s left %d bytes", round, path.name, len(rest))
+			}
+			if !reflect.DeepEqual(got, v) {
+				t.Fatalf("round %d: %s = %+v, want %+v", round, path.name, got, v)
+			}
+		}
+	}
+}
+
+func TestDecodeAnyKeyOrder(t *testing.T) {
+	t.Run("Reading", func(t *testing.T) {
+		v := &Reading{Sensor: "t1", Value: 21.5, Unit: "C", Tags: []string{"lab", "north"}, Meta: map[string]string{"fw": "1.2"}, Note: "calibrated"}
+		checkAnyKeyOrder(t, v, func() decodable { return new(Reading) }, unknownEntries(true))
+	})
+	t.Run("Meter", func(t *testing.T) {
+		v := &Meter{ID: 7, Scale: 3, Name: "cpu", Unit: "%", Value: 0.5, Labels: []string{"host"}, Window: MeterWindow{From: 10, To: 20}}
+		checkAnyKeyOrder(t, v, func() decodable { return new(Meter) }, unknownEntries(true))
+	})
+	t.Run("Person", func(t *testing.T) {
+		checkAnyKeyOrder(t, NewFixturePerson(), func() decodable { return new(Person) }, unknownEntries(false))
+	})
+}
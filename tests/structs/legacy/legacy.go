@@ -0,0 +1,31 @@
+// Package legacy holds a type that only knows JSON, for the
+// --allow-json-fallback fixture.
+package legacy
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Money is an amount in cents that marshals to JSON as "12.34 USD".
+type Money struct {
+	Cents    int64
+	Currency string
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(fmt.Sprintf("%d.%02d %s", m.Cents/100, m.Cents%100, m.Currency))
+}
+
+func (m *Money) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	var units, cents int64
+	if _, err := fmt.Sscanf(s, "%d.%d %s", &units, &cents, &m.Currency); err != nil {
+		return fmt.Errorf("legacy: bad money %q: %w", s, err)
+	}
+	m.Cents = units*100 + cents
+	return nil
+}
@@ -0,0 +1,72 @@
+package structs
+
+import (
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+// swappedPerson encodes a Person whose name is a byte string and whose
+// data is a text string, the reverse of what MarshalCBOR writes.
+func swappedPerson(indefinite bool) []byte {
+	var b []byte
+	b = cbor.AppendMapHeader(b, 2)
+	b = cbor.AppendString(b, "name")
+	if indefinite {
+		b = append(b, 0x5f)
+		b = cbor.AppendBytes(b, []byte("Ad"))
+		b = cbor.AppendBytes(b, []byte("a"))
+		b = append(b, 0xff)
+	} else {
+		b = cbor.AppendBytes(b, []byte("Ada"))
+	}
+	b = cbor.AppendString(b, "data")
+	if indefinite {
+		b = append(b, 0x7f)
+		b = cbor.AppendString(b, "engi")
+		b = cbor.AppendString(b, "ne")
+		return append(b, 0xff)
+	}
+	return cbor.AppendString(b, "engine")
+}
+
+func TestByteStringMismatchStrict(t *testing.T) {
+	setOption(t, &cbor.LenientByteStrings, false)
+	for _, tc := range personDecoders {
+		t.Run(tc.name, func(t *testing.T) {
+			var dst Person
+			if _, err := tc.decode(&dst, swappedPerson(false)); err == nil {
+				t.Fatalf("%s accepted swapped string types: %+v", tc.name, dst)
+			}
+		})
+	}
+}
+
+func TestByteStringMismatchLenient(t *testing.T) {
+	setOption(t, &cbor.LenientByteStrings, true)
+	for _, indefinite := range []bool{false, true} {
+		for _, tc := range personDecoders {
+			t.Run(tc.name, func(t *testing.T) {
+				var dst Person
+				rest, err := tc.decode(&dst, swappedPerson(indefinite))
+				if err != nil {
+					t.Fatalf("%s error (indefinite=%v): %v", tc.name, indefinite, err)
+				}
+				if len(rest) != 0 {
+					t.Fatalf("%s leftover bytes: %d", tc.name, len(rest))
+				}
+				if dst.Name != "Ada" || string(dst.Data) != "engine" {
+					t.Fatalf("%s mismatch (indefinite=%v): got %+v", tc.name, indefinite, dst)
+				}
+			})
+		}
+	}
+}
+
+func TestByteStringLenientValidatesUTF8(t *testing.T) {
+	setOption(t, &cbor.LenientByteStrings, true)
+	b := cbor.AppendBytes(nil, []byte{0xff, 0xfe})
+	if _, _, err := cbor.ReadStringBytes(b); err != cbor.ErrInvalidUTF8 {
+		t.Fatalf("ReadStringBytes error = %v, want ErrInvalidUTF8", err)
+	}
+}
@@ -0,0 +1,15 @@
+package structs
+
+// ConsumerState and Stream exercise string-keyed maps whose values are
+// structs generated in the same file, pointers to them and slices.
+type ConsumerState struct {
+	Name      string `cbor:"name"`
+	Delivered uint64 `cbor:"delivered"`
+}
+
+type Stream struct {
+	Consumers map[string]ConsumerState   `cbor:"consumers"`
+	Pending   map[string]*ConsumerState  `cbor:"pending"`
+	Groups    map[string][]ConsumerState `cbor:"groups"`
+	Seqs      map[string][]uint64        `cbor:"seqs"`
+}
@@ -0,0 +1,839 @@
+// Code generated by cborgen DO NOT EDIT.
+
+package structs
+
+import (
+	"io"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+// Fail the build if a generated method drifts from the runtime interfaces.
+var (
+	_ cbor.Marshaler   = (*ConsumerState)(nil)
+	_ cbor.Unmarshaler = (*ConsumerState)(nil)
+	_ cbor.Marshaler   = (*Stream)(nil)
+	_ cbor.Unmarshaler = (*Stream)(nil)
+)
+
+func (x ConsumerState) Msgsize() (s int) {
+	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("name") + cbor.StringPrefixSize + len(x.Name) + cbor.StringPrefixSize + len("delivered") + cbor.Uint64Size
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *ConsumerState) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *ConsumerState) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *ConsumerState) AppendCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	b = cbor.AppendMapHeader(b, 2)
+	var err error
+	b = cbor.AppendString(b, "name")
+	b, err = cbor.AppendString(b, x.Name), nil
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "delivered")
+	b, err = cbor.AppendUint64(b, x.Delivered), nil
+	if err != nil {
+		return b, err
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *ConsumerState) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *ConsumerState) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := in.ReadStringBytes(rest)
+		if err != nil {
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+		}
+		switch key {
+		case "name":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
+				}
+			}
+
+			var tmp string
+			tmp, v, err = in.ReadStringBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
+			}
+			x.Name = tmp
+		case "delivered":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "delivered", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "delivered", len(b)-len(v))
+				}
+			}
+
+			var tmp uint64
+			tmp, v, err = cbor.ReadUint64Bytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "delivered", len(b)-len(v))
+			}
+			x.Delivered = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *ConsumerState) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "name":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			x.Name, v, err = cbor.ReadTrustedStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+		case "delivered":
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp uint64
+			tmp, v, err = cbor.ReadUint64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Delivered = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *ConsumerState) resetCBOR() {
+	var zero ConsumerState
+	x.Name = zero.Name
+	x.Delivered = zero.Delivered
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *ConsumerState) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x Stream) Msgsize() (s int) {
+	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("consumers") + cbor.MapHeaderSize + len(x.Consumers)*(cbor.StringPrefixSize+0)
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *Stream) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *Stream) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *Stream) AppendCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	b = cbor.AppendMapHeader(b, 4)
+	var err error
+	if x.Consumers == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = cbor.AppendString(b, "consumers")
+		b = cbor.AppendNil(b)
+	} else {
+		b = cbor.AppendString(b, "consumers")
+		if cbor.CanonicalMapEncode {
+			b, err = cbor.AppendMapDeterministic(b, x.Consumers, cbor.EncKeyString, func(b []byte, v ConsumerState) ([]byte, error) { return v.AppendCBOR(b) })
+			if err != nil {
+				return b, err
+			}
+		} else {
+			b = cbor.AppendMapHeader(b, uint32(len(x.Consumers)))
+			for k, v := range x.Consumers {
+				b = cbor.AppendString(b, k)
+				b, err = v.AppendCBOR(b)
+				if err != nil {
+					return b, err
+				}
+			}
+		}
+	}
+	if x.Pending == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = cbor.AppendString(b, "pending")
+		b = cbor.AppendNil(b)
+	} else {
+		b = cbor.AppendString(b, "pending")
+		if cbor.CanonicalMapEncode {
+			b, err = cbor.AppendMapDeterministic(b, x.Pending, cbor.EncKeyString, func(b []byte, v *ConsumerState) ([]byte, error) {
+				if v == nil {
+					return cbor.AppendNil(b), nil
+				}
+				return v.AppendCBOR(b)
+			})
+			if err != nil {
+				return b, err
+			}
+		} else {
+			b = cbor.AppendMapHeader(b, uint32(len(x.Pending)))
+			for k, v := range x.Pending {
+				b = cbor.AppendString(b, k)
+				if v == nil {
+					b = cbor.AppendNil(b)
+				} else {
+					b, err = v.AppendCBOR(b)
+					if err != nil {
+						return b, err
+					}
+				}
+			}
+		}
+	}
+	if x.Groups == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = cbor.AppendString(b, "groups")
+		b = cbor.AppendNil(b)
+	} else {
+		b = cbor.AppendString(b, "groups")
+		if cbor.CanonicalMapEncode {
+			b, err = cbor.AppendMapDeterministic(b, x.Groups, cbor.EncKeyString, func(b []byte, v []ConsumerState) ([]byte, error) {
+				b = cbor.AppendArrayHeader(b, uint32(len(v)))
+				for i := range v {
+					var err error
+					if b, err = v[i].AppendCBOR(b); err != nil {
+						return b, err
+					}
+				}
+				return b, nil
+			})
+			if err != nil {
+				return b, err
+			}
+		} else {
+			b = cbor.AppendMapHeader(b, uint32(len(x.Groups)))
+			for k, v := range x.Groups {
+				b = cbor.AppendString(b, k)
+				b = cbor.AppendArrayHeader(b, uint32(len(v)))
+				for i := range v {
+					b, err = v[i].AppendCBOR(b)
+					if err != nil {
+						return b, err
+					}
+				}
+			}
+		}
+	}
+	if x.Seqs == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = cbor.AppendString(b, "seqs")
+		b = cbor.AppendNil(b)
+	} else {
+		b = cbor.AppendString(b, "seqs")
+		if cbor.CanonicalMapEncode {
+			b, err = cbor.AppendMapDeterministic(b, x.Seqs, cbor.EncKeyString, func(b []byte, v []uint64) ([]byte, error) {
+				b = cbor.AppendArrayHeader(b, uint32(len(v)))
+				for i := range v {
+					b = cbor.AppendUint64(b, v[i])
+				}
+				return b, nil
+			})
+			if err != nil {
+				return b, err
+			}
+		} else {
+			b = cbor.AppendMapHeader(b, uint32(len(x.Seqs)))
+			for k, v := range x.Seqs {
+				b = cbor.AppendString(b, k)
+				b = cbor.AppendArrayHeader(b, uint32(len(v)))
+				for i := range v {
+					b = cbor.AppendUint64(b, v[i])
+				}
+			}
+		}
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *Stream) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *Stream) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := in.ReadStringBytes(rest)
+		if err != nil {
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+		}
+		switch key {
+		case "consumers":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "consumers", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "consumers", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Consumers = cbor.NullMap(x.Consumers)
+				break
+			}
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "consumers", len(b)-len(v))
+			}
+			if x.Consumers == nil && sz > 0 {
+				x.Consumers = make(map[string]ConsumerState, sz)
+			} else if x.Consumers != nil {
+				clear(x.Consumers)
+			}
+			for iConsumers := uint32(0); iConsumers < sz; iConsumers++ {
+				var key string
+				key, v, err = in.ReadStringBytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "consumers", len(b)-len(v))
+				}
+				if _, dup := x.Consumers[key]; dup {
+					var skip bool
+					if v, skip, err = cbor.SkipDuplicate(v); err != nil {
+						return b, cbor.WrapDecodeError(cbor.WrapDecodeKey(err, key), "consumers", len(b)-len(v))
+					}
+					if skip {
+						continue
+					}
+				}
+				var tmp ConsumerState
+				v, err = (&tmp).DecodeInterned(v, in)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeKey(err, key), "consumers", len(b)-len(v))
+				}
+				x.Consumers[key] = tmp
+			}
+		case "pending":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "pending", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "pending", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Pending = cbor.NullMap(x.Pending)
+				break
+			}
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "pending", len(b)-len(v))
+			}
+			if x.Pending == nil && sz > 0 {
+				x.Pending = make(map[string]*ConsumerState, sz)
+			} else if x.Pending != nil {
+				clear(x.Pending)
+			}
+			for iPending := uint32(0); iPending < sz; iPending++ {
+				var key string
+				key, v, err = in.ReadStringBytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "pending", len(b)-len(v))
+				}
+				if _, dup := x.Pending[key]; dup {
+					var skip bool
+					if v, skip, err = cbor.SkipDuplicate(v); err != nil {
+						return b, cbor.WrapDecodeError(cbor.WrapDecodeKey(err, key), "pending", len(b)-len(v))
+					}
+					if skip {
+						continue
+					}
+				}
+				if cbor.IsNilOrUndefined(v) {
+					v = v[1:]
+					x.Pending[key] = nil
+					continue
+				}
+				tmp := new(ConsumerState)
+				v, err = tmp.DecodeInterned(v, in)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeKey(err, key), "pending", len(b)-len(v))
+				}
+				x.Pending[key] = tmp
+			}
+		case "groups":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 2); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "groups", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "groups", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Groups = cbor.NullMap(x.Groups)
+				break
+			}
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "groups", len(b)-len(v))
+			}
+			if x.Groups == nil && sz > 0 {
+				x.Groups = make(map[string][]ConsumerState, sz)
+			} else if x.Groups != nil {
+				clear(x.Groups)
+			}
+			for iGroups := uint32(0); iGroups < sz; iGroups++ {
+				var key string
+				key, v, err = in.ReadStringBytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "groups", len(b)-len(v))
+				}
+				if _, dup := x.Groups[key]; dup {
+					var skip bool
+					if v, skip, err = cbor.SkipDuplicate(v); err != nil {
+						return b, cbor.WrapDecodeError(cbor.WrapDecodeKey(err, key), "groups", len(b)-len(v))
+					}
+					if skip {
+						continue
+					}
+				}
+				var n uint32
+				var indef bool
+				n, indef, v, err = cbor.ReadArraySizeBytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeKey(err, key), "groups", len(b)-len(v))
+				}
+				tmp := make([]ConsumerState, n)
+				for j := range tmp {
+					v, err = tmp[j].DecodeInterned(v, in)
+					if err != nil {
+						return b, cbor.WrapDecodeError(cbor.WrapDecodeKey(cbor.WrapDecodeIndex(err, j), key), "groups", len(b)-len(v))
+					}
+				}
+				if indef {
+					v = v[1:] // break
+				}
+				x.Groups[key] = tmp
+			}
+		case "seqs":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 3); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "seqs", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "seqs", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Seqs = cbor.NullMap(x.Seqs)
+				break
+			}
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "seqs", len(b)-len(v))
+			}
+			if x.Seqs == nil && sz > 0 {
+				x.Seqs = make(map[string][]uint64, sz)
+			} else if x.Seqs != nil {
+				clear(x.Seqs)
+			}
+			for iSeqs := uint32(0); iSeqs < sz; iSeqs++ {
+				var key string
+				key, v, err = in.ReadStringBytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "seqs", len(b)-len(v))
+				}
+				if _, dup := x.Seqs[key]; dup {
+					var skip bool
+					if v, skip, err = cbor.SkipDuplicate(v); err != nil {
+						return b, cbor.WrapDecodeError(cbor.WrapDecodeKey(err, key), "seqs", len(b)-len(v))
+					}
+					if skip {
+						continue
+					}
+				}
+				var n uint32
+				var indef bool
+				n, indef, v, err = cbor.ReadArraySizeBytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeKey(err, key), "seqs", len(b)-len(v))
+				}
+				tmp := make([]uint64, n)
+				for j := range tmp {
+					tmp[j], v, err = cbor.ReadUint64Bytes(v)
+					if err != nil {
+						return b, cbor.WrapDecodeError(cbor.WrapDecodeKey(cbor.WrapDecodeIndex(err, j), key), "seqs", len(b)-len(v))
+					}
+				}
+				if indef {
+					v = v[1:] // break
+				}
+				x.Seqs[key] = tmp
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *Stream) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "consumers":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Consumers = cbor.NullMap(x.Consumers)
+				break
+			}
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if x.Consumers == nil && sz > 0 {
+				x.Consumers = make(map[string]ConsumerState, sz)
+			} else if x.Consumers != nil {
+				clear(x.Consumers)
+			}
+			for iConsumers := uint32(0); iConsumers < sz; iConsumers++ {
+				var key string
+				key, v, err = cbor.ReadTrustedStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				var tmp ConsumerState
+				v, err = (&tmp).DecodeTrusted(v)
+				if err != nil {
+					return b, err
+				}
+				x.Consumers[key] = tmp
+			}
+		case "pending":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Pending = cbor.NullMap(x.Pending)
+				break
+			}
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if x.Pending == nil && sz > 0 {
+				x.Pending = make(map[string]*ConsumerState, sz)
+			} else if x.Pending != nil {
+				clear(x.Pending)
+			}
+			for iPending := uint32(0); iPending < sz; iPending++ {
+				var key string
+				key, v, err = cbor.ReadTrustedStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				if cbor.IsNilOrUndefined(v) {
+					v = v[1:]
+					x.Pending[key] = nil
+					continue
+				}
+				tmp := new(ConsumerState)
+				v, err = tmp.DecodeTrusted(v)
+				if err != nil {
+					return b, err
+				}
+				x.Pending[key] = tmp
+			}
+		case "groups":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Groups = cbor.NullMap(x.Groups)
+				break
+			}
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if x.Groups == nil && sz > 0 {
+				x.Groups = make(map[string][]ConsumerState, sz)
+			} else if x.Groups != nil {
+				clear(x.Groups)
+			}
+			for iGroups := uint32(0); iGroups < sz; iGroups++ {
+				var key string
+				key, v, err = cbor.ReadTrustedStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				var n uint32
+				var indef bool
+				n, indef, v, err = cbor.ReadArraySizeBytes(v)
+				if err != nil {
+					return b, err
+				}
+				tmp := make([]ConsumerState, n)
+				for j := range tmp {
+					v, err = tmp[j].DecodeTrusted(v)
+					if err != nil {
+						return b, err
+					}
+				}
+				if indef {
+					v = v[1:] // break
+				}
+				x.Groups[key] = tmp
+			}
+		case "seqs":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Seqs = cbor.NullMap(x.Seqs)
+				break
+			}
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if x.Seqs == nil && sz > 0 {
+				x.Seqs = make(map[string][]uint64, sz)
+			} else if x.Seqs != nil {
+				clear(x.Seqs)
+			}
+			for iSeqs := uint32(0); iSeqs < sz; iSeqs++ {
+				var key string
+				key, v, err = cbor.ReadTrustedStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				var n uint32
+				var indef bool
+				n, indef, v, err = cbor.ReadArraySizeBytes(v)
+				if err != nil {
+					return b, err
+				}
+				tmp := make([]uint64, n)
+				for j := range tmp {
+					tmp[j], v, err = cbor.ReadUint64Bytes(v)
+					if err != nil {
+						return b, err
+					}
+				}
+				if indef {
+					v = v[1:] // break
+				}
+				x.Seqs[key] = tmp
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *Stream) resetCBOR() {
+	clear(x.Consumers)
+	clear(x.Pending)
+	clear(x.Groups)
+	clear(x.Seqs)
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Stream) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
@@ -0,0 +1,149 @@
+package structs
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+var streamDecoders = []struct {
+	name   string
+	decode func(dst *Stream, b []byte) ([]byte, error)
+}{
+	{"DecodeSafe", (*Stream).DecodeSafe},
+	{"DecodeTrusted", (*Stream).DecodeTrusted},
+}
+
+func TestStreamMapsRoundTrip(t *testing.T) {
+	orig := &Stream{
+		Consumers: map[string]ConsumerState{
+			"a": {Name: "a", Delivered: 1},
+			"b": {Name: "b", Delivered: 2},
+		},
+		Pending: map[string]*ConsumerState{
+			"p": {Name: "p", Delivered: 3},
+			"q": nil,
+		},
+		Groups: map[string][]ConsumerState{
+			"g":     {{Name: "x", Delivered: 4}, {Name: "y"}},
+			"empty": {},
+		},
+		Seqs: map[string][]uint64{"s": {1, 1 << 40}},
+	}
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	for _, tc := range streamDecoders {
+		t.Run(tc.name, func(t *testing.T) {
+			// Stale entries must not survive into the decoded maps.
+			dst := Stream{Consumers: map[string]ConsumerState{"stale": {}}}
+			rest, err := tc.decode(&dst, b)
+			if err != nil {
+				t.Fatalf("decode error: %v", err)
+			}
+			if len(rest) != 0 {
+				t.Fatalf("leftover bytes: %d", len(rest))
+			}
+			if !reflect.DeepEqual(&dst, orig) {
+				t.Fatalf("round trip mismatch:\n got %+v\nwant %+v", dst, *orig)
+			}
+		})
+	}
+}
+
+func TestStreamMapsCanonical(t *testing.T) {
+	cbor.CanonicalMapEncode = true
+	defer func() { cbor.CanonicalMapEncode = false }()
+
+	orig := &Stream{
+		Groups: map[string][]ConsumerState{"b": {{Name: "x"}}, "a": nil},
+		Seqs:   map[string][]uint64{"z": {9}, "y": {}},
+	}
+	b1, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	b2, _ := orig.MarshalCBOR(nil)
+	if string(b1) != string(b2) {
+		t.Fatalf("canonical encoding not stable")
+	}
+	var dst Stream
+	if _, err := dst.DecodeSafe(b1); err != nil {
+		t.Fatalf("DecodeSafe error: %v", err)
+	}
+	if len(dst.Groups["b"]) != 1 || dst.Groups["b"][0].Name != "x" || len(dst.Seqs["z"]) != 1 {
+		t.Fatalf("decoded %+v", dst)
+	}
+}
+
+func TestStreamMapsDuplicateKey(t *testing.T) {
+	entry := func(b []byte, name string) []byte {
+		b = cbor.AppendString(b, name)
+		s := ConsumerState{Name: name}
+		b, _ = s.MarshalCBOR(b)
+		return b
+	}
+	b := cbor.AppendMapHeader(nil, 1)
+	b = cbor.AppendString(b, "consumers")
+	b = cbor.AppendMapHeader(b, 2)
+	b = entry(b, "dup")
+	b = entry(b, "dup")
+
+	var dst Stream
+	_, err := dst.DecodeSafe(b)
+	if !errors.Is(err, cbor.ErrDuplicateMapKey) {
+		t.Fatalf("DecodeSafe error = %v, want ErrDuplicateMapKey", err)
+	}
+	if want := `consumers["dup"]`; !containsPath(err, want) {
+		t.Fatalf("error %v does not locate %s", err, want)
+	}
+
+	// The Trusted path does not look for duplicates; the last entry wins.
+	dst = Stream{}
+	if _, err := dst.DecodeTrusted(b); err != nil {
+		t.Fatalf("DecodeTrusted error: %v", err)
+	}
+	if len(dst.Consumers) != 1 {
+		t.Fatalf("Consumers = %v", dst.Consumers)
+	}
+}
+
+func TestStreamMapsSliceElementError(t *testing.T) {
+	b := cbor.AppendMapHeader(nil, 1)
+	b = cbor.AppendString(b, "groups")
+	b = cbor.AppendMapHeader(b, 1)
+	b = cbor.AppendString(b, "g")
+	b = cbor.AppendArrayHeader(b, 1)
+	b = cbor.AppendInt(b, 7) // not a ConsumerState map
+
+	var dst Stream
+	_, err := dst.DecodeSafe(b)
+	if want := `groups["g"][0]`; !containsPath(err, want) {
+		t.Fatalf("error %v does not locate %s", err, want)
+	}
+}
+
+func containsPath(err error, path string) bool {
+	var de *cbor.DecodeError
+	return errors.As(err, &de) && de.Path == path
+}
+
+func TestStreamMaxElements(t *testing.T) {
+	defer func(n int) { cbor.MaxElements = n }(cbor.MaxElements)
+	cbor.MaxElements = 3
+
+	orig := &Stream{Seqs: map[string][]uint64{"s": {1, 2, 3, 4}}}
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	for _, tc := range streamDecoders {
+		var dst Stream
+		if _, err := tc.decode(&dst, b); !errors.Is(err, cbor.ErrContainerTooLarge) {
+			t.Errorf("%s: error = %v, want ErrContainerTooLarge", tc.name, err)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package structs
+
+import (
+	"fmt"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+// Celsius is a temperature in tenths of a degree with hand-written CBOR
+// methods, which write it as a decimal fraction.
+type Celsius int32
+
+// MarshalCBOR appends c as a tag 4 decimal fraction with scale 1.
+func (c Celsius) MarshalCBOR(b []byte) ([]byte, error) {
+	return cbor.AppendDecimal(b, cbor.Decimal{Scale: 1, Value: int64(c)}), nil
+}
+
+// UnmarshalCBOR reads a decimal fraction with scale 1 into c.
+func (c *Celsius) UnmarshalCBOR(b []byte) ([]byte, error) {
+	d, o, err := cbor.ReadDecimalBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if d.Scale != 1 || int64(Celsius(d.Value)) != d.Value {
+		return b, fmt.Errorf("structs: %s is not a Celsius", d)
+	}
+	*c = Celsius(d.Value)
+	return o, nil
+}
+
+// Forecast exercises slices of types with their own CBOR methods, which
+// generated code encodes by calling each element's method directly.
+type Forecast struct {
+	Highs []Celsius      `cbor:"highs"`
+	Lows  []*Celsius     `cbor:"lows"`
+	Exact []cbor.Decimal `cbor:"exact"`
+}
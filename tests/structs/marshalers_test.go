@@ -0,0 +1,94 @@
+package structs
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+func newForecast() Forecast {
+	low := Celsius(-42)
+	return Forecast{
+		Highs: []Celsius{315, 298, 330},
+		Lows:  []*Celsius{&low, nil},
+		Exact: []cbor.Decimal{{Scale: 2, Value: 1999}, {Scale: 0, Value: 7}},
+	}
+}
+
+func TestForecastRoundTrip(t *testing.T) {
+	in := newForecast()
+	b, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	if got, want := diagOf(t, &in), `{"highs": [4([-1, 315]), 4([-1, 298]), 4([-1, 330])], "lows": [4([-1, -42]), null], "exact": [4([-2, 1999]), 4([0, 7])]}`; got != want {
+		t.Fatalf("diag = %s, want %s", got, want)
+	}
+	for name, decode := range map[string]func(*Forecast, []byte) ([]byte, error){
+		"DecodeSafe":    (*Forecast).DecodeSafe,
+		"DecodeTrusted": (*Forecast).DecodeTrusted,
+	} {
+		var out Forecast
+		if _, err := decode(&out, b); err != nil {
+			t.Fatalf("%s error: %v", name, err)
+		}
+		if !reflect.DeepEqual(out, in) {
+			t.Fatalf("%s = %+v, want %+v", name, out, in)
+		}
+	}
+
+	// An element rejected by its UnmarshalCBOR is reported at its index.
+	bad := cbor.AppendMapHeader(nil, 1)
+	bad = cbor.AppendString(bad, "highs")
+	bad = cbor.AppendArrayHeader(bad, 2)
+	bad = cbor.AppendDecimal(bad, cbor.Decimal{Scale: 1, Value: 1})
+	bad = cbor.AppendDecimal(bad, cbor.Decimal{Scale: 2, Value: 1})
+	var out Forecast
+	_, err = out.DecodeSafe(bad)
+	var de *cbor.DecodeError
+	if !errors.As(err, &de) || de.Path != "highs[1]" {
+		t.Fatalf("DecodeSafe error = %v, want one at highs[1]", err)
+	}
+}
+
+func TestForecastAppendAllocs(t *testing.T) {
+	// Each element's MarshalCBOR is called in place, so encoding into a
+	// buffer with room to spare does not allocate: no element is boxed
+	// into an interface.
+	in := newForecast()
+	buf := make([]byte, 0, 256)
+	allocs := testing.AllocsPerRun(100, func() {
+		if _, err := in.AppendCBOR(buf); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Fatalf("AppendCBOR allocated %v times", allocs)
+	}
+
+	highs := in.Highs
+	allocs = testing.AllocsPerRun(100, func() {
+		if _, err := cbor.AppendSliceMarshaler(buf, highs); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Fatalf("AppendSliceMarshaler allocated %v times", allocs)
+	}
+}
+
+func BenchmarkForecastAppendCBOR(b *testing.B) {
+	in := newForecast()
+	for i := 0; i < 61; i++ {
+		in.Highs = append(in.Highs, Celsius(i))
+	}
+	buf := make([]byte, 0, 1024)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := in.AppendCBOR(buf); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
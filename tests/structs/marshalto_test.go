@@ -0,0 +1,73 @@
+package structs
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+// shortWriter accepts at most n bytes per Write without reporting an error.
+type shortWriter struct{ n int }
+
+func (w shortWriter) Write(p []byte) (int, error) { return min(len(p), w.n), nil }
+
+type failWriter struct{}
+
+var errWrite = errors.New("write failed")
+
+func (failWriter) Write(p []byte) (int, error) { return 0, errWrite }
+
+func TestMarshalCBORTo(t *testing.T) {
+	in := Person{Name: "Ada", Age: 36, Data: bytes.Repeat([]byte{7}, 2000)}
+	want, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	n, err := in.MarshalCBORTo(&buf)
+	if err != nil || n != len(want) || !bytes.Equal(buf.Bytes(), want) {
+		t.Fatalf("MarshalCBORTo = %d, %v; wrote % x, want % x", n, err, buf.Bytes(), want)
+	}
+
+	// Types without Msgsize grow the pooled buffer as needed.
+	name, count := "p", 3
+	patch := &Patch{Name: &name, Count: &count}
+	want, err = patch.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	if n, err := patch.MarshalCBORTo(&buf); err != nil || n != len(want) || !bytes.Equal(buf.Bytes(), want) {
+		t.Fatalf("Patch MarshalCBORTo = %d, %v", n, err)
+	}
+
+	var nilPerson *Person
+	buf.Reset()
+	if n, err := nilPerson.MarshalCBORTo(&buf); err != nil || n != 1 || buf.Bytes()[0] != 0xf6 {
+		t.Fatalf("nil MarshalCBORTo = %d, %v, % x", n, err, buf.Bytes())
+	}
+}
+
+func TestMarshalCBORToWriteErrors(t *testing.T) {
+	in := Person{Name: "Ada", Age: 36}
+	if _, err := in.MarshalCBORTo(failWriter{}); !errors.Is(err, errWrite) {
+		t.Fatalf("failing writer: error = %v", err)
+	}
+	if n, err := in.MarshalCBORTo(shortWriter{n: 3}); n != 3 || !errors.Is(err, io.ErrShortWrite) {
+		t.Fatalf("short writer: %d, %v, want 3, io.ErrShortWrite", n, err)
+	}
+}
+
+func TestMarshalCBORToAllocs(t *testing.T) {
+	in := Person{Name: "Ada", Age: 36, Data: []byte{1, 2, 3}}
+	allocs := testing.AllocsPerRun(100, func() {
+		if _, err := in.MarshalCBORTo(io.Discard); err != nil {
+			t.Fatal(err)
+		}
+	})
+	// Returning the scratch buffer to its sync.Pool boxes the slice header.
+	if allocs > 1 {
+		t.Fatalf("MarshalCBORTo allocates %v times per call, want at most 1", allocs)
+	}
+}
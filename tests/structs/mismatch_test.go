@@ -0,0 +1,121 @@
+package structs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+// nestedWith encodes a Nested map holding a valid "id" followed by key
+// with the raw value val, returning it with the offset of val.
+func nestedWith(key string, val []byte) ([]byte, int) {
+	b := cbor.AppendMapHeader(nil, 2)
+	b = cbor.AppendString(b, "id")
+	b = cbor.AppendString(b, "n1")
+	b = cbor.AppendString(b, key)
+	off := len(b)
+	return append(b, val...), off
+}
+
+// nestedBase wraps a single-entry Scalars map in the "base" field of a
+// Nested, returning the offset of the entry's value.
+func nestedBase(key string, val []byte) ([]byte, int) {
+	inner := cbor.AppendMapHeader(nil, 1)
+	inner = cbor.AppendString(inner, key)
+	valOff := len(inner)
+	inner = append(inner, val...)
+	b, off := nestedWith("base", inner)
+	return b, off + valOff
+}
+
+func TestDecodeHeterogeneousFields(t *testing.T) {
+	// Keys arrive out of declaration order, each dispatched to its own
+	// typed reader.
+	b := cbor.AppendMapHeader(nil, 2)
+	b = cbor.AppendString(b, "base")
+	b = cbor.AppendMapHeader(b, 3)
+	b = cbor.AppendString(b, "names")
+	b = cbor.AppendArrayHeader(b, 1)
+	b = cbor.AppendString(b, "x")
+	b = cbor.AppendString(b, "i")
+	b = cbor.AppendInt(b, -7)
+	b = cbor.AppendString(b, "s")
+	b = cbor.AppendString(b, "str")
+	b = cbor.AppendString(b, "id")
+	b = cbor.AppendString(b, "n1")
+
+	var n Nested
+	if _, err := n.DecodeSafe(b); err != nil {
+		t.Fatalf("DecodeSafe error: %v", err)
+	}
+	if n.ID != "n1" || n.Base.I != -7 || n.Base.S != "str" || len(n.Base.Names) != 1 || n.Base.Names[0] != "x" {
+		t.Fatalf("decoded %+v", n)
+	}
+}
+
+func TestDecodeFieldTypeMismatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		b         []byte
+		off       int
+		path      string
+		want, got uint8 // major types
+	}{
+		{name: "int as text", path: "base.i", want: 0, got: 3},
+		{name: "string as int", path: "id", want: 3, got: 0},
+		{name: "struct as array", path: "base", want: 5, got: 4},
+		{name: "uint as negative", path: "base.u8", want: 0, got: 1},
+		{name: "float as text", path: "base.f64", want: 7, got: 3},
+		{name: "slice as map", path: "base.ints", want: 4, got: 5},
+		{name: "bytes as text", path: "base.data", want: 2, got: 3},
+	}
+	tests[0].b, tests[0].off = nestedBase("i", cbor.AppendString(nil, "7"))
+	tests[1].b = cbor.AppendMapHeader(nil, 1)
+	tests[1].b = cbor.AppendString(tests[1].b, "id")
+	tests[1].off = len(tests[1].b)
+	tests[1].b = cbor.AppendInt(tests[1].b, 1)
+	tests[2].b, tests[2].off = nestedWith("base", cbor.AppendArrayHeader(nil, 0))
+	tests[3].b, tests[3].off = nestedBase("u8", cbor.AppendInt(nil, -1))
+	tests[4].b, tests[4].off = nestedBase("f64", cbor.AppendString(nil, "1.5"))
+	tests[5].b, tests[5].off = nestedBase("ints", cbor.AppendMapHeader(nil, 0))
+	tests[6].b, tests[6].off = nestedBase("data", cbor.AppendString(nil, "x"))
+
+	for _, tc := range tests {
+		var n Nested
+		_, err := n.DecodeSafe(tc.b)
+		var de *cbor.DecodeError
+		if !errors.As(err, &de) {
+			t.Fatalf("%s: error = %v, want *cbor.DecodeError", tc.name, err)
+		}
+		if de.Path != tc.path || de.Offset != tc.off {
+			t.Fatalf("%s: error at %q offset %d, want %q offset %d", tc.name, de.Path, de.Offset, tc.path, tc.off)
+		}
+		var pe cbor.InvalidPrefixError
+		if !errors.As(err, &pe) || pe.Want != tc.want || pe.Got != tc.got {
+			t.Fatalf("%s: error = %v, want major type %d but got %d", tc.name, err, tc.want, tc.got)
+		}
+	}
+}
+
+func TestDecodeFieldTypeMismatchMessage(t *testing.T) {
+	b, _ := nestedBase("i", cbor.AppendString(nil, "7"))
+	var n Nested
+	_, err := n.DecodeSafe(b)
+	want := "cbor: expected major type 0 (unsigned integer) but got 3 (text string) (at base.i, offset 15)"
+	if err == nil || err.Error() != want {
+		t.Fatalf("error = %v, want %s", err, want)
+	}
+
+	// Booleans report the CBOR type found against the Go type wanted.
+	b, _ = nestedBase("b", cbor.AppendInt(nil, 1))
+	_, err = n.DecodeSafe(b)
+	var te cbor.TypeError
+	if !errors.As(err, &te) || te.Method != cbor.BoolType || te.Encoded != cbor.UintType {
+		t.Fatalf("error = %v, want TypeError for bool", err)
+	}
+	if !strings.Contains(err.Error(), "at base.b") {
+		t.Fatalf("error %q does not name base.b", err)
+	}
+}
@@ -0,0 +1,28 @@
+package structs
+
+// MultiLevel exercises containers of containers: arrays of byte strings,
+// two- and three-level slices, fixed-size arrays and string-keyed maps of
+// them. Cell is generated here while ConsumerState (maps.go) is only
+// known by its methods.
+type MultiLevel struct {
+	Blobs  [][]byte                     `cbor:"blobs"`
+	Grid   [][]int64                    `cbor:"grid"`
+	Words  [][]string                   `cbor:"words"`
+	Cube   [][][]uint16                 `cbor:"cube"`
+	Keys   [][4]byte                    `cbor:"keys"`
+	Pairs  [2][]uint32                  `cbor:"pairs"`
+	Cells  [][]Cell                     `cbor:"cells"`
+	Refs   [][]*ConsumerState           `cbor:"refs"`
+	Chunks map[string][][]byte          `cbor:"chunks"`
+	Files  map[string][]byte            `cbor:"files"`
+	Owners map[string][]*Cell           `cbor:"owners,omitempty"`
+	Table  Matrix                       `cbor:"table,omitempty"`
+	States map[string][][]ConsumerState `cbor:"states,omitempty"`
+}
+
+type Cell struct {
+	V int `cbor:"v"`
+}
+
+// Matrix is a named two-level slice.
+type Matrix [][]float64
@@ -0,0 +1,2038 @@
+// Code generated by cborgen DO NOT EDIT.
+
+package structs
+
+import (
+	"io"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+// Fail the build if a generated method drifts from the runtime interfaces.
+var (
+	_ cbor.Marshaler   = (*MultiLevel)(nil)
+	_ cbor.Unmarshaler = (*MultiLevel)(nil)
+	_ cbor.Marshaler   = (*Cell)(nil)
+	_ cbor.Unmarshaler = (*Cell)(nil)
+	_ cbor.Marshaler   = (*Matrix)(nil)
+	_ cbor.Unmarshaler = (*Matrix)(nil)
+)
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *MultiLevel) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *MultiLevel) MarshalCBORTo(w io.Writer) (int, error) {
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *MultiLevel) AppendCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	count := uint32(0)
+	count++
+	count++
+	count++
+	count++
+	count++
+	count++
+	count++
+	count++
+	count++
+	count++
+	if !(len(x.Owners) == 0) {
+		count++
+	}
+	if !(len(x.Table) == 0) {
+		count++
+	}
+	if !(len(x.States) == 0) {
+		count++
+	}
+	b = cbor.AppendMapHeader(b, count)
+	var err error
+	if x.Blobs == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = cbor.AppendString(b, "blobs")
+		b = cbor.AppendNil(b)
+	} else {
+		b = cbor.AppendString(b, "blobs")
+		b = cbor.AppendArrayHeader(b, uint32(len(x.Blobs)))
+		for i1 := range x.Blobs {
+			b = cbor.AppendBytes(b, x.Blobs[i1])
+		}
+	}
+	if x.Grid == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = cbor.AppendString(b, "grid")
+		b = cbor.AppendNil(b)
+	} else {
+		b = cbor.AppendString(b, "grid")
+		b = cbor.AppendArrayHeader(b, uint32(len(x.Grid)))
+		for i1 := range x.Grid {
+			b = cbor.AppendArrayHeader(b, uint32(len(x.Grid[i1])))
+			for i2 := range x.Grid[i1] {
+				b = cbor.AppendInt64(b, x.Grid[i1][i2])
+			}
+		}
+	}
+	if x.Words == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = cbor.AppendString(b, "words")
+		b = cbor.AppendNil(b)
+	} else {
+		b = cbor.AppendString(b, "words")
+		b = cbor.AppendArrayHeader(b, uint32(len(x.Words)))
+		for i1 := range x.Words {
+			b = cbor.AppendArrayHeader(b, uint32(len(x.Words[i1])))
+			for i2 := range x.Words[i1] {
+				b = cbor.AppendString(b, x.Words[i1][i2])
+			}
+		}
+	}
+	if x.Cube == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = cbor.AppendString(b, "cube")
+		b = cbor.AppendNil(b)
+	} else {
+		b = cbor.AppendString(b, "cube")
+		b = cbor.AppendArrayHeader(b, uint32(len(x.Cube)))
+		for i1 := range x.Cube {
+			b = cbor.AppendArrayHeader(b, uint32(len(x.Cube[i1])))
+			for i2 := range x.Cube[i1] {
+				b = cbor.AppendArrayHeader(b, uint32(len(x.Cube[i1][i2])))
+				for i3 := range x.Cube[i1][i2] {
+					b = cbor.AppendUint16(b, x.Cube[i1][i2][i3])
+				}
+			}
+		}
+	}
+	if x.Keys == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = cbor.AppendString(b, "keys")
+		b = cbor.AppendNil(b)
+	} else {
+		b = cbor.AppendString(b, "keys")
+		b = cbor.AppendArrayHeader(b, uint32(len(x.Keys)))
+		for i1 := range x.Keys {
+			b = cbor.AppendBytes(b, x.Keys[i1][:])
+		}
+	}
+	b = cbor.AppendString(b, "pairs")
+	b = cbor.AppendArrayHeader(b, uint32(len(x.Pairs)))
+	for i1 := range x.Pairs {
+		b = cbor.AppendArrayHeader(b, uint32(len(x.Pairs[i1])))
+		for i2 := range x.Pairs[i1] {
+			b = cbor.AppendUint32(b, x.Pairs[i1][i2])
+		}
+	}
+	if x.Cells == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = cbor.AppendString(b, "cells")
+		b = cbor.AppendNil(b)
+	} else {
+		b = cbor.AppendString(b, "cells")
+		b = cbor.AppendArrayHeader(b, uint32(len(x.Cells)))
+		for i1 := range x.Cells {
+			b = cbor.AppendArrayHeader(b, uint32(len(x.Cells[i1])))
+			for i2 := range x.Cells[i1] {
+				if b, err = x.Cells[i1][i2].AppendCBOR(b); err != nil {
+					return b, err
+				}
+			}
+		}
+	}
+	if x.Refs == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = cbor.AppendString(b, "refs")
+		b = cbor.AppendNil(b)
+	} else {
+		b = cbor.AppendString(b, "refs")
+		b = cbor.AppendArrayHeader(b, uint32(len(x.Refs)))
+		for i1 := range x.Refs {
+			b = cbor.AppendArrayHeader(b, uint32(len(x.Refs[i1])))
+			for i2 := range x.Refs[i1] {
+				if x.Refs[i1][i2] == nil {
+					b = cbor.AppendNil(b)
+				} else if b, err = x.Refs[i1][i2].MarshalCBOR(b); err != nil {
+					return b, err
+				}
+			}
+		}
+	}
+	if x.Chunks == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = cbor.AppendString(b, "chunks")
+		b = cbor.AppendNil(b)
+	} else {
+		b = cbor.AppendString(b, "chunks")
+		if cbor.CanonicalMapEncode {
+			b, err = cbor.AppendMapDeterministic(b, x.Chunks, cbor.EncKeyString, func(b []byte, v [][]byte) ([]byte, error) {
+				b = cbor.AppendArrayHeader(b, uint32(len(v)))
+				for i1 := range v {
+					b = cbor.AppendBytes(b, v[i1])
+				}
+				return b, nil
+			})
+			if err != nil {
+				return b, err
+			}
+		} else {
+			b = cbor.AppendMapHeader(b, uint32(len(x.Chunks)))
+			for k, v := range x.Chunks {
+				b = cbor.AppendString(b, k)
+				b = cbor.AppendArrayHeader(b, uint32(len(v)))
+				for i1 := range v {
+					b = cbor.AppendBytes(b, v[i1])
+				}
+			}
+		}
+	}
+	if x.Files == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = cbor.AppendString(b, "files")
+		b = cbor.AppendNil(b)
+	} else {
+		b = cbor.AppendString(b, "files")
+		if cbor.CanonicalMapEncode {
+			b, err = cbor.AppendMapDeterministic(b, x.Files, cbor.EncKeyString, func(b []byte, v []byte) ([]byte, error) {
+				b = cbor.AppendBytes(b, v)
+				return b, nil
+			})
+			if err != nil {
+				return b, err
+			}
+		} else {
+			b = cbor.AppendMapHeader(b, uint32(len(x.Files)))
+			for k, v := range x.Files {
+				b = cbor.AppendString(b, k)
+				b = cbor.AppendBytes(b, v)
+			}
+		}
+	}
+	if !(len(x.Owners) == 0) {
+		if x.Owners == nil && cbor.NilContainers == cbor.NilAsNull {
+			b = cbor.AppendString(b, "owners")
+			b = cbor.AppendNil(b)
+		} else {
+			b = cbor.AppendString(b, "owners")
+			if cbor.CanonicalMapEncode {
+				b, err = cbor.AppendMapDeterministic(b, x.Owners, cbor.EncKeyString, func(b []byte, v []*Cell) ([]byte, error) {
+					b = cbor.AppendArrayHeader(b, uint32(len(v)))
+					for i1 := range v {
+						if v[i1] == nil {
+							b = cbor.AppendNil(b)
+						} else if b, err = v[i1].AppendCBOR(b); err != nil {
+							return b, err
+						}
+					}
+					return b, nil
+				})
+				if err != nil {
+					return b, err
+				}
+			} else {
+				b = cbor.AppendMapHeader(b, uint32(len(x.Owners)))
+				for k, v := range x.Owners {
+					b = cbor.AppendString(b, k)
+					b = cbor.AppendArrayHeader(b, uint32(len(v)))
+					for i1 := range v {
+						if v[i1] == nil {
+							b = cbor.AppendNil(b)
+						} else if b, err = v[i1].AppendCBOR(b); err != nil {
+							return b, err
+						}
+					}
+				}
+			}
+		}
+	}
+	if !(len(x.Table) == 0) {
+		b = cbor.AppendString(b, "table")
+		b, err = x.Table.AppendCBOR(b)
+		if err != nil {
+			return b, err
+		}
+	}
+	if !(len(x.States) == 0) {
+		if x.States == nil && cbor.NilContainers == cbor.NilAsNull {
+			b = cbor.AppendString(b, "states")
+			b = cbor.AppendNil(b)
+		} else {
+			b = cbor.AppendString(b, "states")
+			if cbor.CanonicalMapEncode {
+				b, err = cbor.AppendMapDeterministic(b, x.States, cbor.EncKeyString, func(b []byte, v [][]ConsumerState) ([]byte, error) {
+					b = cbor.AppendArrayHeader(b, uint32(len(v)))
+					for i1 := range v {
+						b = cbor.AppendArrayHeader(b, uint32(len(v[i1])))
+						for i2 := range v[i1] {
+							if b, err = v[i1][i2].MarshalCBOR(b); err != nil {
+								return b, err
+							}
+						}
+					}
+					return b, nil
+				})
+				if err != nil {
+					return b, err
+				}
+			} else {
+				b = cbor.AppendMapHeader(b, uint32(len(x.States)))
+				for k, v := range x.States {
+					b = cbor.AppendString(b, k)
+					b = cbor.AppendArrayHeader(b, uint32(len(v)))
+					for i1 := range v {
+						b = cbor.AppendArrayHeader(b, uint32(len(v[i1])))
+						for i2 := range v[i1] {
+							if b, err = v[i1][i2].MarshalCBOR(b); err != nil {
+								return b, err
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *MultiLevel) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *MultiLevel) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := in.ReadStringBytes(rest)
+		if err != nil {
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+		}
+		switch key {
+		case "blobs":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "blobs", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "blobs", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Blobs = cbor.NullSlice(x.Blobs)
+				break
+			}
+			var n1 uint32
+			var indef1 bool
+			n1, indef1, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "blobs", len(b)-len(v))
+			}
+			if cap(x.Blobs) >= int(n1) {
+				x.Blobs = x.Blobs[:n1]
+			} else {
+				x.Blobs = make([][]byte, n1)
+			}
+			for i1 := range x.Blobs {
+				x.Blobs[i1], v, err = cbor.ReadBytesBytes(v, nil)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, i1), "blobs", len(b)-len(v))
+				}
+			}
+			if indef1 {
+				v = v[1:] // break
+			}
+
+		case "grid":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "grid", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "grid", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Grid = cbor.NullSlice(x.Grid)
+				break
+			}
+			var n1 uint32
+			var indef1 bool
+			n1, indef1, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "grid", len(b)-len(v))
+			}
+			if cap(x.Grid) >= int(n1) {
+				x.Grid = x.Grid[:n1]
+			} else {
+				x.Grid = make([][]int64, n1)
+			}
+			for i1 := range x.Grid {
+				var n2 uint32
+				var indef2 bool
+				n2, indef2, v, err = cbor.ReadArraySizeBytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, i1), "grid", len(b)-len(v))
+				}
+				if cap(x.Grid[i1]) >= int(n2) {
+					x.Grid[i1] = x.Grid[i1][:n2]
+				} else {
+					x.Grid[i1] = make([]int64, n2)
+				}
+				for i2 := range x.Grid[i1] {
+					x.Grid[i1][i2], v, err = cbor.ReadInt64Bytes(v)
+					if err != nil {
+						return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(cbor.WrapDecodeIndex(err, i2), i1), "grid", len(b)-len(v))
+					}
+				}
+				if indef2 {
+					v = v[1:] // break
+				}
+			}
+			if indef1 {
+				v = v[1:] // break
+			}
+
+		case "words":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 2); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "words", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "words", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Words = cbor.NullSlice(x.Words)
+				break
+			}
+			var n1 uint32
+			var indef1 bool
+			n1, indef1, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "words", len(b)-len(v))
+			}
+			if cap(x.Words) >= int(n1) {
+				x.Words = x.Words[:n1]
+			} else {
+				x.Words = make([][]string, n1)
+			}
+			for i1 := range x.Words {
+				var n2 uint32
+				var indef2 bool
+				n2, indef2, v, err = cbor.ReadArraySizeBytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, i1), "words", len(b)-len(v))
+				}
+				if cap(x.Words[i1]) >= int(n2) {
+					x.Words[i1] = x.Words[i1][:n2]
+				} else {
+					x.Words[i1] = make([]string, n2)
+				}
+				for i2 := range x.Words[i1] {
+					x.Words[i1][i2], v, err = in.ReadStringBytes(v)
+					if err != nil {
+						return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(cbor.WrapDecodeIndex(err, i2), i1), "words", len(b)-len(v))
+					}
+				}
+				if indef2 {
+					v = v[1:] // break
+				}
+			}
+			if indef1 {
+				v = v[1:] // break
+			}
+
+		case "cube":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 3); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "cube", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "cube", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Cube = cbor.NullSlice(x.Cube)
+				break
+			}
+			var n1 uint32
+			var indef1 bool
+			n1, indef1, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "cube", len(b)-len(v))
+			}
+			if cap(x.Cube) >= int(n1) {
+				x.Cube = x.Cube[:n1]
+			} else {
+				x.Cube = make([][][]uint16, n1)
+			}
+			for i1 := range x.Cube {
+				var n2 uint32
+				var indef2 bool
+				n2, indef2, v, err = cbor.ReadArraySizeBytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, i1), "cube", len(b)-len(v))
+				}
+				if cap(x.Cube[i1]) >= int(n2) {
+					x.Cube[i1] = x.Cube[i1][:n2]
+				} else {
+					x.Cube[i1] = make([][]uint16, n2)
+				}
+				for i2 := range x.Cube[i1] {
+					var n3 uint32
+					var indef3 bool
+					n3, indef3, v, err = cbor.ReadArraySizeBytes(v)
+					if err != nil {
+						return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(cbor.WrapDecodeIndex(err, i2), i1), "cube", len(b)-len(v))
+					}
+					if cap(x.Cube[i1][i2]) >= int(n3) {
+						x.Cube[i1][i2] = x.Cube[i1][i2][:n3]
+					} else {
+						x.Cube[i1][i2] = make([]uint16, n3)
+					}
+					for i3 := range x.Cube[i1][i2] {
+						x.Cube[i1][i2][i3], v, err = cbor.ReadUint16Bytes(v)
+						if err != nil {
+							return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(cbor.WrapDecodeIndex(cbor.WrapDecodeIndex(err, i3), i2), i1), "cube", len(b)-len(v))
+						}
+					}
+					if indef3 {
+						v = v[1:] // break
+					}
+				}
+				if indef2 {
+					v = v[1:] // break
+				}
+			}
+			if indef1 {
+				v = v[1:] // break
+			}
+
+		case "keys":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 4); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "keys", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "keys", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Keys = cbor.NullSlice(x.Keys)
+				break
+			}
+			var n1 uint32
+			var indef1 bool
+			n1, indef1, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "keys", len(b)-len(v))
+			}
+			if cap(x.Keys) >= int(n1) {
+				x.Keys = x.Keys[:n1]
+			} else {
+				x.Keys = make([][4]byte, n1)
+			}
+			for i1 := range x.Keys {
+				var tmp2 []byte
+				tmp2, v, err = cbor.ReadBytesBytes(v, nil)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, i1), "keys", len(b)-len(v))
+				}
+				if len(tmp2) != len(x.Keys[i1]) {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(cbor.ArrayError{Wanted: uint32(len(x.Keys[i1])), Got: uint32(len(tmp2))}, i1), "keys", len(b)-len(v))
+				}
+				copy(x.Keys[i1][:], tmp2)
+			}
+			if indef1 {
+				v = v[1:] // break
+			}
+
+		case "pairs":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 5); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "pairs", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "pairs", len(b)-len(v))
+				}
+			}
+			var n1 uint32
+			n1, v, err = cbor.ReadArrayHeaderBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "pairs", len(b)-len(v))
+			}
+			if n1 != uint32(len(x.Pairs)) {
+				return b, cbor.WrapDecodeError(cbor.ArrayError{Wanted: uint32(len(x.Pairs)), Got: n1}, "pairs", len(b)-len(v))
+			}
+			for i1 := range x.Pairs {
+				var n2 uint32
+				var indef2 bool
+				n2, indef2, v, err = cbor.ReadArraySizeBytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, i1), "pairs", len(b)-len(v))
+				}
+				if cap(x.Pairs[i1]) >= int(n2) {
+					x.Pairs[i1] = x.Pairs[i1][:n2]
+				} else {
+					x.Pairs[i1] = make([]uint32, n2)
+				}
+				for i2 := range x.Pairs[i1] {
+					x.Pairs[i1][i2], v, err = cbor.ReadUint32Bytes(v)
+					if err != nil {
+						return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(cbor.WrapDecodeIndex(err, i2), i1), "pairs", len(b)-len(v))
+					}
+				}
+				if indef2 {
+					v = v[1:] // break
+				}
+			}
+
+		case "cells":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 6); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "cells", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "cells", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Cells = cbor.NullSlice(x.Cells)
+				break
+			}
+			var n1 uint32
+			var indef1 bool
+			n1, indef1, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "cells", len(b)-len(v))
+			}
+			if cap(x.Cells) >= int(n1) {
+				x.Cells = x.Cells[:n1]
+			} else {
+				x.Cells = make([][]Cell, n1)
+			}
+			for i1 := range x.Cells {
+				var n2 uint32
+				var indef2 bool
+				n2, indef2, v, err = cbor.ReadArraySizeBytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, i1), "cells", len(b)-len(v))
+				}
+				if cap(x.Cells[i1]) >= int(n2) {
+					x.Cells[i1] = x.Cells[i1][:n2]
+				} else {
+					x.Cells[i1] = make([]Cell, n2)
+				}
+				for i2 := range x.Cells[i1] {
+					x.Cells[i1][i2].resetCBOR()
+					v, err = x.Cells[i1][i2].DecodeInterned(v, in)
+					if err != nil {
+						return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(cbor.WrapDecodeIndex(err, i2), i1), "cells", len(b)-len(v))
+					}
+				}
+				if indef2 {
+					v = v[1:] // break
+				}
+			}
+			if indef1 {
+				v = v[1:] // break
+			}
+
+		case "refs":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 7); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "refs", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "refs", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Refs = cbor.NullSlice(x.Refs)
+				break
+			}
+			var n1 uint32
+			var indef1 bool
+			n1, indef1, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "refs", len(b)-len(v))
+			}
+			if cap(x.Refs) >= int(n1) {
+				x.Refs = x.Refs[:n1]
+			} else {
+				x.Refs = make([][]*ConsumerState, n1)
+			}
+			for i1 := range x.Refs {
+				var n2 uint32
+				var indef2 bool
+				n2, indef2, v, err = cbor.ReadArraySizeBytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, i1), "refs", len(b)-len(v))
+				}
+				if cap(x.Refs[i1]) >= int(n2) {
+					x.Refs[i1] = x.Refs[i1][:n2]
+				} else {
+					x.Refs[i1] = make([]*ConsumerState, n2)
+				}
+				for i2 := range x.Refs[i1] {
+					if cbor.IsNilOrUndefined(v) {
+						v = v[1:]
+						x.Refs[i1][i2] = nil
+					} else {
+						if x.Refs[i1][i2] == nil {
+							x.Refs[i1][i2] = new(ConsumerState)
+						}
+						v, err = x.Refs[i1][i2].UnmarshalCBOR(v)
+						if err != nil {
+							return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(cbor.WrapDecodeIndex(err, i2), i1), "refs", len(b)-len(v))
+						}
+					}
+				}
+				if indef2 {
+					v = v[1:] // break
+				}
+			}
+			if indef1 {
+				v = v[1:] // break
+			}
+
+		case "chunks":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 8); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "chunks", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "chunks", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Chunks = cbor.NullMap(x.Chunks)
+				break
+			}
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "chunks", len(b)-len(v))
+			}
+			if x.Chunks == nil && sz > 0 {
+				x.Chunks = make(map[string][][]byte, sz)
+			} else if x.Chunks != nil {
+				clear(x.Chunks)
+			}
+			for iChunks := uint32(0); iChunks < sz; iChunks++ {
+				var key string
+				key, v, err = in.ReadStringBytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "chunks", len(b)-len(v))
+				}
+				if _, dup := x.Chunks[key]; dup {
+					var skip bool
+					if v, skip, err = cbor.SkipDuplicate(v); err != nil {
+						return b, cbor.WrapDecodeError(cbor.WrapDecodeKey(err, key), "chunks", len(b)-len(v))
+					}
+					if skip {
+						continue
+					}
+				}
+				var tmp [][]byte
+				var n1 uint32
+				var indef1 bool
+				n1, indef1, v, err = cbor.ReadArraySizeBytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeKey(err, key), "chunks", len(b)-len(v))
+				}
+				if cap(tmp) >= int(n1) {
+					tmp = tmp[:n1]
+				} else {
+					tmp = make([][]byte, n1)
+				}
+				for i1 := range tmp {
+					tmp[i1], v, err = cbor.ReadBytesBytes(v, nil)
+					if err != nil {
+						return b, cbor.WrapDecodeError(cbor.WrapDecodeKey(cbor.WrapDecodeIndex(err, i1), key), "chunks", len(b)-len(v))
+					}
+				}
+				if indef1 {
+					v = v[1:] // break
+				}
+				x.Chunks[key] = tmp
+			}
+		case "files":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 9); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "files", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "files", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Files = cbor.NullMap(x.Files)
+				break
+			}
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "files", len(b)-len(v))
+			}
+			if x.Files == nil && sz > 0 {
+				x.Files = make(map[string][]byte, sz)
+			} else if x.Files != nil {
+				clear(x.Files)
+			}
+			for iFiles := uint32(0); iFiles < sz; iFiles++ {
+				var key string
+				key, v, err = in.ReadStringBytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "files", len(b)-len(v))
+				}
+				if _, dup := x.Files[key]; dup {
+					var skip bool
+					if v, skip, err = cbor.SkipDuplicate(v); err != nil {
+						return b, cbor.WrapDecodeError(cbor.WrapDecodeKey(err, key), "files", len(b)-len(v))
+					}
+					if skip {
+						continue
+					}
+				}
+				var tmp []byte
+				tmp, v, err = cbor.ReadBytesBytes(v, nil)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeKey(err, key), "files", len(b)-len(v))
+				}
+				x.Files[key] = tmp
+			}
+		case "owners":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 10); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "owners", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "owners", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Owners = cbor.NullMap(x.Owners)
+				break
+			}
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "owners", len(b)-len(v))
+			}
+			if x.Owners == nil && sz > 0 {
+				x.Owners = make(map[string][]*Cell, sz)
+			} else if x.Owners != nil {
+				clear(x.Owners)
+			}
+			for iOwners := uint32(0); iOwners < sz; iOwners++ {
+				var key string
+				key, v, err = in.ReadStringBytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "owners", len(b)-len(v))
+				}
+				if _, dup := x.Owners[key]; dup {
+					var skip bool
+					if v, skip, err = cbor.SkipDuplicate(v); err != nil {
+						return b, cbor.WrapDecodeError(cbor.WrapDecodeKey(err, key), "owners", len(b)-len(v))
+					}
+					if skip {
+						continue
+					}
+				}
+				var tmp []*Cell
+				var n1 uint32
+				var indef1 bool
+				n1, indef1, v, err = cbor.ReadArraySizeBytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeKey(err, key), "owners", len(b)-len(v))
+				}
+				if cap(tmp) >= int(n1) {
+					tmp = tmp[:n1]
+				} else {
+					tmp = make([]*Cell, n1)
+				}
+				for i1 := range tmp {
+					if cbor.IsNilOrUndefined(v) {
+						v = v[1:]
+						tmp[i1] = nil
+					} else {
+						if tmp[i1] == nil {
+							tmp[i1] = new(Cell)
+						} else {
+							tmp[i1].resetCBOR()
+						}
+						v, err = tmp[i1].DecodeInterned(v, in)
+						if err != nil {
+							return b, cbor.WrapDecodeError(cbor.WrapDecodeKey(cbor.WrapDecodeIndex(err, i1), key), "owners", len(b)-len(v))
+						}
+					}
+				}
+				if indef1 {
+					v = v[1:] // break
+				}
+				x.Owners[key] = tmp
+			}
+		case "table":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 11); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "table", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+
+			v, err = x.Table.DecodeInterned(v, in)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "table", len(b)-len(v))
+			}
+		case "states":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 12); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "states", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "states", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.States = cbor.NullMap(x.States)
+				break
+			}
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "states", len(b)-len(v))
+			}
+			if x.States == nil && sz > 0 {
+				x.States = make(map[string][][]ConsumerState, sz)
+			} else if x.States != nil {
+				clear(x.States)
+			}
+			for iStates := uint32(0); iStates < sz; iStates++ {
+				var key string
+				key, v, err = in.ReadStringBytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "states", len(b)-len(v))
+				}
+				if _, dup := x.States[key]; dup {
+					var skip bool
+					if v, skip, err = cbor.SkipDuplicate(v); err != nil {
+						return b, cbor.WrapDecodeError(cbor.WrapDecodeKey(err, key), "states", len(b)-len(v))
+					}
+					if skip {
+						continue
+					}
+				}
+				var tmp [][]ConsumerState
+				var n1 uint32
+				var indef1 bool
+				n1, indef1, v, err = cbor.ReadArraySizeBytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeKey(err, key), "states", len(b)-len(v))
+				}
+				if cap(tmp) >= int(n1) {
+					tmp = tmp[:n1]
+				} else {
+					tmp = make([][]ConsumerState, n1)
+				}
+				for i1 := range tmp {
+					var n2 uint32
+					var indef2 bool
+					n2, indef2, v, err = cbor.ReadArraySizeBytes(v)
+					if err != nil {
+						return b, cbor.WrapDecodeError(cbor.WrapDecodeKey(cbor.WrapDecodeIndex(err, i1), key), "states", len(b)-len(v))
+					}
+					if cap(tmp[i1]) >= int(n2) {
+						tmp[i1] = tmp[i1][:n2]
+					} else {
+						tmp[i1] = make([]ConsumerState, n2)
+					}
+					for i2 := range tmp[i1] {
+						var tmp3 ConsumerState
+						v, err = (&tmp3).UnmarshalCBOR(v)
+						if err != nil {
+							return b, cbor.WrapDecodeError(cbor.WrapDecodeKey(cbor.WrapDecodeIndex(cbor.WrapDecodeIndex(err, i2), i1), key), "states", len(b)-len(v))
+						}
+						tmp[i1][i2] = tmp3
+					}
+					if indef2 {
+						v = v[1:] // break
+					}
+				}
+				if indef1 {
+					v = v[1:] // break
+				}
+				x.States[key] = tmp
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *MultiLevel) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "blobs":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Blobs = cbor.NullSlice(x.Blobs)
+				break
+			}
+			var n1 uint32
+			var indef1 bool
+			n1, indef1, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap(x.Blobs) >= int(n1) {
+				x.Blobs = x.Blobs[:n1]
+			} else {
+				x.Blobs = make([][]byte, n1)
+			}
+			for i1 := range x.Blobs {
+				x.Blobs[i1], v, err = cbor.ReadBytesBytes(v, nil)
+				if err != nil {
+					return b, err
+				}
+			}
+			if indef1 {
+				v = v[1:] // break
+			}
+
+		case "grid":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Grid = cbor.NullSlice(x.Grid)
+				break
+			}
+			var n1 uint32
+			var indef1 bool
+			n1, indef1, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap(x.Grid) >= int(n1) {
+				x.Grid = x.Grid[:n1]
+			} else {
+				x.Grid = make([][]int64, n1)
+			}
+			for i1 := range x.Grid {
+				var n2 uint32
+				var indef2 bool
+				n2, indef2, v, err = cbor.ReadArraySizeBytes(v)
+				if err != nil {
+					return b, err
+				}
+				if cap(x.Grid[i1]) >= int(n2) {
+					x.Grid[i1] = x.Grid[i1][:n2]
+				} else {
+					x.Grid[i1] = make([]int64, n2)
+				}
+				for i2 := range x.Grid[i1] {
+					x.Grid[i1][i2], v, err = cbor.ReadInt64Bytes(v)
+					if err != nil {
+						return b, err
+					}
+				}
+				if indef2 {
+					v = v[1:] // break
+				}
+			}
+			if indef1 {
+				v = v[1:] // break
+			}
+
+		case "words":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Words = cbor.NullSlice(x.Words)
+				break
+			}
+			var n1 uint32
+			var indef1 bool
+			n1, indef1, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap(x.Words) >= int(n1) {
+				x.Words = x.Words[:n1]
+			} else {
+				x.Words = make([][]string, n1)
+			}
+			for i1 := range x.Words {
+				var n2 uint32
+				var indef2 bool
+				n2, indef2, v, err = cbor.ReadArraySizeBytes(v)
+				if err != nil {
+					return b, err
+				}
+				if cap(x.Words[i1]) >= int(n2) {
+					x.Words[i1] = x.Words[i1][:n2]
+				} else {
+					x.Words[i1] = make([]string, n2)
+				}
+				for i2 := range x.Words[i1] {
+					x.Words[i1][i2], v, err = cbor.ReadTrustedStringBytes(v)
+					if err != nil {
+						return b, err
+					}
+				}
+				if indef2 {
+					v = v[1:] // break
+				}
+			}
+			if indef1 {
+				v = v[1:] // break
+			}
+
+		case "cube":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Cube = cbor.NullSlice(x.Cube)
+				break
+			}
+			var n1 uint32
+			var indef1 bool
+			n1, indef1, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap(x.Cube) >= int(n1) {
+				x.Cube = x.Cube[:n1]
+			} else {
+				x.Cube = make([][][]uint16, n1)
+			}
+			for i1 := range x.Cube {
+				var n2 uint32
+				var indef2 bool
+				n2, indef2, v, err = cbor.ReadArraySizeBytes(v)
+				if err != nil {
+					return b, err
+				}
+				if cap(x.Cube[i1]) >= int(n2) {
+					x.Cube[i1] = x.Cube[i1][:n2]
+				} else {
+					x.Cube[i1] = make([][]uint16, n2)
+				}
+				for i2 := range x.Cube[i1] {
+					var n3 uint32
+					var indef3 bool
+					n3, indef3, v, err = cbor.ReadArraySizeBytes(v)
+					if err != nil {
+						return b, err
+					}
+					if cap(x.Cube[i1][i2]) >= int(n3) {
+						x.Cube[i1][i2] = x.Cube[i1][i2][:n3]
+					} else {
+						x.Cube[i1][i2] = make([]uint16, n3)
+					}
+					for i3 := range x.Cube[i1][i2] {
+						x.Cube[i1][i2][i3], v, err = cbor.ReadUint16Bytes(v)
+						if err != nil {
+							return b, err
+						}
+					}
+					if indef3 {
+						v = v[1:] // break
+					}
+				}
+				if indef2 {
+					v = v[1:] // break
+				}
+			}
+			if indef1 {
+				v = v[1:] // break
+			}
+
+		case "keys":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Keys = cbor.NullSlice(x.Keys)
+				break
+			}
+			var n1 uint32
+			var indef1 bool
+			n1, indef1, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap(x.Keys) >= int(n1) {
+				x.Keys = x.Keys[:n1]
+			} else {
+				x.Keys = make([][4]byte, n1)
+			}
+			for i1 := range x.Keys {
+				var tmp2 []byte
+				tmp2, v, err = cbor.ReadBytesBytes(v, nil)
+				if err != nil {
+					return b, err
+				}
+				if len(tmp2) != len(x.Keys[i1]) {
+					return b, cbor.ArrayError{Wanted: uint32(len(x.Keys[i1])), Got: uint32(len(tmp2))}
+				}
+				copy(x.Keys[i1][:], tmp2)
+			}
+			if indef1 {
+				v = v[1:] // break
+			}
+
+		case "pairs":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			var n1 uint32
+			n1, v, err = cbor.ReadArrayHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if n1 != uint32(len(x.Pairs)) {
+				return b, cbor.ArrayError{Wanted: uint32(len(x.Pairs)), Got: n1}
+			}
+			for i1 := range x.Pairs {
+				var n2 uint32
+				var indef2 bool
+				n2, indef2, v, err = cbor.ReadArraySizeBytes(v)
+				if err != nil {
+					return b, err
+				}
+				if cap(x.Pairs[i1]) >= int(n2) {
+					x.Pairs[i1] = x.Pairs[i1][:n2]
+				} else {
+					x.Pairs[i1] = make([]uint32, n2)
+				}
+				for i2 := range x.Pairs[i1] {
+					x.Pairs[i1][i2], v, err = cbor.ReadUint32Bytes(v)
+					if err != nil {
+						return b, err
+					}
+				}
+				if indef2 {
+					v = v[1:] // break
+				}
+			}
+
+		case "cells":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Cells = cbor.NullSlice(x.Cells)
+				break
+			}
+			var n1 uint32
+			var indef1 bool
+			n1, indef1, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap(x.Cells) >= int(n1) {
+				x.Cells = x.Cells[:n1]
+			} else {
+				x.Cells = make([][]Cell, n1)
+			}
+			for i1 := range x.Cells {
+				var n2 uint32
+				var indef2 bool
+				n2, indef2, v, err = cbor.ReadArraySizeBytes(v)
+				if err != nil {
+					return b, err
+				}
+				if cap(x.Cells[i1]) >= int(n2) {
+					x.Cells[i1] = x.Cells[i1][:n2]
+				} else {
+					x.Cells[i1] = make([]Cell, n2)
+				}
+				for i2 := range x.Cells[i1] {
+					x.Cells[i1][i2].resetCBOR()
+					v, err = x.Cells[i1][i2].DecodeTrusted(v)
+					if err != nil {
+						return b, err
+					}
+				}
+				if indef2 {
+					v = v[1:] // break
+				}
+			}
+			if indef1 {
+				v = v[1:] // break
+			}
+
+		case "refs":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Refs = cbor.NullSlice(x.Refs)
+				break
+			}
+			var n1 uint32
+			var indef1 bool
+			n1, indef1, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap(x.Refs) >= int(n1) {
+				x.Refs = x.Refs[:n1]
+			} else {
+				x.Refs = make([][]*ConsumerState, n1)
+			}
+			for i1 := range x.Refs {
+				var n2 uint32
+				var indef2 bool
+				n2, indef2, v, err = cbor.ReadArraySizeBytes(v)
+				if err != nil {
+					return b, err
+				}
+				if cap(x.Refs[i1]) >= int(n2) {
+					x.Refs[i1] = x.Refs[i1][:n2]
+				} else {
+					x.Refs[i1] = make([]*ConsumerState, n2)
+				}
+				for i2 := range x.Refs[i1] {
+					if cbor.IsNilOrUndefined(v) {
+						v = v[1:]
+						x.Refs[i1][i2] = nil
+					} else {
+						if x.Refs[i1][i2] == nil {
+							x.Refs[i1][i2] = new(ConsumerState)
+						}
+						v, err = x.Refs[i1][i2].UnmarshalCBOR(v)
+						if err != nil {
+							return b, err
+						}
+					}
+				}
+				if indef2 {
+					v = v[1:] // break
+				}
+			}
+			if indef1 {
+				v = v[1:] // break
+			}
+
+		case "chunks":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Chunks = cbor.NullMap(x.Chunks)
+				break
+			}
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if x.Chunks == nil && sz > 0 {
+				x.Chunks = make(map[string][][]byte, sz)
+			} else if x.Chunks != nil {
+				clear(x.Chunks)
+			}
+			for iChunks := uint32(0); iChunks < sz; iChunks++ {
+				var key string
+				key, v, err = cbor.ReadTrustedStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				var tmp [][]byte
+				var n1 uint32
+				var indef1 bool
+				n1, indef1, v, err = cbor.ReadArraySizeBytes(v)
+				if err != nil {
+					return b, err
+				}
+				if cap(tmp) >= int(n1) {
+					tmp = tmp[:n1]
+				} else {
+					tmp = make([][]byte, n1)
+				}
+				for i1 := range tmp {
+					tmp[i1], v, err = cbor.ReadBytesBytes(v, nil)
+					if err != nil {
+						return b, err
+					}
+				}
+				if indef1 {
+					v = v[1:] // break
+				}
+				x.Chunks[key] = tmp
+			}
+		case "files":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Files = cbor.NullMap(x.Files)
+				break
+			}
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if x.Files == nil && sz > 0 {
+				x.Files = make(map[string][]byte, sz)
+			} else if x.Files != nil {
+				clear(x.Files)
+			}
+			for iFiles := uint32(0); iFiles < sz; iFiles++ {
+				var key string
+				key, v, err = cbor.ReadTrustedStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				var tmp []byte
+				tmp, v, err = cbor.ReadBytesBytes(v, nil)
+				if err != nil {
+					return b, err
+				}
+				x.Files[key] = tmp
+			}
+		case "owners":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Owners = cbor.NullMap(x.Owners)
+				break
+			}
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if x.Owners == nil && sz > 0 {
+				x.Owners = make(map[string][]*Cell, sz)
+			} else if x.Owners != nil {
+				clear(x.Owners)
+			}
+			for iOwners := uint32(0); iOwners < sz; iOwners++ {
+				var key string
+				key, v, err = cbor.ReadTrustedStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				var tmp []*Cell
+				var n1 uint32
+				var indef1 bool
+				n1, indef1, v, err = cbor.ReadArraySizeBytes(v)
+				if err != nil {
+					return b, err
+				}
+				if cap(tmp) >= int(n1) {
+					tmp = tmp[:n1]
+				} else {
+					tmp = make([]*Cell, n1)
+				}
+				for i1 := range tmp {
+					if cbor.IsNilOrUndefined(v) {
+						v = v[1:]
+						tmp[i1] = nil
+					} else {
+						if tmp[i1] == nil {
+							tmp[i1] = new(Cell)
+						} else {
+							tmp[i1].resetCBOR()
+						}
+						v, err = tmp[i1].DecodeTrusted(v)
+						if err != nil {
+							return b, err
+						}
+					}
+				}
+				if indef1 {
+					v = v[1:] // break
+				}
+				x.Owners[key] = tmp
+			}
+		case "table":
+
+			v, err = (&x.Table).DecodeTrusted(v)
+			if err != nil {
+				return b, err
+			}
+		case "states":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.States = cbor.NullMap(x.States)
+				break
+			}
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if x.States == nil && sz > 0 {
+				x.States = make(map[string][][]ConsumerState, sz)
+			} else if x.States != nil {
+				clear(x.States)
+			}
+			for iStates := uint32(0); iStates < sz; iStates++ {
+				var key string
+				key, v, err = cbor.ReadTrustedStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				var tmp [][]ConsumerState
+				var n1 uint32
+				var indef1 bool
+				n1, indef1, v, err = cbor.ReadArraySizeBytes(v)
+				if err != nil {
+					return b, err
+				}
+				if cap(tmp) >= int(n1) {
+					tmp = tmp[:n1]
+				} else {
+					tmp = make([][]ConsumerState, n1)
+				}
+				for i1 := range tmp {
+					var n2 uint32
+					var indef2 bool
+					n2, indef2, v, err = cbor.ReadArraySizeBytes(v)
+					if err != nil {
+						return b, err
+					}
+					if cap(tmp[i1]) >= int(n2) {
+						tmp[i1] = tmp[i1][:n2]
+					} else {
+						tmp[i1] = make([]ConsumerState, n2)
+					}
+					for i2 := range tmp[i1] {
+						var tmp3 ConsumerState
+						v, err = (&tmp3).UnmarshalCBOR(v)
+						if err != nil {
+							return b, err
+						}
+						tmp[i1][i2] = tmp3
+					}
+					if indef2 {
+						v = v[1:] // break
+					}
+				}
+				if indef1 {
+					v = v[1:] // break
+				}
+				x.States[key] = tmp
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *MultiLevel) resetCBOR() {
+	var zero MultiLevel
+	x.Blobs = x.Blobs[:0]
+	x.Grid = x.Grid[:0]
+	x.Words = x.Words[:0]
+	x.Cube = x.Cube[:0]
+	x.Keys = x.Keys[:0]
+	x.Pairs = zero.Pairs
+	x.Cells = x.Cells[:0]
+	x.Refs = x.Refs[:0]
+	clear(x.Chunks)
+	clear(x.Files)
+	clear(x.Owners)
+	x.Table = zero.Table
+	clear(x.States)
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *MultiLevel) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x Cell) Msgsize() (s int) {
+	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("v") + cbor.IntSize
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *Cell) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *Cell) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *Cell) AppendCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	b = cbor.AppendMapHeader(b, uint32(1))
+	var err error
+	b = cbor.AppendString(b, "v")
+	b, err = cbor.AppendInt(b, x.V), nil
+	if err != nil {
+		return b, err
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *Cell) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *Cell) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := in.ReadStringBytes(rest)
+		if err != nil {
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+		}
+		switch key {
+		case "v":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "v", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "v", len(b)-len(v))
+				}
+			}
+
+			var tmp int
+			tmp, v, err = cbor.ReadIntBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "v", len(b)-len(v))
+			}
+			x.V = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *Cell) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "v":
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp int
+			tmp, v, err = cbor.ReadIntBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.V = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *Cell) resetCBOR() {
+	var zero Cell
+	x.V = zero.V
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Cell) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *Matrix) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// AppendCBOR appends x to b as a bare CBOR array or map.
+func (x *Matrix) AppendCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+	if (*x) == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = cbor.AppendNil(b)
+	} else {
+		b = cbor.AppendArrayHeader(b, uint32(len(*x)))
+		for i1 := range *x {
+			b = cbor.AppendArrayHeader(b, uint32(len((*x)[i1])))
+			for i2 := range (*x)[i1] {
+				b = cbor.AppendFloat64(b, (*x)[i1][i2])
+			}
+		}
+	}
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *Matrix) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+func (x *Matrix) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	v := b
+	var err error
+	// The decode case breaks out of this switch once x is filled in.
+	switch {
+	default:
+		if cbor.IsTagged(v) {
+			if v, err = cbor.UntagBytes(v); err != nil {
+				return b, cbor.WrapDecodeError(err, "", len(b)-len(v))
+			}
+		}
+		if cbor.IsNilOrUndefined(v) {
+			v = v[1:]
+			(*x) = cbor.NullSlice(*x)
+			break
+		}
+		var n1 uint32
+		var indef1 bool
+		n1, indef1, v, err = cbor.ReadArraySizeBytes(v)
+		if err != nil {
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(v))
+		}
+		if cap(*x) >= int(n1) {
+			(*x) = (*x)[:n1]
+		} else {
+			(*x) = make([][]float64, n1)
+		}
+		for i1 := range *x {
+			var n2 uint32
+			var indef2 bool
+			n2, indef2, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, i1), "", len(b)-len(v))
+			}
+			if cap((*x)[i1]) >= int(n2) {
+				(*x)[i1] = (*x)[i1][:n2]
+			} else {
+				(*x)[i1] = make([]float64, n2)
+			}
+			for i2 := range (*x)[i1] {
+				(*x)[i1][i2], v, err = cbor.ReadFloat64Bytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(cbor.WrapDecodeIndex(err, i2), i1), "", len(b)-len(v))
+				}
+			}
+			if indef2 {
+				v = v[1:] // break
+			}
+		}
+		if indef1 {
+			v = v[1:] // break
+		}
+
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return v, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *Matrix) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	v := b
+	var err error
+	// The decode case breaks out of this switch once x is filled in.
+	switch {
+	default:
+		if cbor.IsTagged(v) {
+			if v, err = cbor.UntagBytes(v); err != nil {
+				return b, err
+			}
+		}
+		if cbor.IsNilOrUndefined(v) {
+			v = v[1:]
+			(*x) = cbor.NullSlice(*x)
+			break
+		}
+		var n1 uint32
+		var indef1 bool
+		n1, indef1, v, err = cbor.ReadArraySizeBytes(v)
+		if err != nil {
+			return b, err
+		}
+		if cap(*x) >= int(n1) {
+			(*x) = (*x)[:n1]
+		} else {
+			(*x) = make([][]float64, n1)
+		}
+		for i1 := range *x {
+			var n2 uint32
+			var indef2 bool
+			n2, indef2, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap((*x)[i1]) >= int(n2) {
+				(*x)[i1] = (*x)[i1][:n2]
+			} else {
+				(*x)[i1] = make([]float64, n2)
+			}
+			for i2 := range (*x)[i1] {
+				(*x)[i1][i2], v, err = cbor.ReadFloat64Bytes(v)
+				if err != nil {
+					return b, err
+				}
+			}
+			if indef2 {
+				v = v[1:] // break
+			}
+		}
+		if indef1 {
+			v = v[1:] // break
+		}
+
+	}
+	return v, nil
+}
+
+// resetCBOR empties x, keeping its storage for reuse.
+func (x *Matrix) resetCBOR() {
+	(*x) = (*x)[:0]
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Matrix) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
@@ -0,0 +1,154 @@
+package structs
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+var multiLevelDecoders = []struct {
+	name   string
+	decode func(dst *MultiLevel, b []byte) ([]byte, error)
+}{
+	{"DecodeSafe", (*MultiLevel).DecodeSafe},
+	{"DecodeTrusted", (*MultiLevel).DecodeTrusted},
+}
+
+func newMultiLevel() *MultiLevel {
+	return &MultiLevel{
+		Blobs:  [][]byte{{0x01, 0x02}, {0xff}},
+		Grid:   [][]int64{{1, -2}, {}, {1 << 40}},
+		Words:  [][]string{{"a", "b"}, {"c"}},
+		Cube:   [][][]uint16{{{1, 2}, {3}}, {{}}},
+		Keys:   [][4]byte{{1, 2, 3, 4}},
+		Pairs:  [2][]uint32{{1}, {2, 3}},
+		Cells:  [][]Cell{{{V: 1}, {V: 2}}, {}},
+		Refs:   [][]*ConsumerState{{{Name: "r", Delivered: 7}, nil}},
+		Chunks: map[string][][]byte{"c": {{0xaa}, {0xbb, 0xcc}}},
+		Files:  map[string][]byte{"f": []byte("data")},
+		Owners: map[string][]*Cell{"o": {{V: 9}, nil}},
+		Table:  Matrix{{1.5, 2}, {-3}},
+		States: map[string][][]ConsumerState{"s": {{{Name: "x"}}}},
+	}
+}
+
+func TestMultiLevelRoundTrip(t *testing.T) {
+	orig := newMultiLevel()
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	for _, tc := range multiLevelDecoders {
+		// Stale elements and map entries must be replaced.
+		dst := MultiLevel{
+			Grid:   [][]int64{{9, 9, 9}, {9}, {9}, {9}},
+			Chunks: map[string][][]byte{"stale": nil},
+		}
+		rest, err := tc.decode(&dst, b)
+		if err != nil || len(rest) != 0 {
+			t.Fatalf("%s: rest=%d, err=%v", tc.name, len(rest), err)
+		}
+		// Empty inner slices may come back nil, so compare encodings.
+		again, err := dst.MarshalCBOR(nil)
+		if err != nil || !bytes.Equal(again, b) {
+			t.Fatalf("%s: round trip mismatch:\n got %+v\nwant %+v", tc.name, dst, *orig)
+		}
+		if *dst.Refs[0][0] != *orig.Refs[0][0] || dst.Refs[0][1] != nil || dst.Owners["o"][1] != nil {
+			t.Fatalf("%s: pointers = %+v, %+v", tc.name, dst.Refs, dst.Owners)
+		}
+	}
+
+	// Canonical map order reaches the nested map fields too.
+	defer func(v bool) { cbor.CanonicalMapEncode = v }(cbor.CanonicalMapEncode)
+	cbor.CanonicalMapEncode = true
+	orig.Chunks["a"] = [][]byte{{0x01}}
+	cb, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("canonical MarshalCBOR error: %v", err)
+	}
+	var dst MultiLevel
+	if _, err := dst.DecodeSafe(cb); err != nil || !reflect.DeepEqual(dst.Chunks, orig.Chunks) {
+		t.Fatalf("canonical round trip: %+v, %v", dst.Chunks, err)
+	}
+	if again, _ := dst.MarshalCBOR(nil); !bytes.Equal(again, cb) {
+		t.Fatal("canonical encoding differs after a round trip")
+	}
+}
+
+// TestMultiLevelByteStrings checks that [][]byte is an array of byte
+// strings while []byte stays a single byte string.
+func TestMultiLevelByteStrings(t *testing.T) {
+	x := MultiLevel{
+		Blobs: [][]byte{{0x01, 0x02}, {}},
+		Files: map[string][]byte{"f": {0x03}},
+		Keys:  [][4]byte{{1, 2, 3, 4}},
+	}
+	b, err := x.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	for _, want := range [][]byte{
+		// "blobs": [h'0102', h'']
+		append(cbor.AppendString(nil, "blobs"), 0x82, 0x42, 0x01, 0x02, 0x40),
+		// "files": {"f": h'03'}
+		append(cbor.AppendString(nil, "files"), 0xa1, 0x61, 'f', 0x41, 0x03),
+		// "keys": [h'01020304']
+		append(cbor.AppendString(nil, "keys"), 0x81, 0x44, 0x01, 0x02, 0x03, 0x04),
+	} {
+		if !bytes.Contains(b, want) {
+			t.Errorf("encoding lacks % x", want)
+		}
+	}
+
+	// A byte string where the outer array is expected, and an array
+	// where a byte string is expected, are both rejected.
+	for _, bad := range [][]byte{
+		append(append(cbor.AppendMapHeader(nil, 1), cbor.AppendString(nil, "blobs")...), 0x42, 0x01, 0x02),
+		append(append(cbor.AppendMapHeader(nil, 1), cbor.AppendString(nil, "blobs")...), 0x81, 0x81, 0x01),
+	} {
+		for _, tc := range multiLevelDecoders {
+			var dst MultiLevel
+			if _, err := tc.decode(&dst, bad); err == nil {
+				t.Errorf("%s decoded % x: %+v", tc.name, bad, dst)
+			}
+		}
+	}
+}
+
+func TestMultiLevelErrors(t *testing.T) {
+	// {"grid": [[1], [2, "x"]]}
+	b := cbor.AppendMapHeader(nil, 1)
+	b = cbor.AppendString(b, "grid")
+	b = append(b, 0x82, 0x81, 0x01, 0x82, 0x02)
+	b = cbor.AppendString(b, "x")
+	var dst MultiLevel
+	_, err := dst.DecodeSafe(b)
+	var de *cbor.DecodeError
+	if !errors.As(err, &de) || de.Path != "grid[1][1]" {
+		t.Fatalf("error = %v, want path grid[1][1]", err)
+	}
+
+	// {"chunks": {"k": [h'', 5]}}
+	b = cbor.AppendMapHeader(nil, 1)
+	b = cbor.AppendString(b, "chunks")
+	b = append(b, 0xa1)
+	b = cbor.AppendString(b, "k")
+	b = append(b, 0x82, 0x40, 0x05)
+	if _, err := dst.DecodeSafe(b); !errors.As(err, &de) || de.Path != `chunks["k"][1]` {
+		t.Fatalf("error = %v, want path chunks[\"k\"][1]", err)
+	}
+
+	// Fixed-size arrays need exactly their length.
+	b = cbor.AppendMapHeader(nil, 1)
+	b = cbor.AppendString(b, "keys")
+	b = append(b, 0x81, 0x43, 0x01, 0x02, 0x03)
+	for _, tc := range multiLevelDecoders {
+		var ae cbor.ArrayError
+		if _, err := tc.decode(&dst, b); !errors.As(err, &ae) || ae.Wanted != 4 || ae.Got != 3 {
+			t.Fatalf("%s: error = %v, want ArrayError", tc.name, err)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package structs
+
+// SnakeConfig is generated with --namecase snake: untagged fields get
+// snake_case keys and explicit tag names win.
+type SnakeConfig struct {
+	ConfigJSON  string
+	UserID      int
+	RTTMillis   int64
+	HTTPServer  string
+	Base64Data  []byte
+	Listen_Addr string
+	Timeout     int    `cbor:",omitempty"`
+	Region      string `json:"zone"`
+	Owner       string `cbor:"OWNER"`
+}
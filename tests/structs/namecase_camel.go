@@ -0,0 +1,10 @@
+package structs
+
+// CamelConfig is generated with --namecase camel.
+type CamelConfig struct {
+	ConfigJSON string
+	UserID     int
+	RTTMillis  int64
+	HTTPServer string
+	Owner      string `cbor:"Owner"`
+}
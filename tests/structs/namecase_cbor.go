@@ -0,0 +1,503 @@
+// Code generated by cborgen DO NOT EDIT.
+
+package structs
+
+import (
+	"io"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+// Fail the build if a generated method drifts from the runtime interfaces.
+var (
+	_ cbor.Marshaler   = (*SnakeConfig)(nil)
+	_ cbor.Unmarshaler = (*SnakeConfig)(nil)
+)
+
+func (x SnakeConfig) Msgsize() (s int) {
+	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("config_json") + cbor.StringPrefixSize + len(x.ConfigJSON) + cbor.StringPrefixSize + len("user_id") + cbor.IntSize + cbor.StringPrefixSize + len("rtt_millis") + cbor.Int64Size + cbor.StringPrefixSize + len("http_server") + cbor.StringPrefixSize + len(x.HTTPServer) + cbor.StringPrefixSize + len("base64_data") + cbor.BytesPrefixSize + len(x.Base64Data) + cbor.StringPrefixSize + len("listen_addr") + cbor.StringPrefixSize + len(x.Listen_Addr) + cbor.StringPrefixSize + len("timeout") + cbor.IntSize + cbor.StringPrefixSize + len("zone") + cbor.StringPrefixSize + len(x.Region) + cbor.StringPrefixSize + len("OWNER") + cbor.StringPrefixSize + len(x.Owner)
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *SnakeConfig) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *SnakeConfig) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *SnakeConfig) AppendCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	count := uint32(0)
+	count++
+	count++
+	count++
+	count++
+	count++
+	count++
+	if !(x.Timeout == 0) {
+		count++
+	}
+	count++
+	count++
+	b = cbor.AppendMapHeader(b, count)
+	var err error
+	b = cbor.AppendString(b, "config_json")
+	b, err = cbor.AppendString(b, x.ConfigJSON), nil
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "user_id")
+	b, err = cbor.AppendInt(b, x.UserID), nil
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "rtt_millis")
+	b, err = cbor.AppendInt64(b, x.RTTMillis), nil
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "http_server")
+	b, err = cbor.AppendString(b, x.HTTPServer), nil
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "base64_data")
+	if x.Base64Data == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = cbor.AppendNil(b)
+	} else {
+		b, err = cbor.AppendBytes(b, x.Base64Data), nil
+		if err != nil {
+			return b, err
+		}
+	}
+	b = cbor.AppendString(b, "listen_addr")
+	b, err = cbor.AppendString(b, x.Listen_Addr), nil
+	if err != nil {
+		return b, err
+	}
+	if !(x.Timeout == 0) {
+		b = cbor.AppendString(b, "timeout")
+		b, err = cbor.AppendInt(b, x.Timeout), nil
+		if err != nil {
+			return b, err
+		}
+	}
+	b = cbor.AppendString(b, "zone")
+	b, err = cbor.AppendString(b, x.Region), nil
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "OWNER")
+	b, err = cbor.AppendString(b, x.Owner), nil
+	if err != nil {
+		return b, err
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *SnakeConfig) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *SnakeConfig) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := in.ReadStringBytes(rest)
+		if err != nil {
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+		}
+		switch key {
+		case "config_json":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "config_json", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "config_json", len(b)-len(v))
+				}
+			}
+
+			var tmp string
+			tmp, v, err = in.ReadStringBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "config_json", len(b)-len(v))
+			}
+			x.ConfigJSON = tmp
+		case "user_id":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "user_id", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "user_id", len(b)-len(v))
+				}
+			}
+
+			var tmp int
+			tmp, v, err = cbor.ReadIntBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "user_id", len(b)-len(v))
+			}
+			x.UserID = tmp
+		case "rtt_millis":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 2); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "rtt_millis", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "rtt_millis", len(b)-len(v))
+				}
+			}
+
+			var tmp int64
+			tmp, v, err = cbor.ReadInt64Bytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "rtt_millis", len(b)-len(v))
+			}
+			x.RTTMillis = tmp
+		case "http_server":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 3); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "http_server", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "http_server", len(b)-len(v))
+				}
+			}
+
+			var tmp string
+			tmp, v, err = in.ReadStringBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "http_server", len(b)-len(v))
+			}
+			x.HTTPServer = tmp
+		case "base64_data":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 4); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "base64_data", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "base64_data", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Base64Data = cbor.NullSlice(x.Base64Data)
+				break
+			}
+			var tmp []byte
+			tmp, v, err = cbor.ReadBytesBytes(v, nil)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "base64_data", len(b)-len(v))
+			}
+			x.Base64Data = tmp
+		case "listen_addr":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 5); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "listen_addr", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "listen_addr", len(b)-len(v))
+				}
+			}
+
+			var tmp string
+			tmp, v, err = in.ReadStringBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "listen_addr", len(b)-len(v))
+			}
+			x.Listen_Addr = tmp
+		case "timeout":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 6); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "timeout", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "timeout", len(b)-len(v))
+				}
+			}
+
+			var tmp int
+			tmp, v, err = cbor.ReadIntBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "timeout", len(b)-len(v))
+			}
+			x.Timeout = tmp
+		case "zone":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 7); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "zone", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "zone", len(b)-len(v))
+				}
+			}
+
+			var tmp string
+			tmp, v, err = in.ReadStringBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "zone", len(b)-len(v))
+			}
+			x.Region = tmp
+		case "OWNER":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 8); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "OWNER", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "OWNER", len(b)-len(v))
+				}
+			}
+
+			var tmp string
+			tmp, v, err = in.ReadStringBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "OWNER", len(b)-len(v))
+			}
+			x.Owner = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *SnakeConfig) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "config_json":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			x.ConfigJSON, v, err = cbor.ReadTrustedStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+		case "user_id":
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp int
+			tmp, v, err = cbor.ReadIntBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.UserID = tmp
+		case "rtt_millis":
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp int64
+			tmp, v, err = cbor.ReadInt64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.RTTMillis = tmp
+		case "http_server":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			x.HTTPServer, v, err = cbor.ReadTrustedStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+		case "base64_data":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Base64Data = cbor.NullSlice(x.Base64Data)
+				break
+			}
+			var tmp []byte
+			tmp, v, err = cbor.ReadBytesBytes(v, nil)
+			if err != nil {
+				return b, err
+			}
+			x.Base64Data = tmp
+		case "listen_addr":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			x.Listen_Addr, v, err = cbor.ReadTrustedStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+		case "timeout":
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp int
+			tmp, v, err = cbor.ReadIntBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Timeout = tmp
+		case "zone":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			x.Region, v, err = cbor.ReadTrustedStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+		case "OWNER":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			x.Owner, v, err = cbor.ReadTrustedStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *SnakeConfig) resetCBOR() {
+	var zero SnakeConfig
+	x.ConfigJSON = zero.ConfigJSON
+	x.UserID = zero.UserID
+	x.RTTMillis = zero.RTTMillis
+	x.HTTPServer = zero.HTTPServer
+	x.Base64Data = x.Base64Data[:0]
+	x.Listen_Addr = zero.Listen_Addr
+	x.Timeout = zero.Timeout
+	x.Region = zero.Region
+	x.Owner = zero.Owner
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *SnakeConfig) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
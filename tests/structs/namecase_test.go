@@ -0,0 +1,62 @@
+package structs
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+// encodedKeys returns the sorted text keys of the map encoded in b.
+func encodedKeys(t *testing.T, b []byte) string {
+	t.Helper()
+	iv, _, err := cbor.ReadInterfaceBytes(b)
+	if err != nil {
+		t.Fatalf("ReadInterfaceBytes error: %v", err)
+	}
+	m, ok := iv.(map[string]any)
+	if !ok {
+		t.Fatalf("decoded %T, want map[string]any", iv)
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
+
+func TestSnakeConfigNameCase(t *testing.T) {
+	orig := &SnakeConfig{
+		ConfigJSON: "{}", UserID: 7, RTTMillis: 12, HTTPServer: "h", Base64Data: []byte{1},
+		Listen_Addr: ":80", Timeout: 30, Region: "eu", Owner: "ops",
+	}
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	want := "OWNER,base64_data,config_json,http_server,listen_addr,rtt_millis,timeout,user_id,zone"
+	if got := encodedKeys(t, b); got != want {
+		t.Fatalf("keys = %s, want %s", got, want)
+	}
+
+	var dst SnakeConfig
+	if _, err := dst.DecodeSafe(b); err != nil {
+		t.Fatalf("DecodeSafe error: %v", err)
+	}
+	if dst.ConfigJSON != "{}" || dst.UserID != 7 || dst.RTTMillis != 12 || dst.Listen_Addr != ":80" || dst.Timeout != 30 || dst.Region != "eu" || dst.Owner != "ops" {
+		t.Fatalf("mismatch: got %+v want %+v", dst, *orig)
+	}
+}
+
+func TestCamelConfigNameCase(t *testing.T) {
+	b, err := (&CamelConfig{}).MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	want := "Owner,configJson,httpServer,rttMillis,userId"
+	if got := encodedKeys(t, b); got != want {
+		t.Fatalf("keys = %s, want %s", got, want)
+	}
+}
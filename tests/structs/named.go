@@ -0,0 +1,14 @@
+package structs
+
+// Roster and Tally exercise code generation for named top-level slice
+// and map types, which encode as a bare array or map.
+type Roster []Person
+
+// Tally counts occurrences by name.
+type Tally map[string]uint64
+
+// Team refers to the named types from struct fields.
+type Team struct {
+	Members Roster `cbor:"members,omitempty"`
+	Votes   Tally  `cbor:"votes"`
+}
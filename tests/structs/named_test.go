@@ -0,0 +1,130 @@
+package structs
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+type rosterDecoder struct {
+	name   string
+	decode func(dst *Roster, b []byte) ([]byte, error)
+}
+
+var rosterDecoders = []rosterDecoder{
+	{
+		name:   "DecodeSafe",
+		decode: (*Roster).DecodeSafe,
+	},
+	{
+		name:   "DecodeTrusted",
+		decode: (*Roster).DecodeTrusted,
+	},
+}
+
+type tallyDecoder struct {
+	name   string
+	decode func(dst *Tally, b []byte) ([]byte, error)
+}
+
+var tallyDecoders = []tallyDecoder{
+	{
+		name:   "DecodeSafe",
+		decode: (*Tally).DecodeSafe,
+	},
+	{
+		name:   "DecodeTrusted",
+		decode: (*Tally).DecodeTrusted,
+	},
+}
+
+func TestNamedSliceEncodesBareArray(t *testing.T) {
+	orig := Roster{{Name: "Ada", Age: 36}, {Name: "Grace", Data: []byte{1}}}
+
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	want := cbor.AppendArrayHeader(nil, 2)
+	for i := range orig {
+		if want, err = orig[i].MarshalCBOR(want); err != nil {
+			t.Fatalf("Person.MarshalCBOR error: %v", err)
+		}
+	}
+	if !bytes.Equal(b, want) {
+		t.Fatalf("encoded %x, want %x", b, want)
+	}
+
+	for _, dec := range rosterDecoders {
+		t.Run(dec.name, func(t *testing.T) {
+			var got Roster
+			rest, err := dec.decode(&got, b)
+			if err != nil {
+				t.Fatalf("decode error: %v", err)
+			}
+			if len(rest) != 0 {
+				t.Fatalf("unexpected trailing bytes: %d", len(rest))
+			}
+			if !reflect.DeepEqual(got, orig) {
+				t.Fatalf("round trip mismatch: got %+v, want %+v", got, orig)
+			}
+		})
+	}
+}
+
+func TestNamedMapEncodesBareMap(t *testing.T) {
+	orig := Tally{"yes": 3, "no": 1}
+
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	if sz, _, err := cbor.ReadMapHeaderBytes(b); err != nil || sz != 2 {
+		t.Fatalf("map header = %d, %v; want 2 entries", sz, err)
+	}
+
+	for _, dec := range tallyDecoders {
+		t.Run(dec.name, func(t *testing.T) {
+			got := Tally{"stale": 9}
+			rest, err := dec.decode(&got, b)
+			if err != nil {
+				t.Fatalf("decode error: %v", err)
+			}
+			if len(rest) != 0 {
+				t.Fatalf("unexpected trailing bytes: %d", len(rest))
+			}
+			if !reflect.DeepEqual(got, orig) {
+				t.Fatalf("round trip mismatch: got %v, want %v", got, orig)
+			}
+		})
+	}
+}
+
+func TestNamedTypesAsFields(t *testing.T) {
+	orig := &Team{Members: Roster{{Name: "Ada"}}, Votes: Tally{"yes": 2}}
+
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	for _, dec := range []func(*Team, []byte) ([]byte, error){(*Team).DecodeSafe, (*Team).DecodeTrusted} {
+		var got Team
+		if _, err := dec(&got, b); err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+		if !reflect.DeepEqual(&got, orig) {
+			t.Fatalf("round trip mismatch: got %+v, want %+v", got, orig)
+		}
+	}
+
+	// An empty Roster is dropped by omitempty.
+	b, err = (&Team{Votes: Tally{}}).MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	if sz, _, err := cbor.ReadMapHeaderBytes(b); err != nil || sz != 1 {
+		t.Fatalf("map header = %d, %v; want 1 entry", sz, err)
+	}
+}
@@ -0,0 +1,76 @@
+package structs
+
+import (
+	"encoding/hex"
+	"errors"
+	"math"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+// float32 and float64 destinations of f16, f32 and f64 items, with
+// NarrowFloats off and on.
+func TestFloatWidths(t *testing.T) {
+	type want struct {
+		f32 float32
+		err bool // float32 destination fails
+	}
+	cases := []struct {
+		name   string
+		item   string
+		f64    float64 // float64 destination, either way
+		strict want    // NarrowFloats off
+		narrow want    // NarrowFloats on
+	}{
+		{"f16", "f93e00", 1.5, want{f32: 1.5}, want{f32: 1.5}},
+		{"f16 inf", "f97c00", math.Inf(1), want{f32: float32(math.Inf(1))}, want{f32: float32(math.Inf(1))}},
+		{"f32", "fa3dcccccd", float64(float32(0.1)), want{f32: 0.1}, want{f32: 0.1}},
+		{"f64 exact", "fb3ff8000000000000", 1.5, want{f32: 1.5}, want{f32: 1.5}},
+		{"f64 lossy", "fb3fb999999999999a", 0.1, want{err: true}, want{f32: 0.1}},
+		{"f64 overflow", "fb7e37e43c8800759c", 1e300, want{err: true}, want{f32: float32(math.Inf(1))}},
+	}
+	defer func() { cbor.NarrowFloats = false }()
+	for _, narrow := range []bool{false, true} {
+		cbor.NarrowFloats = narrow
+		for _, tc := range cases {
+			item, _ := hex.DecodeString(tc.item)
+			w := tc.strict
+			if narrow {
+				w = tc.narrow
+			}
+			for _, dec := range scalarsDecoders {
+				var dst Scalars
+				_, err := dec.decode(&dst, scalarsField("f64", func(b []byte) []byte { return append(b, item...) }))
+				if err != nil || dst.F64 != tc.f64 {
+					t.Errorf("narrow=%v %s %s: F64 = %v, %v; want %v", narrow, tc.name, dec.name, dst.F64, err, tc.f64)
+				}
+				_, err = dec.decode(&dst, scalarsField("f32", func(b []byte) []byte { return append(b, item...) }))
+				checkFloat32(t, narrow, tc.name+" "+dec.name, dst.F32, err, w.f32, w.err)
+			}
+			var rv struct{ F float32 }
+			err := cbor.Unmarshal(scalarsField("F", func(b []byte) []byte { return append(b, item...) }), &rv)
+			checkFloat32(t, narrow, tc.name+" Unmarshal", rv.F, err, w.f32, w.err)
+		}
+	}
+	cbor.NarrowFloats = false
+	// NaN survives in any width, NarrowFloats or not.
+	f, _, err := cbor.ReadFloat32Bytes([]byte{0xfb, 0x7f, 0xf8, 0, 0, 0, 0, 0, 1})
+	if err != nil || !math.IsNaN(float64(f)) {
+		t.Fatalf("float64 NaN as float32 = %v, %v", f, err)
+	}
+}
+
+func checkFloat32(t *testing.T, narrow bool, name string, got float32, err error, want float32, wantErr bool) {
+	t.Helper()
+	if wantErr {
+		var fce cbor.FloatConversionError
+		if !errors.As(err, &fce) || fce.Type != "float32" {
+			t.Errorf("narrow=%v %s: error = %v, want FloatConversionError to float32", narrow, name, err)
+		}
+		return
+	}
+	if err != nil || got != want {
+		t.Errorf("narrow=%v %s: F32 = %v, %v; want %v", narrow, name, got, err, want)
+	}
+}
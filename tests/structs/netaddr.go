@@ -0,0 +1,18 @@
+package structs
+
+import (
+	"net"
+	"net/netip"
+)
+
+// Endpoint exercises net.IP, netip.Addr and netip.AddrPort fields, with
+// and without the RFC 9164 address tags.
+type Endpoint struct {
+	Name    string         `cbor:"name"`
+	IP      net.IP         `cbor:"ip"`
+	Addr    netip.Addr     `cbor:"addr"`
+	Listen  netip.AddrPort `cbor:"listen"`
+	Gateway net.IP         `cbor:"gateway,omitempty,tag=52"`
+	Peer    netip.Addr     `cbor:"peer,omitempty,tag=54"`
+	Proxy   netip.AddrPort `cbor:"proxy,omitzero"`
+}
@@ -0,0 +1,171 @@
+package structs
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"net/netip"
+	"reflect"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+var endpointDecoders = []struct {
+	name   string
+	decode func(dst *Endpoint, b []byte) ([]byte, error)
+}{
+	{"DecodeSafe", (*Endpoint).DecodeSafe},
+	{"DecodeTrusted", (*Endpoint).DecodeTrusted},
+}
+
+func TestEndpointRoundTrip(t *testing.T) {
+	cases := []Endpoint{
+		{Name: "empty"},
+		{
+			Name:    "v4",
+			IP:      net.ParseIP("192.0.2.1"),
+			Addr:    netip.MustParseAddr("198.51.100.7"),
+			Listen:  netip.MustParseAddrPort("0.0.0.0:8080"),
+			Gateway: net.IPv4(192, 0, 2, 254),
+			Peer:    netip.MustParseAddr("203.0.113.9"),
+			Proxy:   netip.MustParseAddrPort("127.0.0.1:3128"),
+		},
+		{
+			Name:    "v6",
+			IP:      net.ParseIP("2001:db8::1"),
+			Addr:    netip.MustParseAddr("::ffff:198.51.100.7"),
+			Listen:  netip.MustParseAddrPort("[::1]:443"),
+			Gateway: net.ParseIP("fe80::1"),
+			Peer:    netip.MustParseAddr("2001:db8::2"),
+		},
+	}
+	for _, in := range cases {
+		b, err := in.MarshalCBOR(nil)
+		if err != nil {
+			t.Fatalf("%s: MarshalCBOR error: %v", in.Name, err)
+		}
+		if len(b) > in.Msgsize() {
+			t.Fatalf("%s: encoded %d bytes, Msgsize %d", in.Name, len(b), in.Msgsize())
+		}
+		for _, d := range endpointDecoders {
+			var out Endpoint
+			rest, err := d.decode(&out, b)
+			if err != nil || len(rest) != 0 {
+				t.Fatalf("%s %s: err=%v rest=%d", in.Name, d.name, err, len(rest))
+			}
+			if !out.IP.Equal(in.IP) || !out.Gateway.Equal(in.Gateway) {
+				t.Fatalf("%s %s: IPs %v %v, want %v %v", in.Name, d.name, out.IP, out.Gateway, in.IP, in.Gateway)
+			}
+			if out.Addr != in.Addr || out.Listen != in.Listen || out.Peer != in.Peer || out.Proxy != in.Proxy {
+				t.Fatalf("%s %s: got %+v, want %+v", in.Name, d.name, out, in)
+			}
+			// A second round trip writes the same bytes.
+			again, err := out.MarshalCBOR(nil)
+			if err != nil || !bytes.Equal(again, b) {
+				t.Fatalf("%s %s: re-encoding differs: % x vs % x", in.Name, d.name, again, b)
+			}
+		}
+	}
+}
+
+func TestEndpointAddressForms(t *testing.T) {
+	// A 16-byte IPv4 net.IP is written as 4 bytes; tag 52 marks it IPv4.
+	in := Endpoint{IP: net.ParseIP("192.0.2.1"), Gateway: net.ParseIP("192.0.2.254")}
+	b, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(b, []byte{0x44, 192, 0, 2, 1}) {
+		t.Fatalf("IPv4 net.IP not written as 4 bytes: % x", b)
+	}
+	if !bytes.Contains(b, []byte{0xd8, 52, 0x44, 192, 0, 2, 254}) {
+		t.Fatalf("gateway not written under tag 52: % x", b)
+	}
+
+	// An IPv4-mapped netip.Addr stays IPv6 and is tagged 54.
+	mapped := netip.MustParseAddr("::ffff:192.0.2.1")
+	in = Endpoint{Addr: mapped, Peer: mapped}
+	if b, err = in.MarshalCBOR(nil); err != nil {
+		t.Fatal(err)
+	}
+	as16 := mapped.As16()
+	want := append([]byte{0xd8, 54, 0x50}, as16[:]...)
+	if !bytes.Contains(b, want) {
+		t.Fatalf("mapped peer not written as tag 54 and 16 bytes: % x", b)
+	}
+
+	// Decoders accept tagged and untagged addresses in any field.
+	tagged := cbor.AppendAddrTagged(nil, netip.MustParseAddr("192.0.2.1"))
+	a, rest, err := cbor.ReadAddrBytes(tagged)
+	if err != nil || len(rest) != 0 || a != netip.MustParseAddr("192.0.2.1") {
+		t.Fatalf("ReadAddrBytes(tagged) = %v, %d, %v", a, len(rest), err)
+	}
+	ip, _, err := cbor.ReadIPBytes(cbor.AppendAddr(nil, mapped))
+	if err != nil || !ip.Equal(net.ParseIP("192.0.2.1")) {
+		t.Fatalf("ReadIPBytes(mapped) = %v, %v", ip, err)
+	}
+}
+
+func TestEndpointInvalidAddress(t *testing.T) {
+	cases := map[string][]byte{
+		"short":      cbor.AppendBytes(nil, []byte{1, 2, 3}),
+		"tag 52 v6":  cbor.AppendBytes(cbor.AppendTag(nil, 52), make([]byte, 16)),
+		"tag 54 v4":  cbor.AppendBytes(cbor.AppendTag(nil, 54), []byte{1, 2, 3, 4}),
+		"other tag":  cbor.AppendBytes(cbor.AppendTag(nil, 22), []byte{1, 2, 3, 4}),
+		"addrport 1": cbor.AppendArrayHeader(nil, 1),
+	}
+	for name, v := range cases {
+		b := cbor.AppendMapHeader(nil, 1)
+		if name == "addrport 1" {
+			b = cbor.AppendString(b, "listen")
+		} else {
+			b = cbor.AppendString(b, "addr")
+		}
+		b = append(b, v...)
+		for _, d := range endpointDecoders {
+			var out Endpoint
+			if _, err := d.decode(&out, b); err == nil {
+				t.Fatalf("%s %s: decoded % x without error", name, d.name, b)
+			}
+		}
+	}
+
+	var ipErr cbor.InvalidIPError
+	_, _, err := cbor.ReadIPBytes(cases["tag 54 v4"])
+	if !errors.As(err, &ipErr) || ipErr.Len != 4 || ipErr.Tag != 54 {
+		t.Fatalf("ReadIPBytes error = %v, want InvalidIPError{4, 54}", err)
+	}
+}
+
+func TestEndpointReflectMatchesGenerated(t *testing.T) {
+	type plain Endpoint // no generated methods
+	in := Endpoint{
+		Name:    "r",
+		IP:      net.ParseIP("192.0.2.1"),
+		Addr:    netip.MustParseAddr("2001:db8::1"),
+		Listen:  netip.MustParseAddrPort("192.0.2.1:53"),
+		Gateway: net.ParseIP("2001:db8::fe"),
+	}
+	want, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := cbor.Marshal(plain(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("Marshal = % x\nwant      % x", got, want)
+	}
+	var out plain
+	if err := cbor.Unmarshal(want, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !out.IP.Equal(in.IP) || out.Addr != in.Addr || out.Listen != in.Listen || !out.Gateway.Equal(in.Gateway) {
+		t.Fatalf("Unmarshal = %+v, want %+v", out, in)
+	}
+	if !reflect.DeepEqual(out.Peer, netip.Addr{}) {
+		t.Fatalf("Peer = %v, want zero", out.Peer)
+	}
+}
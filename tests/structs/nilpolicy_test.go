@@ -0,0 +1,77 @@
+package structs
+
+import (
+	"bytes"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+func TestNilContainersRoundTrip(t *testing.T) {
+	defer func() { cbor.NilContainers = cbor.NilAsEmpty }()
+	in := Series{Name: "idle"}
+	empty, err := in.MarshalCBOR(nil)
+	if err != nil || bytes.Contains(empty, []byte{0xf6}) {
+		t.Fatalf("NilAsEmpty: MarshalCBOR = %x, %v; want no nulls", empty, err)
+	}
+
+	cbor.NilContainers = cbor.NilAsNull
+	null, err := in.MarshalCBOR(nil)
+	if err != nil || bytes.Count(null, []byte{0xf6}) != 4 {
+		t.Fatalf("NilAsNull: MarshalCBOR = %x, %v; want four nulls", null, err)
+	}
+	var streamed bytes.Buffer
+	enc := cbor.NewEncoder(&streamed)
+	if err := in.MarshalCBORStream(enc); err != nil {
+		t.Fatal(err)
+	}
+	if err := enc.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(streamed.Bytes(), null) {
+		t.Errorf("NilAsNull: MarshalCBORStream = %x, want %x", streamed.Bytes(), null)
+	}
+
+	for _, policy := range []cbor.NilPolicy{cbor.NilAsEmpty, cbor.NilAsNull} {
+		cbor.NilContainers = policy
+		for _, dec := range seriesDecoders {
+			// Decode over a value whose fields are set, as a pooled value would be.
+			out := *newSeries(2)
+			if _, err := dec.decode(&out, null); err != nil {
+				t.Fatalf("%v %s: error: %v", policy, dec.name, err)
+			}
+			nils := []bool{out.Samples == nil, out.Raw == nil, out.Attrs == nil, out.Counts == nil}
+			for i, isNil := range nils {
+				if isNil != (policy == cbor.NilAsNull) {
+					t.Errorf("%v %s: field %d nil = %v", policy, dec.name, i, isNil)
+				}
+			}
+			if len(out.Samples)+len(out.Raw)+len(out.Attrs)+len(out.Counts) != 0 {
+				t.Errorf("%v %s: decoded %+v, want empty fields", policy, dec.name, out)
+			}
+		}
+	}
+}
+
+func TestNilContainersNamedTypes(t *testing.T) {
+	defer func() { cbor.NilContainers = cbor.NilAsEmpty }()
+	cbor.NilContainers = cbor.NilAsNull
+	var votes Tally
+	b, err := votes.MarshalCBOR(nil)
+	if err != nil || !bytes.Equal(b, []byte{0xf6}) {
+		t.Fatalf("MarshalCBOR = %x, %v; want f6", b, err)
+	}
+	votes = Tally{"a": 1}
+	if _, err := votes.DecodeSafe(b); err != nil || votes != nil {
+		t.Fatalf("DecodeSafe(null) = %v, %v; want nil", votes, err)
+	}
+
+	cbor.NilContainers = cbor.NilAsEmpty
+	var team Team
+	if _, err := team.DecodeTrusted([]byte{0xa1, 0x65, 'v', 'o', 't', 'e', 's', 0xf6}); err != nil {
+		t.Fatal(err)
+	}
+	if team.Votes == nil || len(team.Votes) != 0 {
+		t.Fatalf("Votes = %#v, want an empty map", team.Votes)
+	}
+}
@@ -0,0 +1,21 @@
+package structs
+
+import cbor "github.com/delaneyj/cbor/runtime"
+
+// Ledger exercises cbor.Number fields, which accept any CBOR integer or
+// float and re-encode it exactly, alongside an interface field decoded
+// with cbor.UseNumber.
+type Ledger struct {
+	Account string      `cbor:"account"`
+	Amount  cbor.Number `cbor:"amount"`
+	Rate    cbor.Number `cbor:"rate"`
+	Extra   any         `cbor:"extra,omitempty"`
+}
+
+// Receipt exercises cbor.Decimal fields, fixed-point amounts written as
+// tag 4 decimal fractions.
+type Receipt struct {
+	Total    cbor.Decimal   `cbor:"total"`
+	Discount *cbor.Decimal  `cbor:"discount,omitempty"`
+	Lines    []cbor.Decimal `cbor:"lines"`
+}
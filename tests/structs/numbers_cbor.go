@@ -0,0 +1,521 @@
+// Code generated by cborgen DO NOT EDIT.
+
+package structs
+
+import (
+	"io"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+// Fail the build if a generated method drifts from the runtime interfaces.
+var (
+	_ cbor.Marshaler   = (*Ledger)(nil)
+	_ cbor.Unmarshaler = (*Ledger)(nil)
+	_ cbor.Marshaler   = (*Receipt)(nil)
+	_ cbor.Unmarshaler = (*Receipt)(nil)
+)
+
+func (x Ledger) Msgsize() (s int) {
+	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("account") + cbor.StringPrefixSize + len(x.Account)
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *Ledger) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *Ledger) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *Ledger) AppendCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	count := uint32(0)
+	count++
+	count++
+	count++
+	if !(x.Extra == nil) {
+		count++
+	}
+	b = cbor.AppendMapHeader(b, count)
+	var err error
+	b = cbor.AppendString(b, "account")
+	b, err = cbor.AppendString(b, x.Account), nil
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "amount")
+	b, err = x.Amount.MarshalCBOR(b)
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "rate")
+	b, err = x.Rate.MarshalCBOR(b)
+	if err != nil {
+		return b, err
+	}
+	if !(x.Extra == nil) {
+		b = cbor.AppendString(b, "extra")
+		b, err = cbor.AppendInterface(b, x.Extra)
+		if err != nil {
+			return b, err
+		}
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *Ledger) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *Ledger) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := in.ReadStringBytes(rest)
+		if err != nil {
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+		}
+		switch key {
+		case "account":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "account", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "account", len(b)-len(v))
+				}
+			}
+
+			var tmp string
+			tmp, v, err = in.ReadStringBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "account", len(b)-len(v))
+			}
+			x.Account = tmp
+		case "amount":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "amount", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+
+			v, err = x.Amount.UnmarshalCBOR(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "amount", len(b)-len(v))
+			}
+		case "rate":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 2); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "rate", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+
+			v, err = x.Rate.UnmarshalCBOR(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "rate", len(b)-len(v))
+			}
+		case "extra":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 3); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "extra", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+
+			x.Extra, v, err = cbor.ReadInterfaceAsBytes[any](v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "extra", len(b)-len(v))
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *Ledger) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "account":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			x.Account, v, err = cbor.ReadTrustedStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+		case "amount":
+
+			v, err = x.Amount.UnmarshalCBOR(v)
+			if err != nil {
+				return b, err
+			}
+		case "rate":
+
+			v, err = x.Rate.UnmarshalCBOR(v)
+			if err != nil {
+				return b, err
+			}
+		case "extra":
+
+			x.Extra, v, err = cbor.ReadInterfaceAsBytes[any](v)
+			if err != nil {
+				return b, err
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *Ledger) resetCBOR() {
+	var zero Ledger
+	x.Account = zero.Account
+	x.Amount = zero.Amount
+	x.Rate = zero.Rate
+	x.Extra = zero.Extra
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Ledger) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *Receipt) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *Receipt) MarshalCBORTo(w io.Writer) (int, error) {
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *Receipt) AppendCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	count := uint32(0)
+	count++
+	if !(x.Discount == nil) {
+		count++
+	}
+	count++
+	b = cbor.AppendMapHeader(b, count)
+	var err error
+	b = cbor.AppendString(b, "total")
+	b, err = x.Total.MarshalCBOR(b)
+	if err != nil {
+		return b, err
+	}
+	if !(x.Discount == nil) {
+		b = cbor.AppendString(b, "discount")
+		b, err = cbor.AppendInterface(b, x.Discount)
+		if err != nil {
+			return b, err
+		}
+	}
+	if x.Lines == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = cbor.AppendString(b, "lines")
+		b = cbor.AppendNil(b)
+	} else {
+		b = cbor.AppendString(b, "lines")
+		b = cbor.AppendArrayHeader(b, uint32(len(x.Lines)))
+		for i := range x.Lines {
+			b, err = x.Lines[i].MarshalCBOR(b)
+			if err != nil {
+				return b, err
+			}
+		}
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *Receipt) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *Receipt) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := in.ReadStringBytes(rest)
+		if err != nil {
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+		}
+		switch key {
+		case "total":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "total", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+
+			v, err = x.Total.UnmarshalCBOR(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "total", len(b)-len(v))
+			}
+		case "discount":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "discount", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+
+			v, err = cbor.ReadWithCodec(v, &x.Discount)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "discount", len(b)-len(v))
+			}
+		case "lines":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 2); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "lines", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "lines", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Lines = cbor.NullSlice(x.Lines)
+				break
+			}
+			var sz uint32
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "lines", len(b)-len(v))
+			}
+			if cap(x.Lines) >= int(sz) {
+				x.Lines = x.Lines[:sz]
+			} else {
+				x.Lines = make([]cbor.Decimal, sz)
+			}
+			if sz > 0 {
+				_ = x.Lines[sz-1]
+			}
+			for iLines := uint32(0); iLines < sz; iLines++ {
+				var tmp cbor.Decimal
+				v, err = (&tmp).UnmarshalCBOR(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, int(iLines)), "lines", len(b)-len(v))
+				}
+				x.Lines[iLines] = tmp
+			}
+			if indef {
+				v = v[1:] // break
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *Receipt) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "total":
+
+			v, err = x.Total.UnmarshalCBOR(v)
+			if err != nil {
+				return b, err
+			}
+		case "discount":
+
+			v, err = cbor.ReadWithCodec(v, &x.Discount)
+			if err != nil {
+				return b, err
+			}
+		case "lines":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Lines = cbor.NullSlice(x.Lines)
+				break
+			}
+			var sz uint32
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap(x.Lines) >= int(sz) {
+				x.Lines = x.Lines[:sz]
+			} else {
+				x.Lines = make([]cbor.Decimal, sz)
+			}
+			if sz > 0 {
+				_ = x.Lines[sz-1]
+			}
+			for iLines := uint32(0); iLines < sz; iLines++ {
+				var tmp cbor.Decimal
+				v, err = (&tmp).UnmarshalCBOR(v)
+				if err != nil {
+					return b, err
+				}
+				x.Lines[iLines] = tmp
+			}
+			if indef {
+				v = v[1:] // break
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *Receipt) resetCBOR() {
+	var zero Receipt
+	x.Total = zero.Total
+	x.Discount = zero.Discount
+	x.Lines = x.Lines[:0]
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Receipt) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
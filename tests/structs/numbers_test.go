@@ -0,0 +1,186 @@
+package structs
+
+import (
+	"bytes"
+	"math"
+	"math/big"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+func TestLedgerNumberRoundTrip(t *testing.T) {
+	huge, _ := new(big.Int).SetString("-340282366920938463463374607431768211456", 10)
+
+	cases := []struct {
+		name   string
+		amount []byte
+		want   string
+	}{
+		{name: "MaxUint64", amount: cbor.AppendUint64(nil, math.MaxUint64), want: "18446744073709551615"},
+		{name: "MinInt64", amount: cbor.AppendInt64(nil, math.MinInt64), want: "-9223372036854775808"},
+		// 0x3b ff..ff is -2^64, below the int64 range.
+		{name: "NegBeyondInt64", amount: []byte{0x3b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, want: "-18446744073709551616"},
+		{name: "Bignum", amount: cbor.AppendBigInt(nil, huge), want: huge.String()},
+		{name: "Float16", amount: []byte{0xf9, 0x3e, 0x00}, want: "1.5"},
+		{name: "Float32", amount: cbor.AppendFloat32(nil, 0.1), want: "0.1"},
+		{name: "Float64", amount: cbor.AppendFloat64(nil, 0.1), want: "0.1"},
+	}
+
+	decoders := []struct {
+		name string
+		fn   func(*Ledger, []byte) ([]byte, error)
+	}{
+		{name: "DecodeSafe", fn: (*Ledger).DecodeSafe},
+		{name: "DecodeTrusted", fn: (*Ledger).DecodeTrusted},
+	}
+
+	for _, tc := range cases {
+		// Hand-build {"account": "acct", "amount": <n>, "rate": <n>}.
+		in := cbor.AppendMapHeader(nil, 3)
+		in = cbor.AppendString(in, "account")
+		in = cbor.AppendString(in, "acct")
+		in = cbor.AppendString(in, "amount")
+		in = append(in, tc.amount...)
+		in = cbor.AppendString(in, "rate")
+		in = append(in, tc.amount...)
+
+		for _, dec := range decoders {
+			t.Run(tc.name+"/"+dec.name, func(t *testing.T) {
+				var got Ledger
+				rest, err := dec.fn(&got, in)
+				if err != nil {
+					t.Fatalf("%s error: %v", dec.name, err)
+				}
+				if len(rest) != 0 {
+					t.Fatalf("%s left %d bytes", dec.name, len(rest))
+				}
+				if s := got.Amount.String(); s != tc.want {
+					t.Fatalf("Amount = %s, want %s", s, tc.want)
+				}
+
+				out, err := got.MarshalCBOR(nil)
+				if err != nil {
+					t.Fatalf("MarshalCBOR error: %v", err)
+				}
+				// Half floats are widened to float32 on decode; every
+				// other form re-encodes byte for byte.
+				if tc.amount[0] != 0xf9 && !bytes.Equal(out, in) {
+					t.Fatalf("round trip mismatch:\n got %x\nwant %x", out, in)
+				}
+			})
+		}
+	}
+}
+
+func TestLedgerNumberRejectsNonNumbers(t *testing.T) {
+	in := cbor.AppendMapHeader(nil, 1)
+	in = cbor.AppendString(in, "amount")
+	in = cbor.AppendString(in, "12")
+
+	var got Ledger
+	if _, err := got.DecodeSafe(in); err == nil {
+		t.Fatalf("expected error decoding text into cbor.Number")
+	}
+}
+
+func TestLedgerInterfaceUseNumber(t *testing.T) {
+	src := Ledger{Account: "acct", Extra: uint64(math.MaxUint64)}
+	b, err := src.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+
+	var dst Ledger
+	if _, err := dst.DecodeSafe(b); err != nil {
+		t.Fatalf("DecodeSafe error: %v", err)
+	}
+	if _, ok := dst.Extra.(uint64); !ok {
+		t.Fatalf("expected uint64 without UseNumber, got %T", dst.Extra)
+	}
+
+	cbor.UseNumber = true
+	defer func() { cbor.UseNumber = false }()
+
+	// -2^64 only fits a Number; nested values are converted too.
+	neg := []byte{0x3b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+	extra := cbor.AppendArrayHeader(nil, 2)
+	extra = append(extra, neg...)
+	extra = cbor.AppendFloat64(extra, 2.5)
+	in := cbor.AppendMapHeader(nil, 1)
+	in = cbor.AppendString(in, "extra")
+	in = append(in, extra...)
+
+	dst = Ledger{}
+	if _, err := dst.DecodeSafe(in); err != nil {
+		t.Fatalf("DecodeSafe error: %v", err)
+	}
+	list, ok := dst.Extra.([]any)
+	if !ok || len(list) != 2 {
+		t.Fatalf("expected []any of 2, got %#v", dst.Extra)
+	}
+	n, ok := list[0].(cbor.Number)
+	if !ok {
+		t.Fatalf("expected cbor.Number, got %T", list[0])
+	}
+	if z, ok := n.BigInt(); !ok || z.String() != "-18446744073709551616" {
+		t.Fatalf("BigInt = %v, %v", z, ok)
+	}
+	if f, ok := list[1].(cbor.Number); !ok || f.String() != "2.5" {
+		t.Fatalf("expected Number 2.5, got %#v", list[1])
+	}
+
+	out, err := dst.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	want := cbor.AppendMapHeader(nil, 4)
+	want = cbor.AppendString(want, "account")
+	want = cbor.AppendString(want, "")
+	want = cbor.AppendString(want, "amount")
+	want = cbor.AppendInt64(want, 0)
+	want = cbor.AppendString(want, "rate")
+	want = cbor.AppendInt64(want, 0)
+	want = cbor.AppendString(want, "extra")
+	want = append(want, extra...)
+	if !bytes.Equal(out, want) {
+		t.Fatalf("re-encode mismatch:\n got %x\nwant %x", out, want)
+	}
+}
+
+func TestReceiptDecimalRoundTrip(t *testing.T) {
+	discount := cbor.Decimal{Scale: 1, Value: -25}
+	in := Receipt{
+		Total:    cbor.Decimal{Scale: 2, Value: 12345},
+		Discount: &discount,
+		Lines:    []cbor.Decimal{{Scale: 2, Value: 100}, {Scale: 0, Value: 7}, {Scale: -3, Value: 2}},
+	}
+	b, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The total opens with tag 4 and [-2, 12345].
+	if !bytes.Contains(b, []byte{0xc4, 0x82, 0x21, 0x19, 0x30, 0x39}) {
+		t.Fatalf("no tag 4 decimal fraction for the total in %x", b)
+	}
+	for _, dec := range []struct {
+		name string
+		fn   func(*Receipt, []byte) ([]byte, error)
+	}{
+		{name: "DecodeSafe", fn: (*Receipt).DecodeSafe},
+		{name: "DecodeTrusted", fn: (*Receipt).DecodeTrusted},
+	} {
+		t.Run(dec.name, func(t *testing.T) {
+			var got Receipt
+			if _, err := dec.fn(&got, b); err != nil {
+				t.Fatal(err)
+			}
+			if got.Total.String() != "123.45" || got.Discount == nil || got.Discount.String() != "-2.5" {
+				t.Fatalf("got total %v, discount %v", got.Total, got.Discount)
+			}
+			if len(got.Lines) != 3 || got.Lines[0].String() != "1.00" || got.Lines[2].String() != "2000" {
+				t.Fatalf("got lines %v", got.Lines)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package structs
+
+import "time"
+
+// Group is a comparable nested struct used with omitzero.
+type Group struct {
+	ID   int    `cbor:"id"`
+	Name string `cbor:"name"`
+}
+
+// Settings is a nested struct that is not comparable (it holds a slice),
+// so omitzero falls back to a reflection-based zero check.
+type Settings struct {
+	Flags []string `cbor:"flags"`
+}
+
+// Member exercises omitzero on struct values alongside omitempty.
+type Member struct {
+	Name     string    `cbor:"name"`
+	Group    Group     `cbor:"group,omitzero"`
+	Settings Settings  `cbor:"settings,omitzero"`
+	Joined   time.Time `cbor:"joined,omitzero"`
+	Tags     []string  `cbor:"tags,omitzero"`
+	Count    int       `cbor:"count,omitempty,omitzero"`
+}
+
+// Decimal is a fixed-point number. Any value with a zero mantissa is
+// zero, whatever its scale, which its IsZero method says.
+type Decimal struct {
+	Mantissa int64 `cbor:"m"`
+	Scale    int32 `cbor:"s"`
+}
+
+// IsZero reports whether d is zero.
+func (d Decimal) IsZero() bool { return d.Mantissa == 0 }
+
+// Currency is an ISO 4217 code; "XXX" means no currency.
+type Currency string
+
+// IsZero reports whether c names no currency.
+func (c *Currency) IsZero() bool { return *c == "" || *c == "XXX" }
+
+// Payment exercises omitempty and omitzero on types with an IsZero
+// method, which decides over the built-in checks.
+type Payment struct {
+	Amount   Decimal  `cbor:"amount,omitempty"`
+	Fee      Decimal  `cbor:"fee,omitzero"`
+	Tax      *Decimal `cbor:"tax,omitempty"`
+	Currency Currency `cbor:"currency,omitempty"`
+}
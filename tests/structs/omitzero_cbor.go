@@ -0,0 +1,1385 @@
+// Code generated by cborgen DO NOT EDIT.
+
+package structs
+
+import (
+	"io"
+	"time"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+// Fail the build if a generated method drifts from the runtime interfaces.
+var (
+	_ cbor.Marshaler   = (*Group)(nil)
+	_ cbor.Unmarshaler = (*Group)(nil)
+	_ cbor.Marshaler   = (*Settings)(nil)
+	_ cbor.Unmarshaler = (*Settings)(nil)
+	_ cbor.Marshaler   = (*Member)(nil)
+	_ cbor.Unmarshaler = (*Member)(nil)
+	_ cbor.Marshaler   = (*Decimal)(nil)
+	_ cbor.Unmarshaler = (*Decimal)(nil)
+	_ cbor.Marshaler   = (*Payment)(nil)
+	_ cbor.Unmarshaler = (*Payment)(nil)
+	_ cbor.Marshaler   = (*Currency)(nil)
+	_ cbor.Unmarshaler = (*Currency)(nil)
+)
+
+func (x Group) Msgsize() (s int) {
+	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("id") + cbor.IntSize + cbor.StringPrefixSize + len("name") + cbor.StringPrefixSize + len(x.Name)
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *Group) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *Group) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *Group) AppendCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	b = cbor.AppendMapHeader(b, uint32(2))
+	var err error
+	b = cbor.AppendString(b, "id")
+	b, err = cbor.AppendInt(b, x.ID), nil
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "name")
+	b, err = cbor.AppendString(b, x.Name), nil
+	if err != nil {
+		return b, err
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *Group) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *Group) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := in.ReadStringBytes(rest)
+		if err != nil {
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+		}
+		switch key {
+		case "id":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "id", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "id", len(b)-len(v))
+				}
+			}
+
+			var tmp int
+			tmp, v, err = cbor.ReadIntBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "id", len(b)-len(v))
+			}
+			x.ID = tmp
+		case "name":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
+				}
+			}
+
+			var tmp string
+			tmp, v, err = in.ReadStringBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
+			}
+			x.Name = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *Group) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "id":
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp int
+			tmp, v, err = cbor.ReadIntBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.ID = tmp
+		case "name":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			x.Name, v, err = cbor.ReadTrustedStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *Group) resetCBOR() {
+	var zero Group
+	x.ID = zero.ID
+	x.Name = zero.Name
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Group) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x Settings) Msgsize() (s int) {
+	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("flags") + cbor.ArrayHeaderSize + len(x.Flags)*cbor.StringPrefixSize
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *Settings) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *Settings) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *Settings) AppendCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	b = cbor.AppendMapHeader(b, uint32(1))
+	if x.Flags == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = cbor.AppendString(b, "flags")
+		b = cbor.AppendNil(b)
+	} else {
+		b = cbor.AppendString(b, "flags")
+		b = cbor.AppendArrayHeader(b, uint32(len(x.Flags)))
+		for _, v := range x.Flags {
+			b = cbor.AppendString(b, v)
+		}
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *Settings) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *Settings) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := in.ReadStringBytes(rest)
+		if err != nil {
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+		}
+		switch key {
+		case "flags":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "flags", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "flags", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Flags = cbor.NullSlice(x.Flags)
+				break
+			}
+			var sz uint32
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "flags", len(b)-len(v))
+			}
+			if cap(x.Flags) >= int(sz) {
+				x.Flags = x.Flags[:sz]
+			} else {
+				x.Flags = make([]string, sz)
+			}
+			if sz > 0 {
+				_ = x.Flags[sz-1]
+			}
+			for iFlags := uint32(0); iFlags < sz; iFlags++ {
+				var tmp string
+				tmp, v, err = in.ReadStringBytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, int(iFlags)), "flags", len(b)-len(v))
+				}
+				x.Flags[iFlags] = tmp
+			}
+			if indef {
+				v = v[1:] // break
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *Settings) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "flags":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Flags = cbor.NullSlice(x.Flags)
+				break
+			}
+			var sz uint32
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap(x.Flags) >= int(sz) {
+				x.Flags = x.Flags[:sz]
+			} else {
+				x.Flags = make([]string, sz)
+			}
+			if sz > 0 {
+				_ = x.Flags[sz-1]
+			}
+			for iFlags := uint32(0); iFlags < sz; iFlags++ {
+				var tmp string
+				tmp, v, err = cbor.ReadTrustedStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Flags[iFlags] = tmp
+			}
+			if indef {
+				v = v[1:] // break
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *Settings) resetCBOR() {
+	x.Flags = x.Flags[:0]
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Settings) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x Member) Msgsize() (s int) {
+	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("name") + cbor.StringPrefixSize + len(x.Name) + cbor.StringPrefixSize + len("joined") + cbor.TimeSize + cbor.StringPrefixSize + len("tags") + cbor.ArrayHeaderSize + len(x.Tags)*cbor.StringPrefixSize + cbor.StringPrefixSize + len("count") + cbor.IntSize
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *Member) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *Member) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *Member) AppendCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	count := uint32(0)
+	count++
+	if !(x.Group == (Group{})) {
+		count++
+	}
+	if !(cbor.IsZeroValue(x.Settings)) {
+		count++
+	}
+	if !(x.Joined.IsZero()) {
+		count++
+	}
+	if !(x.Tags == nil) {
+		count++
+	}
+	if !(x.Count == 0) {
+		count++
+	}
+	b = cbor.AppendMapHeader(b, count)
+	var err error
+	b = cbor.AppendString(b, "name")
+	b, err = cbor.AppendString(b, x.Name), nil
+	if err != nil {
+		return b, err
+	}
+	if !(x.Group == (Group{})) {
+		b = cbor.AppendString(b, "group")
+		b, err = x.Group.AppendCBOR(b)
+		if err != nil {
+			return b, err
+		}
+	}
+	if !(cbor.IsZeroValue(x.Settings)) {
+		b = cbor.AppendString(b, "settings")
+		b, err = x.Settings.AppendCBOR(b)
+		if err != nil {
+			return b, err
+		}
+	}
+	if !(x.Joined.IsZero()) {
+		b = cbor.AppendString(b, "joined")
+		b, err = cbor.AppendTime(b, x.Joined), nil
+		if err != nil {
+			return b, err
+		}
+	}
+	if !(x.Tags == nil) {
+		if x.Tags == nil && cbor.NilContainers == cbor.NilAsNull {
+			b = cbor.AppendString(b, "tags")
+			b = cbor.AppendNil(b)
+		} else {
+			b = cbor.AppendString(b, "tags")
+			b = cbor.AppendArrayHeader(b, uint32(len(x.Tags)))
+			for _, v := range x.Tags {
+				b = cbor.AppendString(b, v)
+			}
+		}
+	}
+	if !(x.Count == 0) {
+		b = cbor.AppendString(b, "count")
+		b, err = cbor.AppendInt(b, x.Count), nil
+		if err != nil {
+			return b, err
+		}
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *Member) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *Member) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := in.ReadStringBytes(rest)
+		if err != nil {
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+		}
+		switch key {
+		case "name":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
+				}
+			}
+
+			var tmp string
+			tmp, v, err = in.ReadStringBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
+			}
+			x.Name = tmp
+		case "group":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "group", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+
+			v, err = x.Group.DecodeInterned(v, in)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "group", len(b)-len(v))
+			}
+		case "settings":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 2); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "settings", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+
+			v, err = x.Settings.DecodeInterned(v, in)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "settings", len(b)-len(v))
+			}
+		case "joined":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 3); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "joined", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+
+			var tmp time.Time
+			tmp, v, err = cbor.ReadAnyTimeBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "joined", len(b)-len(v))
+			}
+			x.Joined = tmp
+		case "tags":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 4); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "tags", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "tags", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Tags = cbor.NullSlice(x.Tags)
+				break
+			}
+			var sz uint32
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "tags", len(b)-len(v))
+			}
+			if cap(x.Tags) >= int(sz) {
+				x.Tags = x.Tags[:sz]
+			} else {
+				x.Tags = make([]string, sz)
+			}
+			if sz > 0 {
+				_ = x.Tags[sz-1]
+			}
+			for iTags := uint32(0); iTags < sz; iTags++ {
+				var tmp string
+				tmp, v, err = in.ReadStringBytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, int(iTags)), "tags", len(b)-len(v))
+				}
+				x.Tags[iTags] = tmp
+			}
+			if indef {
+				v = v[1:] // break
+			}
+		case "count":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 5); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "count", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "count", len(b)-len(v))
+				}
+			}
+
+			var tmp int
+			tmp, v, err = cbor.ReadIntBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "count", len(b)-len(v))
+			}
+			x.Count = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *Member) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "name":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			x.Name, v, err = cbor.ReadTrustedStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+		case "group":
+
+			v, err = (&x.Group).DecodeTrusted(v)
+			if err != nil {
+				return b, err
+			}
+		case "settings":
+
+			v, err = (&x.Settings).DecodeTrusted(v)
+			if err != nil {
+				return b, err
+			}
+		case "joined":
+
+			var tmp time.Time
+			tmp, v, err = cbor.ReadAnyTimeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Joined = tmp
+		case "tags":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Tags = cbor.NullSlice(x.Tags)
+				break
+			}
+			var sz uint32
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap(x.Tags) >= int(sz) {
+				x.Tags = x.Tags[:sz]
+			} else {
+				x.Tags = make([]string, sz)
+			}
+			if sz > 0 {
+				_ = x.Tags[sz-1]
+			}
+			for iTags := uint32(0); iTags < sz; iTags++ {
+				var tmp string
+				tmp, v, err = cbor.ReadTrustedStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Tags[iTags] = tmp
+			}
+			if indef {
+				v = v[1:] // break
+			}
+		case "count":
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp int
+			tmp, v, err = cbor.ReadIntBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Count = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *Member) resetCBOR() {
+	var zero Member
+	x.Name = zero.Name
+	x.Group = zero.Group
+	x.Settings = zero.Settings
+	x.Joined = zero.Joined
+	x.Tags = x.Tags[:0]
+	x.Count = zero.Count
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Member) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x Decimal) Msgsize() (s int) {
+	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("m") + cbor.Int64Size + cbor.StringPrefixSize + len("s") + cbor.Int32Size
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *Decimal) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *Decimal) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *Decimal) AppendCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	b = cbor.AppendMapHeader(b, uint32(2))
+	var err error
+	b = cbor.AppendString(b, "m")
+	b, err = cbor.AppendInt64(b, x.Mantissa), nil
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "s")
+	b, err = cbor.AppendInt32(b, x.Scale), nil
+	if err != nil {
+		return b, err
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *Decimal) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *Decimal) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := in.ReadStringBytes(rest)
+		if err != nil {
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+		}
+		switch key {
+		case "m":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "m", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "m", len(b)-len(v))
+				}
+			}
+
+			var tmp int64
+			tmp, v, err = cbor.ReadInt64Bytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "m", len(b)-len(v))
+			}
+			x.Mantissa = tmp
+		case "s":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "s", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "s", len(b)-len(v))
+				}
+			}
+
+			var tmp int32
+			tmp, v, err = cbor.ReadInt32Bytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "s", len(b)-len(v))
+			}
+			x.Scale = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *Decimal) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "m":
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp int64
+			tmp, v, err = cbor.ReadInt64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Mantissa = tmp
+		case "s":
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp int32
+			tmp, v, err = cbor.ReadInt32Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Scale = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *Decimal) resetCBOR() {
+	var zero Decimal
+	x.Mantissa = zero.Mantissa
+	x.Scale = zero.Scale
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Decimal) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x Payment) Msgsize() (s int) {
+	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("currency") + cbor.StringPrefixSize + len(x.Currency)
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *Payment) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *Payment) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *Payment) AppendCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	count := uint32(0)
+	if !(x.Amount.IsZero()) {
+		count++
+	}
+	if !(x.Fee.IsZero()) {
+		count++
+	}
+	if !(x.Tax == nil) {
+		count++
+	}
+	if !(x.Currency.IsZero()) {
+		count++
+	}
+	b = cbor.AppendMapHeader(b, count)
+	var err error
+	if !(x.Amount.IsZero()) {
+		b = cbor.AppendString(b, "amount")
+		b, err = x.Amount.AppendCBOR(b)
+		if err != nil {
+			return b, err
+		}
+	}
+	if !(x.Fee.IsZero()) {
+		b = cbor.AppendString(b, "fee")
+		b, err = x.Fee.AppendCBOR(b)
+		if err != nil {
+			return b, err
+		}
+	}
+	if !(x.Tax == nil) {
+		b = cbor.AppendString(b, "tax")
+		b, err = x.Tax.AppendCBOR(b)
+		if err != nil {
+			return b, err
+		}
+	}
+	if !(x.Currency.IsZero()) {
+		b = cbor.AppendString(b, "currency")
+		b, err = x.Currency.AppendCBOR(b)
+		if err != nil {
+			return b, err
+		}
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *Payment) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *Payment) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := in.ReadStringBytes(rest)
+		if err != nil {
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+		}
+		switch key {
+		case "amount":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "amount", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+
+			v, err = x.Amount.DecodeInterned(v, in)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "amount", len(b)-len(v))
+			}
+		case "fee":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "fee", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+
+			v, err = x.Fee.DecodeInterned(v, in)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "fee", len(b)-len(v))
+			}
+		case "tax":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 2); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "tax", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Tax = nil
+				break
+			}
+			if x.Tax == nil {
+				x.Tax = new(Decimal)
+			} else {
+				x.Tax.resetCBOR()
+			}
+			v, err = x.Tax.DecodeInterned(v, in)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "tax", len(b)-len(v))
+			}
+		case "currency":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 3); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "currency", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+
+			v, err = x.Currency.DecodeInterned(v, in)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "currency", len(b)-len(v))
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *Payment) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "amount":
+
+			v, err = (&x.Amount).DecodeTrusted(v)
+			if err != nil {
+				return b, err
+			}
+		case "fee":
+
+			v, err = (&x.Fee).DecodeTrusted(v)
+			if err != nil {
+				return b, err
+			}
+		case "tax":
+
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Tax = nil
+				break
+			}
+			if x.Tax == nil {
+				x.Tax = new(Decimal)
+			} else {
+				x.Tax.resetCBOR()
+			}
+			v, err = x.Tax.DecodeTrusted(v)
+			if err != nil {
+				return b, err
+			}
+		case "currency":
+
+			v, err = (&x.Currency).DecodeTrusted(v)
+			if err != nil {
+				return b, err
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *Payment) resetCBOR() {
+	var zero Payment
+	x.Amount = zero.Amount
+	x.Fee = zero.Fee
+	x.Tax = zero.Tax
+	x.Currency = zero.Currency
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Payment) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *Currency) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// AppendCBOR appends x to b as the string it holds.
+func (x *Currency) AppendCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+	var err error
+	b, err = cbor.AppendString(b, string(*x)), nil
+	if err != nil {
+		return b, err
+	}
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *Currency) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+func (x *Currency) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	v := b
+	var err error
+	// The decode case breaks out of this switch once x is filled in.
+	switch {
+	default:
+		if cbor.IsTagged(v) {
+			if v, err = cbor.UntagBytes(v); err != nil {
+				return b, cbor.WrapDecodeError(err, "", len(b)-len(v))
+			}
+		}
+		var tmp string
+		tmp, v, err = cbor.ReadStringBytes(v)
+		if err != nil {
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(v))
+		}
+		*x = Currency(tmp)
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return v, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *Currency) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	v := b
+	var err error
+	// The decode case breaks out of this switch once x is filled in.
+	switch {
+	default:
+		if cbor.IsTagged(v) {
+			if v, err = cbor.UntagBytes(v); err != nil {
+				return b, err
+			}
+		}
+		var tmp string
+		tmp, v, err = cbor.ReadTrustedStringBytes(v)
+		if err != nil {
+			return b, err
+		}
+		*x = Currency(tmp)
+	}
+	return v, nil
+}
+
+// resetCBOR empties x, keeping its storage for reuse.
+func (x *Currency) resetCBOR() {
+	*x = ""
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Currency) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
@@ -0,0 +1,113 @@
+package structs
+
+import (
+	"testing"
+	"time"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+func memberKeys(t *testing.T, b []byte) map[string]bool {
+	t.Helper()
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		t.Fatalf("ReadMapHeaderBytes error: %v", err)
+	}
+	keys := make(map[string]bool, sz)
+	for i := uint32(0); i < sz; i++ {
+		var key string
+		key, rest, err = cbor.ReadStringBytes(rest)
+		if err != nil {
+			t.Fatalf("ReadStringBytes error: %v", err)
+		}
+		keys[key] = true
+		rest, err = cbor.Skip(rest)
+		if err != nil {
+			t.Fatalf("Skip error: %v", err)
+		}
+	}
+	return keys
+}
+
+func TestMemberOmitZeroDropsZeroStructs(t *testing.T) {
+	b, err := (&Member{Name: "solo"}).MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	keys := memberKeys(t, b)
+	if len(keys) != 1 || !keys["name"] {
+		t.Fatalf("expected only name key, got %v", keys)
+	}
+}
+
+func TestMemberOmitZeroKeepsNonZero(t *testing.T) {
+	orig := &Member{
+		Name:     "m",
+		Group:    Group{ID: 7},
+		Settings: Settings{Flags: []string{}},
+		Joined:   time.Unix(1700000000, 0),
+		Tags:     []string{},
+		Count:    3,
+	}
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	keys := memberKeys(t, b)
+	// An empty but non-nil slice is not the zero value, so omitzero
+	// keeps it (unlike omitempty).
+	for _, k := range []string{"name", "group", "settings", "joined", "tags", "count"} {
+		if !keys[k] {
+			t.Fatalf("expected key %q in %v", k, keys)
+		}
+	}
+
+	var dst Member
+	if _, err := dst.DecodeSafe(b); err != nil {
+		t.Fatalf("DecodeSafe error: %v", err)
+	}
+	if dst.Group != orig.Group || !dst.Joined.Equal(orig.Joined) || dst.Count != 3 {
+		t.Fatalf("mismatch: got %+v, want %+v", dst, orig)
+	}
+}
+
+func TestPaymentOmitsByIsZero(t *testing.T) {
+	// reflected mirrors Payment for cbor.Marshal, which must agree.
+	type reflected struct {
+		Amount   Decimal  `cbor:"amount,omitempty"`
+		Fee      Decimal  `cbor:"fee,omitzero"`
+		Tax      *Decimal `cbor:"tax,omitempty"`
+		Currency Currency `cbor:"currency,omitempty"`
+	}
+	tests := []struct {
+		in   Payment
+		want []string
+	}{
+		// A zero mantissa at scale 2 is not the Go zero value, but IsZero.
+		{Payment{Amount: Decimal{Scale: 2}, Fee: Decimal{Scale: 2}, Currency: "XXX"}, nil},
+		// A non-nil pointer is kept even when it points to zero.
+		{Payment{Tax: &Decimal{}}, []string{"tax"}},
+		{Payment{Amount: Decimal{Mantissa: 5}, Fee: Decimal{Mantissa: 1}, Currency: "EUR"}, []string{"amount", "fee", "currency"}},
+	}
+	for _, tc := range tests {
+		b, err := tc.in.MarshalCBOR(nil)
+		if err != nil {
+			t.Fatalf("MarshalCBOR error: %v", err)
+		}
+		rb, err := cbor.Marshal(reflected(tc.in))
+		if err != nil {
+			t.Fatalf("Marshal error: %v", err)
+		}
+		for name, enc := range map[string][]byte{"MarshalCBOR": b, "Marshal": rb} {
+			keys := memberKeys(t, enc)
+			if len(keys) != len(tc.want) {
+				t.Errorf("%s(%+v) keys = %v, want %v", name, tc.in, keys, tc.want)
+			}
+			for _, k := range tc.want {
+				if !keys[k] {
+					t.Errorf("%s(%+v) lacks %q", name, tc.in, k)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,12 @@
+package structs
+
+import "testing"
+
+// setOption sets a runtime option such as cbor.SkipUnknownTags to v for
+// the rest of the test, restoring its previous value on cleanup.
+func setOption[T any](t *testing.T, p *T, v T) {
+	t.Helper()
+	prev := *p
+	*p = v
+	t.Cleanup(func() { *p = prev })
+}
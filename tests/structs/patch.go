@@ -0,0 +1,11 @@
+package structs
+
+// Patch exercises scalar pointer fields: nil means absent, while a
+// non-nil pointer is set, possibly to its zero value.
+type Patch struct {
+	Name    *string  `cbor:"name,omitempty"`
+	Enabled *bool    `cbor:"enabled,omitempty"`
+	Count   *int     `cbor:"count"`
+	Ratio   *float64 `cbor:"ratio,omitempty"`
+	Level   *uint8   `cbor:"level,omitempty"`
+}
@@ -0,0 +1,133 @@
+package structs
+
+import (
+	"bytes"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+type patchDecoder struct {
+	name   string
+	decode func(dst *Patch, b []byte) ([]byte, error)
+}
+
+var patchDecoders = []patchDecoder{
+	{
+		name:   "DecodeSafe",
+		decode: (*Patch).DecodeSafe,
+	},
+	{
+		name:   "DecodeTrusted",
+		decode: (*Patch).DecodeTrusted,
+	},
+}
+
+func TestPatchFalseVersusNil(t *testing.T) {
+	off := false
+	set, err := (&Patch{Enabled: &off}).MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	unset, err := (&Patch{}).MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+
+	// A pointer to false is written; a nil omitempty pointer is not, and a
+	// nil pointer without omitempty is written as null.
+	wantSet := cbor.AppendMapHeader(nil, 2)
+	wantSet = cbor.AppendString(wantSet, "enabled")
+	wantSet = cbor.AppendBool(wantSet, false)
+	wantSet = cbor.AppendString(wantSet, "count")
+	wantSet = cbor.AppendNil(wantSet)
+	if !bytes.Equal(set, wantSet) {
+		t.Fatalf("encoded %x, want %x", set, wantSet)
+	}
+	wantUnset := cbor.AppendMapHeader(nil, 1)
+	wantUnset = cbor.AppendString(wantUnset, "count")
+	wantUnset = cbor.AppendNil(wantUnset)
+	if !bytes.Equal(unset, wantUnset) {
+		t.Fatalf("encoded %x, want %x", unset, wantUnset)
+	}
+
+	for _, dec := range patchDecoders {
+		t.Run(dec.name, func(t *testing.T) {
+			var got Patch
+			if _, err := dec.decode(&got, set); err != nil {
+				t.Fatalf("decode error: %v", err)
+			}
+			if got.Enabled == nil || *got.Enabled {
+				t.Fatalf("Enabled = %v, want pointer to false", got.Enabled)
+			}
+			if got.Count != nil {
+				t.Fatalf("Count = %v, want nil", *got.Count)
+			}
+
+			got = Patch{}
+			if _, err := dec.decode(&got, unset); err != nil {
+				t.Fatalf("decode error: %v", err)
+			}
+			if got.Enabled != nil {
+				t.Fatalf("Enabled = %v, want nil", *got.Enabled)
+			}
+		})
+	}
+}
+
+func TestPatchRoundTrip(t *testing.T) {
+	name, enabled, count, ratio, level := "", true, 0, 0.5, uint8(200)
+	orig := &Patch{Name: &name, Enabled: &enabled, Count: &count, Ratio: &ratio, Level: &level}
+
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+
+	for _, dec := range patchDecoders {
+		t.Run(dec.name, func(t *testing.T) {
+			var got Patch
+			rest, err := dec.decode(&got, b)
+			if err != nil {
+				t.Fatalf("decode error: %v", err)
+			}
+			if len(rest) != 0 {
+				t.Fatalf("unexpected trailing bytes: %d", len(rest))
+			}
+			if got.Name == nil || *got.Name != name {
+				t.Fatalf("Name = %v, want %q", got.Name, name)
+			}
+			if got.Enabled == nil || *got.Enabled != enabled {
+				t.Fatalf("Enabled = %v, want %v", got.Enabled, enabled)
+			}
+			if got.Count == nil || *got.Count != count {
+				t.Fatalf("Count = %v, want %d", got.Count, count)
+			}
+			if got.Ratio == nil || *got.Ratio != ratio {
+				t.Fatalf("Ratio = %v, want %v", got.Ratio, ratio)
+			}
+			if got.Level == nil || *got.Level != level {
+				t.Fatalf("Level = %v, want %d", got.Level, level)
+			}
+		})
+	}
+}
+
+func TestPatchDecodeNullClearsPointer(t *testing.T) {
+	b := cbor.AppendMapHeader(nil, 1)
+	b = cbor.AppendString(b, "enabled")
+	b = cbor.AppendNil(b)
+
+	for _, dec := range patchDecoders {
+		t.Run(dec.name, func(t *testing.T) {
+			on := true
+			got := Patch{Enabled: &on}
+			if _, err := dec.decode(&got, b); err != nil {
+				t.Fatalf("decode error: %v", err)
+			}
+			if got.Enabled != nil {
+				t.Fatalf("Enabled = %v, want nil", *got.Enabled)
+			}
+		})
+	}
+}
@@ -9,3 +9,10 @@ type Person struct {
 	Data []byte `cbor:"data"`
 }
 
+
+
+// NewFixturePerson returns the value used by the generated --bench
+// benchmarks for Person.
+func NewFixturePerson() *Person {
+	return &Person{Name: "Ada Lovelace", Age: 36, Data: []byte("analytical engine")}
+}
@@ -2,14 +2,39 @@
 
 package structs
 
-import cbor "github.com/delaneyj/cbor/runtime"
+import (
+	"io"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+// Fail the build if a generated method drifts from the runtime interfaces.
+var (
+	_ cbor.Marshaler   = (*Person)(nil)
+	_ cbor.Unmarshaler = (*Person)(nil)
+)
 
 func (x Person) Msgsize() (s int) {
 	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("name") + cbor.StringPrefixSize + len(x.Name) + cbor.StringPrefixSize + len("age") + cbor.IntSize + cbor.StringPrefixSize + len("data") + cbor.BytesPrefixSize + len(x.Data)
 	return
 }
 
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
 func (x *Person) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *Person) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *Person) AppendCBOR(b []byte) ([]byte, error) {
 	if x == nil {
 		return cbor.AppendNil(b), nil
 	}
@@ -37,65 +62,130 @@ func (x *Person) MarshalCBOR(b []byte) ([]byte, error) {
 		}
 	}
 	b = cbor.AppendString(b, "data")
-	b, err = cbor.AppendInterface(b, x.Data)
-	if err != nil {
-		return b, err
+	if x.Data == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = cbor.AppendNil(b)
+	} else {
+		b, err = cbor.AppendBytes(b, x.Data), nil
+		if err != nil {
+			return b, err
+		}
 	}
 
 	return b, nil
 }
 
-// DecodeSafe decodes using validated, allocating string handling.
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
 func (x *Person) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *Person) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
 	if x == nil {
 		return b, cbor.ErrNotNil
 	}
 	sz, rest, err := cbor.ReadMapHeaderBytes(b)
 	if err != nil {
-		return b, err
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
 	}
+	var seen [1]uint64
 	for i := uint32(0); i < sz; i++ {
-		key, v, err := cbor.ReadStringBytes(rest)
+		key, v, err := in.ReadStringBytes(rest)
 		if err != nil {
-			return b, err
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
 		}
 		switch key {
 		case "name":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
+				}
+			}
 
 			var tmp string
-			tmp, v, err = cbor.ReadStringBytes(v)
+			tmp, v, err = in.ReadStringBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
 			}
 			x.Name = tmp
 		case "age":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "age", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "age", len(b)-len(v))
+				}
+			}
 
 			var tmp int
 			tmp, v, err = cbor.ReadIntBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "age", len(b)-len(v))
 			}
 			x.Age = tmp
 		case "data":
-
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 2); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "data", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "data", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Data = cbor.NullSlice(x.Data)
+				break
+			}
 			var tmp []byte
 			tmp, v, err = cbor.ReadBytesBytes(v, nil)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "data", len(b)-len(v))
 			}
 			x.Data = tmp
 		default:
 			v, err = cbor.Skip(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
 			}
 		}
 		rest = v
 	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
 	return rest, nil
 }
 
-// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
 func (x *Person) DecodeTrusted(b []byte) ([]byte, error) {
 	if x == nil {
 		return b, cbor.ErrNotNil
@@ -104,6 +194,9 @@ func (x *Person) DecodeTrusted(b []byte) ([]byte, error) {
 	if err != nil {
 		return b, err
 	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
 	for i := uint32(0); i < sz; i++ {
 		keyBytes, v, err := cbor.ReadStringZC(rest)
 		if err != nil {
@@ -112,14 +205,22 @@ func (x *Person) DecodeTrusted(b []byte) ([]byte, error) {
 		key := cbor.UnsafeString(keyBytes)
 		switch key {
 		case "name":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
-			var tmpBytes []byte
-			tmpBytes, v, err = cbor.ReadStringZC(v)
+			x.Name, v, err = cbor.ReadTrustedStringBytes(v)
 			if err != nil {
 				return b, err
 			}
-			x.Name = cbor.UnsafeString(tmpBytes)
 		case "age":
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
 			var tmp int
 			tmp, v, err = cbor.ReadIntBytes(v)
@@ -128,7 +229,16 @@ func (x *Person) DecodeTrusted(b []byte) ([]byte, error) {
 			}
 			x.Age = tmp
 		case "data":
-
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Data = cbor.NullSlice(x.Data)
+				break
+			}
 			var tmp []byte
 			tmp, v, err = cbor.ReadBytesBytes(v, nil)
 			if err != nil {
@@ -146,6 +256,15 @@ func (x *Person) DecodeTrusted(b []byte) ([]byte, error) {
 	return rest, nil
 }
 
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *Person) resetCBOR() {
+	var zero Person
+	x.Name = zero.Name
+	x.Age = zero.Age
+	x.Data = x.Data[:0]
+}
+
 // UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
 func (x *Person) UnmarshalCBOR(b []byte) ([]byte, error) {
 	return x.DecodeSafe(b)
@@ -0,0 +1,56 @@
+// Code generated by cborgen DO NOT EDIT.
+
+package structs
+
+import "testing"
+
+func BenchmarkMarshalPerson(b *testing.B) {
+	v := NewFixturePerson()
+	buf, err := v.MarshalCBOR(nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(int64(len(buf)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf, err = v.MarshalCBOR(buf[:0])
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeSafePerson(b *testing.B) {
+	v := NewFixturePerson()
+	buf, err := v.MarshalCBOR(nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(int64(len(buf)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var dst Person
+		if _, err := dst.DecodeSafe(buf); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeTrustedPerson(b *testing.B) {
+	v := NewFixturePerson()
+	buf, err := v.MarshalCBOR(nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(int64(len(buf)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var dst Person
+		if _, err := dst.DecodeTrusted(buf); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
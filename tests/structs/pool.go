@@ -0,0 +1,16 @@
+package structs
+
+// Snapshot and Consumer exercise decoding into a pooled value: slices of
+// structs keep their backing arrays, and their elements are reset and
+// decoded in place rather than allocated afresh.
+type Snapshot struct {
+	Consumers []Consumer  `cbor:"consumers"`
+	Leaders   []*Consumer `cbor:"leaders,omitempty"`
+}
+
+// Consumer is an element of Snapshot's slices.
+type Consumer struct {
+	Name    string   `cbor:"name"`
+	Pending uint64   `cbor:"pending,omitempty"`
+	Tags    []string `cbor:"tags,omitempty"`
+}
@@ -0,0 +1,647 @@
+// Code generated by cborgen DO NOT EDIT.
+
+package structs
+
+import (
+	"io"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+// Fail the build if a generated method drifts from the runtime interfaces.
+var (
+	_ cbor.Marshaler   = (*Snapshot)(nil)
+	_ cbor.Unmarshaler = (*Snapshot)(nil)
+	_ cbor.Marshaler   = (*Consumer)(nil)
+	_ cbor.Unmarshaler = (*Consumer)(nil)
+)
+
+func (x Snapshot) Msgsize() (s int) {
+	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("consumers") + cbor.ArrayHeaderSize + len(x.Consumers)*0
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *Snapshot) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *Snapshot) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *Snapshot) AppendCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	count := uint32(0)
+	count++
+	if !(len(x.Leaders) == 0) {
+		count++
+	}
+	b = cbor.AppendMapHeader(b, count)
+	var err error
+	if x.Consumers == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = cbor.AppendString(b, "consumers")
+		b = cbor.AppendNil(b)
+	} else {
+		b = cbor.AppendString(b, "consumers")
+		b = cbor.AppendArrayHeader(b, uint32(len(x.Consumers)))
+		for i := range x.Consumers {
+			b, err = x.Consumers[i].AppendCBOR(b)
+			if err != nil {
+				return b, err
+			}
+		}
+	}
+	if !(len(x.Leaders) == 0) {
+		if x.Leaders == nil && cbor.NilContainers == cbor.NilAsNull {
+			b = cbor.AppendString(b, "leaders")
+			b = cbor.AppendNil(b)
+		} else {
+			b = cbor.AppendString(b, "leaders")
+			b = cbor.AppendArrayHeader(b, uint32(len(x.Leaders)))
+			for _, c := range x.Leaders {
+				if c == nil {
+					b = cbor.AppendNil(b)
+				} else {
+					b, err = c.AppendCBOR(b)
+					if err != nil {
+						return b, err
+					}
+				}
+			}
+		}
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *Snapshot) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *Snapshot) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := in.ReadStringBytes(rest)
+		if err != nil {
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+		}
+		switch key {
+		case "consumers":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "consumers", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "consumers", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Consumers = cbor.NullSlice(x.Consumers)
+				break
+			}
+			var sz uint32
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "consumers", len(b)-len(v))
+			}
+			if cap(x.Consumers) >= int(sz) {
+				x.Consumers = x.Consumers[:sz]
+			} else {
+				x.Consumers = make([]Consumer, sz)
+			}
+			if sz > 0 {
+				_ = x.Consumers[sz-1]
+			}
+			for iConsumers := uint32(0); iConsumers < sz; iConsumers++ {
+				x.Consumers[iConsumers].resetCBOR()
+				v, err = x.Consumers[iConsumers].DecodeInterned(v, in)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, int(iConsumers)), "consumers", len(b)-len(v))
+				}
+			}
+			if indef {
+				v = v[1:] // break
+			}
+		case "leaders":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "leaders", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "leaders", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Leaders = cbor.NullSlice(x.Leaders)
+				break
+			}
+			var sz uint32
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "leaders", len(b)-len(v))
+			}
+			if cap(x.Leaders) >= int(sz) {
+				x.Leaders = x.Leaders[:sz]
+			} else {
+				x.Leaders = make([]*Consumer, sz)
+			}
+			if sz > 0 {
+				_ = x.Leaders[sz-1]
+			}
+			for iLeaders := uint32(0); iLeaders < sz; iLeaders++ {
+				if cbor.IsNilOrUndefined(v) {
+					v = v[1:]
+					x.Leaders[iLeaders] = nil
+					continue
+				}
+				if x.Leaders[iLeaders] == nil {
+					x.Leaders[iLeaders] = new(Consumer)
+				} else {
+					x.Leaders[iLeaders].resetCBOR()
+				}
+				v, err = x.Leaders[iLeaders].DecodeInterned(v, in)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, int(iLeaders)), "leaders", len(b)-len(v))
+				}
+			}
+			if indef {
+				v = v[1:] // break
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *Snapshot) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "consumers":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Consumers = cbor.NullSlice(x.Consumers)
+				break
+			}
+			var sz uint32
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap(x.Consumers) >= int(sz) {
+				x.Consumers = x.Consumers[:sz]
+			} else {
+				x.Consumers = make([]Consumer, sz)
+			}
+			if sz > 0 {
+				_ = x.Consumers[sz-1]
+			}
+			for iConsumers := uint32(0); iConsumers < sz; iConsumers++ {
+				x.Consumers[iConsumers].resetCBOR()
+				v, err = x.Consumers[iConsumers].DecodeTrusted(v)
+				if err != nil {
+					return b, err
+				}
+			}
+			if indef {
+				v = v[1:] // break
+			}
+		case "leaders":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Leaders = cbor.NullSlice(x.Leaders)
+				break
+			}
+			var sz uint32
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap(x.Leaders) >= int(sz) {
+				x.Leaders = x.Leaders[:sz]
+			} else {
+				x.Leaders = make([]*Consumer, sz)
+			}
+			if sz > 0 {
+				_ = x.Leaders[sz-1]
+			}
+			for iLeaders := uint32(0); iLeaders < sz; iLeaders++ {
+				if cbor.IsNilOrUndefined(v) {
+					v = v[1:]
+					x.Leaders[iLeaders] = nil
+					continue
+				}
+				if x.Leaders[iLeaders] == nil {
+					x.Leaders[iLeaders] = new(Consumer)
+				} else {
+					x.Leaders[iLeaders].resetCBOR()
+				}
+				v, err = x.Leaders[iLeaders].DecodeTrusted(v)
+				if err != nil {
+					return b, err
+				}
+			}
+			if indef {
+				v = v[1:] // break
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *Snapshot) resetCBOR() {
+	x.Consumers = x.Consumers[:0]
+	x.Leaders = x.Leaders[:0]
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Snapshot) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x Consumer) Msgsize() (s int) {
+	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("name") + cbor.StringPrefixSize + len(x.Name) + cbor.StringPrefixSize + len("pending") + cbor.Uint64Size + cbor.StringPrefixSize + len("tags") + cbor.ArrayHeaderSize + len(x.Tags)*cbor.StringPrefixSize
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *Consumer) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *Consumer) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *Consumer) AppendCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	count := uint32(0)
+	count++
+	if !(x.Pending == 0) {
+		count++
+	}
+	if !(len(x.Tags) == 0) {
+		count++
+	}
+	b = cbor.AppendMapHeader(b, count)
+	var err error
+	b = cbor.AppendString(b, "name")
+	b, err = cbor.AppendString(b, x.Name), nil
+	if err != nil {
+		return b, err
+	}
+	if !(x.Pending == 0) {
+		b = cbor.AppendString(b, "pending")
+		b, err = cbor.AppendUint64(b, x.Pending), nil
+		if err != nil {
+			return b, err
+		}
+	}
+	if !(len(x.Tags) == 0) {
+		if x.Tags == nil && cbor.NilContainers == cbor.NilAsNull {
+			b = cbor.AppendString(b, "tags")
+			b = cbor.AppendNil(b)
+		} else {
+			b = cbor.AppendString(b, "tags")
+			b = cbor.AppendArrayHeader(b, uint32(len(x.Tags)))
+			for _, v := range x.Tags {
+				b = cbor.AppendString(b, v)
+			}
+		}
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *Consumer) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *Consumer) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := in.ReadStringBytes(rest)
+		if err != nil {
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+		}
+		switch key {
+		case "name":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
+				}
+			}
+
+			var tmp string
+			tmp, v, err = in.ReadStringBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
+			}
+			x.Name = tmp
+		case "pending":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "pending", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "pending", len(b)-len(v))
+				}
+			}
+
+			var tmp uint64
+			tmp, v, err = cbor.ReadUint64Bytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "pending", len(b)-len(v))
+			}
+			x.Pending = tmp
+		case "tags":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 2); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "tags", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "tags", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Tags = cbor.NullSlice(x.Tags)
+				break
+			}
+			var sz uint32
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "tags", len(b)-len(v))
+			}
+			if cap(x.Tags) >= int(sz) {
+				x.Tags = x.Tags[:sz]
+			} else {
+				x.Tags = make([]string, sz)
+			}
+			if sz > 0 {
+				_ = x.Tags[sz-1]
+			}
+			for iTags := uint32(0); iTags < sz; iTags++ {
+				var tmp string
+				tmp, v, err = in.ReadStringBytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, int(iTags)), "tags", len(b)-len(v))
+				}
+				x.Tags[iTags] = tmp
+			}
+			if indef {
+				v = v[1:] // break
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *Consumer) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "name":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			x.Name, v, err = cbor.ReadTrustedStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+		case "pending":
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp uint64
+			tmp, v, err = cbor.ReadUint64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Pending = tmp
+		case "tags":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Tags = cbor.NullSlice(x.Tags)
+				break
+			}
+			var sz uint32
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap(x.Tags) >= int(sz) {
+				x.Tags = x.Tags[:sz]
+			} else {
+				x.Tags = make([]string, sz)
+			}
+			if sz > 0 {
+				_ = x.Tags[sz-1]
+			}
+			for iTags := uint32(0); iTags < sz; iTags++ {
+				var tmp string
+				tmp, v, err = cbor.ReadTrustedStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Tags[iTags] = tmp
+			}
+			if indef {
+				v = v[1:] // break
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *Consumer) resetCBOR() {
+	var zero Consumer
+	x.Name = zero.Name
+	x.Pending = zero.Pending
+	x.Tags = x.Tags[:0]
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Consumer) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
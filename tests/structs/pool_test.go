@@ -0,0 +1,79 @@
+package structs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSnapshotDecodeReusesStorage(t *testing.T) {
+	orig := &Snapshot{
+		Consumers: []Consumer{
+			{Name: "a", Pending: 3, Tags: []string{"x", "y"}},
+			{Name: "b"},
+		},
+		Leaders: []*Consumer{{Name: "a", Tags: []string{"z"}}},
+	}
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+
+	for _, decode := range []func(*Snapshot, []byte) ([]byte, error){
+		(*Snapshot).DecodeSafe,
+		(*Snapshot).DecodeTrusted,
+	} {
+		// A destination left over from an earlier, larger decode: stale
+		// fields of reused elements must not leak into the result.
+		dst := &Snapshot{
+			Consumers: []Consumer{
+				{Name: "old", Tags: make([]string, 0, 4)},
+				{Name: "old", Pending: 99, Tags: []string{"stale"}},
+				{Name: "old"},
+			},
+			Leaders: []*Consumer{{Name: "old", Pending: 7, Tags: make([]string, 0, 4)}},
+		}
+		consumers, leader := &dst.Consumers[0], dst.Leaders[0]
+		if _, err := decode(dst, b); err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+		if &dst.Consumers[0] != consumers || dst.Leaders[0] != leader {
+			t.Fatalf("decode reallocated storage it could reuse")
+		}
+		// Emptied tags keep their storage, so compare by length.
+		if len(dst.Consumers[1].Tags) != 0 {
+			t.Fatalf("stale tags survived: %v", dst.Consumers[1].Tags)
+		}
+		dst.Consumers[1].Tags = nil
+		if !reflect.DeepEqual(dst, orig) {
+			t.Fatalf("decoded %+v, want %+v", dst, orig)
+		}
+	}
+}
+
+func TestSnapshotDecodeTrustedNoAllocs(t *testing.T) {
+	orig := &Snapshot{
+		Consumers: []Consumer{
+			{Name: "a", Pending: 3, Tags: []string{"x", "y"}},
+			{Name: "b", Tags: []string{"z"}},
+		},
+		Leaders: []*Consumer{{Name: "a", Tags: []string{"x"}}},
+	}
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	// Decoding once sizes every slice; decoding again into the same
+	// value must reuse all of it.
+	var dst Snapshot
+	if _, err := dst.DecodeTrusted(b); err != nil {
+		t.Fatalf("DecodeTrusted error: %v", err)
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		if _, err := dst.DecodeTrusted(b); err != nil {
+			t.Fatalf("DecodeTrusted error: %v", err)
+		}
+	})
+	if allocs != 0 {
+		t.Fatalf("DecodeTrusted into a pre-sized Snapshot allocated %v times, want 0", allocs)
+	}
+}
@@ -0,0 +1,25 @@
+package structs
+
+// Observation is a sparse record written with a presence bitmap: only
+// the fields that are set follow it, in order.
+type Observation struct {
+	_        struct{}          `cbor:",toarray,presence"`
+	Station  string            `cbor:"station"`
+	Temp     float64           `cbor:"temp,omitempty"`
+	Humidity float64           `cbor:"humidity,omitempty"`
+	Pressure float64           `cbor:"pressure,omitempty"`
+	WindDir  uint16            `cbor:"wind_dir,omitempty"`
+	WindKph  float32           `cbor:"wind_kph,omitempty"`
+	Rain     float32           `cbor:"rain,omitempty"`
+	Snow     float32           `cbor:"snow,omitempty"`
+	Flags    []string          `cbor:"flags,omitempty"`
+	Notes    string            `cbor:"notes,omitzero"`
+	Extra    map[string]string `cbor:"extra,omitempty"`
+}
+
+// DensePresence combines dense key order with a presence bitmap.
+type DensePresence struct {
+	_     struct{} `cbor:",dense,presence"`
+	Label string   `cbor:"1,keyasint,omitempty"`
+	ID    uint64   `cbor:"0,keyasint"`
+}
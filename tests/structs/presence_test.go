@@ -0,0 +1,156 @@
+package structs
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+var observationDecoders = []struct {
+	name   string
+	decode func(dst *Observation, b []byte) ([]byte, error)
+}{
+	{"DecodeSafe", (*Observation).DecodeSafe},
+	{"DecodeTrusted", (*Observation).DecodeTrusted},
+}
+
+func TestObservationPresenceRoundTrip(t *testing.T) {
+	cases := []Observation{
+		{Station: "empty"},
+		{Station: "temp", Temp: 21.5},
+		{Station: "late", Flags: []string{"gust"}, Extra: map[string]string{"k": "v"}},
+		{
+			Station: "full", Temp: -3.25, Humidity: 80, Pressure: 1013.2,
+			WindDir: 270, WindKph: 12.5, Rain: 0.5, Snow: 2,
+			Flags: []string{"a", "b"}, Notes: "n", Extra: map[string]string{"x": "y"},
+		},
+	}
+	for _, in := range cases {
+		b, err := in.MarshalCBOR(nil)
+		if err != nil {
+			t.Fatalf("%s: MarshalCBOR error: %v", in.Station, err)
+		}
+		for _, d := range observationDecoders {
+			var out Observation
+			rest, err := d.decode(&out, b)
+			if err != nil || len(rest) != 0 {
+				t.Fatalf("%s %s: err=%v rest=%d", in.Station, d.name, err, len(rest))
+			}
+			if !reflect.DeepEqual(out, in) {
+				t.Fatalf("%s %s: got %+v, want %+v", in.Station, d.name, out, in)
+			}
+		}
+	}
+}
+
+func TestObservationPresenceEncoding(t *testing.T) {
+	in := Observation{Station: "s", Temp: 1.5, Flags: []string{"f"}}
+	b, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// [h'0301', "s", 1.5, ["f"]]: bits 0 and 1 of byte 0, bit 0 of byte 1.
+	want := []byte{0x84, 0x42, 0x03, 0x01, 0x61, 's'}
+	want = cbor.AppendFloat64(want, 1.5)
+	want = append(want, 0x81, 0x61, 'f')
+	if !bytes.Equal(b, want) {
+		t.Fatalf("encoding = % x\nwant       % x", b, want)
+	}
+	if len(b) > in.Msgsize() {
+		t.Fatalf("encoded %d bytes, Msgsize %d", len(b), in.Msgsize())
+	}
+
+	// Dense structs order the bitmap by key.
+	d, err := (&DensePresence{ID: 7}).MarshalCBOR(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []byte{0x82, 0x41, 0x01, 0x07}; !bytes.Equal(d, want) {
+		t.Fatalf("DensePresence = % x, want % x", d, want)
+	}
+}
+
+func TestObservationPresenceDecode(t *testing.T) {
+	// Absent fields are left as they are.
+	b := []byte{0x82, 0x41, 0x02, 0xf9, 0x3e, 0x00} // [h'02', 1.5]
+	for _, d := range observationDecoders {
+		out := Observation{Station: "kept", Rain: 3}
+		if _, err := d.decode(&out, b); err != nil {
+			t.Fatalf("%s: %v", d.name, err)
+		}
+		if out.Station != "kept" || out.Temp != 1.5 || out.Rain != 3 {
+			t.Fatalf("%s: got %+v", d.name, out)
+		}
+	}
+
+	// Indefinite-length arrays and bitmaps shorter than the struct.
+	b = []byte{0x9f, 0x41, 0x01, 0x61, 'i', 0xff}
+	for _, d := range observationDecoders {
+		var out Observation
+		rest, err := d.decode(&out, b)
+		if err != nil || len(rest) != 0 || out.Station != "i" {
+			t.Fatalf("%s: got %+v, rest=%d, err=%v", d.name, out, len(rest), err)
+		}
+	}
+
+	bad := map[string][]byte{
+		"no bitmap":   {0x80},
+		"not bytes":   {0x81, 0x01},
+		"too few":     {0x82, 0x41, 0x03, 0x61, 's'},
+		"too many":    {0x83, 0x41, 0x01, 0x61, 's', 0x00},
+		"unknown bit": {0x83, 0x42, 0x01, 0x08, 0x61, 's', 0x00},
+	}
+	for name, b := range bad {
+		for _, d := range observationDecoders {
+			var out Observation
+			if _, err := d.decode(&out, b); err == nil {
+				t.Fatalf("%s %s: decoded % x without error", name, d.name, b)
+			}
+		}
+	}
+	var ae cbor.ArrayError
+	var out Observation
+	if _, err := out.DecodeSafe(bad["too few"]); !errors.As(err, &ae) || ae.Wanted != 3 || ae.Got != 2 {
+		t.Fatalf("too few: error = %v, want ArrayError{3, 2}", err)
+	}
+}
+
+func TestObservationPresenceNewerSchema(t *testing.T) {
+	// Bit 11 is a field this struct does not have.
+	b := []byte{0x83, 0x42, 0x01, 0x08, 0x61, 's', 0x18, 0x2a}
+	cbor.TolerateExtraArrayElements = true
+	defer func() { cbor.TolerateExtraArrayElements = false }()
+	for _, d := range observationDecoders {
+		var out Observation
+		rest, err := d.decode(&out, b)
+		if err != nil || len(rest) != 0 || out.Station != "s" {
+			t.Fatalf("%s: got %+v, rest=%d, err=%v", d.name, out, len(rest), err)
+		}
+	}
+}
+
+func TestObservationPresenceReflectMatchesGenerated(t *testing.T) {
+	type plain Observation // no generated methods
+	in := Observation{Station: "r", Pressure: 990, Snow: 1, Notes: "x"}
+	want, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := cbor.Marshal(plain(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("Marshal = % x\nwant      % x", got, want)
+	}
+	var out plain
+	if err := cbor.Unmarshal(want, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(Observation(out), in) {
+		t.Fatalf("Unmarshal = %+v, want %+v", out, in)
+	}
+}
@@ -0,0 +1,9 @@
+package structs
+
+// TreeNode is a self-referential type used to exercise code generation
+// for recursive structs, including cycle detection on encode.
+type TreeNode struct {
+	Value    string      `cbor:"value"`
+	Next     *TreeNode   `cbor:"next,omitempty"`
+	Children []*TreeNode `cbor:"children,omitempty"`
+}
@@ -0,0 +1,352 @@
+// Code generated by cborgen DO NOT EDIT.
+
+package structs
+
+import (
+	"io"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+// Fail the build if a generated method drifts from the runtime interfaces.
+var (
+	_ cbor.Marshaler   = (*TreeNode)(nil)
+	_ cbor.Unmarshaler = (*TreeNode)(nil)
+)
+
+func (x TreeNode) Msgsize() (s int) {
+	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("value") + cbor.StringPrefixSize + len(x.Value)
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *TreeNode) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *TreeNode) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *TreeNode) AppendCBOR(b []byte) ([]byte, error) {
+	return x.marshalCBORDepth(b, 0)
+}
+
+// marshalCBORDepth encodes x at the given nesting depth, failing with
+// ErrCycleDetected once depth exceeds MaxEncodeDepth.
+func (x *TreeNode) marshalCBORDepth(b []byte, depth int) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+	if depth > cbor.MaxEncodeDepth {
+		return b, cbor.ErrCycleDetected
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	count := uint32(0)
+	count++
+	if !(x.Next == nil) {
+		count++
+	}
+	if !(len(x.Children) == 0) {
+		count++
+	}
+	b = cbor.AppendMapHeader(b, count)
+	var err error
+	b = cbor.AppendString(b, "value")
+	b, err = cbor.AppendString(b, x.Value), nil
+	if err != nil {
+		return b, err
+	}
+	if !(x.Next == nil) {
+		b = cbor.AppendString(b, "next")
+		b, err = x.Next.marshalCBORDepth(b, depth+1)
+		if err != nil {
+			return b, err
+		}
+	}
+	if !(len(x.Children) == 0) {
+		if x.Children == nil && cbor.NilContainers == cbor.NilAsNull {
+			b = cbor.AppendString(b, "children")
+			b = cbor.AppendNil(b)
+		} else {
+			b = cbor.AppendString(b, "children")
+			b = cbor.AppendArrayHeader(b, uint32(len(x.Children)))
+			for _, t := range x.Children {
+				if t == nil {
+					b = cbor.AppendNil(b)
+				} else {
+					b, err = t.marshalCBORDepth(b, depth+1)
+					if err != nil {
+						return b, err
+					}
+				}
+			}
+		}
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *TreeNode) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *TreeNode) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := in.ReadStringBytes(rest)
+		if err != nil {
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+		}
+		switch key {
+		case "value":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "value", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "value", len(b)-len(v))
+				}
+			}
+
+			var tmp string
+			tmp, v, err = in.ReadStringBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "value", len(b)-len(v))
+			}
+			x.Value = tmp
+		case "next":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "next", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Next = nil
+				break
+			}
+			if x.Next == nil {
+				x.Next = new(TreeNode)
+			} else {
+				x.Next.resetCBOR()
+			}
+			v, err = x.Next.DecodeInterned(v, in)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "next", len(b)-len(v))
+			}
+		case "children":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 2); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "children", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "children", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Children = cbor.NullSlice(x.Children)
+				break
+			}
+			var sz uint32
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "children", len(b)-len(v))
+			}
+			if cap(x.Children) >= int(sz) {
+				x.Children = x.Children[:sz]
+			} else {
+				x.Children = make([]*TreeNode, sz)
+			}
+			if sz > 0 {
+				_ = x.Children[sz-1]
+			}
+			for iChildren := uint32(0); iChildren < sz; iChildren++ {
+				if cbor.IsNilOrUndefined(v) {
+					v = v[1:]
+					x.Children[iChildren] = nil
+					continue
+				}
+				if x.Children[iChildren] == nil {
+					x.Children[iChildren] = new(TreeNode)
+				} else {
+					x.Children[iChildren].resetCBOR()
+				}
+				v, err = x.Children[iChildren].DecodeInterned(v, in)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, int(iChildren)), "children", len(b)-len(v))
+				}
+			}
+			if indef {
+				v = v[1:] // break
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *TreeNode) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "value":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			x.Value, v, err = cbor.ReadTrustedStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+		case "next":
+
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Next = nil
+				break
+			}
+			if x.Next == nil {
+				x.Next = new(TreeNode)
+			} else {
+				x.Next.resetCBOR()
+			}
+			v, err = x.Next.DecodeTrusted(v)
+			if err != nil {
+				return b, err
+			}
+		case "children":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Children = cbor.NullSlice(x.Children)
+				break
+			}
+			var sz uint32
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap(x.Children) >= int(sz) {
+				x.Children = x.Children[:sz]
+			} else {
+				x.Children = make([]*TreeNode, sz)
+			}
+			if sz > 0 {
+				_ = x.Children[sz-1]
+			}
+			for iChildren := uint32(0); iChildren < sz; iChildren++ {
+				if cbor.IsNilOrUndefined(v) {
+					v = v[1:]
+					x.Children[iChildren] = nil
+					continue
+				}
+				if x.Children[iChildren] == nil {
+					x.Children[iChildren] = new(TreeNode)
+				} else {
+					x.Children[iChildren].resetCBOR()
+				}
+				v, err = x.Children[iChildren].DecodeTrusted(v)
+				if err != nil {
+					return b, err
+				}
+			}
+			if indef {
+				v = v[1:] // break
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *TreeNode) resetCBOR() {
+	var zero TreeNode
+	x.Value = zero.Value
+	x.Next = zero.Next
+	x.Children = x.Children[:0]
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *TreeNode) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
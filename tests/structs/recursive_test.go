@@ -0,0 +1,103 @@
+package structs
+
+import (
+	"errors"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+type treeNodeDecoder struct {
+	name   string
+	decode func(dst *TreeNode, b []byte) ([]byte, error)
+}
+
+var treeNodeDecoders = []treeNodeDecoder{
+	{
+		name:   "DecodeSafe",
+		decode: (*TreeNode).DecodeSafe,
+	},
+	{
+		name:   "DecodeTrusted",
+		decode: (*TreeNode).DecodeTrusted,
+	},
+}
+
+func TestTreeNodeRoundTrip(t *testing.T) {
+	orig := &TreeNode{
+		Value: "root",
+		Next:  &TreeNode{Value: "sibling", Next: &TreeNode{Value: "last"}},
+		Children: []*TreeNode{
+			{Value: "a"},
+			{Value: "b", Children: []*TreeNode{{Value: "b1"}}},
+		},
+	}
+
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+
+	for _, tc := range treeNodeDecoders {
+		t.Run(tc.name, func(t *testing.T) {
+			var dst TreeNode
+			rest, err := tc.decode(&dst, b)
+			if err != nil {
+				t.Fatalf("%s error: %v", tc.name, err)
+			}
+			if len(rest) != 0 {
+				t.Fatalf("%s leftover bytes: %d", tc.name, len(rest))
+			}
+			if dst.Value != "root" || dst.Next == nil || dst.Next.Value != "sibling" {
+				t.Fatalf("%s next mismatch: %+v", tc.name, dst)
+			}
+			if dst.Next.Next == nil || dst.Next.Next.Value != "last" || dst.Next.Next.Next != nil {
+				t.Fatalf("%s tail mismatch: %+v", tc.name, dst.Next.Next)
+			}
+			if len(dst.Children) != 2 || dst.Children[1].Value != "b" || len(dst.Children[1].Children) != 1 || dst.Children[1].Children[0].Value != "b1" {
+				t.Fatalf("%s children mismatch: %+v", tc.name, dst.Children)
+			}
+		})
+	}
+}
+
+func TestTreeNodeNullPointerDecodes(t *testing.T) {
+	// {"value": "x", "next": null}
+	b := cbor.AppendMapHeader(nil, 2)
+	b = cbor.AppendString(b, "value")
+	b = cbor.AppendString(b, "x")
+	b = cbor.AppendString(b, "next")
+	b = cbor.AppendNil(b)
+
+	for _, tc := range treeNodeDecoders {
+		t.Run(tc.name, func(t *testing.T) {
+			dst := TreeNode{Next: &TreeNode{Value: "stale"}}
+			if _, err := tc.decode(&dst, b); err != nil {
+				t.Fatalf("%s error: %v", tc.name, err)
+			}
+			if dst.Next != nil {
+				t.Fatalf("%s expected nil Next, got %+v", tc.name, dst.Next)
+			}
+		})
+	}
+}
+
+func TestTreeNodeCycleDetected(t *testing.T) {
+	a := &TreeNode{Value: "a"}
+	b := &TreeNode{Value: "b", Next: a}
+	a.Next = b
+
+	if _, err := a.MarshalCBOR(nil); !errors.Is(err, cbor.ErrCycleDetected) {
+		t.Fatalf("expected ErrCycleDetected via Next, got %v", err)
+	}
+
+	self := &TreeNode{Value: "self"}
+	self.Children = []*TreeNode{self}
+	_, err := self.MarshalCBOR(nil)
+	if !errors.Is(err, cbor.ErrCycleDetected) {
+		t.Fatalf("expected ErrCycleDetected via Children, got %v", err)
+	}
+	if err.Error() != "cbor: cycle detected" {
+		t.Fatalf("unexpected error text: %q", err.Error())
+	}
+}
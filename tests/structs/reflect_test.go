@@ -0,0 +1,398 @@
+package structs
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+// adhocPerson mirrors Person without generated methods, so Marshal and
+// Unmarshal handle it by reflection.
+type adhocPerson struct {
+	Name string `cbor:"name"`
+	Age  int    `cbor:"age,omitempty"`
+	Data []byte `cbor:"data"`
+}
+
+type adhocEvent struct {
+	ID      uint64            `cbor:"1,keyasint"`
+	At      time.Time         `cbor:"at"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	Owner   *Person           `cbor:"owner"`
+	Members []Person          `cbor:"members"`
+	Note    string            `cbor:"-"`
+	secret  string
+}
+
+type adhocPoint struct {
+	_    struct{} `cbor:",toarray"`
+	X, Y int
+	Tags []string `cbor:"tags,omitempty"`
+}
+
+func TestReflectMatchesGenerated(t *testing.T) {
+	p := &Person{Name: "Ada", Data: []byte{1, 2}}
+	want, err := p.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+
+	got, err := cbor.Marshal(adhocPerson{Name: "Ada", Data: []byte{1, 2}})
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("reflection encoded %x, generated %x", got, want)
+	}
+
+	// Generated types use their own methods, by value or by pointer.
+	for _, v := range []any{p, *p} {
+		got, err := cbor.Marshal(v)
+		if err != nil {
+			t.Fatalf("Marshal(%T) error: %v", v, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Fatalf("Marshal(%T) encoded %x, want %x", v, got, want)
+		}
+	}
+
+	var adhoc adhocPerson
+	if err := cbor.Unmarshal(want, &adhoc); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if adhoc.Name != "Ada" || adhoc.Age != 0 || !bytes.Equal(adhoc.Data, p.Data) {
+		t.Fatalf("decoded %+v", adhoc)
+	}
+}
+
+func TestReflectRoundTrip(t *testing.T) {
+	orig := adhocEvent{
+		ID:      7,
+		At:      time.Unix(1700000000, 0).UTC(),
+		Owner:   &Person{Name: "Ada", Age: 36},
+		Members: []Person{{Name: "Bob"}},
+		Note:    "not encoded",
+		secret:  "not encoded",
+	}
+	b, err := cbor.Marshal(&orig)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+
+	var got adhocEvent
+	if err := cbor.Unmarshal(b, &got); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if got.ID != 7 || !got.At.Equal(orig.At) || got.Labels != nil {
+		t.Fatalf("decoded %+v", got)
+	}
+	if got.Note != "" || got.secret != "" {
+		t.Fatalf("ignored fields decoded: %+v", got)
+	}
+	if !reflect.DeepEqual(got.Owner, orig.Owner) || len(got.Members) != 1 || got.Members[0].Name != "Bob" {
+		t.Fatalf("nested generated values decoded as %+v, %+v", got.Owner, got.Members)
+	}
+
+	// Integer and text keys mix, so the generic form is a map[any]any.
+	var generic map[any]any
+	if err := cbor.Unmarshal(b, &generic); err != nil {
+		t.Fatalf("Unmarshal into map[any]any error: %v", err)
+	}
+	if _, ok := generic[uint64(1)]; !ok {
+		t.Fatalf("keyasint field missing from %v", generic)
+	}
+}
+
+func TestReflectToArray(t *testing.T) {
+	b, err := cbor.Marshal(adhocPoint{X: 1, Y: -2})
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	want := cbor.AppendArrayHeader(nil, 2)
+	want = cbor.AppendInt(want, 1)
+	want = cbor.AppendInt(want, -2)
+	if !bytes.Equal(b, want) {
+		t.Fatalf("encoded %x, want %x", b, want)
+	}
+
+	var got adhocPoint
+	if err := cbor.Unmarshal(b, &got); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if got.X != 1 || got.Y != -2 || got.Tags != nil {
+		t.Fatalf("decoded %+v", got)
+	}
+}
+
+func TestReflectUnmarshalErrors(t *testing.T) {
+	b, err := cbor.Marshal(adhocPerson{Name: "Ada"})
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+
+	var p adhocPerson
+	if err := cbor.Unmarshal(append(b, 0x00), &p); !errors.Is(err, cbor.ErrTrailingBytes) {
+		t.Fatalf("trailing bytes error = %v, want ErrTrailingBytes", err)
+	}
+	cbor.AllowTrailingBytes = true
+	err = cbor.Unmarshal(append(b, 0x00), &p)
+	cbor.AllowTrailingBytes = false
+	if err != nil || p.Name != "Ada" {
+		t.Fatalf("with AllowTrailingBytes: %+v, %v", p, err)
+	}
+	if err := cbor.Unmarshal(b, p); err == nil {
+		t.Fatalf("expected an error for a non-pointer target")
+	}
+
+	bad := cbor.AppendMapHeader(nil, 1)
+	bad = cbor.AppendString(bad, "age")
+	off := len(bad)
+	bad = cbor.AppendString(bad, "old")
+	err = cbor.Unmarshal(bad, &p)
+	var de *cbor.DecodeError
+	if !errors.As(err, &de) {
+		t.Fatalf("error %v is not a *DecodeError", err)
+	}
+	if de.Path != "age" || de.Offset != off {
+		t.Fatalf("error at %q offset %d, want age offset %d", de.Path, de.Offset, off)
+	}
+
+	var small struct {
+		N int8 `cbor:"n"`
+	}
+	big := cbor.AppendMapHeader(nil, 1)
+	big = cbor.AppendString(big, "n")
+	big = cbor.AppendInt(big, 300)
+	var overflow cbor.IntOverflow
+	if err := cbor.Unmarshal(big, &small); !errors.As(err, &overflow) {
+		t.Fatalf("overflow error = %v, want IntOverflow", err)
+	}
+}
+
+func TestReflectKeyCollisions(t *testing.T) {
+	type inner struct {
+		A int
+		B int
+	}
+	// Without the collision the inlined fields round-trip.
+	type spread struct {
+		In inner `cbor:",inline"`
+		C  int
+	}
+	in := spread{In: inner{A: 1, B: 2}, C: 3}
+	b, err := cbor.Marshal(in)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	var out spread
+	if err := cbor.Unmarshal(b, &out); err != nil || out != in {
+		t.Fatalf("Unmarshal = %+v, %v, want %+v", out, err, in)
+	}
+
+	type inlined struct {
+		In inner `cbor:",inline"`
+		A  int
+	}
+	type sameName struct {
+		X int `cbor:"x"`
+		Y int `cbor:"x"`
+	}
+	type sameInt struct {
+		X int `cbor:"1,keyasint"`
+		Y int `cbor:"1,keyasint"`
+	}
+	type aliased struct {
+		X int `cbor:"x"`
+		Y int `cbor:"y,alias=x"`
+	}
+	tests := []struct {
+		v    any
+		want string
+	}{
+		{&inlined{In: inner{A: 1, B: 2}, A: 3}, `fields In.A and A both use CBOR key "A"`},
+		{&sameName{}, `fields X and Y both use CBOR key "x"`},
+		{&sameInt{}, "fields X and Y both use CBOR key 1"},
+		{&aliased{}, `fields X and Y both use CBOR key "x"`},
+	}
+	for _, tc := range tests {
+		if _, err := cbor.Marshal(tc.v); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("Marshal(%T) error = %v, want %q", tc.v, err, tc.want)
+		}
+		if err := cbor.Unmarshal(cbor.AppendMapHeader(nil, 0), tc.v); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("Unmarshal(%T) error = %v, want %q", tc.v, err, tc.want)
+		}
+	}
+}
+
+func TestReflectEmbeddedShadowing(t *testing.T) {
+	type Inner struct {
+		A int
+		B int
+	}
+	// encoding/json would drop Inner.A in favour of A; the map written
+	// here would repeat "A", so the clash is an error instead.
+	type outer struct {
+		Inner
+		A int
+	}
+	const want = `fields Inner.A and A both use CBOR key "A"`
+	in := outer{Inner: Inner{A: 1, B: 2}, A: 3}
+	if _, err := cbor.Marshal(&in); err == nil || !strings.Contains(err.Error(), want) {
+		t.Fatalf("Marshal error = %v, want %q", err, want)
+	}
+	var out outer
+	if err := cbor.Unmarshal(cbor.AppendMapHeader(nil, 0), &out); err == nil || !strings.Contains(err.Error(), want) {
+		t.Fatalf("Unmarshal error = %v, want %q", err, want)
+	}
+
+	// An embedded struct with a key of its own does not clash.
+	type keyed struct {
+		Inner `cbor:"inner"`
+		A     int
+	}
+	k := keyed{Inner: Inner{A: 1, B: 2}, A: 3}
+	b, err := cbor.Marshal(&k)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	var kout keyed
+	if err := cbor.Unmarshal(b, &kout); err != nil || kout != k {
+		t.Fatalf("Unmarshal = %+v, %v, want %+v", kout, err, k)
+	}
+}
+
+func TestUnmarshalUsesGeneratedMethods(t *testing.T) {
+	in := Person{Name: "Ada", Age: 36, Data: []byte{1}}
+	b, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+
+	var out Person
+	if err := cbor.Unmarshal(b, &out); err != nil || !reflect.DeepEqual(out, in) {
+		t.Fatalf("Unmarshal = %+v, %v; want %+v", out, err, in)
+	}
+	// Past the interface assertion, Unmarshal costs what DecodeSafe does.
+	direct := testing.AllocsPerRun(100, func() { _, _ = out.DecodeSafe(b) })
+	viaAny := testing.AllocsPerRun(100, func() { _ = cbor.Unmarshal(b, &out) })
+	if viaAny != direct {
+		t.Fatalf("Unmarshal allocates %v times, DecodeSafe %v", viaAny, direct)
+	}
+
+	// Errors and trailing bytes are reported as for reflection.
+	if err := cbor.Unmarshal(append(b, 0x00), &out); !errors.Is(err, cbor.ErrTrailingBytes) {
+		t.Fatalf("trailing bytes error = %v, want ErrTrailingBytes", err)
+	}
+	var de *cbor.DecodeError
+	if err := cbor.Unmarshal(b[:len(b)-1], &out); !errors.As(err, &de) {
+		t.Fatalf("truncated input error = %v, want a *DecodeError", err)
+	}
+}
+
+func TestDecodeValue(t *testing.T) {
+	in := Person{Name: "Ada", Age: 36, Data: []byte{1}}
+	b, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+
+	// A container library holding only the element type fills a new
+	// slice element by element.
+	seq := append(append([]byte(nil), b...), b...)
+	elems := reflect.MakeSlice(reflect.TypeFor[[]Person](), 2, 2)
+	rest := seq
+	for i := range elems.Len() {
+		if rest, err = cbor.DecodeValue(rest, elems.Index(i)); err != nil {
+			t.Fatalf("DecodeValue %d error: %v", i, err)
+		}
+	}
+	if len(rest) != 0 {
+		t.Fatalf("DecodeValue left %d bytes", len(rest))
+	}
+	if got := elems.Interface().([]Person); !reflect.DeepEqual(got, []Person{in, in}) {
+		t.Fatalf("DecodeValue = %+v, want two of %+v", got, in)
+	}
+
+	// Celsius has only hand-written methods, which reflection alone could
+	// not follow: its value is a decimal fraction, not an integer.
+	temp := cbor.AppendDecimal(nil, cbor.Decimal{Scale: 1, Value: 215})
+	c := reflect.New(reflect.TypeFor[*Celsius]()).Elem()
+	if _, err := cbor.DecodeValue(temp, c); err != nil {
+		t.Fatalf("DecodeValue(*Celsius) error: %v", err)
+	}
+	if got := *c.Interface().(*Celsius); got != 215 {
+		t.Fatalf("DecodeValue(*Celsius) = %d, want 215", got)
+	}
+
+	var de *cbor.DecodeError
+	if _, err := cbor.DecodeValue(b[:len(b)-1], reflect.New(reflect.TypeFor[Person]()).Elem()); !errors.As(err, &de) {
+		t.Fatalf("truncated input error = %v, want a *DecodeError", err)
+	}
+	var unsupported *cbor.ErrUnsupportedType
+	if _, err := cbor.DecodeValue(b, reflect.ValueOf(in)); !errors.As(err, &unsupported) {
+		t.Fatalf("unaddressable value error = %v, want ErrUnsupportedType", err)
+	}
+	if _, err := cbor.DecodeValue(b, reflect.Value{}); !errors.As(err, &unsupported) {
+		t.Fatalf("invalid value error = %v, want ErrUnsupportedType", err)
+	}
+}
+
+// itemBounds records the bytes its UnmarshalCBOR method is handed.
+type itemBounds struct{ len, cap int }
+
+func (x *itemBounds) UnmarshalCBOR(b []byte) ([]byte, error) {
+	x.len, x.cap = len(b), cap(b)
+	return cbor.Skip(b)
+}
+
+func TestDecodeOne(t *testing.T) {
+	in := Person{Name: "Ada", Age: 36, Data: []byte{1}}
+	item, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	// A frame of custom framing: the item, then bytes that are not CBOR.
+	frame := append(append([]byte(nil), item...), "\xff\r\nbody"...)
+
+	var out Person
+	rest, err := cbor.DecodeOne(frame, &out)
+	if err != nil {
+		t.Fatalf("DecodeOne error: %v", err)
+	}
+	if !reflect.DeepEqual(out, in) {
+		t.Fatalf("DecodeOne = %+v, want %+v", out, in)
+	}
+	if string(rest) != "\xff\r\nbody" || &rest[0] != &frame[len(item)] {
+		t.Fatalf("rest = %q, want the bytes after the item in place", rest)
+	}
+
+	// Reflection targets are decoded from the item alone as well.
+	var adhoc adhocPerson
+	if rest, err = cbor.DecodeOne(frame, &adhoc); err != nil || len(rest) != len(frame)-len(item) {
+		t.Fatalf("DecodeOne(adhoc) error: %v, rest = %q", err, rest)
+	}
+	if adhoc.Name != in.Name || adhoc.Age != in.Age {
+		t.Fatalf("DecodeOne(adhoc) = %+v, want %+v", adhoc, in)
+	}
+
+	var bounds itemBounds
+	if _, err := cbor.DecodeOne(frame, &bounds); err != nil {
+		t.Fatalf("DecodeOne(itemBounds) error: %v", err)
+	}
+	if bounds.len != len(item) || bounds.cap != len(item) {
+		t.Fatalf("UnmarshalCBOR got len %d cap %d, want both %d", bounds.len, bounds.cap, len(item))
+	}
+
+	var de *cbor.DecodeError
+	if rest, err := cbor.DecodeOne(item[:len(item)-1], &out); !errors.As(err, &de) || len(rest) != len(item)-1 {
+		t.Fatalf("truncated item: rest %d bytes, error = %v, want a *DecodeError and all of buf", len(rest), err)
+	}
+	if _, err := cbor.DecodeOne(frame, out); err == nil {
+		t.Fatal("DecodeOne into a non-pointer succeeded")
+	}
+}
@@ -0,0 +1,27 @@
+package structs
+
+// Letter and Octet are named scalar types; they encode as the rune and
+// byte they hold.
+type (
+	Letter rune
+	Octet  byte
+)
+
+// Initial is an alias, so it is a rune.
+type Initial = rune
+
+// Glyphs exercises rune and byte fields, which are integers, next to
+// []rune, an array of integers, and []byte, a byte string.
+type Glyphs struct {
+	R       rune      `cbor:"r"`
+	B       byte      `cbor:"b"`
+	Runes   []rune    `cbor:"runes"`
+	Bytes   []byte    `cbor:"bytes"`
+	Letter  Letter    `cbor:"letter"`
+	Octet   Octet     `cbor:"octet,omitempty"`
+	Letters []Letter  `cbor:"letters"`
+	Octets  []Octet   `cbor:"octets"`
+	Initial Initial   `cbor:"initial"`
+	Marks   []Initial `cbor:"marks"`
+	Last    *Letter   `cbor:"last"`
+}
@@ -0,0 +1,84 @@
+package structs
+
+import (
+	"bytes"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+func TestGlyphsEncoding(t *testing.T) {
+	last := Letter('z')
+	in := Glyphs{
+		R:       'é',
+		B:       0xff,
+		Runes:   []rune("hé"),
+		Bytes:   []byte("hé"),
+		Letter:  'λ',
+		Octet:   7,
+		Letters: []Letter{'a', 'b'},
+		Octets:  []Octet{1, 2},
+		Initial: 'Q',
+		Marks:   []Initial{'!'},
+		Last:    &last,
+	}
+	b, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+
+	want := cbor.AppendMapHeader(nil, 11)
+	want = cbor.AppendInt64(cbor.AppendString(want, "r"), 'é')
+	want = cbor.AppendUint64(cbor.AppendString(want, "b"), 0xff)
+	want = cbor.AppendArrayHeader(cbor.AppendString(want, "runes"), 2)
+	want = cbor.AppendInt64(cbor.AppendInt64(want, 'h'), 'é')
+	want = cbor.AppendBytes(cbor.AppendString(want, "bytes"), []byte("hé"))
+	want = cbor.AppendInt64(cbor.AppendString(want, "letter"), 'λ')
+	want = cbor.AppendUint64(cbor.AppendString(want, "octet"), 7)
+	want = cbor.AppendArrayHeader(cbor.AppendString(want, "letters"), 2)
+	want = cbor.AppendInt64(cbor.AppendInt64(want, 'a'), 'b')
+	want = cbor.AppendArrayHeader(cbor.AppendString(want, "octets"), 2)
+	want = cbor.AppendUint64(cbor.AppendUint64(want, 1), 2)
+	want = cbor.AppendInt64(cbor.AppendString(want, "initial"), 'Q')
+	want = cbor.AppendArrayHeader(cbor.AppendString(want, "marks"), 1)
+	want = cbor.AppendInt64(want, '!')
+	want = cbor.AppendInt64(cbor.AppendString(want, "last"), 'z')
+	if !bytes.Equal(b, want) {
+		t.Fatalf("encoded\n %x\nwant\n %x", b, want)
+	}
+
+	for name, decode := range map[string]func(*Glyphs, []byte) ([]byte, error){
+		"DecodeSafe":    (*Glyphs).DecodeSafe,
+		"DecodeTrusted": (*Glyphs).DecodeTrusted,
+	} {
+		var out Glyphs
+		if _, err := decode(&out, b); err != nil {
+			t.Fatalf("%s error: %v", name, err)
+		}
+		if out.R != in.R || out.B != in.B || string(out.Runes) != "hé" || string(out.Bytes) != "hé" ||
+			out.Letter != in.Letter || out.Octet != in.Octet || len(out.Letters) != 2 || out.Letters[1] != 'b' ||
+			len(out.Octets) != 2 || out.Octets[1] != 2 || out.Initial != 'Q' || len(out.Marks) != 1 ||
+			out.Last == nil || *out.Last != 'z' {
+			t.Fatalf("%s decoded %+v, want %+v", name, out, in)
+		}
+	}
+}
+
+func TestNamedScalarRange(t *testing.T) {
+	// A named byte type is as narrow as byte.
+	b := cbor.AppendMapHeader(nil, 1)
+	b = cbor.AppendUint64(cbor.AppendString(b, "octet"), 256)
+	var out Glyphs
+	if _, err := out.UnmarshalCBOR(b); err == nil {
+		t.Fatalf("decoding 256 into an Octet succeeded")
+	}
+
+	// Omitempty compares a named scalar against its zero value.
+	enc, err := (&Glyphs{}).MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	if bytes.Contains(enc, cbor.AppendString(nil, "octet")) {
+		t.Fatalf("zero Octet was not omitted: %x", enc)
+	}
+}
@@ -3,17 +3,41 @@
 package structs
 
 import (
+	"io"
 	"time"
 
 	cbor "github.com/delaneyj/cbor/runtime"
 )
 
+// Fail the build if a generated method drifts from the runtime interfaces.
+var (
+	_ cbor.Marshaler   = (*Scalars)(nil)
+	_ cbor.Unmarshaler = (*Scalars)(nil)
+	_ cbor.Marshaler   = (*Nested)(nil)
+	_ cbor.Unmarshaler = (*Nested)(nil)
+)
+
 func (x Scalars) Msgsize() (s int) {
 	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("s") + cbor.StringPrefixSize + len(x.S) + cbor.StringPrefixSize + len("b") + cbor.BoolSize + cbor.StringPrefixSize + len("i") + cbor.IntSize + cbor.StringPrefixSize + len("i8") + cbor.Int8Size + cbor.StringPrefixSize + len("i16") + cbor.Int16Size + cbor.StringPrefixSize + len("i32") + cbor.Int32Size + cbor.StringPrefixSize + len("i64") + cbor.Int64Size + cbor.StringPrefixSize + len("u") + cbor.UintSize + cbor.StringPrefixSize + len("u8") + cbor.Uint8Size + cbor.StringPrefixSize + len("u16") + cbor.Uint16Size + cbor.StringPrefixSize + len("u32") + cbor.Uint32Size + cbor.StringPrefixSize + len("u64") + cbor.Uint64Size + cbor.StringPrefixSize + len("f32") + cbor.Float32Size + cbor.StringPrefixSize + len("f64") + cbor.Float64Size + cbor.StringPrefixSize + len("data") + cbor.BytesPrefixSize + len(x.Data) + cbor.StringPrefixSize + len("ints") + cbor.ArrayHeaderSize + len(x.Ints)*cbor.IntSize + cbor.StringPrefixSize + len("names") + cbor.ArrayHeaderSize + len(x.Names)*cbor.StringPrefixSize + cbor.StringPrefixSize + len("scores") + cbor.MapHeaderSize + len(x.Scores)*(cbor.StringPrefixSize+cbor.IntSize) + cbor.StringPrefixSize + len("t") + cbor.TimeSize + cbor.StringPrefixSize + len("d") + cbor.DurationSize
 	return
 }
 
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
 func (x *Scalars) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *Scalars) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *Scalars) AppendCBOR(b []byte) ([]byte, error) {
 	if x == nil {
 		return cbor.AppendNil(b), nil
 	}
@@ -93,28 +117,51 @@ func (x *Scalars) MarshalCBOR(b []byte) ([]byte, error) {
 		return b, err
 	}
 	b = cbor.AppendString(b, "data")
-	b, err = cbor.AppendInterface(b, x.Data)
-	if err != nil {
-		return b, err
+	if x.Data == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = cbor.AppendNil(b)
+	} else {
+		b, err = cbor.AppendBytes(b, x.Data), nil
+		if err != nil {
+			return b, err
+		}
 	}
-
-	b = cbor.AppendString(b, "ints")
-	b = cbor.AppendArrayHeader(b, uint32(len(x.Ints)))
-	for _, v := range x.Ints {
-		b = cbor.AppendInt(b, v)
+	if x.Ints == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = cbor.AppendString(b, "ints")
+		b = cbor.AppendNil(b)
+	} else {
+		b = cbor.AppendString(b, "ints")
+		b = cbor.AppendArrayHeader(b, uint32(len(x.Ints)))
+		for _, v := range x.Ints {
+			b = cbor.AppendInt(b, v)
+		}
 	}
-
-	b = cbor.AppendString(b, "names")
-	b = cbor.AppendArrayHeader(b, uint32(len(x.Names)))
-	for _, v := range x.Names {
-		b = cbor.AppendString(b, v)
+	if x.Names == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = cbor.AppendString(b, "names")
+		b = cbor.AppendNil(b)
+	} else {
+		b = cbor.AppendString(b, "names")
+		b = cbor.AppendArrayHeader(b, uint32(len(x.Names)))
+		for _, v := range x.Names {
+			b = cbor.AppendString(b, v)
+		}
 	}
-
-	b = cbor.AppendString(b, "scores")
-	b = cbor.AppendMapHeader(b, uint32(len(x.Scores)))
-	for k, v := range x.Scores {
-		b = cbor.AppendString(b, k)
-		b = cbor.AppendInt(b, v)
+	if x.Scores == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = cbor.AppendString(b, "scores")
+		b = cbor.AppendNil(b)
+	} else {
+		b = cbor.AppendString(b, "scores")
+		if cbor.CanonicalMapEncode {
+			b, err = cbor.AppendMapDeterministic(b, x.Scores, cbor.EncKeyString, func(b []byte, v int) ([]byte, error) { return cbor.AppendInt(b, v), nil })
+			if err != nil {
+				return b, err
+			}
+		} else {
+			b = cbor.AppendMapHeader(b, uint32(len(x.Scores)))
+			for k, v := range x.Scores {
+				b = cbor.AppendString(b, k)
+				b = cbor.AppendInt(b, v)
+			}
+		}
 	}
 	b = cbor.AppendString(b, "t")
 	b, err = cbor.AppendTime(b, x.T), nil
@@ -130,147 +177,365 @@ func (x *Scalars) MarshalCBOR(b []byte) ([]byte, error) {
 	return b, nil
 }
 
-// DecodeSafe decodes using validated, allocating string handling.
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
 func (x *Scalars) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *Scalars) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
 	if x == nil {
 		return b, cbor.ErrNotNil
 	}
 	sz, rest, err := cbor.ReadMapHeaderBytes(b)
 	if err != nil {
-		return b, err
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
 	}
+	var seen [1]uint64
 	for i := uint32(0); i < sz; i++ {
-		key, v, err := cbor.ReadStringBytes(rest)
+		key, v, err := in.ReadStringBytes(rest)
 		if err != nil {
-			return b, err
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
 		}
 		switch key {
 		case "s":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "s", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "s", len(b)-len(v))
+				}
+			}
 
 			var tmp string
-			tmp, v, err = cbor.ReadStringBytes(v)
+			tmp, v, err = in.ReadStringBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "s", len(b)-len(v))
 			}
 			x.S = tmp
 		case "b":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "b", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "b", len(b)-len(v))
+				}
+			}
 
 			var tmp bool
 			tmp, v, err = cbor.ReadBoolBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "b", len(b)-len(v))
 			}
 			x.B = tmp
 		case "i":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 2); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "i", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "i", len(b)-len(v))
+				}
+			}
 
 			var tmp int
 			tmp, v, err = cbor.ReadIntBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "i", len(b)-len(v))
 			}
 			x.I = tmp
 		case "i8":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 3); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "i8", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "i8", len(b)-len(v))
+				}
+			}
 
 			var tmp int8
 			tmp, v, err = cbor.ReadInt8Bytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "i8", len(b)-len(v))
 			}
 			x.I8 = tmp
 		case "i16":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 4); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "i16", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "i16", len(b)-len(v))
+				}
+			}
 
 			var tmp int16
 			tmp, v, err = cbor.ReadInt16Bytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "i16", len(b)-len(v))
 			}
 			x.I16 = tmp
 		case "i32":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 5); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "i32", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "i32", len(b)-len(v))
+				}
+			}
 
 			var tmp int32
 			tmp, v, err = cbor.ReadInt32Bytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "i32", len(b)-len(v))
 			}
 			x.I32 = tmp
 		case "i64":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 6); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "i64", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "i64", len(b)-len(v))
+				}
+			}
 
 			var tmp int64
 			tmp, v, err = cbor.ReadInt64Bytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "i64", len(b)-len(v))
 			}
 			x.I64 = tmp
 		case "u":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 7); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "u", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "u", len(b)-len(v))
+				}
+			}
 
 			var tmp uint
 			tmp, v, err = cbor.ReadUintBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "u", len(b)-len(v))
 			}
 			x.U = tmp
 		case "u8":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 8); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "u8", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "u8", len(b)-len(v))
+				}
+			}
 
 			var tmp uint8
 			tmp, v, err = cbor.ReadUint8Bytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "u8", len(b)-len(v))
 			}
 			x.U8 = tmp
 		case "u16":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 9); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "u16", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "u16", len(b)-len(v))
+				}
+			}
 
 			var tmp uint16
 			tmp, v, err = cbor.ReadUint16Bytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "u16", len(b)-len(v))
 			}
 			x.U16 = tmp
 		case "u32":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 10); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "u32", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "u32", len(b)-len(v))
+				}
+			}
 
 			var tmp uint32
 			tmp, v, err = cbor.ReadUint32Bytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "u32", len(b)-len(v))
 			}
 			x.U32 = tmp
 		case "u64":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 11); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "u64", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "u64", len(b)-len(v))
+				}
+			}
 
 			var tmp uint64
 			tmp, v, err = cbor.ReadUint64Bytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "u64", len(b)-len(v))
 			}
 			x.U64 = tmp
 		case "f32":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 12); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "f32", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "f32", len(b)-len(v))
+				}
+			}
 
 			var tmp float32
 			tmp, v, err = cbor.ReadFloat32Bytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "f32", len(b)-len(v))
 			}
 			x.F32 = tmp
 		case "f64":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 13); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "f64", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "f64", len(b)-len(v))
+				}
+			}
 
 			var tmp float64
 			tmp, v, err = cbor.ReadFloat64Bytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "f64", len(b)-len(v))
 			}
 			x.F64 = tmp
 		case "data":
-
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 14); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "data", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "data", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Data = cbor.NullSlice(x.Data)
+				break
+			}
 			var tmp []byte
 			tmp, v, err = cbor.ReadBytesBytes(v, nil)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "data", len(b)-len(v))
 			}
 			x.Data = tmp
 		case "ints":
-
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 15); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "ints", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "ints", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Ints = cbor.NullSlice(x.Ints)
+				break
+			}
 			var sz uint32
-			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "ints", len(b)-len(v))
 			}
 			if cap(x.Ints) >= int(sz) {
 				x.Ints = x.Ints[:sz]
@@ -284,16 +549,36 @@ func (x *Scalars) DecodeSafe(b []byte) ([]byte, error) {
 				var tmp int
 				tmp, v, err = cbor.ReadIntBytes(v)
 				if err != nil {
-					return b, err
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, int(iInts)), "ints", len(b)-len(v))
 				}
 				x.Ints[iInts] = tmp
 			}
+			if indef {
+				v = v[1:] // break
+			}
 		case "names":
-
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 16); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "names", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "names", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Names = cbor.NullSlice(x.Names)
+				break
+			}
 			var sz uint32
-			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "names", len(b)-len(v))
 			}
 			if cap(x.Names) >= int(sz) {
 				x.Names = x.Names[:sz]
@@ -305,18 +590,37 @@ func (x *Scalars) DecodeSafe(b []byte) ([]byte, error) {
 			}
 			for iNames := uint32(0); iNames < sz; iNames++ {
 				var tmp string
-				tmp, v, err = cbor.ReadStringBytes(v)
+				tmp, v, err = in.ReadStringBytes(v)
 				if err != nil {
-					return b, err
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, int(iNames)), "names", len(b)-len(v))
 				}
 				x.Names[iNames] = tmp
 			}
+			if indef {
+				v = v[1:] // break
+			}
 		case "scores":
-
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 17); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "scores", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "scores", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Scores = cbor.NullMap(x.Scores)
+				break
+			}
 			var sz uint32
 			sz, v, err = cbor.ReadMapHeaderBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "scores", len(b)-len(v))
 			}
 			if x.Scores == nil && sz > 0 {
 				x.Scores = make(map[string]int, sz)
@@ -325,45 +629,77 @@ func (x *Scalars) DecodeSafe(b []byte) ([]byte, error) {
 			}
 			for iScores := uint32(0); iScores < sz; iScores++ {
 				var key string
-				key, v, err = cbor.ReadStringBytes(v)
+				key, v, err = in.ReadStringBytes(v)
 				if err != nil {
-					return b, err
+					return b, cbor.WrapDecodeError(err, "scores", len(b)-len(v))
+				}
+				if _, dup := x.Scores[key]; dup {
+					var skip bool
+					if v, skip, err = cbor.SkipDuplicate(v); err != nil {
+						return b, cbor.WrapDecodeError(cbor.WrapDecodeKey(err, key), "scores", len(b)-len(v))
+					}
+					if skip {
+						continue
+					}
 				}
 				var tmp int
 				tmp, v, err = cbor.ReadIntBytes(v)
 				if err != nil {
-					return b, err
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeKey(err, key), "scores", len(b)-len(v))
 				}
 				x.Scores[key] = tmp
 			}
 		case "t":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 18); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "t", len(b)-len(v))
+				}
+				v = o
+				break
+			}
 
 			var tmp time.Time
-			tmp, v, err = cbor.ReadTimeBytes(v)
+			tmp, v, err = cbor.ReadAnyTimeBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "t", len(b)-len(v))
 			}
 			x.T = tmp
 		case "d":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 19); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "d", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "d", len(b)-len(v))
+				}
+			}
 
 			var tmp time.Duration
 			tmp, v, err = cbor.ReadDurationBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "d", len(b)-len(v))
 			}
 			x.D = tmp
 		default:
 			v, err = cbor.Skip(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
 			}
 		}
 		rest = v
 	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
 	return rest, nil
 }
 
-// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
 func (x *Scalars) DecodeTrusted(b []byte) ([]byte, error) {
 	if x == nil {
 		return b, cbor.ErrNotNil
@@ -372,6 +708,9 @@ func (x *Scalars) DecodeTrusted(b []byte) ([]byte, error) {
 	if err != nil {
 		return b, err
 	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
 	for i := uint32(0); i < sz; i++ {
 		keyBytes, v, err := cbor.ReadStringZC(rest)
 		if err != nil {
@@ -380,14 +719,22 @@ func (x *Scalars) DecodeTrusted(b []byte) ([]byte, error) {
 		key := cbor.UnsafeString(keyBytes)
 		switch key {
 		case "s":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
-			var tmpBytes []byte
-			tmpBytes, v, err = cbor.ReadStringZC(v)
+			x.S, v, err = cbor.ReadTrustedStringBytes(v)
 			if err != nil {
 				return b, err
 			}
-			x.S = cbor.UnsafeString(tmpBytes)
 		case "b":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
 			var tmp bool
 			tmp, v, err = cbor.ReadBoolBytes(v)
@@ -396,6 +743,11 @@ func (x *Scalars) DecodeTrusted(b []byte) ([]byte, error) {
 			}
 			x.B = tmp
 		case "i":
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
 			var tmp int
 			tmp, v, err = cbor.ReadIntBytes(v)
@@ -404,6 +756,11 @@ func (x *Scalars) DecodeTrusted(b []byte) ([]byte, error) {
 			}
 			x.I = tmp
 		case "i8":
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
 			var tmp int8
 			tmp, v, err = cbor.ReadInt8Bytes(v)
@@ -412,6 +769,11 @@ func (x *Scalars) DecodeTrusted(b []byte) ([]byte, error) {
 			}
 			x.I8 = tmp
 		case "i16":
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
 			var tmp int16
 			tmp, v, err = cbor.ReadInt16Bytes(v)
@@ -420,6 +782,11 @@ func (x *Scalars) DecodeTrusted(b []byte) ([]byte, error) {
 			}
 			x.I16 = tmp
 		case "i32":
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
 			var tmp int32
 			tmp, v, err = cbor.ReadInt32Bytes(v)
@@ -428,6 +795,11 @@ func (x *Scalars) DecodeTrusted(b []byte) ([]byte, error) {
 			}
 			x.I32 = tmp
 		case "i64":
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
 			var tmp int64
 			tmp, v, err = cbor.ReadInt64Bytes(v)
@@ -436,6 +808,11 @@ func (x *Scalars) DecodeTrusted(b []byte) ([]byte, error) {
 			}
 			x.I64 = tmp
 		case "u":
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
 			var tmp uint
 			tmp, v, err = cbor.ReadUintBytes(v)
@@ -444,6 +821,11 @@ func (x *Scalars) DecodeTrusted(b []byte) ([]byte, error) {
 			}
 			x.U = tmp
 		case "u8":
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
 			var tmp uint8
 			tmp, v, err = cbor.ReadUint8Bytes(v)
@@ -452,6 +834,11 @@ func (x *Scalars) DecodeTrusted(b []byte) ([]byte, error) {
 			}
 			x.U8 = tmp
 		case "u16":
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
 			var tmp uint16
 			tmp, v, err = cbor.ReadUint16Bytes(v)
@@ -460,6 +847,11 @@ func (x *Scalars) DecodeTrusted(b []byte) ([]byte, error) {
 			}
 			x.U16 = tmp
 		case "u32":
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
 			var tmp uint32
 			tmp, v, err = cbor.ReadUint32Bytes(v)
@@ -468,6 +860,11 @@ func (x *Scalars) DecodeTrusted(b []byte) ([]byte, error) {
 			}
 			x.U32 = tmp
 		case "u64":
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
 			var tmp uint64
 			tmp, v, err = cbor.ReadUint64Bytes(v)
@@ -476,6 +873,11 @@ func (x *Scalars) DecodeTrusted(b []byte) ([]byte, error) {
 			}
 			x.U64 = tmp
 		case "f32":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
 			var tmp float32
 			tmp, v, err = cbor.ReadFloat32Bytes(v)
@@ -484,6 +886,11 @@ func (x *Scalars) DecodeTrusted(b []byte) ([]byte, error) {
 			}
 			x.F32 = tmp
 		case "f64":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
 			var tmp float64
 			tmp, v, err = cbor.ReadFloat64Bytes(v)
@@ -492,7 +899,16 @@ func (x *Scalars) DecodeTrusted(b []byte) ([]byte, error) {
 			}
 			x.F64 = tmp
 		case "data":
-
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Data = cbor.NullSlice(x.Data)
+				break
+			}
 			var tmp []byte
 			tmp, v, err = cbor.ReadBytesBytes(v, nil)
 			if err != nil {
@@ -500,9 +916,19 @@ func (x *Scalars) DecodeTrusted(b []byte) ([]byte, error) {
 			}
 			x.Data = tmp
 		case "ints":
-
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Ints = cbor.NullSlice(x.Ints)
+				break
+			}
 			var sz uint32
-			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
 			if err != nil {
 				return b, err
 			}
@@ -522,10 +948,23 @@ func (x *Scalars) DecodeTrusted(b []byte) ([]byte, error) {
 				}
 				x.Ints[iInts] = tmp
 			}
+			if indef {
+				v = v[1:] // break
+			}
 		case "names":
-
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Names = cbor.NullSlice(x.Names)
+				break
+			}
 			var sz uint32
-			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
 			if err != nil {
 				return b, err
 			}
@@ -539,14 +978,26 @@ func (x *Scalars) DecodeTrusted(b []byte) ([]byte, error) {
 			}
 			for iNames := uint32(0); iNames < sz; iNames++ {
 				var tmp string
-				tmp, v, err = cbor.ReadStringBytes(v)
+				tmp, v, err = cbor.ReadTrustedStringBytes(v)
 				if err != nil {
 					return b, err
 				}
 				x.Names[iNames] = tmp
 			}
+			if indef {
+				v = v[1:] // break
+			}
 		case "scores":
-
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Scores = cbor.NullMap(x.Scores)
+				break
+			}
 			var sz uint32
 			sz, v, err = cbor.ReadMapHeaderBytes(v)
 			if err != nil {
@@ -559,7 +1010,7 @@ func (x *Scalars) DecodeTrusted(b []byte) ([]byte, error) {
 			}
 			for iScores := uint32(0); iScores < sz; iScores++ {
 				var key string
-				key, v, err = cbor.ReadStringBytes(v)
+				key, v, err = cbor.ReadTrustedStringBytes(v)
 				if err != nil {
 					return b, err
 				}
@@ -573,12 +1024,17 @@ func (x *Scalars) DecodeTrusted(b []byte) ([]byte, error) {
 		case "t":
 
 			var tmp time.Time
-			tmp, v, err = cbor.ReadTimeBytes(v)
+			tmp, v, err = cbor.ReadAnyTimeBytes(v)
 			if err != nil {
 				return b, err
 			}
 			x.T = tmp
 		case "d":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
 			var tmp time.Duration
 			tmp, v, err = cbor.ReadDurationBytes(v)
@@ -597,6 +1053,32 @@ func (x *Scalars) DecodeTrusted(b []byte) ([]byte, error) {
 	return rest, nil
 }
 
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *Scalars) resetCBOR() {
+	var zero Scalars
+	x.S = zero.S
+	x.B = zero.B
+	x.I = zero.I
+	x.I8 = zero.I8
+	x.I16 = zero.I16
+	x.I32 = zero.I32
+	x.I64 = zero.I64
+	x.U = zero.U
+	x.U8 = zero.U8
+	x.U16 = zero.U16
+	x.U32 = zero.U32
+	x.U64 = zero.U64
+	x.F32 = zero.F32
+	x.F64 = zero.F64
+	x.Data = x.Data[:0]
+	x.Ints = x.Ints[:0]
+	x.Names = x.Names[:0]
+	clear(x.Scores)
+	x.T = zero.T
+	x.D = zero.D
+}
+
 // UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
 func (x *Scalars) UnmarshalCBOR(b []byte) ([]byte, error) {
 	return x.DecodeSafe(b)
@@ -607,7 +1089,22 @@ func (x Nested) Msgsize() (s int) {
 	return
 }
 
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
 func (x *Nested) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *Nested) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *Nested) AppendCBOR(b []byte) ([]byte, error) {
 	if x == nil {
 		return cbor.AppendNil(b), nil
 	}
@@ -628,13 +1125,13 @@ func (x *Nested) MarshalCBOR(b []byte) ([]byte, error) {
 		return b, err
 	}
 	b = cbor.AppendString(b, "base")
-	b, err = x.Base.MarshalCBOR(b)
+	b, err = x.Base.AppendCBOR(b)
 	if err != nil {
 		return b, err
 	}
 	if !(x.Ptr == nil) {
 		b = cbor.AppendString(b, "ptr")
-		b, err = cbor.AppendPtrMarshaler(b, x.Ptr)
+		b, err = x.Ptr.AppendCBOR(b)
 		if err != nil {
 			return b, err
 		}
@@ -643,56 +1140,110 @@ func (x *Nested) MarshalCBOR(b []byte) ([]byte, error) {
 	return b, nil
 }
 
-// DecodeSafe decodes using validated, allocating string handling.
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
 func (x *Nested) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *Nested) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
 	if x == nil {
 		return b, cbor.ErrNotNil
 	}
 	sz, rest, err := cbor.ReadMapHeaderBytes(b)
 	if err != nil {
-		return b, err
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
 	}
+	var seen [1]uint64
 	for i := uint32(0); i < sz; i++ {
-		key, v, err := cbor.ReadStringBytes(rest)
+		key, v, err := in.ReadStringBytes(rest)
 		if err != nil {
-			return b, err
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
 		}
 		switch key {
 		case "id":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "id", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "id", len(b)-len(v))
+				}
+			}
 
 			var tmp string
-			tmp, v, err = cbor.ReadStringBytes(v)
+			tmp, v, err = in.ReadStringBytes(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "id", len(b)-len(v))
 			}
 			x.ID = tmp
 		case "base":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "base", len(b)-len(v))
+				}
+				v = o
+				break
+			}
 
-			v, err = x.Base.UnmarshalCBOR(v)
+			v, err = x.Base.DecodeInterned(v, in)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "base", len(b)-len(v))
 			}
 		case "ptr":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 2); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "ptr", len(b)-len(v))
+				}
+				v = o
+				break
+			}
 
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Ptr = nil
+				break
+			}
 			if x.Ptr == nil {
 				x.Ptr = new(Scalars)
+			} else {
+				x.Ptr.resetCBOR()
 			}
-			v, err = x.Ptr.UnmarshalCBOR(v)
+			v, err = x.Ptr.DecodeInterned(v, in)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, "ptr", len(b)-len(v))
 			}
 		default:
 			v, err = cbor.Skip(v)
 			if err != nil {
-				return b, err
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
 			}
 		}
 		rest = v
 	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
 	return rest, nil
 }
 
-// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
 func (x *Nested) DecodeTrusted(b []byte) ([]byte, error) {
 	if x == nil {
 		return b, cbor.ErrNotNil
@@ -701,6 +1252,9 @@ func (x *Nested) DecodeTrusted(b []byte) ([]byte, error) {
 	if err != nil {
 		return b, err
 	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
 	for i := uint32(0); i < sz; i++ {
 		keyBytes, v, err := cbor.ReadStringZC(rest)
 		if err != nil {
@@ -709,13 +1263,16 @@ func (x *Nested) DecodeTrusted(b []byte) ([]byte, error) {
 		key := cbor.UnsafeString(keyBytes)
 		switch key {
 		case "id":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
 
-			var tmpBytes []byte
-			tmpBytes, v, err = cbor.ReadStringZC(v)
+			x.ID, v, err = cbor.ReadTrustedStringBytes(v)
 			if err != nil {
 				return b, err
 			}
-			x.ID = cbor.UnsafeString(tmpBytes)
 		case "base":
 
 			v, err = (&x.Base).DecodeTrusted(v)
@@ -724,8 +1281,15 @@ func (x *Nested) DecodeTrusted(b []byte) ([]byte, error) {
 			}
 		case "ptr":
 
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Ptr = nil
+				break
+			}
 			if x.Ptr == nil {
 				x.Ptr = new(Scalars)
+			} else {
+				x.Ptr.resetCBOR()
 			}
 			v, err = x.Ptr.DecodeTrusted(v)
 			if err != nil {
@@ -742,6 +1306,15 @@ func (x *Nested) DecodeTrusted(b []byte) ([]byte, error) {
 	return rest, nil
 }
 
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *Nested) resetCBOR() {
+	var zero Nested
+	x.ID = zero.ID
+	x.Base = zero.Base
+	x.Ptr = zero.Ptr
+}
+
 // UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
 func (x *Nested) UnmarshalCBOR(b []byte) ([]byte, error) {
 	return x.DecodeSafe(b)
@@ -3,6 +3,8 @@ package structs
 import (
 	"testing"
 	"time"
+
+	cbor "github.com/delaneyj/cbor/runtime"
 )
 
 type scalarsDecoder struct {
@@ -173,3 +175,20 @@ func TestNestedRoundTripSafeAndTrusted(t *testing.T) {
 		})
 	}
 }
+
+func TestScalarsResetBeforeDecode(t *testing.T) {
+	cbor.ResetBeforeDecode = true
+	defer func() { cbor.ResetBeforeDecode = false }()
+
+	in := cbor.AppendMapHeader(nil, 1)
+	in = cbor.AppendString(in, "i")
+	in = cbor.AppendInt(in, 5)
+
+	dst := Scalars{S: "old", B: true, F64: 2.5, Data: []byte("old"), T: time.Unix(1, 0), Scores: map[string]int{"old": 1}}
+	if _, err := dst.DecodeSafe(in); err != nil {
+		t.Fatalf("DecodeSafe error: %v", err)
+	}
+	if dst.I != 5 || dst.S != "" || dst.B || dst.F64 != 0 || len(dst.Data) != 0 || !dst.T.IsZero() || len(dst.Scores) != 0 {
+		t.Fatalf("stale fields survived reset: %+v", dst)
+	}
+}
@@ -0,0 +1,46 @@
+package structs
+
+// Shape is a sum-type-style interface used to exercise polymorphic
+// interface fields backed by cbor.RegisterType.
+type Shape interface {
+	Area() float64
+}
+
+// Circle is a Shape registered under its own CBOR tag in tests.
+type Circle struct {
+	Radius float64 `cbor:"r"`
+}
+
+// Area implements Shape.
+func (c Circle) Area() float64 { return 3.14159 * c.Radius * c.Radius }
+
+// Rect is a Shape registered (as a pointer) under its own CBOR tag in tests.
+type Rect struct {
+	W float64 `cbor:"w"`
+	H float64 `cbor:"h"`
+}
+
+// Area implements Shape.
+func (r *Rect) Area() float64 { return r.W * r.H }
+
+// Drawing holds polymorphic interface fields.
+type Drawing struct {
+	Name    string `cbor:"name"`
+	Primary Shape  `cbor:"primary,omitempty"`
+	Extra   any    `cbor:"extra,omitempty"`
+}
+
+// Envelope carries interface fields encoded as discriminated unions
+// ({0: typeTag, 1: payload}) rather than tagged items.
+type Envelope struct {
+	Subject string `cbor:"subject"`
+	Body    Shape  `cbor:"body,union"`
+	Meta    any    `cbor:"meta,union,omitempty"`
+}
+
+// Canvas holds interface slices whose elements are dispatched on their
+// dynamic type one by one.
+type Canvas struct {
+	Shapes []Shape `cbor:"shapes"`
+	Layers []any   `cbor:"layers,omitempty"`
+}
@@ -0,0 +1,341 @@
+package structs
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+const (
+	circleTag = 40001
+	rectTag   = 40002
+)
+
+var registerShapesOnce sync.Once
+
+func registerShapes(t *testing.T) {
+	t.Helper()
+	registerShapesOnce.Do(func() {
+		if err := cbor.RegisterType(circleTag, Circle{}); err != nil {
+			t.Fatalf("RegisterType Circle: %v", err)
+		}
+		if err := cbor.RegisterType(rectTag, &Rect{}); err != nil {
+			t.Fatalf("RegisterType *Rect: %v", err)
+		}
+	})
+}
+
+func TestDrawingPolymorphicRoundTrip(t *testing.T) {
+	registerShapes(t)
+
+	cases := []struct {
+		name  string
+		shape Shape
+		tag   uint64
+	}{
+		{name: "Circle", shape: Circle{Radius: 2}, tag: circleTag},
+		{name: "Rect", shape: &Rect{W: 3, H: 4}, tag: rectTag},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			orig := &Drawing{Name: "d", Primary: tc.shape, Extra: tc.shape}
+			b, err := orig.MarshalCBOR(nil)
+			if err != nil {
+				t.Fatalf("MarshalCBOR error: %v", err)
+			}
+
+			for _, decode := range []func(*Drawing, []byte) ([]byte, error){
+				(*Drawing).DecodeSafe,
+				(*Drawing).DecodeTrusted,
+			} {
+				var dst Drawing
+				rest, err := decode(&dst, b)
+				if err != nil {
+					t.Fatalf("decode error: %v", err)
+				}
+				if len(rest) != 0 {
+					t.Fatalf("leftover bytes: %d", len(rest))
+				}
+				if dst.Primary == nil || dst.Primary.Area() != tc.shape.Area() {
+					t.Fatalf("Primary mismatch: got %#v want %#v", dst.Primary, tc.shape)
+				}
+				if _, ok := dst.Extra.(Shape); !ok {
+					t.Fatalf("Extra not decoded as Shape: %#v", dst.Extra)
+				}
+			}
+
+			// The interface value must be wrapped in the registered tag.
+			enc, err := cbor.AppendInterface(nil, tc.shape)
+			if err != nil {
+				t.Fatalf("AppendInterface error: %v", err)
+			}
+			tag, _, err := cbor.ReadTagBytes(enc)
+			if err != nil || tag != tc.tag {
+				t.Fatalf("expected tag %d, got %d (err=%v)", tc.tag, tag, err)
+			}
+		})
+	}
+}
+
+func TestDrawingNilInterfaceFields(t *testing.T) {
+	registerShapes(t)
+
+	orig := &Drawing{Name: "empty"}
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	var dst Drawing
+	if _, err := dst.DecodeSafe(b); err != nil {
+		t.Fatalf("DecodeSafe error: %v", err)
+	}
+	if dst.Primary != nil || dst.Extra != nil {
+		t.Fatalf("expected nil interface fields, got %+v", dst)
+	}
+}
+
+func TestRegisterTypeDuplicates(t *testing.T) {
+	registerShapes(t)
+
+	err := cbor.RegisterType(circleTag, Person{})
+	if !errors.Is(err, cbor.ErrDuplicateTag) {
+		t.Fatalf("expected ErrDuplicateTag, got %v", err)
+	}
+	if err := cbor.RegisterType(circleTag+100, Circle{}); err == nil {
+		t.Fatalf("expected error registering Circle under a second tag")
+	}
+	if err := cbor.RegisterType(circleTag+101, 42); err == nil {
+		t.Fatalf("expected error registering a type without Marshaler")
+	}
+}
+
+func TestRegisterTypeConcurrent(t *testing.T) {
+	// Only one of the racing registrations for the same tag may win.
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	wins := 0
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := cbor.RegisterType(40100, Scalars{}); err == nil {
+				mu.Lock()
+				wins++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if wins != 1 {
+		t.Fatalf("expected exactly one successful registration, got %d", wins)
+	}
+}
+
+func TestEnvelopeUnionRoundTrip(t *testing.T) {
+	registerShapes(t)
+
+	orig := &Envelope{Subject: "orders.new", Body: &Rect{W: 2, H: 5}, Meta: Circle{Radius: 1}}
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+
+	for _, decode := range []func(*Envelope, []byte) ([]byte, error){
+		(*Envelope).DecodeSafe,
+		(*Envelope).DecodeTrusted,
+	} {
+		var dst Envelope
+		rest, err := decode(&dst, b)
+		if err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+		if len(rest) != 0 {
+			t.Fatalf("leftover bytes: %d", len(rest))
+		}
+		r, ok := dst.Body.(*Rect)
+		if !ok || r.W != 2 || r.H != 5 {
+			t.Fatalf("Body mismatch: %#v", dst.Body)
+		}
+		if c, ok := dst.Meta.(Circle); !ok || c.Radius != 1 {
+			t.Fatalf("Meta mismatch: %#v", dst.Meta)
+		}
+	}
+}
+
+func TestUnionWireShape(t *testing.T) {
+	registerShapes(t)
+
+	b, err := cbor.AppendUnion(nil, Circle{Radius: 1})
+	if err != nil {
+		t.Fatalf("AppendUnion error: %v", err)
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil || sz != 2 {
+		t.Fatalf("expected 2-entry map, got sz=%d err=%v", sz, err)
+	}
+	key, rest, _ := cbor.ReadUint64Bytes(rest)
+	tag, rest, _ := cbor.ReadUint64Bytes(rest)
+	if key != 0 || tag != circleTag {
+		t.Fatalf("expected {0: %d}, got {%d: %d}", circleTag, key, tag)
+	}
+	key, rest, _ = cbor.ReadUint64Bytes(rest)
+	if key != 1 {
+		t.Fatalf("expected payload key 1, got %d", key)
+	}
+	var c Circle
+	if _, err := c.DecodeSafe(rest); err != nil || c.Radius != 1 {
+		t.Fatalf("payload mismatch: %+v err=%v", c, err)
+	}
+
+	if _, err := cbor.AppendUnion(nil, Person{}); err == nil {
+		t.Fatalf("expected error encoding unregistered type as union")
+	}
+}
+
+func TestUnionUnknownTag(t *testing.T) {
+	registerShapes(t)
+
+	// {0: 99999, 1: {}}
+	u := cbor.AppendMapHeader(nil, 2)
+	u = cbor.AppendUint64(u, 0)
+	u = cbor.AppendUint64(u, 99999)
+	u = cbor.AppendUint64(u, 1)
+	u = cbor.AppendMapHeader(u, 0)
+
+	b := cbor.AppendMapHeader(nil, 2)
+	b = cbor.AppendString(b, "subject")
+	b = cbor.AppendString(b, "x")
+	b = cbor.AppendString(b, "meta")
+	b = append(b, u...)
+
+	var dst Envelope
+	_, err := dst.DecodeSafe(b)
+	var unknown cbor.UnknownUnionTagError
+	if !errors.As(err, &unknown) || unknown.Tag != 99999 {
+		t.Fatalf("expected UnknownUnionTagError for tag 99999, got %v", err)
+	}
+
+	cbor.LenientUnionDecode = true
+	defer func() { cbor.LenientUnionDecode = false }()
+
+	dst = Envelope{}
+	if _, err := dst.DecodeSafe(b); err != nil {
+		t.Fatalf("lenient DecodeSafe error: %v", err)
+	}
+	raw, ok := dst.Meta.(cbor.RawMessage)
+	if !ok || string(raw) != string(u) {
+		t.Fatalf("expected RawMessage %x, got %#v", u, dst.Meta)
+	}
+
+	// The preserved union re-encodes verbatim.
+	out, err := cbor.AppendUnion(nil, dst.Meta)
+	if err != nil || string(out) != string(u) {
+		t.Fatalf("re-encode mismatch: %x err=%v", out, err)
+	}
+
+	// Body, a Shape, cannot hold a RawMessage: decoding fails, handing
+	// over the item instead.
+	b = cbor.AppendMapHeader(nil, 2)
+	b = cbor.AppendString(b, "subject")
+	b = cbor.AppendString(b, "x")
+	b = cbor.AppendString(b, "body")
+	b = append(b, u...)
+	decoders := map[string]func(*Envelope, []byte) ([]byte, error){
+		"DecodeSafe":    (*Envelope).DecodeSafe,
+		"DecodeTrusted": (*Envelope).DecodeTrusted,
+	}
+	for name, decode := range decoders {
+		dst = Envelope{}
+		_, err := decode(&dst, b)
+		if !errors.As(err, &unknown) || unknown.Tag != 99999 || string(unknown.Raw) != string(u) {
+			t.Fatalf("lenient %s of body error = %v, want UnknownUnionTagError holding %x", name, err, u)
+		}
+	}
+
+	cbor.LenientUnionDecode = false
+	if _, err := dst.DecodeSafe(b); !errors.As(err, &unknown) || unknown.Raw != nil {
+		t.Fatalf("strict DecodeSafe of body error = %v, want UnknownUnionTagError without Raw", err)
+	}
+}
+
+func TestCanvasInterfaceSliceRoundTrip(t *testing.T) {
+	registerShapes(t)
+
+	orig := &Canvas{
+		Shapes: []Shape{Circle{Radius: 1}, &Rect{W: 2, H: 3}, nil},
+		Layers: []any{&Rect{W: 1, H: 1}, "label", uint64(7)},
+	}
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+
+	for _, decode := range []func(*Canvas, []byte) ([]byte, error){
+		(*Canvas).DecodeSafe,
+		(*Canvas).DecodeTrusted,
+	} {
+		var dst Canvas
+		rest, err := decode(&dst, b)
+		if err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+		if len(rest) != 0 {
+			t.Fatalf("leftover bytes: %d", len(rest))
+		}
+		if len(dst.Shapes) != 3 || dst.Shapes[2] != nil {
+			t.Fatalf("decoded shapes %#v", dst.Shapes)
+		}
+		if c, ok := dst.Shapes[0].(Circle); !ok || c.Radius != 1 {
+			t.Fatalf("shapes[0] = %#v, want Circle{1}", dst.Shapes[0])
+		}
+		if r, ok := dst.Shapes[1].(*Rect); !ok || *r != (Rect{W: 2, H: 3}) {
+			t.Fatalf("shapes[1] = %#v, want &Rect{2, 3}", dst.Shapes[1])
+		}
+		if r, ok := dst.Layers[0].(*Rect); !ok || *r != (Rect{W: 1, H: 1}) {
+			t.Fatalf("layers[0] = %#v, want &Rect{1, 1}", dst.Layers[0])
+		}
+		if dst.Layers[1] != "label" || dst.Layers[2] != uint64(7) {
+			t.Fatalf("layers = %#v", dst.Layers)
+		}
+	}
+}
+
+func TestCanvasUnregisteredElement(t *testing.T) {
+	registerShapes(t)
+
+	// layers: [circle, tag(40999, 0)] with 40999 never registered.
+	b := cbor.AppendMapHeader(nil, 2)
+	b = cbor.AppendString(b, "shapes")
+	b = cbor.AppendArrayHeader(b, 0)
+	b = cbor.AppendString(b, "layers")
+	b = cbor.AppendArrayHeader(b, 2)
+	b, err := cbor.AppendInterface(b, Circle{Radius: 1})
+	if err != nil {
+		t.Fatalf("AppendInterface error: %v", err)
+	}
+	b = cbor.AppendTag(b, 40999)
+	b = cbor.AppendUint64(b, 0)
+
+	var safe Canvas
+	_, err = safe.DecodeSafe(b)
+	var tagErr cbor.UnexpectedTagError
+	if !errors.As(err, &tagErr) || tagErr.Tag != 40999 {
+		t.Fatalf("DecodeSafe error = %v, want UnexpectedTagError for 40999", err)
+	}
+	var de *cbor.DecodeError
+	if !errors.As(err, &de) || de.Path != "layers[1]" {
+		t.Fatalf("DecodeSafe error %v not located at layers[1]", err)
+	}
+
+	// Trusted decoding keeps the unknown element as a Raw item.
+	var trusted Canvas
+	if _, err := trusted.DecodeTrusted(b); err != nil {
+		t.Fatalf("DecodeTrusted error: %v", err)
+	}
+	if _, ok := trusted.Layers[1].(cbor.RawMessage); !ok {
+		t.Fatalf("layers[1] = %#v, want RawMessage", trusted.Layers[1])
+	}
+}
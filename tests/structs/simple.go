@@ -0,0 +1,14 @@
+package structs
+
+import cbor "github.com/delaneyj/cbor/runtime"
+
+// Signal exercises CBOR simple values: a cbor.SimpleValue field keeps
+// the raw simple value, while bool, pointer and interface fields map
+// false/true/null/undefined onto Go values.
+type Signal struct {
+	Name  string           `cbor:"name"`
+	State cbor.SimpleValue `cbor:"state"`
+	On    bool             `cbor:"on"`
+	At    *Point           `cbor:"at,omitempty"`
+	Extra any              `cbor:"extra,omitempty"`
+}
@@ -0,0 +1,317 @@
+// Code generated by cborgen DO NOT EDIT.
+
+package structs
+
+import (
+	"io"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+// Fail the build if a generated method drifts from the runtime interfaces.
+var (
+	_ cbor.Marshaler   = (*Signal)(nil)
+	_ cbor.Unmarshaler = (*Signal)(nil)
+)
+
+func (x Signal) Msgsize() (s int) {
+	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("name") + cbor.StringPrefixSize + len(x.Name) + cbor.StringPrefixSize + len("on") + cbor.BoolSize
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *Signal) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *Signal) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *Signal) AppendCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	count := uint32(0)
+	count++
+	count++
+	count++
+	if !(x.At == nil) {
+		count++
+	}
+	if !(x.Extra == nil) {
+		count++
+	}
+	b = cbor.AppendMapHeader(b, count)
+	var err error
+	b = cbor.AppendString(b, "name")
+	b, err = cbor.AppendString(b, x.Name), nil
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "state")
+	b, err = x.State.MarshalCBOR(b)
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "on")
+	b, err = cbor.AppendBool(b, x.On), nil
+	if err != nil {
+		return b, err
+	}
+	if !(x.At == nil) {
+		b = cbor.AppendString(b, "at")
+		b, err = cbor.AppendPtrMarshaler(b, x.At)
+		if err != nil {
+			return b, err
+		}
+	}
+	if !(x.Extra == nil) {
+		b = cbor.AppendString(b, "extra")
+		b, err = cbor.AppendInterface(b, x.Extra)
+		if err != nil {
+			return b, err
+		}
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *Signal) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *Signal) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := in.ReadStringBytes(rest)
+		if err != nil {
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+		}
+		switch key {
+		case "name":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
+				}
+			}
+
+			var tmp string
+			tmp, v, err = in.ReadStringBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
+			}
+			x.Name = tmp
+		case "state":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "state", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+
+			v, err = x.State.UnmarshalCBOR(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "state", len(b)-len(v))
+			}
+		case "on":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 2); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "on", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "on", len(b)-len(v))
+				}
+			}
+
+			var tmp bool
+			tmp, v, err = cbor.ReadBoolBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "on", len(b)-len(v))
+			}
+			x.On = tmp
+		case "at":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 3); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "at", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.At = nil
+				break
+			}
+			if x.At == nil {
+				x.At = new(Point)
+			}
+			v, err = x.At.UnmarshalCBOR(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "at", len(b)-len(v))
+			}
+		case "extra":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 4); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "extra", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+
+			x.Extra, v, err = cbor.ReadInterfaceAsBytes[any](v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "extra", len(b)-len(v))
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *Signal) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "name":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			x.Name, v, err = cbor.ReadTrustedStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+		case "state":
+
+			v, err = x.State.UnmarshalCBOR(v)
+			if err != nil {
+				return b, err
+			}
+		case "on":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp bool
+			tmp, v, err = cbor.ReadBoolBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.On = tmp
+		case "at":
+
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.At = nil
+				break
+			}
+			if x.At == nil {
+				x.At = new(Point)
+			}
+			v, err = x.At.UnmarshalCBOR(v)
+			if err != nil {
+				return b, err
+			}
+		case "extra":
+
+			x.Extra, v, err = cbor.ReadInterfaceAsBytes[any](v)
+			if err != nil {
+				return b, err
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *Signal) resetCBOR() {
+	var zero Signal
+	x.Name = zero.Name
+	x.State = zero.State
+	x.On = zero.On
+	x.At = zero.At
+	x.Extra = zero.Extra
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Signal) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
@@ -0,0 +1,113 @@
+package structs
+
+import (
+	"errors"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+// signalBytes hand-builds a Signal map with the given raw items.
+func signalBytes(state, on, at, extra []byte) []byte {
+	b := cbor.AppendMapHeader(nil, 5)
+	b = cbor.AppendString(b, "name")
+	b = cbor.AppendString(b, "sig")
+	b = cbor.AppendString(b, "state")
+	b = append(b, state...)
+	b = cbor.AppendString(b, "on")
+	b = append(b, on...)
+	b = cbor.AppendString(b, "at")
+	b = append(b, at...)
+	b = cbor.AppendString(b, "extra")
+	b = append(b, extra...)
+	return b
+}
+
+func TestSignalSimpleValues(t *testing.T) {
+	undefined := []byte{0xf7}
+	decoders := []struct {
+		name string
+		fn   func(*Signal, []byte) ([]byte, error)
+	}{
+		{name: "DecodeSafe", fn: (*Signal).DecodeSafe},
+		{name: "DecodeTrusted", fn: (*Signal).DecodeTrusted},
+	}
+
+	for _, dec := range decoders {
+		t.Run(dec.name, func(t *testing.T) {
+			// simple(99), true, undefined pointer, simple(16) in an any field.
+			in := signalBytes([]byte{0xf8, 99}, []byte{0xf5}, undefined, []byte{0xf0})
+			got := Signal{At: &Point{X: 1}, Extra: "stale"}
+			rest, err := dec.fn(&got, in)
+			if err != nil {
+				t.Fatalf("%s error: %v", dec.name, err)
+			}
+			if len(rest) != 0 {
+				t.Fatalf("%s left %d bytes", dec.name, len(rest))
+			}
+			if got.State != 99 || !got.On {
+				t.Fatalf("unexpected State/On: %v/%v", got.State, got.On)
+			}
+			if got.At != nil {
+				t.Fatalf("undefined should clear pointer field, got %#v", got.At)
+			}
+			if sv, ok := got.Extra.(cbor.SimpleValue); !ok || sv != 16 {
+				t.Fatalf("expected SimpleValue(16) in Extra, got %#v", got.Extra)
+			}
+
+			// undefined in an interface field decodes to nil; the
+			// SimpleValue field keeps it as 23.
+			in = signalBytes(undefined, []byte{0xf4}, []byte{0xf6}, undefined)
+			got = Signal{}
+			if _, err := dec.fn(&got, in); err != nil {
+				t.Fatalf("%s error: %v", dec.name, err)
+			}
+			if got.State != cbor.SimpleUndefined || got.Extra != nil {
+				t.Fatalf("unexpected State/Extra: %v/%#v", got.State, got.Extra)
+			}
+		})
+	}
+}
+
+func TestSignalRoundTrip(t *testing.T) {
+	src := Signal{Name: "sig", State: 200, On: true, Extra: cbor.SimpleValue(5)}
+	b, err := src.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	var dst Signal
+	if _, err := dst.DecodeSafe(b); err != nil {
+		t.Fatalf("DecodeSafe error: %v", err)
+	}
+	if dst != src {
+		t.Fatalf("round trip mismatch:\n got %#v\nwant %#v", dst, src)
+	}
+
+	src.State = 24
+	if _, err := src.MarshalCBOR(nil); !errors.Is(err, cbor.ErrInvalidSimpleValue) {
+		t.Fatalf("expected ErrInvalidSimpleValue for reserved value, got %v", err)
+	}
+}
+
+func TestSignalSafeDecodeRejects(t *testing.T) {
+	cases := []struct {
+		name  string
+		state []byte
+		on    []byte
+	}{
+		{name: "BoolFromUndefined", state: []byte{0xf7}, on: []byte{0xf7}},
+		{name: "BoolFromNull", state: []byte{0xf7}, on: []byte{0xf6}},
+		{name: "BoolFromSimple", state: []byte{0xf7}, on: []byte{0xf8, 99}},
+		{name: "BoolFromInt", state: []byte{0xf7}, on: []byte{0x01}},
+		{name: "TwoByteBelow32", state: []byte{0xf8, 0x10}, on: []byte{0xf5}},
+		{name: "Float", state: []byte{0xf9, 0x3c, 0x00}, on: []byte{0xf5}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got Signal
+			if _, err := got.DecodeSafe(signalBytes(tc.state, tc.on, []byte{0xf6}, []byte{0xf6})); err == nil {
+				t.Fatalf("expected error, got %#v", got)
+			}
+		})
+	}
+}
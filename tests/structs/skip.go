@@ -0,0 +1,11 @@
+package structs
+
+// Credentials exercises `cbor:"-"` exclusion and the `cbor:"-,"` escape
+// for a key that is literally "-".
+type Credentials struct {
+	User     string `cbor:"user"`
+	Password string `cbor:"-"`
+	Token    string `json:"-"`
+	Dash     int    `cbor:"-,"`
+	Note     string `cbor:",omitempty"`
+}
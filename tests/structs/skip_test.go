@@ -0,0 +1,106 @@
+package structs
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+type credentialsDecoder struct {
+	name   string
+	decode func(dst *Credentials, b []byte) ([]byte, error)
+}
+
+var credentialsDecoders = []credentialsDecoder{
+	{
+		name:   "DecodeSafe",
+		decode: (*Credentials).DecodeSafe,
+	},
+	{
+		name:   "DecodeTrusted",
+		decode: (*Credentials).DecodeTrusted,
+	},
+}
+
+func TestCredentialsSkippedFields(t *testing.T) {
+	orig := &Credentials{User: "alice", Password: "hunter2", Token: "t0k", Dash: 7, Note: "n"}
+
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	if len(b) > orig.Msgsize() {
+		t.Fatalf("encoded %d bytes, Msgsize %d", len(b), orig.Msgsize())
+	}
+
+	// Excluded fields are absent and not counted in the map header; the
+	// "-," field is written under the key "-" and an empty name keeps the
+	// Go field name.
+	iv, _, err := cbor.ReadInterfaceBytes(b)
+	if err != nil {
+		t.Fatalf("ReadInterfaceBytes error: %v", err)
+	}
+	m, ok := iv.(map[string]any)
+	if !ok {
+		t.Fatalf("decoded %T, want map[string]any", iv)
+	}
+	sz, _, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		t.Fatalf("ReadMapHeaderBytes error: %v", err)
+	}
+	if int(sz) != len(m) {
+		t.Fatalf("map header = %d entries, decoded %d", sz, len(m))
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	if got, want := strings.Join(keys, ","), "-,Note,user"; got != want {
+		t.Fatalf("keys = %s, want %s", got, want)
+	}
+
+	for _, tc := range credentialsDecoders {
+		t.Run(tc.name, func(t *testing.T) {
+			var dst Credentials
+			rest, err := tc.decode(&dst, b)
+			if err != nil {
+				t.Fatalf("%s error: %v", tc.name, err)
+			}
+			if len(rest) != 0 {
+				t.Fatalf("%s leftover bytes: %d", tc.name, len(rest))
+			}
+			want := Credentials{User: "alice", Dash: 7, Note: "n"}
+			if dst != want {
+				t.Fatalf("%s mismatch: got %+v want %+v", tc.name, dst, want)
+			}
+		})
+	}
+}
+
+func TestCredentialsIgnoresExcludedKeysOnDecode(t *testing.T) {
+	// A payload that carries keys matching the excluded fields' Go names
+	// must not populate them.
+	var b []byte
+	b = cbor.AppendMapHeader(b, 3)
+	b = cbor.AppendString(b, "user")
+	b = cbor.AppendString(b, "bob")
+	b = cbor.AppendString(b, "Password")
+	b = cbor.AppendString(b, "leaked")
+	b = cbor.AppendString(b, "Token")
+	b = cbor.AppendString(b, "leaked")
+
+	for _, tc := range credentialsDecoders {
+		t.Run(tc.name, func(t *testing.T) {
+			dst := Credentials{Password: "keep", Token: "keep"}
+			if _, err := tc.decode(&dst, b); err != nil {
+				t.Fatalf("%s error: %v", tc.name, err)
+			}
+			if dst.User != "bob" || dst.Password != "keep" || dst.Token != "keep" {
+				t.Fatalf("%s mismatch: got %+v", tc.name, dst)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package structs
+
+// Sample is one element of a streamed Series.
+type Sample struct {
+	At    int64   `cbor:"at"`
+	Value float64 `cbor:"value"`
+}
+
+// Series exercises MarshalCBORStream (cborgen --stream): slice fields are
+// written as indefinite-length arrays element by element.
+type Series struct {
+	Name    string            `cbor:"name"`
+	Samples []Sample          `cbor:"samples"`
+	Refs    []*Sample         `cbor:"refs,omitempty"`
+	Labels  []string          `cbor:"labels,omitempty"`
+	Raw     []byte            `cbor:"raw"`
+	Attrs   map[string]string `cbor:"attrs"`
+	Counts  []uint32          `cbor:"counts"`
+}
@@ -0,0 +1,1144 @@
+// Code generated by cborgen DO NOT EDIT.
+
+package structs
+
+import (
+	"io"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+// Fail the build if a generated method drifts from the runtime interfaces.
+var (
+	_ cbor.Marshaler       = (*Sample)(nil)
+	_ cbor.Unmarshaler     = (*Sample)(nil)
+	_ cbor.StreamMarshaler = (*Sample)(nil)
+	_ cbor.Marshaler       = (*Series)(nil)
+	_ cbor.Unmarshaler     = (*Series)(nil)
+	_ cbor.StreamMarshaler = (*Series)(nil)
+)
+
+func (x Sample) Msgsize() (s int) {
+	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("at") + cbor.Int64Size + cbor.StringPrefixSize + len("value") + cbor.Float64Size
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *Sample) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *Sample) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *Sample) AppendCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	b = cbor.AppendMapHeader(b, uint32(2))
+	var err error
+	b = cbor.AppendString(b, "at")
+	b, err = cbor.AppendInt64(b, x.At), nil
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "value")
+	b, err = cbor.AppendFloat64(b, x.Value), nil
+	if err != nil {
+		return b, err
+	}
+
+	return b, nil
+}
+
+// MarshalCBORStream writes x to enc with the same keys as MarshalCBOR,
+// except that slice fields are written as indefinite-length arrays one
+// element at a time, so a large slice never has to be encoded in full
+// before enc can flush it.
+func (x *Sample) MarshalCBORStream(enc *cbor.Encoder) error {
+	if x == nil {
+		return enc.EncodeFunc(func(b []byte) ([]byte, error) { return cbor.AppendNil(b), nil })
+	}
+	err := enc.EncodeFunc(func(b []byte) ([]byte, error) {
+		return cbor.AppendMapHeader(b, uint32(2)), nil
+	})
+	if err != nil {
+		return err
+	}
+	err = enc.EncodeFunc(func(b []byte) ([]byte, error) {
+		var err error
+		b = cbor.AppendString(b, "at")
+		b, err = cbor.AppendInt64(b, x.At), nil
+		return b, err
+	})
+	if err != nil {
+		return err
+	}
+	err = enc.EncodeFunc(func(b []byte) ([]byte, error) {
+		var err error
+		b = cbor.AppendString(b, "value")
+		b, err = cbor.AppendFloat64(b, x.Value), nil
+		return b, err
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *Sample) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *Sample) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := in.ReadStringBytes(rest)
+		if err != nil {
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+		}
+		switch key {
+		case "at":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "at", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "at", len(b)-len(v))
+				}
+			}
+
+			var tmp int64
+			tmp, v, err = cbor.ReadInt64Bytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "at", len(b)-len(v))
+			}
+			x.At = tmp
+		case "value":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "value", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "value", len(b)-len(v))
+				}
+			}
+
+			var tmp float64
+			tmp, v, err = cbor.ReadFloat64Bytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "value", len(b)-len(v))
+			}
+			x.Value = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *Sample) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "at":
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp int64
+			tmp, v, err = cbor.ReadInt64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.At = tmp
+		case "value":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp float64
+			tmp, v, err = cbor.ReadFloat64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Value = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *Sample) resetCBOR() {
+	var zero Sample
+	x.At = zero.At
+	x.Value = zero.Value
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Sample) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x Series) Msgsize() (s int) {
+	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("name") + cbor.StringPrefixSize + len(x.Name) + cbor.StringPrefixSize + len("samples") + cbor.ArrayHeaderSize + len(x.Samples)*0 + cbor.StringPrefixSize + len("labels") + cbor.ArrayHeaderSize + len(x.Labels)*cbor.StringPrefixSize + cbor.StringPrefixSize + len("raw") + cbor.BytesPrefixSize + len(x.Raw) + cbor.StringPrefixSize + len("attrs") + cbor.MapHeaderSize + len(x.Attrs)*(cbor.StringPrefixSize+cbor.StringPrefixSize) + cbor.StringPrefixSize + len("counts") + cbor.ArrayHeaderSize + len(x.Counts)*cbor.Uint32Size
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *Series) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *Series) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *Series) AppendCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	count := uint32(0)
+	count++
+	count++
+	if !(len(x.Refs) == 0) {
+		count++
+	}
+	if !(len(x.Labels) == 0) {
+		count++
+	}
+	count++
+	count++
+	count++
+	b = cbor.AppendMapHeader(b, count)
+	var err error
+	b = cbor.AppendString(b, "name")
+	b, err = cbor.AppendString(b, x.Name), nil
+	if err != nil {
+		return b, err
+	}
+	if x.Samples == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = cbor.AppendString(b, "samples")
+		b = cbor.AppendNil(b)
+	} else {
+		b = cbor.AppendString(b, "samples")
+		b = cbor.AppendArrayHeader(b, uint32(len(x.Samples)))
+		for i := range x.Samples {
+			b, err = x.Samples[i].AppendCBOR(b)
+			if err != nil {
+				return b, err
+			}
+		}
+	}
+	if !(len(x.Refs) == 0) {
+		if x.Refs == nil && cbor.NilContainers == cbor.NilAsNull {
+			b = cbor.AppendString(b, "refs")
+			b = cbor.AppendNil(b)
+		} else {
+			b = cbor.AppendString(b, "refs")
+			b = cbor.AppendArrayHeader(b, uint32(len(x.Refs)))
+			for _, s := range x.Refs {
+				if s == nil {
+					b = cbor.AppendNil(b)
+				} else {
+					b, err = s.AppendCBOR(b)
+					if err != nil {
+						return b, err
+					}
+				}
+			}
+		}
+	}
+	if !(len(x.Labels) == 0) {
+		if x.Labels == nil && cbor.NilContainers == cbor.NilAsNull {
+			b = cbor.AppendString(b, "labels")
+			b = cbor.AppendNil(b)
+		} else {
+			b = cbor.AppendString(b, "labels")
+			b = cbor.AppendArrayHeader(b, uint32(len(x.Labels)))
+			for _, v := range x.Labels {
+				b = cbor.AppendString(b, v)
+			}
+		}
+	}
+	b = cbor.AppendString(b, "raw")
+	if x.Raw == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = cbor.AppendNil(b)
+	} else {
+		b, err = cbor.AppendBytes(b, x.Raw), nil
+		if err != nil {
+			return b, err
+		}
+	}
+	if x.Attrs == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = cbor.AppendString(b, "attrs")
+		b = cbor.AppendNil(b)
+	} else {
+		b = cbor.AppendString(b, "attrs")
+		if cbor.CanonicalMapEncode {
+			b, err = cbor.AppendMapDeterministic(b, x.Attrs, cbor.EncKeyString, cbor.EncValString)
+			if err != nil {
+				return b, err
+			}
+		} else {
+			b = cbor.AppendMapHeader(b, uint32(len(x.Attrs)))
+			for k, v := range x.Attrs {
+				b = cbor.AppendString(b, k)
+				b = cbor.AppendString(b, v)
+			}
+		}
+	}
+	if x.Counts == nil && cbor.NilContainers == cbor.NilAsNull {
+		b = cbor.AppendString(b, "counts")
+		b = cbor.AppendNil(b)
+	} else {
+		b = cbor.AppendString(b, "counts")
+		b = cbor.AppendArrayHeader(b, uint32(len(x.Counts)))
+		for _, v := range x.Counts {
+			b = cbor.AppendUint32(b, v)
+		}
+	}
+
+	return b, nil
+}
+
+// MarshalCBORStream writes x to enc with the same keys as MarshalCBOR,
+// except that slice fields are written as indefinite-length arrays one
+// element at a time, so a large slice never has to be encoded in full
+// before enc can flush it.
+func (x *Series) MarshalCBORStream(enc *cbor.Encoder) error {
+	if x == nil {
+		return enc.EncodeFunc(func(b []byte) ([]byte, error) { return cbor.AppendNil(b), nil })
+	}
+	err := enc.EncodeFunc(func(b []byte) ([]byte, error) {
+		count := uint32(0)
+		count++
+		count++
+		if !(len(x.Refs) == 0) {
+			count++
+		}
+		if !(len(x.Labels) == 0) {
+			count++
+		}
+		count++
+		count++
+		count++
+		return cbor.AppendMapHeader(b, count), nil
+	})
+	if err != nil {
+		return err
+	}
+	err = enc.EncodeFunc(func(b []byte) ([]byte, error) {
+		var err error
+		b = cbor.AppendString(b, "name")
+		b, err = cbor.AppendString(b, x.Name), nil
+		return b, err
+	})
+	if err != nil {
+		return err
+	}
+	if x.Samples == nil && cbor.NilContainers == cbor.NilAsNull {
+		err = enc.EncodeFunc(func(b []byte) ([]byte, error) {
+			b = cbor.AppendString(b, "samples")
+			return cbor.AppendNil(b), nil
+		})
+	} else {
+		err = enc.EncodeFunc(func(b []byte) ([]byte, error) {
+			b = cbor.AppendString(b, "samples")
+			return cbor.AppendArrayHeaderIndefinite(b), nil
+		})
+		if err != nil {
+			return err
+		}
+		for i := range x.Samples {
+			err = enc.EncodeFunc(func(b []byte) ([]byte, error) { return x.Samples[i].AppendCBOR(b) })
+			if err != nil {
+				return err
+			}
+		}
+		err = enc.EncodeFunc(func(b []byte) ([]byte, error) { return cbor.AppendBreak(b), nil })
+	}
+	if err != nil {
+		return err
+	}
+	if !(len(x.Refs) == 0) {
+		if x.Refs == nil && cbor.NilContainers == cbor.NilAsNull {
+			err = enc.EncodeFunc(func(b []byte) ([]byte, error) {
+				b = cbor.AppendString(b, "refs")
+				return cbor.AppendNil(b), nil
+			})
+		} else {
+			err = enc.EncodeFunc(func(b []byte) ([]byte, error) {
+				b = cbor.AppendString(b, "refs")
+				return cbor.AppendArrayHeaderIndefinite(b), nil
+			})
+			if err != nil {
+				return err
+			}
+			for i := range x.Refs {
+				err = enc.EncodeFunc(func(b []byte) ([]byte, error) { return x.Refs[i].AppendCBOR(b) })
+				if err != nil {
+					return err
+				}
+			}
+			err = enc.EncodeFunc(func(b []byte) ([]byte, error) { return cbor.AppendBreak(b), nil })
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if !(len(x.Labels) == 0) {
+		if x.Labels == nil && cbor.NilContainers == cbor.NilAsNull {
+			err = enc.EncodeFunc(func(b []byte) ([]byte, error) {
+				b = cbor.AppendString(b, "labels")
+				return cbor.AppendNil(b), nil
+			})
+		} else {
+			err = enc.EncodeFunc(func(b []byte) ([]byte, error) {
+				b = cbor.AppendString(b, "labels")
+				return cbor.AppendArrayHeaderIndefinite(b), nil
+			})
+			if err != nil {
+				return err
+			}
+			for i := range x.Labels {
+				err = enc.EncodeFunc(func(b []byte) ([]byte, error) { return cbor.AppendString(b, x.Labels[i]), nil })
+				if err != nil {
+					return err
+				}
+			}
+			err = enc.EncodeFunc(func(b []byte) ([]byte, error) { return cbor.AppendBreak(b), nil })
+		}
+		if err != nil {
+			return err
+		}
+	}
+	err = enc.EncodeFunc(func(b []byte) ([]byte, error) {
+		var err error
+		b = cbor.AppendString(b, "raw")
+		if x.Raw == nil && cbor.NilContainers == cbor.NilAsNull {
+			b = cbor.AppendNil(b)
+		} else {
+			b, err = cbor.AppendBytes(b, x.Raw), nil
+			if err != nil {
+				return b, err
+			}
+		}
+		return b, err
+	})
+	if err != nil {
+		return err
+	}
+	err = enc.EncodeFunc(func(b []byte) ([]byte, error) {
+		var err error
+		if x.Attrs == nil && cbor.NilContainers == cbor.NilAsNull {
+			b = cbor.AppendString(b, "attrs")
+			b = cbor.AppendNil(b)
+		} else {
+			b = cbor.AppendString(b, "attrs")
+			if cbor.CanonicalMapEncode {
+				b, err = cbor.AppendMapDeterministic(b, x.Attrs, cbor.EncKeyString, cbor.EncValString)
+				if err != nil {
+					return b, err
+				}
+			} else {
+				b = cbor.AppendMapHeader(b, uint32(len(x.Attrs)))
+				for k, v := range x.Attrs {
+					b = cbor.AppendString(b, k)
+					b = cbor.AppendString(b, v)
+				}
+			}
+		}
+		return b, err
+	})
+	if err != nil {
+		return err
+	}
+	if x.Counts == nil && cbor.NilContainers == cbor.NilAsNull {
+		err = enc.EncodeFunc(func(b []byte) ([]byte, error) {
+			b = cbor.AppendString(b, "counts")
+			return cbor.AppendNil(b), nil
+		})
+	} else {
+		err = enc.EncodeFunc(func(b []byte) ([]byte, error) {
+			b = cbor.AppendString(b, "counts")
+			return cbor.AppendArrayHeaderIndefinite(b), nil
+		})
+		if err != nil {
+			return err
+		}
+		for i := range x.Counts {
+			err = enc.EncodeFunc(func(b []byte) ([]byte, error) { return cbor.AppendUint32(b, x.Counts[i]), nil })
+			if err != nil {
+				return err
+			}
+		}
+		err = enc.EncodeFunc(func(b []byte) ([]byte, error) { return cbor.AppendBreak(b), nil })
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *Series) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *Series) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := in.ReadStringBytes(rest)
+		if err != nil {
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+		}
+		switch key {
+		case "name":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
+				}
+			}
+
+			var tmp string
+			tmp, v, err = in.ReadStringBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
+			}
+			x.Name = tmp
+		case "samples":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "samples", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "samples", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Samples = cbor.NullSlice(x.Samples)
+				break
+			}
+			var sz uint32
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "samples", len(b)-len(v))
+			}
+			if cap(x.Samples) >= int(sz) {
+				x.Samples = x.Samples[:sz]
+			} else {
+				x.Samples = make([]Sample, sz)
+			}
+			if sz > 0 {
+				_ = x.Samples[sz-1]
+			}
+			for iSamples := uint32(0); iSamples < sz; iSamples++ {
+				x.Samples[iSamples].resetCBOR()
+				v, err = x.Samples[iSamples].DecodeInterned(v, in)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, int(iSamples)), "samples", len(b)-len(v))
+				}
+			}
+			if indef {
+				v = v[1:] // break
+			}
+		case "refs":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 2); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "refs", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "refs", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Refs = cbor.NullSlice(x.Refs)
+				break
+			}
+			var sz uint32
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "refs", len(b)-len(v))
+			}
+			if cap(x.Refs) >= int(sz) {
+				x.Refs = x.Refs[:sz]
+			} else {
+				x.Refs = make([]*Sample, sz)
+			}
+			if sz > 0 {
+				_ = x.Refs[sz-1]
+			}
+			for iRefs := uint32(0); iRefs < sz; iRefs++ {
+				if cbor.IsNilOrUndefined(v) {
+					v = v[1:]
+					x.Refs[iRefs] = nil
+					continue
+				}
+				if x.Refs[iRefs] == nil {
+					x.Refs[iRefs] = new(Sample)
+				} else {
+					x.Refs[iRefs].resetCBOR()
+				}
+				v, err = x.Refs[iRefs].DecodeInterned(v, in)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, int(iRefs)), "refs", len(b)-len(v))
+				}
+			}
+			if indef {
+				v = v[1:] // break
+			}
+		case "labels":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 3); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "labels", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "labels", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Labels = cbor.NullSlice(x.Labels)
+				break
+			}
+			var sz uint32
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "labels", len(b)-len(v))
+			}
+			if cap(x.Labels) >= int(sz) {
+				x.Labels = x.Labels[:sz]
+			} else {
+				x.Labels = make([]string, sz)
+			}
+			if sz > 0 {
+				_ = x.Labels[sz-1]
+			}
+			for iLabels := uint32(0); iLabels < sz; iLabels++ {
+				var tmp string
+				tmp, v, err = in.ReadStringBytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, int(iLabels)), "labels", len(b)-len(v))
+				}
+				x.Labels[iLabels] = tmp
+			}
+			if indef {
+				v = v[1:] // break
+			}
+		case "raw":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 4); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "raw", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "raw", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Raw = cbor.NullSlice(x.Raw)
+				break
+			}
+			var tmp []byte
+			tmp, v, err = cbor.ReadBytesBytes(v, nil)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "raw", len(b)-len(v))
+			}
+			x.Raw = tmp
+		case "attrs":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 5); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "attrs", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "attrs", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Attrs = cbor.NullMap(x.Attrs)
+				break
+			}
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "attrs", len(b)-len(v))
+			}
+			if x.Attrs == nil && sz > 0 {
+				x.Attrs = make(map[string]string, sz)
+			} else if x.Attrs != nil {
+				clear(x.Attrs)
+			}
+			for iAttrs := uint32(0); iAttrs < sz; iAttrs++ {
+				var key string
+				key, v, err = in.ReadStringBytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "attrs", len(b)-len(v))
+				}
+				if _, dup := x.Attrs[key]; dup {
+					var skip bool
+					if v, skip, err = cbor.SkipDuplicate(v); err != nil {
+						return b, cbor.WrapDecodeError(cbor.WrapDecodeKey(err, key), "attrs", len(b)-len(v))
+					}
+					if skip {
+						continue
+					}
+				}
+				var tmp string
+				tmp, v, err = in.ReadStringBytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeKey(err, key), "attrs", len(b)-len(v))
+				}
+				x.Attrs[key] = tmp
+			}
+		case "counts":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 6); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "counts", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "counts", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Counts = cbor.NullSlice(x.Counts)
+				break
+			}
+			var sz uint32
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "counts", len(b)-len(v))
+			}
+			if cap(x.Counts) >= int(sz) {
+				x.Counts = x.Counts[:sz]
+			} else {
+				x.Counts = make([]uint32, sz)
+			}
+			if sz > 0 {
+				_ = x.Counts[sz-1]
+			}
+			for iCounts := uint32(0); iCounts < sz; iCounts++ {
+				var tmp uint32
+				tmp, v, err = cbor.ReadUint32Bytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, int(iCounts)), "counts", len(b)-len(v))
+				}
+				x.Counts[iCounts] = tmp
+			}
+			if indef {
+				v = v[1:] // break
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *Series) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "name":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			x.Name, v, err = cbor.ReadTrustedStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+		case "samples":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Samples = cbor.NullSlice(x.Samples)
+				break
+			}
+			var sz uint32
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap(x.Samples) >= int(sz) {
+				x.Samples = x.Samples[:sz]
+			} else {
+				x.Samples = make([]Sample, sz)
+			}
+			if sz > 0 {
+				_ = x.Samples[sz-1]
+			}
+			for iSamples := uint32(0); iSamples < sz; iSamples++ {
+				x.Samples[iSamples].resetCBOR()
+				v, err = x.Samples[iSamples].DecodeTrusted(v)
+				if err != nil {
+					return b, err
+				}
+			}
+			if indef {
+				v = v[1:] // break
+			}
+		case "refs":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Refs = cbor.NullSlice(x.Refs)
+				break
+			}
+			var sz uint32
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap(x.Refs) >= int(sz) {
+				x.Refs = x.Refs[:sz]
+			} else {
+				x.Refs = make([]*Sample, sz)
+			}
+			if sz > 0 {
+				_ = x.Refs[sz-1]
+			}
+			for iRefs := uint32(0); iRefs < sz; iRefs++ {
+				if cbor.IsNilOrUndefined(v) {
+					v = v[1:]
+					x.Refs[iRefs] = nil
+					continue
+				}
+				if x.Refs[iRefs] == nil {
+					x.Refs[iRefs] = new(Sample)
+				} else {
+					x.Refs[iRefs].resetCBOR()
+				}
+				v, err = x.Refs[iRefs].DecodeTrusted(v)
+				if err != nil {
+					return b, err
+				}
+			}
+			if indef {
+				v = v[1:] // break
+			}
+		case "labels":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Labels = cbor.NullSlice(x.Labels)
+				break
+			}
+			var sz uint32
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap(x.Labels) >= int(sz) {
+				x.Labels = x.Labels[:sz]
+			} else {
+				x.Labels = make([]string, sz)
+			}
+			if sz > 0 {
+				_ = x.Labels[sz-1]
+			}
+			for iLabels := uint32(0); iLabels < sz; iLabels++ {
+				var tmp string
+				tmp, v, err = cbor.ReadTrustedStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Labels[iLabels] = tmp
+			}
+			if indef {
+				v = v[1:] // break
+			}
+		case "raw":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Raw = cbor.NullSlice(x.Raw)
+				break
+			}
+			var tmp []byte
+			tmp, v, err = cbor.ReadBytesBytes(v, nil)
+			if err != nil {
+				return b, err
+			}
+			x.Raw = tmp
+		case "attrs":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Attrs = cbor.NullMap(x.Attrs)
+				break
+			}
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if x.Attrs == nil && sz > 0 {
+				x.Attrs = make(map[string]string, sz)
+			} else if x.Attrs != nil {
+				clear(x.Attrs)
+			}
+			for iAttrs := uint32(0); iAttrs < sz; iAttrs++ {
+				var key string
+				key, v, err = cbor.ReadTrustedStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				var tmp string
+				tmp, v, err = cbor.ReadTrustedStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Attrs[key] = tmp
+			}
+		case "counts":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Counts = cbor.NullSlice(x.Counts)
+				break
+			}
+			var sz uint32
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap(x.Counts) >= int(sz) {
+				x.Counts = x.Counts[:sz]
+			} else {
+				x.Counts = make([]uint32, sz)
+			}
+			if sz > 0 {
+				_ = x.Counts[sz-1]
+			}
+			for iCounts := uint32(0); iCounts < sz; iCounts++ {
+				var tmp uint32
+				tmp, v, err = cbor.ReadUint32Bytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Counts[iCounts] = tmp
+			}
+			if indef {
+				v = v[1:] // break
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *Series) resetCBOR() {
+	var zero Series
+	x.Name = zero.Name
+	x.Samples = x.Samples[:0]
+	x.Refs = x.Refs[:0]
+	x.Labels = x.Labels[:0]
+	x.Raw = x.Raw[:0]
+	clear(x.Attrs)
+	x.Counts = x.Counts[:0]
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Series) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
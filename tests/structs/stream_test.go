@@ -0,0 +1,136 @@
+package structs
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+type seriesDecoder struct {
+	name   string
+	decode func(dst *Series, b []byte) ([]byte, error)
+}
+
+var seriesDecoders = []seriesDecoder{
+	{
+		name:   "DecodeSafe",
+		decode: (*Series).DecodeSafe,
+	},
+	{
+		name:   "DecodeTrusted",
+		decode: (*Series).DecodeTrusted,
+	},
+}
+
+// maxWriter records the largest single Write it receives.
+type maxWriter struct {
+	bytes.Buffer
+	max int
+}
+
+func (w *maxWriter) Write(p []byte) (int, error) {
+	w.max = max(w.max, len(p))
+	return w.Buffer.Write(p)
+}
+
+func newSeries(n int) *Series {
+	s := &Series{
+		Name:   "cpu",
+		Refs:   []*Sample{{At: 1, Value: 0.5}, nil},
+		Labels: []string{"host=a", "dc=x"},
+		Raw:    []byte{1, 2, 3},
+		Attrs:  map[string]string{"unit": "%"},
+		Counts: []uint32{1, 1 << 20},
+	}
+	for i := range n {
+		s.Samples = append(s.Samples, Sample{At: int64(i), Value: float64(i) / 3})
+	}
+	return s
+}
+
+func TestSeriesMarshalCBORStreamRoundTrip(t *testing.T) {
+	orig := newSeries(100)
+
+	var w maxWriter
+	enc := cbor.NewEncoder(&w)
+	if err := orig.MarshalCBORStream(enc); err != nil {
+		t.Fatalf("MarshalCBORStream error: %v", err)
+	}
+	if err := enc.Flush(); err != nil {
+		t.Fatalf("Flush error: %v", err)
+	}
+	b := w.Bytes()
+
+	if err := cbor.ValidateDocument(b); err != nil {
+		t.Fatalf("stream output not well-formed: %v", err)
+	}
+	want := cbor.AppendArrayHeaderIndefinite(cbor.AppendString(nil, "samples"))
+	if !bytes.Contains(b, want) {
+		t.Fatalf("samples not written as an indefinite-length array: % x", b[:32])
+	}
+
+	for _, tc := range seriesDecoders {
+		t.Run(tc.name, func(t *testing.T) {
+			var dst Series
+			rest, err := tc.decode(&dst, b)
+			if err != nil {
+				t.Fatalf("%s error: %v", tc.name, err)
+			}
+			if len(rest) != 0 {
+				t.Fatalf("%s leftover bytes: %d", tc.name, len(rest))
+			}
+			if !reflect.DeepEqual(dst, *orig) {
+				t.Fatalf("%s mismatch: got %+v want %+v", tc.name, dst, *orig)
+			}
+		})
+	}
+
+	// Omitted slices are not written at all.
+	short := &Series{Name: "empty"}
+	w.Reset()
+	if err := short.MarshalCBORStream(enc); err != nil {
+		t.Fatalf("MarshalCBORStream error: %v", err)
+	}
+	if err := enc.Flush(); err != nil {
+		t.Fatalf("Flush error: %v", err)
+	}
+	var dst Series
+	if _, err := dst.DecodeSafe(w.Bytes()); err != nil {
+		t.Fatalf("DecodeSafe error: %v", err)
+	}
+	if dst.Name != "empty" || dst.Refs != nil || dst.Labels != nil || len(dst.Samples) != 0 {
+		t.Fatalf("mismatch: got %+v", dst)
+	}
+}
+
+func TestSeriesMarshalCBORStreamBoundedBuffer(t *testing.T) {
+	// ~1 MiB of samples must reach the writer in chunks no larger than
+	// the Encoder's flush threshold plus one element.
+	orig := newSeries(64 << 10)
+	full, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+
+	var w maxWriter
+	enc := cbor.NewEncoder(&w)
+	if err := orig.MarshalCBORStream(enc); err != nil {
+		t.Fatalf("MarshalCBORStream error: %v", err)
+	}
+	if err := enc.Flush(); err != nil {
+		t.Fatalf("Flush error: %v", err)
+	}
+	if w.max > 65<<10 || w.max >= len(full)/4 {
+		t.Fatalf("largest write %d bytes for a %d byte value", w.max, len(full))
+	}
+
+	var dst Series
+	if _, err := dst.DecodeTrusted(w.Bytes()); err != nil {
+		t.Fatalf("DecodeTrusted error: %v", err)
+	}
+	if len(dst.Samples) != len(orig.Samples) || dst.Samples[len(dst.Samples)-1] != orig.Samples[len(orig.Samples)-1] {
+		t.Fatalf("decoded %d samples, want %d", len(dst.Samples), len(orig.Samples))
+	}
+}
@@ -0,0 +1,19 @@
+package structs
+
+// LegacyQuote mirrors a feed that sends its numbers and flags as text.
+type LegacyQuote struct {
+	Symbol string  `cbor:"symbol"`
+	Price  float64 `cbor:"price,string"`
+	Ratio  float32 `cbor:"ratio,string,omitempty"`
+	Change int32   `cbor:"change,string"`
+	Volume uint64  `cbor:"volume,string,omitempty"`
+	Lot    uint8   `cbor:"lot,string"`
+	Halted bool    `cbor:"halted,string"`
+}
+
+// LegacySwitch mirrors a peer that sends its flags as the integers 0 and 1.
+type LegacySwitch struct {
+	Name  string `cbor:"name"`
+	On    bool   `cbor:"on,boolasint"`
+	Armed bool   `cbor:"armed,boolasint,omitempty"`
+}
@@ -0,0 +1,222 @@
+package structs
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+var legacyQuoteDecoders = []struct {
+	name   string
+	decode func(dst *LegacyQuote, b []byte) ([]byte, error)
+}{
+	{"DecodeSafe", (*LegacyQuote).DecodeSafe},
+	{"DecodeTrusted", (*LegacyQuote).DecodeTrusted},
+}
+
+func TestLegacyQuoteEncodesText(t *testing.T) {
+	in := LegacyQuote{Symbol: "ACME", Price: 12.5, Ratio: 0.1, Change: -3, Volume: math.MaxUint64, Lot: 100, Halted: true}
+	b, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b) > in.Msgsize() {
+		t.Fatalf("encoded %d bytes, Msgsize %d", len(b), in.Msgsize())
+	}
+	var want []byte
+	want = cbor.AppendMapHeader(want, 7)
+	for _, kv := range [][2]string{
+		{"symbol", "ACME"}, {"price", "12.5"}, {"ratio", "0.1"}, {"change", "-3"},
+		{"volume", "18446744073709551615"}, {"lot", "100"}, {"halted", "true"},
+	} {
+		want = cbor.AppendString(want, kv[0])
+		want = cbor.AppendString(want, kv[1])
+	}
+	if !bytes.Equal(b, want) {
+		t.Fatalf("encoding = % x\nwant       % x", b, want)
+	}
+
+	for _, d := range legacyQuoteDecoders {
+		var out LegacyQuote
+		rest, err := d.decode(&out, b)
+		if err != nil || len(rest) != 0 {
+			t.Fatalf("%s: err=%v rest=%d", d.name, err, len(rest))
+		}
+		if out != in {
+			t.Fatalf("%s: got %+v, want %+v", d.name, out, in)
+		}
+	}
+}
+
+func TestLegacyQuoteSpecialFloats(t *testing.T) {
+	for _, f := range []float64{math.Inf(1), math.Inf(-1), math.MaxFloat64, -2.2250738585072014e-308} {
+		in := LegacyQuote{Price: f}
+		b, err := in.MarshalCBOR(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(b) > in.Msgsize() {
+			t.Fatalf("%v: encoded %d bytes, Msgsize %d", f, len(b), in.Msgsize())
+		}
+		var out LegacyQuote
+		if _, err := out.DecodeSafe(b); err != nil || out.Price != f {
+			t.Fatalf("%v: got %v, %v", f, out.Price, err)
+		}
+	}
+}
+
+func TestLegacyQuoteParseErrors(t *testing.T) {
+	field := func(key string, v func([]byte) []byte) []byte {
+		b := cbor.AppendMapHeader(nil, 1)
+		return v(cbor.AppendString(b, key))
+	}
+	text := func(s string) func([]byte) []byte {
+		return func(b []byte) []byte { return cbor.AppendString(b, s) }
+	}
+	cases := map[string][]byte{
+		"not a number": field("price", text("twelve")),
+		"int8 range":   field("lot", text("256")),
+		"fraction":     field("change", text("1.5")),
+		"int32 range":  field("change", text("2147483648")),
+		"negative":     field("volume", text("-1")),
+		"bool":         field("halted", text("yes")),
+		"not text":     field("price", func(b []byte) []byte { return cbor.AppendFloat64(b, 1) }),
+	}
+	for name, b := range cases {
+		for _, d := range legacyQuoteDecoders {
+			var out LegacyQuote
+			if _, err := d.decode(&out, b); err == nil {
+				t.Fatalf("%s %s: decoded without error: %+v", name, d.name, out)
+			}
+		}
+	}
+
+	var out LegacyQuote
+	_, err := out.DecodeSafe(cases["int32 range"])
+	var de *cbor.DecodeError
+	var se cbor.StringNumberError
+	if !errors.As(err, &de) || de.Path != "change" || !errors.As(err, &se) || se.Value != "2147483648" || se.Type != "int32" {
+		t.Fatalf("error = %v, want StringNumberError at change", err)
+	}
+}
+
+func TestLegacyQuoteReflectMatchesGenerated(t *testing.T) {
+	type plain LegacyQuote // no generated methods
+	in := LegacyQuote{Symbol: "X", Price: -0.5, Change: 7, Lot: 1, Halted: false}
+	want, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := cbor.Marshal(plain(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("Marshal = % x\nwant      % x", got, want)
+	}
+	var out plain
+	if err := cbor.Unmarshal(want, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(LegacyQuote(out), in) {
+		t.Fatalf("Unmarshal = %+v, want %+v", out, in)
+	}
+}
+
+var legacySwitchDecoders = []struct {
+	name   string
+	decode func(dst *LegacySwitch, b []byte) ([]byte, error)
+}{
+	{"DecodeSafe", (*LegacySwitch).DecodeSafe},
+	{"DecodeTrusted", (*LegacySwitch).DecodeTrusted},
+}
+
+func TestLegacySwitchBoolAsInt(t *testing.T) {
+	in := LegacySwitch{Name: "pump", On: true}
+	b, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := cbor.AppendMapHeader(nil, 2)
+	want = cbor.AppendString(want, "name")
+	want = cbor.AppendString(want, "pump")
+	want = cbor.AppendString(want, "on")
+	want = cbor.AppendInt(want, 1)
+	if !bytes.Equal(b, want) {
+		t.Fatalf("encoding = % x\nwant       % x", b, want)
+	}
+
+	// 0, 1, true and false all decode; 1 in a long head too.
+	payload := func(v ...byte) []byte {
+		b := cbor.AppendMapHeader(nil, 1)
+		return append(cbor.AppendString(b, "armed"), v...)
+	}
+	cases := []struct {
+		in   []byte
+		want bool
+	}{
+		{payload(0x00), false},
+		{payload(0x01), true},
+		{payload(0xf4), false},
+		{payload(0xf5), true},
+		{payload(0x18, 0x01), true},
+	}
+	for _, tc := range cases {
+		for _, d := range legacySwitchDecoders {
+			out := LegacySwitch{Armed: !tc.want}
+			if _, err := d.decode(&out, tc.in); err != nil || out.Armed != tc.want {
+				t.Fatalf("%s % x: got %v, %v", d.name, tc.in, out.Armed, err)
+			}
+		}
+	}
+}
+
+func TestLegacySwitchBoolAsIntErrors(t *testing.T) {
+	payload := func(v ...byte) []byte {
+		b := cbor.AppendMapHeader(nil, 1)
+		return append(cbor.AppendString(b, "on"), v...)
+	}
+	for _, in := range [][]byte{payload(0x02), payload(0x20), payload(0x61, '1'), payload(0xf6)} {
+		for _, d := range legacySwitchDecoders {
+			var out LegacySwitch
+			if _, err := d.decode(&out, in); err == nil {
+				t.Fatalf("%s % x: decoded without error: %+v", d.name, in, out)
+			}
+		}
+	}
+
+	var out LegacySwitch
+	_, err := out.DecodeSafe(payload(0x02))
+	var de *cbor.DecodeError
+	var be cbor.BoolIntError
+	if !errors.As(err, &de) || de.Path != "on" || !errors.As(err, &be) || be.Value != 2 {
+		t.Fatalf("error = %v, want BoolIntError at on", err)
+	}
+}
+
+func TestLegacySwitchReflectMatchesGenerated(t *testing.T) {
+	type plain LegacySwitch // no generated methods
+	in := LegacySwitch{Name: "fan", On: false, Armed: true}
+	want, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := cbor.Marshal(plain(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("Marshal = % x\nwant      % x", got, want)
+	}
+	var out plain
+	if err := cbor.Unmarshal(want, &out); err != nil {
+		t.Fatal(err)
+	}
+	if LegacySwitch(out) != in {
+		t.Fatalf("Unmarshal = %+v, want %+v", out, in)
+	}
+}
@@ -0,0 +1,41 @@
+package structs
+
+import "time"
+
+// Lease exercises struct tags whose options appear in any order, with
+// empty names and empty options, and the unit option on durations.
+type Lease struct {
+	Holder string        `cbor:",omitempty"`
+	TTL    time.Duration `cbor:"ttl,unit=ms,omitempty"`
+	Renew  time.Duration `cbor:"renew,,unit=s"`
+	Ref    string        `cbor:",tag=32,omitempty"`
+	Seq    int64         `cbor:"1,omitzero,keyasint"`
+}
+
+// Heartbeat exercises tag=1 on time.Time fields, with and without float.
+type Heartbeat struct {
+	At   time.Time `cbor:"at,tag=1,float"`
+	Seen time.Time `cbor:"seen,tag=1"`
+}
+
+// AuditEntry exercises tag=0 on a time.Time field, which keeps the
+// offset the time was recorded at.
+type AuditEntry struct {
+	At    time.Time `cbor:"at,tag=0"`
+	Actor string    `cbor:"actor"`
+}
+
+// Shift mixes untagged time.Time fields, which follow
+// cbor.DefaultTimeFormat, with tagged ones, which keep their form.
+type Shift struct {
+	Start  time.Time `cbor:"start"`
+	Seen   time.Time `cbor:"seen,tag=1"`
+	Logged time.Time `cbor:"logged,tag=0"`
+}
+
+// Ticket exercises the set option on map[string]struct{} fields.
+type Ticket struct {
+	ID       uint64              `cbor:"id"`
+	Labels   map[string]struct{} `cbor:"labels,set"`
+	Watchers map[string]struct{} `cbor:"watchers,set,omitempty"`
+}
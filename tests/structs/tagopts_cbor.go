@@ -0,0 +1,1201 @@
+// Code generated by cborgen DO NOT EDIT.
+
+package structs
+
+import (
+	"io"
+	"time"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+// Fail the build if a generated method drifts from the runtime interfaces.
+var (
+	_ cbor.Marshaler   = (*Lease)(nil)
+	_ cbor.Unmarshaler = (*Lease)(nil)
+	_ cbor.Marshaler   = (*Heartbeat)(nil)
+	_ cbor.Unmarshaler = (*Heartbeat)(nil)
+	_ cbor.Marshaler   = (*AuditEntry)(nil)
+	_ cbor.Unmarshaler = (*AuditEntry)(nil)
+	_ cbor.Marshaler   = (*Shift)(nil)
+	_ cbor.Unmarshaler = (*Shift)(nil)
+	_ cbor.Marshaler   = (*Ticket)(nil)
+	_ cbor.Unmarshaler = (*Ticket)(nil)
+)
+
+func (x Lease) Msgsize() (s int) {
+	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("Holder") + cbor.StringPrefixSize + len(x.Holder) + cbor.StringPrefixSize + len("ttl") + cbor.DurationSize + cbor.StringPrefixSize + len("renew") + cbor.DurationSize + cbor.StringPrefixSize + len("Ref") + cbor.StringPrefixSize + len(x.Ref) + cbor.TagSize + cbor.StringPrefixSize + len("1") + cbor.Int64Size
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *Lease) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *Lease) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *Lease) AppendCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	count := uint32(0)
+	if !(x.Holder == "") {
+		count++
+	}
+	if !(x.TTL == 0) {
+		count++
+	}
+	count++
+	if !(x.Ref == "") {
+		count++
+	}
+	if !(x.Seq == 0) {
+		count++
+	}
+	b = cbor.AppendMapHeader(b, count)
+	var err error
+	if !(x.Holder == "") {
+		b = cbor.AppendString(b, "Holder")
+		b, err = cbor.AppendString(b, x.Holder), nil
+		if err != nil {
+			return b, err
+		}
+	}
+	if !(x.TTL == 0) {
+		b = cbor.AppendString(b, "ttl")
+		b, err = cbor.AppendDurationUnit(b, x.TTL, time.Millisecond), nil
+		if err != nil {
+			return b, err
+		}
+	}
+	b = cbor.AppendString(b, "renew")
+	b, err = cbor.AppendDurationUnit(b, x.Renew, time.Second), nil
+	if err != nil {
+		return b, err
+	}
+	if !(x.Ref == "") {
+		b = cbor.AppendString(b, "Ref")
+		b, err = cbor.AppendURI(b, x.Ref), nil
+		if err != nil {
+			return b, err
+		}
+	}
+	if !(x.Seq == 0) {
+		b = cbor.AppendInt64(b, 1)
+		b, err = cbor.AppendInt64(b, x.Seq), nil
+		if err != nil {
+			return b, err
+		}
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *Lease) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *Lease) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
+	for i := uint32(0); i < sz; i++ {
+		if t := cbor.NextType(rest); t == cbor.UintType || t == cbor.IntType {
+			ikey, fits, v, err := cbor.ReadIntKeyBytes(rest)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+			}
+			if !fits {
+				// No field has a key outside the int64 range.
+				if rest, err = cbor.Skip(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "", len(b)-len(v))
+				}
+				continue
+			}
+			switch ikey {
+			case 1:
+				if o, skip, err := cbor.DuplicateField(v, seen[:], 4); skip || err != nil {
+					if err != nil {
+						return b, cbor.WrapDecodeError(err, "1", len(b)-len(v))
+					}
+					v = o
+					break
+				}
+				if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+					if v, err = cbor.UntagBytes(v); err != nil {
+						return b, cbor.WrapDecodeError(err, "1", len(b)-len(v))
+					}
+				}
+
+				var tmp int64
+				tmp, v, err = cbor.ReadInt64Bytes(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "1", len(b)-len(v))
+				}
+				x.Seq = tmp
+			default:
+				v, err = cbor.Skip(v)
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "", len(b)-len(v))
+				}
+			}
+			rest = v
+			continue
+		}
+		key, v, err := in.ReadStringBytes(rest)
+		if err != nil {
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+		}
+		switch key {
+		case "Holder":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "Holder", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "Holder", len(b)-len(v))
+				}
+			}
+
+			var tmp string
+			tmp, v, err = in.ReadStringBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "Holder", len(b)-len(v))
+			}
+			x.Holder = tmp
+		case "ttl":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "ttl", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "ttl", len(b)-len(v))
+				}
+			}
+
+			var tmp time.Duration
+			tmp, v, err = cbor.ReadDurationUnitBytes(v, time.Millisecond)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "ttl", len(b)-len(v))
+			}
+			x.TTL = tmp
+		case "renew":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 2); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "renew", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "renew", len(b)-len(v))
+				}
+			}
+
+			var tmp time.Duration
+			tmp, v, err = cbor.ReadDurationUnitBytes(v, time.Second)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "renew", len(b)-len(v))
+			}
+			x.Renew = tmp
+		case "Ref":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 3); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "Ref", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+
+			var tmp string
+			tmp, v, err = cbor.ReadValidURIStringBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "Ref", len(b)-len(v))
+			}
+			x.Ref = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *Lease) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		if t := cbor.NextType(rest); t == cbor.UintType || t == cbor.IntType {
+			ikey, fits, v, err := cbor.ReadIntKeyBytes(rest)
+			if err != nil {
+				return b, err
+			}
+			if !fits {
+				if rest, err = cbor.Skip(v); err != nil {
+					return b, err
+				}
+				continue
+			}
+			switch ikey {
+			case 1:
+				if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+					if v, err = cbor.UntagBytes(v); err != nil {
+						return b, err
+					}
+				}
+
+				var tmp int64
+				tmp, v, err = cbor.ReadInt64Bytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Seq = tmp
+			default:
+				v, err = cbor.Skip(v)
+				if err != nil {
+					return b, err
+				}
+			}
+			rest = v
+			continue
+		}
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "Holder":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			x.Holder, v, err = cbor.ReadTrustedStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+		case "ttl":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp time.Duration
+			tmp, v, err = cbor.ReadDurationUnitBytes(v, time.Millisecond)
+			if err != nil {
+				return b, err
+			}
+			x.TTL = tmp
+		case "renew":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp time.Duration
+			tmp, v, err = cbor.ReadDurationUnitBytes(v, time.Second)
+			if err != nil {
+				return b, err
+			}
+			x.Renew = tmp
+		case "Ref":
+
+			var tmp string
+			tmp, v, err = cbor.ReadURIStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Ref = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *Lease) resetCBOR() {
+	var zero Lease
+	x.Holder = zero.Holder
+	x.TTL = zero.TTL
+	x.Renew = zero.Renew
+	x.Ref = zero.Ref
+	x.Seq = zero.Seq
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Lease) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x Heartbeat) Msgsize() (s int) {
+	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("at") + cbor.TimeSize + cbor.TagSize + cbor.StringPrefixSize + len("seen") + cbor.TimeSize + cbor.TagSize
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *Heartbeat) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *Heartbeat) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *Heartbeat) AppendCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	b = cbor.AppendMapHeader(b, uint32(2))
+	var err error
+	b = cbor.AppendString(b, "at")
+	b, err = cbor.AppendTimeFloat(b, x.At), nil
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "seen")
+	b, err = cbor.AppendEpochTime(b, x.Seen), nil
+	if err != nil {
+		return b, err
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *Heartbeat) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *Heartbeat) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := in.ReadStringBytes(rest)
+		if err != nil {
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+		}
+		switch key {
+		case "at":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "at", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+
+			var tmp time.Time
+			tmp, v, err = cbor.ReadAnyTimeBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "at", len(b)-len(v))
+			}
+			x.At = tmp
+		case "seen":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "seen", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+
+			var tmp time.Time
+			tmp, v, err = cbor.ReadAnyTimeBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "seen", len(b)-len(v))
+			}
+			x.Seen = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *Heartbeat) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "at":
+
+			var tmp time.Time
+			tmp, v, err = cbor.ReadAnyTimeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.At = tmp
+		case "seen":
+
+			var tmp time.Time
+			tmp, v, err = cbor.ReadAnyTimeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Seen = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *Heartbeat) resetCBOR() {
+	var zero Heartbeat
+	x.At = zero.At
+	x.Seen = zero.Seen
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Heartbeat) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x AuditEntry) Msgsize() (s int) {
+	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("at") + cbor.TimeSize + cbor.TagSize + cbor.StringPrefixSize + len("actor") + cbor.StringPrefixSize + len(x.Actor)
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *AuditEntry) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *AuditEntry) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *AuditEntry) AppendCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	b = cbor.AppendMapHeader(b, uint32(2))
+	var err error
+	b = cbor.AppendString(b, "at")
+	b, err = cbor.AppendRFC3339Time(b, x.At), nil
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "actor")
+	b, err = cbor.AppendString(b, x.Actor), nil
+	if err != nil {
+		return b, err
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *AuditEntry) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *AuditEntry) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := in.ReadStringBytes(rest)
+		if err != nil {
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+		}
+		switch key {
+		case "at":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "at", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+
+			var tmp time.Time
+			tmp, v, err = cbor.ReadAnyTimeBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "at", len(b)-len(v))
+			}
+			x.At = tmp
+		case "actor":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "actor", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "actor", len(b)-len(v))
+				}
+			}
+
+			var tmp string
+			tmp, v, err = in.ReadStringBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "actor", len(b)-len(v))
+			}
+			x.Actor = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *AuditEntry) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "at":
+
+			var tmp time.Time
+			tmp, v, err = cbor.ReadAnyTimeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.At = tmp
+		case "actor":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			x.Actor, v, err = cbor.ReadTrustedStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *AuditEntry) resetCBOR() {
+	var zero AuditEntry
+	x.At = zero.At
+	x.Actor = zero.Actor
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *AuditEntry) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x Shift) Msgsize() (s int) {
+	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("start") + cbor.TimeSize + cbor.StringPrefixSize + len("seen") + cbor.TimeSize + cbor.TagSize + cbor.StringPrefixSize + len("logged") + cbor.TimeSize + cbor.TagSize
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *Shift) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *Shift) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *Shift) AppendCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	b = cbor.AppendMapHeader(b, uint32(3))
+	var err error
+	b = cbor.AppendString(b, "start")
+	b, err = cbor.AppendTime(b, x.Start), nil
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "seen")
+	b, err = cbor.AppendEpochTime(b, x.Seen), nil
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "logged")
+	b, err = cbor.AppendRFC3339Time(b, x.Logged), nil
+	if err != nil {
+		return b, err
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *Shift) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *Shift) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := in.ReadStringBytes(rest)
+		if err != nil {
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+		}
+		switch key {
+		case "start":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "start", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+
+			var tmp time.Time
+			tmp, v, err = cbor.ReadAnyTimeBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "start", len(b)-len(v))
+			}
+			x.Start = tmp
+		case "seen":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "seen", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+
+			var tmp time.Time
+			tmp, v, err = cbor.ReadAnyTimeBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "seen", len(b)-len(v))
+			}
+			x.Seen = tmp
+		case "logged":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 2); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "logged", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+
+			var tmp time.Time
+			tmp, v, err = cbor.ReadAnyTimeBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "logged", len(b)-len(v))
+			}
+			x.Logged = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *Shift) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "start":
+
+			var tmp time.Time
+			tmp, v, err = cbor.ReadAnyTimeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Start = tmp
+		case "seen":
+
+			var tmp time.Time
+			tmp, v, err = cbor.ReadAnyTimeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Seen = tmp
+		case "logged":
+
+			var tmp time.Time
+			tmp, v, err = cbor.ReadAnyTimeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Logged = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *Shift) resetCBOR() {
+	var zero Shift
+	x.Start = zero.Start
+	x.Seen = zero.Seen
+	x.Logged = zero.Logged
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Shift) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x Ticket) Msgsize() (s int) {
+	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("id") + cbor.Uint64Size + cbor.StringPrefixSize + len("labels") + cbor.ArrayHeaderSize + len(x.Labels)*cbor.StringPrefixSize + cbor.StringPrefixSize + len("watchers") + cbor.ArrayHeaderSize + len(x.Watchers)*cbor.StringPrefixSize
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *Ticket) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *Ticket) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *Ticket) AppendCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	count := uint32(0)
+	count++
+	count++
+	if !(len(x.Watchers) == 0) {
+		count++
+	}
+	b = cbor.AppendMapHeader(b, count)
+	var err error
+	b = cbor.AppendString(b, "id")
+	b, err = cbor.AppendUint64(b, x.ID), nil
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "labels")
+	b, err = cbor.AppendStringSet(b, x.Labels), nil
+	if err != nil {
+		return b, err
+	}
+	if !(len(x.Watchers) == 0) {
+		b = cbor.AppendString(b, "watchers")
+		b, err = cbor.AppendStringSet(b, x.Watchers), nil
+		if err != nil {
+			return b, err
+		}
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *Ticket) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *Ticket) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := in.ReadStringBytes(rest)
+		if err != nil {
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+		}
+		switch key {
+		case "id":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "id", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "id", len(b)-len(v))
+				}
+			}
+
+			var tmp uint64
+			tmp, v, err = cbor.ReadUint64Bytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "id", len(b)-len(v))
+			}
+			x.ID = tmp
+		case "labels":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "labels", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "labels", len(b)-len(v))
+				}
+			}
+			x.Labels, v, err = cbor.ReadStringSetBytes(v, x.Labels, in)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "labels", len(b)-len(v))
+			}
+		case "watchers":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 2); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "watchers", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "watchers", len(b)-len(v))
+				}
+			}
+			x.Watchers, v, err = cbor.ReadStringSetBytes(v, x.Watchers, in)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "watchers", len(b)-len(v))
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *Ticket) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "id":
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp uint64
+			tmp, v, err = cbor.ReadUint64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.ID = tmp
+		case "labels":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			x.Labels, v, err = cbor.ReadStringSetBytes(v, x.Labels, nil)
+			if err != nil {
+				return b, err
+			}
+		case "watchers":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			x.Watchers, v, err = cbor.ReadStringSetBytes(v, x.Watchers, nil)
+			if err != nil {
+				return b, err
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *Ticket) resetCBOR() {
+	var zero Ticket
+	x.ID = zero.ID
+	clear(x.Labels)
+	clear(x.Watchers)
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Ticket) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
@@ -0,0 +1,346 @@
+package structs
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+func TestLeaseTagOptions(t *testing.T) {
+	in := Lease{Holder: "ada", TTL: 1500 * time.Millisecond, Renew: 90 * time.Second, Seq: 3}
+	b, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+
+	// Empty names keep the Go name; durations are counted in their unit.
+	want := cbor.AppendMapHeader(nil, 4)
+	want = cbor.AppendString(want, "Holder")
+	want = cbor.AppendString(want, "ada")
+	want = cbor.AppendString(want, "ttl")
+	want = cbor.AppendInt64(want, 1500)
+	want = cbor.AppendString(want, "renew")
+	want = cbor.AppendInt64(want, 90)
+	want = cbor.AppendInt64(want, 1)
+	want = cbor.AppendInt64(want, 3)
+	if !bytes.Equal(b, want) {
+		t.Fatalf("encoded %x, want %x", b, want)
+	}
+
+	var out Lease
+	if _, err := out.UnmarshalCBOR(b); err != nil {
+		t.Fatalf("UnmarshalCBOR error: %v", err)
+	}
+	if out != in {
+		t.Fatalf("decoded %+v, want %+v", out, in)
+	}
+}
+
+func TestLeaseDurationOverflow(t *testing.T) {
+	b := cbor.AppendMapHeader(nil, 1)
+	b = cbor.AppendString(b, "renew")
+	b = cbor.AppendInt64(b, 1<<40)
+	var out Lease
+	var overflow cbor.IntOverflow
+	if _, err := out.UnmarshalCBOR(b); !errors.As(err, &overflow) {
+		t.Fatalf("error = %v, want IntOverflow", err)
+	}
+}
+
+func TestHeartbeatFloatTime(t *testing.T) {
+	in := Heartbeat{At: time.Unix(1700000000, 0), Seen: time.Unix(1700000000, 0)}
+	b, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+
+	// float writes whole seconds as a float too; plain tag=1 uses an int.
+	want := cbor.AppendMapHeader(nil, 2)
+	want = cbor.AppendString(want, "at")
+	want = cbor.AppendTag(want, 1)
+	want = cbor.AppendFloat64(want, 1700000000)
+	want = cbor.AppendString(want, "seen")
+	want = cbor.AppendTag(want, 1)
+	want = cbor.AppendInt64(want, 1700000000)
+	if !bytes.Equal(b, want) {
+		t.Fatalf("encoded %x, want %x", b, want)
+	}
+}
+
+func TestHeartbeatFloatTimeRoundTrip(t *testing.T) {
+	tests := []struct {
+		at  time.Time
+		tol time.Duration
+	}{
+		// Within about 97 days of 1970 every nanosecond survives.
+		{time.Unix(1e6, 123456789), 0},
+		{time.Unix(-1e6, 987654321), 0},
+		{time.Unix(1700000000, 500e6), 0},
+		// A present-day time is off by at most half a float64 step.
+		{time.Unix(1700000000, 123456789), 120 * time.Nanosecond},
+		// Far-future dates lose more of the fraction.
+		{time.Date(9999, 12, 31, 23, 59, 59, 999999999, time.UTC), 4 * time.Microsecond},
+	}
+	for _, tc := range tests {
+		in := Heartbeat{At: tc.at}
+		b, err := in.MarshalCBOR(nil)
+		if err != nil {
+			t.Fatalf("MarshalCBOR error: %v", err)
+		}
+		var out Heartbeat
+		if _, err := out.UnmarshalCBOR(b); err != nil {
+			t.Fatalf("UnmarshalCBOR error: %v", err)
+		}
+		if d := out.At.Sub(tc.at).Abs(); d > tc.tol {
+			t.Errorf("%v decoded as %v, off by %v, want at most %v", tc.at, out.At, d, tc.tol)
+		}
+		// The decoded time is the one the float stands for.
+		again, err := out.MarshalCBOR(nil)
+		if err != nil {
+			t.Fatalf("MarshalCBOR error: %v", err)
+		}
+		if !bytes.Equal(again, b) {
+			t.Errorf("%v re-encoded as %x, want %x", tc.at, again, b)
+		}
+	}
+}
+
+func TestAuditEntryKeepsOffset(t *testing.T) {
+	zones := []*time.Location{
+		time.UTC,
+		time.FixedZone("PST", -8*3600),
+		time.FixedZone("IST", 5*3600+30*60),
+	}
+	for _, loc := range zones {
+		in := AuditEntry{At: time.Date(2024, 3, 10, 9, 15, 0, 500e6, loc), Actor: "ops"}
+		b, err := in.MarshalCBOR(nil)
+		if err != nil {
+			t.Fatalf("MarshalCBOR error: %v", err)
+		}
+		want := cbor.AppendMapHeader(nil, 2)
+		want = cbor.AppendString(want, "at")
+		want = cbor.AppendTag(want, 0)
+		want = cbor.AppendString(want, in.At.Format(time.RFC3339Nano))
+		want = cbor.AppendString(want, "actor")
+		want = cbor.AppendString(want, "ops")
+		if !bytes.Equal(b, want) {
+			t.Fatalf("%s: encoded %x, want %x", loc, b, want)
+		}
+		for _, dec := range []func(*AuditEntry, []byte) ([]byte, error){
+			(*AuditEntry).DecodeSafe,
+			(*AuditEntry).DecodeTrusted,
+		} {
+			var out AuditEntry
+			if _, err := dec(&out, b); err != nil {
+				t.Fatalf("%s: decode error: %v", loc, err)
+			}
+			// The offset survives; the zone name does not.
+			_, wantOff := in.At.Zone()
+			_, gotOff := out.At.Zone()
+			if !out.At.Equal(in.At) || gotOff != wantOff {
+				t.Errorf("%s: decoded %v, want %v", loc, out.At, in.At)
+			}
+		}
+	}
+}
+
+func TestAuditEntrySecondsOffset(t *testing.T) {
+	// Local mean time offsets have seconds RFC 3339 cannot write, so the
+	// time is written in UTC rather than at a shifted instant.
+	lmt := time.FixedZone("LMT", -(4*3600 + 56*60 + 2))
+	in := AuditEntry{At: time.Date(1880, 1, 1, 12, 0, 0, 0, lmt)}
+	b, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	var out AuditEntry
+	if _, err := out.UnmarshalCBOR(b); err != nil {
+		t.Fatalf("UnmarshalCBOR error: %v", err)
+	}
+	if !out.At.Equal(in.At) || out.At.Location() != time.UTC {
+		t.Fatalf("decoded %v, want %v in UTC", out.At, in.At.UTC())
+	}
+}
+
+func TestAuditEntryTimeUTC(t *testing.T) {
+	cbor.TimeUTC = true
+	defer func() { cbor.TimeUTC = false }()
+
+	at := time.Date(2024, 3, 10, 9, 15, 0, 0, time.FixedZone("PST", -8*3600))
+	in := AuditEntry{At: at}
+	b, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	if !bytes.Contains(b, []byte("2024-03-10T17:15:00Z")) {
+		t.Fatalf("encoded %x, want the time in UTC", b)
+	}
+
+	// Offsets written by another encoder are normalized on decode, and so
+	// are tag 1 times, which would otherwise decode in time.Local.
+	offset := cbor.AppendMapHeader(nil, 1)
+	offset = cbor.AppendString(offset, "at")
+	offset = cbor.AppendTag(offset, 0)
+	offset = cbor.AppendString(offset, "2024-03-10T09:15:00-08:00")
+	epoch := cbor.AppendMapHeader(nil, 1)
+	epoch = cbor.AppendString(epoch, "at")
+	epoch = cbor.AppendTime(epoch, at)
+	for _, in := range [][]byte{offset, epoch} {
+		var out AuditEntry
+		if _, err := out.UnmarshalCBOR(in); err != nil {
+			t.Fatalf("UnmarshalCBOR error: %v", err)
+		}
+		if !out.At.Equal(at) || out.At.Location() != time.UTC {
+			t.Errorf("decoded %v, want %v in UTC", out.At, at.UTC())
+		}
+	}
+}
+
+func TestTicketSetOption(t *testing.T) {
+	cbor.CanonicalMapEncode = true
+	defer func() { cbor.CanonicalMapEncode = false }()
+
+	in := Ticket{ID: 7, Labels: map[string]struct{}{"ops": {}, "db": {}, "p1": {}}}
+	b, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	// Members are written as a sorted array; an empty set is omitted.
+	want := cbor.AppendMapHeader(nil, 2)
+	want = cbor.AppendString(want, "id")
+	want = cbor.AppendUint64(want, 7)
+	want = cbor.AppendString(want, "labels")
+	want = cbor.AppendArrayHeader(want, 3)
+	for _, s := range []string{"db", "ops", "p1"} {
+		want = cbor.AppendString(want, s)
+	}
+	if !bytes.Equal(b, want) {
+		t.Fatalf("encoded %x, want %x", b, want)
+	}
+
+	for _, dec := range []func(*Ticket, []byte) ([]byte, error){
+		(*Ticket).DecodeSafe,
+		(*Ticket).DecodeTrusted,
+	} {
+		// Decoding replaces the members of a set already present.
+		out := Ticket{Labels: map[string]struct{}{"stale": {}}}
+		if _, err := dec(&out, b); err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+		if !reflect.DeepEqual(out, in) {
+			t.Fatalf("decoded %+v, want %+v", out, in)
+		}
+	}
+}
+
+func TestTicketSetDecode(t *testing.T) {
+	// A repeated member is kept once, and null decodes as a nil set.
+	b := cbor.AppendMapHeader(nil, 2)
+	b = cbor.AppendString(b, "labels")
+	b = cbor.AppendArrayHeader(b, 2)
+	b = cbor.AppendString(b, "db")
+	b = cbor.AppendString(b, "db")
+	b = cbor.AppendString(b, "watchers")
+	b = cbor.AppendNil(b)
+	out := Ticket{Watchers: map[string]struct{}{"ada": {}}}
+	if _, err := out.DecodeSafe(b); err != nil {
+		t.Fatalf("DecodeSafe error: %v", err)
+	}
+	if len(out.Labels) != 1 || out.Watchers != nil {
+		t.Fatalf("decoded %+v, want one label and no watchers", out)
+	}
+
+	// Members must be text strings.
+	b = cbor.AppendMapHeader(nil, 1)
+	b = cbor.AppendString(b, "labels")
+	b = cbor.AppendArrayHeader(b, 1)
+	b = cbor.AppendInt(b, 1)
+	var de *cbor.DecodeError
+	if _, err := out.DecodeSafe(b); !errors.As(err, &de) || de.Path != "labels" {
+		t.Fatalf("DecodeSafe error = %v, want a DecodeError at labels", err)
+	}
+}
+
+func TestTicketSetReflectMatchesGenerated(t *testing.T) {
+	cbor.CanonicalMapEncode = true
+	defer func() { cbor.CanonicalMapEncode = false }()
+
+	type plain Ticket // no generated methods
+	in := Ticket{ID: 1, Labels: map[string]struct{}{"b": {}, "a": {}}, Watchers: map[string]struct{}{"ada": {}}}
+	want, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := cbor.Marshal(plain(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("Marshal = % x\nwant      % x", got, want)
+	}
+	var out plain
+	if err := cbor.Unmarshal(want, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(Ticket(out), in) {
+		t.Fatalf("Unmarshal = %+v, want %+v", out, in)
+	}
+}
+
+func TestShiftFollowsDefaultTimeFormat(t *testing.T) {
+	defer func(f cbor.TimeFormat) { cbor.DefaultTimeFormat = f }(cbor.DefaultTimeFormat)
+	at := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	in := Shift{Start: at, Seen: at, Logged: at}
+	for _, f := range []cbor.TimeFormat{cbor.TimeEpoch, cbor.TimeEpochInt, cbor.TimeEpochFloat, cbor.TimeRFC3339} {
+		cbor.DefaultTimeFormat = f
+		b, err := in.MarshalCBOR(nil)
+		if err != nil {
+			t.Fatalf("%v: MarshalCBOR error: %v", f, err)
+		}
+		// Untagged fields take the default; tag=1 and tag=0 keep theirs.
+		want := cbor.AppendMapHeader(nil, 3)
+		want = cbor.AppendString(want, "start")
+		want = cbor.AppendTime(want, in.Start)
+		want = cbor.AppendString(want, "seen")
+		want = cbor.AppendEpochTime(want, in.Seen)
+		want = cbor.AppendString(want, "logged")
+		want = cbor.AppendRFC3339Time(want, in.Logged)
+		if !bytes.Equal(b, want) {
+			t.Errorf("%v: encoded %x, want %x", f, b, want)
+		}
+
+		// Whatever the default, every form decodes.
+		cbor.DefaultTimeFormat = cbor.TimeEpoch
+		var out Shift
+		if _, err := out.UnmarshalCBOR(b); err != nil {
+			t.Fatalf("%v: UnmarshalCBOR error: %v", f, err)
+		}
+		if !out.Start.Equal(in.Start) || !out.Seen.Equal(in.Seen) || !out.Logged.Equal(in.Logged) {
+			t.Errorf("%v: decoded %+v, want %+v", f, out, in)
+		}
+	}
+}
+
+func TestTimeFieldsDecodeEitherTag(t *testing.T) {
+	at := time.Unix(1700000000, 0)
+	for _, enc := range []func([]byte, time.Time) []byte{cbor.AppendEpochTime, cbor.AppendRFC3339Time} {
+		b := cbor.AppendMapHeader(nil, 2)
+		b = cbor.AppendString(b, "start")
+		b = enc(b, at)
+		b = cbor.AppendString(b, "seen")
+		b = enc(b, at)
+		for _, decode := range []func(*Shift, []byte) ([]byte, error){(*Shift).DecodeSafe, (*Shift).DecodeTrusted} {
+			var out Shift
+			if _, err := decode(&out, b); err != nil {
+				t.Fatalf("decode %x: %v", b, err)
+			}
+			if !out.Start.Equal(at) || !out.Seen.Equal(at) {
+				t.Errorf("decode %x = %v, %v, want %v", b, out.Start, out.Seen, at)
+			}
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package structs
+
+import (
+	"errors"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+// taggedPerson encodes a Person whose age is wrapped in tags.
+func taggedPerson(tags ...uint64) []byte {
+	var b []byte
+	b = cbor.AppendMapHeader(b, 3)
+	b = cbor.AppendString(b, "name")
+	b = cbor.AppendString(b, "Ada")
+	b = cbor.AppendString(b, "age")
+	for _, tag := range tags {
+		b = cbor.AppendTag(b, tag)
+	}
+	b = cbor.AppendInt(b, 36)
+	b = cbor.AppendString(b, "data")
+	b = cbor.AppendTag(b, 55799)
+	return cbor.AppendBytes(b, []byte{1})
+}
+
+func TestPlainFieldTagStrict(t *testing.T) {
+	setOption(t, &cbor.SkipUnknownTags, false)
+	for _, tc := range personDecoders {
+		t.Run(tc.name, func(t *testing.T) {
+			var dst Person
+			_, err := tc.decode(&dst, taggedPerson(1))
+			var te cbor.UnexpectedTagError
+			if !errors.As(err, &te) || te.Tag != 1 {
+				t.Fatalf("%s error = %v, want UnexpectedTagError{Tag: 1}", tc.name, err)
+			}
+		})
+	}
+}
+
+func TestPlainFieldTagSkipped(t *testing.T) {
+	setOption(t, &cbor.SkipUnknownTags, true)
+	for _, tc := range personDecoders {
+		t.Run(tc.name, func(t *testing.T) {
+			var dst Person
+			rest, err := tc.decode(&dst, taggedPerson(1, 4000))
+			if err != nil {
+				t.Fatalf("%s error: %v", tc.name, err)
+			}
+			if len(rest) != 0 {
+				t.Fatalf("%s leftover bytes: %d", tc.name, len(rest))
+			}
+			if dst.Name != "Ada" || dst.Age != 36 || len(dst.Data) != 1 || dst.Data[0] != 1 {
+				t.Fatalf("%s mismatch: got %+v", tc.name, dst)
+			}
+		})
+	}
+}
+
+func TestTypedFieldWrongTag(t *testing.T) {
+	// A field that expects a specific tag rejects another one, naming it,
+	// whether or not SkipUnknownTags is set.
+	var b []byte
+	b = cbor.AppendMapHeader(b, 1)
+	b = cbor.AppendString(b, "href")
+	b = cbor.AppendBase64URLString(b, "aGk")
+
+	for _, skip := range []bool{false, true} {
+		setOption(t, &cbor.SkipUnknownTags, skip)
+		for _, tc := range linkDecoders {
+			var dst Link
+			_, err := tc.decode(&dst, b)
+			var te cbor.UnexpectedTagError
+			if !errors.As(err, &te) || te.Tag != 33 {
+				t.Fatalf("%s (skip=%v) error = %v, want UnexpectedTagError{Tag: 33}", tc.name, skip, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,10 @@
+package structs
+
+// Memo holds text strings in each position a decoder reads them from: a
+// field, slice elements, map keys and values, and behind a pointer.
+type Memo struct {
+	Title string            `cbor:"title"`
+	Tags  []string          `cbor:"tags"`
+	Attrs map[string]string `cbor:"attrs"`
+	Alt   *string           `cbor:"alt"`
+}
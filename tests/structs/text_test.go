@@ -0,0 +1,101 @@
+package structs
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+// malformedMemos returns Memos with a malformed UTF-8 sequence in one
+// place each: a lone continuation byte, a truncated sequence, an overlong
+// encoding and a surrogate half.
+func malformedMemos() map[string]Memo {
+	alt := "\xed\xa0\x80"
+	return map[string]Memo{
+		"title":     {Title: "ok\x80"},
+		"tag":       {Tags: []string{"ok", "\xe2\x82"}},
+		"attrKey":   {Attrs: map[string]string{"\xc0\xaf": "v"}},
+		"attrValue": {Attrs: map[string]string{"k": "\xff"}},
+		"alt":       {Alt: &alt},
+	}
+}
+
+func TestMemoInvalidUTF8(t *testing.T) {
+	for name, in := range malformedMemos() {
+		b, err := in.MarshalCBOR(nil)
+		if err != nil {
+			t.Fatalf("%s: MarshalCBOR error: %v", name, err)
+		}
+
+		var out Memo
+		if _, err := out.DecodeSafe(b); !errors.Is(err, cbor.ErrInvalidUTF8) {
+			t.Fatalf("%s: DecodeSafe error = %v, want ErrInvalidUTF8", name, err)
+		}
+		var de *cbor.DecodeError
+		if err := cbor.Unmarshal(b, &out); !errors.As(err, &de) || !errors.Is(err, cbor.ErrInvalidUTF8) {
+			t.Fatalf("%s: Unmarshal error = %v, want a *DecodeError for ErrInvalidUTF8", name, err)
+		}
+
+		// DecodeTrusted skips the check and keeps the bytes as they are.
+		out = Memo{}
+		if _, err := out.DecodeTrusted(b); err != nil {
+			t.Fatalf("%s: DecodeTrusted error: %v", name, err)
+		}
+		if !reflect.DeepEqual(out, in) {
+			t.Fatalf("%s: DecodeTrusted = %+v, want %+v", name, out, in)
+		}
+	}
+}
+
+func TestMemoAllowInvalidUTF8(t *testing.T) {
+	setOption(t, &cbor.AllowInvalidUTF8, true)
+	for name, in := range malformedMemos() {
+		b, err := in.MarshalCBOR(nil)
+		if err != nil {
+			t.Fatalf("%s: MarshalCBOR error: %v", name, err)
+		}
+		var out Memo
+		if _, err := out.DecodeSafe(b); err != nil {
+			t.Fatalf("%s: DecodeSafe error: %v", name, err)
+		}
+		if !reflect.DeepEqual(out, in) {
+			t.Fatalf("%s: DecodeSafe = %+v, want %+v", name, out, in)
+		}
+	}
+}
+
+func TestInvalidUTF8Chunks(t *testing.T) {
+	// An indefinite-length string is checked once its chunks are joined,
+	// so a sequence may be split across them.
+	split := []byte{0x7f, 0x61, 0xe2, 0x62, 0x82, 0xac, 0xff} // "€" in two chunks
+	if s, _, err := cbor.ReadStringBytes(split); err != nil || s != "€" {
+		t.Fatalf("ReadStringBytes = %q, %v; want €", s, err)
+	}
+	bad := []byte{0x7f, 0x61, 0xe2, 0x61, 0x82, 0xff} // "€" missing its last byte
+	if _, _, err := cbor.ReadStringBytes(bad); !errors.Is(err, cbor.ErrInvalidUTF8) {
+		t.Fatalf("ReadStringBytes error = %v, want ErrInvalidUTF8", err)
+	}
+
+	// Reflection decodes through the same reader, and an unknown key is
+	// checked as well as a known one.
+	var plain struct {
+		S string `cbor:"s"`
+	}
+	unknown := cbor.AppendMapHeader(nil, 1)
+	unknown = cbor.AppendString(unknown, "\xfe")
+	unknown = cbor.AppendString(unknown, "v")
+	if err := cbor.Unmarshal(unknown, &plain); !errors.Is(err, cbor.ErrInvalidUTF8) {
+		t.Fatalf("Unmarshal error = %v, want ErrInvalidUTF8", err)
+	}
+	var memo Memo
+	if _, err := memo.DecodeSafe(unknown); !errors.Is(err, cbor.ErrInvalidUTF8) {
+		t.Fatalf("DecodeSafe error = %v, want ErrInvalidUTF8", err)
+	}
+
+	setOption(t, &cbor.AllowInvalidUTF8, true)
+	if s, _, err := cbor.ReadStringBytes(bad); err != nil || s != "\xe2\x82" {
+		t.Fatalf("ReadStringBytes = %q, %v; want the bytes as they are", s, err)
+	}
+}
@@ -0,0 +1,22 @@
+package structs
+
+// Measurement exercises the toarray option: it is encoded as the array
+// [sensor, value, tags] and decoded positionally.
+type Measurement struct {
+	_      struct{} `cbor:",toarray"`
+	Sensor string   `cbor:"sensor"`
+	Value  float64  `cbor:"value"`
+	Tags   []string `cbor:"tags"`
+}
+
+// SparseMeasurement exercises omitempty in toarray mode: empty Notes and
+// Attrs at the end shorten the array, while an empty Labels is written
+// to keep the positions after it.
+type SparseMeasurement struct {
+	_      struct{}          `cbor:",toarray"`
+	Sensor string            `cbor:"sensor"`
+	Labels []string          `cbor:"labels,omitempty"`
+	Value  float64           `cbor:"value"`
+	Notes  []string          `cbor:"notes,omitempty"`
+	Attrs  map[string]string `cbor:"attrs,omitempty"`
+}
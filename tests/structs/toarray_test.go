@@ -0,0 +1,183 @@
+package structs
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+type measurementDecoder struct {
+	name   string
+	decode func(dst *Measurement, b []byte) ([]byte, error)
+}
+
+var measurementDecoders = []measurementDecoder{
+	{
+		name:   "DecodeSafe",
+		decode: (*Measurement).DecodeSafe,
+	},
+	{
+		name:   "DecodeTrusted",
+		decode: (*Measurement).DecodeTrusted,
+	},
+}
+
+func TestToArrayEncodesPositionally(t *testing.T) {
+	orig := &Measurement{Sensor: "t1", Value: 21.5, Tags: []string{"lab"}}
+
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	want := cbor.AppendArrayHeader(nil, 3)
+	want = cbor.AppendString(want, "t1")
+	want = cbor.AppendFloat64(want, 21.5)
+	want = cbor.AppendStringSlice(want, []string{"lab"})
+	if !bytes.Equal(b, want) {
+		t.Fatalf("encoded %x, want %x", b, want)
+	}
+
+	for _, dec := range measurementDecoders {
+		t.Run(dec.name, func(t *testing.T) {
+			var got Measurement
+			rest, err := dec.decode(&got, b)
+			if err != nil {
+				t.Fatalf("decode error: %v", err)
+			}
+			if len(rest) != 0 {
+				t.Fatalf("unexpected trailing bytes: %d", len(rest))
+			}
+			if !reflect.DeepEqual(&got, orig) {
+				t.Fatalf("round trip mismatch: got %+v, want %+v", got, orig)
+			}
+		})
+	}
+}
+
+func TestToArrayMissingTrailingElements(t *testing.T) {
+	b := cbor.AppendArrayHeader(nil, 1)
+	b = cbor.AppendString(b, "t1")
+
+	for _, dec := range measurementDecoders {
+		t.Run(dec.name, func(t *testing.T) {
+			var got Measurement
+			if _, err := dec.decode(&got, b); err != nil {
+				t.Fatalf("decode error: %v", err)
+			}
+			if got.Sensor != "t1" || got.Value != 0 || got.Tags != nil {
+				t.Fatalf("got %+v, want only Sensor set", got)
+			}
+		})
+	}
+}
+
+func TestToArrayExtraElements(t *testing.T) {
+	// A newer writer appended a fourth element, in indefinite form.
+	b := []byte{0x9f}
+	b = cbor.AppendString(b, "t1")
+	b = cbor.AppendFloat64(b, 1.5)
+	b = cbor.AppendStringSlice(b, nil)
+	b = cbor.AppendMapHeader(b, 1)
+	b = cbor.AppendString(b, "unit")
+	b = cbor.AppendString(b, "C")
+	b = append(b, 0xff)
+
+	setOption(t, &cbor.TolerateExtraArrayElements, false)
+	for _, dec := range measurementDecoders {
+		var got Measurement
+		_, err := dec.decode(&got, b)
+		var ae cbor.ArrayError
+		if !errors.As(err, &ae) || ae.Wanted != 3 || ae.Got != 4 {
+			t.Fatalf("%s strict error = %v, want ArrayError{Wanted: 3, Got: 4}", dec.name, err)
+		}
+	}
+
+	setOption(t, &cbor.TolerateExtraArrayElements, true)
+	for _, dec := range measurementDecoders {
+		var got Measurement
+		rest, err := dec.decode(&got, b)
+		if err != nil {
+			t.Fatalf("%s tolerant error: %v", dec.name, err)
+		}
+		if len(rest) != 0 {
+			t.Fatalf("%s unexpected trailing bytes: %d", dec.name, len(rest))
+		}
+		if got.Sensor != "t1" || got.Value != 1.5 || len(got.Tags) != 0 {
+			t.Fatalf("%s got %+v", dec.name, got)
+		}
+	}
+}
+
+func TestToArrayOmitEmptyDropsTrailingFields(t *testing.T) {
+	orig := &SparseMeasurement{Sensor: "t1", Value: 21.5}
+
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	// Labels is interior and keeps its position as an empty array; the
+	// empty Notes and Attrs at the end are dropped.
+	want := cbor.AppendArrayHeader(nil, 3)
+	want = cbor.AppendString(want, "t1")
+	want = cbor.AppendArrayHeader(want, 0)
+	want = cbor.AppendFloat64(want, 21.5)
+	if !bytes.Equal(b, want) {
+		t.Fatalf("encoded %x, want %x", b, want)
+	}
+
+	var got SparseMeasurement
+	if _, err := got.DecodeSafe(b); err != nil {
+		t.Fatalf("DecodeSafe error: %v", err)
+	}
+	if got.Sensor != "t1" || got.Value != 21.5 || got.Notes != nil || got.Attrs != nil {
+		t.Fatalf("decoded %+v", got)
+	}
+}
+
+func TestToArrayOmitEmptyKeepsPositions(t *testing.T) {
+	// An empty Notes before a non-empty Attrs must still be written.
+	orig := &SparseMeasurement{Sensor: "t1", Value: 1, Attrs: map[string]string{"unit": "C"}}
+
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	sz, _, err := cbor.ReadArrayHeaderBytes(b)
+	if err != nil {
+		t.Fatalf("ReadArrayHeaderBytes error: %v", err)
+	}
+	if sz != 5 {
+		t.Fatalf("array length %d, want 5", sz)
+	}
+
+	full := &SparseMeasurement{Sensor: "t1", Notes: []string{"n"}, Value: 2}
+	fb, err := full.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	if sz, _, _ := cbor.ReadArrayHeaderBytes(fb); sz != 4 {
+		t.Fatalf("array length %d, want 4", sz)
+	}
+
+	for _, data := range [][]byte{b, fb} {
+		var safe, trusted SparseMeasurement
+		if _, err := safe.DecodeSafe(data); err != nil {
+			t.Fatalf("DecodeSafe error: %v", err)
+		}
+		if _, err := trusted.DecodeTrusted(data); err != nil {
+			t.Fatalf("DecodeTrusted error: %v", err)
+		}
+		for _, got := range []*SparseMeasurement{&safe, &trusted} {
+			again, err := got.MarshalCBOR(nil)
+			if err != nil {
+				t.Fatalf("MarshalCBOR error: %v", err)
+			}
+			if !bytes.Equal(again, data) {
+				t.Fatalf("re-encoded %x, want %x", again, data)
+			}
+		}
+	}
+}
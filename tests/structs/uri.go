@@ -0,0 +1,11 @@
+package structs
+
+import "net/url"
+
+// Link exercises tag 32 (URI) on string and *url.URL fields.
+type Link struct {
+	Title string   `cbor:"title"`
+	Href  string   `cbor:"href,tag=32"`
+	API   *url.URL `cbor:"api"`
+	Docs  *url.URL `cbor:"docs,omitempty,tag=32"`
+}
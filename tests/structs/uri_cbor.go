@@ -0,0 +1,270 @@
+// Code generated by cborgen DO NOT EDIT.
+
+package structs
+
+import (
+	"io"
+	"net/url"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+// Fail the build if a generated method drifts from the runtime interfaces.
+var (
+	_ cbor.Marshaler   = (*Link)(nil)
+	_ cbor.Unmarshaler = (*Link)(nil)
+)
+
+func (x Link) Msgsize() (s int) {
+	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("title") + cbor.StringPrefixSize + len(x.Title) + cbor.StringPrefixSize + len("href") + cbor.StringPrefixSize + len(x.Href) + cbor.TagSize
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *Link) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *Link) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *Link) AppendCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	count := uint32(0)
+	count++
+	count++
+	count++
+	if !(x.Docs == nil) {
+		count++
+	}
+	b = cbor.AppendMapHeader(b, count)
+	var err error
+	b = cbor.AppendString(b, "title")
+	b, err = cbor.AppendString(b, x.Title), nil
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "href")
+	b, err = cbor.AppendURI(b, x.Href), nil
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "api")
+	b, err = cbor.AppendURL(b, x.API), nil
+	if err != nil {
+		return b, err
+	}
+	if !(x.Docs == nil) {
+		b = cbor.AppendString(b, "docs")
+		b, err = cbor.AppendURL(b, x.Docs), nil
+		if err != nil {
+			return b, err
+		}
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *Link) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *Link) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := in.ReadStringBytes(rest)
+		if err != nil {
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+		}
+		switch key {
+		case "title":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "title", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "title", len(b)-len(v))
+				}
+			}
+
+			var tmp string
+			tmp, v, err = in.ReadStringBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "title", len(b)-len(v))
+			}
+			x.Title = tmp
+		case "href":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "href", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+
+			var tmp string
+			tmp, v, err = cbor.ReadValidURIStringBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "href", len(b)-len(v))
+			}
+			x.Href = tmp
+		case "api":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 2); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "api", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+
+			var tmp *url.URL
+			tmp, v, err = cbor.ReadURLBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "api", len(b)-len(v))
+			}
+			x.API = tmp
+		case "docs":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 3); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "docs", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+
+			var tmp *url.URL
+			tmp, v, err = cbor.ReadURLBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "docs", len(b)-len(v))
+			}
+			x.Docs = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *Link) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "title":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			x.Title, v, err = cbor.ReadTrustedStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+		case "href":
+
+			var tmp string
+			tmp, v, err = cbor.ReadURIStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Href = tmp
+		case "api":
+
+			var tmp *url.URL
+			tmp, v, err = cbor.ReadURLBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.API = tmp
+		case "docs":
+
+			var tmp *url.URL
+			tmp, v, err = cbor.ReadURLBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Docs = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *Link) resetCBOR() {
+	var zero Link
+	x.Title = zero.Title
+	x.Href = zero.Href
+	x.API = zero.API
+	x.Docs = zero.Docs
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Link) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
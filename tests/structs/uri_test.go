@@ -0,0 +1,102 @@
+package structs
+
+import (
+	"bytes"
+	"errors"
+	"net/url"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+type linkDecoder struct {
+	name   string
+	decode func(dst *Link, b []byte) ([]byte, error)
+}
+
+var linkDecoders = []linkDecoder{
+	{
+		name:   "DecodeSafe",
+		decode: (*Link).DecodeSafe,
+	},
+	{
+		name:   "DecodeTrusted",
+		decode: (*Link).DecodeTrusted,
+	},
+}
+
+func TestLinkURITagRoundTrip(t *testing.T) {
+	api, err := url.Parse("https://api.example.com/v1?q=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig := &Link{Title: "home", Href: "https://example.com/", API: api}
+
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	// Both URI fields carry tag 32 (0xd8 0x20) ahead of their text.
+	for _, s := range []string{orig.Href, api.String()} {
+		want := cbor.AppendURI(nil, s)
+		if !bytes.Contains(b, want) {
+			t.Fatalf("missing tag 32 item % x in % x", want, b)
+		}
+	}
+
+	for _, tc := range linkDecoders {
+		t.Run(tc.name, func(t *testing.T) {
+			var dst Link
+			rest, err := tc.decode(&dst, b)
+			if err != nil {
+				t.Fatalf("%s error: %v", tc.name, err)
+			}
+			if len(rest) != 0 {
+				t.Fatalf("%s leftover bytes: %d", tc.name, len(rest))
+			}
+			if dst.Title != orig.Title || dst.Href != orig.Href || dst.API == nil || *dst.API != *api || dst.Docs != nil {
+				t.Fatalf("%s mismatch: got %+v want %+v", tc.name, dst, *orig)
+			}
+		})
+	}
+}
+
+func TestLinkURITagRejectsMalformed(t *testing.T) {
+	build := func(href []byte, api []byte) []byte {
+		var b []byte
+		b = cbor.AppendMapHeader(b, 2)
+		b = cbor.AppendString(b, "href")
+		b = append(b, href...)
+		b = cbor.AppendString(b, "api")
+		return append(b, api...)
+	}
+	good := cbor.AppendURI(nil, "https://example.com")
+	bad := cbor.AppendURI(nil, "http://[::1")
+
+	// A malformed tag 32 string field fails the Safe path only; the
+	// Trusted path skips validation.
+	var dst Link
+	var uerr cbor.InvalidURIError
+	if _, err := dst.DecodeSafe(build(bad, good)); !errors.As(err, &uerr) {
+		t.Fatalf("DecodeSafe error = %v, want InvalidURIError", err)
+	}
+	if _, err := dst.DecodeTrusted(build(bad, good)); err != nil {
+		t.Fatalf("DecodeTrusted error: %v", err)
+	}
+
+	// *url.URL fields are parsed on both paths.
+	for _, tc := range linkDecoders {
+		var dst Link
+		if _, err := tc.decode(&dst, build(good, bad)); !errors.As(err, &uerr) {
+			t.Fatalf("%s error = %v, want InvalidURIError", tc.name, err)
+		}
+	}
+
+	// An untagged string where tag 32 is expected is a type error.
+	for _, tc := range linkDecoders {
+		var dst Link
+		if _, err := tc.decode(&dst, build(cbor.AppendString(nil, "https://example.com"), good)); err == nil {
+			t.Fatalf("%s accepted an untagged URI", tc.name)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package structs
+
+import "errors"
+
+// Account has a Validate method, which generated DecodeSafe methods call
+// once the value is decoded.
+type Account struct {
+	Name string `cbor:"name"`
+	Plan string `cbor:"plan"`
+}
+
+// Validate requires a name and a known plan.
+func (a *Account) Validate() error {
+	if a.Name == "" {
+		return errors.New("account: empty name")
+	}
+	switch a.Plan {
+	case "free", "pro":
+		return nil
+	}
+	return errors.New("account: unknown plan " + a.Plan)
+}
+
+// Org nests accounts, whose validation errors carry their path.
+type Org struct {
+	Owner   Account   `cbor:"owner"`
+	Members []Account `cbor:"members"`
+}
@@ -0,0 +1,67 @@
+package structs
+
+import (
+	"errors"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+func marshalOrg(t *testing.T, org *Org) []byte {
+	t.Helper()
+	b, err := org.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	return b
+}
+
+func TestValidateRunsOnSafeDecode(t *testing.T) {
+	setOption(t, &cbor.SkipValidateHooks, false)
+	good := &Org{Owner: Account{Name: "ada", Plan: "pro"}, Members: []Account{{Name: "bob", Plan: "free"}}}
+	var got Org
+	if _, err := got.DecodeSafe(marshalOrg(t, good)); err != nil {
+		t.Fatalf("DecodeSafe of a valid Org: %v", err)
+	}
+
+	bad := &Org{Owner: Account{Name: "ada", Plan: "pro"}, Members: []Account{{Name: "bob", Plan: "free"}, {Plan: "free"}}}
+	_, err := got.DecodeSafe(marshalOrg(t, bad))
+	var de *cbor.DecodeError
+	if !errors.As(err, &de) {
+		t.Fatalf("DecodeSafe error = %v, want a *cbor.DecodeError", err)
+	}
+	if de.Path != "members[1]" || de.Msg != "account: empty name" {
+		t.Fatalf("got Path %q Msg %q, want members[1] and the Validate error", de.Path, de.Msg)
+	}
+
+	var acct Account
+	_, err = acct.DecodeSafe(marshalAccount(t, Account{Name: "ada", Plan: "gold"}))
+	if err == nil || !errors.As(err, &de) || de.Msg != "account: unknown plan gold" {
+		t.Fatalf("Account.DecodeSafe error = %v, want the Validate error", err)
+	}
+}
+
+func TestValidateSkipped(t *testing.T) {
+	bad := &Org{Owner: Account{Plan: "gold"}}
+	b := marshalOrg(t, bad)
+
+	// DecodeTrusted never validates.
+	var got Org
+	if _, err := got.DecodeTrusted(b); err != nil {
+		t.Fatalf("DecodeTrusted error: %v", err)
+	}
+
+	setOption(t, &cbor.SkipValidateHooks, true)
+	if _, err := got.DecodeSafe(b); err != nil {
+		t.Fatalf("DecodeSafe with SkipValidateHooks: %v", err)
+	}
+}
+
+func marshalAccount(t *testing.T, a Account) []byte {
+	t.Helper()
+	b, err := a.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	return b
+}
@@ -0,0 +1,32 @@
+package structs
+
+// VersionedOrder is a message whose fields were added over three schema
+// versions; AppendCBORWith writes the fields of the version it is given.
+type VersionedOrder struct {
+	ID       uint64          `cbor:"id"`
+	Qty      int             `cbor:"qty"`
+	Currency string          `cbor:"currency,sinceversion=2"`
+	Note     string          `cbor:"note,omitempty,sinceversion=3"`
+	Lines    []VersionedLine `cbor:"lines,omitempty"`
+}
+
+// VersionedLine is versioned itself, so VersionedOrder passes its
+// options on to each line.
+type VersionedLine struct {
+	SKU      string `cbor:"sku"`
+	Discount int    `cbor:"discount,sinceversion=3"`
+}
+
+// VersionedRecord is a toarray struct, whose later fields come last.
+type VersionedRecord struct {
+	_     struct{} `cbor:",toarray"`
+	ID    uint64
+	Label string `cbor:",sinceversion=2"`
+}
+
+// VersionedNode is recursive and versioned.
+type VersionedNode struct {
+	Name     string           `cbor:"name"`
+	Weight   int              `cbor:"weight,sinceversion=2"`
+	Children []*VersionedNode `cbor:"children,omitempty"`
+}
@@ -0,0 +1,1156 @@
+// Code generated by cborgen DO NOT EDIT.
+
+package structs
+
+import (
+	"io"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+// Fail the build if a generated method drifts from the runtime interfaces.
+var (
+	_ cbor.Marshaler   = (*VersionedOrder)(nil)
+	_ cbor.Unmarshaler = (*VersionedOrder)(nil)
+	_ cbor.Marshaler   = (*VersionedLine)(nil)
+	_ cbor.Unmarshaler = (*VersionedLine)(nil)
+	_ cbor.Marshaler   = (*VersionedRecord)(nil)
+	_ cbor.Unmarshaler = (*VersionedRecord)(nil)
+	_ cbor.Marshaler   = (*VersionedNode)(nil)
+	_ cbor.Unmarshaler = (*VersionedNode)(nil)
+)
+
+func (x VersionedOrder) Msgsize() (s int) {
+	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("id") + cbor.Uint64Size + cbor.StringPrefixSize + len("qty") + cbor.IntSize + cbor.StringPrefixSize + len("currency") + cbor.StringPrefixSize + len(x.Currency) + cbor.StringPrefixSize + len("note") + cbor.StringPrefixSize + len(x.Note) + cbor.StringPrefixSize + len("lines") + cbor.ArrayHeaderSize + len(x.Lines)*0
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *VersionedOrder) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *VersionedOrder) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *VersionedOrder) AppendCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBORWith(b, cbor.EncodeOptions{})
+}
+
+// AppendCBORWith appends the encoding of x to b as of
+// opts.SchemaVersion, leaving out fields added in later versions.
+func (x *VersionedOrder) AppendCBORWith(b []byte, opts cbor.EncodeOptions) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	count := uint32(0)
+	count++
+	count++
+	if !(opts.Omits(2)) {
+		count++
+	}
+	if !((x.Note == "") || opts.Omits(3)) {
+		count++
+	}
+	if !(len(x.Lines) == 0) {
+		count++
+	}
+	b = cbor.AppendMapHeader(b, count)
+	var err error
+	b = cbor.AppendString(b, "id")
+	b, err = cbor.AppendUint64(b, x.ID), nil
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "qty")
+	b, err = cbor.AppendInt(b, x.Qty), nil
+	if err != nil {
+		return b, err
+	}
+	if !(opts.Omits(2)) {
+		b = cbor.AppendString(b, "currency")
+		b, err = cbor.AppendString(b, x.Currency), nil
+		if err != nil {
+			return b, err
+		}
+	}
+	if !((x.Note == "") || opts.Omits(3)) {
+		b = cbor.AppendString(b, "note")
+		b, err = cbor.AppendString(b, x.Note), nil
+		if err != nil {
+			return b, err
+		}
+	}
+	if !(len(x.Lines) == 0) {
+		if x.Lines == nil && cbor.NilContainers == cbor.NilAsNull {
+			b = cbor.AppendString(b, "lines")
+			b = cbor.AppendNil(b)
+		} else {
+			b = cbor.AppendString(b, "lines")
+			b = cbor.AppendArrayHeader(b, uint32(len(x.Lines)))
+			for i := range x.Lines {
+				b, err = x.Lines[i].AppendCBORWith(b, opts)
+				if err != nil {
+					return b, err
+				}
+			}
+		}
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *VersionedOrder) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *VersionedOrder) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := in.ReadStringBytes(rest)
+		if err != nil {
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+		}
+		switch key {
+		case "id":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "id", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "id", len(b)-len(v))
+				}
+			}
+
+			var tmp uint64
+			tmp, v, err = cbor.ReadUint64Bytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "id", len(b)-len(v))
+			}
+			x.ID = tmp
+		case "qty":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "qty", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "qty", len(b)-len(v))
+				}
+			}
+
+			var tmp int
+			tmp, v, err = cbor.ReadIntBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "qty", len(b)-len(v))
+			}
+			x.Qty = tmp
+		case "currency":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 2); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "currency", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "currency", len(b)-len(v))
+				}
+			}
+
+			var tmp string
+			tmp, v, err = in.ReadStringBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "currency", len(b)-len(v))
+			}
+			x.Currency = tmp
+		case "note":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 3); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "note", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "note", len(b)-len(v))
+				}
+			}
+
+			var tmp string
+			tmp, v, err = in.ReadStringBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "note", len(b)-len(v))
+			}
+			x.Note = tmp
+		case "lines":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 4); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "lines", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "lines", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Lines = cbor.NullSlice(x.Lines)
+				break
+			}
+			var sz uint32
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "lines", len(b)-len(v))
+			}
+			if cap(x.Lines) >= int(sz) {
+				x.Lines = x.Lines[:sz]
+			} else {
+				x.Lines = make([]VersionedLine, sz)
+			}
+			if sz > 0 {
+				_ = x.Lines[sz-1]
+			}
+			for iLines := uint32(0); iLines < sz; iLines++ {
+				x.Lines[iLines].resetCBOR()
+				v, err = x.Lines[iLines].DecodeInterned(v, in)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, int(iLines)), "lines", len(b)-len(v))
+				}
+			}
+			if indef {
+				v = v[1:] // break
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *VersionedOrder) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "id":
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp uint64
+			tmp, v, err = cbor.ReadUint64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.ID = tmp
+		case "qty":
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp int
+			tmp, v, err = cbor.ReadIntBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Qty = tmp
+		case "currency":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			x.Currency, v, err = cbor.ReadTrustedStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+		case "note":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			x.Note, v, err = cbor.ReadTrustedStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+		case "lines":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Lines = cbor.NullSlice(x.Lines)
+				break
+			}
+			var sz uint32
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap(x.Lines) >= int(sz) {
+				x.Lines = x.Lines[:sz]
+			} else {
+				x.Lines = make([]VersionedLine, sz)
+			}
+			if sz > 0 {
+				_ = x.Lines[sz-1]
+			}
+			for iLines := uint32(0); iLines < sz; iLines++ {
+				x.Lines[iLines].resetCBOR()
+				v, err = x.Lines[iLines].DecodeTrusted(v)
+				if err != nil {
+					return b, err
+				}
+			}
+			if indef {
+				v = v[1:] // break
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *VersionedOrder) resetCBOR() {
+	var zero VersionedOrder
+	x.ID = zero.ID
+	x.Qty = zero.Qty
+	x.Currency = zero.Currency
+	x.Note = zero.Note
+	x.Lines = x.Lines[:0]
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *VersionedOrder) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x VersionedLine) Msgsize() (s int) {
+	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("sku") + cbor.StringPrefixSize + len(x.SKU) + cbor.StringPrefixSize + len("discount") + cbor.IntSize
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *VersionedLine) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *VersionedLine) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *VersionedLine) AppendCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBORWith(b, cbor.EncodeOptions{})
+}
+
+// AppendCBORWith appends the encoding of x to b as of
+// opts.SchemaVersion, leaving out fields added in later versions.
+func (x *VersionedLine) AppendCBORWith(b []byte, opts cbor.EncodeOptions) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	count := uint32(0)
+	count++
+	if !(opts.Omits(3)) {
+		count++
+	}
+	b = cbor.AppendMapHeader(b, count)
+	var err error
+	b = cbor.AppendString(b, "sku")
+	b, err = cbor.AppendString(b, x.SKU), nil
+	if err != nil {
+		return b, err
+	}
+	if !(opts.Omits(3)) {
+		b = cbor.AppendString(b, "discount")
+		b, err = cbor.AppendInt(b, x.Discount), nil
+		if err != nil {
+			return b, err
+		}
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *VersionedLine) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *VersionedLine) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := in.ReadStringBytes(rest)
+		if err != nil {
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+		}
+		switch key {
+		case "sku":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "sku", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "sku", len(b)-len(v))
+				}
+			}
+
+			var tmp string
+			tmp, v, err = in.ReadStringBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "sku", len(b)-len(v))
+			}
+			x.SKU = tmp
+		case "discount":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "discount", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "discount", len(b)-len(v))
+				}
+			}
+
+			var tmp int
+			tmp, v, err = cbor.ReadIntBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "discount", len(b)-len(v))
+			}
+			x.Discount = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *VersionedLine) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "sku":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			x.SKU, v, err = cbor.ReadTrustedStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+		case "discount":
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp int
+			tmp, v, err = cbor.ReadIntBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Discount = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *VersionedLine) resetCBOR() {
+	var zero VersionedLine
+	x.SKU = zero.SKU
+	x.Discount = zero.Discount
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *VersionedLine) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x VersionedRecord) Msgsize() (s int) {
+	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("ID") + cbor.Uint64Size + cbor.StringPrefixSize + len("Label") + cbor.StringPrefixSize + len(x.Label)
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *VersionedRecord) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *VersionedRecord) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *VersionedRecord) AppendCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBORWith(b, cbor.EncodeOptions{})
+}
+
+// AppendCBORWith appends the encoding of x to b as of
+// opts.SchemaVersion, leaving out fields added in later versions.
+func (x *VersionedRecord) AppendCBORWith(b []byte, opts cbor.EncodeOptions) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	var count uint32
+	switch {
+	case !(opts.Omits(2)):
+		count = 2
+	default:
+		count = 1
+	}
+	b = cbor.AppendArrayHeader(b, count)
+	var err error
+	b, err = cbor.AppendUint64(b, x.ID), nil
+	if err != nil {
+		return b, err
+	}
+	if !(count <= 1) {
+		b, err = cbor.AppendString(b, x.Label), nil
+		if err != nil {
+			return b, err
+		}
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *VersionedRecord) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *VersionedRecord) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, indef, rest, err := cbor.ReadArraySizeBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if sz > 2 && !cbor.TolerateExtraArrayElements {
+		return b, cbor.WrapDecodeError(cbor.ArrayError{Wanted: 2, Got: sz}, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		v := rest
+		switch i {
+		case 0:
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "ID", len(b)-len(v))
+				}
+			}
+
+			var tmp uint64
+			tmp, v, err = cbor.ReadUint64Bytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "ID", len(b)-len(v))
+			}
+			x.ID = tmp
+		case 1:
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "Label", len(b)-len(v))
+				}
+			}
+
+			var tmp string
+			tmp, v, err = in.ReadStringBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "Label", len(b)-len(v))
+			}
+			x.Label = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "", len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if indef {
+		rest = rest[1:] // break
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *VersionedRecord) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, indef, rest, err := cbor.ReadArraySizeBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if sz > 2 && !cbor.TolerateExtraArrayElements {
+		return b, cbor.ArrayError{Wanted: 2, Got: sz}
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		v := rest
+		switch i {
+		case 0:
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp uint64
+			tmp, v, err = cbor.ReadUint64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.ID = tmp
+		case 1:
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			x.Label, v, err = cbor.ReadTrustedStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	if indef {
+		rest = rest[1:] // break
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *VersionedRecord) resetCBOR() {
+	var zero VersionedRecord
+	x.ID = zero.ID
+	x.Label = zero.Label
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *VersionedRecord) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x VersionedNode) Msgsize() (s int) {
+	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("name") + cbor.StringPrefixSize + len(x.Name) + cbor.StringPrefixSize + len("weight") + cbor.IntSize
+	return
+}
+
+// MarshalCBOR implements cbor.Marshaler; it is AppendCBOR.
+func (x *VersionedNode) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.AppendCBOR(b)
+}
+
+// MarshalCBORTo encodes x into a pooled scratch buffer and writes it to w
+// with a single Write, returning the number of bytes written.
+func (x *VersionedNode) MarshalCBORTo(w io.Writer) (int, error) {
+	if x != nil {
+		return cbor.MarshalTo(w, x, x.Msgsize())
+	}
+	return cbor.MarshalTo(w, x, 0)
+}
+
+// AppendCBOR appends the encoding of x to b.
+func (x *VersionedNode) AppendCBOR(b []byte) ([]byte, error) {
+	return x.marshalCBORDepth(b, 0, cbor.EncodeOptions{})
+}
+
+// AppendCBORWith appends the encoding of x to b as of
+// opts.SchemaVersion, leaving out fields added in later versions.
+func (x *VersionedNode) AppendCBORWith(b []byte, opts cbor.EncodeOptions) ([]byte, error) {
+	return x.marshalCBORDepth(b, 0, opts)
+}
+
+// marshalCBORDepth encodes x at the given nesting depth, failing with
+// ErrCycleDetected once depth exceeds MaxEncodeDepth.
+func (x *VersionedNode) marshalCBORDepth(b []byte, depth int, opts cbor.EncodeOptions) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+	if depth > cbor.MaxEncodeDepth {
+		return b, cbor.ErrCycleDetected
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	count := uint32(0)
+	count++
+	if !(opts.Omits(2)) {
+		count++
+	}
+	if !(len(x.Children) == 0) {
+		count++
+	}
+	b = cbor.AppendMapHeader(b, count)
+	var err error
+	b = cbor.AppendString(b, "name")
+	b, err = cbor.AppendString(b, x.Name), nil
+	if err != nil {
+		return b, err
+	}
+	if !(opts.Omits(2)) {
+		b = cbor.AppendString(b, "weight")
+		b, err = cbor.AppendInt(b, x.Weight), nil
+		if err != nil {
+			return b, err
+		}
+	}
+	if !(len(x.Children) == 0) {
+		if x.Children == nil && cbor.NilContainers == cbor.NilAsNull {
+			b = cbor.AppendString(b, "children")
+			b = cbor.AppendNil(b)
+		} else {
+			b = cbor.AppendString(b, "children")
+			b = cbor.AppendArrayHeader(b, uint32(len(x.Children)))
+			for _, v := range x.Children {
+				if v == nil {
+					b = cbor.AppendNil(b)
+				} else {
+					b, err = v.marshalCBORDepth(b, depth+1, opts)
+					if err != nil {
+						return b, err
+					}
+				}
+			}
+		}
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling. With
+// cbor.IncludeHexContext set, errors carry the bytes around the failure.
+func (x *VersionedNode) DecodeSafe(b []byte) ([]byte, error) {
+	o, err := x.DecodeInterned(b, nil)
+	if err != nil {
+		return o, cbor.WithHexContext(err, b)
+	}
+	return o, nil
+}
+
+// DecodeInterned decodes like DecodeSafe, taking repeated text strings
+// from in instead of allocating them again. A nil in interns nothing.
+// Errors are returned as a *cbor.DecodeError locating the failing item.
+// Once decoded, x is checked with its Validate method, if it has one.
+func (x *VersionedNode) DecodeInterned(b []byte, in *cbor.Interner) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	var seen [1]uint64
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := in.ReadStringBytes(rest)
+		if err != nil {
+			return b, cbor.WrapDecodeError(err, "", len(b)-len(rest))
+		}
+		switch key {
+		case "name":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 0); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
+				}
+			}
+
+			var tmp string
+			tmp, v, err = in.ReadStringBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "name", len(b)-len(v))
+			}
+			x.Name = tmp
+		case "weight":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 1); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "weight", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "weight", len(b)-len(v))
+				}
+			}
+
+			var tmp int
+			tmp, v, err = cbor.ReadIntBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "weight", len(b)-len(v))
+			}
+			x.Weight = tmp
+		case "children":
+			if o, skip, err := cbor.DuplicateField(v, seen[:], 2); skip || err != nil {
+				if err != nil {
+					return b, cbor.WrapDecodeError(err, "children", len(b)-len(v))
+				}
+				v = o
+				break
+			}
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, cbor.WrapDecodeError(err, "children", len(b)-len(v))
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Children = cbor.NullSlice(x.Children)
+				break
+			}
+			var sz uint32
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, "children", len(b)-len(v))
+			}
+			if cap(x.Children) >= int(sz) {
+				x.Children = x.Children[:sz]
+			} else {
+				x.Children = make([]*VersionedNode, sz)
+			}
+			if sz > 0 {
+				_ = x.Children[sz-1]
+			}
+			for iChildren := uint32(0); iChildren < sz; iChildren++ {
+				if cbor.IsNilOrUndefined(v) {
+					v = v[1:]
+					x.Children[iChildren] = nil
+					continue
+				}
+				if x.Children[iChildren] == nil {
+					x.Children[iChildren] = new(VersionedNode)
+				} else {
+					x.Children[iChildren].resetCBOR()
+				}
+				v, err = x.Children[iChildren].DecodeInterned(v, in)
+				if err != nil {
+					return b, cbor.WrapDecodeError(cbor.WrapDecodeIndex(err, int(iChildren)), "children", len(b)-len(v))
+				}
+			}
+			if indef {
+				v = v[1:] // break
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, cbor.WrapDecodeError(err, key, len(b)-len(v))
+			}
+		}
+		rest = v
+	}
+	if err := cbor.ValidateDecoded(x); err != nil {
+		return b, cbor.WrapDecodeError(err, "", 0)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes without per-string UTF-8 validation; strings alias b
+// while cbor.ZeroCopyStrings is set.
+func (x *VersionedNode) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if cbor.ResetBeforeDecode {
+		x.resetCBOR()
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "name":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			x.Name, v, err = cbor.ReadTrustedStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+		case "weight":
+			if cbor.IsTagged(v) && !cbor.IsBignum(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+
+			var tmp int
+			tmp, v, err = cbor.ReadIntBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Weight = tmp
+		case "children":
+			if cbor.IsTagged(v) {
+				if v, err = cbor.UntagBytes(v); err != nil {
+					return b, err
+				}
+			}
+			if cbor.IsNilOrUndefined(v) {
+				v = v[1:]
+				x.Children = cbor.NullSlice(x.Children)
+				break
+			}
+			var sz uint32
+			var indef bool
+			sz, indef, v, err = cbor.ReadArraySizeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap(x.Children) >= int(sz) {
+				x.Children = x.Children[:sz]
+			} else {
+				x.Children = make([]*VersionedNode, sz)
+			}
+			if sz > 0 {
+				_ = x.Children[sz-1]
+			}
+			for iChildren := uint32(0); iChildren < sz; iChildren++ {
+				if cbor.IsNilOrUndefined(v) {
+					v = v[1:]
+					x.Children[iChildren] = nil
+					continue
+				}
+				if x.Children[iChildren] == nil {
+					x.Children[iChildren] = new(VersionedNode)
+				} else {
+					x.Children[iChildren].resetCBOR()
+				}
+				v, err = x.Children[iChildren].DecodeTrusted(v)
+				if err != nil {
+					return b, err
+				}
+			}
+			if indef {
+				v = v[1:] // break
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// resetCBOR clears every field x decodes, truncating slices and clearing
+// maps so their storage is reused.
+func (x *VersionedNode) resetCBOR() {
+	var zero VersionedNode
+	x.Name = zero.Name
+	x.Weight = zero.Weight
+	x.Children = x.Children[:0]
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *VersionedNode) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
@@ -0,0 +1,92 @@
+package structs
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+func versionedOrder() VersionedOrder {
+	return VersionedOrder{
+		ID:       1,
+		Qty:      2,
+		Currency: "EUR",
+		Note:     "gift",
+		Lines:    []VersionedLine{{SKU: "a", Discount: 5}},
+	}
+}
+
+func TestVersionedOrderSchemaVersions(t *testing.T) {
+	in := versionedOrder()
+	tests := []struct {
+		version int
+		want    VersionedOrder
+	}{
+		{1, VersionedOrder{ID: 1, Qty: 2, Lines: []VersionedLine{{SKU: "a"}}}},
+		{2, VersionedOrder{ID: 1, Qty: 2, Currency: "EUR", Lines: []VersionedLine{{SKU: "a"}}}},
+		{3, in},
+		{0, in}, // the latest schema
+	}
+	for _, tc := range tests {
+		b, err := in.AppendCBORWith(nil, cbor.EncodeOptions{SchemaVersion: tc.version})
+		if err != nil {
+			t.Fatalf("v%d: AppendCBORWith error: %v", tc.version, err)
+		}
+		// Decoders accept any version: absent fields keep their zero value.
+		var out VersionedOrder
+		if _, err := out.DecodeSafe(b); err != nil {
+			t.Fatalf("v%d: DecodeSafe error: %v", tc.version, err)
+		}
+		if !reflect.DeepEqual(out, tc.want) {
+			t.Errorf("v%d: decoded %+v, want %+v", tc.version, out, tc.want)
+		}
+	}
+
+	// AppendCBOR and MarshalCBOR write the latest schema.
+	latest, err := in.AppendCBORWith(nil, cbor.EncodeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := in.MarshalCBOR(nil)
+	if err != nil || !bytes.Equal(b, latest) {
+		t.Fatalf("MarshalCBOR = %x, %v; want %x", b, err, latest)
+	}
+}
+
+func TestVersionedRecordToArray(t *testing.T) {
+	in := VersionedRecord{ID: 9, Label: "x"}
+	b, err := in.AppendCBORWith(nil, cbor.EncodeOptions{SchemaVersion: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Version 1 ends the array before the field added in version 2.
+	want := cbor.AppendUint64(cbor.AppendArrayHeader(nil, 1), 9)
+	if !bytes.Equal(b, want) {
+		t.Fatalf("v1 = %x, want %x", b, want)
+	}
+	b, err = in.AppendCBORWith(nil, cbor.EncodeOptions{SchemaVersion: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out VersionedRecord
+	if _, err := out.DecodeTrusted(b); err != nil || out != in {
+		t.Fatalf("v2 decoded %+v, %v; want %+v", out, err, in)
+	}
+}
+
+func TestVersionedNodePassesOptions(t *testing.T) {
+	in := VersionedNode{Name: "root", Weight: 1, Children: []*VersionedNode{{Name: "leaf", Weight: 2}}}
+	b, err := in.AppendCBORWith(nil, cbor.EncodeOptions{SchemaVersion: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out VersionedNode
+	if _, err := out.DecodeSafe(b); err != nil {
+		t.Fatal(err)
+	}
+	if out.Weight != 0 || len(out.Children) != 1 || out.Children[0].Weight != 0 {
+		t.Fatalf("v1 decoded %+v, want no weights", out)
+	}
+}
@@ -0,0 +1,50 @@
+package structs
+
+import (
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+// decodeThenClobber decodes a Bin with DecodeTrusted and then overwrites
+// the input buffer, as reusing it for the next message would.
+func decodeThenClobber(t *testing.T) Bin {
+	t.Helper()
+	b, err := (&Bin{Label: "shelf", Items: []string{"bolt"}}).MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	var got Bin
+	if _, err := got.DecodeTrusted(b); err != nil {
+		t.Fatalf("DecodeTrusted error: %v", err)
+	}
+	for i := range b {
+		b[i] = 'x'
+	}
+	return got
+}
+
+func TestZeroCopyStringsDisabledCopies(t *testing.T) {
+	setOption(t, &cbor.ZeroCopyStrings, false)
+	got := decodeThenClobber(t)
+	if got.Label != "shelf" || got.Items[0] != "bolt" {
+		t.Fatalf("got %+v, want strings copied out of the buffer", got)
+	}
+}
+
+func TestTrustedIndefiniteString(t *testing.T) {
+	b := cbor.AppendMapHeader(nil, 1)
+	b = cbor.AppendString(b, "label")
+	b = append(b, 0x7f)
+	b = cbor.AppendString(b, "sh")
+	b = cbor.AppendString(b, "elf")
+	b = append(b, 0xff)
+
+	var got Bin
+	if _, err := got.DecodeTrusted(b); err != nil {
+		t.Fatalf("DecodeTrusted error: %v", err)
+	}
+	if got.Label != "shelf" {
+		t.Fatalf("Label = %q, want %q", got.Label, "shelf")
+	}
+}
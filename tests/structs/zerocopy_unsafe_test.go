@@ -0,0 +1,18 @@
+//go:build !purego && !tinygo
+
+package structs
+
+import (
+	"testing"
+
+	cbor "github.com/delaneyj/cbor/runtime"
+)
+
+// Without unsafe (the purego tag or TinyGo) strings are always copied.
+func TestZeroCopyStringsAliasBuffer(t *testing.T) {
+	setOption(t, &cbor.ZeroCopyStrings, true)
+	got := decodeThenClobber(t)
+	if got.Label != "xxxxx" || got.Items[0] != "xxxx" {
+		t.Fatalf("got %+v, want strings aliasing the clobbered buffer", got)
+	}
+}